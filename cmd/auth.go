@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -42,11 +43,27 @@ func gatewayURL() string {
 
 // gatewayRequest sends an authenticated request to the running gateway.
 func gatewayRequest(method, path string) (map[string]any, error) {
+	return gatewayRequestBody(method, path, nil)
+}
+
+// gatewayRequestBody is gatewayRequest with an optional JSON request body.
+func gatewayRequestBody(method, path string, payload any) (map[string]any, error) {
 	url := gatewayURL() + path
-	req, err := http.NewRequest(method, url, nil)
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, url, reqBody)
 	if err != nil {
 		return nil, err
 	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	if token := os.Getenv("GOCLAW_TOKEN"); token != "" {
 		req.Header.Set("Authorization", "Bearer "+token)
@@ -58,7 +75,7 @@ func gatewayRequest(method, path string) (map[string]any, error) {
 	}
 	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
 	var result map[string]any
 	if err := json.Unmarshal(body, &result); err != nil {
 		return nil, fmt.Errorf("invalid response from gateway: %s", string(body))
@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nextlevelbuilder/goclaw/internal/eval"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func evalCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "eval",
+		Short: "Replay datasets against agents and score the responses",
+		Long:  "Run JSONL evaluation datasets against agents on the running gateway, grade the responses (exact, regex, llm_judge) and compare two agent configs side by side.",
+	}
+	cmd.AddCommand(evalRunCmd())
+	cmd.AddCommand(evalListCmd())
+	cmd.AddCommand(evalShowCmd())
+	cmd.AddCommand(evalCancelCmd())
+	return cmd
+}
+
+func evalRunCmd() *cobra.Command {
+	var (
+		name, agentKey, model, provider         string
+		compareAgent, compareModel, compareProv string
+		judgeProvider, judgeModel               string
+		concurrency                             int
+		noWait, jsonOutput                      bool
+	)
+	cmd := &cobra.Command{
+		Use:   "run <dataset.jsonl>",
+		Short: "Run a dataset against an agent (optionally compared with a second config)",
+		Example: `  goclaw eval run smoke.jsonl --agent support
+  goclaw eval run smoke.jsonl --agent support --compare-model gpt-4.1-mini
+  goclaw eval run smoke.jsonl --agent support --compare support-v2 --judge-provider openai`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			f, err := os.Open(args[0])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			cases, err := eval.ParseDataset(f)
+			f.Close()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s: %v\n", args[0], err)
+				os.Exit(1)
+			}
+			if agentKey == "" {
+				fmt.Fprintln(os.Stderr, "Error: --agent is required")
+				os.Exit(1)
+			}
+
+			spec := eval.Spec{
+				Name:        name,
+				Variants:    []eval.Variant{{Agent: agentKey, Model: model, Provider: provider}},
+				Cases:       cases,
+				Concurrency: concurrency,
+			}
+			if spec.Name == "" {
+				spec.Name = strings.TrimSuffix(filepath.Base(args[0]), filepath.Ext(args[0]))
+			}
+			if compareAgent != "" || compareModel != "" || compareProv != "" {
+				other := eval.Variant{Agent: compareAgent, Model: compareModel, Provider: compareProv}
+				if other.Agent == "" {
+					other.Agent = agentKey
+				}
+				spec.Variants = append(spec.Variants, other)
+			}
+			if judgeProvider != "" {
+				spec.Judge = &eval.JudgeSpec{Provider: judgeProvider, Model: judgeModel}
+			}
+
+			requireGateway()
+			run, err := evalRequest("POST", "/v1/evals", spec)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if noWait {
+				fmt.Printf("Started eval %s (%d cases)\n", run.ID, run.CaseCount)
+				return
+			}
+			if !jsonOutput {
+				fmt.Fprintf(os.Stderr, "Running eval %s: %d cases x %d variants\n", run.ID, run.CaseCount, len(run.Variants))
+			}
+			run = evalWait(run, jsonOutput)
+			printEvalRun(run, jsonOutput)
+			if run.Status != store.TraceStatusCompleted {
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "run name (default: dataset file name)")
+	cmd.Flags().StringVar(&agentKey, "agent", "", "agent key or ID to evaluate (required)")
+	cmd.Flags().StringVar(&model, "model", "", "override the agent's model")
+	cmd.Flags().StringVar(&provider, "provider", "", "override the agent's provider")
+	cmd.Flags().StringVar(&compareAgent, "compare", "", "second agent to compare against (default: --agent)")
+	cmd.Flags().StringVar(&compareModel, "compare-model", "", "model override for the comparison variant")
+	cmd.Flags().StringVar(&compareProv, "compare-provider", "", "provider override for the comparison variant")
+	cmd.Flags().StringVar(&judgeProvider, "judge-provider", "", "provider for llm_judge grading (default: the first variant's)")
+	cmd.Flags().StringVar(&judgeModel, "judge-model", "", "model for llm_judge grading")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 0, "cases run in parallel (default 4, max 16)")
+	cmd.Flags().BoolVar(&noWait, "no-wait", false, "start the run and return immediately")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	return cmd
+}
+
+func evalListCmd() *cobra.Command {
+	var jsonOutput bool
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List eval runs",
+		Run: func(cmd *cobra.Command, args []string) {
+			requireGateway()
+			result, err := gatewayRequest("GET", fmt.Sprintf("/v1/evals?limit=%d", limit))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			raw, _ := json.Marshal(result["runs"])
+			var runs []eval.Run
+			if err := json.Unmarshal(raw, &runs); err != nil {
+				fmt.Fprintf(os.Stderr, "Error parsing response: %v\n", err)
+				os.Exit(1)
+			}
+			if jsonOutput {
+				data, _ := json.MarshalIndent(runs, "", "  ")
+				fmt.Println(string(data))
+				return
+			}
+			if len(runs) == 0 {
+				fmt.Println("No eval runs found.")
+				return
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(tw, "ID\tNAME\tSTATUS\tCASES\tRESULT\tSTARTED\n")
+			for _, r := range runs {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\n",
+					r.ID, r.Name, r.Status, r.CaseCount, r.Preview(), r.StartedAt.Local().Format(time.DateTime))
+			}
+			tw.Flush()
+		},
+	}
+	cmd.Flags().IntVar(&limit, "limit", 20, "maximum runs to show")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	return cmd
+}
+
+func evalShowCmd() *cobra.Command {
+	var jsonOutput bool
+	cmd := &cobra.Command{
+		Use:   "show <runId>",
+		Short: "Show an eval run's summary and failed cases",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			requireGateway()
+			run, err := evalRequest("GET", "/v1/evals/"+url.PathEscape(args[0]), nil)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			printEvalRun(run, jsonOutput)
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "output as JSON")
+	return cmd
+}
+
+func evalCancelCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel <runId>",
+		Short: "Cancel a running eval",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			requireGateway()
+			if _, err := gatewayRequest("POST", "/v1/evals/"+url.PathEscape(args[0])+"/cancel"); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Cancelled eval %s\n", args[0])
+		},
+	}
+}
+
+// evalRequest calls an eval endpoint that responds with {"run": ...}.
+func evalRequest(method, path string, payload any) (*eval.Run, error) {
+	result, err := gatewayRequestBody(method, path, payload)
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := json.Marshal(result["run"])
+	var run eval.Run
+	if err := json.Unmarshal(raw, &run); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+	return &run, nil
+}
+
+// evalWait polls a run until it leaves the running state.
+func evalWait(run *eval.Run, quiet bool) *eval.Run {
+	total := run.CaseCount * len(run.Variants)
+	last := -1
+	for run.Status == store.TraceStatusRunning {
+		time.Sleep(2 * time.Second)
+		next, err := evalRequest("GET", "/v1/evals/"+run.ID.String(), nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		run = next
+		done := 0
+		for _, v := range run.Variants {
+			done += v.Completed
+		}
+		if !quiet && done != last {
+			fmt.Fprintf(os.Stderr, "  %d/%d\n", done, total)
+			last = done
+		}
+	}
+	return run
+}
+
+func printEvalRun(run *eval.Run, jsonOutput bool) {
+	if jsonOutput {
+		data, _ := json.MarshalIndent(run, "", "  ")
+		fmt.Println(string(data))
+		return
+	}
+
+	fmt.Printf("Eval:    %s (%s)\n", run.Name, run.ID)
+	fmt.Printf("Status:  %s\n", run.Status)
+	if run.Error != "" {
+		fmt.Printf("Error:   %s\n", run.Error)
+	}
+	fmt.Printf("Cases:   %d\n\n", run.CaseCount)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "VARIANT\tPASSED\tFAILED\tERRORS\tPASS RATE\tAVG SCORE\tAVG MS\tTOKENS (IN/OUT)\n")
+	for _, v := range run.Variants {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.0f%%\t%.2f\t%d\t%d/%d\n",
+			v.Label, v.Passed, v.Failed, v.Errors, v.PassRate*100, v.AvgScore, v.AvgDurationMS, v.InputTokens, v.OutputTokens)
+	}
+	tw.Flush()
+
+	if c := run.Comparison; c != nil {
+		fmt.Printf("\n%s vs %s: pass rate %+.0f pts, score %+.2f\n", c.Candidate, c.Baseline, c.PassRateDelta*100, c.ScoreDelta)
+		if len(c.Improved) > 0 {
+			fmt.Printf("  improved:  %s\n", strings.Join(c.Improved, ", "))
+		}
+		if len(c.Regressed) > 0 {
+			fmt.Printf("  regressed: %s\n", strings.Join(c.Regressed, ", "))
+		}
+	}
+
+	var failed []eval.CaseResult
+	for _, r := range run.Results {
+		if !r.Pass {
+			failed = append(failed, r)
+		}
+	}
+	if len(failed) == 0 {
+		return
+	}
+	fmt.Printf("\nFailed cases:\n")
+	for _, r := range failed {
+		reason := r.Error
+		if reason == "" {
+			for _, g := range r.Grades {
+				if !g.Pass {
+					reason = g.Grader + ": " + g.Reason
+					break
+				}
+			}
+		}
+		fmt.Printf("  [%s] %s — %s\n", r.Variant, r.CaseID, reason)
+	}
+}
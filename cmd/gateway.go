@@ -27,6 +27,7 @@ import (
 	zalopersonal "github.com/nextlevelbuilder/goclaw/internal/channels/zalo/personal"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
 	"github.com/nextlevelbuilder/goclaw/internal/edition"
+	"github.com/nextlevelbuilder/goclaw/internal/eval"
 	"github.com/nextlevelbuilder/goclaw/internal/gateway"
 	"github.com/nextlevelbuilder/goclaw/internal/gateway/methods"
 	"github.com/nextlevelbuilder/goclaw/internal/heartbeat"
@@ -40,7 +41,9 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/internal/store/pg"
 	"github.com/nextlevelbuilder/goclaw/internal/tasks"
+	"github.com/nextlevelbuilder/goclaw/internal/tokenizer"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
+	"github.com/nextlevelbuilder/goclaw/internal/workflow"
 	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
 )
 
@@ -118,6 +121,8 @@ func runGateway() {
 	}
 
 	toolsReg, execApprovalMgr, mcpMgr, sandboxMgr, browserMgr, webFetchTool, ttsTool, permPE, toolPE, dataDir, agentCfg := setupToolRegistry(cfg, workspace, providerRegistry)
+	setupToolGuardrails(cfg, toolsReg, msgBus)
+	toolApprovalMgr := setupToolApprovals(cfg, toolsReg, msgBus)
 	if browserMgr != nil {
 		defer browserMgr.Close()
 	}
@@ -135,10 +140,22 @@ func runGateway() {
 		defer snapshotWorker.Stop()
 	}
 
+	piiRedactor, err := setupPIIRedaction(cfg, traceCollector)
+	if err != nil {
+		slog.Error("invalid gateway.pii config", "error", err)
+		os.Exit(1)
+	}
+
 	// Redis cache: compiled via build tags. Build with 'go build -tags redis' to enable.
 	redisClient := initRedisClient(cfg)
 	defer shutdownRedis(redisClient)
 
+	// Durable inbound queue (GOCLAW_BUS_BACKEND); in-memory channel by default.
+	setupInboundQueue(cfg, msgBus, pgStores, redisClient)
+
+	// Multi-replica coordination (GOCLAW_CLUSTER_BACKEND); nil = single node.
+	clusterCoord := setupCluster(cfg, pgStores, redisClient)
+
 	// Register providers from DB (overrides config providers).
 	if pgStores.Providers != nil {
 		dbGatewayAddr := loopbackAddr(cfg.Gateway.Host, cfg.Gateway.Port)
@@ -164,7 +181,8 @@ func runGateway() {
 			slog.Info("system_configs applied to in-memory config", "keys", len(sysConfigs))
 		}
 	}
-	setupMemoryEmbeddings(pgStores, providerRegistry)
+	embProvider := setupMemoryEmbeddings(pgStores, providerRegistry)
+	setupResponseCache(pgStores, providerRegistry, redisClient)
 
 	loadBootstrapFiles(pgStores, workspace, agentCfg)
 
@@ -355,10 +373,23 @@ func runGateway() {
 	var postTurn tools.PostTurnProcessor
 	var localWorkerWaiters *localworker.WaiterRegistry
 	outboundManager := localworker.NewOutboundManager(pgStores.WorkerEndpoints)
-	contextFileInterceptor, mcpPool, mediaStore, postTurn, localWorkerWaiters = wireExtras(pgStores, agentRouter, providerRegistry, msgBus, pgStores.Sessions, toolsReg, toolPE, skillsLoader, hasMemory, traceCollector, workspace, cfg.Gateway.InjectionAction, cfg, sandboxMgr, outboundManager, workerManager, redisClient)
+	budgetEnforcer := setupBudgetEnforcer(pgStores, msgBus)
+	contextFileInterceptor, mcpPool, mediaStore, postTurn, localWorkerWaiters = wireExtras(pgStores, agentRouter, providerRegistry, msgBus, pgStores.Sessions, toolsReg, toolPE, skillsLoader, hasMemory, traceCollector, workspace, cfg.Gateway.InjectionAction, piiRedactor, cfg, sandboxMgr, outboundManager, workerManager, redisClient, budgetEnforcer)
 	if mcpPool != nil {
 		defer mcpPool.Stop()
 	}
+
+	// Declarative team workflows: runs advance on task events; the task ticker
+	// reconciles runs whose events were missed.
+	var workflowEngine *workflow.Engine
+	if pgStores.Teams != nil && pgStores.Workflows != nil {
+		workflowEngine = workflow.NewEngine(pgStores.Teams, pgStores.Workflows, msgBus)
+		workflowEngine.SetTracer(traceCollector)
+		if postTurn != nil {
+			workflowEngine.SetDispatcher(postTurn)
+		}
+		workflowEngine.Subscribe()
+	}
 	gatewayAddr := loopbackAddr(cfg.Gateway.Host, cfg.Gateway.Port)
 	var mcpToolLister httpapi.MCPToolLister
 	if mcpMgr != nil {
@@ -383,6 +414,9 @@ func runGateway() {
 	if postTurn != nil {
 		wakeH.SetPostTurnProcessor(postTurn)
 	}
+	if workflowEngine != nil {
+		wakeH.SetWorkflows(pgStores.Agents, pgStores.Teams, pgStores.Workflows, workflowEngine)
+	}
 	server.SetWakeHandler(wakeH)
 	if mcpH != nil {
 		if mcpPool != nil {
@@ -463,6 +497,18 @@ func runGateway() {
 	if pgStores.Snapshots != nil {
 		server.SetUsageHandler(httpapi.NewUsageHandler(pgStores.Snapshots, pgStores.DB))
 	}
+	server.SetBudgetHandler(httpapi.NewBudgetHandler(budgetEnforcer, pgStores.Agents))
+
+	// Agent evaluation API: runs are stored as traces next to normal ones.
+	evalMgr := eval.NewManager(agentRouter, providerRegistry, traceCollector, pgStores.Tracing)
+	evalMgr.SetSessionStore(pgStores.Sessions)
+	server.SetEvalsHandler(httpapi.NewEvalsHandler(evalMgr, msgBus))
+
+	// OpenAI-compatible embeddings via the tenant's embedding provider
+	if pgStores.Providers != nil {
+		embeddingResolver := newTenantEmbeddingResolver(pgStores.Providers, providerRegistry, pgStores.SystemConfigs)
+		server.SetEmbeddingsHandler(httpapi.NewEmbeddingsHandler(embeddingResolver.Resolve))
+	}
 
 	// Runtime package management (install/uninstall system/pip/npm packages)
 	server.SetPackagesHandler(httpapi.NewPackagesHandler())
@@ -495,6 +541,11 @@ func runGateway() {
 		server.SetKnowledgeGraphHandler(httpapi.NewKnowledgeGraphHandler(pgStores.KnowledgeGraph, providerRegistry))
 	}
 
+	// Document knowledge base API
+	if pgStores != nil && pgStores.KnowledgeBases != nil {
+		server.SetKnowledgeBasesHandler(httpapi.NewKnowledgeBasesHandler(pgStores.KnowledgeBases, pgStores.Agents, msgBus))
+	}
+
 	// Workspace file serving endpoint — serves files by absolute path, auth-token protected.
 	// Supports media from any agent workspace (each agent has its own workspace from DB).
 	server.SetFilesHandler(httpapi.NewFilesHandler(workspace, dataDir))
@@ -557,6 +608,11 @@ func runGateway() {
 			tc.SetChannelTenantChecker(channelMgr.ChannelTenantID)
 		}
 	}
+	// Tool approvals: prompts with buttons go out via channels, decisions come back via RPC or buttons.
+	wireToolApprovals(toolApprovalMgr, channelMgr)
+	wirePIIRehydration(piiRedactor, channelMgr)
+	methods.NewToolApprovalMethods(toolApprovalMgr, msgBus).Register(server.Router())
+
 	// Wire group member lister on list_group_members tool
 	if t, ok := toolsReg.Get("list_group_members"); ok {
 		if gl, ok := t.(tools.GroupMemberListerAware); ok {
@@ -586,7 +642,7 @@ func runGateway() {
 	registerConfigChannels(cfg, channelMgr, msgBus, pgStores, instanceLoader)
 
 	// Register channels/instances/links/teams RPC methods
-	wireChannelRPCMethods(server, pgStores, channelMgr, agentRouter, msgBus, workspace)
+	wireChannelRPCMethods(server, pgStores, channelMgr, agentRouter, msgBus, workspace, workflowEngine)
 
 	// Wire channel event subscribers (cache invalidation, pairing, cascade disable)
 	wireChannelEventSubscribers(msgBus, server, pgStores, channelMgr, instanceLoader, pairingMethods, cfg)
@@ -870,7 +926,20 @@ func runGateway() {
 		}
 	}
 
-	// Start channels
+	// Cluster relay: cross-node WebSocket events, cache invalidation, chat.abort.
+	relay := startClusterRelay(ctx, clusterCoord, msgBus, server, agentRouter, channelMgr)
+	if relay != nil {
+		chatMethods.SetAbortRelay(relay.Abort)
+	}
+
+	// Start channels (in cluster mode each channel runs on exactly one replica;
+	// replies produced on other replicas are forwarded to it over the relay)
+	if clusterCoord != nil {
+		channelMgr.SetOwnershipGate(clusterCoord.RunExclusive)
+		if relay != nil {
+			channelMgr.SetOutboundForwarder(relay.ForwardOutbound)
+		}
+	}
 	if err := channelMgr.StartAll(ctx); err != nil {
 		slog.Error("failed to start channels", "error", err)
 	}
@@ -884,15 +953,19 @@ func runGateway() {
 		makeSchedulerRunFunc(agentRouter, cfg),
 	)
 	defer sched.Stop()
+	if clusterCoord != nil {
+		sched.SetSessionLockFunc(clusterCoord.LockSession)
+	}
 
 	// Start cron service with job handler (routes through scheduler's cron lane)
-	pgStores.Cron.SetOnJob(makeCronJobHandler(sched, msgBus, cfg, channelMgr, pgStores.Sessions, pgStores.Agents))
+	pgStores.Cron.SetOnJob(makeCronJobHandler(sched, msgBus, cfg, channelMgr, pgStores.Sessions, pgStores.Agents, pgStores.Workflows, workflowEngine))
 	pgStores.Cron.SetOnEvent(func(event store.CronEvent) {
-		server.BroadcastEvent(*protocol.NewEvent(protocol.EventCron, event))
+		relay.BroadcastFrame(server, *protocol.NewEvent(protocol.EventCron, event))
 	})
-	if err := pgStores.Cron.Start(); err != nil {
-		slog.Warn("cron service failed to start", "error", err)
-	}
+	stopCron := runSingleton(ctx, clusterCoord, "cron", pgStores.Cron.Start, pgStores.Cron.Stop)
+
+	// Re-embed after an embedding provider change and backfill missing vectors.
+	stopEmbeddings := startEmbeddingMaintenance(ctx, clusterCoord, pgStores, embProvider)
 
 	// Start heartbeat ticker (routes through scheduler's cron lane)
 	heartbeatTicker := heartbeat.NewTicker(heartbeat.TickerConfig{
@@ -906,30 +979,39 @@ func runGateway() {
 		RunAgent:      makeHeartbeatRunFn(sched),
 	})
 	heartbeatTicker.SetOnEvent(func(event store.HeartbeatEvent) {
-		server.BroadcastEvent(*protocol.NewEvent(protocol.EventHeartbeat, event))
+		relay.BroadcastFrame(server, *protocol.NewEvent(protocol.EventHeartbeat, event))
 	})
-	heartbeatTicker.Start()
+	stopHeartbeat := runSingleton(ctx, clusterCoord, "heartbeat", func() error {
+		heartbeatTicker.Start()
+		return nil
+	}, heartbeatTicker.Stop)
 
 	// Wire heartbeat wake function to tool + RPC + cron wakeMode
-	heartbeatTool.SetWakeFn(heartbeatTicker.Wake)
-	heartbeatMethods.SetWakeFn(heartbeatTicker.Wake)
+	wakeHeartbeat := heartbeatWakeFn(ctx, relay, heartbeatTicker)
+	heartbeatTool.SetWakeFn(wakeHeartbeat)
+	heartbeatMethods.SetWakeFn(wakeHeartbeat)
 	heartbeatMethods.SetAgentStore(pgStores.Agents)
 	heartbeatMethods.SetProviderStore(pgStores.Providers)
 	cronHeartbeatWakeFn = func(agentID string) {
 		if id, err := uuid.Parse(agentID); err == nil {
-			heartbeatTicker.Wake(id)
+			wakeHeartbeat(id)
 		}
 	}
 
 	// Adaptive throttle: reduce per-session concurrency when nearing the summary threshold.
 	// This prevents concurrent runs from racing with summarization.
-	// Uses calibrated token estimation (actual prompt tokens from last LLM call)
-	// and the agent's real context window (cached on session by the Loop).
+	// Uses calibrated token estimation (actual prompt tokens from last LLM call),
+	// the session model's tokenizer for new messages, and the agent's real
+	// context window (cached on session by the Loop).
 	sched.SetTokenEstimateFunc(func(sessionKey string) (int, int) {
 		bctx := context.Background()
 		history := pgStores.Sessions.GetHistory(bctx, sessionKey)
 		lastPT, lastMC := pgStores.Sessions.GetLastPromptTokens(bctx, sessionKey)
-		tokens := agent.EstimateTokensWithCalibration(history, lastPT, lastMC)
+		tok := tokenizer.Default()
+		if sess := pgStores.Sessions.Get(bctx, sessionKey); sess != nil {
+			tok = tokenizer.ForModel(sess.Provider, sess.Model)
+		}
+		tokens := agent.EstimateTokensWithCalibration(tok, history, lastPT, lastMC)
 		cw := pgStores.Sessions.GetContextWindow(bctx, sessionKey)
 		if cw <= 0 {
 			cw = config.DefaultContextWindow
@@ -997,7 +1079,9 @@ func runGateway() {
 
 	// Register quota usage RPC.
 	// Pass DB so summary cards still work when quota is disabled (queries traces directly).
-	methods.NewQuotaMethods(quotaChecker, pgStores.DB).Register(server.Router())
+	quotaMethods := methods.NewQuotaMethods(quotaChecker, pgStores.DB)
+	quotaMethods.SetBudget(budgetEnforcer, pgStores.Agents, cfg.Gateway.OwnerIDs)
+	quotaMethods.Register(server.Router())
 
 	// API key management RPC
 	if pgStores.APIKeys != nil {
@@ -1108,10 +1192,19 @@ func runGateway() {
 	go consumeInboundMessages(ctx, msgBus, agentRouter, cfg, sched, channelMgr, consumerTeamStore, quotaChecker, pgStores.Sessions, pgStores.Agents, contactCollector, postTurn, subagentMgr)
 
 	// Task recovery ticker: re-dispatches stale/pending team tasks on startup and periodically.
-	var taskTicker *tasks.TaskTicker
+	stopTaskTicker := func() {}
 	if pgStores.Teams != nil {
-		taskTicker = tasks.NewTaskTicker(pgStores.Teams, pgStores.Agents, msgBus, cfg.Gateway.TaskRecoveryIntervalSec)
-		taskTicker.Start()
+		taskTicker := tasks.NewTaskTicker(pgStores.Teams, pgStores.Agents, msgBus, cfg.Gateway.TaskRecoveryIntervalSec)
+		if workflowEngine != nil {
+			taskTicker.SetWorkflowReconciler(workflowEngine)
+		}
+		if postTurn != nil {
+			taskTicker.SetDispatcher(postTurn)
+		}
+		stopTaskTicker = runSingleton(ctx, clusterCoord, "task-ticker", func() error {
+			taskTicker.Start()
+			return nil
+		}, taskTicker.Stop)
 	}
 
 	go func() {
@@ -1121,13 +1214,12 @@ func runGateway() {
 		// Broadcast shutdown event
 		server.BroadcastEvent(*protocol.NewEvent(protocol.EventShutdown, nil))
 
-		// Stop channels, cron, heartbeat, and task ticker
+		// Stop channels, cron, heartbeat, task ticker and embedding maintenance
 		channelMgr.StopAll(context.Background())
-		pgStores.Cron.Stop()
-		heartbeatTicker.Stop()
-		if taskTicker != nil {
-			taskTicker.Stop()
-		}
+		stopCron()
+		stopHeartbeat()
+		stopTaskTicker()
+		stopEmbeddings()
 
 		// Drain audit log queue before closing DB
 		if auditCh != nil {
@@ -1231,6 +1323,8 @@ func teamTaskEventType(eventName string) string {
 		return "updated"
 	case protocol.EventTeamTaskStale:
 		return "stale"
+	case protocol.EventTeamTaskOverdue:
+		return "overdue"
 	default:
 		return ""
 	}
@@ -3,6 +3,10 @@ package cmd
 import (
 	"context"
 	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
@@ -99,14 +103,17 @@ func resolveEmbeddingFromDB(
 }
 
 // buildEmbeddingProvider creates a memory.EmbeddingProvider from a DB provider record.
+// The client speaks the provider's native embedding API (store.EmbeddingAPIFor)
+// and is wrapped with request batching, retries, the configured rate limit and
+// fitting to the schema's vector size.
 func buildEmbeddingProvider(
 	dbp *store.LLMProviderData,
 	es *store.EmbeddingSettings,
 	memCfg *config.MemoryConfig,
 	providerReg *providers.Registry,
 ) memory.EmbeddingProvider {
-	// Resolve model: embedding settings → memCfg override → default
-	model := "text-embedding-3-small"
+	// Resolve model: embedding settings → memCfg override → API default
+	model := ""
 	if es != nil && es.Model != "" {
 		model = es.Model
 	}
@@ -131,28 +138,134 @@ func buildEmbeddingProvider(
 			"provider", dbp.Name, "requested", es.Dimensions, "required", store.RequiredMemoryEmbeddingDimensions)
 	}
 
-	// Try registry first for the actual API key / base (handles runtime-registered providers)
+	// Try registry first for the actual API key / base (handles runtime-registered providers),
+	// then fall back to the DB record. Local Ollama needs no key.
+	apiKey := dbp.APIKey
 	if providerReg != nil {
-		if regProv, regErr := providerReg.Get(context.Background(), dbp.Name); regErr == nil {
+		if regProv, regErr := providerReg.GetForTenant(dbp.TenantID, dbp.Name); regErr == nil {
 			if op, ok := regProv.(*providers.OpenAIProvider); ok {
 				if apiBase == "" {
 					apiBase = op.APIBase()
 				}
-				ep := memory.NewOpenAIEmbeddingProvider(dbp.Name, op.APIKey(), apiBase, model)
-				ep.WithDimensions(dims)
-				return ep
+				apiKey = op.APIKey()
+			} else {
+				slog.Debug("embedding provider in registry is not OpenAI-compatible, using DB record", "name", dbp.Name)
 			}
-			slog.Debug("embedding provider in registry is not OpenAI-compatible, using DB record", "name", dbp.Name)
 		}
 	}
+	api := store.EmbeddingAPIFor(dbp.ProviderType, apiBase, es)
+	if apiKey == "" && api != memory.EmbeddingAPIOllama {
+		return nil
+	}
+
+	ep, err := memory.NewEmbeddingProvider(api, dbp.Name, apiKey, apiBase, model, dims)
+	if err != nil {
+		slog.Warn("embedding provider not supported", "name", dbp.Name, "error", err)
+		return nil
+	}
+	opts := memory.BatchOptions{Dimensions: dims}
+	if es != nil {
+		opts.RequestsPerMinute = es.RequestsPerMinute
+	}
+	return memory.NewBatchingEmbeddingProvider(ep, opts).
+		WithFingerprint(memory.EmbeddingFingerprint(api, apiBase, ep.Model(), dims))
+}
+
+// tenantEmbeddingTTL bounds how long a resolved tenant embedding provider is
+// reused before provider or system_configs changes are picked up.
+const tenantEmbeddingTTL = time.Minute
+
+// tenantEmbeddingResolver resolves the embedding provider for the tenant in a
+// request context (used by /v1/embeddings). Resolution order:
+//  1. tenant system_configs "embedding.provider" + "embedding.model"
+//  2. first tenant provider with settings.embedding.enabled = true
+//
+// The master tenant uses resolveEmbeddingProvider. Other tenants never fall
+// back to the master tenant's provider: that would spend its credentials on
+// another tenant's behalf, so an unconfigured tenant gets no provider.
+type tenantEmbeddingResolver struct {
+	providerStore store.ProviderStore
+	providerReg   *providers.Registry
+	sysConfigs    store.SystemConfigStore
+
+	mu      sync.Mutex
+	entries map[uuid.UUID]tenantEmbeddingEntry
+}
+
+type tenantEmbeddingEntry struct {
+	provider  memory.EmbeddingProvider
+	expiresAt time.Time
+}
+
+func newTenantEmbeddingResolver(providerStore store.ProviderStore, providerReg *providers.Registry, sysConfigs store.SystemConfigStore) *tenantEmbeddingResolver {
+	return &tenantEmbeddingResolver{
+		providerStore: providerStore,
+		providerReg:   providerReg,
+		sysConfigs:    sysConfigs,
+		entries:       make(map[uuid.UUID]tenantEmbeddingEntry),
+	}
+}
+
+// Resolve returns the embedding provider for the tenant in ctx, or nil.
+func (r *tenantEmbeddingResolver) Resolve(ctx context.Context) store.EmbeddingProvider {
+	tenantID := store.TenantIDFromContext(ctx)
+	if tenantID == uuid.Nil {
+		tenantID = store.MasterTenantID
+	}
+	return r.lookup(ctx, tenantID)
+}
+
+func (r *tenantEmbeddingResolver) lookup(ctx context.Context, tenantID uuid.UUID) memory.EmbeddingProvider {
+	r.mu.Lock()
+	if e, ok := r.entries[tenantID]; ok && time.Now().Before(e.expiresAt) {
+		r.mu.Unlock()
+		return e.provider
+	}
+	r.mu.Unlock()
+
+	var p memory.EmbeddingProvider
+	if tenantID == store.MasterTenantID {
+		p = resolveEmbeddingProvider(r.providerStore, r.providerReg, r.sysConfigs)
+	} else {
+		p = r.resolveTenant(store.WithTenantID(ctx, tenantID))
+	}
 
-	// Fallback: build directly from DB record
-	if dbp.APIKey != "" {
-		ep := memory.NewOpenAIEmbeddingProvider(dbp.Name, dbp.APIKey, apiBase, model)
-		ep.WithDimensions(dims)
-		return ep
+	r.mu.Lock()
+	r.entries[tenantID] = tenantEmbeddingEntry{provider: p, expiresAt: time.Now().Add(tenantEmbeddingTTL)}
+	r.mu.Unlock()
+	return p
+}
+
+// resolveTenant applies the tenant-scoped steps of the resolution order.
+func (r *tenantEmbeddingResolver) resolveTenant(ctx context.Context) memory.EmbeddingProvider {
+	if r.sysConfigs != nil {
+		if name, err := r.sysConfigs.Get(ctx, "embedding.provider"); err == nil && name != "" {
+			var mcfg *config.MemoryConfig
+			if m, mErr := r.sysConfigs.Get(ctx, "embedding.model"); mErr == nil && m != "" {
+				mcfg = &config.MemoryConfig{EmbeddingModel: m}
+			}
+			if p := resolveEmbeddingFromDB(ctx, r.providerStore, name, mcfg, r.providerReg); p != nil {
+				return p
+			}
+		}
 	}
 
+	tenantProviders, err := r.providerStore.ListProviders(ctx)
+	if err != nil {
+		slog.Warn("failed to list tenant providers for embeddings", "error", err)
+		return nil
+	}
+	for i := range tenantProviders {
+		dbp := &tenantProviders[i]
+		if !dbp.Enabled || store.NoEmbeddingTypes[dbp.ProviderType] {
+			continue
+		}
+		if es := store.ParseEmbeddingSettings(dbp.Settings); es != nil && es.Enabled {
+			if p := buildEmbeddingProvider(dbp, es, nil, r.providerReg); p != nil {
+				return p
+			}
+		}
+	}
 	return nil
 }
 
@@ -209,11 +322,13 @@ func buildSubagentTools(parent *tools.Registry, workspace string, restrict bool,
 		reg.Register(tools.NewSandboxedReadFileTool(workspace, restrict, sandboxMgr))
 		reg.Register(tools.NewSandboxedWriteFileTool(workspace, restrict, sandboxMgr))
 		reg.Register(tools.NewSandboxedListFilesTool(workspace, restrict, sandboxMgr))
+		reg.Register(tools.NewSandboxedSearchFilesTool(workspace, restrict, sandboxMgr))
 		reg.Register(tools.NewSandboxedExecTool(workspace, restrict, sandboxMgr))
 	} else {
 		reg.Register(tools.NewReadFileTool(workspace, restrict))
 		reg.Register(tools.NewWriteFileTool(workspace, restrict))
 		reg.Register(tools.NewListFilesTool(workspace, restrict))
+		reg.Register(tools.NewSearchFilesTool(workspace, restrict))
 		reg.Register(tools.NewExecTool(workspace, restrict))
 	}
 	allowReadFileSkillPaths(reg, readPathCfg)
@@ -18,7 +18,9 @@ func builtinToolSeedData() []store.BuiltinToolDef {
 		{Name: "read_file", DisplayName: "Read File", Description: "Read the contents of a file from the agent's workspace by path", Category: "filesystem", Enabled: true},
 		{Name: "write_file", DisplayName: "Write File", Description: "Write content to a file in the workspace, creating directories as needed", Category: "filesystem", Enabled: true},
 		{Name: "list_files", DisplayName: "List Files", Description: "List files and directories in a given path within the workspace", Category: "filesystem", Enabled: true},
+		{Name: "search_files", DisplayName: "Search Files", Description: "Search file contents by regex or literal text and find files by glob across the workspace", Category: "filesystem", Enabled: true},
 		{Name: "edit", DisplayName: "Edit File", Description: "Apply targeted search-and-replace edits to existing files without rewriting the entire file", Category: "filesystem", Enabled: true},
+		{Name: "apply_patch", DisplayName: "Apply Patch", Description: "Apply a multi-file unified diff or Begin Patch block atomically, with dry-run and per-hunk conflict reports", Category: "filesystem", Enabled: true},
 
 		// runtime
 		{Name: "exec", DisplayName: "Execute Command", Description: "Execute a shell command in the workspace and return stdout/stderr", Category: "runtime", Enabled: true,
@@ -44,6 +46,9 @@ func builtinToolSeedData() []store.BuiltinToolDef {
 			Settings: json.RawMessage(`{"extract_on_memory_write":false,"extraction_provider":"","extraction_model":"","min_confidence":0.75}`),
 			Requires: []string{"knowledge_graph"},
 		},
+		{Name: "kb_search", DisplayName: "Knowledge Base Search", Description: "Search document knowledge bases attached to the agent and return cited passages", Category: "memory", Enabled: true,
+			Requires: []string{"knowledge_base"},
+		},
 
 		// media — user must configure provider chain via UI before use
 		{Name: "read_image", DisplayName: "Read Image", Description: "Analyze images using a vision-capable LLM provider", Category: "media", Enabled: false,
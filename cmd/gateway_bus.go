@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"log/slog"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// setupInboundQueue attaches the durable inbound queue selected by
+// GOCLAW_BUS_BACKEND. Falls back to the in-memory bus when the backend is
+// unset, unknown, or unavailable.
+func setupInboundQueue(cfg *config.Config, msgBus *bus.MessageBus, stores *store.Stores, redisClient any) {
+	var q bus.InboundQueue
+	switch backend := cfg.Database.BusBackend; backend {
+	case "", bus.QueueBackendMemory:
+		return
+	case bus.QueueBackendRedis:
+		q = makeRedisInboundQueue(redisClient)
+	case bus.QueueBackendPostgres:
+		if stores != nil && stores.DB != nil && cfg.Database.StorageBackend != "sqlite" {
+			q = bus.NewPGQueue(stores.DB, "inbound", bus.DefaultRedeliverAfter)
+		}
+	default:
+		slog.Warn("unknown GOCLAW_BUS_BACKEND, using in-memory bus", "value", backend)
+		return
+	}
+	if q == nil {
+		slog.Warn("durable bus backend unavailable, using in-memory bus", "backend", cfg.Database.BusBackend)
+		return
+	}
+	msgBus.SetInboundQueue(q)
+	slog.Info("bus backend: durable inbound queue", "backend", cfg.Database.BusBackend)
+}
@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/cluster"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/heartbeat"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// Cluster pub/sub topics.
+const (
+	clusterTopicEvents = "events" // bus events for WebSocket clients
+	clusterTopicCache  = "cache"  // cache invalidation
+	clusterTopicFrames = "frames" // server-level event frames (cron, heartbeat)
+	clusterTopicAbort  = "abort"  // chat.abort for runs on other nodes
+	clusterTopicWake   = "wake"   // heartbeat wakes for the node running the ticker
+	// outbound channel messages for the node that owns the channel
+	clusterTopicOutbound = "outbound"
+
+	clusterRelayBuffer = 1024
+
+	// maxRelayedMediaBytes caps the local media files inlined into one
+	// forwarded outbound message; larger attachments are dropped with a warning.
+	maxRelayedMediaBytes = 16 << 20
+)
+
+// setupCluster creates the multi-replica coordinator selected by
+// GOCLAW_CLUSTER_BACKEND. Returns nil for single-node deployments.
+func setupCluster(cfg *config.Config, stores *store.Stores, redisClient any) *cluster.Coordinator {
+	backend := cfg.Database.ClusterBackend
+	if backend == "" {
+		return nil
+	}
+	nodeID := cluster.DefaultNodeID()
+
+	var coord *cluster.Coordinator
+	switch backend {
+	case cluster.BackendPostgres:
+		if stores == nil || stores.DB == nil || cfg.Database.StorageBackend == "sqlite" {
+			slog.Warn("cluster backend postgres requires the Postgres store, running single-node")
+			return nil
+		}
+		coord = cluster.NewCoordinator(nodeID,
+			cluster.NewPGLocker(stores.DB, nodeID, cluster.DefaultLockTTL),
+			cluster.NewPGPubSub(stores.DB))
+	case cluster.BackendRedis:
+		locker, pubsub := makeRedisCluster(redisClient, nodeID)
+		if locker == nil {
+			slog.Warn("cluster backend redis unavailable (build with -tags redis and set GOCLAW_REDIS_DSN), running single-node")
+			return nil
+		}
+		coord = cluster.NewCoordinator(nodeID, locker, pubsub)
+	default:
+		slog.Warn("unknown GOCLAW_CLUSTER_BACKEND, running single-node", "value", backend)
+		return nil
+	}
+	slog.Info("cluster mode enabled", "backend", backend, "node", nodeID)
+	return coord
+}
+
+// runSingleton starts a process-wide singleton service. In cluster mode it
+// only runs on the replica holding the named lock. Returns the stop function.
+func runSingleton(ctx context.Context, coord *cluster.Coordinator, name string, start func() error, stop func()) func() {
+	if coord == nil {
+		if err := start(); err != nil {
+			slog.Warn("service failed to start", "service", name, "error", err)
+		}
+		return stop
+	}
+	return coord.RunExclusive(ctx, name, start, stop)
+}
+
+// relayedEvent is a bus event or event frame carried between replicas.
+type relayedEvent struct {
+	Name     string          `json:"name"`
+	TenantID uuid.UUID       `json:"tenantId"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+}
+
+// relayedAbort is a chat.abort forwarded to the replica running the session.
+type relayedAbort struct {
+	RunID      string `json:"runId,omitempty"`
+	SessionKey string `json:"sessionKey,omitempty"`
+}
+
+// relayedWake is a heartbeat wake forwarded to the replica running the ticker.
+type relayedWake struct {
+	AgentID uuid.UUID `json:"agentId"`
+}
+
+// relayedOutbound is an outbound channel message forwarded to the replica
+// that owns the channel. Local media files are inlined (Data) because the
+// owning replica cannot read this replica's temp files.
+type relayedOutbound struct {
+	Message bus.OutboundMessage `json:"message"`
+	Media   []relayedMedia      `json:"media,omitempty"`
+}
+
+type relayedMedia struct {
+	Index int    `json:"index"`
+	Name  string `json:"name"`
+	Data  []byte `json:"data"`
+}
+
+// clusterRelay fans events and aborts out across replicas.
+type clusterRelay struct {
+	coord  *cluster.Coordinator
+	outbox chan relayOut
+}
+
+type relayOut struct {
+	topic string
+	event relayedEvent
+}
+
+// startClusterRelay forwards local bus events (WebSocket-visible events and
+// cache invalidations) to other replicas and applies theirs locally.
+// Returns nil when coord is nil.
+func startClusterRelay(ctx context.Context, coord *cluster.Coordinator, msgBus *bus.MessageBus, server *gateway.Server, agents *agent.Router, channelMgr *channels.Manager) *clusterRelay {
+	if coord == nil {
+		return nil
+	}
+	r := &clusterRelay{coord: coord, outbox: make(chan relayOut, clusterRelayBuffer)}
+
+	// Publishing happens off the Broadcast path so slow backends never block
+	// local event delivery.
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case out := <-r.outbox:
+				if err := coord.Publish(ctx, out.topic, out.event); err != nil {
+					slog.Warn("cluster: relay publish failed, event dropped", "topic", out.topic, "event", out.event.Name, "error", err)
+				}
+			}
+		}
+	}()
+
+	msgBus.Subscribe("cluster.relay", func(event bus.Event) {
+		if event.Origin != "" {
+			return // already relayed from another node
+		}
+		topic := clusterTopicEvents
+		if event.Name == protocol.EventCacheInvalidate {
+			topic = clusterTopicCache
+		} else if !relayableEvent(event.Name) {
+			return
+		}
+		r.enqueue(topic, event.Name, event.TenantID, event.Payload)
+	})
+
+	coord.Subscribe(ctx, clusterTopicEvents, func(origin string, data json.RawMessage) {
+		var ev relayedEvent
+		if json.Unmarshal(data, &ev) != nil {
+			return
+		}
+		var payload any
+		_ = json.Unmarshal(ev.Payload, &payload)
+		server.DeliverEvent(bus.Event{Name: ev.Name, Payload: payload, TenantID: ev.TenantID, Origin: origin})
+	})
+
+	coord.Subscribe(ctx, clusterTopicCache, func(origin string, data json.RawMessage) {
+		var ev relayedEvent
+		var payload bus.CacheInvalidatePayload
+		if json.Unmarshal(data, &ev) != nil || json.Unmarshal(ev.Payload, &payload) != nil {
+			return
+		}
+		msgBus.Broadcast(bus.Event{Name: ev.Name, Payload: payload, TenantID: ev.TenantID, Origin: origin})
+	})
+
+	coord.Subscribe(ctx, clusterTopicFrames, func(_ string, data json.RawMessage) {
+		var ev relayedEvent
+		if json.Unmarshal(data, &ev) != nil {
+			return
+		}
+		var payload any
+		_ = json.Unmarshal(ev.Payload, &payload)
+		server.BroadcastEvent(*protocol.NewEvent(ev.Name, payload))
+	})
+
+	coord.Subscribe(ctx, clusterTopicAbort, func(origin string, data json.RawMessage) {
+		var req relayedAbort
+		if json.Unmarshal(data, &req) != nil {
+			return
+		}
+		if req.RunID != "" {
+			if agents.AbortRun(req.RunID, req.SessionKey) {
+				slog.Info("cluster: aborted run for remote chat.abort", "run_id", req.RunID, "from", origin)
+			}
+			return
+		}
+		if ids := agents.AbortRunsForSession(req.SessionKey); len(ids) > 0 {
+			slog.Info("cluster: aborted session runs for remote chat.abort", "session", req.SessionKey, "runs", ids, "from", origin)
+		}
+	})
+
+	coord.Subscribe(ctx, clusterTopicOutbound, func(origin string, data json.RawMessage) {
+		var out relayedOutbound
+		if err := json.Unmarshal(data, &out); err != nil {
+			slog.Warn("cluster: bad forwarded outbound message", "from", origin, "error", err)
+			return
+		}
+		msg, written := out.restoreMedia()
+		if !channelMgr.DeliverForwarded(msg) {
+			removeFiles(written)
+		}
+	})
+
+	return r
+}
+
+// ForwardOutbound publishes msg for the replica that owns its channel.
+// Satisfies channels.OutboundForwarder.
+func (r *clusterRelay) ForwardOutbound(ctx context.Context, msg bus.OutboundMessage) error {
+	out := relayedOutbound{Message: msg}
+	out.Message.Media = nil
+	total := 0
+	for _, media := range msg.Media {
+		if media.URL != "" && !strings.HasPrefix(media.URL, "http://") && !strings.HasPrefix(media.URL, "https://") {
+			data, err := os.ReadFile(media.URL)
+			if err == nil && total+len(data) > maxRelayedMediaBytes {
+				err = fmt.Errorf("exceeds the %d byte relay limit", maxRelayedMediaBytes)
+			}
+			if err != nil {
+				slog.Warn("cluster: dropping media from forwarded message", "channel", msg.Channel, "path", media.URL, "error", err)
+				continue
+			}
+			total += len(data)
+			out.Media = append(out.Media, relayedMedia{Index: len(out.Message.Media), Name: filepath.Base(media.URL), Data: data})
+		}
+		out.Message.Media = append(out.Message.Media, media)
+	}
+	return r.coord.Publish(ctx, clusterTopicOutbound, out)
+}
+
+// restoreMedia writes inlined media to temp files (removed by the channel
+// dispatcher after sending) and points the message at them.
+func (o relayedOutbound) restoreMedia() (bus.OutboundMessage, []string) {
+	msg := o.Message
+	msg.Media = append([]bus.MediaAttachment(nil), o.Message.Media...)
+	var written []string
+	for _, m := range o.Media {
+		if m.Index < 0 || m.Index >= len(msg.Media) {
+			continue
+		}
+		f, err := os.CreateTemp("", "goclaw-relay-*-"+filepath.Base(m.Name))
+		if err == nil {
+			_, err = f.Write(m.Data)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			slog.Warn("cluster: could not restore forwarded media", "channel", msg.Channel, "error", err)
+			continue
+		}
+		msg.Media[m.Index].URL = f.Name()
+		written = append(written, f.Name())
+	}
+	return msg, written
+}
+
+func removeFiles(paths []string) {
+	for _, p := range paths {
+		_ = os.Remove(p)
+	}
+}
+
+// relayableEvent reports whether a bus event is meant for WebSocket clients
+// (as opposed to in-process topics such as "config:changed" or audit).
+func relayableEvent(name string) bool {
+	if strings.Contains(name, ":") || strings.HasPrefix(name, "cache.") {
+		return false
+	}
+	switch name {
+	case protocol.EventAuditLog, bus.TopicAudit, bus.TopicTeamTaskAudit, bus.TopicChannelStreaming:
+		return false
+	}
+	return true
+}
+
+func (r *clusterRelay) enqueue(topic, name string, tenantID uuid.UUID, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		slog.Warn("cluster: event not serializable, dropped", "event", name, "error", err)
+		return
+	}
+	select {
+	case r.outbox <- relayOut{topic: topic, event: relayedEvent{Name: name, TenantID: tenantID, Payload: data}}:
+	default:
+		slog.Warn("cluster: relay buffer full, dropping event", "event", name)
+	}
+}
+
+// BroadcastFrame sends a server-level event frame to every replica's clients.
+// Safe to call on a nil relay (single node).
+func (r *clusterRelay) BroadcastFrame(server *gateway.Server, frame protocol.EventFrame) {
+	server.BroadcastEvent(frame)
+	if r != nil {
+		r.enqueue(clusterTopicFrames, frame.Event, uuid.Nil, frame.Payload)
+	}
+}
+
+// Abort forwards a chat.abort to the other replicas.
+func (r *clusterRelay) Abort(runID, sessionKey string) {
+	go func() {
+		if err := r.coord.Publish(context.Background(), clusterTopicAbort, relayedAbort{RunID: runID, SessionKey: sessionKey}); err != nil {
+			slog.Warn("cluster: abort relay failed", "run_id", runID, "session", sessionKey, "error", err)
+		}
+	}()
+}
+
+// heartbeatWakeFn returns the wake function for the heartbeat tool, RPC and
+// cron wakeMode. The ticker only runs on the replica holding the heartbeat
+// singleton, so wakes raised on any other replica are forwarded to it.
+func heartbeatWakeFn(ctx context.Context, relay *clusterRelay, ticker *heartbeat.Ticker) func(uuid.UUID) {
+	if relay == nil {
+		return ticker.Wake
+	}
+	relay.coord.Subscribe(ctx, clusterTopicWake, func(origin string, data json.RawMessage) {
+		var req relayedWake
+		if json.Unmarshal(data, &req) != nil || !ticker.Running() {
+			return
+		}
+		slog.Info("cluster: heartbeat wake from remote node", "agent_id", req.AgentID, "from", origin)
+		ticker.Wake(req.AgentID)
+	})
+	return func(agentID uuid.UUID) {
+		if ticker.Running() {
+			ticker.Wake(agentID)
+			return
+		}
+		go func() {
+			if err := relay.coord.Publish(context.Background(), clusterTopicWake, relayedWake{AgentID: agentID}); err != nil {
+				slog.Warn("cluster: heartbeat wake relay failed, wake dropped", "agent_id", agentID, "error", err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/cluster"
+	"github.com/nextlevelbuilder/goclaw/internal/heartbeat"
+)
+
+type capturePubSub struct {
+	topic   string
+	payload []byte
+}
+
+func (p *capturePubSub) Publish(_ context.Context, topic string, payload []byte) error {
+	p.topic, p.payload = topic, payload
+	return nil
+}
+
+func (p *capturePubSub) Subscribe(ctx context.Context, _ string, _ func([]byte)) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestForwardOutboundInlinesLocalMedia(t *testing.T) {
+	pubsub := &capturePubSub{}
+	relay := &clusterRelay{coord: cluster.NewCoordinator("node-a", cluster.NewLocalLocker(), pubsub)}
+
+	local := filepath.Join(t.TempDir(), "chart.png")
+	if err := os.WriteFile(local, []byte("png-bytes"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	msg := bus.OutboundMessage{
+		Channel: "telegram",
+		ChatID:  "42",
+		Content: "report",
+		Media: []bus.MediaAttachment{
+			{URL: filepath.Join(t.TempDir(), "gone.png")}, // unreadable: dropped
+			{URL: "https://example.com/a.jpg"},
+			{URL: local, ContentType: "image/png", Caption: "chart"},
+		},
+	}
+	if err := relay.ForwardOutbound(context.Background(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if pubsub.topic != clusterTopicOutbound {
+		t.Fatalf("topic = %q", pubsub.topic)
+	}
+
+	var env struct {
+		Data json.RawMessage `json:"data"`
+	}
+	var out relayedOutbound
+	if err := json.Unmarshal(pubsub.payload, &env); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(env.Data, &out); err != nil {
+		t.Fatal(err)
+	}
+	restored, written := out.restoreMedia()
+	defer removeFiles(written)
+
+	if len(restored.Media) != 2 || restored.Media[0].URL != "https://example.com/a.jpg" {
+		t.Fatalf("media = %+v", restored.Media)
+	}
+	got := restored.Media[1]
+	if got.URL == local || got.Caption != "chart" || got.ContentType != "image/png" {
+		t.Fatalf("restored media = %+v", got)
+	}
+	if data, err := os.ReadFile(got.URL); err != nil || string(data) != "png-bytes" {
+		t.Fatalf("restored file = %q, %v", data, err)
+	}
+}
+
+// chanPubSub hands published messages to a channel.
+type chanPubSub struct{ published chan string }
+
+func (p *chanPubSub) Publish(_ context.Context, topic string, _ []byte) error {
+	p.published <- topic
+	return nil
+}
+
+func (p *chanPubSub) Subscribe(ctx context.Context, _ string, _ func([]byte)) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestHeartbeatWakeFnForwardsWhenTickerNotLocal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pubsub := &chanPubSub{published: make(chan string, 1)}
+	relay := &clusterRelay{coord: cluster.NewCoordinator("node-a", cluster.NewLocalLocker(), pubsub)}
+
+	// This node does not own the heartbeat singleton: the ticker never started.
+	wake := heartbeatWakeFn(ctx, relay, heartbeat.NewTicker(heartbeat.TickerConfig{}))
+	wake(uuid.New())
+
+	select {
+	case topic := <-pubsub.published:
+		if topic != clusterTopicWake {
+			t.Errorf("topic = %q, want %q", topic, clusterTopicWake)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("wake was not forwarded to the ticker owner")
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/channels"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/scheduler"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
@@ -89,7 +90,9 @@ func consumeInboundMessages(ctx context.Context, msgBus *bus.MessageBus, agents
 	debouncer := bus.NewInboundDebouncer(
 		time.Duration(debounceMs)*time.Millisecond,
 		func(msg bus.InboundMessage) {
-			processNormalMessage(ctx, msg, deps)
+			processNormalMessage(ctx, msg, deps, func(runErr error) {
+				settleInbound(ctx, msgBus, msg, runErr)
+			})
 		},
 	)
 	defer debouncer.Stop()
@@ -115,26 +118,24 @@ func consumeInboundMessages(ctx context.Context, msgBus *bus.MessageBus, agents
 			dedupeKey := fmt.Sprintf("%s|%s|%s|%s", msg.Channel, msg.SenderID, msg.ChatID, msgID)
 			if dedupe.IsDuplicate(dedupeKey) {
 				slog.Debug("dedup: skipping duplicate message", "key", dedupeKey)
+				ackInbound(ctx, msgBus, msg)
 				continue
 			}
 		}
 
-		if handleSubagentAnnounce(ctx, msg, deps) {
-			continue
-		}
-		if handleTeammateMessage(ctx, msg, deps) {
-			continue
-		}
-		if handleResetCommand(msg, deps) {
-			continue
-		}
-		if handleStopCommand(msg, deps) {
+		if handleSubagentAnnounce(ctx, msg, deps) ||
+			handleTeammateMessage(ctx, msg, deps) ||
+			handleResetCommand(msg, deps) ||
+			handleStopCommand(msg, deps) {
+			ackInbound(ctx, msgBus, msg)
 			continue
 		}
 
 		// Blocker escalation messages bypass debounce — deliver immediately to leader.
 		if msg.SenderID == "system:escalation" {
-			go processNormalMessage(ctx, msg, deps)
+			go processNormalMessage(ctx, msg, deps, func(runErr error) {
+				settleInbound(ctx, msgBus, msg, runErr)
+			})
 			continue
 		}
 
@@ -143,6 +144,33 @@ func consumeInboundMessages(ctx context.Context, msgBus *bus.MessageBus, agents
 	}
 }
 
+// settleInbound acks a message once its run has finished, unless the run
+// failed with a transient error (rate limit, provider outage, network): those
+// stay unacked so the durable bus redelivers them, up to its delivery cap.
+func settleInbound(ctx context.Context, msgBus *bus.MessageBus, msg bus.InboundMessage, runErr error) {
+	if runErr != nil && providers.IsRetryableError(runErr) {
+		if len(msg.DeliveryIDs) > 0 {
+			slog.Warn("inbound: run failed with a transient error, leaving message for redelivery",
+				"channel", msg.Channel, "chat_id", msg.ChatID, "error", runErr)
+		}
+		msgBus.Release(msg)
+		return
+	}
+	ackInbound(ctx, msgBus, msg)
+}
+
+// ackInbound acknowledges a processed message on the durable bus. Messages
+// still in flight at shutdown are left unacked so another replica (or the
+// next start) redelivers them.
+func ackInbound(ctx context.Context, msgBus *bus.MessageBus, msg bus.InboundMessage) {
+	if len(msg.DeliveryIDs) == 0 || ctx.Err() != nil {
+		return
+	}
+	if err := msgBus.Ack(context.Background(), msg); err != nil {
+		slog.Warn("inbound ack failed", "channel", msg.Channel, "chat_id", msg.ChatID, "error", err)
+	}
+}
+
 // autoSetFollowup sets followup reminders on in_progress tasks when the lead agent
 // replies on a real channel. Only sets followup if the task doesn't already have one
 // (respects LLM-initiated ask_user). Fire-and-forget, logs errors.
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+type ackRecordingQueue struct {
+	acked []string
+}
+
+func (q *ackRecordingQueue) Enqueue(context.Context, bus.InboundMessage) error { return nil }
+func (q *ackRecordingQueue) Dequeue(context.Context) (*bus.Delivery, error)    { return nil, nil }
+func (q *ackRecordingQueue) Ack(_ context.Context, id string) error {
+	q.acked = append(q.acked, id)
+	return nil
+}
+func (q *ackRecordingQueue) Extend(context.Context, string) error { return nil }
+func (q *ackRecordingQueue) Close() error                         { return nil }
+
+func TestSettleInboundAcksOnlySettledRuns(t *testing.T) {
+	tests := []struct {
+		name    string
+		runErr  error
+		wantAck bool
+	}{
+		{"success", nil, true},
+		{"cancelled by user", context.Canceled, true},
+		{"permanent error", &providers.HTTPError{Status: 400, Body: "bad request"}, true},
+		{"rate limited", &providers.HTTPError{Status: 429, Body: "slow down"}, false},
+		{"provider outage", errors.Join(errors.New("run failed"), &providers.HTTPError{Status: 503}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &ackRecordingQueue{}
+			mb := bus.New()
+			mb.SetInboundQueue(q)
+			msg := bus.InboundMessage{Channel: "telegram", ChatID: "1", DeliveryIDs: []string{"7"}}
+
+			settleInbound(context.Background(), mb, msg, tt.runErr)
+			if got := len(q.acked) == 1; got != tt.wantAck {
+				t.Fatalf("acked = %v, want %v", q.acked, tt.wantAck)
+			}
+		})
+	}
+
+	// Messages still in flight at shutdown stay unacked.
+	q := &ackRecordingQueue{}
+	mb := bus.New()
+	mb.SetInboundQueue(q)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	settleInbound(ctx, mb, bus.InboundMessage{DeliveryIDs: []string{"8"}}, nil)
+	if len(q.acked) != 0 {
+		t.Fatalf("acked during shutdown: %v", q.acked)
+	}
+}
@@ -21,11 +21,21 @@ import (
 
 // processNormalMessage handles routing, scheduling, and response delivery for a single
 // (possibly merged) inbound message. Called directly by the debouncer's flush callback.
+// done is called exactly once when the message is settled: with the run's error once
+// a scheduled run finishes, or with nil when the message was handled without a run.
 func processNormalMessage(
 	ctx context.Context,
 	msg bus.InboundMessage,
 	deps *ConsumerDeps,
+	done func(error),
 ) {
+	scheduled := false
+	defer func() {
+		if !scheduled {
+			done(nil)
+		}
+	}()
+
 	// Inject tenant from channel instance into context so all store operations
 	// (agent lookup, session creation, etc.) are tenant-scoped.
 	if msg.TenantID != uuid.Nil {
@@ -369,8 +379,10 @@ func processNormalMessage(
 	})
 
 	// Handle result asynchronously to not block the flush callback.
+	scheduled = true
 	go func(agentKey, channel, chatID, session, rID, peerKind, inboundContent string, meta map[string]string, blockReplyEnabled bool, ptd *tools.PendingTeamDispatch) {
 		outcome := <-outCh
+		defer func() { done(outcome.Err) }()
 
 		// Release team create lock — tasks already visible in DB, other goroutines can list.
 		ptd.ReleaseTeamLock()
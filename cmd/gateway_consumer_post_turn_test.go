@@ -170,6 +170,12 @@ func (s *postTurnTeamStoreStub) MarkInReviewStaleTasks(context.Context, time.Tim
 func (s *postTurnTeamStoreStub) FixOrphanedBlockedTasks(context.Context) ([]store.RecoveredTaskInfo, error) {
 	return nil, nil
 }
+func (s *postTurnTeamStoreStub) ListAllOverdueTasks(context.Context) ([]store.RecoveredTaskInfo, error) {
+	return nil, nil
+}
+func (s *postTurnTeamStoreStub) MarkTaskEscalated(context.Context, uuid.UUID, *time.Time) error {
+	return nil
+}
 func (s *postTurnTeamStoreStub) SetTaskFollowup(context.Context, uuid.UUID, uuid.UUID, time.Time, int, string, string, string) error {
 	return nil
 }
@@ -204,6 +210,27 @@ func (s *postTurnTeamStoreStub) ListUserTeams(context.Context, string) ([]store.
 func (s *postTurnTeamStoreStub) HasTeamAccess(context.Context, uuid.UUID, string) (bool, error) {
 	return false, nil
 }
+func (s *postTurnTeamStoreStub) CreateTaskTemplate(context.Context, *store.TeamTaskTemplateData) error {
+	return nil
+}
+func (s *postTurnTeamStoreStub) GetTaskTemplate(context.Context, uuid.UUID) (*store.TeamTaskTemplateData, error) {
+	return nil, store.ErrTaskTemplateNotFound
+}
+func (s *postTurnTeamStoreStub) ListTaskTemplates(context.Context, uuid.UUID) ([]store.TeamTaskTemplateData, error) {
+	return nil, nil
+}
+func (s *postTurnTeamStoreStub) UpdateTaskTemplate(context.Context, *store.TeamTaskTemplateData) error {
+	return nil
+}
+func (s *postTurnTeamStoreStub) DeleteTaskTemplate(context.Context, uuid.UUID, uuid.UUID) error {
+	return nil
+}
+func (s *postTurnTeamStoreStub) ListDueTaskTemplates(context.Context, time.Time) ([]store.TeamTaskTemplateData, error) {
+	return nil, nil
+}
+func (s *postTurnTeamStoreStub) MarkTaskTemplateRun(context.Context, uuid.UUID, time.Time, *time.Time) error {
+	return nil
+}
 
 type postTurnStub struct{ dispatched bool }
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -12,9 +13,12 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/channels"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/cron"
 	"github.com/nextlevelbuilder/goclaw/internal/scheduler"
 	"github.com/nextlevelbuilder/goclaw/internal/sessions"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+	"github.com/nextlevelbuilder/goclaw/internal/workflow"
 )
 
 // makeCronJobHandler creates a cron job handler that routes through the scheduler's cron lane.
@@ -24,8 +28,15 @@ import (
 // Safe because cron jobs only fire after Start(), well after this is set.
 var cronHeartbeatWakeFn func(agentID string)
 
-func makeCronJobHandler(sched *scheduler.Scheduler, msgBus *bus.MessageBus, cfg *config.Config, channelMgr *channels.Manager, sessionMgr store.SessionStore, agentStore store.AgentStore) func(job *store.CronJob) (*store.CronJobResult, error) {
+func makeCronJobHandler(sched *scheduler.Scheduler, msgBus *bus.MessageBus, cfg *config.Config, channelMgr *channels.Manager, sessionMgr store.SessionStore, agentStore store.AgentStore, workflowStore store.WorkflowStore, workflowEngine *workflow.Engine) func(job *store.CronJob) (*store.CronJobResult, error) {
 	return func(job *store.CronJob) (*store.CronJobResult, error) {
+		switch job.Payload.Kind {
+		case store.CronPayloadKindWorkflow:
+			return runCronWorkflow(job, workflowStore, workflowEngine)
+		case store.CronPayloadKindWebhook:
+			return runCronWebhook(job, msgBus)
+		}
+
 		agentID := job.AgentID
 		if agentID == "" && agentStore != nil {
 			// Resolve real default agent from DB instead of using literal "default" string.
@@ -93,7 +104,7 @@ func makeCronJobHandler(sched *scheduler.Scheduler, msgBus *bus.MessageBus, cfg
 		// Schedule through cron lane — scheduler handles agent resolution and concurrency
 		outCh := sched.Schedule(cronCtx, scheduler.LaneCron, agent.RunRequest{
 			SessionKey:        sessionKey,
-			Message:           job.Payload.Message,
+			Message:           store.CronChainedMessage(job.Payload.Message, job.State.Input),
 			Channel:           channel,
 			ChannelType:       channelType,
 			ChatID:            job.DeliverTo,
@@ -114,22 +125,7 @@ func makeCronJobHandler(sched *scheduler.Scheduler, msgBus *bus.MessageBus, cfg
 
 		result := outcome.Result
 
-		// If job wants delivery to a channel, send the agent response to the target chat.
-		if job.Deliver && job.DeliverChannel != "" && job.DeliverTo != "" {
-			outMsg := bus.OutboundMessage{
-				Channel: job.DeliverChannel,
-				ChatID:  job.DeliverTo,
-				Content: result.Content,
-			}
-			if peerKind == "group" {
-				outMsg.Metadata = map[string]string{"group_id": job.DeliverTo}
-			}
-			appendMediaToOutbound(&outMsg, result.Media)
-			msgBus.PublishOutbound(outMsg)
-		} else if job.Deliver {
-			slog.Warn("cron: delivery configured but channel/chatID missing — output discarded",
-				"job_id", job.ID, "job_name", job.Name, "channel", job.DeliverChannel, "to", job.DeliverTo)
-		}
+		deliverCronOutput(msgBus, job, result.Content, result.Media)
 
 		cronResult := &store.CronJobResult{
 			Content: result.Content,
@@ -149,6 +145,38 @@ func makeCronJobHandler(sched *scheduler.Scheduler, msgBus *bus.MessageBus, cfg
 	}
 }
 
+// deliverCronOutput sends a run's output to the job's target chat when
+// delivery is configured and the job's deliverIf condition passes.
+func deliverCronOutput(msgBus *bus.MessageBus, job *store.CronJob, content string, media []agent.MediaResult) {
+	if !job.Deliver {
+		return
+	}
+	if job.DeliverChannel == "" || job.DeliverTo == "" {
+		slog.Warn("cron: delivery configured but channel/chatID missing — output discarded",
+			"job_id", job.ID, "job_name", job.Name, "channel", job.DeliverChannel, "to", job.DeliverTo)
+		return
+	}
+	ok, err := cron.EvalCondition(job.DeliverIf, content, job.State.LastOutput)
+	if err != nil {
+		slog.Warn("cron: deliver condition failed", "job_id", job.ID, "condition", job.DeliverIf, "error", err)
+		return
+	}
+	if !ok {
+		slog.Debug("cron: delivery skipped by condition", "job_id", job.ID, "condition", job.DeliverIf)
+		return
+	}
+	outMsg := bus.OutboundMessage{
+		Channel: job.DeliverChannel,
+		ChatID:  job.DeliverTo,
+		Content: content,
+	}
+	if resolveCronPeerKind(job) == "group" {
+		outMsg.Metadata = map[string]string{"group_id": job.DeliverTo}
+	}
+	appendMediaToOutbound(&outMsg, media)
+	msgBus.PublishOutbound(outMsg)
+}
+
 // resolveCronPeerKind infers peer kind from the cron job's user ID.
 // Group cron jobs have userID prefixed with "group:" or "guild:" (set during job creation).
 func resolveCronPeerKind(job *store.CronJob) string {
@@ -157,3 +185,60 @@ func resolveCronPeerKind(job *store.CronJob) string {
 	}
 	return ""
 }
+
+// runCronWorkflow starts the team workflow a "workflow" cron job points at,
+// passing the job message as input "message". The job does not wait for the
+// run to finish: progress is tracked on the run and its trace.
+func runCronWorkflow(job *store.CronJob, workflowStore store.WorkflowStore, engine *workflow.Engine) (*store.CronJobResult, error) {
+	if workflowStore == nil || engine == nil {
+		return nil, fmt.Errorf("workflows are not available")
+	}
+	workflowID, err := uuid.Parse(job.Payload.WorkflowID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workflow ID %q", job.Payload.WorkflowID)
+	}
+	ctx := store.WithTenantID(context.Background(), job.TenantID)
+	wf, err := workflowStore.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, fmt.Errorf("workflow %s: %w", workflowID, err)
+	}
+	input := map[string]string{}
+	if job.Payload.Message != "" {
+		input["message"] = job.Payload.Message
+	}
+	if job.State.Input != "" {
+		input["input"] = job.State.Input
+	}
+	run, err := engine.Start(ctx, wf, workflow.RunOptions{
+		Trigger:     store.WorkflowTriggerCron,
+		TriggeredBy: job.ID,
+		Input:       input,
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("cron: started workflow", "job_id", job.ID, "workflow_id", wf.ID, "run_id", run.ID)
+	return &store.CronJobResult{
+		Content: fmt.Sprintf("Started workflow %q (run %s)", wf.Name, run.ID),
+	}, nil
+}
+
+// runCronWebhook POSTs a "webhook" cron job's payload to its URL through the
+// web_fetch SSRF guard, so jobs cannot reach loopback or private addresses.
+// The response body is the run output (chained to downstream jobs and
+// delivered like an agent reply).
+func runCronWebhook(job *store.CronJob, msgBus *bus.MessageBus) (*store.CronJobResult, error) {
+	ctx := store.WithTenantID(context.Background(), job.TenantID)
+	content, err := cron.PostWebhook(ctx, nil, tools.CheckSSRF, job.Payload.URL, job.Payload.Headers, cron.WebhookBody{
+		JobID:   job.ID,
+		JobName: job.Name,
+		Message: job.Payload.Message,
+		Input:   job.State.Input,
+		FiredAt: time.Now().UnixMilli(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	deliverCronOutput(msgBus, job, content, nil)
+	return &store.CronJobResult{Content: content}, nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestRunCronWebhookBlocksLoopback(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer srv.Close()
+
+	job := &store.CronJob{
+		ID:      "job-1",
+		Name:    "ping",
+		Payload: store.CronPayload{Kind: store.CronPayloadKindWebhook, URL: srv.URL},
+	}
+	_, err := runCronWebhook(job, bus.New())
+	if err == nil || !strings.Contains(err.Error(), "blocked") {
+		t.Fatalf("expected SSRF block, got %v", err)
+	}
+	if hits.Load() != 0 {
+		t.Fatal("loopback webhook target was contacted")
+	}
+}
+
+func TestDeliverCronOutputHonorsCondition(t *testing.T) {
+	mb := bus.New()
+	job := &store.CronJob{
+		ID:             "job-1",
+		Deliver:        true,
+		DeliverChannel: "telegram",
+		DeliverTo:      "42",
+		DeliverIf:      "changed",
+		State:          store.CronJobState{LastOutput: "same"},
+	}
+
+	deliverCronOutput(mb, job, "same", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if msg, ok := mb.SubscribeOutbound(ctx); ok {
+		t.Fatalf("unchanged output delivered: %+v", msg)
+	}
+
+	deliverCronOutput(mb, job, "different", nil)
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	msg, ok := mb.SubscribeOutbound(ctx)
+	if !ok || msg.Content != "different" || msg.ChatID != "42" {
+		t.Fatalf("changed output not delivered: %+v", msg)
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/nextlevelbuilder/goclaw/internal/cluster"
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// embeddingFingerprintKey is the master-tenant system_configs key recording
+// the memory.EmbeddingFingerprint of the provider that produced the stored
+// vectors.
+const embeddingFingerprintKey = "embedding.fingerprint"
+
+// resolveSharedEmbeddingProvider resolves the master embedding provider and
+// wraps it with the content-hash cache, so memory, skills, team tasks, the
+// knowledge graph and knowledge bases embed each distinct text once.
+func resolveSharedEmbeddingProvider(pgStores *store.Stores, providerRegistry *providers.Registry) memory.EmbeddingProvider {
+	p := resolveEmbeddingProvider(pgStores.Providers, providerRegistry, pgStores.SystemConfigs)
+	if p == nil || pgStores.EmbeddingCache == nil {
+		return p
+	}
+	return memory.NewCachedEmbeddingProvider(p, pgStores.EmbeddingCache)
+}
+
+// clearEmbeddingsOnProviderChange starts a re-embed when the embedding API,
+// endpoint, model or dimensions differ from the ones that produced the stored
+// vectors:
+// it clears them so the backfills started afterwards regenerate every vector
+// with p. Vectors from different models aren't comparable, so keeping them
+// would silently break semantic search. The first run only records the
+// fingerprint. Returns true when vectors were cleared.
+func clearEmbeddingsOnProviderChange(sysConfigs store.SystemConfigStore, cache store.EmbeddingCacheStore, p memory.EmbeddingProvider) bool {
+	if sysConfigs == nil || cache == nil || p == nil {
+		return false
+	}
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+	fingerprint := p.Model()
+	if fp, ok := p.(memory.Fingerprinted); ok && fp.Fingerprint() != "" {
+		fingerprint = fp.Fingerprint()
+	}
+
+	previous, _ := sysConfigs.Get(ctx, embeddingFingerprintKey)
+	if previous == fingerprint {
+		return false
+	}
+
+	cleared := false
+	if previous != "" {
+		slog.Info("embedding provider changed, re-embedding stored vectors", "from", previous, "to", fingerprint)
+		n, err := cache.ClearEmbeddings(ctx)
+		if err != nil {
+			// Keep the old fingerprint so the next start retries.
+			slog.Warn("failed to clear embeddings for re-embed", "error", err)
+			return false
+		}
+		slog.Info("stored embeddings cleared for re-embed", "rows", n)
+		cleared = true
+	}
+	if err := sysConfigs.Set(ctx, embeddingFingerprintKey, fingerprint); err != nil {
+		slog.Warn("failed to record embedding fingerprint", "error", err)
+	}
+	return cleared
+}
+
+// startEmbeddingMaintenance runs the re-embed check and the embedding
+// backfills as the "embeddings" singleton, so one replica clears and
+// regenerates vectors instead of every replica repeating it at startup.
+// The backfills run after the clear, on the same node.
+func startEmbeddingMaintenance(ctx context.Context, coord *cluster.Coordinator, pgStores *store.Stores, p memory.EmbeddingProvider) func() {
+	if p == nil {
+		return func() {}
+	}
+	return runSingleton(ctx, coord, "embeddings", func() error {
+		clearEmbeddingsOnProviderChange(pgStores.SystemConfigs, pgStores.EmbeddingCache, p)
+		backfillEmbeddings(pgStores)
+		return nil
+	}, func() {})
+}
+
+// backfillEmbeddings embeds, in the background, the memory chunks, team
+// tasks, KG entities, knowledge base chunks and skills stored without vectors.
+func backfillEmbeddings(pgStores *store.Stores) {
+	backfill := func(kind, unit string, run func(context.Context) (int, error)) {
+		go func() {
+			count, err := run(context.Background())
+			if err != nil {
+				slog.Warn(kind+" embeddings backfill failed", "error", err)
+			} else if count > 0 {
+				slog.Info(kind+" embeddings backfill complete", unit, count)
+			}
+		}()
+	}
+
+	if bf, ok := pgStores.Memory.(interface {
+		BackfillEmbeddings(ctx context.Context) (int, error)
+	}); ok {
+		backfill("memory", "chunks_updated", bf.BackfillEmbeddings)
+	}
+	if bf, ok := pgStores.Teams.(interface {
+		BackfillTaskEmbeddings(ctx context.Context) (int, error)
+	}); ok {
+		backfill("task", "tasks_updated", bf.BackfillTaskEmbeddings)
+	}
+	if bf, ok := pgStores.KnowledgeGraph.(interface {
+		BackfillKGEmbeddings(ctx context.Context) (int, error)
+	}); ok {
+		backfill("KG", "entities_updated", bf.BackfillKGEmbeddings)
+	}
+	if bf, ok := pgStores.KnowledgeBases.(interface {
+		BackfillKBEmbeddings(ctx context.Context) (int, error)
+	}); ok {
+		backfill("knowledge base", "chunks_updated", bf.BackfillKBEmbeddings)
+	}
+	if bf, ok := pgStores.Skills.(interface {
+		BackfillSkillEmbeddings(ctx context.Context) (int, error)
+	}); ok {
+		backfill("skill", "skills_updated", bf.BackfillSkillEmbeddings)
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+type mapSystemConfigs map[string]string
+
+func (m mapSystemConfigs) Get(_ context.Context, key string) (string, error) { return m[key], nil }
+func (m mapSystemConfigs) Set(_ context.Context, key, value string) error {
+	m[key] = value
+	return nil
+}
+func (m mapSystemConfigs) Delete(_ context.Context, key string) error {
+	delete(m, key)
+	return nil
+}
+func (m mapSystemConfigs) List(context.Context) (map[string]string, error) { return m, nil }
+
+type clearCountingCache struct {
+	store.EmbeddingCacheStore
+	clears int
+}
+
+func (c *clearCountingCache) ClearEmbeddings(context.Context) (int64, error) {
+	c.clears++
+	return 3, nil
+}
+
+func TestClearEmbeddingsOnProviderChange(t *testing.T) {
+	sc := mapSystemConfigs{}
+	cache := &clearCountingCache{}
+	build := func(name, api, model string) memory.EmbeddingProvider {
+		ep, err := memory.NewEmbeddingProvider(api, name, "k", "", model, 1536)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return memory.NewBatchingEmbeddingProvider(ep, memory.BatchOptions{}).
+			WithFingerprint(memory.EmbeddingFingerprint(api, "", ep.Model(), 1536))
+	}
+	openai := build("openai", memory.EmbeddingAPIOpenAI, "")
+	gemini := build("gemini", memory.EmbeddingAPIGemini, "")
+
+	// First start only records which provider produced the vectors.
+	if clearEmbeddingsOnProviderChange(sc, cache, openai) || cache.clears != 0 {
+		t.Fatal("first start must not clear embeddings")
+	}
+	if got := sc[embeddingFingerprintKey]; got != "openai||text-embedding-3-small|1536" {
+		t.Fatalf("fingerprint = %q", got)
+	}
+
+	// Same provider and model: nothing to do.
+	if clearEmbeddingsOnProviderChange(sc, cache, openai) || cache.clears != 0 {
+		t.Fatal("unchanged provider must not clear embeddings")
+	}
+
+	// Renaming the provider keeps the vectors.
+	if clearEmbeddingsOnProviderChange(sc, cache, build("OpenAI (prod)", memory.EmbeddingAPIOpenAI, "")) || cache.clears != 0 {
+		t.Fatal("provider rename must not clear embeddings")
+	}
+
+	// Provider change clears once and records the new fingerprint.
+	if !clearEmbeddingsOnProviderChange(sc, cache, gemini) || cache.clears != 1 {
+		t.Fatalf("provider change: clears = %d, want 1", cache.clears)
+	}
+	if got := sc[embeddingFingerprintKey]; got != "gemini||gemini-embedding-001|1536" {
+		t.Fatalf("fingerprint = %q", got)
+	}
+	if clearEmbeddingsOnProviderChange(sc, cache, memory.NewCachedEmbeddingProvider(gemini, nil)) || cache.clears != 1 {
+		t.Fatal("re-embed must run only once per change")
+	}
+
+	// A different model behind the same API is a change too.
+	if !clearEmbeddingsOnProviderChange(sc, cache, build("gemini", memory.EmbeddingAPIGemini, "text-embedding-004")) || cache.clears != 2 {
+		t.Fatalf("model change: clears = %d, want 2", cache.clears)
+	}
+}
+
+func TestBuildEmbeddingProviderUsesNativeOllamaAPI(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"embeddings":[[0.6,0.8]]}`))
+	}))
+	defer server.Close()
+
+	// Local Ollama has no API key; its api_base is stored with /v1.
+	ep := buildEmbeddingProvider(&store.LLMProviderData{
+		Name:         "local",
+		ProviderType: store.ProviderOllama,
+		APIBase:      server.URL + "/v1",
+		Enabled:      true,
+	}, &store.EmbeddingSettings{Enabled: true, Model: "nomic-embed-text"}, nil, nil)
+	if ep == nil {
+		t.Fatal("buildEmbeddingProvider() = nil, want provider")
+	}
+	vecs, err := ep.Embed(context.Background(), []string{"hello"})
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if path != "/api/embed" {
+		t.Errorf("path = %q, want /api/embed", path)
+	}
+	if len(vecs) != 1 || len(vecs[0]) != store.RequiredMemoryEmbeddingDimensions || vecs[0][0] != 0.6 {
+		t.Errorf("vector not fitted to schema size: len %d", len(vecs[0]))
+	}
+}
+
+type emptyProviderStore struct{ store.ProviderStore }
+
+func (emptyProviderStore) ListProviders(context.Context) ([]store.LLMProviderData, error) {
+	return nil, nil
+}
+
+func TestTenantEmbeddingResolverDoesNotFallBackToMaster(t *testing.T) {
+	r := newTenantEmbeddingResolver(emptyProviderStore{}, nil, mapSystemConfigs{})
+	master := memory.NewOpenAIEmbeddingProvider("openai", "master-key", "", "")
+	r.entries[store.MasterTenantID] = tenantEmbeddingEntry{provider: master, expiresAt: time.Now().Add(time.Hour)}
+
+	if got := r.Resolve(context.Background()); got != master {
+		t.Fatalf("master tenant resolved %v, want its own provider", got)
+	}
+	ctx := store.WithTenantID(context.Background(), uuid.New())
+	if got := r.Resolve(ctx); got != nil {
+		t.Fatalf("unconfigured tenant resolved %v, want nil", got)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
 	"github.com/nextlevelbuilder/goclaw/internal/edition"
@@ -19,6 +20,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/localworker"
 	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
 	"github.com/nextlevelbuilder/goclaw/internal/media"
+	"github.com/nextlevelbuilder/goclaw/internal/pii"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/sandbox"
 	"github.com/nextlevelbuilder/goclaw/internal/skills"
@@ -48,11 +50,13 @@ func wireExtras(
 	traceCollector *tracing.Collector,
 	workspace string,
 	injectionAction string,
+	piiRedactor *pii.Redactor, // nil = no personal data redaction
 	appCfg *config.Config,
 	sandboxMgr sandbox.Manager,
 	outboundManager *localworker.OutboundManager,
 	workerManager *localworker.Manager,
 	redisClient any, // nil when built without -tags redis or when Redis is unconfigured
+	budgetEnforcer *budget.Enforcer, // nil = no USD budget enforcement
 ) (*tools.ContextFileInterceptor, *mcpbridge.Pool, *media.Store, tools.PostTurnProcessor, *localworker.WaiterRegistry) {
 	// 1. Build cache instances (in-memory or Redis depending on build tags)
 	agentCtxCache, userCtxCache := makeCaches(redisClient)
@@ -152,6 +156,7 @@ func wireExtras(
 		BootstrapCleanup:       buildBootstrapCleanup(stores.Agents),
 		CacheInvalidate:        buildCacheInvalidate(contextFileInterceptor),
 		InjectionAction:        injectionAction,
+		PII:                    piiRedactor,
 		MaxMessageChars:        appCfg.Gateway.MaxMessageChars,
 		CompactionCfg:          appCfg.Agents.Defaults.Compaction,
 		ContextPruningCfg:      appCfg.Agents.Defaults.ContextPruning,
@@ -168,7 +173,7 @@ func wireExtras(
 		ConfigPermStore:        stores.ConfigPermissions,
 		MediaStore:             mediaStore,
 		ModelPricing:           appCfg.Telemetry.ModelPricing,
-		TracingStore:           stores.Tracing,
+		Budget:                 budgetEnforcer,
 		MemoryStore:            stores.Memory,
 		TenantStore:            stores.Tenants,
 		BuiltinToolTenantCfgs:  stores.BuiltinToolTenantCfgs,
@@ -259,6 +264,16 @@ func wireExtras(
 			}
 		}
 	}
+	if patchTool, ok := toolsReg.Get("apply_patch"); ok {
+		if ia, ok := patchTool.(tools.InterceptorAware); ok {
+			if contextFileInterceptor != nil {
+				ia.SetContextFileInterceptor(contextFileInterceptor)
+			}
+			if writeMemIntc != nil {
+				ia.SetMemoryInterceptor(writeMemIntc)
+			}
+		}
+	}
 	if listTool, ok := toolsReg.Get("list_files"); ok {
 		if ia, ok := listTool.(tools.InterceptorAware); ok {
 			if stores.Memory != nil {
@@ -269,7 +284,7 @@ func wireExtras(
 
 	// Wire config perm store for file writer permission checks
 	if stores.ConfigPermissions != nil {
-		for _, toolName := range []string{"read_file", "write_file", "edit", "cron"} {
+		for _, toolName := range []string{"read_file", "write_file", "edit", "apply_patch", "cron"} {
 			if t, ok := toolsReg.Get(toolName); ok {
 				if cpa, ok := t.(tools.ConfigPermAware); ok {
 					cpa.SetConfigPermStore(stores.ConfigPermissions)
@@ -312,6 +327,15 @@ func wireExtras(
 		slog.Info("knowledge graph tool wired (Postgres)")
 	}
 
+	// Wire knowledge base store on kb_search tool
+	if stores.KnowledgeBases != nil {
+		if kbTool, ok := toolsReg.Get("kb_search"); ok {
+			if kbt, ok := kbTool.(*tools.KBSearchTool); ok {
+				kbt.SetKnowledgeBaseStore(stores.KnowledgeBases)
+			}
+		}
+	}
+
 	// --- Cache invalidation event subscribers ---
 
 	// Context file cache: invalidate on agent/context data changes
@@ -456,12 +480,14 @@ func wireExtras(
 			teamPolicy = tools.LiteTeamPolicy{}
 		}
 		toolsReg.Register(tools.NewTeamTasksTool(teamMgr, teamPolicy))
-		// Wire workspace interceptor into write_file so team workspace validation
-		// and event broadcasting happen transparently via existing file tools.
+		// Wire workspace interceptor into write_file and apply_patch so team workspace
+		// validation and event broadcasting happen transparently via existing file tools.
 		wsInterceptor := tools.NewWorkspaceInterceptor(teamMgr)
-		if writeTool, ok := toolsReg.Get("write_file"); ok {
-			if wia, ok := writeTool.(tools.WorkspaceInterceptorAware); ok {
-				wia.SetWorkspaceInterceptor(wsInterceptor)
+		for _, toolName := range []string{"write_file", "apply_patch"} {
+			if t, ok := toolsReg.Get(toolName); ok {
+				if wia, ok := t.(tools.WorkspaceInterceptorAware); ok {
+					wia.SetWorkspaceInterceptor(wsInterceptor)
+				}
 			}
 		}
 		slog.Info("team tools registered", "workspace", workspace)
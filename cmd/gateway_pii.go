@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/pii"
+	"github.com/nextlevelbuilder/goclaw/internal/tracing"
+)
+
+// setupPIIRedaction creates the personal data redactor from gateway.pii and
+// attaches it to trace previews. Returns nil when redaction is disabled.
+// An invalid config is fatal: starting without redaction would leak the data
+// the operator asked to protect.
+func setupPIIRedaction(cfg *config.Config, traceCollector *tracing.Collector) (*pii.Redactor, error) {
+	redactor, err := pii.New(cfg.Gateway.PII)
+	if err != nil || redactor == nil {
+		return nil, err
+	}
+	if traceCollector != nil && redactor.Traces() {
+		traceCollector.SetPreviewRedactor(redactor.RedactPreview)
+	}
+	slog.Info("pii redaction enabled", "mode", redactor.Mode(), "tool_results", redactor.ToolResults(), "traces", redactor.Traces())
+	return redactor, nil
+}
+
+// wirePIIRehydration restores tokenize-mode tokens in messages delivered to channels.
+func wirePIIRehydration(redactor *pii.Redactor, channelMgr *channels.Manager) {
+	if redactor.Mode() != pii.ModeTokenize {
+		return
+	}
+	channelMgr.SetRehydrator(func(tenantID uuid.UUID, channelName, chatID, text string) string {
+		return redactor.Rehydrate(tenantID, pii.Destination(channelName, chatID), text)
+	})
+}
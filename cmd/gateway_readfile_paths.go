@@ -15,15 +15,17 @@ type readFilePathConfig struct {
 }
 
 func allowReadFileSkillPaths(reg *tools.Registry, cfg readFilePathConfig) {
-	readTool, ok := reg.Get("read_file")
-	if !ok {
-		return
-	}
-	pa, ok := readTool.(tools.PathAllowable)
-	if !ok {
-		return
+	for _, name := range []string{"read_file", "search_files"} {
+		if t, ok := reg.Get(name); ok {
+			if pa, ok := t.(tools.PathAllowable); ok {
+				allowSkillPaths(pa, cfg)
+			}
+		}
 	}
+}
 
+// allowSkillPaths grants read access to skill and shared data directories.
+func allowSkillPaths(pa tools.PathAllowable, cfg readFilePathConfig) {
 	if cfg.globalSkillsDir != "" {
 		pa.AllowPaths(cfg.globalSkillsDir)
 	}
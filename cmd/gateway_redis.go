@@ -3,12 +3,18 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
+	"os"
 
 	"github.com/redis/go-redis/v9"
 
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/cache"
+	"github.com/nextlevelbuilder/goclaw/internal/cluster"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
@@ -45,6 +51,48 @@ func makeCaches(raw any) (
 		cache.NewRedisCache[[]store.AgentContextFileData](client, "ctx:user")
 }
 
+// makeResponseCaches creates the LLM response cache stores backed by Redis
+// (or in-memory if client is nil) so replicas share cached responses.
+func makeResponseCaches(raw any) (
+	entries cache.Cache[providers.CachedResponse],
+	index cache.Cache[[]providers.SemanticCacheEntry],
+) {
+	client, _ := raw.(*redis.Client)
+	if client == nil {
+		return cache.NewInMemoryCache[providers.CachedResponse](),
+			cache.NewInMemoryCache[[]providers.SemanticCacheEntry]()
+	}
+	return cache.NewRedisCache[providers.CachedResponse](client, "llm:resp"),
+		cache.NewRedisCache[[]providers.SemanticCacheEntry](client, "llm:sem")
+}
+
+// makeRedisInboundQueue creates a Redis Streams inbound queue shared by all
+// gateway replicas. Returns nil if Redis is not connected.
+func makeRedisInboundQueue(raw any) bus.InboundQueue {
+	client, _ := raw.(*redis.Client)
+	if client == nil {
+		return nil
+	}
+	host, _ := os.Hostname()
+	consumer := fmt.Sprintf("%s-%d", host, os.Getpid())
+	q, err := bus.NewRedisStreamQueue(context.Background(), client, "goclaw:bus:inbound", "gateway", consumer, bus.DefaultRedeliverAfter)
+	if err != nil {
+		slog.Warn("Redis inbound queue unavailable", "error", err)
+		return nil
+	}
+	return q
+}
+
+// makeRedisCluster returns Redis-backed cluster locks and pub/sub.
+// Returns nils if Redis is not connected.
+func makeRedisCluster(raw any, nodeID string) (cluster.Locker, cluster.PubSub) {
+	client, _ := raw.(*redis.Client)
+	if client == nil {
+		return nil, nil
+	}
+	return cluster.NewRedisLocker(client, nodeID, cluster.DefaultLockTTL), cluster.NewRedisPubSub(client)
+}
+
 // shutdownRedis closes the Redis client connection.
 func shutdownRedis(raw any) {
 	if client, ok := raw.(*redis.Client); ok && client != nil {
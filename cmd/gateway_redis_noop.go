@@ -5,8 +5,11 @@ package cmd
 import (
 	"log/slog"
 
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/cache"
+	"github.com/nextlevelbuilder/goclaw/internal/cluster"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
@@ -24,5 +27,20 @@ func makeCaches(_ any) (
 		cache.NewInMemoryCache[[]store.AgentContextFileData]()
 }
 
+// makeResponseCaches returns in-memory response cache stores when Redis is not compiled in.
+func makeResponseCaches(_ any) (
+	entries cache.Cache[providers.CachedResponse],
+	index cache.Cache[[]providers.SemanticCacheEntry],
+) {
+	return cache.NewInMemoryCache[providers.CachedResponse](),
+		cache.NewInMemoryCache[[]providers.SemanticCacheEntry]()
+}
+
+// makeRedisInboundQueue returns nil when Redis is not compiled in.
+func makeRedisInboundQueue(_ any) bus.InboundQueue { return nil }
+
+// makeRedisCluster returns nils when Redis is not compiled in.
+func makeRedisCluster(_ any, _ string) (cluster.Locker, cluster.PubSub) { return nil, nil }
+
 // shutdownRedis is a no-op when built without the "redis" tag.
 func shutdownRedis(_ any) {}
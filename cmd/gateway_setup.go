@@ -11,9 +11,11 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/nextlevelbuilder/goclaw/internal/bootstrap"
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
 	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
 	"github.com/nextlevelbuilder/goclaw/internal/permissions"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/sandbox"
@@ -72,13 +74,17 @@ func setupToolRegistry(
 		toolsReg.Register(tools.NewSandboxedReadFileTool(workspace, agentCfg.RestrictToWorkspace, sandboxMgr))
 		toolsReg.Register(tools.NewSandboxedWriteFileTool(workspace, agentCfg.RestrictToWorkspace, sandboxMgr))
 		toolsReg.Register(tools.NewSandboxedListFilesTool(workspace, agentCfg.RestrictToWorkspace, sandboxMgr))
+		toolsReg.Register(tools.NewSandboxedSearchFilesTool(workspace, agentCfg.RestrictToWorkspace, sandboxMgr))
 		toolsReg.Register(tools.NewSandboxedEditTool(workspace, agentCfg.RestrictToWorkspace, sandboxMgr))
+		toolsReg.Register(tools.NewSandboxedApplyPatchTool(workspace, agentCfg.RestrictToWorkspace, sandboxMgr))
 		toolsReg.Register(tools.NewSandboxedExecTool(workspace, agentCfg.RestrictToWorkspace, sandboxMgr))
 	} else {
 		toolsReg.Register(tools.NewReadFileTool(workspace, agentCfg.RestrictToWorkspace))
 		toolsReg.Register(tools.NewWriteFileTool(workspace, agentCfg.RestrictToWorkspace))
 		toolsReg.Register(tools.NewListFilesTool(workspace, agentCfg.RestrictToWorkspace))
+		toolsReg.Register(tools.NewSearchFilesTool(workspace, agentCfg.RestrictToWorkspace))
 		toolsReg.Register(tools.NewEditTool(workspace, agentCfg.RestrictToWorkspace))
+		toolsReg.Register(tools.NewApplyPatchTool(workspace, agentCfg.RestrictToWorkspace))
 		toolsReg.Register(tools.NewExecTool(workspace, agentCfg.RestrictToWorkspace))
 	}
 
@@ -86,7 +92,8 @@ func setupToolRegistry(
 	toolsReg.Register(tools.NewMemorySearchTool())
 	toolsReg.Register(tools.NewMemoryGetTool())
 	toolsReg.Register(tools.NewKnowledgeGraphSearchTool())
-	slog.Info("memory + knowledge graph tools registered (PG-backed)")
+	toolsReg.Register(tools.NewKBSearchTool())
+	slog.Info("memory + knowledge graph + knowledge base tools registered (PG-backed)")
 
 	// Browser automation tool
 	if cfg.Tools.Browser.Enabled {
@@ -270,6 +277,16 @@ func setupToolRegistry(
 			t.DenyPaths(internalDenyPaths...)
 		}
 	}
+	if ap, ok := toolsReg.Get("apply_patch"); ok {
+		if t, ok := ap.(*tools.ApplyPatchTool); ok {
+			t.DenyPaths(internalDenyPaths...)
+		}
+	}
+	if sf, ok := toolsReg.Get("search_files"); ok {
+		if t, ok := sf.(*tools.SearchFilesTool); ok {
+			t.DenyPaths(internalDenyPaths...)
+		}
+	}
 
 	return
 }
@@ -339,60 +356,77 @@ func wireTracingAndCron(
 	return traceCollector, snapshotWorker
 }
 
-// setupMemoryEmbeddings wires embedding provider to PGMemoryStore and triggers backfill.
-// Resolves embedding provider from DB providers with settings.embedding.enabled.
-func setupMemoryEmbeddings(
+// setupBudgetEnforcer creates the USD budget enforcer shared by agent loops,
+// quota.budget and GET /v1/budget. Soft-limit warnings go out as bus events
+// and owner chat messages. Returns nil when tracing is unavailable.
+func setupBudgetEnforcer(pgStores *store.Stores, msgBus *bus.MessageBus) *budget.Enforcer {
+	if pgStores.Tracing == nil {
+		return nil
+	}
+	var tenants budget.TenantReader
+	if pgStores.Tenants != nil {
+		tenants = pgStores.Tenants
+	}
+	enforcer := budget.NewEnforcer(pgStores.Tracing, tenants)
+	enforcer.SetWarningHandler(budget.BusNotifier(msgBus))
+	return enforcer
+}
+
+// setupResponseCache installs the shared LLM response cache on the provider
+// registry. Agents opt in via other_config.response_cache; semantic matching
+// additionally needs an embedding provider.
+func setupResponseCache(
 	pgStores *store.Stores,
 	providerRegistry *providers.Registry,
+	redisClient any,
 ) {
-	if pgStores.Memory != nil {
+	entries, index := makeResponseCaches(redisClient)
+	rc := providers.NewResponseCache(entries, index)
+	if pgStores.Providers != nil {
 		if embProvider := resolveEmbeddingProvider(pgStores.Providers, providerRegistry, pgStores.SystemConfigs); embProvider != nil {
-			pgStores.Memory.SetEmbeddingProvider(embProvider)
-			slog.Info("memory embeddings enabled", "provider", embProvider.Name(), "model", embProvider.Model())
+			rc.SetEmbedder(embProvider)
+			slog.Info("response cache semantic matching enabled", "provider", embProvider.Name(), "model", embProvider.Model())
+		}
+	}
+	providerRegistry.SetResponseCache(rc)
+}
 
-			// Backfill embeddings for existing chunks that were stored without vectors.
-			type backfiller interface {
-				BackfillEmbeddings(ctx context.Context) (int, error)
-			}
-			if bf, ok := pgStores.Memory.(backfiller); ok {
-				go func() {
-					bgCtx := context.Background()
-					count, err := bf.BackfillEmbeddings(bgCtx)
-					if err != nil {
-						slog.Warn("memory embeddings backfill failed", "error", err)
-					} else if count > 0 {
-						slog.Info("memory embeddings backfill complete", "chunks_updated", count)
-					}
-				}()
-			}
+// setupMemoryEmbeddings wires the embedding provider into the memory, team,
+// knowledge graph and knowledge base stores. Resolves embedding provider from
+// DB providers with settings.embedding.enabled. The re-embed check and the
+// backfills run later in startEmbeddingMaintenance; the provider is returned
+// for it (nil when none is configured).
+func setupMemoryEmbeddings(
+	pgStores *store.Stores,
+	providerRegistry *providers.Registry,
+) memory.EmbeddingProvider {
+	if pgStores.Memory == nil {
+		return nil
+	}
+	embProvider := resolveSharedEmbeddingProvider(pgStores, providerRegistry)
+	if embProvider == nil {
+		slog.Warn("memory embeddings disabled (no API key), chunks stored without vectors")
+		return nil
+	}
 
-			// Wire embedding provider into team store for semantic task search.
-			if pgTeamStore, ok := pgStores.Teams.(*pg.PGTeamStore); ok {
-				pgTeamStore.SetEmbeddingProvider(embProvider)
-				go func() {
-					if count, err := pgTeamStore.BackfillTaskEmbeddings(context.Background()); err != nil {
-						slog.Warn("task embeddings backfill failed", "error", err)
-					} else if count > 0 {
-						slog.Info("task embeddings backfill complete", "tasks_updated", count)
-					}
-				}()
-			}
+	pgStores.Memory.SetEmbeddingProvider(embProvider)
+	slog.Info("memory embeddings enabled", "provider", embProvider.Name(), "model", embProvider.Model())
 
-			// Wire embedding provider into KG store for entity semantic search.
-			if pgKG, ok := pgStores.KnowledgeGraph.(*pg.PGKnowledgeGraphStore); ok {
-				pgKG.SetEmbeddingProvider(embProvider)
-				go func() {
-					if count, err := pgKG.BackfillKGEmbeddings(context.Background()); err != nil {
-						slog.Warn("KG embeddings backfill failed", "error", err)
-					} else if count > 0 {
-						slog.Info("KG embeddings backfill complete", "entities_updated", count)
-					}
-				}()
-			}
-		} else {
-			slog.Warn("memory embeddings disabled (no API key), chunks stored without vectors")
-		}
+	// Wire embedding provider into team store for semantic task search.
+	if pgTeamStore, ok := pgStores.Teams.(*pg.PGTeamStore); ok {
+		pgTeamStore.SetEmbeddingProvider(embProvider)
 	}
+
+	// Wire embedding provider into KG store for entity semantic search.
+	if pgStores.KnowledgeGraph != nil {
+		pgStores.KnowledgeGraph.SetEmbeddingProvider(embProvider)
+	}
+
+	// Wire embedding provider into knowledge base store for document retrieval.
+	if pgStores.KnowledgeBases != nil {
+		pgStores.KnowledgeBases.SetEmbeddingProvider(embProvider)
+	}
+	return embProvider
 }
 
 // seedSystemConfigs ensures system_configs has all expected keys for all tenants.
@@ -552,20 +586,10 @@ func setupSkillsSystem(
 			skillSearchTool.SetSkillAccessStore(sas)
 		}
 		if pgSkills, ok := pgStores.Skills.(*pg.PGSkillStore); ok {
-			if embProvider := resolveEmbeddingProvider(pgStores.Providers, providerRegistry, pgStores.SystemConfigs); embProvider != nil {
+			if embProvider := resolveSharedEmbeddingProvider(pgStores, providerRegistry); embProvider != nil {
 				pgSkills.SetEmbeddingProvider(embProvider)
 				skillSearchTool.SetEmbeddingSearcher(pgSkills, embProvider)
 				slog.Info("skill embeddings enabled", "provider", embProvider.Name())
-
-				// Backfill embeddings for existing skills
-				go func() {
-					count, err := pgSkills.BackfillSkillEmbeddings(context.Background())
-					if err != nil {
-						slog.Warn("skill embeddings backfill failed", "error", err)
-					} else if count > 0 {
-						slog.Info("skill embeddings backfill complete", "skills_updated", count)
-					}
-				}()
 			}
 		}
 	}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"log/slog"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+// setupToolApprovals installs the human approval gate for arbitrary tool calls
+// on the registry. Returns nil when no approval rules are configured.
+func setupToolApprovals(cfg *config.Config, toolsReg *tools.Registry, msgBus *bus.MessageBus) *tools.ToolApprovalManager {
+	if len(cfg.Tools.Approvals.Rules) == 0 {
+		return nil
+	}
+	mgr := tools.NewToolApprovalManager(cfg.Tools.Approvals, msgBus)
+	toolsReg.SetApprovalManager(mgr)
+	slog.Info("tool approval enabled", "rules", len(cfg.Tools.Approvals.Rules))
+	return mgr
+}
+
+// wireToolApprovals routes approval prompts to chat channels as interactive
+// buttons and feeds button presses back into the approval manager.
+func wireToolApprovals(mgr *tools.ToolApprovalManager, channelMgr *channels.Manager) {
+	if mgr == nil {
+		return
+	}
+	mgr.SetPrompter(channelMgr.SendApprovalPrompt)
+	channelMgr.SetApprovalResolver(mgr.ResolveFromChannel)
+}
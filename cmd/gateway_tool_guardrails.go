@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"log/slog"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+// setupToolGuardrails installs content-aware argument/result rules on the registry.
+// Always installed so per-agent rules from tools_config apply even when no
+// global rules are configured.
+func setupToolGuardrails(cfg *config.Config, toolsReg *tools.Registry, msgBus *bus.MessageBus) {
+	toolsReg.SetGuardrails(tools.NewToolGuardrails(cfg.Tools.Guardrails, msgBus))
+	if len(cfg.Tools.Guardrails) > 0 {
+		slog.Info("tool guardrails enabled", "rules", len(cfg.Tools.Guardrails))
+	}
+}
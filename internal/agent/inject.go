@@ -1,6 +1,7 @@
 package agent
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -29,7 +30,7 @@ const injectBufferSize = 5
 
 // processInjectedMessage validates and wraps an injected message for the LLM.
 // Returns nil, false if the message should be skipped (blocked by input guard).
-func (l *Loop) processInjectedMessage(injected InjectedMessage, emitRun func(AgentEvent)) (*processedInjection, bool) {
+func (l *Loop) processInjectedMessage(ctx context.Context, injected InjectedMessage, piiDest string, emitRun func(AgentEvent)) (*processedInjection, bool) {
 	// Security: scan injected content with input guard
 	if l.inputGuard != nil {
 		if matches := l.inputGuard.Scan(injected.Content); len(matches) > 0 {
@@ -47,7 +48,7 @@ func (l *Loop) processInjectedMessage(injected InjectedMessage, emitRun func(Age
 	}
 
 	// Truncate oversized content
-	content := injected.Content
+	content := l.redactPII(ctx, piiDest, "followup", injected.UserID, injected.Content)
 	maxChars := l.maxMessageChars
 	if maxChars <= 0 {
 		maxChars = config.DefaultMaxMessageChars
@@ -83,14 +84,15 @@ func (l *Loop) processInjectedMessage(injected InjectedMessage, emitRun func(Age
 
 // drainInjectChannel reads all available messages from the injection channel
 // without blocking. Returns processed messages ready to append to the loop.
-func (l *Loop) drainInjectChannel(ch <-chan InjectedMessage, emitRun func(AgentEvent)) (forLLM, forSession []providers.Message) {
+// piiDest scopes PII tokens to the run's chat (see piiDestination).
+func (l *Loop) drainInjectChannel(ctx context.Context, ch <-chan InjectedMessage, piiDest string, emitRun func(AgentEvent)) (forLLM, forSession []providers.Message) {
 	if ch == nil {
 		return nil, nil
 	}
 	for {
 		select {
 		case injected := <-ch:
-			if result, ok := l.processInjectedMessage(injected, emitRun); ok {
+			if result, ok := l.processInjectedMessage(ctx, injected, piiDest, emitRun); ok {
 				forLLM = append(forLLM, result.forLLM)
 				forSession = append(forSession, result.forSession)
 			}
@@ -2,6 +2,7 @@ package agent
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -30,7 +31,20 @@ Classify the user's intent into exactly ONE of these categories:
 - steer: The user wants to add instructions or redirect the current task (e.g., "also check X", "focus on Y instead", "thêm phần Z nữa")
 - new_task: The user is sending a new unrelated request or message
 
-Respond with ONLY the category name, nothing else.`
+Respond with ONLY a JSON object of the form {"intent": "<category>"}, nothing else.`
+
+// intentSchema constrains the classifier's reply to one of the intent names.
+var intentSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"intent": map[string]any{
+			"type": "string",
+			"enum": []string{string(IntentStatusQuery), string(IntentCancel), string(IntentSteer), string(IntentNewTask)},
+		},
+	},
+	"required":             []string{"intent"},
+	"additionalProperties": false,
+}
 
 // cancelKeywords for fast-path detection of obvious cancel intents.
 // Only matched on very short messages (≤ 15 runes) to avoid false positives
@@ -106,15 +120,26 @@ func ClassifyIntent(ctx context.Context, provider providers.Provider, model, use
 		},
 		Model: model,
 		Options: map[string]any{
-			providers.OptMaxTokens:   20,
+			providers.OptMaxTokens:   40,
 			providers.OptTemperature: 0.0,
 		},
+		ResponseFormat: providers.JSONSchemaResponse("intent", intentSchema),
 	})
 	if err != nil {
 		return IntentNewTask
 	}
 
 	result := strings.TrimSpace(strings.ToLower(resp.Content))
+	var parsed struct {
+		Intent IntentType `json:"intent"`
+	}
+	if json.Unmarshal([]byte(result), &parsed) == nil {
+		switch parsed.Intent {
+		case IntentStatusQuery, IntentCancel, IntentSteer, IntentNewTask:
+			return parsed.Intent
+		}
+	}
+	// Providers without schema support may still answer in free text.
 	switch {
 	case strings.Contains(result, "status_query"):
 		return IntentStatusQuery
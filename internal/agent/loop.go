@@ -144,22 +144,14 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 		maxIter = req.MaxIterations
 	}
 
-	// Budget check: query monthly spent once before starting iterations.
-	if l.budgetMonthlyCents > 0 && l.tracingStore != nil && l.agentUUID != uuid.Nil {
-		now := time.Now().UTC()
-		spent, err := l.tracingStore.GetMonthlyAgentCost(ctx, l.agentUUID, now.Year(), now.Month())
-		if err == nil {
-			spentCents := int(spent * 100)
-			if spentCents >= l.budgetMonthlyCents {
-				slog.Warn("agent budget exceeded", "agent", l.id, "spent_cents", spentCents, "budget_cents", l.budgetMonthlyCents)
-				return nil, fmt.Errorf("monthly budget exceeded ($%.2f / $%.2f)", spent, float64(l.budgetMonthlyCents)/100)
-			}
-		}
-	}
-
 	for rs.iteration < maxIter {
 		rs.iteration++
 
+		// Budget check: re-run before every LLM call so spend within this run counts.
+		if err := l.checkBudget(ctx, &req); err != nil {
+			return nil, err
+		}
+
 		slog.Debug("agent iteration", "agent", l.id, "iteration", rs.iteration, "messages", len(messages))
 
 		// Skill evolution: budget pressure nudges at 70% and 90% of iteration budget.
@@ -266,6 +258,7 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 		if tid := store.TenantIDFromContext(ctx); tid != uuid.Nil {
 			chatReq.Options[providers.OptTenantID] = tid.String()
 		}
+		chatReq.ResponseFormat = req.ResponseFormat
 		reasoningDecision := providers.ResolveReasoningDecision(
 			provider,
 			model,
@@ -290,6 +283,8 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 		var err error
 
 		callCtx := providers.WithChatGPTOAuthRoutingObservation(ctx, providers.NewChatGPTOAuthRoutingObservation())
+		callCtx = providers.WithFailoverObservation(callCtx, providers.NewFailoverObservation())
+		callCtx = providers.WithResponseCacheObservation(callCtx, providers.NewResponseCacheObservation())
 		if reasoningDecision.HasObservation() {
 			callCtx = providers.WithReasoningDecision(callCtx, reasoningDecision)
 		}
@@ -325,6 +320,7 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 		}
 
 		l.emitLLMSpanEnd(callCtx, llmSpanID, llmSpanStart, resp, nil, withModel(model), withProvider(provider.Name()))
+		l.recordBudgetSpend(callCtx, &req, model, provider.Name(), resp)
 
 		// For non-streaming responses, emit thinking and content as single events
 		if !req.Stream {
@@ -365,7 +361,7 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 
 			// Calibrate overhead on first LLM response with usage data.
 			if !rs.overheadCalibrated && resp.Usage != nil && resp.Usage.PromptTokens > 0 {
-				historyEst := EstimateHistoryTokens(messages)
+				historyEst := EstimateHistoryTokens(l.tokenizer, messages)
 				rs.overheadTokens = max(resp.Usage.PromptTokens-historyEst, 0)
 				rs.overheadCalibrated = true
 			}
@@ -375,16 +371,16 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 			if resp.Usage != nil && resp.Usage.PromptTokens > 0 && rs.overheadCalibrated {
 				historyTokens = resp.Usage.PromptTokens - rs.overheadTokens
 			} else {
-				historyTokens = EstimateHistoryTokens(messages)
+				historyTokens = EstimateHistoryTokens(l.tokenizer, messages)
 			}
 
 			// Phase 1: Prune old tool results before resorting to full compaction (at 70% of budget).
 			// Re-triggers each iteration — new tool results may have grown context since last prune.
 			if historyTokens >= int(float64(historyBudget)*0.7) {
-				pruned := pruneContextMessages(messages, l.contextWindow, l.contextPruningCfg)
+				pruned := pruneContextMessages(messages, l.contextWindow, l.contextPruningCfg, l.tokenizer)
 				if len(pruned) > 0 {
 					messages = pruned
-					historyTokens = EstimateHistoryTokens(messages)
+					historyTokens = EstimateHistoryTokens(l.tokenizer, messages)
 				}
 				slog.Info("mid_loop_pruning",
 					"agent", l.id,
@@ -456,7 +452,7 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 			// Mid-run injection (Point B): drain all buffered user follow-up messages
 			// before exiting. If found, save current assistant response and continue
 			// the loop so the LLM can respond to the injected messages.
-			if forLLM, forSession := l.drainInjectChannel(req.InjectCh, emitRun); len(forLLM) > 0 {
+			if forLLM, forSession := l.drainInjectChannel(ctx, req.InjectCh, piiDestination(&req), emitRun); len(forLLM) > 0 {
 				messages = append(messages, providers.Message{Role: "assistant", Content: resp.Content})
 				messages = append(messages, forLLM...)
 				rs.pendingMsgs = append(rs.pendingMsgs, providers.Message{Role: "assistant", Content: resp.Content})
@@ -464,6 +460,24 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 				continue
 			}
 
+			// Structured output: validate the final answer; invalid output gets one repair turn.
+			if req.ResponseFormat.IsJSON() {
+				content, verr := providers.CheckStructuredOutput(req.ResponseFormat, resp.Content)
+				if verr != nil && !rs.formatRepaired && resp.FinishReason != "length" {
+					rs.formatRepaired = true
+					slog.Warn("structured output invalid, requesting repair", "agent", l.id, "iteration", rs.iteration, "error", verr)
+					messages = append(messages,
+						providers.Message{Role: "assistant", Content: resp.Content},
+						providers.Message{Role: "user", Content: providers.RepairPrompt(req.ResponseFormat, verr)},
+					)
+					continue
+				}
+				if verr != nil {
+					slog.Warn("structured output invalid after repair", "agent", l.id, "error", verr)
+				}
+				resp.Content = content
+			}
+
 			rs.finalContent = resp.Content
 			rs.finalThinking = resp.Thinking
 			break
@@ -588,7 +602,8 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 			}
 			stopSlowTimer()
 
-			l.emitToolSpanEnd(ctx, toolSpanID, toolSpanStart, result)
+			verdict := l.classifyToolResult(iterCtx, registryName, result)
+			l.emitToolSpanEnd(ctx, toolSpanID, toolSpanStart, result, verdict)
 
 			// Record tool execution time for adaptive thresholds.
 			toolTiming.Record(tc.Name, time.Since(toolSpanStart).Milliseconds())
@@ -671,7 +686,8 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 						result = l.tools.ExecuteWithContext(iterCtx, registryName, tc.Arguments, req.Channel, req.ChatID, req.PeerKind, req.SessionKey, nil)
 					}
 					stopSlowTimer()
-					l.emitToolSpanEnd(ctx, spanID, spanStart, result)
+					verdict := l.classifyToolResult(iterCtx, registryName, result)
+					l.emitToolSpanEnd(ctx, spanID, spanStart, result, verdict)
 					resultCh <- indexedResult{idx: idx, tc: tc, registryName: registryName, result: result, argsJSON: string(argsJSON), spanStart: spanStart}
 				}(i, tc)
 			}
@@ -744,7 +760,7 @@ func (l *Loop) runLoop(ctx context.Context, req RunRequest) (result *RunResult,
 		// Mid-run injection (Point A): drain any user follow-up messages
 		// that arrived during tool execution. Append them after tool results
 		// so the next LLM call sees: [tool results...] + [user follow-ups...].
-		if forLLM, forSession := l.drainInjectChannel(req.InjectCh, emitRun); len(forLLM) > 0 {
+		if forLLM, forSession := l.drainInjectChannel(ctx, req.InjectCh, piiDestination(&req), emitRun); len(forLLM) > 0 {
 			messages = append(messages, forLLM...)
 			rs.pendingMsgs = append(rs.pendingMsgs, forSession...)
 		}
@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/tracing"
+)
+
+// budgetScope identifies the tenant, agent and channel user a run spends for.
+func (l *Loop) budgetScope(req *RunRequest) budget.Scope {
+	return budget.Scope{
+		TenantID: l.tenantID,
+		AgentID:  l.agentUUID,
+		AgentKey: l.id,
+		UserID:   req.UserID,
+		Agent:    l.budgetCfg,
+	}
+}
+
+// checkBudget returns an error when any tenant, agent or user cap is reached.
+// Called before every LLM iteration so a runaway tool loop stops mid-run.
+func (l *Loop) checkBudget(ctx context.Context, req *RunRequest) error {
+	if l.budget == nil {
+		return nil
+	}
+	if err := l.budget.Check(ctx, l.budgetScope(req)); err != nil {
+		slog.Warn("agent budget exceeded", "agent", l.id, "user", req.UserID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// recordBudgetSpend charges the cost of one LLM call against the run's caps,
+// priced against the failover member that actually served it. Responses
+// served from the response cache cost nothing.
+func (l *Loop) recordBudgetSpend(ctx context.Context, req *RunRequest, model, providerName string, resp *providers.ChatResponse) {
+	if l.budget == nil || resp == nil || resp.Usage == nil {
+		return
+	}
+	if providers.ResponseCacheObservationFromContext(ctx).Snapshot().Served() {
+		return
+	}
+	model, providerName = servingModel(ctx, model, providerName)
+	pricing := tracing.LookupPricing(l.modelPricing, providerName, model)
+	if pricing == nil {
+		return
+	}
+	l.budget.Record(ctx, l.budgetScope(req), tracing.CalculateCost(pricing, resp.Usage))
+}
+
+// servingModel returns the model and provider that actually answered the call.
+// A failover chain records its serving member even when no failover happened
+// from the caller's point of view (e.g. the primary was skipped during
+// cooldown), so attribution keys on that member directly rather than on
+// whether the chain failed over.
+func servingModel(ctx context.Context, model, providerName string) (string, string) {
+	failover := providers.FailoverObservationFromContext(ctx).Snapshot()
+	if failover.ServingProvider == "" {
+		return model, providerName
+	}
+	if failover.ServingModel != "" {
+		model = failover.ServingModel
+	}
+	return model, failover.ServingProvider
+}
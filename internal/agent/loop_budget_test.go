@@ -0,0 +1,27 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+func TestServingModelUsesFailoverServingMember(t *testing.T) {
+	model, provider := servingModel(context.Background(), "claude-sonnet", "anthropic")
+	if model != "claude-sonnet" || provider != "anthropic" {
+		t.Fatalf("no observation: got %s/%s", provider, model)
+	}
+
+	// Primary in cooldown: the chain never attempted it and went straight to the fallback.
+	observation := providers.NewFailoverObservation()
+	observation.SetChain([]string{"anthropic", "openrouter"})
+	observation.RecordAttempt("openrouter")
+	observation.RecordSuccess("openrouter", "router-default")
+	ctx := providers.WithFailoverObservation(context.Background(), observation)
+
+	model, provider = servingModel(ctx, "claude-sonnet", "anthropic")
+	if model != "router-default" || provider != "openrouter" {
+		t.Fatalf("cooldown fallback: got %s/%s, want openrouter/router-default", provider, model)
+	}
+}
@@ -81,6 +81,9 @@ func (l *Loop) injectContext(ctx context.Context, req *RunRequest) (contextSetup
 	if l.sandboxCfg != nil {
 		ctx = tools.WithSandboxConfig(ctx, l.sandboxCfg)
 	}
+	if l.agentToolPolicy != nil && len(l.agentToolPolicy.Guardrails) > 0 {
+		ctx = tools.WithToolGuardrails(ctx, l.agentToolPolicy.Guardrails)
+	}
 	if l.shellDenyGroups != nil {
 		ctx = store.WithShellDenyGroups(ctx, l.shellDenyGroups)
 	}
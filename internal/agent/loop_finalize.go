@@ -50,7 +50,7 @@ func (l *Loop) finalizeRun(
 	// 5b. Skill evolution: postscript suggestion after complex tasks.
 	if l.skillEvolve && l.skillNudgeInterval > 0 &&
 		rs.totalToolCalls >= l.skillNudgeInterval &&
-		rs.finalContent != "" && !isSilent && !rs.skillPostscriptSent {
+		rs.finalContent != "" && !isSilent && !rs.skillPostscriptSent && !req.ResponseFormat.IsJSON() {
 		rs.skillPostscriptSent = true
 		locale := store.LocaleFromContext(ctx)
 		rs.finalContent += "\n\n---\n_" + i18n.T(locale, i18n.MsgSkillNudgePostscript) + "_"
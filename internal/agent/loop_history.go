@@ -319,7 +319,7 @@ func (l *Loop) buildMessages(ctx context.Context, history []providers.Message, s
 
 	// History pipeline matching TS: limitHistoryTurns → pruneContext → sanitizeHistory.
 	trimmed := limitHistoryTurns(history, historyLimit)
-	pruned := pruneContextMessages(trimmed, l.contextWindow, l.contextPruningCfg)
+	pruned := pruneContextMessages(trimmed, l.contextWindow, l.contextPruningCfg, l.tokenizer)
 	sanitized, droppedCount := sanitizeHistory(pruned)
 	messages = append(messages, sanitized...)
 
@@ -632,7 +632,7 @@ func (l *Loop) maybeSummarize(ctx context.Context, sessionKey string) {
 	// We subtract estimated overhead so the threshold comparison is history-only.
 	lastPT, lastMC := l.sessions.GetLastPromptTokens(ctx, sessionKey)
 	adjustedLastPT := max(lastPT-l.estimateOverhead(history, lastPT, lastMC), 0)
-	tokenEstimate := EstimateTokensWithCalibration(history, adjustedLastPT, lastMC)
+	tokenEstimate := EstimateTokensWithCalibration(l.tokenizer, history, adjustedLastPT, lastMC)
 
 	// Resolve compaction threshold from config: token-only (no message count guard).
 	// Industry standard — Claude Code, Anthropic API, LangChain all use token-based thresholds.
@@ -753,7 +753,7 @@ func (l *Loop) estimateOverhead(history []providers.Message, lastPromptTokens, l
 
 	// Overhead = total prompt tokens - estimated history tokens at calibration time.
 	count := min(lastMsgCount, len(history))
-	historyEstAtCalibration := EstimateHistoryTokens(history[:count])
+	historyEstAtCalibration := EstimateHistoryTokens(l.tokenizer, history[:count])
 	overhead := max(lastPromptTokens-historyEstAtCalibration, 0)
 	// Clamp: overhead shouldn't exceed 40% of context window.
 	maxOverhead := int(float64(l.contextWindow) * 0.4)
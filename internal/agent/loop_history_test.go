@@ -6,6 +6,7 @@ import (
 
 	"github.com/nextlevelbuilder/goclaw/internal/config"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/tokenizer"
 )
 
 func TestLimitHistoryTurns_NoLimit(t *testing.T) {
@@ -521,7 +522,7 @@ func TestEstimateTokens(t *testing.T) {
 		{Role: "user", Content: "Hello world!"},                // 12 chars → ~4 tokens
 		{Role: "assistant", Content: "Hi there, how are you?"}, // 22 chars → ~7 tokens
 	}
-	got := EstimateTokens(msgs)
+	got := EstimateTokens(nil, msgs)
 	if got <= 0 {
 		t.Errorf("expected positive token estimate, got %d", got)
 	}
@@ -540,8 +541,8 @@ func TestEstimateTokensIncludesToolCalls(t *testing.T) {
 		}},
 	}
 
-	contentTokens := EstimateTokens(contentOnly)
-	toolTokens := EstimateTokens(withToolCalls)
+	contentTokens := EstimateTokens(nil, contentOnly)
+	toolTokens := EstimateTokens(nil, withToolCalls)
 
 	if toolTokens <= contentTokens {
 		t.Errorf("expected tool call tokens (%d) > content-only tokens (%d)", toolTokens, contentTokens)
@@ -555,15 +556,15 @@ func TestEstimateHistoryTokensSkipsSystem(t *testing.T) {
 		{Role: "assistant", Content: "Hi there!"},
 	}
 
-	allTokens := EstimateTokens(msgs)
-	historyTokens := EstimateHistoryTokens(msgs)
+	allTokens := EstimateTokens(nil, msgs)
+	historyTokens := EstimateHistoryTokens(nil, msgs)
 
 	if historyTokens >= allTokens {
 		t.Errorf("history tokens (%d) should be less than all tokens (%d)", historyTokens, allTokens)
 	}
 
 	// History should only count user + assistant messages.
-	expectedHistory := EstimateTokens(msgs[1:])
+	expectedHistory := EstimateTokens(nil, msgs[1:])
 	if historyTokens != expectedHistory {
 		t.Errorf("history tokens (%d) != expected (%d)", historyTokens, expectedHistory)
 	}
@@ -621,7 +622,7 @@ func TestPruneContextMessagesDefaultEnabled(t *testing.T) {
 
 	// With nil config and small context window (to trigger soft trim ratio > 0.3),
 	// pruning should trim the large tool result.
-	result := pruneContextMessages(msgs, 5000, nil)
+	result := pruneContextMessages(msgs, 5000, nil, nil)
 
 	// The large tool result should have been trimmed.
 	toolMsg := result[2]
@@ -630,12 +631,38 @@ func TestPruneContextMessagesDefaultEnabled(t *testing.T) {
 	}
 }
 
+func TestPruneContextMessagesPerResultGuardCountsTokens(t *testing.T) {
+	// ~6k tokens of prose in a single old tool result against a 10k window:
+	// above the 30% per-result guard, so it is trimmed to fit.
+	large := strings.Repeat("The deployment finished and all health checks passed. ", 600)
+	msgs := []providers.Message{
+		{Role: "user", Content: "Check the deploy"},
+		{Role: "assistant", Content: "Checking.", ToolCalls: []providers.ToolCall{{ID: "tc1", Name: "exec"}}},
+		{Role: "tool", Content: large, ToolCallID: "tc1"},
+		{Role: "assistant", Content: "Done."},
+		{Role: "user", Content: "Thanks"},
+		{Role: "assistant", Content: "Anytime."},
+		{Role: "user", Content: "Bye"},
+		{Role: "assistant", Content: "Bye."},
+	}
+
+	tok := tokenizer.Get(tokenizer.NameCL100k)
+	result := pruneContextMessages(msgs, 10000, nil, tok)
+	got := tokenizer.CountMessage(tok, result[2])
+	if got >= tokenizer.CountMessage(tok, msgs[2]) {
+		t.Fatal("expected the oversized tool result to be trimmed")
+	}
+	if got > 3000+100 {
+		t.Errorf("trimmed result has %d tokens, want about the 3000-token per-result limit", got)
+	}
+}
+
 func TestPruneContextMessagesExplicitOff(t *testing.T) {
 	msgs := []providers.Message{
 		{Role: "user", Content: "Hello"},
 	}
 	cfg := &config.ContextPruningConfig{Mode: "off"}
-	result := pruneContextMessages(msgs, 200000, cfg)
+	result := pruneContextMessages(msgs, 200000, cfg, nil)
 	// Should return original messages unchanged.
 	if len(result) != len(msgs) {
 		t.Errorf("expected %d messages, got %d", len(msgs), len(result))
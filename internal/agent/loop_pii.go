@@ -0,0 +1,47 @@
+package agent
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/nextlevelbuilder/goclaw/internal/pii"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+)
+
+// redactPII replaces personal data in inbound text before the provider,
+// session history and traces see it. In tokenize mode the originals are kept
+// in the redactor's vault under dest (the run's chat) and re-hydrated only
+// when a reply is delivered back to that chat.
+func (l *Loop) redactPII(ctx context.Context, dest, source, userID, text string) string {
+	if !l.pii.Enabled() || text == "" {
+		return text
+	}
+	redacted, findings := l.pii.Redact(store.TenantIDFromContext(ctx), dest, text)
+	if len(findings) > 0 {
+		types := make([]string, 0, len(findings))
+		for _, f := range findings {
+			types = append(types, f.Type)
+		}
+		slog.Info("security.pii_redacted",
+			"agent", l.id, "user", userID, "source", source,
+			"count", len(findings), "types", types, "mode", l.pii.Mode(),
+		)
+	}
+	return redacted
+}
+
+// redactToolResultPII redacts personal data in a tool result before the LLM sees it
+// (only when tool result redaction is enabled).
+func (l *Loop) redactToolResultPII(ctx context.Context, req *RunRequest, toolName string, result *tools.Result) {
+	if !l.pii.ToolResults() || result == nil {
+		return
+	}
+	result.ForLLM = l.redactPII(ctx, piiDestination(req), "tool:"+toolName, req.UserID, result.ForLLM)
+}
+
+// piiDestination is the chat a run's replies are delivered to, which scopes
+// the PII tokens recorded during the run.
+func piiDestination(req *RunRequest) string {
+	return pii.Destination(req.Channel, req.ChatID)
+}
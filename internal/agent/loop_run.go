@@ -19,6 +19,9 @@ func (l *Loop) Run(ctx context.Context, req RunRequest) (*RunResult, error) {
 	l.activeRuns.Add(1)
 	defer l.activeRuns.Add(-1)
 
+	// Privacy: redact personal data before events, traces, history and the provider see it.
+	req.Message = l.redactPII(ctx, piiDestination(&req), "message", req.UserID, req.Message)
+
 	// Per-run emit wrapper: enriches every AgentEvent with delegation + routing context.
 	emitRun := func(event AgentEvent) {
 		event.RunKind = req.RunKind
@@ -12,6 +12,7 @@ import (
 
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tokenizer"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
 	"github.com/nextlevelbuilder/goclaw/internal/tracing"
 )
@@ -140,11 +141,24 @@ func (l *Loop) emitLLMSpanEnd(ctx context.Context, spanID uuid.UUID, start time.
 	}
 	var spanMetadata json.RawMessage
 
+	// When a failover chain served the call from a fallback member, price and
+	// attribute the span against that member instead of the configured primary.
+	failover := providers.FailoverObservationFromContext(ctx).Snapshot()
+	if failover.FailedOver() {
+		opts = append(opts, withProvider(failover.ServingProvider))
+		if failover.ServingModel != "" {
+			opts = append(opts, withModel(failover.ServingModel))
+		}
+	}
+
+	responseCache := providers.ResponseCacheObservationFromContext(ctx).Snapshot()
+
 	if callErr != nil {
 		updates["status"] = store.SpanStatusError
 		updates["error"] = callErr.Error()
 	} else if resp != nil {
-		if resp.Usage != nil {
+		// Cached responses made no provider call, so they carry no token usage or cost.
+		if resp.Usage != nil && !responseCache.Served() {
 			updates["input_tokens"] = resp.Usage.PromptTokens
 			updates["output_tokens"] = resp.Usage.CompletionTokens
 			hasMeta := resp.Usage.CacheCreationTokens > 0 || resp.Usage.CacheReadTokens > 0 || resp.Usage.ThinkingTokens > 0
@@ -166,7 +180,7 @@ func (l *Loop) emitLLMSpanEnd(ctx context.Context, spanID uuid.UUID, start time.
 		}
 		// Calculate cost if pricing config is available.
 		model, providerName := l.resolveSpan(opts)
-		if pricing := tracing.LookupPricing(l.modelPricing, providerName, model); pricing != nil {
+		if pricing := tracing.LookupPricing(l.modelPricing, providerName, model); pricing != nil && !responseCache.Served() {
 			cost := tracing.CalculateCost(pricing, resp.Usage)
 			if cost > 0 {
 				updates["total_cost"] = cost
@@ -189,6 +203,18 @@ func (l *Loop) emitLLMSpanEnd(ctx context.Context, spanID uuid.UUID, start time.
 			}
 		}
 	}
+	if responseCache.HasData() {
+		spanMetadata = providers.MergeResponseCacheMetadata(spanMetadata, responseCache)
+	}
+	if failover.HasData() {
+		spanMetadata = providers.MergeFailoverMetadata(spanMetadata, failover)
+		if failover.FailedOver() {
+			updates["provider"] = failover.ServingProvider
+			if failover.ServingModel != "" {
+				updates["model"] = failover.ServingModel
+			}
+		}
+	}
 	if decision := providers.ReasoningDecisionFromContext(ctx); decision != nil {
 		spanMetadata = providers.MergeReasoningMetadata(spanMetadata, *decision)
 	}
@@ -246,9 +272,10 @@ func (l *Loop) emitToolSpanStart(ctx context.Context, start time.Time, toolName,
 }
 
 // emitToolSpanEnd finalizes a running tool span with execution results.
+// A non-nil injection verdict is recorded in the span metadata.
 // Uses EmitSpanUpdate (channel send) — safe after ctx cancellation.
 // Goroutine-safe: only does a channel send via EmitSpanUpdate.
-func (l *Loop) emitToolSpanEnd(ctx context.Context, spanID uuid.UUID, start time.Time, result *tools.Result) {
+func (l *Loop) emitToolSpanEnd(ctx context.Context, spanID uuid.UUID, start time.Time, result *tools.Result, injection *InjectionVerdict) {
 	if spanID == uuid.Nil {
 		return // tracing disabled
 	}
@@ -273,6 +300,11 @@ func (l *Loop) emitToolSpanEnd(ctx context.Context, spanID uuid.UUID, start time
 		updates["error"] = truncateStr(result.ForLLM, 200)
 	}
 
+	meta := map[string]any{}
+	if injection != nil {
+		meta["injection"] = injection
+	}
+
 	// Record token usage from tools that make internal LLM calls (e.g. read_image).
 	if result.Usage != nil {
 		updates["input_tokens"] = result.Usage.PromptTokens
@@ -280,13 +312,8 @@ func (l *Loop) emitToolSpanEnd(ctx context.Context, spanID uuid.UUID, start time
 		updates["provider"] = result.Provider
 		updates["model"] = result.Model
 		if result.Usage.CacheCreationTokens > 0 || result.Usage.CacheReadTokens > 0 {
-			meta := map[string]int{
-				"cache_creation_tokens": result.Usage.CacheCreationTokens,
-				"cache_read_tokens":     result.Usage.CacheReadTokens,
-			}
-			if b, err := json.Marshal(meta); err == nil {
-				updates["metadata"] = b
-			}
+			meta["cache_creation_tokens"] = result.Usage.CacheCreationTokens
+			meta["cache_read_tokens"] = result.Usage.CacheReadTokens
 		}
 		// Calculate cost for tool's internal LLM calls.
 		provider := result.Provider
@@ -299,6 +326,12 @@ func (l *Loop) emitToolSpanEnd(ctx context.Context, spanID uuid.UUID, start time
 		}
 	}
 
+	if len(meta) > 0 {
+		if b, err := json.Marshal(meta); err == nil {
+			updates["metadata"] = b
+		}
+	}
+
 	collector.EmitSpanUpdate(spanID, traceID, updates)
 }
 
@@ -404,46 +437,23 @@ func truncateStr(s string, maxLen int) string {
 	return "..." + s[start:]
 }
 
-// estimateMessageTokens returns a rough token estimate for a single message,
-// including content text and tool call arguments.
-func estimateMessageTokens(m providers.Message) int {
-	tokens := utf8.RuneCountInString(m.Content) / 3
-	for _, tc := range m.ToolCalls {
-		tokens += len(tc.ID)/3 + len(tc.Name)/3
-		for k, v := range tc.Arguments {
-			tokens += len(k) / 3
-			switch val := v.(type) {
-			case string:
-				tokens += len(val) / 3
-			default:
-				tokens += 10 // small fixed estimate for non-string args (numbers, booleans, etc.)
-			}
-		}
-	}
-	return tokens
-}
-
-// EstimateTokens returns a rough token estimate for a slice of messages.
-// Includes content text and tool call arguments (JSON overhead).
-// Used internally for summarization thresholds and externally for adaptive throttle.
-func EstimateTokens(messages []providers.Message) int {
-	total := 0
-	for _, m := range messages {
-		total += estimateMessageTokens(m)
-	}
-	return total
+// EstimateTokens counts the tokens of a slice of messages with tok (nil uses
+// the default tokenizer), including tool call arguments and framing.
+// Used for summarization thresholds and externally for adaptive throttle.
+func EstimateTokens(tok tokenizer.Tokenizer, messages []providers.Message) int {
+	return tokenizer.CountMessages(tok, messages)
 }
 
-// EstimateHistoryTokens estimates tokens for history messages only,
+// EstimateHistoryTokens counts tokens for history messages only,
 // excluding system messages (which are overhead: system prompt, tool defs, context files).
 // Used for compaction threshold checks where we need history-only token count.
-func EstimateHistoryTokens(messages []providers.Message) int {
+func EstimateHistoryTokens(tok tokenizer.Tokenizer, messages []providers.Message) int {
 	total := 0
 	for _, m := range messages {
 		if m.Role == "system" {
 			continue
 		}
-		total += estimateMessageTokens(m)
+		total += tokenizer.CountMessage(tok, m)
 	}
 	return total
 }
@@ -451,23 +461,6 @@ func EstimateHistoryTokens(messages []providers.Message) int {
 // EstimateTokensWithCalibration uses actual prompt tokens from the last LLM
 // response as a calibration base, then estimates only new messages on top.
 // Falls back to EstimateTokens() when no calibration data is available.
-func EstimateTokensWithCalibration(messages []providers.Message, lastPromptTokens, lastMsgCount int) int {
-	if lastPromptTokens <= 0 || lastMsgCount <= 0 {
-		return EstimateTokens(messages)
-	}
-
-	currentCount := len(messages)
-	newMsgs := currentCount - lastMsgCount
-	if newMsgs <= 0 {
-		// No new messages since last calibration (or history was truncated).
-		// Use calibration value as-is; it's the best estimate we have.
-		return lastPromptTokens
-	}
-
-	// Estimate only the new messages with the heuristic and add to base.
-	delta := 0
-	for _, m := range messages[lastMsgCount:] {
-		delta += estimateMessageTokens(m)
-	}
-	return lastPromptTokens + delta
+func EstimateTokensWithCalibration(tok tokenizer.Tokenizer, messages []providers.Message, lastPromptTokens, lastMsgCount int) int {
+	return tokenizer.CountCalibrated(tok, messages, lastPromptTokens, lastMsgCount)
 }
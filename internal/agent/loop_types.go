@@ -8,15 +8,18 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/nextlevelbuilder/goclaw/internal/bootstrap"
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
 	"github.com/nextlevelbuilder/goclaw/internal/localworker"
 	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
 	"github.com/nextlevelbuilder/goclaw/internal/media"
+	"github.com/nextlevelbuilder/goclaw/internal/pii"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/sandbox"
 	"github.com/nextlevelbuilder/goclaw/internal/skills"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tokenizer"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
 	"github.com/nextlevelbuilder/goclaw/internal/tracing"
 )
@@ -78,7 +81,8 @@ type Loop struct {
 	workerEndpointID string
 	workspaceKey     string
 	contextWindow    int
-	maxTokens        int // max output tokens per LLM call (0 = default 8192)
+	tokenizer        tokenizer.Tokenizer // counts prompt tokens for the agent's model
+	maxTokens        int                 // max output tokens per LLM call (0 = default 8192)
 	maxIterations    int
 	maxToolCalls     int
 	workspace        string
@@ -145,8 +149,10 @@ type Loop struct {
 
 	// Security: input scanning and message size limit
 	inputGuard      *InputGuard
-	injectionAction string // "log", "warn" (default), "block", "off"
-	maxMessageChars int    // 0 = use default (32000)
+	injectionAction string               // "log", "warn" (default), "block", "off"
+	pii             *pii.Redactor        // nil = no personal data redaction
+	injectionClf    *InjectionClassifier // nil = legacy web_fetch/web_search pattern scan only
+	maxMessageChars int                  // 0 = use default (32000)
 
 	// Global builtin tool settings (from builtin_tools table)
 	builtinToolSettings tools.BuiltinToolSettings
@@ -184,9 +190,9 @@ type Loop struct {
 	// Model pricing config for cost tracking (nil = no cost calculation)
 	modelPricing map[string]*config.ModelPricing
 
-	// Budget enforcement: monthly spending limit in cents (0 = unlimited)
-	budgetMonthlyCents int
-	tracingStore       store.TracingStore
+	// Budget enforcement (nil enforcer = unlimited)
+	budget    *budget.Enforcer
+	budgetCfg *store.BudgetSettings
 
 	// Memory store for extractive memory fallback (writes directly when LLM flush fails)
 	memStore store.MemoryStore
@@ -294,6 +300,12 @@ type LoopConfig struct {
 	InjectionAction string      // "log", "warn" (default), "block", "off"
 	MaxMessageChars int         // 0 = use default (32000)
 
+	// Privacy: personal data redaction for inbound messages and tool results (nil = off)
+	PII *pii.Redactor
+
+	// Security: second-stage prompt-injection classifier for untrusted tool results (nil = off)
+	InjectionClassifier *InjectionClassifier
+
 	// Global builtin tool settings (from builtin_tools table)
 	BuiltinToolSettings tools.BuiltinToolSettings
 
@@ -325,9 +337,9 @@ type LoopConfig struct {
 	// Model pricing for cost tracking (key = "provider/model" or "model")
 	ModelPricing map[string]*config.ModelPricing
 
-	// Budget enforcement
-	BudgetMonthlyCents int
-	TracingStore       store.TracingStore
+	// Budget enforcement: shared enforcer plus this agent's caps
+	Budget         *budget.Enforcer
+	BudgetSettings *store.BudgetSettings
 
 	// Memory store for extractive memory fallback (writes directly when LLM flush fails)
 	MemoryStore store.MemoryStore
@@ -387,6 +399,7 @@ func NewLoop(cfg LoopConfig) *Loop {
 		workerEndpointID:       cfg.WorkerEndpointID,
 		workspaceKey:           cfg.WorkspaceKey,
 		contextWindow:          cfg.ContextWindow,
+		tokenizer:              tokenizerFor(cfg.Provider, cfg.Model),
 		maxTokens:              cfg.MaxTokens,
 		maxIterations:          cfg.MaxIterations,
 		maxToolCalls:           cfg.MaxToolCalls,
@@ -423,6 +436,8 @@ func NewLoop(cfg LoopConfig) *Loop {
 		traceCollector:         cfg.TraceCollector,
 		inputGuard:             guard,
 		injectionAction:        action,
+		pii:                    cfg.PII,
+		injectionClf:           cfg.InjectionClassifier,
 		maxMessageChars:        cfg.MaxMessageChars,
 		builtinToolSettings:    cfg.BuiltinToolSettings,
 		disabledTools:          cfg.DisabledTools,
@@ -436,8 +451,8 @@ func NewLoop(cfg LoopConfig) *Loop {
 		secureCLIStore:         cfg.SecureCLIStore,
 		mediaStore:             cfg.MediaStore,
 		modelPricing:           cfg.ModelPricing,
-		budgetMonthlyCents:     cfg.BudgetMonthlyCents,
-		tracingStore:           cfg.TracingStore,
+		budget:                 cfg.Budget,
+		budgetCfg:              cfg.BudgetSettings,
 		memStore:               cfg.MemoryStore,
 		mcpStore:               cfg.MCPStore,
 		mcpPool:                cfg.MCPPool,
@@ -477,6 +492,10 @@ type RunRequest struct {
 	ProviderOverride  providers.Provider // per-request provider override (heartbeat uses different provider)
 	LightContext      bool               // skip loading context files (only inject ExtraSystemPrompt)
 
+	// ResponseFormat constrains the final answer to JSON (e.g. response_format
+	// from /v1/chat/completions). Intermediate tool-calling turns are unaffected.
+	ResponseFormat *providers.ResponseFormat
+
 	// Run classification
 	RunKind       string // "delegation", "announce" — empty for user-initiated runs
 	HideInput     bool   // don't persist input message in session history (announce runs)
@@ -573,4 +592,8 @@ type runState struct {
 	// Truncation retry counter — caps consecutive truncation/parse-error retries
 	// to prevent burning through all iterations when max_tokens is too low.
 	truncationRetries int
+
+	// Set once the model has been asked to repair output that failed the
+	// request's ResponseFormat (one repair per run).
+	formatRepaired bool
 }
@@ -13,14 +13,16 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/bootstrap"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
 )
 
 
 // scanWebToolResult checks web_fetch/web_search tool results for prompt injection patterns.
 // If detected, prepends a warning (doesn't block — may be false positive).
+// Skipped when the agent's injection classifier is enabled (classifyToolResult covers it).
 func (l *Loop) scanWebToolResult(toolName string, result *tools.Result) {
-	if (toolName != "web_fetch" && toolName != "web_search") || l.inputGuard == nil {
+	if (toolName != "web_fetch" && toolName != "web_search") || l.inputGuard == nil || l.injectionClf != nil {
 		return
 	}
 	if injMatches := l.inputGuard.Scan(result.ForLLM); len(injMatches) > 0 {
@@ -33,6 +35,46 @@ func (l *Loop) scanWebToolResult(toolName string, result *tools.Result) {
 	}
 }
 
+// classifyToolResult runs the agent's injection classifier over an untrusted
+// tool result and applies the configured action: log keeps the content, warn
+// fences it in untrusted-data delimiters, block replaces it with a quarantine
+// notice. Returns nil when the tool is not covered. Runs before the tool span
+// ends so the verdict is recorded on it; goroutine-safe.
+func (l *Loop) classifyToolResult(ctx context.Context, toolName string, result *tools.Result) *InjectionVerdict {
+	if !l.injectionClf.Applies(toolName) || result == nil || result.IsError || strings.TrimSpace(result.ForLLM) == "" {
+		return nil
+	}
+	content := result.ForLLM
+	if l.pii.ToolResults() {
+		// Don't leak personal data to the classifier model when tool results are redacted.
+		content = l.pii.RedactPreview(content)
+	}
+	v := l.injectionClf.Classify(ctx, content)
+	if !v.Suspicious {
+		slog.Debug("security.injection_classified", "agent", l.id, "tool", toolName, "score", v.Score, "classifier", v.Classifier)
+		return &v
+	}
+
+	logFn := slog.Warn
+	if v.Action == store.InjectionActionLog {
+		logFn = slog.Info
+	}
+	logFn("security.injection_in_tool_result",
+		"agent", l.id, "tool", toolName, "score", v.Score, "classifier", v.Classifier,
+		"signals", strings.Join(v.Signals, ","), "reason", v.Reason, "action", v.Action)
+
+	switch v.Action {
+	case store.InjectionActionWarn:
+		result.ForLLM = wrapUntrusted(toolName, result.ForLLM, v)
+	case store.InjectionActionBlock:
+		result.ForLLM = quarantineNotice(toolName, v)
+		result.ForUser = ""
+		result.Media = nil
+		result.Deliverable = ""
+	}
+	return &v
+}
+
 // shouldShareWorkspace checks if the given user should share the base workspace
 // directory (skip per-user subfolder isolation) based on workspace_sharing config.
 func (l *Loop) shouldShareWorkspace(userID, peerKind string) bool {
@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tokenizer"
+)
+
+// PromptTokenCounter is implemented by agents that can count the prompt a run
+// would send without sending it.
+type PromptTokenCounter interface {
+	CountPromptTokens(ctx context.Context, req RunRequest) (*PromptTokenCount, error)
+}
+
+// PromptTokenCount is the token breakdown of the first LLM request of a run.
+type PromptTokenCount struct {
+	tokenizer.PromptCount
+	Model         string `json:"model"`
+	Provider      string `json:"provider"`
+	ContextWindow int    `json:"contextWindow"`
+	MaxTokens     int    `json:"maxTokens"` // output tokens reserved per call
+}
+
+// tokenizerFor selects the tokenizer matching an agent's provider and model.
+func tokenizerFor(p providers.Provider, model string) tokenizer.Tokenizer {
+	name := ""
+	if p != nil {
+		name = p.Name()
+	}
+	return tokenizer.ForModel(name, model)
+}
+
+// CountPromptTokens builds the messages and tool definitions that
+// the first iteration of a run for req would send (system prompt, context
+// files, summary, history pipeline, new message) and counts their tokens.
+// Nothing is persisted and no LLM call is made. Attached media and team task
+// reminders added later in a run are not included.
+func (l *Loop) CountPromptTokens(ctx context.Context, req RunRequest) (*PromptTokenCount, error) {
+	if l.executionMode == store.AgentExecutionModeLocalWorker {
+		return nil, errors.New("prompt token counting is not available for local worker agents")
+	}
+	ctxSetup, err := l.injectContext(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	ctx = ctxSetup.ctx
+
+	history := l.sessions.GetHistory(ctx, req.SessionKey)
+	summary := l.sessions.GetSummary(ctx, req.SessionKey)
+	messages, hadBootstrap := l.buildMessages(ctx, history, summary, req.Message, req.ExtraSystemPrompt, req.SessionKey, req.Channel, req.ChannelType, req.ChatTitle, req.PeerKind, req.UserID, req.HistoryLimit, req.SkillFilter, req.LightContext)
+
+	maxIter := l.maxIterations
+	if req.MaxIterations > 0 && req.MaxIterations < maxIter {
+		maxIter = req.MaxIterations
+	}
+	if req.UserID != "" {
+		l.getUserMCPTools(ctx, req.UserID)
+	}
+	toolDefs, _, messages := l.buildFilteredTools(&req, hadBootstrap, 1, maxIter, messages)
+
+	return &PromptTokenCount{
+		PromptCount:   tokenizer.CountPrompt(l.tokenizer, messages, toolDefs),
+		Model:         l.model,
+		Provider:      l.ProviderName(),
+		ContextWindow: l.contextWindow,
+		MaxTokens:     l.effectiveMaxTokens(),
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/nextlevelbuilder/goclaw/internal/config"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/tokenizer"
 )
 
 // Context pruning defaults matching TS DEFAULT_CONTEXT_PRUNING_SETTINGS.
@@ -18,7 +19,6 @@ const (
 	defaultSoftTrimHeadChars    = 1500
 	defaultSoftTrimTailChars    = 1500
 	defaultHardClearPlaceholder = "[Old tool result content cleared]"
-	charsPerTokenEstimate       = 4
 )
 
 // effectivePruningSettings holds resolved pruning settings with defaults applied.
@@ -97,8 +97,10 @@ func resolvePruningSettings(cfg *config.ContextPruningConfig) *effectivePruningS
 //  2. Hard clear: replace entire tool result with placeholder.
 //
 // Only tool results older than keepLastAssistants are eligible for pruning.
+// Context ratios are measured in tokens with tok (nil uses the default
+// tokenizer); trim sizes stay in characters as configured.
 // Returns a new slice if any changes were made, otherwise the original.
-func pruneContextMessages(msgs []providers.Message, contextWindowTokens int, cfg *config.ContextPruningConfig) []providers.Message {
+func pruneContextMessages(msgs []providers.Message, contextWindowTokens int, cfg *config.ContextPruningConfig, tok tokenizer.Tokenizer) []providers.Message {
 	// Pruning runs by default for all providers. Only skip when explicitly disabled.
 	if cfg != nil && cfg.Mode == "off" {
 		return msgs
@@ -108,7 +110,7 @@ func pruneContextMessages(msgs []providers.Message, contextWindowTokens int, cfg
 	}
 
 	settings := resolvePruningSettings(cfg)
-	charWindow := contextWindowTokens * charsPerTokenEstimate
+	window := float64(contextWindowTokens)
 
 	// Find cutoff: protect last N assistant messages.
 	cutoffIndex := findAssistantCutoff(msgs, settings.keepLastAssistants)
@@ -125,13 +127,10 @@ func pruneContextMessages(msgs []providers.Message, contextWindowTokens int, cfg
 		}
 	}
 
-	// Estimate total chars.
-	totalChars := 0
-	for _, m := range msgs {
-		totalChars += estimateMessageChars(m)
-	}
+	// Count total tokens.
+	totalTokens := tokenizer.CountMessages(tok, msgs)
 
-	ratio := float64(totalChars) / float64(charWindow)
+	ratio := float64(totalTokens) / window
 	if ratio < settings.softTrimRatio {
 		return msgs // context is small enough
 	}
@@ -151,33 +150,36 @@ func pruneContextMessages(msgs []providers.Message, contextWindowTokens int, cfg
 	// Pass 0: Per-result context guard — force-trim any single tool result
 	// exceeding 30% of the context window. Catches outlier outputs even
 	// when overall context ratio is low.
-	maxSingleResultChars := charWindow * 3 / 10
+	maxSingleResultTokens := contextWindowTokens * 3 / 10
 	var result []providers.Message
 	for _, idx := range prunableIndexes {
-		msgChars := estimateMessageChars(msgs[idx])
-		if msgChars > maxSingleResultChars {
+		msgTokens := tokenizer.CountMessage(tok, msgs[idx])
+		if msgTokens > maxSingleResultTokens {
 			if result == nil {
 				result = make([]providers.Message, len(msgs))
 				copy(result, msgs)
 			}
 			msg := msgs[idx]
-			head := takeHead(msg.Content, maxSingleResultChars*7/10)
-			tail := takeTail(msg.Content, maxSingleResultChars*3/10)
-			trimmed := fmt.Sprintf("%s\n\n⚠️ [... middle content omitted ...]\n\n%s\n\n[Single tool result trimmed: %d chars exceeded per-result limit of %d chars.]",
-				head, tail, msgChars, maxSingleResultChars)
+			// Convert the token limit to chars at this message's own density.
+			msgChars := estimateMessageChars(msg)
+			keepChars := int(int64(msgChars) * int64(maxSingleResultTokens) / int64(msgTokens))
+			head := takeHead(msg.Content, keepChars*7/10)
+			tail := takeTail(msg.Content, keepChars*3/10)
+			trimmed := fmt.Sprintf("%s\n\n⚠️ [... middle content omitted ...]\n\n%s\n\n[Single tool result trimmed: %d tokens exceeded per-result limit of %d tokens.]",
+				head, tail, msgTokens, maxSingleResultTokens)
 			result[idx] = providers.Message{
 				Role:       msg.Role,
 				Content:    trimmed,
 				ToolCallID: msg.ToolCallID,
 			}
-			totalChars += len(trimmed) - msgChars
+			totalTokens += tokenizer.CountMessage(tok, result[idx]) - msgTokens
 		}
 	}
 	if result != nil {
 		msgs = result
 		result = nil
 		// Re-check ratio after per-result guard.
-		ratio = float64(totalChars) / float64(charWindow)
+		ratio = float64(totalTokens) / window
 		if ratio < settings.softTrimRatio {
 			return msgs
 		}
@@ -218,7 +220,7 @@ func pruneContextMessages(msgs []providers.Message, contextWindowTokens int, cfg
 			Content:    trimmed,
 			ToolCallID: msg.ToolCallID,
 		}
-		totalChars += len(trimmed) - msgChars
+		totalTokens += tokenizer.CountMessage(tok, result[idx]) - tokenizer.CountMessage(tok, msg)
 	}
 
 	output := msgs
@@ -227,7 +229,7 @@ func pruneContextMessages(msgs []providers.Message, contextWindowTokens int, cfg
 	}
 
 	// Re-check ratio after soft trim.
-	ratio = float64(totalChars) / float64(charWindow)
+	ratio = float64(totalTokens) / window
 	if ratio < settings.hardClearRatio || !settings.hardClearEnabled {
 		return output
 	}
@@ -253,16 +255,15 @@ func pruneContextMessages(msgs []providers.Message, contextWindowTokens int, cfg
 			break
 		}
 		msg := output[idx]
-		beforeChars := estimateMessageChars(msg)
+		beforeTokens := tokenizer.CountMessage(tok, msg)
 
 		output[idx] = providers.Message{
 			Role:       msg.Role,
 			Content:    settings.hardClearPlaceholder,
 			ToolCallID: msg.ToolCallID,
 		}
-		afterChars := len(settings.hardClearPlaceholder)
-		totalChars += afterChars - beforeChars
-		ratio = float64(totalChars) / float64(charWindow)
+		totalTokens += tokenizer.CountMessage(tok, output[idx]) - beforeTokens
+		ratio = float64(totalTokens) / window
 	}
 
 	return output
@@ -10,11 +10,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nextlevelbuilder/goclaw/internal/bootstrap"
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
 	"github.com/nextlevelbuilder/goclaw/internal/localworker"
 	mcpbridge "github.com/nextlevelbuilder/goclaw/internal/mcp"
 	"github.com/nextlevelbuilder/goclaw/internal/media"
+	"github.com/nextlevelbuilder/goclaw/internal/pii"
 	"github.com/nextlevelbuilder/goclaw/internal/providerresolve"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/sandbox"
@@ -48,6 +50,7 @@ type ResolverDeps struct {
 	// Security
 	InjectionAction string // "log", "warn", "block", "off"
 	MaxMessageChars int
+	PII             *pii.Redactor // nil = no personal data redaction
 
 	// Global defaults (from config.json) — per-agent DB overrides take priority
 	CompactionCfg          *config.CompactionConfig
@@ -87,8 +90,8 @@ type ResolverDeps struct {
 	// Model pricing for cost tracking
 	ModelPricing map[string]*config.ModelPricing
 
-	// Tracing store for budget enforcement queries
-	TracingStore store.TracingStore
+	// Budget enforcement (tenant/agent/user USD caps); nil = unlimited
+	Budget *budget.Enforcer
 
 	// Memory store for extractive memory fallback
 	MemoryStore store.MemoryStore
@@ -362,6 +365,25 @@ func NewManagedResolver(deps ResolverDeps) ResolverFunc {
 			dataDir = config.TenantDataDir(deps.DataDir, ag.TenantID, tenantSlug)
 		}
 
+		// Second-stage injection classifier for untrusted tool results. llm mode
+		// uses the configured provider/model, defaulting to the agent's own.
+		var injectionClf *InjectionClassifier
+		if ic := ag.ParseInjectionClassifier(); ic != nil {
+			clfProvider, clfModel := provider, ag.Model
+			if ic.Mode == store.InjectionClassifierLLM && ic.Provider != "" {
+				if p, err := deps.ProviderReg.GetForTenant(ag.TenantID, ic.Provider); err == nil {
+					clfProvider = p
+				} else {
+					slog.Warn("injection classifier provider not found, using agent provider",
+						"agent", agentKey, "wanted", ic.Provider, "using", provider.Name())
+				}
+			}
+			if ic.Model != "" {
+				clfModel = ic.Model
+			}
+			injectionClf = NewInjectionClassifier(ic, clfProvider, clfModel)
+		}
+
 		restrictVal := true // always restrict agents to their workspace
 		loop := NewLoop(LoopConfig{
 			ID:                     ag.AgentKey,
@@ -402,6 +424,8 @@ func NewManagedResolver(deps ResolverDeps) ResolverFunc {
 			OnEvent:                deps.OnEvent,
 			TraceCollector:         deps.TraceCollector,
 			InjectionAction:        deps.InjectionAction,
+			PII:                    deps.PII,
+			InjectionClassifier:    injectionClf,
 			MaxMessageChars:        deps.MaxMessageChars,
 			CompactionCfg:          compactionCfg,
 			ContextPruningCfg:      contextPruningCfg,
@@ -421,8 +445,8 @@ func NewManagedResolver(deps ResolverDeps) ResolverFunc {
 			SecureCLIStore:         deps.SecureCLIStore,
 			MediaStore:             deps.MediaStore,
 			ModelPricing:           deps.ModelPricing,
-			BudgetMonthlyCents:     derefInt(ag.BudgetMonthlyCents),
-			TracingStore:           deps.TracingStore,
+			Budget:                 deps.Budget,
+			BudgetSettings:         ag.ParseBudget(),
 			MemoryStore:            deps.MemoryStore,
 			MCPStore:               deps.MCPStore,
 			MCPPool:                deps.MCPPool,
@@ -473,10 +497,3 @@ func resolveTenantSlug(ts store.TenantStore, tenantID uuid.UUID) string {
 	}
 	return tenant.Slug
 }
-
-func derefInt(p *int) int {
-	if p == nil {
-		return 0
-	}
-	return *p
-}
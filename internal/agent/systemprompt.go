@@ -96,6 +96,7 @@ var coreToolSummaries = map[string]string{
 	"read_file":     "Read file contents",
 	"write_file":    "Create or overwrite files",
 	"list_files":    "List directory contents",
+	"search_files":  "Search file contents (regex/literal) and find files by glob",
 	"exec":          "Run shell commands",
 	"memory_search": "Search indexed memory files (MEMORY.md + memory/*.md)",
 	"memory_get":    "Read specific sections of memory files",
@@ -113,6 +114,7 @@ var coreToolSummaries = map[string]string{
 	"browser":          "Browse web pages interactively",
 	"tts":              "Convert text to speech audio",
 	"edit":             "Edit a file by replacing exact text matches",
+	"apply_patch":      "Apply a multi-file diff atomically (dry_run to check first)",
 	"message":          "Send a PROACTIVE message to another channel/chat — do NOT use this to reply to the user, just respond directly",
 	"sessions_list":    "List sessions for this agent",
 	"session_status":   "Show session status (model, tokens, compaction count)",
@@ -126,6 +128,7 @@ var coreToolSummaries = map[string]string{
 	"create_image":            "Generate images from text descriptions using AI",
 	"create_audio":            "Generate music or sound effects from text descriptions using AI",
 	"knowledge_graph_search":  "Find people, projects, and their connections — use for relationship questions (who works with whom, project dependencies) that memory_search may miss",
+	"kb_search":               "Search attached knowledge bases (uploaded manuals, policies) — cite passages as [file, page N]",
 	"team_tasks":              "Team task board — track progress, manage dependencies (spawn auto-creates delegation tasks)",
 	"list_group_members":      "List all members of the current group chat (Feishu/Lark only)",
 	"create_forum_topic":      "Create a forum topic in a Telegram supergroup",
@@ -37,7 +37,7 @@ const (
 // increments the read-only streak.
 // team_tasks is excluded: action-level classification in recordMutation.
 var mutatingTools = map[string]bool{
-	"write_file": true, "edit": true, "edit_file": true,
+	"write_file": true, "edit": true, "edit_file": true, "apply_patch": true,
 	"spawn": true, "message": true,
 	"create_image": true, "create_video": true, "create_audio": true,
 	"tts": true, "cron": true, "publish_skill": true,
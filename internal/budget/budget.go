@@ -0,0 +1,404 @@
+// Package budget enforces hierarchical USD spend limits (tenant → agent →
+// channel user) over daily and monthly windows. Spend is read from LLM span
+// costs in the tracing store and topped up with the cost of calls made on
+// this node since the last read, so a run is re-checked before every LLM
+// iteration without querying the database each time.
+package budget
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// Budget levels, from broadest to narrowest.
+const (
+	LevelTenant = "tenant"
+	LevelAgent  = "agent"
+	LevelUser   = "user"
+)
+
+// Budget windows (UTC calendar day and month).
+const (
+	WindowDay   = "day"
+	WindowMonth = "month"
+)
+
+const (
+	// DefaultSoftLimitPercent is the share of a cap at which warnings fire.
+	DefaultSoftLimitPercent = 80
+
+	defaultRefreshInterval = 30 * time.Second
+	tenantSettingsTTL      = time.Minute
+)
+
+// SpendReader reads summed LLM spend. store.TracingStore satisfies it.
+type SpendReader interface {
+	GetSpend(ctx context.Context, q store.SpendQuery) (float64, error)
+}
+
+// TenantReader loads tenant settings. store.TenantStore satisfies it.
+type TenantReader interface {
+	GetTenant(ctx context.Context, id uuid.UUID) (*store.TenantData, error)
+}
+
+// Scope identifies who a run spends for.
+type Scope struct {
+	TenantID uuid.UUID
+	AgentID  uuid.UUID
+	AgentKey string
+	UserID   string                // channel user; "" = no per-user caps
+	Agent    *store.BudgetSettings // agent caps; nil = none
+}
+
+// Usage is consumption against one cap.
+type Usage struct {
+	Level        string  `json:"level"`
+	Window       string  `json:"window"`
+	Subject      string  `json:"subject"`
+	SpentUSD     float64 `json:"spent_usd"`
+	LimitUSD     float64 `json:"limit_usd"`
+	RemainingUSD float64 `json:"remaining_usd"`
+	Percent      float64 `json:"percent"`
+	ResetsAt     string  `json:"resets_at"`
+}
+
+// Warning is emitted once per cap and window when spend crosses the soft limit.
+type Warning struct {
+	TenantID      uuid.UUID
+	AgentKey      string
+	UserID        string
+	Usage         Usage
+	NotifyChannel string
+	NotifyChatID  string
+}
+
+// ExceededError is returned by Check when a cap is reached.
+type ExceededError struct {
+	Usage Usage
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s %s budget exceeded ($%.2f / $%.2f)", e.Usage.Level, dailyOrMonthly(e.Usage.Window), e.Usage.SpentUSD, e.Usage.LimitUSD)
+}
+
+func dailyOrMonthly(window string) string {
+	if window == WindowDay {
+		return "daily"
+	}
+	return "monthly"
+}
+
+// limit is one resolved cap for a scope.
+type limit struct {
+	level    string
+	window   string
+	subject  string
+	limitUSD float64
+	softPct  int
+	notify   *store.BudgetSettings
+	query    store.SpendQuery
+}
+
+// key identifies the spend counter for this cap in the current period.
+func (l limit) key() string {
+	return l.level + "|" + l.subject + "|" + l.window + "|" + l.query.From.Format(time.RFC3339)
+}
+
+type spendEntry struct {
+	base      float64 // spend read from the store
+	local     float64 // recorded on this node since the read
+	fetchedAt time.Time
+	periodEnd time.Time
+}
+
+type tenantEntry struct {
+	settings  *store.BudgetSettings
+	fetchedAt time.Time
+}
+
+// Enforcer checks and tracks spend against configured caps. Nil-safe: a nil
+// Enforcer allows everything.
+type Enforcer struct {
+	spend   SpendReader
+	tenants TenantReader
+	refresh time.Duration
+	now     func() time.Time
+
+	mu     sync.Mutex
+	spent  map[string]*spendEntry
+	tenant map[uuid.UUID]tenantEntry
+	warned map[string]time.Time // cap key → end of the period it fired in
+	onWarn func(Warning)
+}
+
+// NewEnforcer creates an enforcer. tenants may be nil to skip tenant caps.
+func NewEnforcer(spend SpendReader, tenants TenantReader) *Enforcer {
+	return &Enforcer{
+		spend:   spend,
+		tenants: tenants,
+		refresh: defaultRefreshInterval,
+		now:     time.Now,
+		spent:   make(map[string]*spendEntry),
+		tenant:  make(map[uuid.UUID]tenantEntry),
+		warned:  make(map[string]time.Time),
+	}
+}
+
+// SetWarningHandler sets the callback for soft-limit warnings. It is called
+// outside the enforcer's lock and must not block for long.
+func (e *Enforcer) SetWarningHandler(fn func(Warning)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onWarn = fn
+}
+
+// Check returns an *ExceededError if any cap for scope is reached, and fires
+// soft-limit warnings for caps that crossed their threshold. Store errors
+// fail open.
+func (e *Enforcer) Check(ctx context.Context, scope Scope) error {
+	if e == nil {
+		return nil
+	}
+	var warnings []Warning
+	var exceeded error
+	for _, lim := range e.limits(ctx, scope) {
+		usage := e.usage(ctx, lim)
+		if usage.SpentUSD >= lim.limitUSD {
+			exceeded = &ExceededError{Usage: usage}
+			break
+		}
+		if lim.softPct > 0 && usage.Percent >= float64(lim.softPct) && e.markWarned(lim) {
+			w := Warning{TenantID: scope.TenantID, AgentKey: scope.AgentKey, UserID: scope.UserID, Usage: usage}
+			if lim.notify != nil {
+				w.NotifyChannel, w.NotifyChatID = lim.notify.NotifyChannel, lim.notify.NotifyChatID
+			}
+			warnings = append(warnings, w)
+		}
+	}
+
+	e.mu.Lock()
+	onWarn := e.onWarn
+	e.mu.Unlock()
+	if onWarn != nil {
+		for _, w := range warnings {
+			onWarn(w)
+		}
+	}
+	return exceeded
+}
+
+// Record adds the cost of an LLM call to every counter for scope so the next
+// Check sees it before the span reaches the store.
+func (e *Enforcer) Record(ctx context.Context, scope Scope, costUSD float64) {
+	if e == nil || costUSD <= 0 {
+		return
+	}
+	limits := e.limits(ctx, scope)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, lim := range limits {
+		if entry, ok := e.spent[lim.key()]; ok {
+			entry.local += costUSD
+		}
+	}
+}
+
+// Status reports consumption against every cap that applies to scope.
+func (e *Enforcer) Status(ctx context.Context, scope Scope) []Usage {
+	if e == nil {
+		return nil
+	}
+	limits := e.limits(ctx, scope)
+	out := make([]Usage, 0, len(limits))
+	for _, lim := range limits {
+		out = append(out, e.usage(ctx, lim))
+	}
+	return out
+}
+
+// markWarned records that lim's warning fired this period. Returns false if
+// it already had.
+func (e *Enforcer) markWarned(lim limit) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	k := lim.key()
+	if _, ok := e.warned[k]; ok {
+		return false
+	}
+	e.warned[k] = lim.query.To
+	return true
+}
+
+func (e *Enforcer) usage(ctx context.Context, lim limit) Usage {
+	spent := e.spentFor(ctx, lim)
+	u := Usage{
+		Level:    lim.level,
+		Window:   lim.window,
+		Subject:  lim.subject,
+		SpentUSD: spent,
+		LimitUSD: lim.limitUSD,
+		ResetsAt: lim.query.To.Format(time.RFC3339),
+	}
+	if remaining := lim.limitUSD - spent; remaining > 0 {
+		u.RemainingUSD = remaining
+	}
+	if lim.limitUSD > 0 {
+		u.Percent = spent / lim.limitUSD * 100
+	}
+	return u
+}
+
+// spentFor returns cached spend for lim, re-reading the store when stale.
+// Spend only grows within a period, so a fresh read never lowers the total:
+// costs recorded locally but not yet flushed to the store are kept.
+func (e *Enforcer) spentFor(ctx context.Context, lim limit) float64 {
+	k := lim.key()
+	e.mu.Lock()
+	var cached float64
+	entry, ok := e.spent[k]
+	if ok {
+		cached = entry.base + entry.local
+		if e.now().Sub(entry.fetchedAt) < e.refresh {
+			e.mu.Unlock()
+			return cached
+		}
+	}
+	e.mu.Unlock()
+
+	base, err := e.spend.GetSpend(ctx, lim.query)
+	if err != nil {
+		slog.Warn("budget: spend query failed", "level", lim.level, "subject", lim.subject, "error", err)
+		return cached
+	}
+	base = max(base, cached)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spent[k] = &spendEntry{base: base, fetchedAt: e.now(), periodEnd: lim.query.To}
+	e.pruneLocked()
+	return base
+}
+
+// pruneLocked drops counters and warning marks from past periods.
+func (e *Enforcer) pruneLocked() {
+	now := e.now()
+	for k, entry := range e.spent {
+		if !now.Before(entry.periodEnd) {
+			delete(e.spent, k)
+		}
+	}
+	for k, end := range e.warned {
+		if !now.Before(end) {
+			delete(e.warned, k)
+		}
+	}
+}
+
+// tenantSettings returns the tenant's budget, cached for tenantSettingsTTL.
+func (e *Enforcer) tenantSettings(ctx context.Context, tenantID uuid.UUID) *store.BudgetSettings {
+	if e.tenants == nil || tenantID == uuid.Nil {
+		return nil
+	}
+	e.mu.Lock()
+	cached, ok := e.tenant[tenantID]
+	e.mu.Unlock()
+	if ok && e.now().Sub(cached.fetchedAt) < tenantSettingsTTL {
+		return cached.settings
+	}
+
+	t, err := e.tenants.GetTenant(ctx, tenantID)
+	var settings *store.BudgetSettings
+	if err == nil && t != nil {
+		settings = store.ParseBudgetSettings(t.Settings)
+	}
+	e.mu.Lock()
+	e.tenant[tenantID] = tenantEntry{settings: settings, fetchedAt: e.now()}
+	e.mu.Unlock()
+	return settings
+}
+
+// limits resolves every cap that applies to scope, broadest first.
+// Per-user caps come from the agent when it sets them (scoped to that agent),
+// otherwise from the tenant (scoped to the whole tenant).
+func (e *Enforcer) limits(ctx context.Context, scope Scope) []limit {
+	tenantCfg := e.tenantSettings(ctx, scope.TenantID)
+	agentCfg := scope.Agent
+	if tenantCfg == nil && agentCfg == nil {
+		return nil
+	}
+
+	softPct := DefaultSoftLimitPercent
+	for _, cfg := range []*store.BudgetSettings{tenantCfg, agentCfg} {
+		if cfg != nil && cfg.SoftLimitPercent != 0 {
+			softPct = max(cfg.SoftLimitPercent, 0)
+		}
+	}
+
+	now := e.now().UTC()
+	dayFrom, dayTo := dayBounds(now)
+	monthFrom, monthTo := monthBounds(now)
+	var out []limit
+	add := func(level, subject string, notify *store.BudgetSettings, q store.SpendQuery, daily, monthly float64) {
+		q.TenantID = scope.TenantID
+		if daily > 0 {
+			dq := q
+			dq.From, dq.To = dayFrom, dayTo
+			out = append(out, limit{level: level, window: WindowDay, subject: subject, limitUSD: daily, softPct: softPct, notify: notify, query: dq})
+		}
+		if monthly > 0 {
+			mq := q
+			mq.From, mq.To = monthFrom, monthTo
+			out = append(out, limit{level: level, window: WindowMonth, subject: subject, limitUSD: monthly, softPct: softPct, notify: notify, query: mq})
+		}
+	}
+
+	if tenantCfg != nil {
+		add(LevelTenant, scope.TenantID.String(), tenantCfg, store.SpendQuery{}, tenantCfg.DailyUSD, tenantCfg.MonthlyUSD)
+	}
+	agentNotify := notifyTarget(agentCfg, tenantCfg)
+	if agentCfg != nil && scope.AgentID != uuid.Nil {
+		agentID := scope.AgentID
+		add(LevelAgent, scope.AgentKey, agentNotify, store.SpendQuery{AgentID: &agentID}, agentCfg.DailyUSD, agentCfg.MonthlyUSD)
+	}
+	if scope.UserID != "" {
+		switch {
+		case agentCfg != nil && scope.AgentID != uuid.Nil && (agentCfg.UserDailyUSD > 0 || agentCfg.UserMonthlyUSD > 0):
+			agentID := scope.AgentID
+			add(LevelUser, scope.AgentKey+"/"+scope.UserID, agentNotify,
+				store.SpendQuery{AgentID: &agentID, UserID: scope.UserID}, agentCfg.UserDailyUSD, agentCfg.UserMonthlyUSD)
+		case tenantCfg != nil:
+			add(LevelUser, scope.UserID, tenantCfg,
+				store.SpendQuery{UserID: scope.UserID}, tenantCfg.UserDailyUSD, tenantCfg.UserMonthlyUSD)
+		}
+	}
+	return out
+}
+
+// notifyTarget returns the first settings with an owner chat configured.
+func notifyTarget(candidates ...*store.BudgetSettings) *store.BudgetSettings {
+	for _, c := range candidates {
+		if c != nil && c.NotifyChannel != "" && c.NotifyChatID != "" {
+			return c
+		}
+	}
+	return nil
+}
+
+func dayBounds(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 1)
+}
+
+func monthBounds(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
@@ -0,0 +1,183 @@
+package budget
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+type fakeSpend struct {
+	mu      sync.Mutex
+	spend   map[string]float64 // keyed by level: "tenant", "agent", "user"
+	queries int
+}
+
+func (f *fakeSpend) GetSpend(_ context.Context, q store.SpendQuery) (float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queries++
+	switch {
+	case q.UserID != "":
+		return f.spend[LevelUser], nil
+	case q.AgentID != nil:
+		return f.spend[LevelAgent], nil
+	default:
+		return f.spend[LevelTenant], nil
+	}
+}
+
+type fakeTenants struct {
+	settings json.RawMessage
+}
+
+func (f *fakeTenants) GetTenant(_ context.Context, id uuid.UUID) (*store.TenantData, error) {
+	return &store.TenantData{ID: id, Settings: f.settings}, nil
+}
+
+func TestCheckStopsAtAgentCap(t *testing.T) {
+	spend := &fakeSpend{spend: map[string]float64{LevelAgent: 9.5}}
+	e := NewEnforcer(spend, nil)
+	scope := Scope{TenantID: uuid.New(), AgentID: uuid.New(), AgentKey: "ops", Agent: &store.BudgetSettings{MonthlyUSD: 10}}
+
+	if err := e.Check(context.Background(), scope); err != nil {
+		t.Fatalf("Check() under cap = %v", err)
+	}
+
+	// Spend recorded mid-run counts without another store read.
+	e.Record(context.Background(), scope, 0.6)
+	err := e.Check(context.Background(), scope)
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) {
+		t.Fatalf("Check() = %v, want *ExceededError", err)
+	}
+	if exceeded.Usage.Level != LevelAgent || exceeded.Usage.Window != WindowMonth {
+		t.Fatalf("exceeded usage = %+v", exceeded.Usage)
+	}
+	if spend.queries != 1 {
+		t.Fatalf("spend queries = %d, want 1 (cached)", spend.queries)
+	}
+}
+
+func TestRefreshKeepsUnflushedLocalSpend(t *testing.T) {
+	spend := &fakeSpend{spend: map[string]float64{LevelAgent: 5}}
+	e := NewEnforcer(spend, nil)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	e.now = func() time.Time { return now }
+	scope := Scope{TenantID: uuid.New(), AgentID: uuid.New(), Agent: &store.BudgetSettings{DailyUSD: 10}}
+
+	_ = e.Check(context.Background(), scope)
+	e.Record(context.Background(), scope, 3)
+
+	// The store has not seen the recorded spend yet; a refresh must not drop it.
+	now = now.Add(time.Minute)
+	status := e.Status(context.Background(), scope)
+	if len(status) != 1 || status[0].SpentUSD != 8 {
+		t.Fatalf("status = %+v, want spent 8", status)
+	}
+	if status[0].RemainingUSD != 2 {
+		t.Fatalf("remaining = %v, want 2", status[0].RemainingUSD)
+	}
+}
+
+func TestTenantUserCapAppliesAcrossAgents(t *testing.T) {
+	spend := &fakeSpend{spend: map[string]float64{LevelTenant: 1, LevelUser: 2}}
+	tenants := &fakeTenants{settings: json.RawMessage(`{"budget": {"monthly_usd": 100, "user_daily_usd": 2}}`)}
+	e := NewEnforcer(spend, tenants)
+	scope := Scope{TenantID: uuid.New(), AgentID: uuid.New(), AgentKey: "support", UserID: "tg:42"}
+
+	err := e.Check(context.Background(), scope)
+	var exceeded *ExceededError
+	if !errors.As(err, &exceeded) || exceeded.Usage.Level != LevelUser || exceeded.Usage.Window != WindowDay {
+		t.Fatalf("Check() = %v, want user daily cap exceeded", err)
+	}
+
+	// Other users are unaffected.
+	spend.spend[LevelUser] = 0
+	scope.UserID = "tg:43"
+	if err := e.Check(context.Background(), scope); err != nil {
+		t.Fatalf("Check() other user = %v", err)
+	}
+}
+
+func TestSoftLimitWarnsOncePerPeriod(t *testing.T) {
+	spend := &fakeSpend{spend: map[string]float64{LevelAgent: 8.5}}
+	e := NewEnforcer(spend, nil)
+	var warnings []Warning
+	e.SetWarningHandler(func(w Warning) { warnings = append(warnings, w) })
+	scope := Scope{
+		TenantID: uuid.New(),
+		AgentID:  uuid.New(),
+		AgentKey: "ops",
+		Agent:    &store.BudgetSettings{DailyUSD: 10, NotifyChannel: "telegram", NotifyChatID: "100"},
+	}
+
+	for range 3 {
+		if err := e.Check(context.Background(), scope); err != nil {
+			t.Fatalf("Check() = %v", err)
+		}
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %d, want 1", len(warnings))
+	}
+	if warnings[0].NotifyChatID != "100" || warnings[0].Usage.Percent < 80 {
+		t.Fatalf("warning = %+v", warnings[0])
+	}
+}
+
+func TestNoCapsAllowsEverything(t *testing.T) {
+	spend := &fakeSpend{spend: map[string]float64{LevelAgent: 1e6}}
+	e := NewEnforcer(spend, &fakeTenants{})
+	if err := e.Check(context.Background(), Scope{TenantID: uuid.New(), AgentID: uuid.New()}); err != nil {
+		t.Fatalf("Check() = %v", err)
+	}
+	if spend.queries != 0 {
+		t.Fatalf("spend queries = %d, want 0", spend.queries)
+	}
+
+	var nilEnforcer *Enforcer
+	if err := nilEnforcer.Check(context.Background(), Scope{}); err != nil {
+		t.Fatalf("nil Check() = %v", err)
+	}
+}
+
+type recordingPublisher struct {
+	events   []bus.Event
+	outbound []bus.OutboundMessage
+}
+
+func (p *recordingPublisher) Broadcast(event bus.Event) { p.events = append(p.events, event) }
+func (p *recordingPublisher) TryPublishOutbound(msg bus.OutboundMessage) bool {
+	p.outbound = append(p.outbound, msg)
+	return true
+}
+
+func TestBusNotifierSendsEventAndOwnerMessage(t *testing.T) {
+	pub := &recordingPublisher{}
+	notify := BusNotifier(pub)
+	notify(Warning{
+		AgentKey:      "ops",
+		Usage:         Usage{Level: LevelAgent, Window: WindowMonth, SpentUSD: 85, LimitUSD: 100, Percent: 85},
+		NotifyChannel: "telegram",
+		NotifyChatID:  "100",
+	})
+	notify(Warning{Usage: Usage{Level: LevelTenant, Window: WindowDay, SpentUSD: 9, LimitUSD: 10, Percent: 90}})
+
+	if len(pub.events) != 2 {
+		t.Fatalf("events = %d, want 2", len(pub.events))
+	}
+	if len(pub.outbound) != 1 || pub.outbound[0].ChatID != "100" {
+		t.Fatalf("outbound = %+v, want one message to the owner chat", pub.outbound)
+	}
+	if !strings.Contains(pub.outbound[0].Content, "agent ops") || !strings.Contains(pub.outbound[0].Content, "monthly") {
+		t.Fatalf("message = %q", pub.outbound[0].Content)
+	}
+}
@@ -0,0 +1,59 @@
+package budget
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// WarningPayload is the payload of protocol.EventBudgetWarning.
+type WarningPayload struct {
+	AgentKey string `json:"agent_key,omitempty"`
+	UserID   string `json:"user_id,omitempty"`
+	Usage
+}
+
+// Publisher is the subset of *bus.MessageBus used to deliver warnings.
+type Publisher interface {
+	Broadcast(event bus.Event)
+	TryPublishOutbound(msg bus.OutboundMessage) bool
+}
+
+// BusNotifier returns a warning handler that broadcasts a budget.warning
+// event and, when an owner chat is configured, sends it a message.
+func BusNotifier(pub Publisher) func(Warning) {
+	return func(w Warning) {
+		pub.Broadcast(bus.Event{
+			Name:     protocol.EventBudgetWarning,
+			Payload:  WarningPayload{AgentKey: w.AgentKey, UserID: w.UserID, Usage: w.Usage},
+			TenantID: w.TenantID,
+		})
+		if w.NotifyChannel == "" || w.NotifyChatID == "" {
+			return
+		}
+		if !pub.TryPublishOutbound(bus.OutboundMessage{
+			Channel: w.NotifyChannel,
+			ChatID:  w.NotifyChatID,
+			Content: WarningMessage(w),
+		}) {
+			slog.Warn("budget: owner notification dropped (outbound full)", "channel", w.NotifyChannel)
+		}
+	}
+}
+
+// WarningMessage renders a warning for a chat message.
+func WarningMessage(w Warning) string {
+	subject := w.Usage.Subject
+	switch w.Usage.Level {
+	case LevelTenant:
+		subject = "tenant"
+	case LevelAgent:
+		subject = "agent " + w.AgentKey
+	case LevelUser:
+		subject = "user " + w.UserID
+	}
+	return fmt.Sprintf("Budget warning: %s has used $%.2f of its $%.2f %s budget (%.0f%%). Runs stop when the cap is reached; it resets at %s.",
+		subject, w.Usage.SpentUSD, w.Usage.LimitUSD, dailyOrMonthly(w.Usage.Window), w.Usage.Percent, w.Usage.ResetsAt)
+}
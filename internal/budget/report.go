@@ -0,0 +1,53 @@
+package budget
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// Report is the response of the quota.budget RPC and GET /v1/budget.
+type Report struct {
+	Enabled  bool    `json:"enabled"`
+	AgentKey string  `json:"agent_key,omitempty"`
+	UserID   string  `json:"user_id,omitempty"`
+	Budgets  []Usage `json:"budgets"`
+}
+
+// AgentLookup resolves agents by key or UUID. store.AgentStore satisfies it.
+type AgentLookup interface {
+	GetByKey(ctx context.Context, agentKey string) (*store.AgentData, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*store.AgentData, error)
+}
+
+// BuildReport reports consumption for the context tenant and, when given, an
+// agent (key or UUID) and channel user. Enabled is false when no enforcer is
+// configured.
+func BuildReport(ctx context.Context, e *Enforcer, agents AgentLookup, agentRef, userID string) (Report, error) {
+	report := Report{Enabled: e != nil, UserID: userID, Budgets: []Usage{}}
+	if e == nil {
+		return report, nil
+	}
+	scope := Scope{TenantID: store.TenantIDFromContext(ctx), UserID: userID}
+	if agentRef != "" && agents != nil {
+		var ag *store.AgentData
+		var err error
+		if id, parseErr := uuid.Parse(agentRef); parseErr == nil {
+			ag, err = agents.GetByID(ctx, id)
+		} else {
+			ag, err = agents.GetByKey(ctx, agentRef)
+		}
+		if err != nil || ag == nil {
+			return report, fmt.Errorf("agent not found: %s", agentRef)
+		}
+		scope.AgentID = ag.ID
+		scope.AgentKey = ag.AgentKey
+		scope.Agent = ag.ParseBudget()
+		report.AgentKey = ag.AgentKey
+	}
+	report.Budgets = append(report.Budgets, e.Status(ctx, scope)...)
+	return report, nil
+}
@@ -2,9 +2,11 @@ package bus
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 )
 
 // MessageBus routes messages between channels and the agent runtime,
@@ -20,6 +22,16 @@ type MessageBus struct {
 	// Event subscribers (subscriber ID → handler)
 	subscribers map[string]EventHandler
 	subMu       sync.RWMutex
+
+	// Optional durable inbound queue (nil = in-memory channel only).
+	queue InboundQueue
+
+	// Durable deliveries handed out and not yet acked or released (delivery
+	// ID → time handed out). Their leases are renewed every leaseRenewEvery.
+	leases          map[string]time.Time
+	leaseMu         sync.Mutex
+	leaseRenewEvery time.Duration
+	leaseStop       chan struct{}
 }
 
 func New() *MessageBus {
@@ -28,18 +40,37 @@ func New() *MessageBus {
 		outbound:    make(chan OutboundMessage, 1000),
 		handlers:    make(map[string]MessageHandler),
 		subscribers: make(map[string]EventHandler),
+		leases:      make(map[string]time.Time),
+	}
+}
+
+// SetInboundQueue routes inbound messages through a durable queue instead of
+// the in-memory channel. Must be called before the bus is used.
+func (mb *MessageBus) SetInboundQueue(q InboundQueue) {
+	mb.queue = q
+	if mb.leaseRenewEvery <= 0 {
+		mb.leaseRenewEvery = leaseRenewInterval
 	}
+	mb.leaseStop = make(chan struct{})
+	go mb.renewLeases(mb.leaseStop)
 }
 
 // PublishInbound queues an inbound message from a channel.
-// Blocks if the inbound buffer is full.
+// Blocks if the inbound buffer is full. With a durable queue the in-memory
+// buffer is only used when the queue rejects the message.
 func (mb *MessageBus) PublishInbound(msg InboundMessage) {
+	if mb.enqueueDurable(msg) {
+		return
+	}
 	mb.inbound <- msg
 }
 
 // TryPublishInbound attempts to queue an inbound message without blocking.
 // Returns false if the inbound buffer is full (message dropped).
 func (mb *MessageBus) TryPublishInbound(msg InboundMessage) bool {
+	if mb.enqueueDurable(msg) {
+		return true
+	}
 	select {
 	case mb.inbound <- msg:
 		return true
@@ -48,13 +79,138 @@ func (mb *MessageBus) TryPublishInbound(msg InboundMessage) bool {
 	}
 }
 
+// durableEnqueueTimeout bounds a durable enqueue so a stalled backend makes
+// channel handlers fall back to the in-memory buffer instead of hanging.
+const durableEnqueueTimeout = 5 * time.Second
+
+// enqueueDurable persists msg to the durable queue, if configured.
+// Returns false when there is no queue or the enqueue failed.
+func (mb *MessageBus) enqueueDurable(msg InboundMessage) bool {
+	if mb.queue == nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), durableEnqueueTimeout)
+	defer cancel()
+	if err := mb.queue.Enqueue(ctx, msg); err != nil {
+		slog.Warn("bus: durable enqueue failed, using in-memory buffer",
+			"channel", msg.Channel, "chat_id", msg.ChatID, "error", err)
+		return false
+	}
+	return true
+}
+
 // ConsumeInbound blocks until an inbound message is available or ctx is cancelled.
+// Messages from a durable queue must be acked with Ack after processing,
+// otherwise they are redelivered.
 func (mb *MessageBus) ConsumeInbound(ctx context.Context) (InboundMessage, bool) {
-	select {
-	case msg := <-mb.inbound:
-		return msg, true
-	case <-ctx.Done():
-		return InboundMessage{}, false
+	if mb.queue == nil {
+		select {
+		case msg := <-mb.inbound:
+			return msg, true
+		case <-ctx.Done():
+			return InboundMessage{}, false
+		}
+	}
+
+	for {
+		// Messages that fell back to the in-memory buffer go first.
+		select {
+		case msg := <-mb.inbound:
+			return msg, true
+		case <-ctx.Done():
+			return InboundMessage{}, false
+		default:
+		}
+
+		d, err := mb.queue.Dequeue(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return InboundMessage{}, false
+			}
+			slog.Warn("bus: durable dequeue failed", "error", err)
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+				return InboundMessage{}, false
+			}
+			continue
+		}
+		if d != nil {
+			mb.leaseMu.Lock()
+			mb.leases[d.ID] = time.Now()
+			mb.leaseMu.Unlock()
+			return d.Message, true
+		}
+	}
+}
+
+// Ack acknowledges every durable delivery behind msg so it is not redelivered.
+// No-op for messages from the in-memory bus.
+func (mb *MessageBus) Ack(ctx context.Context, msg InboundMessage) error {
+	if mb.queue == nil {
+		return nil
+	}
+	mb.Release(msg)
+	var errs []error
+	for _, id := range msg.DeliveryIDs {
+		if err := mb.queue.Ack(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("ack %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Release stops renewing the leases behind msg without acking it, so the
+// durable queue redelivers it once the current lease runs out.
+func (mb *MessageBus) Release(msg InboundMessage) {
+	mb.leaseMu.Lock()
+	defer mb.leaseMu.Unlock()
+	for _, id := range msg.DeliveryIDs {
+		delete(mb.leases, id)
+	}
+}
+
+const (
+	// leaseRenewInterval keeps in-flight deliveries well inside
+	// DefaultRedeliverAfter.
+	leaseRenewInterval = DefaultRedeliverAfter / 5
+	// maxLeaseHold bounds how long a delivery is kept alive, so a message
+	// that is never acked (lost on some code path) is eventually redelivered.
+	maxLeaseHold = 2 * time.Hour
+)
+
+// renewLeases extends the redelivery deadline of every in-flight delivery
+// until it is acked or released, or until the bus is closed.
+func (mb *MessageBus) renewLeases(stop <-chan struct{}) {
+	ticker := time.NewTicker(mb.leaseRenewEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		mb.leaseMu.Lock()
+		ids := make([]string, 0, len(mb.leases))
+		for id, since := range mb.leases {
+			if now.Sub(since) > maxLeaseHold {
+				slog.Warn("bus: delivery held too long, letting it be redelivered", "id", id, "held", now.Sub(since))
+				delete(mb.leases, id)
+				continue
+			}
+			ids = append(ids, id)
+		}
+		mb.leaseMu.Unlock()
+
+		for _, id := range ids {
+			ctx, cancel := context.WithTimeout(context.Background(), durableEnqueueTimeout)
+			if err := mb.queue.Extend(ctx, id); err != nil {
+				slog.Warn("bus: extend delivery lease failed", "id", id, "error", err)
+			}
+			cancel()
+		}
 	}
 }
 
@@ -140,4 +296,12 @@ func (mb *MessageBus) Broadcast(event Event) {
 func (mb *MessageBus) Close() {
 	close(mb.inbound)
 	close(mb.outbound)
+	if mb.leaseStop != nil {
+		close(mb.leaseStop)
+	}
+	if mb.queue != nil {
+		if err := mb.queue.Close(); err != nil {
+			slog.Warn("bus: close durable queue", "error", err)
+		}
+	}
 }
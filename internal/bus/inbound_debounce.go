@@ -134,7 +134,7 @@ func debounceKey(msg InboundMessage) string {
 
 // mergeInboundMessages combines multiple messages into one.
 // Content is joined with newlines; media paths are concatenated;
-// metadata and other fields come from the last message.
+// delivery IDs are collected; metadata and other fields come from the last message.
 func mergeInboundMessages(msgs []InboundMessage) InboundMessage {
 	if len(msgs) == 1 {
 		return msgs[0]
@@ -158,6 +158,13 @@ func mergeInboundMessages(msgs []InboundMessage) InboundMessage {
 	}
 	last.Media = allMedia
 
+	// Keep every delivery ID so the merged message acks all of its parts.
+	var ids []string
+	for _, m := range msgs {
+		ids = append(ids, m.DeliveryIDs...)
+	}
+	last.DeliveryIDs = ids
+
 	return last
 }
 
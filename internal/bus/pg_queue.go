@@ -0,0 +1,152 @@
+package bus
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// pgQueuePollInterval is how long Dequeue waits when the queue is empty.
+	pgQueuePollInterval = 500 * time.Millisecond
+	// pgDeadLetterPurgeEvery throttles the purge of expired dead letters.
+	pgDeadLetterPurgeEvery = time.Hour
+)
+
+// PGQueue is an InboundQueue backed by the bus_inbound_queue table.
+// Replicas claim rows with FOR UPDATE SKIP LOCKED, so each message goes to
+// one consumer; a claimed row stays hidden for redeliverAfter and becomes
+// visible again if it is not acked (deleted) in time. A row claimed more than
+// maxDeliveries times is dead-lettered (dead_at set) and never claimed again;
+// dead letters are purged after deadRetention.
+type PGQueue struct {
+	db             *sql.DB
+	queue          string
+	redeliverAfter time.Duration
+	maxDeliveries  int
+	deadRetention  time.Duration
+	lastPurge      atomic.Int64 // unix nanos of the last dead-letter purge
+}
+
+// NewPGQueue creates a Postgres queue. queue names the logical stream so
+// several queues can share the table.
+func NewPGQueue(db *sql.DB, queue string, redeliverAfter time.Duration) *PGQueue {
+	if redeliverAfter <= 0 {
+		redeliverAfter = DefaultRedeliverAfter
+	}
+	return &PGQueue{
+		db:             db,
+		queue:          queue,
+		redeliverAfter: redeliverAfter,
+		maxDeliveries:  DefaultMaxDeliveries,
+		deadRetention:  DefaultDeadLetterRetention,
+	}
+}
+
+func (q *PGQueue) Enqueue(ctx context.Context, msg InboundMessage) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = q.db.ExecContext(ctx,
+		`INSERT INTO bus_inbound_queue (queue, payload) VALUES ($1, $2)`,
+		q.queue, payload)
+	return err
+}
+
+func (q *PGQueue) Dequeue(ctx context.Context) (*Delivery, error) {
+	var (
+		id       int64
+		payload  []byte
+		attempts int
+	)
+	err := q.db.QueryRowContext(ctx, `
+		UPDATE bus_inbound_queue
+		SET visible_at = NOW() + make_interval(secs => $2), attempts = attempts + 1
+		WHERE id = (
+			SELECT id FROM bus_inbound_queue
+			WHERE queue = $1 AND dead_at IS NULL AND visible_at <= NOW()
+			ORDER BY id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING id, payload, attempts`,
+		q.queue, q.redeliverAfter.Seconds(),
+	).Scan(&id, &payload, &attempts)
+	if errors.Is(err, sql.ErrNoRows) {
+		q.purgeDeadLetters(ctx)
+		select {
+		case <-time.After(pgQueuePollInterval):
+		case <-ctx.Done():
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if attempts > q.maxDeliveries {
+		slog.Warn("bus: dead-lettering inbound message after repeated failed deliveries",
+			"id", id, "queue", q.queue, "attempts", attempts-1)
+		_, err := q.db.ExecContext(ctx, `UPDATE bus_inbound_queue SET dead_at = NOW() WHERE id = $1`, id)
+		return nil, err
+	}
+
+	deliveryID := strconv.FormatInt(id, 10)
+	msg, err := decodeQueued(payload, deliveryID)
+	if err != nil {
+		// Poison message: drop it rather than redeliver it forever.
+		slog.Warn("bus: dropping undecodable queued message", "id", id, "error", err)
+		return nil, q.Ack(ctx, deliveryID)
+	}
+	return &Delivery{ID: deliveryID, Message: msg}, nil
+}
+
+func (q *PGQueue) Ack(ctx context.Context, id string) error {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return err
+	}
+	_, err = q.db.ExecContext(ctx, `DELETE FROM bus_inbound_queue WHERE id = $1`, n)
+	return err
+}
+
+func (q *PGQueue) Extend(ctx context.Context, id string) error {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return err
+	}
+	_, err = q.db.ExecContext(ctx,
+		`UPDATE bus_inbound_queue SET visible_at = NOW() + make_interval(secs => $2)
+		 WHERE id = $1 AND dead_at IS NULL`,
+		n, q.redeliverAfter.Seconds())
+	return err
+}
+
+// purgeDeadLetters deletes dead letters older than deadRetention. It runs
+// from idle polls at most once per pgDeadLetterPurgeEvery per replica.
+func (q *PGQueue) purgeDeadLetters(ctx context.Context) {
+	now := time.Now()
+	last := q.lastPurge.Load()
+	if now.Sub(time.Unix(0, last)) < pgDeadLetterPurgeEvery || !q.lastPurge.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	res, err := q.db.ExecContext(ctx,
+		`DELETE FROM bus_inbound_queue WHERE queue = $1 AND dead_at < NOW() - make_interval(secs => $2)`,
+		q.queue, q.deadRetention.Seconds())
+	if err != nil {
+		slog.Warn("bus: purge dead-lettered messages failed", "queue", q.queue, "error", err)
+		return
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		slog.Info("bus: purged expired dead-lettered messages", "queue", q.queue, "count", n)
+	}
+}
+
+// Close is a no-op: the database handle is owned by the store layer.
+func (q *PGQueue) Close() error { return nil }
@@ -0,0 +1,144 @@
+package bus
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func newTestPGQueueDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("GOCLAW_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("GOCLAW_POSTGRES_DSN is not set")
+	}
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	stmts := []string{
+		`CREATE TABLE IF NOT EXISTS bus_inbound_queue (
+			id BIGSERIAL PRIMARY KEY,
+			queue VARCHAR(64) NOT NULL,
+			payload JSONB NOT NULL,
+			attempts INT NOT NULL DEFAULT 0,
+			visible_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`,
+		`ALTER TABLE bus_inbound_queue ADD COLUMN IF NOT EXISTS dead_at TIMESTAMPTZ`,
+		`DELETE FROM bus_inbound_queue WHERE queue = 'test'`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("schema: %v", err)
+		}
+	}
+	return db
+}
+
+func TestPGQueue_SkipLockedAndRedelivery(t *testing.T) {
+	db := newTestPGQueueDB(t)
+	ctx := context.Background()
+	a := NewPGQueue(db, "test", time.Second)
+	b := NewPGQueue(db, "test", time.Second)
+
+	if err := a.Enqueue(ctx, InboundMessage{Channel: "telegram", Content: "one"}); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := a.Dequeue(ctx)
+	if err != nil || d == nil || d.Message.Content != "one" {
+		t.Fatalf("Dequeue = %+v, %v", d, err)
+	}
+	// Claimed row is invisible to the other replica.
+	if other, err := b.Dequeue(ctx); err != nil || other != nil {
+		t.Fatalf("second consumer got %+v, %v", other, err)
+	}
+
+	// Unacked: visible again after the redelivery timeout.
+	time.Sleep(1100 * time.Millisecond)
+	again, err := b.Dequeue(ctx)
+	if err != nil || again == nil || again.ID != d.ID {
+		t.Fatalf("redelivery = %+v, %v", again, err)
+	}
+	if err := b.Ack(ctx, again.ID); err != nil {
+		t.Fatal(err)
+	}
+	if left, err := a.Dequeue(ctx); err != nil || left != nil {
+		t.Fatalf("acked message redelivered: %+v, %v", left, err)
+	}
+}
+
+func TestPGQueue_DeadLettersAfterMaxDeliveries(t *testing.T) {
+	db := newTestPGQueueDB(t)
+	ctx := context.Background()
+	q := NewPGQueue(db, "test", 100*time.Millisecond)
+	q.maxDeliveries = 2
+
+	if err := q.Enqueue(ctx, InboundMessage{Channel: "telegram", Content: "poison"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := range q.maxDeliveries {
+		d, err := q.Dequeue(ctx)
+		if err != nil || d == nil {
+			t.Fatalf("delivery %d = %+v, %v", i+1, d, err)
+		}
+		time.Sleep(150 * time.Millisecond) // never acked
+	}
+
+	// The next claim dead-letters the row instead of handing it out.
+	if d, err := q.Dequeue(ctx); err != nil || d != nil {
+		t.Fatalf("over-limit delivery = %+v, %v", d, err)
+	}
+	var dead int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM bus_inbound_queue WHERE queue = 'test' AND dead_at IS NOT NULL`).Scan(&dead); err != nil {
+		t.Fatal(err)
+	}
+	if dead != 1 {
+		t.Fatalf("dead-lettered rows = %d, want 1", dead)
+	}
+	if d, err := q.Dequeue(ctx); err != nil || d != nil {
+		t.Fatalf("dead-lettered message redelivered: %+v, %v", d, err)
+	}
+}
+
+func TestPGQueue_ExtendAndPurge(t *testing.T) {
+	db := newTestPGQueueDB(t)
+	ctx := context.Background()
+	q := NewPGQueue(db, "test", 300*time.Millisecond)
+
+	if err := q.Enqueue(ctx, InboundMessage{Channel: "telegram", Content: "long"}); err != nil {
+		t.Fatal(err)
+	}
+	d, err := q.Dequeue(ctx)
+	if err != nil || d == nil {
+		t.Fatalf("Dequeue = %+v, %v", d, err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if err := q.Extend(ctx, d.ID); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if again, err := q.Dequeue(ctx); err != nil || again != nil {
+		t.Fatalf("extended delivery handed out again: %+v, %v", again, err)
+	}
+
+	// Expired dead letters are purged on an idle poll.
+	if _, err := db.Exec(`UPDATE bus_inbound_queue SET dead_at = NOW() - INTERVAL '30 days' WHERE queue = 'test'`); err != nil {
+		t.Fatal(err)
+	}
+	q.purgeDeadLetters(ctx)
+	var left int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM bus_inbound_queue WHERE queue = 'test'`).Scan(&left); err != nil {
+		t.Fatal(err)
+	}
+	if left != 0 {
+		t.Fatalf("expired dead letters left = %d", left)
+	}
+}
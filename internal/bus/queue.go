@@ -0,0 +1,64 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Durable inbound queue backends (selected via GOCLAW_BUS_BACKEND).
+const (
+	QueueBackendMemory   = "memory"
+	QueueBackendRedis    = "redis"
+	QueueBackendPostgres = "postgres"
+)
+
+// DefaultRedeliverAfter is how long a delivered message may stay unacked
+// before another consumer is allowed to claim it.
+const DefaultRedeliverAfter = 5 * time.Minute
+
+// DefaultMaxDeliveries is how many times a message is handed out before it
+// is dead-lettered instead of redelivered again.
+const DefaultMaxDeliveries = 5
+
+// DefaultDeadLetterRetention is how long dead-lettered messages are kept for
+// inspection before they are purged.
+const DefaultDeadLetterRetention = 7 * 24 * time.Hour
+
+// Delivery is one message handed out by an InboundQueue.
+// ID must be passed to Ack once the message has been processed.
+type Delivery struct {
+	ID      string
+	Message InboundMessage
+}
+
+// InboundQueue is a durable, at-least-once inbound message queue shared by
+// all gateway replicas (a single consumer group). Messages handed out by
+// Dequeue stay pending until acked; pending messages whose consumer crashed
+// are redelivered after the backend's redelivery timeout.
+type InboundQueue interface {
+	// Enqueue persists a message.
+	Enqueue(ctx context.Context, msg InboundMessage) error
+	// Dequeue waits briefly for the next message. Returns (nil, nil) when
+	// nothing arrived within the backend's poll window so callers can
+	// re-check ctx and other sources.
+	Dequeue(ctx context.Context) (*Delivery, error)
+	// Ack marks a delivered message as processed.
+	Ack(ctx context.Context, id string) error
+	// Extend pushes back the redelivery deadline of a delivered message that
+	// is still being processed, so long runs are not handed out twice.
+	Extend(ctx context.Context, id string) error
+	// Close releases backend resources.
+	Close() error
+}
+
+// decodeQueued restores a stored message and stamps it with its delivery ID.
+// DeliveryIDs are never serialized: they belong to a single delivery.
+func decodeQueued(data []byte, id string) (InboundMessage, error) {
+	var msg InboundMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return InboundMessage{}, err
+	}
+	msg.DeliveryIDs = []string{id}
+	return msg, nil
+}
@@ -0,0 +1,198 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memQueue is an InboundQueue fake with visibility-timeout redelivery.
+type memQueue struct {
+	mu       sync.Mutex
+	nextID   int
+	entries  map[string][]byte
+	order    []string
+	hidden   map[string]time.Time
+	redeliv  time.Duration
+	failNext bool
+}
+
+func newMemQueue(redeliver time.Duration) *memQueue {
+	return &memQueue{entries: map[string][]byte{}, hidden: map[string]time.Time{}, redeliv: redeliver}
+}
+
+func (q *memQueue) Enqueue(_ context.Context, msg InboundMessage) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.failNext {
+		q.failNext = false
+		return errors.New("backend down")
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	q.nextID++
+	id := strconv.Itoa(q.nextID)
+	q.entries[id] = data
+	q.order = append(q.order, id)
+	return nil
+}
+
+func (q *memQueue) Dequeue(ctx context.Context) (*Delivery, error) {
+	q.mu.Lock()
+	now := time.Now()
+	for _, id := range q.order {
+		data, ok := q.entries[id]
+		if !ok || now.Before(q.hidden[id]) {
+			continue
+		}
+		q.hidden[id] = now.Add(q.redeliv)
+		q.mu.Unlock()
+		msg, err := decodeQueued(data, id)
+		if err != nil {
+			return nil, err
+		}
+		return &Delivery{ID: id, Message: msg}, nil
+	}
+	q.mu.Unlock()
+	select {
+	case <-time.After(5 * time.Millisecond):
+	case <-ctx.Done():
+	}
+	return nil, nil
+}
+
+func (q *memQueue) Ack(_ context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.entries, id)
+	return nil
+}
+
+func (q *memQueue) Extend(_ context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.entries[id]; ok {
+		q.hidden[id] = time.Now().Add(q.redeliv)
+	}
+	return nil
+}
+
+func (q *memQueue) Close() error { return nil }
+
+func (q *memQueue) pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.entries)
+}
+
+func TestMessageBus_DurableQueueAckAndRedeliver(t *testing.T) {
+	q := newMemQueue(50 * time.Millisecond)
+	mb := New()
+	mb.SetInboundQueue(q)
+
+	mb.PublishInbound(InboundMessage{Channel: "telegram", ChatID: "1", Content: "hello"})
+	if q.pending() != 1 {
+		t.Fatalf("message should be persisted in the queue, pending=%d", q.pending())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	// First delivery is "lost" (consumer crashed before ack).
+	first, ok := mb.ConsumeInbound(ctx)
+	if !ok || first.Content != "hello" || len(first.DeliveryIDs) != 1 {
+		t.Fatalf("first delivery = %+v, %v", first, ok)
+	}
+
+	// After the redelivery timeout it is handed out again.
+	again, ok := mb.ConsumeInbound(ctx)
+	if !ok || again.DeliveryIDs[0] != first.DeliveryIDs[0] {
+		t.Fatalf("expected redelivery of %v, got %+v", first.DeliveryIDs, again)
+	}
+	if err := mb.Ack(ctx, again); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if q.pending() != 0 {
+		t.Fatalf("acked message still pending")
+	}
+}
+
+func TestMessageBus_RenewsLeaseUntilAck(t *testing.T) {
+	q := newMemQueue(100 * time.Millisecond)
+	mb := New()
+	mb.leaseRenewEvery = 20 * time.Millisecond
+	mb.SetInboundQueue(q)
+	defer close(mb.leaseStop)
+
+	mb.PublishInbound(InboundMessage{Channel: "telegram", ChatID: "1", Content: "long run"})
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	msg, ok := mb.ConsumeInbound(ctx)
+	if !ok {
+		t.Fatal("no delivery")
+	}
+
+	// Well past the redelivery timeout the in-flight message is not handed out again.
+	waitCtx, waitCancel := context.WithTimeout(ctx, 400*time.Millisecond)
+	defer waitCancel()
+	if again, ok := mb.ConsumeInbound(waitCtx); ok {
+		t.Fatalf("in-flight message redelivered: %+v", again)
+	}
+
+	// Once released, the lease lapses and the message is redelivered.
+	mb.Release(msg)
+	again, ok := mb.ConsumeInbound(ctx)
+	if !ok || again.DeliveryIDs[0] != msg.DeliveryIDs[0] {
+		t.Fatalf("expected redelivery after release, got %+v", again)
+	}
+	if err := mb.Ack(ctx, again); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMessageBus_DurableQueueFallback(t *testing.T) {
+	q := newMemQueue(time.Minute)
+	q.failNext = true
+	mb := New()
+	mb.SetInboundQueue(q)
+
+	if !mb.TryPublishInbound(InboundMessage{Content: "buffered"}) {
+		t.Fatal("failed enqueue should fall back to the in-memory buffer")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	got, ok := mb.ConsumeInbound(ctx)
+	if !ok || got.Content != "buffered" || len(got.DeliveryIDs) != 0 {
+		t.Fatalf("got %+v, %v", got, ok)
+	}
+}
+
+func TestMergeInboundMessages_KeepsDeliveryIDs(t *testing.T) {
+	q := newMemQueue(time.Minute)
+	mb := New()
+	mb.SetInboundQueue(q)
+	mb.PublishInbound(InboundMessage{Channel: "c", ChatID: "1", SenderID: "u", Content: "a"})
+	mb.PublishInbound(InboundMessage{Channel: "c", ChatID: "1", SenderID: "u", Content: "b"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	m1, _ := mb.ConsumeInbound(ctx)
+	m2, _ := mb.ConsumeInbound(ctx)
+
+	merged := mergeInboundMessages([]InboundMessage{m1, m2})
+	if len(merged.DeliveryIDs) != 2 {
+		t.Fatalf("merged delivery IDs = %v", merged.DeliveryIDs)
+	}
+	if err := mb.Ack(ctx, merged); err != nil {
+		t.Fatal(err)
+	}
+	if q.pending() != 0 {
+		t.Fatalf("merged ack left %d pending", q.pending())
+	}
+}
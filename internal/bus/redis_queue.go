@@ -0,0 +1,177 @@
+//go:build redis
+
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// redisQueueBlock bounds each XREADGROUP wait so callers can re-check ctx.
+	redisQueueBlock = 2 * time.Second
+	// redisQueueMaxLen caps the stream length (approximate trim on XADD).
+	redisQueueMaxLen = 100000
+	redisQueueField  = "msg"
+	// redisDeadLetterSuffix names the stream dead letters are moved to.
+	redisDeadLetterSuffix = ":dead"
+)
+
+// RedisStreamQueue is an InboundQueue backed by a Redis Stream and a consumer
+// group. Every replica joins the same group under its own consumer name, so
+// each message goes to one replica. Entries stay in the group's pending list
+// until XACKed; entries idle longer than redeliverAfter are taken over with
+// XAUTOCLAIM. An entry delivered more than maxDeliveries times is moved to
+// the "<stream>:dead" stream, which keeps entries for DefaultDeadLetterRetention.
+type RedisStreamQueue struct {
+	client         *redis.Client
+	stream         string
+	group          string
+	consumer       string
+	redeliverAfter time.Duration
+	maxDeliveries  int
+}
+
+// NewRedisStreamQueue creates the consumer group (and stream) if needed.
+func NewRedisStreamQueue(ctx context.Context, client *redis.Client, stream, group, consumer string, redeliverAfter time.Duration) (*RedisStreamQueue, error) {
+	if redeliverAfter <= 0 {
+		redeliverAfter = DefaultRedeliverAfter
+	}
+	err := client.XGroupCreateMkStream(ctx, stream, group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil, fmt.Errorf("create consumer group: %w", err)
+	}
+	return &RedisStreamQueue{
+		client:         client,
+		stream:         stream,
+		group:          group,
+		consumer:       consumer,
+		redeliverAfter: redeliverAfter,
+		maxDeliveries:  DefaultMaxDeliveries,
+	}, nil
+}
+
+func (q *RedisStreamQueue) Enqueue(ctx context.Context, msg InboundMessage) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return q.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: q.stream,
+		MaxLen: redisQueueMaxLen,
+		Approx: true,
+		Values: map[string]any{redisQueueField: string(payload)},
+	}).Err()
+}
+
+func (q *RedisStreamQueue) Dequeue(ctx context.Context) (*Delivery, error) {
+	// Redeliver entries abandoned by crashed or stuck consumers first.
+	claimed, _, err := q.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   q.stream,
+		Group:    q.group,
+		Consumer: q.consumer,
+		MinIdle:  q.redeliverAfter,
+		Start:    "0-0",
+		Count:    1,
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("xautoclaim: %w", err)
+	}
+	if len(claimed) > 0 {
+		m := claimed[0]
+		pending, err := q.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: q.stream,
+			Group:  q.group,
+			Start:  m.ID,
+			End:    m.ID,
+			Count:  1,
+		}).Result()
+		if err != nil {
+			return nil, fmt.Errorf("xpending: %w", err)
+		}
+		if len(pending) > 0 && pending[0].RetryCount > int64(q.maxDeliveries) {
+			return nil, q.deadLetter(ctx, m, pending[0].RetryCount-1)
+		}
+		slog.Info("bus: redelivering unacked message", "id", m.ID)
+		return q.delivery(ctx, m)
+	}
+
+	streams, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    q.group,
+		Consumer: q.consumer,
+		Streams:  []string{q.stream, ">"},
+		Count:    1,
+		Block:    redisQueueBlock,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("xreadgroup: %w", err)
+	}
+	if len(streams) == 0 || len(streams[0].Messages) == 0 {
+		return nil, nil
+	}
+	return q.delivery(ctx, streams[0].Messages[0])
+}
+
+// delivery decodes a stream entry. Undecodable entries are acked and skipped.
+func (q *RedisStreamQueue) delivery(ctx context.Context, m redis.XMessage) (*Delivery, error) {
+	raw, _ := m.Values[redisQueueField].(string)
+	msg, err := decodeQueued([]byte(raw), m.ID)
+	if err != nil {
+		slog.Warn("bus: dropping undecodable queued message", "id", m.ID, "error", err)
+		return nil, q.Ack(ctx, m.ID)
+	}
+	return &Delivery{ID: m.ID, Message: msg}, nil
+}
+
+// deadLetter moves an entry that keeps failing to the dead-letter stream and
+// removes it from the queue. Entries older than DefaultDeadLetterRetention are
+// trimmed from the dead-letter stream on each write.
+func (q *RedisStreamQueue) deadLetter(ctx context.Context, m redis.XMessage, deliveries int64) error {
+	slog.Warn("bus: dead-lettering inbound message after repeated failed deliveries",
+		"id", m.ID, "stream", q.stream, "attempts", deliveries)
+	raw, _ := m.Values[redisQueueField].(string)
+	minID := fmt.Sprintf("%d-0", time.Now().Add(-DefaultDeadLetterRetention).UnixMilli())
+	pipe := q.client.TxPipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: q.stream + redisDeadLetterSuffix,
+		MinID:  minID,
+		Approx: true,
+		Values: map[string]any{redisQueueField: raw, "id": m.ID, "attempts": deliveries},
+	})
+	pipe.XAck(ctx, q.stream, q.group, m.ID)
+	pipe.XDel(ctx, q.stream, m.ID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (q *RedisStreamQueue) Ack(ctx context.Context, id string) error {
+	pipe := q.client.TxPipeline()
+	pipe.XAck(ctx, q.stream, q.group, id)
+	pipe.XDel(ctx, q.stream, id)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Extend resets the idle time of a pending entry (XCLAIM JUSTID, which does
+// not count as a delivery) so XAUTOCLAIM leaves it alone while it is processed.
+func (q *RedisStreamQueue) Extend(ctx context.Context, id string) error {
+	return q.client.XClaimJustID(ctx, &redis.XClaimArgs{
+		Stream:   q.stream,
+		Group:    q.group,
+		Consumer: q.consumer,
+		Messages: []string{id},
+	}).Err()
+}
+
+// Close is a no-op: the Redis client is shared with the cache layer.
+func (q *RedisStreamQueue) Close() error { return nil }
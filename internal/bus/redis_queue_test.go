@@ -0,0 +1,112 @@
+//go:build redis
+
+package bus
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisStreamQueue_ConsumerGroupRedelivery(t *testing.T) {
+	dsn := os.Getenv("REDIS_TEST_DSN")
+	if dsn == "" {
+		dsn = "redis://localhost:6379/15"
+	}
+	opts, err := redis.ParseURL(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := redis.NewClient(opts)
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+	const stream = "test:bus:inbound"
+	t.Cleanup(func() {
+		client.Del(context.Background(), stream)
+		client.Close()
+	})
+
+	a, err := NewRedisStreamQueue(ctx, client, stream, "gateway", "a", 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewRedisStreamQueue(ctx, client, stream, "gateway", "b", 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Enqueue(ctx, InboundMessage{Channel: "discord", Content: "ping"}); err != nil {
+		t.Fatal(err)
+	}
+	d, err := a.Dequeue(ctx)
+	if err != nil || d == nil || d.Message.Content != "ping" {
+		t.Fatalf("Dequeue = %+v, %v", d, err)
+	}
+
+	// Consumer a never acks; b claims it once it has been idle long enough.
+	time.Sleep(300 * time.Millisecond)
+	again, err := b.Dequeue(ctx)
+	if err != nil || again == nil || again.ID != d.ID {
+		t.Fatalf("redelivery = %+v, %v", again, err)
+	}
+	if err := b.Ack(ctx, again.ID); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := client.XPending(ctx, stream, "gateway").Result(); pending.Count != 0 {
+		t.Fatalf("pending after ack = %d", pending.Count)
+	}
+}
+
+func TestRedisStreamQueue_DeadLettersAfterMaxDeliveries(t *testing.T) {
+	dsn := os.Getenv("REDIS_TEST_DSN")
+	if dsn == "" {
+		dsn = "redis://localhost:6379/15"
+	}
+	opts, err := redis.ParseURL(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := redis.NewClient(opts)
+	ctx := context.Background()
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Skipf("Redis not available: %v", err)
+	}
+	const stream = "test:bus:poison"
+	t.Cleanup(func() {
+		client.Del(context.Background(), stream, stream+redisDeadLetterSuffix)
+		client.Close()
+	})
+
+	q, err := NewRedisStreamQueue(ctx, client, stream, "gateway", "a", 100*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.maxDeliveries = 2
+	if err := q.Enqueue(ctx, InboundMessage{Channel: "discord", Content: "poison"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := range q.maxDeliveries {
+		if i > 0 {
+			time.Sleep(150 * time.Millisecond) // never acked
+		}
+		if d, err := q.Dequeue(ctx); err != nil || d == nil {
+			t.Fatalf("delivery %d = %+v, %v", i+1, d, err)
+		}
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if d, err := q.Dequeue(ctx); err != nil || d != nil {
+		t.Fatalf("over-limit delivery = %+v, %v", d, err)
+	}
+	if n, _ := client.XLen(ctx, stream+redisDeadLetterSuffix).Result(); n != 1 {
+		t.Fatalf("dead-letter stream length = %d, want 1", n)
+	}
+	if pending, _ := client.XPending(ctx, stream, "gateway").Result(); pending.Count != 0 {
+		t.Fatalf("pending after dead-letter = %d", pending.Count)
+	}
+}
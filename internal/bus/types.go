@@ -30,6 +30,11 @@ type InboundMessage struct {
 	HistoryLimit int               `json:"history_limit,omitempty"` // max turns to keep in context (0=unlimited, from channel config)
 	ToolAllow    []string          `json:"tool_allow,omitempty"`    // per-group tool allow list (nil = no restriction)
 	Metadata     map[string]string `json:"metadata,omitempty"`
+
+	// DeliveryIDs identifies the durable-queue deliveries this message came
+	// from (more than one after debounce merging). Pass the message to
+	// MessageBus.Ack once processed. Empty for the in-memory bus.
+	DeliveryIDs []string `json:"-"`
 }
 
 // OutboundMessage represents a message to be sent to a channel.
@@ -53,6 +58,7 @@ type Event struct {
 	Name     string    `json:"name"`              // event name (e.g. "agent", "chat", "health")
 	Payload  any       `json:"payload,omitempty"`
 	TenantID uuid.UUID `json:"-"` // tenant scope for event filtering (not serialized to clients)
+	Origin   string    `json:"-"` // cluster node that emitted a relayed event (empty = this node)
 }
 
 // Cache invalidation kind constants.
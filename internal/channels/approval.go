@@ -0,0 +1,90 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// approvalCallbackPrefix marks button payloads carrying an approval decision:
+// "ap:a:<id>" approves, "ap:d:<id>" denies. Fits Telegram's 64-byte callback limit.
+const approvalCallbackPrefix = "ap:"
+
+// ApprovalChannel is implemented by channels that can render an approval
+// prompt with interactive approve/deny buttons (Telegram, Slack, Discord).
+type ApprovalChannel interface {
+	SendApprovalPrompt(ctx context.Context, chatID, id, text string) error
+}
+
+// ApprovalResolver decides a pending approval after a button press.
+// channel is the channel instance name; chatID and senderID are platform IDs.
+type ApprovalResolver func(channel, chatID, senderID, id string, approve bool) error
+
+// ApprovalCallbackData returns the button payload for an approval decision.
+func ApprovalCallbackData(id string, approve bool) string {
+	if approve {
+		return approvalCallbackPrefix + "a:" + id
+	}
+	return approvalCallbackPrefix + "d:" + id
+}
+
+// ParseApprovalCallback parses a button payload built by ApprovalCallbackData.
+func ParseApprovalCallback(data string) (id string, approve bool, ok bool) {
+	rest, found := strings.CutPrefix(data, approvalCallbackPrefix)
+	if !found || len(rest) < 3 || rest[1] != ':' {
+		return "", false, false
+	}
+	switch rest[0] {
+	case 'a':
+		return rest[2:], true, true
+	case 'd':
+		return rest[2:], false, true
+	}
+	return "", false, false
+}
+
+// ApprovalOutcome is the text appended to a prompt once it has been decided.
+func ApprovalOutcome(approve bool, by string) string {
+	if approve {
+		return "✅ Approved by " + by
+	}
+	return "❌ Denied by " + by
+}
+
+// SetApprovalResolver sets the resolver used for approval button presses.
+func (c *BaseChannel) SetApprovalResolver(r ApprovalResolver) { c.approvalResolver = r }
+
+// ResolveApproval forwards a button press to the approval resolver.
+func (c *BaseChannel) ResolveApproval(chatID, senderID, id string, approve bool) error {
+	if c.approvalResolver == nil {
+		return fmt.Errorf("approvals are not enabled")
+	}
+	return c.approvalResolver(c.Name(), chatID, senderID, id, approve)
+}
+
+// SetApprovalResolver sets the approval resolver for all current and future channels.
+func (m *Manager) SetApprovalResolver(r ApprovalResolver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.approvalResolver = r
+	for _, ch := range m.channels {
+		if ac, ok := ch.(interface{ SetApprovalResolver(ApprovalResolver) }); ok {
+			ac.SetApprovalResolver(r)
+		}
+	}
+}
+
+// SendApprovalPrompt delivers an approval prompt to a chat. Channels without
+// interactive buttons receive plain text pointing approvers to the dashboard.
+func (m *Manager) SendApprovalPrompt(ctx context.Context, channelName, chatID, id, text string) error {
+	m.mu.RLock()
+	channel, exists := m.channels[channelName]
+	m.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("channel %s not found", channelName)
+	}
+	if ac, ok := channel.(ApprovalChannel); ok {
+		return ac.SendApprovalPrompt(ctx, chatID, id, text)
+	}
+	return m.SendToChannel(ctx, channelName, chatID, text+"\n\nDecide from the dashboard (approval "+id+").")
+}
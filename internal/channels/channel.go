@@ -164,6 +164,7 @@ type BaseChannel struct {
 	agentID          string                  // for DB instances: routes to specific agent (empty = use resolveAgentRoute)
 	tenantID         uuid.UUID               // for DB instances: tenant scope (zero = master tenant fallback)
 	contactCollector *store.ContactCollector // optional: auto-collect contacts from channel messages
+	approvalResolver ApprovalResolver        // optional: decides tool approvals from button presses
 }
 
 // NewBaseChannel creates a new BaseChannel with the given parameters.
@@ -0,0 +1,73 @@
+package discord
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+)
+
+// SendApprovalPrompt sends a tool approval prompt with Approve/Deny buttons.
+// Implements channels.ApprovalChannel.
+func (c *Channel) SendApprovalPrompt(_ context.Context, chatID, id, text string) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("discord bot not running")
+	}
+	_, err := c.session.ChannelMessageSendComplex(chatID, &discordgo.MessageSend{
+		Content: channels.Truncate(text, 1900),
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "Approve", Style: discordgo.SuccessButton, CustomID: channels.ApprovalCallbackData(id, true)},
+				discordgo.Button{Label: "Deny", Style: discordgo.DangerButton, CustomID: channels.ApprovalCallbackData(id, false)},
+			}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("send discord approval prompt: %w", err)
+	}
+	return nil
+}
+
+// handleInteraction handles message component interactions (approval buttons).
+func (c *Channel) handleInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionMessageComponent {
+		return
+	}
+	id, approve, ok := channels.ParseApprovalCallback(i.MessageComponentData().CustomID)
+	if !ok {
+		return
+	}
+
+	user := i.User
+	if i.Member != nil && i.Member.User != nil {
+		user = i.Member.User
+	}
+	if user == nil {
+		return
+	}
+
+	if err := c.ResolveApproval(i.ChannelID, user.ID, id, approve); err != nil {
+		respondErr := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{Content: err.Error(), Flags: discordgo.MessageFlagsEphemeral},
+		})
+		if respondErr != nil {
+			slog.Debug("discord: approval response failed", "id", id, "error", respondErr)
+		}
+		return
+	}
+
+	content := channels.ApprovalOutcome(approve, "<@"+user.ID+">")
+	if i.Message != nil && i.Message.Content != "" {
+		content = channels.Truncate(i.Message.Content, 1900) + "\n\n" + content
+	}
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{Content: content, Components: []discordgo.MessageComponent{}},
+	}); err != nil {
+		slog.Debug("discord: approval prompt update failed", "id", id, "error", err)
+	}
+}
@@ -84,6 +84,7 @@ func (c *Channel) Start(_ context.Context) error {
 	slog.Info("starting discord bot")
 
 	c.session.AddHandler(c.handleMessage)
+	c.session.AddHandler(c.handleInteraction)
 
 	if err := c.session.Open(); err != nil {
 		return fmt.Errorf("open discord session: %w", err)
@@ -68,7 +68,14 @@ func (m *Manager) dispatchOutbound(ctx context.Context) {
 				}
 			}
 
-			if err := channel.Send(ctx, msg); err != nil {
+			msg.Content = m.rehydrate(msg.Channel, msg.ChatID, msg.Content)
+			for i := range msg.Media {
+				msg.Media[i].Caption = m.rehydrate(msg.Channel, msg.ChatID, msg.Media[i].Caption)
+			}
+
+			if m.ownedElsewhere(msg.Channel) {
+				m.forwardOutbound(ctx, msg)
+			} else if err := channel.Send(ctx, msg); err != nil {
 				slog.Error("error sending message to channel",
 					"channel", msg.Channel,
 					"error", err,
@@ -134,9 +141,18 @@ func (m *Manager) SendToChannel(ctx context.Context, channelName, chatID, conten
 	msg := bus.OutboundMessage{
 		Channel: channelName,
 		ChatID:  chatID,
-		Content: content,
+		Content: m.rehydrate(channelName, chatID, content),
 	}
 
+	if m.ownedElsewhere(channelName) {
+		m.mu.RLock()
+		forward := m.forward
+		m.mu.RUnlock()
+		if forward == nil {
+			return fmt.Errorf("channel %s is running on another replica", channelName)
+		}
+		return forward(ctx, msg)
+	}
 	return channel.Send(ctx, msg)
 }
 
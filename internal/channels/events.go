@@ -170,7 +170,7 @@ func (m *Manager) HandleAgentEvent(eventType, runID string, payload any) {
 							currentStream = rc.stream
 							rc.mu.Unlock()
 							if currentStream != nil {
-								currentStream.Update(ctx, split.Answer)
+								currentStream.Update(ctx, m.rehydrate(rc.ChannelName, rc.ChatID, split.Answer))
 							}
 						}
 						break
@@ -216,7 +216,7 @@ func (m *Manager) HandleAgentEvent(eventType, runID string, payload any) {
 				currentStream := rc.stream
 				rc.mu.Unlock()
 				if currentStream != nil {
-					currentStream.Update(ctx, fullText)
+					currentStream.Update(ctx, m.rehydrate(rc.ChannelName, rc.ChatID, fullText))
 				}
 			}
 		case protocol.AgentEventRunCompleted:
@@ -359,10 +359,12 @@ func copyRoutingMeta(src map[string]string) map[string]string {
 // toolStatusMap maps builtin tool names to user-friendly status messages.
 var toolStatusMap = map[string]string{
 	// Filesystem
-	"read_file":  "📝 Reading file...",
-	"write_file": "📝 Writing file...",
-	"list_files": "📝 Listing files...",
-	"edit":       "📝 Editing file...",
+	"read_file":    "📝 Reading file...",
+	"write_file":   "📝 Writing file...",
+	"list_files":   "📝 Listing files...",
+	"search_files": "🔍 Searching files...",
+	"edit":         "📝 Editing file...",
+	"apply_patch":  "📝 Applying patch...",
 	// Runtime
 	"exec": "⚡ Running code...",
 	// Web
@@ -372,6 +374,7 @@ var toolStatusMap = map[string]string{
 	"memory_search":          "🧠 Searching memory...",
 	"memory_get":             "🧠 Retrieving memory...",
 	"knowledge_graph_search": "🧠 Querying knowledge graph...",
+	"kb_search":              "📚 Searching knowledge base...",
 	// Media
 	"read_image":    "👁 Analyzing image...",
 	"read_document": "📄 Reading document...",
@@ -384,8 +387,8 @@ var toolStatusMap = map[string]string{
 	// Browser
 	"browser": "🌐 Browsing...",
 	// Delegation & teams
-	"spawn":        "👥 Delegating task...",
-	"team_tasks":   "📋 Managing team tasks...",
+	"spawn":      "👥 Delegating task...",
+	"team_tasks": "📋 Managing team tasks...",
 	// Sessions
 	"sessions_list":    "📋 Listing sessions...",
 	"session_status":   "📋 Checking session...",
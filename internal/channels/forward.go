@@ -0,0 +1,71 @@
+package channels
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+)
+
+// OutboundForwarder relays an outbound message to the gateway replica that
+// owns its channel. Used with an ownership gate: replies produced on any
+// replica (cron, heartbeat, WS chat, queued turns) must be sent by the one
+// replica where the channel is running.
+type OutboundForwarder func(ctx context.Context, msg bus.OutboundMessage) error
+
+// SetOutboundForwarder installs the relay for channels owned by another replica.
+func (m *Manager) SetOutboundForwarder(fn OutboundForwarder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.forward = fn
+}
+
+// DeliverForwarded sends a message relayed from another replica if this
+// replica owns its channel. Returns false (and sends nothing) otherwise.
+func (m *Manager) DeliverForwarded(msg bus.OutboundMessage) bool {
+	m.mu.RLock()
+	_, exists := m.channels[msg.Channel]
+	m.mu.RUnlock()
+	if !exists || m.ownedElsewhere(msg.Channel) {
+		return false
+	}
+	if !m.bus.TryPublishOutbound(msg) {
+		slog.Warn("outbound buffer full, dropping forwarded message", "channel", msg.Channel, "chat_id", msg.ChatID)
+	}
+	return true
+}
+
+// ownedElsewhere reports whether name is gated and not running on this replica.
+func (m *Manager) ownedElsewhere(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.gate != nil && !m.owned[name]
+}
+
+// setOwned records whether a gated channel is running on this replica.
+func (m *Manager) setOwned(name string, owned bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.owned == nil {
+		return
+	}
+	if owned {
+		m.owned[name] = true
+	} else {
+		delete(m.owned, name)
+	}
+}
+
+// forwardOutbound relays msg to the owning replica.
+func (m *Manager) forwardOutbound(ctx context.Context, msg bus.OutboundMessage) {
+	m.mu.RLock()
+	forward := m.forward
+	m.mu.RUnlock()
+	if forward == nil {
+		slog.Warn("channel is running on another replica, dropping outbound message", "channel", msg.Channel, "chat_id", msg.ChatID)
+		return
+	}
+	if err := forward(ctx, msg); err != nil {
+		slog.Warn("failed to forward outbound message to owning replica", "channel", msg.Channel, "chat_id", msg.ChatID, "error", err)
+	}
+}
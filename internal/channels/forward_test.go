@@ -0,0 +1,111 @@
+package channels
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+)
+
+type sendRecordingChannel struct {
+	*fakeHealthChannel
+	mu   sync.Mutex
+	sent []bus.OutboundMessage
+}
+
+func (c *sendRecordingChannel) Send(_ context.Context, msg bus.OutboundMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, msg)
+	return nil
+}
+
+func (c *sendRecordingChannel) sentCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.sent)
+}
+
+// neverOwnGate models a replica that never wins channel ownership.
+func neverOwnGate(context.Context, string, func() error, func()) func() { return func() {} }
+
+// alwaysOwnGate models a replica that wins ownership of every channel. Like
+// the cluster coordinator it starts the channel in the background.
+func alwaysOwnGate(_ context.Context, _ string, start func() error, stop func()) func() {
+	started := make(chan error, 1)
+	go func() { started <- start() }()
+	return func() {
+		if <-started == nil {
+			stop()
+		}
+	}
+}
+
+func TestManagerForwardsOutboundForChannelsOwnedElsewhere(t *testing.T) {
+	msgBus := bus.New()
+	m := NewManager(msgBus)
+	ch := &sendRecordingChannel{fakeHealthChannel: newFakeHealthChannel("telegram")}
+	m.RegisterChannel("telegram", ch)
+	m.SetOwnershipGate(neverOwnGate)
+
+	forwarded := make(chan bus.OutboundMessage, 1)
+	m.SetOutboundForwarder(func(_ context.Context, msg bus.OutboundMessage) error {
+		forwarded <- msg
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := m.StartAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	msgBus.PublishOutbound(bus.OutboundMessage{Channel: "telegram", ChatID: "42", Content: "hi"})
+
+	select {
+	case msg := <-forwarded:
+		if msg.ChatID != "42" || msg.Content != "hi" {
+			t.Fatalf("forwarded %+v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("outbound message was not forwarded")
+	}
+	if ch.sentCount() != 0 {
+		t.Fatal("non-owning replica sent on a channel it never started")
+	}
+	if m.DeliverForwarded(bus.OutboundMessage{Channel: "telegram", ChatID: "42"}) {
+		t.Fatal("non-owning replica accepted a forwarded message")
+	}
+}
+
+func TestManagerDeliversForwardedOutboundWhenOwner(t *testing.T) {
+	msgBus := bus.New()
+	m := NewManager(msgBus)
+	ch := &sendRecordingChannel{fakeHealthChannel: newFakeHealthChannel("telegram")}
+	m.RegisterChannel("telegram", ch)
+	m.SetOwnershipGate(alwaysOwnGate)
+	m.SetOutboundForwarder(func(context.Context, bus.OutboundMessage) error {
+		t.Error("owner forwarded its own message")
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := m.StartAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for m.ownedElsewhere("telegram") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !m.DeliverForwarded(bus.OutboundMessage{Channel: "telegram", ChatID: "42", Content: "hi"}) {
+		t.Fatal("owner rejected a forwarded message")
+	}
+	for ch.sentCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if ch.sentCount() != 1 {
+		t.Fatalf("sent %d messages, want 1", ch.sentCount())
+	}
+}
@@ -112,7 +112,7 @@ func (l *InstanceLoader) Reload(ctx context.Context) {
 	// Stop and unregister old channels
 	for name := range l.loaded {
 		if ch, ok := l.manager.GetChannel(name); ok {
-			if err := ch.Stop(ctx); err != nil {
+			if err := l.manager.stopChannel(ctx, name, ch); err != nil {
 				slog.Warn("failed to stop channel instance on reload", "name", name, "error", err)
 			}
 		}
@@ -151,7 +151,7 @@ func (l *InstanceLoader) Stop(ctx context.Context) {
 
 	for name := range l.loaded {
 		if ch, ok := l.manager.GetChannel(name); ok {
-			if err := ch.Stop(ctx); err != nil {
+			if err := l.manager.stopChannel(ctx, name, ch); err != nil {
 				slog.Warn("failed to stop channel instance", "name", name, "error", err)
 			}
 		}
@@ -326,7 +326,7 @@ func (l *InstanceLoader) loadInstance(ctx context.Context, inst store.ChannelIns
 
 	// Start the channel if requested (Reload path). LoadAll defers to StartAll.
 	if autoStart {
-		if err := ch.Start(ctx); err != nil {
+		if err := l.manager.startChannel(ctx, inst.Name, ch); err != nil {
 			l.manager.recordChannelStartFailure(inst.Name, ch, "", err)
 			slog.Error("channel instance start failed", "name", inst.Name, "error", err)
 			// Still registered — will show as not running.
@@ -55,8 +55,22 @@ type Manager struct {
 	dispatchTask     *asyncTask
 	mu               sync.RWMutex
 	contactCollector *store.ContactCollector
+	approvalResolver ApprovalResolver
+	rehydrator       Rehydrator
+
+	// Multi-replica ownership: when set, each channel only runs on the
+	// replica that owns it (pollers such as Telegram long-poll must run once).
+	gate    OwnershipGate
+	gated   map[string]func() // channel name → ends ownership
+	owned   map[string]bool   // gated channels currently running on this replica
+	forward OutboundForwarder
 }
 
+// OwnershipGate runs start only while this replica owns the named resource,
+// calling stop if ownership is lost. The returned function gives ownership up
+// (calling stop if running) and waits.
+type OwnershipGate func(ctx context.Context, name string, start func() error, stop func()) (cancel func())
+
 type asyncTask struct {
 	cancel context.CancelFunc
 }
@@ -71,6 +85,16 @@ func NewManager(msgBus *bus.MessageBus) *Manager {
 	}
 }
 
+// SetOwnershipGate makes channel starts exclusive across gateway replicas.
+// Must be called before StartAll.
+func (m *Manager) SetOwnershipGate(gate OwnershipGate) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gate = gate
+	m.gated = make(map[string]func())
+	m.owned = make(map[string]bool)
+}
+
 // StartAll starts all registered channels and the outbound dispatch loop.
 // The dispatcher is always started even when no channels exist yet,
 // because channels may be loaded dynamically later via Reload().
@@ -91,6 +115,10 @@ func (m *Manager) StartAll(ctx context.Context) error {
 	slog.Info("starting all channels")
 
 	for name, channel := range m.channels {
+		if m.gate != nil {
+			m.startGatedLocked(ctx, name, channel)
+			continue
+		}
 		slog.Info("starting channel", "channel", name)
 		if hc, ok := channel.(interface{ MarkStarting(string) }); ok {
 			hc.MarkStarting("Starting")
@@ -110,6 +138,18 @@ func (m *Manager) StartAll(ctx context.Context) error {
 
 // StopAll gracefully stops all channels and the outbound dispatch loop.
 func (m *Manager) StopAll(ctx context.Context) error {
+	// Give up owned channels first, outside m.mu: their start/stop callbacks
+	// record health under the same lock.
+	m.mu.Lock()
+	gated := m.gated
+	if m.gate != nil {
+		m.gated = make(map[string]func())
+	}
+	m.mu.Unlock()
+	for _, cancel := range gated {
+		cancel()
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -121,6 +161,9 @@ func (m *Manager) StopAll(ctx context.Context) error {
 	}
 
 	for name, channel := range m.channels {
+		if _, ok := gated[name]; ok {
+			continue
+		}
 		slog.Info("stopping channel", "channel", name)
 		if err := channel.Stop(ctx); err != nil {
 			m.recordHealthLocked(name, NewFailedChannelHealth("Failed to stop channel", err))
@@ -137,6 +180,57 @@ func (m *Manager) StopAll(ctx context.Context) error {
 	return nil
 }
 
+// startGatedLocked hands a channel to the ownership gate; it starts once this
+// replica owns it. Must be called with m.mu held.
+func (m *Manager) startGatedLocked(ctx context.Context, name string, channel Channel) {
+	if cancel, ok := m.gated[name]; ok {
+		delete(m.gated, name)
+		go cancel()
+	}
+	slog.Info("channel waiting for cluster ownership", "channel", name)
+	m.gated[name] = m.gate(ctx, "channel:"+name, func() error {
+		slog.Info("starting channel", "channel", name)
+		if err := channel.Start(ctx); err != nil {
+			m.recordChannelStartFailure(name, channel, "", err)
+			return err
+		}
+		m.setOwned(name, true)
+		m.RecordHealth(name, snapshotChannelHealth(channel))
+		return nil
+	}, func() {
+		m.setOwned(name, false)
+		slog.Info("stopping channel", "channel", name)
+		if err := channel.Stop(context.Background()); err != nil {
+			slog.Error("error stopping channel", "channel", name, "error", err)
+		}
+	})
+}
+
+// startChannel starts a single channel, through the ownership gate if set.
+func (m *Manager) startChannel(ctx context.Context, name string, channel Channel) error {
+	m.mu.Lock()
+	if m.gate != nil {
+		m.startGatedLocked(ctx, name, channel)
+		m.mu.Unlock()
+		return nil
+	}
+	m.mu.Unlock()
+	return channel.Start(ctx)
+}
+
+// stopChannel stops a single channel, giving up ownership if it is gated.
+func (m *Manager) stopChannel(ctx context.Context, name string, channel Channel) error {
+	m.mu.Lock()
+	cancel, ok := m.gated[name]
+	delete(m.gated, name)
+	m.mu.Unlock()
+	if ok {
+		cancel()
+		return nil
+	}
+	return channel.Stop(ctx)
+}
+
 // GetChannel returns a channel by name.
 func (m *Manager) GetChannel(name string) (Channel, bool) {
 	m.mu.RLock()
@@ -182,6 +276,11 @@ func (m *Manager) RegisterChannel(name string, channel Channel) {
 			bc.SetContactCollector(m.contactCollector)
 		}
 	}
+	if m.approvalResolver != nil {
+		if ac, ok := channel.(interface{ SetApprovalResolver(ApprovalResolver) }); ok {
+			ac.SetApprovalResolver(m.approvalResolver)
+		}
+	}
 	m.channels[name] = channel
 	if hc, ok := channel.(interface{ MarkRegistered(string) }); ok {
 		hc.MarkRegistered("Configured")
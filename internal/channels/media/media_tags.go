@@ -1,11 +1,15 @@
 package media
 
 import (
+	"errors"
 	"fmt"
 	"html"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/nextlevelbuilder/goclaw/internal/docextract"
 )
 
 // docMaxChars is the max characters to extract from text documents (matching TS: 200K).
@@ -91,7 +95,9 @@ var textExtensions = map[string]string{
 
 // ExtractDocumentContent reads a document file and returns its content wrapped in XML tags.
 // For text files: extracts content, truncates at docMaxChars, wraps in <file> block.
-// For binary files: returns a placeholder hint directing to the read_document tool.
+// For PDF/DOCX/XLSX/PPTX: extracts text locally (see docextract) into the same <file> block.
+// For other binary files, scanned PDFs and failed extractions: returns a placeholder
+// hint directing to the read_document tool.
 func ExtractDocumentContent(filePath, fileName string) (string, error) {
 	if filePath == "" {
 		return fmt.Sprintf("[File: %s — download failed]", fileName), nil
@@ -100,7 +106,10 @@ func ExtractDocumentContent(filePath, fileName string) (string, error) {
 	ext := strings.ToLower(filepath.Ext(fileName))
 	mime, isText := textExtensions[ext]
 	if !isText {
-		// Binary files (PDF, DOCX, etc.) are persisted via MediaRef and analyzed
+		if format := docextract.FormatOf(fileName, ""); format != "" {
+			return extractBinaryDocument(filePath, fileName, format), nil
+		}
+		// Other binary files are persisted via MediaRef and analyzed
 		// by the read_document tool. Return a hint instead of "not supported" placeholder.
 		return fmt.Sprintf("[File: %s — use read_document tool to analyze this file]", fileName), nil
 	}
@@ -122,3 +131,23 @@ func ExtractDocumentContent(filePath, fileName string) (string, error) {
 
 	return fmt.Sprintf("<file name=%q mime=%q>\n%s\n</file>", fileName, mime, escaped), nil
 }
+
+// extractBinaryDocument inlines the text of a PDF or Office document. Documents
+// without a text layer (scanned PDFs, image-only files) and unreadable files
+// get the read_document hint, which falls back to a vision-capable provider.
+func extractBinaryDocument(filePath, fileName string, format docextract.Format) string {
+	doc, err := docextract.ExtractFile(filePath, format, docMaxChars)
+	if err != nil {
+		if errors.Is(err, docextract.ErrNoText) {
+			return fmt.Sprintf("[File: %s — no text layer (scanned or image-only), use read_document tool to analyze this file]", fileName)
+		}
+		slog.Warn("media: document text extraction failed", "file", fileName, "format", format, "error", err)
+		return fmt.Sprintf("[File: %s — use read_document tool to analyze this file]", fileName)
+	}
+
+	content := doc.Text
+	if doc.Truncated {
+		content += "\n... [truncated]"
+	}
+	return fmt.Sprintf("<file name=%q mime=%q>\n%s\n</file>", fileName, format.MIMEType(), html.EscapeString(content))
+}
@@ -1,6 +1,13 @@
 package media
 
-import "testing"
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestBuildMediaTags_ImageWithSourceURL(t *testing.T) {
 	tags := BuildMediaTags([]MediaInfo{{
@@ -13,3 +20,38 @@ func TestBuildMediaTags_ImageWithSourceURL(t *testing.T) {
 		t.Fatalf("BuildMediaTags() = %q, want %q", tags, want)
 	}
 }
+
+func TestExtractDocumentContent_DOCXInlinesText(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("word/document.xml")
+	w.Write([]byte(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Invoice total: 42 &lt;EUR&gt;</w:t></w:r></w:p></w:body></w:document>`))
+	zw.Close()
+	path := filepath.Join(t.TempDir(), "upload.docx")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ExtractDocumentContent(path, "invoice.docx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<file name=\"invoice.docx\" mime=\"application/vnd.openxmlformats-officedocument.wordprocessingml.document\">\nInvoice total: 42 &lt;EUR&gt;\n</file>"
+	if got != want {
+		t.Fatalf("ExtractDocumentContent() = %q, want %q", got, want)
+	}
+}
+
+func TestExtractDocumentContent_UnreadablePDFHintsReadDocument(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.pdf")
+	if err := os.WriteFile(path, []byte("not really a pdf"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ExtractDocumentContent(path, "scan.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "use read_document tool") {
+		t.Fatalf("ExtractDocumentContent() = %q, want read_document hint", got)
+	}
+}
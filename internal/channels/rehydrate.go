@@ -0,0 +1,31 @@
+package channels
+
+import (
+	"github.com/google/uuid"
+)
+
+// Rehydrator restores redacted values (e.g. PII tokens) in outbound text for a
+// channel's tenant and the destination chat. Only values redacted from that
+// same chat are restored.
+type Rehydrator func(tenantID uuid.UUID, channelName, chatID, text string) string
+
+// SetRehydrator installs the outbound rehydrator. Applied to every message
+// delivered through the manager, including streaming previews, so the agent
+// and stored history only ever see the redacted form.
+func (m *Manager) SetRehydrator(fn Rehydrator) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rehydrator = fn
+}
+
+// rehydrate applies the rehydrator to text bound for chatID on channelName.
+func (m *Manager) rehydrate(channelName, chatID, text string) string {
+	m.mu.RLock()
+	fn := m.rehydrator
+	m.mu.RUnlock()
+	if fn == nil || text == "" {
+		return text
+	}
+	tenantID, _ := m.ChannelTenantID(channelName)
+	return fn(tenantID, channelName, chatID, text)
+}
@@ -0,0 +1,80 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	slackapi "github.com/slack-go/slack"
+	"github.com/slack-go/slack/socketmode"
+
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+)
+
+// approvalBlockID identifies the actions block of a tool approval prompt.
+const approvalBlockID = "tool_approval"
+
+// SendApprovalPrompt posts a tool approval prompt with Approve/Deny buttons.
+// Implements channels.ApprovalChannel. Requires Interactivity to be enabled
+// for the Slack app (delivered over Socket Mode).
+func (c *Channel) SendApprovalPrompt(_ context.Context, chatID, id, text string) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("slack bot not running")
+	}
+	text = channels.Truncate(text, 2900)
+	section := slackapi.NewSectionBlock(slackapi.NewTextBlockObject(slackapi.PlainTextType, text, false, false), nil, nil)
+	approve := slackapi.NewButtonBlockElement("approve", channels.ApprovalCallbackData(id, true),
+		slackapi.NewTextBlockObject(slackapi.PlainTextType, "Approve", false, false)).WithStyle(slackapi.StylePrimary)
+	deny := slackapi.NewButtonBlockElement("deny", channels.ApprovalCallbackData(id, false),
+		slackapi.NewTextBlockObject(slackapi.PlainTextType, "Deny", false, false)).WithStyle(slackapi.StyleDanger)
+
+	_, _, err := c.api.PostMessage(chatID,
+		slackapi.MsgOptionText(text, false),
+		slackapi.MsgOptionBlocks(section, slackapi.NewActionBlock(approvalBlockID, approve, deny)))
+	return err
+}
+
+// handleInteractive handles block action callbacks (approval buttons).
+func (c *Channel) handleInteractive(evt socketmode.Event) {
+	callback, ok := evt.Data.(slackapi.InteractionCallback)
+	if !ok {
+		return
+	}
+	c.sm.Ack(*evt.Request)
+
+	if callback.Type != slackapi.InteractionTypeBlockActions {
+		return
+	}
+	for _, action := range callback.ActionCallback.BlockActions {
+		if action.BlockID != approvalBlockID {
+			continue
+		}
+		id, approve, ok := channels.ParseApprovalCallback(action.Value)
+		if !ok {
+			continue
+		}
+		c.handleApprovalAction(callback, id, approve)
+	}
+}
+
+// handleApprovalAction decides a tool approval and replaces the buttons with the outcome.
+func (c *Channel) handleApprovalAction(callback slackapi.InteractionCallback, id string, approve bool) {
+	channelID := callback.Channel.ID
+	userID := callback.User.ID
+
+	if err := c.ResolveApproval(channelID, userID, id, approve); err != nil {
+		if _, err := c.api.PostEphemeral(channelID, userID, slackapi.MsgOptionText(err.Error(), false)); err != nil {
+			slog.Debug("slack: approval ephemeral failed", "id", id, "error", err)
+		}
+		return
+	}
+
+	text := channels.ApprovalOutcome(approve, "<@"+userID+">")
+	if callback.Message.Text != "" {
+		text = callback.Message.Text + "\n\n" + text
+	}
+	if _, _, _, err := c.api.UpdateMessage(channelID, callback.Container.MessageTs,
+		slackapi.MsgOptionText(text, false), slackapi.MsgOptionBlocks()); err != nil {
+		slog.Debug("slack: approval prompt update failed", "id", id, "error", err)
+	}
+}
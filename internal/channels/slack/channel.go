@@ -268,6 +268,8 @@ func (c *Channel) handleEvent(evt socketmode.Event) {
 	switch evt.Type {
 	case socketmode.EventTypeEventsAPI:
 		c.handleEventsAPI(evt)
+	case socketmode.EventTypeInteractive:
+		c.handleInteractive(evt)
 	case socketmode.EventTypeDisconnect:
 		slog.Info("slack socket mode disconnecting (will auto-reconnect)")
 	}
@@ -0,0 +1,70 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/mymmrac/telego"
+	tu "github.com/mymmrac/telego/telegoutil"
+
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
+)
+
+// SendApprovalPrompt sends a tool approval prompt with Approve/Deny buttons.
+// Implements channels.ApprovalChannel.
+func (c *Channel) SendApprovalPrompt(ctx context.Context, chatID, id, text string) error {
+	if !c.IsRunning() {
+		return fmt.Errorf("telegram bot not running")
+	}
+	rawID, err := parseRawChatID(chatID)
+	if err != nil {
+		return fmt.Errorf("invalid chat ID: %w", err)
+	}
+
+	msg := tu.Message(tu.ID(rawID), truncateStr(text, telegramMaxMessageLen))
+	if idx := strings.Index(chatID, ":topic:"); idx > 0 {
+		var threadID int
+		fmt.Sscanf(chatID[idx+7:], "%d", &threadID)
+		msg.MessageThreadID = resolveThreadIDForSend(threadID)
+	}
+	msg.ReplyMarkup = &telego.InlineKeyboardMarkup{InlineKeyboard: [][]telego.InlineKeyboardButton{{
+		{Text: "✅ Approve", CallbackData: channels.ApprovalCallbackData(id, true)},
+		{Text: "❌ Deny", CallbackData: channels.ApprovalCallbackData(id, false)},
+	}}}
+	_, err = c.bot.SendMessage(ctx, msg)
+	return err
+}
+
+// handleApprovalCallback decides a tool approval from an "ap:" button press
+// and replaces the buttons with the outcome.
+func (c *Channel) handleApprovalCallback(ctx context.Context, query *telego.CallbackQuery, id string, approve bool) {
+	chat := query.Message.GetChat()
+	senderID := fmt.Sprintf("%d", query.From.ID)
+
+	answer := &telego.AnswerCallbackQueryParams{CallbackQueryID: query.ID}
+	if err := c.ResolveApproval(fmt.Sprintf("%d", chat.ID), senderID, id, approve); err != nil {
+		answer.Text = err.Error()
+		answer.ShowAlert = true
+		c.bot.AnswerCallbackQuery(ctx, answer)
+		return
+	}
+	c.bot.AnswerCallbackQuery(ctx, answer)
+
+	by := senderID
+	if query.From.Username != "" {
+		by = "@" + query.From.Username
+	}
+	text := channels.ApprovalOutcome(approve, by)
+	if m := query.Message.Message(); m != nil && m.Text != "" {
+		text = truncateStr(m.Text, telegramMaxMessageLen-100) + "\n\n" + text
+	}
+	if _, err := c.bot.EditMessageText(ctx, &telego.EditMessageTextParams{
+		ChatID:    tu.ID(chat.ID),
+		MessageID: query.Message.GetMessageID(),
+		Text:      text,
+	}); err != nil {
+		slog.Debug("telegram: approval prompt edit failed", "id", id, "error", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/mymmrac/telego"
 	tu "github.com/mymmrac/telego/telegoutil"
 
+	"github.com/nextlevelbuilder/goclaw/internal/channels"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
@@ -199,6 +200,12 @@ func (c *Channel) handleCallbackQuery(ctx context.Context, query *telego.Callbac
 	// Inject tenant scope (callback queries bypass handleBotCommand).
 	ctx = store.WithTenantID(ctx, c.TenantID())
 
+	// Approval buttons answer the query themselves (with an error alert when rejected).
+	if id, approve, ok := channels.ParseApprovalCallback(query.Data); ok {
+		c.handleApprovalCallback(ctx, query, id, approve)
+		return
+	}
+
 	c.bot.AnswerCallbackQuery(ctx, &telego.AnswerCallbackQueryParams{
 		CallbackQueryID: query.ID,
 	})
@@ -0,0 +1,237 @@
+// Package cluster coordinates multiple gateway replicas running behind a load
+// balancer: named locks give exactly-one ownership of singletons (cron,
+// tickers, channel pollers) and serialize session runs across nodes, and a
+// pub/sub channel carries cross-node messages (chat.abort, WebSocket events,
+// cache invalidation).
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cluster backends (selected via GOCLAW_CLUSTER_BACKEND).
+const (
+	BackendPostgres = "postgres"
+	BackendRedis    = "redis"
+)
+
+const (
+	// DefaultLockTTL is how long a lock survives without renewal, i.e. how
+	// quickly a crashed owner's singletons fail over.
+	DefaultLockTTL = 30 * time.Second
+
+	defaultRetryInterval = 5 * time.Second
+	sessionPollInterval  = 250 * time.Millisecond
+	resubscribeDelay     = 2 * time.Second
+)
+
+// ErrSessionLockLost is the cancellation cause of a session run whose lock
+// was lost while it ran (another node may now run the session).
+var ErrSessionLockLost = errors.New("session lock lost")
+
+// Lock is a held distributed lock.
+type Lock interface {
+	// Done is closed when the lock is lost (renewal failed or expired).
+	Done() <-chan struct{}
+	// Release gives the lock up.
+	Release(ctx context.Context) error
+}
+
+// Locker hands out named locks shared by every replica.
+type Locker interface {
+	// TryLock acquires key without waiting. Returns ok=false if another
+	// holder owns it.
+	TryLock(ctx context.Context, key string) (lock Lock, ok bool, err error)
+}
+
+// PubSub broadcasts payloads to every replica (including the sender).
+type PubSub interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+	// Subscribe delivers messages for topic to fn until ctx is cancelled or
+	// the subscription fails.
+	Subscribe(ctx context.Context, topic string, fn func(payload []byte)) error
+}
+
+// Coordinator is this node's handle on the cluster.
+type Coordinator struct {
+	nodeID string
+	locker Locker
+	pubsub PubSub
+	retry  time.Duration
+}
+
+// NewCoordinator creates a coordinator. pubsub may be nil when cross-node
+// messaging is not needed.
+func NewCoordinator(nodeID string, locker Locker, pubsub PubSub) *Coordinator {
+	if nodeID == "" {
+		nodeID = DefaultNodeID()
+	}
+	return &Coordinator{nodeID: nodeID, locker: locker, pubsub: pubsub, retry: defaultRetryInterval}
+}
+
+// DefaultNodeID returns a node ID unique to this process.
+func DefaultNodeID() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8])
+}
+
+// NodeID returns this node's ID.
+func (c *Coordinator) NodeID() string { return c.nodeID }
+
+// RunExclusive keeps trying to acquire the named lock in the background and
+// calls start while this node holds it. If the lock is lost, stop is called
+// and the node goes back to contending. The returned function ends the loop:
+// it calls stop if start had succeeded, releases the lock and waits.
+func (c *Coordinator) RunExclusive(ctx context.Context, name string, start func() error, stop func()) (cancel func()) {
+	ctx, cancelCtx := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		key := "singleton:" + name
+		for {
+			lock, ok, err := c.locker.TryLock(ctx, key)
+			if err != nil && ctx.Err() == nil {
+				slog.Warn("cluster: lock attempt failed", "name", name, "error", err)
+			}
+			if ok {
+				c.holdExclusive(ctx, name, lock, start, stop)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(c.retry):
+			}
+		}
+	}()
+	return func() {
+		cancelCtx()
+		wg.Wait()
+	}
+}
+
+// holdExclusive runs one leadership term for name.
+func (c *Coordinator) holdExclusive(ctx context.Context, name string, lock Lock, start func() error, stop func()) {
+	defer func() {
+		rctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := lock.Release(rctx); err != nil {
+			slog.Warn("cluster: lock release failed", "name", name, "error", err)
+		}
+	}()
+
+	slog.Info("cluster: acquired ownership", "name", name, "node", c.nodeID)
+	if err := start(); err != nil {
+		slog.Warn("cluster: owned service failed to start", "name", name, "error", err)
+		return
+	}
+	select {
+	case <-lock.Done():
+		slog.Warn("cluster: lost ownership", "name", name, "node", c.nodeID)
+	case <-ctx.Done():
+	}
+	stop()
+}
+
+// LockSession blocks until this node holds the run lock for sessionKey, so
+// a session runs on at most one node at a time. The run must use runCtx: it
+// is cancelled with ErrSessionLockLost if the lock is lost before unlock is
+// called. Call unlock when the run ends.
+func (c *Coordinator) LockSession(ctx context.Context, sessionKey string) (runCtx context.Context, unlock func(), err error) {
+	key := "session:" + sessionKey
+	for {
+		lock, ok, err := c.locker.TryLock(ctx, key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("session lock: %w", err)
+		}
+		if ok {
+			runCtx, cancelRun := context.WithCancelCause(ctx)
+			go func() {
+				select {
+				case <-lock.Done():
+					slog.Warn("cluster: session lock lost, cancelling run", "session", sessionKey, "node", c.nodeID)
+					cancelRun(ErrSessionLockLost)
+				case <-runCtx.Done():
+				}
+			}()
+			return runCtx, func() {
+				cancelRun(nil)
+				rctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := lock.Release(rctx); err != nil {
+					slog.Warn("cluster: session unlock failed", "session", sessionKey, "error", err)
+				}
+			}, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(sessionPollInterval):
+		}
+	}
+}
+
+// envelope wraps published data with the sending node so receivers can skip
+// their own messages.
+type envelope struct {
+	Origin string          `json:"origin"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Publish sends v (JSON-encoded) to every other node subscribed to topic.
+// No-op without a pub/sub backend.
+func (c *Coordinator) Publish(ctx context.Context, topic string, v any) error {
+	if c.pubsub == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(envelope{Origin: c.nodeID, Data: data})
+	if err != nil {
+		return err
+	}
+	return c.pubsub.Publish(ctx, topic, payload)
+}
+
+// Subscribe calls fn for every message other nodes publish on topic until ctx
+// is cancelled, resubscribing after backend errors. Runs in the background.
+func (c *Coordinator) Subscribe(ctx context.Context, topic string, fn func(origin string, data json.RawMessage)) {
+	if c.pubsub == nil {
+		return
+	}
+	go func() {
+		for {
+			err := c.pubsub.Subscribe(ctx, topic, func(payload []byte) {
+				var env envelope
+				if err := json.Unmarshal(payload, &env); err != nil {
+					slog.Warn("cluster: bad message", "topic", topic, "error", err)
+					return
+				}
+				if env.Origin == c.nodeID {
+					return
+				}
+				fn(env.Origin, env.Data)
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			slog.Warn("cluster: subscription dropped, retrying", "topic", topic, "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(resubscribeDelay):
+			}
+		}
+	}()
+}
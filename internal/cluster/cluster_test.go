@@ -0,0 +1,175 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestCoordinator(id string, locker Locker, ps PubSub) *Coordinator {
+	c := NewCoordinator(id, locker, ps)
+	c.retry = 10 * time.Millisecond
+	return c
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRunExclusive_OneOwnerAndFailover(t *testing.T) {
+	locker := NewLocalLocker()
+	var running [2]atomic.Bool
+	var cancels [2]func()
+	for i := range 2 {
+		c := newTestCoordinator([]string{"a", "b"}[i], locker, nil)
+		cancels[i] = c.RunExclusive(context.Background(), "cron",
+			func() error { running[i].Store(true); return nil },
+			func() { running[i].Store(false) })
+	}
+
+	waitFor(t, "an owner", func() bool { return running[0].Load() || running[1].Load() })
+	time.Sleep(50 * time.Millisecond)
+	if running[0].Load() && running[1].Load() {
+		t.Fatal("both nodes own the singleton")
+	}
+
+	owner, other := 0, 1
+	if running[1].Load() {
+		owner, other = 1, 0
+	}
+	cancels[owner]()
+	if running[owner].Load() {
+		t.Fatal("stop was not called on shutdown")
+	}
+	waitFor(t, "failover", func() bool { return running[other].Load() })
+
+	// Losing the lock (e.g. expired lease) stops the service.
+	locker.Expire("singleton:cron")
+	waitFor(t, "stop after lock loss", func() bool { return !running[other].Load() })
+	// ...and the node contends again and takes it back.
+	waitFor(t, "reacquire", func() bool { return running[other].Load() })
+	cancels[other]()
+}
+
+func TestLockSession_Serializes(t *testing.T) {
+	locker := NewLocalLocker()
+	a := newTestCoordinator("a", locker, nil)
+	b := newTestCoordinator("b", locker, nil)
+
+	runCtx, unlock, err := a.LockSession(context.Background(), "agent:x:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, _, err := b.LockSession(ctx, "agent:x:1"); err == nil {
+		t.Fatal("second node acquired a held session lock")
+	}
+
+	unlock()
+	if runCtx.Err() == nil {
+		t.Error("run context still live after unlock")
+	}
+	_, unlockB, err := b.LockSession(context.Background(), "agent:x:1")
+	if err != nil {
+		t.Fatalf("lock after release: %v", err)
+	}
+	unlockB()
+}
+
+func TestLockSession_LostLockCancelsRun(t *testing.T) {
+	locker := NewLocalLocker()
+	c := newTestCoordinator("a", locker, nil)
+
+	runCtx, unlock, err := c.LockSession(context.Background(), "agent:x:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+	locker.Expire("session:agent:x:1")
+
+	select {
+	case <-runCtx.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("run context not cancelled after the lock was lost")
+	}
+	if cause := context.Cause(runCtx); !errors.Is(cause, ErrSessionLockLost) {
+		t.Errorf("cause = %v, want ErrSessionLockLost", cause)
+	}
+}
+
+// memPubSub delivers to every subscriber in-process.
+type memPubSub struct {
+	mu   sync.Mutex
+	subs map[string][]func([]byte)
+}
+
+func (p *memPubSub) Publish(_ context.Context, topic string, payload []byte) error {
+	p.mu.Lock()
+	subs := append([]func([]byte){}, p.subs[topic]...)
+	p.mu.Unlock()
+	for _, fn := range subs {
+		fn(payload)
+	}
+	return nil
+}
+
+func (p *memPubSub) Subscribe(ctx context.Context, topic string, fn func([]byte)) error {
+	p.mu.Lock()
+	if p.subs == nil {
+		p.subs = map[string][]func([]byte){}
+	}
+	p.subs[topic] = append(p.subs[topic], fn)
+	p.mu.Unlock()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestPublishSubscribe_SkipsOwnMessages(t *testing.T) {
+	ps := &memPubSub{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := newTestCoordinator("a", NewLocalLocker(), ps)
+	b := newTestCoordinator("b", NewLocalLocker(), ps)
+
+	got := make(chan string, 4)
+	for _, c := range []*Coordinator{a, b} {
+		c.Subscribe(ctx, "abort", func(origin string, data json.RawMessage) {
+			got <- c.NodeID() + "<-" + origin + ":" + string(data)
+		})
+	}
+	waitFor(t, "subscriptions", func() bool {
+		ps.mu.Lock()
+		defer ps.mu.Unlock()
+		return len(ps.subs["abort"]) == 2
+	})
+
+	if err := a.Publish(ctx, "abort", "run-1"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case msg := <-got:
+		if msg != `b<-a:"run-1"` {
+			t.Fatalf("got %q", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not delivered")
+	}
+	select {
+	case msg := <-got:
+		t.Fatalf("unexpected extra delivery %q", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,69 @@
+package cluster
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// lease is a TTL-based Lock kept alive by periodic renewal. Transient renewal
+// errors are tolerated until the TTL has passed since the last success.
+type lease struct {
+	key     string
+	renew   func(ctx context.Context) (bool, error)
+	release func(ctx context.Context) error
+
+	done     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newLease(key string, ttl time.Duration, renew func(context.Context) (bool, error), release func(context.Context) error) *lease {
+	l := &lease{
+		key:     key,
+		renew:   renew,
+		release: release,
+		done:    make(chan struct{}),
+		stop:    make(chan struct{}),
+	}
+	go l.keepAlive(ttl)
+	return l
+}
+
+func (l *lease) keepAlive(ttl time.Duration) {
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+	lastRenew := time.Now()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), ttl/3)
+		ok, err := l.renew(ctx)
+		cancel()
+		switch {
+		case err == nil && ok:
+			lastRenew = time.Now()
+			continue
+		case err == nil:
+			slog.Warn("cluster: lock taken over", "key", l.key)
+		case time.Since(lastRenew) < ttl:
+			slog.Warn("cluster: lock renewal failed", "key", l.key, "error", err)
+			continue
+		default:
+			slog.Warn("cluster: lock expired after renewal failures", "key", l.key, "error", err)
+		}
+		close(l.done)
+		return
+	}
+}
+
+func (l *lease) Done() <-chan struct{} { return l.done }
+
+func (l *lease) Release(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	return l.release(ctx)
+}
@@ -0,0 +1,54 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+)
+
+// LocalLocker is an in-process Locker for single-node deployments and tests.
+type LocalLocker struct {
+	mu   sync.Mutex
+	held map[string]*localLock
+}
+
+func NewLocalLocker() *LocalLocker {
+	return &LocalLocker{held: make(map[string]*localLock)}
+}
+
+func (l *LocalLocker) TryLock(_ context.Context, key string) (Lock, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.held[key]; ok {
+		return nil, false, nil
+	}
+	lk := &localLock{owner: l, key: key, done: make(chan struct{})}
+	l.held[key] = lk
+	return lk, true, nil
+}
+
+// Expire simulates losing key (e.g. a partitioned node) for tests.
+func (l *LocalLocker) Expire(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if lk, ok := l.held[key]; ok {
+		delete(l.held, key)
+		close(lk.done)
+	}
+}
+
+type localLock struct {
+	owner *LocalLocker
+	key   string
+	done  chan struct{}
+}
+
+func (lk *localLock) Done() <-chan struct{} { return lk.done }
+
+func (lk *localLock) Release(context.Context) error {
+	lk.owner.mu.Lock()
+	defer lk.owner.mu.Unlock()
+	if cur, ok := lk.owner.held[lk.key]; ok && cur == lk {
+		delete(lk.owner.held, lk.key)
+	}
+	return nil
+}
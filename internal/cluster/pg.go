@@ -0,0 +1,228 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// maxNotifyPayload is Postgres' NOTIFY payload limit (8000 bytes, minus slack).
+const maxNotifyPayload = 7900
+
+// Larger payloads are base64-encoded and split into chunks of the form
+// "#chunk <id> <index> <count> <data>", sent in one transaction and
+// reassembled by Subscribe. Plain payloads are JSON, so never start with '#'.
+const (
+	notifyChunkPrefix = "#chunk "
+	notifyChunkData   = maxNotifyPayload - 64 // room for the chunk header
+	maxChunkedPayload = 32 << 20
+	chunkAssemblyTTL  = time.Minute
+)
+
+// PGLocker implements Locker with leases in the cluster_locks table.
+// Leases are used instead of session advisory locks so that holding many
+// session run locks does not pin one pooled connection each.
+type PGLocker struct {
+	db     *sql.DB
+	nodeID string
+	ttl    time.Duration
+}
+
+func NewPGLocker(db *sql.DB, nodeID string, ttl time.Duration) *PGLocker {
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+	return &PGLocker{db: db, nodeID: nodeID, ttl: ttl}
+}
+
+func (l *PGLocker) TryLock(ctx context.Context, key string) (Lock, bool, error) {
+	owner := l.nodeID + "/" + uuid.NewString()
+	var got string
+	err := l.db.QueryRowContext(ctx, `
+		INSERT INTO cluster_locks (lock_key, owner, expires_at)
+		VALUES ($1, $2, NOW() + make_interval(secs => $3))
+		ON CONFLICT (lock_key) DO UPDATE
+		SET owner = EXCLUDED.owner, expires_at = EXCLUDED.expires_at
+		WHERE cluster_locks.expires_at < NOW()
+		RETURNING owner`,
+		key, owner, l.ttl.Seconds(),
+	).Scan(&got)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	renew := func(ctx context.Context) (bool, error) {
+		res, err := l.db.ExecContext(ctx,
+			`UPDATE cluster_locks SET expires_at = NOW() + make_interval(secs => $3)
+			 WHERE lock_key = $1 AND owner = $2`,
+			key, owner, l.ttl.Seconds())
+		if err != nil {
+			return false, err
+		}
+		n, _ := res.RowsAffected()
+		return n > 0, nil
+	}
+	release := func(ctx context.Context) error {
+		_, err := l.db.ExecContext(ctx,
+			`DELETE FROM cluster_locks WHERE lock_key = $1 AND owner = $2`, key, owner)
+		return err
+	}
+	return newLease(key, l.ttl, renew, release), true, nil
+}
+
+// PGPubSub implements PubSub with LISTEN/NOTIFY. Payloads over the NOTIFY
+// size limit are sent as chunks and reassembled by subscribers.
+type PGPubSub struct {
+	db *sql.DB
+}
+
+func NewPGPubSub(db *sql.DB) *PGPubSub {
+	return &PGPubSub{db: db}
+}
+
+// pgChannel maps a topic to a NOTIFY channel name.
+func pgChannel(topic string) string {
+	return "goclaw_" + strings.NewReplacer(".", "_", ":", "_", "-", "_").Replace(topic)
+}
+
+func (p *PGPubSub) Publish(ctx context.Context, topic string, payload []byte) error {
+	if len(payload) <= maxNotifyPayload {
+		_, err := p.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, pgChannel(topic), string(payload))
+		return err
+	}
+	chunks, err := splitNotifyPayload(payload)
+	if err != nil {
+		return err
+	}
+	// One transaction: listeners get all chunks together, in order, or none.
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, chunk := range chunks {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, pgChannel(topic), chunk); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// splitNotifyPayload encodes an oversized payload as NOTIFY-sized chunks.
+func splitNotifyPayload(payload []byte) ([]string, error) {
+	if len(payload) > maxChunkedPayload {
+		return nil, fmt.Errorf("payload of %d bytes exceeds the %d byte relay limit", len(payload), maxChunkedPayload)
+	}
+	enc := base64.StdEncoding.EncodeToString(payload)
+	id := uuid.NewString()
+	count := (len(enc) + notifyChunkData - 1) / notifyChunkData
+	chunks := make([]string, 0, count)
+	for i := range count {
+		end := min((i+1)*notifyChunkData, len(enc))
+		chunks = append(chunks, fmt.Sprintf("%s%s %d %d %s", notifyChunkPrefix, id, i, count, enc[i*notifyChunkData:end]))
+	}
+	return chunks, nil
+}
+
+// chunkAssembler reassembles chunked payloads for one subscription.
+type chunkAssembler struct {
+	partial map[string]*partialPayload
+}
+
+type partialPayload struct {
+	parts    []string
+	received int
+	started  time.Time
+}
+
+func newChunkAssembler() *chunkAssembler {
+	return &chunkAssembler{partial: make(map[string]*partialPayload)}
+}
+
+// add records one chunk and returns the full payload once every chunk of it
+// has arrived. Malformed chunks and chunks of abandoned payloads are dropped.
+func (a *chunkAssembler) add(chunk string) ([]byte, bool) {
+	now := time.Now()
+	for id, p := range a.partial {
+		if now.Sub(p.started) > chunkAssemblyTTL {
+			slog.Warn("cluster: dropping incomplete chunked message", "id", id, "received", p.received, "chunks", len(p.parts))
+			delete(a.partial, id)
+		}
+	}
+
+	fields := strings.SplitN(strings.TrimPrefix(chunk, notifyChunkPrefix), " ", 4)
+	if len(fields) != 4 {
+		return nil, false
+	}
+	index, err1 := strconv.Atoi(fields[1])
+	count, err2 := strconv.Atoi(fields[2])
+	if err1 != nil || err2 != nil || count <= 0 || count > maxChunkedPayload/notifyChunkData+1 || index < 0 || index >= count {
+		return nil, false
+	}
+	p, ok := a.partial[fields[0]]
+	if !ok {
+		p = &partialPayload{parts: make([]string, count), started: now}
+		a.partial[fields[0]] = p
+	}
+	if len(p.parts) != count || p.parts[index] != "" {
+		return nil, false
+	}
+	p.parts[index] = fields[3]
+	p.received++
+	if p.received < count {
+		return nil, false
+	}
+	delete(a.partial, fields[0])
+	payload, err := base64.StdEncoding.DecodeString(strings.Join(p.parts, ""))
+	if err != nil {
+		slog.Warn("cluster: dropping undecodable chunked message", "id", fields[0], "error", err)
+		return nil, false
+	}
+	return payload, true
+}
+
+func (p *PGPubSub) Subscribe(ctx context.Context, topic string, fn func(payload []byte)) error {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		sc, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("LISTEN requires the pgx driver, got %T", driverConn)
+		}
+		pc := sc.Conn()
+		if _, err := pc.Exec(ctx, "LISTEN "+pgx.Identifier{pgChannel(topic)}.Sanitize()); err != nil {
+			return err
+		}
+		chunks := newChunkAssembler()
+		for {
+			n, err := pc.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(n.Payload, notifyChunkPrefix) {
+				if payload, ok := chunks.add(n.Payload); ok {
+					fn(payload)
+				}
+				continue
+			}
+			fn([]byte(n.Payload))
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func newTestPGClusterDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("GOCLAW_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("GOCLAW_POSTGRES_DSN is not set")
+	}
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS cluster_locks (
+		lock_key VARCHAR(512) PRIMARY KEY,
+		owner VARCHAR(255) NOT NULL,
+		expires_at TIMESTAMPTZ NOT NULL
+	)`); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	_, _ = db.Exec(`DELETE FROM cluster_locks WHERE lock_key LIKE 'test:%'`)
+	return db
+}
+
+func TestPGLocker_LeaseExclusiveAndExpiry(t *testing.T) {
+	db := newTestPGClusterDB(t)
+	ctx := context.Background()
+	a := NewPGLocker(db, "a", time.Second)
+	b := NewPGLocker(db, "b", time.Second)
+
+	lock, ok, err := a.TryLock(ctx, "test:leader")
+	if err != nil || !ok {
+		t.Fatalf("TryLock a = %v, %v", ok, err)
+	}
+	if _, ok, err := b.TryLock(ctx, "test:leader"); err != nil || ok {
+		t.Fatalf("TryLock b on held lock = %v, %v", ok, err)
+	}
+	// Renewal keeps it held past the TTL.
+	time.Sleep(1500 * time.Millisecond)
+	if _, ok, _ := b.TryLock(ctx, "test:leader"); ok {
+		t.Fatal("renewed lease was taken over")
+	}
+	if err := lock.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	lockB, ok, err := b.TryLock(ctx, "test:leader")
+	if err != nil || !ok {
+		t.Fatalf("TryLock after release = %v, %v", ok, err)
+	}
+	_ = lockB.Release(ctx)
+}
+
+func TestNotifyChunksRoundTrip(t *testing.T) {
+	payload := []byte(`{"origin":"a","data":"` + strings.Repeat("héllo ", 5000) + `"}`)
+	chunks, err := splitNotifyPayload(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks, want several", len(chunks))
+	}
+	for _, c := range chunks {
+		if len(c) > maxNotifyPayload {
+			t.Fatalf("chunk of %d bytes exceeds the NOTIFY limit", len(c))
+		}
+	}
+
+	// Chunks of another payload interleaved with ours are kept apart.
+	other, _ := splitNotifyPayload([]byte(strings.Repeat("x", maxNotifyPayload*2)))
+	a := newChunkAssembler()
+	for i, c := range chunks {
+		got, done := a.add(c)
+		if i < len(chunks)-1 {
+			if done {
+				t.Fatalf("assembled early at chunk %d", i)
+			}
+			if i < len(other) {
+				a.add(other[i])
+			}
+			continue
+		}
+		if !done || string(got) != string(payload) {
+			t.Fatalf("reassembled %d bytes, want %d", len(got), len(payload))
+		}
+	}
+
+	if _, err := splitNotifyPayload(make([]byte, maxChunkedPayload+1)); err == nil {
+		t.Fatal("expected an error for a payload over the relay limit")
+	}
+}
@@ -0,0 +1,96 @@
+//go:build redis
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const redisLockPrefix = "goclaw:lock:"
+
+// Compare-and-act scripts so a node only renews or deletes its own lock.
+var (
+	redisRenewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	redisReleaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// RedisLocker implements Locker with SET NX PX leases.
+type RedisLocker struct {
+	client *redis.Client
+	nodeID string
+	ttl    time.Duration
+}
+
+func NewRedisLocker(client *redis.Client, nodeID string, ttl time.Duration) *RedisLocker {
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+	return &RedisLocker{client: client, nodeID: nodeID, ttl: ttl}
+}
+
+func (l *RedisLocker) TryLock(ctx context.Context, key string) (Lock, bool, error) {
+	rkey := redisLockPrefix + key
+	owner := l.nodeID + "/" + uuid.NewString()
+	err := l.client.SetArgs(ctx, rkey, owner, redis.SetArgs{Mode: "NX", TTL: l.ttl}).Err()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	renew := func(ctx context.Context) (bool, error) {
+		n, err := redisRenewScript.Run(ctx, l.client, []string{rkey}, owner, l.ttl.Milliseconds()).Int()
+		return n == 1, err
+	}
+	release := func(ctx context.Context) error {
+		return redisReleaseScript.Run(ctx, l.client, []string{rkey}, owner).Err()
+	}
+	return newLease(key, l.ttl, renew, release), true, nil
+}
+
+// RedisPubSub implements PubSub with Redis PUBLISH/SUBSCRIBE.
+type RedisPubSub struct {
+	client *redis.Client
+}
+
+func NewRedisPubSub(client *redis.Client) *RedisPubSub {
+	return &RedisPubSub{client: client}
+}
+
+func (p *RedisPubSub) Publish(ctx context.Context, topic string, payload []byte) error {
+	return p.client.Publish(ctx, "goclaw:cluster:"+topic, payload).Err()
+}
+
+func (p *RedisPubSub) Subscribe(ctx context.Context, topic string, fn func(payload []byte)) error {
+	sub := p.client.Subscribe(ctx, "goclaw:cluster:"+topic)
+	defer sub.Close()
+	if _, err := sub.Receive(ctx); err != nil {
+		return err
+	}
+	ch := sub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return errors.New("subscription closed")
+			}
+			fn([]byte(msg.Payload))
+		}
+	}
+}
@@ -73,6 +73,8 @@ type DatabaseConfig struct {
 	RedisDSN       string `json:"-"` // from env GOCLAW_REDIS_DSN only (optional, requires -tags redis)
 	StorageBackend string `json:"-"` // from env GOCLAW_STORAGE_BACKEND only ("postgres" or "sqlite", default "postgres")
 	SQLitePath     string `json:"-"` // from env GOCLAW_SQLITE_PATH only (default: {dataDir}/goclaw.db)
+	BusBackend     string `json:"-"` // from env GOCLAW_BUS_BACKEND only ("memory" (default), "redis" or "postgres")
+	ClusterBackend string `json:"-"` // from env GOCLAW_CLUSTER_BACKEND only ("" = single node, "redis" or "postgres")
 }
 
 // SkillsConfig configures the skills storage system.
@@ -357,6 +357,28 @@ type GatewayConfig struct {
 	BlockReply              *bool        `json:"block_reply,omitempty"`                // deliver intermediate text during tool iterations (default false)
 	ToolStatus              *bool        `json:"tool_status,omitempty"`                // show tool name in streaming preview during tool execution (default true)
 	TaskRecoveryIntervalSec int          `json:"task_recovery_interval_sec,omitempty"` // team task recovery ticker interval in seconds (default 300 = 5min)
+	PII                     PIIConfig    `json:"pii"`                                  // personal data redaction for messages, history and traces
+}
+
+// PIIConfig controls detection and redaction of personal data in inbound
+// messages (before the provider and session history see them), tool results
+// and trace previews. In "tokenize" mode, tokens in agent replies are
+// re-hydrated to the original values when delivered to the channel.
+type PIIConfig struct {
+	Enabled       bool         `json:"enabled"`
+	Mode          string       `json:"mode,omitempty"`            // "tokenize" (default), "mask", "hash"
+	Detectors     []string     `json:"detectors,omitempty"`       // built-in detectors (default: all)
+	Patterns      []PIIPattern `json:"patterns,omitempty"`        // custom detectors (e.g. country-specific national IDs)
+	ToolResults   bool         `json:"tool_results,omitempty"`    // also redact tool results before the LLM sees them
+	Traces        *bool        `json:"traces,omitempty"`          // redact trace input/output previews (default true)
+	TokenTTLHours int          `json:"token_ttl_hours,omitempty"` // how long tokens stay re-hydratable (default 24)
+	Secret        string       `json:"-"`                         // from env GOCLAW_PII_SECRET only: HMAC key for hash/tokenize
+}
+
+// PIIPattern is a custom PII detector.
+type PIIPattern struct {
+	Name    string `json:"name"`    // lower_snake_case, used in tokens (e.g. "vn_citizen_id" → [VN_CITIZEN_ID_1a2b3c4d])
+	Pattern string `json:"pattern"` // regular expression
 }
 
 // ToolsConfig controls tool availability, policy, and web search.
@@ -367,6 +389,8 @@ type ToolsConfig struct {
 	AlsoAllow        []string                    `json:"alsoAllow,omitempty"`  // additive: adds without removing existing
 	ByProvider       map[string]*ToolPolicySpec  `json:"byProvider,omitempty"` // per-provider overrides
 	ExecApproval     ExecApprovalCfg             `json:"execApproval"`         // exec command approval settings
+	Approvals        ToolApprovalCfg             `json:"approvals"`            // human approval for arbitrary tool calls
+	Guardrails       []ToolGuardrailRule         `json:"guardrails,omitempty"` // content-aware argument/result rules
 	WebFetch         WebFetchPolicyConfig        `json:"web_fetch"`            // domain policy for URL fetching
 	Web              WebToolsConfig              `json:"web"`
 	Browser          BrowserToolConfig           `json:"browser"`
@@ -400,6 +424,54 @@ type ExecApprovalCfg struct {
 	Allowlist []string `json:"allowlist,omitempty"` // glob patterns for allowed commands
 }
 
+// ToolApprovalCfg configures human-in-the-loop approval for arbitrary tool calls
+// (built-in and MCP). A call matching any rule blocks until an approver decides
+// or the timeout passes, in which case it is denied.
+type ToolApprovalCfg struct {
+	TimeoutSec int                `json:"timeout_sec,omitempty"` // wait before default-deny (default 300)
+	Approvers  []ToolApprover     `json:"approvers,omitempty"`   // default prompt destinations (empty = dashboard only)
+	Rules      []ToolApprovalRule `json:"rules,omitempty"`
+}
+
+// ToolApprovalRule selects tool calls that need approval. All set conditions must hold.
+type ToolApprovalRule struct {
+	Tools       []string          `json:"tools"`                  // tool names, globs ("mcp_*") or "group:xxx"
+	Agents      []string          `json:"agents,omitempty"`       // agent keys (empty = all agents)
+	Args        map[string]string `json:"args,omitempty"`         // argument globs, "!" prefix negates (e.g. {"action": "add"})
+	PathOutside []string          `json:"path_outside,omitempty"` // only when the "path" argument resolves outside these dirs ("." = workspace)
+	WriteOnly   bool              `json:"write_only,omitempty"`   // skip tools that declare themselves read-only (MCP readOnlyHint)
+	Approvers   []ToolApprover    `json:"approvers,omitempty"`    // overrides the default destinations
+	TimeoutSec  int               `json:"timeout_sec,omitempty"`  // overrides the default timeout
+}
+
+// ToolApprover is a chat that receives approval prompts with approve/deny buttons.
+type ToolApprover struct {
+	Channel string   `json:"channel"`            // channel instance name
+	ChatID  string   `json:"chat_id"`            // chat/channel ID on that platform
+	UserIDs []string `json:"user_ids,omitempty"` // platform user IDs allowed to decide (empty = anyone in the chat)
+}
+
+// ToolGuardrailRule is a content-aware rule evaluated around tool execution.
+// Call-stage rules inspect arguments before the tool runs (deny or rewrite);
+// result-stage rules inspect the output before it reaches the LLM (redact or deny).
+// A rule triggers when its selectors hold and any content condition matches
+// (a rule with no content condition triggers on the selectors alone).
+type ToolGuardrailRule struct {
+	Name        string            `json:"name"`                  // identifies the rule in audit events
+	Tools       []string          `json:"tools"`                 // tool names, globs ("mcp_*") or "group:xxx"
+	Agents      []string          `json:"agents,omitempty"`      // agent keys (empty = all agents)
+	Tenants     []string          `json:"tenants,omitempty"`     // tenant IDs (empty = all tenants)
+	Stage       string            `json:"stage,omitempty"`       // "call" (default) or "result"
+	Action      string            `json:"action,omitempty"`      // call: "deny" (default), "rewrite"; result: "redact" (default), "deny"
+	Args        map[string]string `json:"args,omitempty"`        // argument globs, "!" prefix negates (e.g. {"path": "*.env"})
+	Fields      []string          `json:"fields,omitempty"`      // arguments scanned by pattern/detect/cidrs (default: all string arguments)
+	Pattern     string            `json:"pattern,omitempty"`     // regular expression matched against fields or the result
+	Detect      []string          `json:"detect,omitempty"`      // PII detectors: "email", "phone", "credit_card", "iban", "national_id", "address"
+	CIDRs       []string          `json:"cidrs,omitempty"`       // call stage: URL/host arguments resolving into these networks
+	Replacement string            `json:"replacement,omitempty"` // rewrite/redact replacement (default "[REDACTED]")
+	Message     string            `json:"message,omitempty"`     // error shown to the model on deny
+}
+
 // WebFetchPolicyConfig controls domain filtering for the web_fetch tool.
 type WebFetchPolicyConfig struct {
 	Policy         string   `json:"policy,omitempty"`          // "allow_all" (default), "allowlist"
@@ -425,6 +497,7 @@ type ToolPolicySpec struct {
 	AlsoAllow  []string                   `json:"alsoAllow,omitempty"`
 	ByProvider map[string]*ToolPolicySpec `json:"byProvider,omitempty"`
 	ToolCallPrefix string `json:"toolCallPrefix,omitempty"` // prefix to strip from model's tool call names before registry lookup
+	Guardrails     []ToolGuardrailRule `json:"guardrails,omitempty"` // per-agent guardrails, evaluated after the global ones
 }
 
 type WebToolsConfig struct {
@@ -175,6 +175,7 @@ func (c *Config) applyEnvOverrides() {
 
 	// Gateway host/port
 	envStr("GOCLAW_HOST", &c.Gateway.Host)
+	envStr("GOCLAW_PII_SECRET", &c.Gateway.PII.Secret)
 	if v := os.Getenv("GOCLAW_PORT"); v != "" {
 		if port, err := strconv.Atoi(v); err == nil && port > 0 {
 			c.Gateway.Port = port
@@ -186,6 +187,8 @@ func (c *Config) applyEnvOverrides() {
 	envStr("GOCLAW_REDIS_DSN", &c.Database.RedisDSN)
 	envStr("GOCLAW_STORAGE_BACKEND", &c.Database.StorageBackend)
 	envStr("GOCLAW_SQLITE_PATH", &c.Database.SQLitePath)
+	envStr("GOCLAW_BUS_BACKEND", &c.Database.BusBackend)
+	envStr("GOCLAW_CLUSTER_BACKEND", &c.Database.ClusterBackend)
 
 	// Deprecation warning for GOCLAW_MODE (removed — PostgreSQL is always active)
 	if v := os.Getenv("GOCLAW_MODE"); v != "" {
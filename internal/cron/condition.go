@@ -0,0 +1,55 @@
+package cron
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// EvalCondition evaluates a DeliverIf expression against a run's output.
+// previous is the output of the job's prior run (for "changed"), as stored by
+// TruncateOutput, so the comparison truncates output the same way.
+//
+// Supported expressions (prefix any with "!" to negate):
+//   - ""                 always true
+//   - "nonempty"         output has non-whitespace content
+//   - "changed"          output differs from the previous run's output
+//   - "contains:<text>"  output contains text (case-insensitive)
+//   - "matches:<regex>"  output matches a Go regular expression
+func EvalCondition(expr, output, previous string) (bool, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return true, nil
+	}
+	negate := false
+	if strings.HasPrefix(expr, "!") {
+		negate = true
+		expr = strings.TrimSpace(expr[1:])
+	}
+
+	var ok bool
+	op, arg, _ := strings.Cut(expr, ":")
+	switch op {
+	case "nonempty":
+		ok = strings.TrimSpace(output) != ""
+	case "changed":
+		ok = strings.TrimSpace(TruncateOutput(output)) != strings.TrimSpace(previous)
+	case "contains":
+		ok = strings.Contains(strings.ToLower(output), strings.ToLower(arg))
+	case "matches":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return false, fmt.Errorf("invalid condition regex: %w", err)
+		}
+		ok = re.MatchString(output)
+	default:
+		return false, fmt.Errorf("unknown condition: %s", expr)
+	}
+	return ok != negate, nil
+}
+
+// ValidateCondition checks that a DeliverIf expression parses.
+func ValidateCondition(expr string) error {
+	_, err := EvalCondition(expr, "", "")
+	return err
+}
@@ -0,0 +1,91 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	webhookTimeout      = 30 * time.Second
+	maxWebhookRespBytes = 64 * 1024
+	maxWebhookRedirects = 3
+)
+
+// WebhookBody is the JSON document POSTed by "webhook" payloads.
+type WebhookBody struct {
+	JobID   string `json:"jobId"`
+	JobName string `json:"jobName"`
+	Message string `json:"message"`
+	Input   string `json:"input,omitempty"`
+	FiredAt int64  `json:"firedAtMs"`
+}
+
+// ValidateWebhookURL checks that raw is an absolute http(s) URL.
+func ValidateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid webhook url: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook url must be absolute http(s): %s", raw)
+	}
+	return nil
+}
+
+// PostWebhook POSTs body to rawURL and returns the response body. checkURL
+// vets the target and every redirect (the SSRF guard); it is required.
+// Non-2xx responses are returned as errors so they go through retry.
+func PostWebhook(ctx context.Context, client *http.Client, checkURL func(string) error, rawURL string, headers map[string]string, body WebhookBody) (string, error) {
+	if checkURL == nil {
+		return "", fmt.Errorf("webhook url check not configured")
+	}
+	if err := ValidateWebhookURL(rawURL); err != nil {
+		return "", err
+	}
+	if err := checkURL(rawURL); err != nil {
+		return "", fmt.Errorf("webhook blocked: %w", err)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout}
+	}
+	guarded := *client
+	guarded.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > maxWebhookRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxWebhookRedirects)
+		}
+		if err := checkURL(req.URL.String()); err != nil {
+			return fmt.Errorf("webhook redirect blocked: %w", err)
+		}
+		return nil
+	}
+	resp, err := guarded.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookRespBytes))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("webhook returned %d: %s", resp.StatusCode, TruncateOutput(string(respBody)))
+	}
+	return string(respBody), nil
+}
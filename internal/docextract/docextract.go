@@ -0,0 +1,181 @@
+// Package docextract extracts plain text from PDF, DOCX, XLSX and PPTX files
+// in pure Go, so documents can be read without a vision/document-capable
+// provider and without network access.
+//
+// PDFs yield the text layer page by page; scanned PDFs have none and return
+// ErrNoText so callers can fall back to an OCR-capable model. Office files are
+// zipped XML: DOCX paragraphs and tables, XLSX sheets and PPTX slides are
+// rendered as markdown.
+package docextract
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Format identifies a supported document format.
+type Format string
+
+const (
+	FormatPDF  Format = "pdf"
+	FormatDOCX Format = "docx"
+	FormatXLSX Format = "xlsx"
+	FormatPPTX Format = "pptx"
+)
+
+// MaxFileBytes is the largest file ExtractFile reads.
+const MaxFileBytes = 50 * 1024 * 1024
+
+var (
+	// ErrUnsupported is returned for formats this package can't read.
+	ErrUnsupported = errors.New("unsupported document format")
+	// ErrNoText means the document parsed but holds no extractable text:
+	// a scanned or image-only PDF, or an office file with only pictures.
+	ErrNoText = errors.New("document has no extractable text")
+)
+
+// Document is the text extracted from a file.
+type Document struct {
+	Format    Format
+	Text      string
+	Parts     int  // pages (PDF), slides (PPTX) or sheets (XLSX); 0 for DOCX
+	Truncated bool // Text was cut at the requested limit
+}
+
+var formatsByExt = map[string]Format{
+	".pdf":  FormatPDF,
+	".docx": FormatDOCX,
+	".xlsx": FormatXLSX,
+	".xlsm": FormatXLSX,
+	".pptx": FormatPPTX,
+}
+
+var formatsByMIME = map[string]Format{
+	"application/pdf": FormatPDF,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   FormatDOCX,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet":         FormatXLSX,
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": FormatPPTX,
+}
+
+// FormatOf returns the format of a file from its name, falling back to its
+// MIME type, or "" when neither is supported. Legacy binary formats (.doc,
+// .xls, .ppt) are not supported.
+func FormatOf(fileName, mimeType string) Format {
+	if f, ok := formatsByExt[strings.ToLower(filepath.Ext(fileName))]; ok {
+		return f
+	}
+	if i := strings.IndexByte(mimeType, ';'); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	return formatsByMIME[strings.TrimSpace(strings.ToLower(mimeType))]
+}
+
+// MIMEType returns the MIME type of a format.
+func (f Format) MIMEType() string {
+	for m, ff := range formatsByMIME {
+		if ff == f {
+			return m
+		}
+	}
+	return "application/octet-stream"
+}
+
+// ExtractFile reads the file at path and extracts its text. maxChars limits
+// the extracted text in bytes (0 = no limit).
+func ExtractFile(path string, format Format, maxChars int) (*Document, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > MaxFileBytes {
+		return nil, fmt.Errorf("document too large: %d bytes (max %d)", info.Size(), MaxFileBytes)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Extract(data, format, maxChars)
+}
+
+// Extract extracts the text of an in-memory document. maxChars limits the
+// extracted text in bytes (0 = no limit); extraction stops once it's reached.
+func Extract(data []byte, format Format, maxChars int) (*Document, error) {
+	out := &output{limit: maxChars}
+	var (
+		parts int
+		err   error
+	)
+	switch format {
+	case FormatPDF:
+		parts, err = extractPDF(data, out)
+	case FormatDOCX:
+		err = extractDOCX(data, out)
+	case FormatXLSX:
+		parts, err = extractXLSX(data, out)
+	case FormatPPTX:
+		parts, err = extractPPTX(data, out)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupported, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("extract %s: %w", format, err)
+	}
+
+	text := strings.TrimSpace(collapseBlankLines(out.String()))
+	if text == "" {
+		return nil, ErrNoText
+	}
+	return &Document{Format: format, Text: text, Parts: parts, Truncated: out.truncated}, nil
+}
+
+// output accumulates extracted text up to a byte limit. Extractors check
+// full() to stop parsing early on huge documents.
+type output struct {
+	b         strings.Builder
+	limit     int
+	truncated bool
+}
+
+func (o *output) WriteString(s string) {
+	if o.truncated {
+		return
+	}
+	if o.limit > 0 && o.b.Len()+len(s) > o.limit {
+		s = s[:o.limit-o.b.Len()]
+		for len(s) > 0 && !utf8.ValidString(s) {
+			s = s[:len(s)-1] // don't split a multi-byte rune
+		}
+		o.truncated = true
+	}
+	o.b.WriteString(s)
+}
+
+func (o *output) full() bool { return o.truncated }
+
+func (o *output) String() string { return o.b.String() }
+
+// collapseBlankLines trims trailing spaces from lines and folds runs of blank
+// lines into one.
+func collapseBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	kept := lines[:0]
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
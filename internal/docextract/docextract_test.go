@@ -0,0 +1,312 @@
+package docextract
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// buildZip packs name → content pairs into an in-memory zip.
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// buildPDF writes a minimal PDF with one Helvetica text line per page. An
+// empty line produces a page without a text layer, like a scanned page.
+func buildPDF(pages ...string) []byte {
+	var objs []string
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objs = append(objs,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	)
+	for i, text := range pages {
+		stream := ""
+		if text != "" {
+			stream = fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+		}
+		objs = append(objs,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, o := range objs {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return b.Bytes()
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := []struct {
+		name, mime string
+		want       Format
+	}{
+		{"report.PDF", "", FormatPDF},
+		{"notes.docx", "", FormatDOCX},
+		{"budget.xlsx", "", FormatXLSX},
+		{"deck.pptx", "", FormatPPTX},
+		{"upload.bin", "application/pdf", FormatPDF},
+		{"", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet; charset=binary", FormatXLSX},
+		{"legacy.doc", "application/msword", ""},
+		{"photo.jpg", "image/jpeg", ""},
+	}
+	for _, tt := range tests {
+		if got := FormatOf(tt.name, tt.mime); got != tt.want {
+			t.Errorf("FormatOf(%q, %q) = %q, want %q", tt.name, tt.mime, got, tt.want)
+		}
+	}
+}
+
+func TestExtractPDF(t *testing.T) {
+	doc, err := Extract(buildPDF("Quarterly revenue grew by twelve percent", "Outlook remains positive for next year"), FormatPDF, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Parts != 2 {
+		t.Errorf("Parts = %d, want 2", doc.Parts)
+	}
+	for _, want := range []string{"--- Page 1 ---", "Quarterly revenue grew", "--- Page 2 ---", "Outlook remains positive"} {
+		if !strings.Contains(doc.Text, want) {
+			t.Errorf("text missing %q:\n%s", want, doc.Text)
+		}
+	}
+}
+
+func TestExtractPDFScanned(t *testing.T) {
+	_, err := Extract(buildPDF("", "3"), FormatPDF, 0)
+	if !errors.Is(err, ErrNoText) {
+		t.Fatalf("err = %v, want ErrNoText", err)
+	}
+	if _, err := Extract([]byte("not a pdf"), FormatPDF, 0); err == nil || errors.Is(err, ErrNoText) {
+		t.Errorf("garbage input: err = %v, want parse error", err)
+	}
+}
+
+const docxBody = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:body>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Project Plan</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Kickoff is </w:t></w:r><w:r><w:t>Monday.</w:t></w:r><w:del><w:r><w:delText>Tuesday</w:delText></w:r></w:del></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>Hire designer</w:t></w:r></w:p>
+<w:tbl>
+<w:tr><w:tc><w:p><w:r><w:t>Task</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Owner</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:p><w:r><w:t>Design | UX</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Ana</w:t></w:r></w:p></w:tc></w:tr>
+</w:tbl>
+<w:p/>
+<w:p><w:r><w:t>End of plan.</w:t></w:r></w:p>
+</w:body>
+</w:document>`
+
+func TestExtractDOCX(t *testing.T) {
+	data := buildZip(t, map[string]string{docxMainPart: docxBody})
+	doc, err := Extract(data, FormatDOCX, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Project Plan\n\nKickoff is Monday.\n\n- Hire designer\n\n" +
+		"| Task | Owner |\n| --- | --- |\n| Design \\| UX | Ana |\n\nEnd of plan."
+	if doc.Text != want {
+		t.Errorf("text =\n%s\nwant\n%s", doc.Text, want)
+	}
+}
+
+func TestExtractXLSX(t *testing.T) {
+	data := buildZip(t, map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Totals" sheetId="2" r:id="rId2"/><sheet name="Raw" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>Item</t></si><si><t>Qty</t></si><si><r><t>App</t></r><r><t>les</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+<row r="2"/>
+<row r="3"><c r="A3" t="s"><v>2</v></c><c r="C3"><f>SUM(1,2)</f><v>3</v></c></row>
+</sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>Done</t></is></c><c r="B1" t="b"><v>1</v></c></row>
+</sheetData></worksheet>`,
+	})
+	doc, err := Extract(data, FormatXLSX, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Parts != 2 {
+		t.Errorf("Parts = %d, want 2", doc.Parts)
+	}
+	want := "## Sheet: Totals\n\n| Done | TRUE |\n| --- | --- |\n\n" +
+		"## Sheet: Raw\n\n| Item | Qty |  |\n| --- | --- | --- |\n| Apples |  | 3 |"
+	if doc.Text != want {
+		t.Errorf("text =\n%s\nwant\n%s", doc.Text, want)
+	}
+}
+
+func TestExtractPPTX(t *testing.T) {
+	slide := func(texts ...string) string {
+		var b strings.Builder
+		b.WriteString(`<p:sld xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"><p:cSld><p:spTree><p:sp><p:txBody>`)
+		for _, s := range texts {
+			b.WriteString("<a:p><a:r><a:t>" + s + "</a:t></a:r></a:p>")
+		}
+		b.WriteString(`</p:txBody></p:sp></p:spTree></p:cSld></p:sld>`)
+		return b.String()
+	}
+	data := buildZip(t, map[string]string{
+		"ppt/presentation.xml": `<p:presentation xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<p:sldIdLst><p:sldId id="256" r:id="rId3"/><p:sldId id="257" r:id="rId2"/></p:sldIdLst></p:presentation>`,
+		"ppt/_rels/presentation.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId2" Target="slides/slide1.xml"/><Relationship Id="rId3" Target="slides/slide2.xml"/></Relationships>`,
+		"ppt/slides/slide1.xml": slide("Thanks", "Questions?"),
+		"ppt/slides/slide2.xml": slide("Roadmap 2027", ""),
+	})
+	doc, err := Extract(data, FormatPPTX, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "## Slide 1\n\nRoadmap 2027\n\n## Slide 2\n\nThanks\nQuestions?"
+	if doc.Text != want {
+		t.Errorf("text =\n%s\nwant\n%s", doc.Text, want)
+	}
+}
+
+func TestExtractTruncates(t *testing.T) {
+	var body strings.Builder
+	body.WriteString(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`)
+	for range 500 {
+		body.WriteString("<w:p><w:r><w:t>Ünïcode paragraph text</w:t></w:r></w:p>")
+	}
+	body.WriteString("</w:body></w:document>")
+
+	doc, err := Extract(buildZip(t, map[string]string{docxMainPart: body.String()}), FormatDOCX, 1001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Truncated || len(doc.Text) > 1001 {
+		t.Errorf("Truncated = %v, len = %d", doc.Truncated, len(doc.Text))
+	}
+	if !strings.HasPrefix(doc.Text, "Ünïcode") || !utf8.ValidString(doc.Text) {
+		t.Errorf("bad truncated text %q", doc.Text[len(doc.Text)-20:])
+	}
+}
+
+func TestPackageDecompressionBudget(t *testing.T) {
+	part := "<a>" + strings.Repeat("x", 4096) + "</a>"
+	pkg, err := openPackage(buildZip(t, map[string]string{"one.xml": part, "two.xml": part}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg.budget = int64(len(part)) + 100
+
+	read := func(name string) error {
+		d, rc, err := pkg.decoder(name)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		for {
+			if _, err := d.Token(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+	if err := read("one.xml"); err != nil {
+		t.Fatalf("first part: %v", err)
+	}
+	if err := read("two.xml"); !errors.Is(err, errPackageTooLarge) {
+		t.Errorf("second part: err = %v, want errPackageTooLarge", err)
+	}
+}
+
+func TestExtractEmptyAndUnsupported(t *testing.T) {
+	empty := buildZip(t, map[string]string{docxMainPart: `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p/></w:body></w:document>`})
+	if _, err := Extract(empty, FormatDOCX, 0); !errors.Is(err, ErrNoText) {
+		t.Errorf("empty docx: err = %v, want ErrNoText", err)
+	}
+	if _, err := Extract([]byte("x"), "doc", 0); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("err = %v, want ErrUnsupported", err)
+	}
+	if _, err := Extract([]byte("not a zip"), FormatXLSX, 0); err == nil {
+		t.Error("expected error for non-zip xlsx")
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	for ref, want := range map[string]int{"A1": 0, "C7": 2, "Z3": 25, "AA1": 26, "AB12": 27} {
+		if got, ok := columnIndex(ref); !ok || got != want {
+			t.Errorf("columnIndex(%q) = %d, %v; want %d", ref, got, ok, want)
+		}
+	}
+	if _, ok := columnIndex("12"); ok {
+		t.Error("reference without column letters should fail")
+	}
+}
+
+func TestSplitPages(t *testing.T) {
+	doc, err := Extract(buildPDF("Quarterly revenue grew by twelve percent", "Outlook remains positive for next year"), FormatPDF, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages := SplitPages(doc.Text)
+	if len(pages) != 2 {
+		t.Fatalf("got %d pages, want 2: %+v", len(pages), pages)
+	}
+	if pages[0].Number != 1 || !strings.Contains(pages[0].Text, "Quarterly revenue") || strings.Contains(pages[0].Text, "Outlook") {
+		t.Errorf("page 1 = %+v", pages[0])
+	}
+	if pages[1].Number != 2 || !strings.Contains(pages[1].Text, "Outlook remains") || strings.Contains(pages[1].Text, "---") {
+		t.Errorf("page 2 = %+v", pages[1])
+	}
+
+	slides := SplitPages("## Slide 1\n\nRoadmap 2027\n\n## Slide 2\n\n## Slide 3\n\nThanks")
+	if len(slides) != 2 || slides[0].Number != 1 || slides[1].Number != 3 || slides[1].Text != "Thanks" {
+		t.Errorf("slides = %+v", slides)
+	}
+
+	plain := SplitPages("# Manual\n\nNo page markers here.")
+	if len(plain) != 1 || plain[0].Number != 0 {
+		t.Errorf("plain = %+v", plain)
+	}
+	if got := SplitPages("  \n"); got != nil {
+		t.Errorf("blank text = %+v, want nil", got)
+	}
+}
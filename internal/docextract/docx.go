@@ -0,0 +1,161 @@
+package docextract
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const docxMainPart = "word/document.xml"
+
+// docxTable collects the cells of a table being parsed.
+type docxTable struct {
+	rows [][]string
+	cell strings.Builder
+}
+
+// extractDOCX renders the main document body: paragraphs as lines (headings
+// as markdown headings, list items as bullets) and tables as markdown tables.
+// Headers, footers, comments and tracked deletions are skipped.
+func extractDOCX(data []byte, out *output) error {
+	pkg, err := openPackage(data)
+	if err != nil {
+		return err
+	}
+	d, c, err := pkg.decoder(docxMainPart)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	var (
+		paras  []*strings.Builder // nested for text boxes inside paragraphs
+		prefix []string           // markdown prefix per open paragraph
+		tables []*docxTable
+		inText bool
+	)
+	for !out.full() {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				paras = append(paras, &strings.Builder{})
+				prefix = append(prefix, "")
+			case "pStyle":
+				if len(prefix) > 0 {
+					prefix[len(prefix)-1] = headingPrefix(attr(t, "val"))
+				}
+			case "numPr":
+				if len(prefix) > 0 && prefix[len(prefix)-1] == "" {
+					prefix[len(prefix)-1] = "- "
+				}
+			case "t":
+				inText = true
+			case "tab":
+				if len(paras) > 0 {
+					paras[len(paras)-1].WriteString("\t")
+				}
+			case "br", "cr":
+				if len(paras) > 0 {
+					paras[len(paras)-1].WriteString("\n")
+				}
+			case "tbl":
+				tables = append(tables, &docxTable{})
+			case "tr":
+				if len(tables) > 0 {
+					tbl := tables[len(tables)-1]
+					tbl.rows = append(tbl.rows, nil)
+				}
+			case "tc":
+				if len(tables) > 0 {
+					tables[len(tables)-1].cell.Reset()
+				}
+			}
+
+		case xml.CharData:
+			if inText && len(paras) > 0 {
+				paras[len(paras)-1].Write(t)
+			}
+
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if len(paras) == 0 {
+					continue
+				}
+				text := strings.TrimSpace(paras[len(paras)-1].String())
+				pfx := prefix[len(prefix)-1]
+				paras, prefix = paras[:len(paras)-1], prefix[:len(prefix)-1]
+				if text == "" {
+					continue
+				}
+				switch {
+				case len(paras) > 0: // text box content joins the enclosing paragraph
+					paras[len(paras)-1].WriteString(" " + text)
+				case len(tables) > 0:
+					cell := &tables[len(tables)-1].cell
+					if cell.Len() > 0 {
+						cell.WriteString("\n")
+					}
+					cell.WriteString(text)
+				default:
+					out.WriteString(pfx + text + "\n\n")
+				}
+			case "tc":
+				if len(tables) > 0 {
+					tbl := tables[len(tables)-1]
+					if len(tbl.rows) > 0 {
+						last := len(tbl.rows) - 1
+						tbl.rows[last] = append(tbl.rows[last], tbl.cell.String())
+					}
+				}
+			case "tbl":
+				if len(tables) == 0 {
+					continue
+				}
+				tbl := tables[len(tables)-1]
+				tables = tables[:len(tables)-1]
+				if len(tables) > 0 {
+					// Nested table: flatten into the enclosing cell.
+					cell := &tables[len(tables)-1].cell
+					for _, row := range tbl.rows {
+						cell.WriteString("\n" + strings.Join(row, " / "))
+					}
+					continue
+				}
+				writeTable(out, tbl.rows)
+			}
+		}
+	}
+	return nil
+}
+
+// headingPrefix maps a paragraph style ID to a markdown heading prefix.
+// Built-in styles are "Title" and "Heading1".."Heading9" in English
+// templates; localized templates keep those IDs.
+func headingPrefix(style string) string {
+	if style == "Title" {
+		return "# "
+	}
+	level, ok := strings.CutPrefix(style, "Heading")
+	if !ok {
+		return ""
+	}
+	n, err := strconv.Atoi(level)
+	if err != nil || n < 1 {
+		return ""
+	}
+	return strings.Repeat("#", min(n, 6)) + " "
+}
@@ -0,0 +1,192 @@
+package docextract
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Zip bomb guards: maxPartBytes caps the decompressed size of a single XML
+// part, maxPackageBytes the total decompressed across every part read from
+// one package (many small, highly compressible parts add up).
+const (
+	maxPartBytes    = 64 * 1024 * 1024
+	maxPackageBytes = 256 * 1024 * 1024
+)
+
+var (
+	errPartTooLarge    = errors.New("document part exceeds size limit")
+	errPackageTooLarge = errors.New("document exceeds decompressed size limit")
+)
+
+// ooxmlPackage is an Office Open XML package (a zip of XML parts).
+type ooxmlPackage struct {
+	parts map[string]*zip.File
+	// budget is how many more decompressed bytes may be read from the package.
+	budget int64
+}
+
+func openPackage(data []byte) (*ooxmlPackage, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not an office document: %w", err)
+	}
+	p := &ooxmlPackage{parts: make(map[string]*zip.File, len(zr.File)), budget: maxPackageBytes}
+	for _, f := range zr.File {
+		p.parts[strings.TrimPrefix(f.Name, "/")] = f
+	}
+	return p, nil
+}
+
+func (p *ooxmlPackage) has(name string) bool {
+	_, ok := p.parts[name]
+	return ok
+}
+
+// decoder opens a part for streaming XML decoding. The caller closes it.
+func (p *ooxmlPackage) decoder(name string) (*xml.Decoder, io.Closer, error) {
+	f, ok := p.parts[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("missing part %s", name)
+	}
+	if f.UncompressedSize64 > maxPartBytes {
+		return nil, nil, fmt.Errorf("%s: %w", name, errPartTooLarge)
+	}
+	if f.UncompressedSize64 > uint64(max(p.budget, 0)) {
+		return nil, nil, fmt.Errorf("%s: %w", name, errPackageTooLarge)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, nil, fmt.Errorf("open %s: %w", name, err)
+	}
+	// The header size can lie; cap what is actually read too.
+	d := xml.NewDecoder(&budgetReader{r: io.LimitReader(rc, maxPartBytes), p: p})
+	d.Strict = false
+	return d, rc, nil
+}
+
+// budgetReader charges what it reads to the package-wide budget and fails
+// once the budget is spent.
+type budgetReader struct {
+	r io.Reader
+	p *ooxmlPackage
+}
+
+func (b *budgetReader) Read(buf []byte) (int, error) {
+	if b.p.budget <= 0 {
+		return 0, errPackageTooLarge
+	}
+	if int64(len(buf)) > b.p.budget {
+		buf = buf[:b.p.budget]
+	}
+	n, err := b.r.Read(buf)
+	b.p.budget -= int64(n)
+	return n, err
+}
+
+// relationships returns the relationship ID → target part map of a part,
+// with targets resolved to package paths ("xl/worksheets/sheet1.xml").
+func (p *ooxmlPackage) relationships(part string) (map[string]string, error) {
+	dir, file := path.Split(part)
+	relsName := dir + "_rels/" + file + ".rels"
+	d, c, err := p.decoder(relsName)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	var rels struct {
+		Items []struct {
+			ID         string `xml:"Id,attr"`
+			Target     string `xml:"Target,attr"`
+			TargetMode string `xml:"TargetMode,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := d.Decode(&rels); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", relsName, err)
+	}
+	out := make(map[string]string, len(rels.Items))
+	for _, r := range rels.Items {
+		if r.TargetMode == "External" {
+			continue
+		}
+		if strings.HasPrefix(r.Target, "/") {
+			out[r.ID] = strings.TrimPrefix(r.Target, "/")
+		} else {
+			out[r.ID] = path.Join(dir, r.Target)
+		}
+	}
+	return out, nil
+}
+
+// attr returns the value of the attribute with the given local name.
+func attr(se xml.StartElement, local string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// relID returns the r:id attribute of an element. encoding/xml resolves the
+// prefix to the relationships namespace URL, so match on the local name and
+// skip plain "id" attributes.
+func relID(se xml.StartElement) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == "id" && a.Name.Space != "" {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// escapeCell makes text safe for a markdown table cell.
+func escapeCell(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// writeTable renders rows as a markdown table, using the first row as the
+// header. Rows shorter than the widest row are padded.
+func writeTable(out *output, rows [][]string) {
+	width := 0
+	for _, r := range rows {
+		width = max(width, len(r))
+	}
+	if width == 0 {
+		return
+	}
+	var b strings.Builder
+	writeRow := func(r []string) {
+		b.Reset()
+		b.WriteString("|")
+		for i := range width {
+			cell := ""
+			if i < len(r) {
+				cell = escapeCell(r[i])
+			}
+			b.WriteString(" ")
+			b.WriteString(cell)
+			b.WriteString(" |")
+		}
+		b.WriteString("\n")
+		out.WriteString(b.String())
+	}
+	writeRow(rows[0])
+	out.WriteString("|" + strings.Repeat(" --- |", width) + "\n")
+	for _, r := range rows[1:] {
+		if out.full() {
+			return
+		}
+		writeRow(r)
+	}
+	out.WriteString("\n")
+}
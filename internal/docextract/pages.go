@@ -0,0 +1,53 @@
+package docextract
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Page is one page (PDF) or slide (PPTX) of extracted text.
+type Page struct {
+	Number int // 1-based; 0 when the text has no page markers
+	Text   string
+}
+
+// pageMarker matches the page and slide headings written by the PDF and PPTX
+// extractors.
+var pageMarker = regexp.MustCompile(`(?m)^(?:--- Page (\d+) ---|## Slide (\d+))$`)
+
+// SplitPages splits text produced by Extract back into pages using the
+// "--- Page N ---" and "## Slide N" markers. Text without markers, such as
+// DOCX and XLSX output, is returned as a single page numbered 0; text before
+// the first marker is dropped when empty. Markers are removed from page text.
+func SplitPages(text string) []Page {
+	locs := pageMarker.FindAllStringSubmatchIndex(text, -1)
+	if len(locs) == 0 {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		return []Page{{Text: text}}
+	}
+
+	var pages []Page
+	if lead := strings.TrimSpace(text[:locs[0][0]]); lead != "" {
+		pages = append(pages, Page{Text: lead})
+	}
+	for i, loc := range locs {
+		numStart, numEnd := loc[2], loc[3]
+		if numStart < 0 {
+			numStart, numEnd = loc[4], loc[5]
+		}
+		n, _ := strconv.Atoi(text[numStart:numEnd])
+		end := len(text)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		body := strings.TrimSpace(text[loc[1]:end])
+		if body == "" {
+			continue
+		}
+		pages = append(pages, Page{Number: n, Text: body})
+	}
+	return pages
+}
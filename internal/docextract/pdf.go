@@ -0,0 +1,63 @@
+package docextract
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// minCharsPerPage is the average amount of text per page below which a PDF is
+// treated as scanned: image-only pages often still carry a page number or a
+// scanner watermark in their text layer.
+const minCharsPerPage = 16
+
+func extractPDF(data []byte, out *output) (pages int, err error) {
+	// The PDF reader panics on some malformed files instead of returning errors.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed pdf: %v", r)
+		}
+	}()
+
+	r, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return 0, err
+	}
+	pages = r.NumPage()
+	if pages <= 0 {
+		return 0, ErrNoText
+	}
+
+	var page strings.Builder
+	chars := 0
+	for i := 1; i <= pages && !out.full(); i++ {
+		text, err := r.Page(i).GetPlainText(nil)
+		if err != nil {
+			return pages, fmt.Errorf("page %d: %w", i, err)
+		}
+		chars += countVisible(text)
+
+		page.Reset()
+		fmt.Fprintf(&page, "--- Page %d ---\n", i)
+		page.WriteString(strings.TrimSpace(text))
+		page.WriteString("\n\n")
+		out.WriteString(page.String())
+	}
+	if !out.full() && chars < pages*minCharsPerPage {
+		return pages, ErrNoText
+	}
+	return pages, nil
+}
+
+func countVisible(s string) int {
+	n := 0
+	for _, r := range s {
+		if !unicode.IsSpace(r) {
+			n++
+		}
+	}
+	return n
+}
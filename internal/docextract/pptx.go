@@ -0,0 +1,155 @@
+package docextract
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const pptxPresentationPart = "ppt/presentation.xml"
+
+// extractPPTX renders the text of each slide under a "## Slide N" heading,
+// one line per paragraph, in presentation order. Speaker notes are skipped.
+func extractPPTX(data []byte, out *output) (int, error) {
+	pkg, err := openPackage(data)
+	if err != nil {
+		return 0, err
+	}
+	slides, err := pptxSlides(pkg)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, part := range slides {
+		if out.full() {
+			break
+		}
+		lines, err := pptxSlideText(pkg, part)
+		if err != nil {
+			return len(slides), fmt.Errorf("slide %d: %w", i+1, err)
+		}
+		out.WriteString(fmt.Sprintf("## Slide %d\n\n", i+1))
+		for _, l := range lines {
+			out.WriteString(l + "\n")
+		}
+		out.WriteString("\n")
+	}
+	return len(slides), nil
+}
+
+// pptxSlides returns slide part paths in presentation order. If the slide
+// list can't be read, slides are ordered by their file number instead.
+func pptxSlides(pkg *ooxmlPackage) ([]string, error) {
+	if slides, err := pptxSlideList(pkg); err == nil && len(slides) > 0 {
+		return slides, nil
+	}
+	var slides []string
+	for name := range pkg.parts {
+		if slideNumber(name) > 0 {
+			slides = append(slides, name)
+		}
+	}
+	if len(slides) == 0 {
+		return nil, errors.New("no slides found")
+	}
+	sort.Slice(slides, func(i, j int) bool { return slideNumber(slides[i]) < slideNumber(slides[j]) })
+	return slides, nil
+}
+
+func pptxSlideList(pkg *ooxmlPackage) ([]string, error) {
+	rels, err := pkg.relationships(pptxPresentationPart)
+	if err != nil {
+		return nil, err
+	}
+	d, c, err := pkg.decoder(pptxPresentationPart)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	var slides []string
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "sldId" {
+			continue
+		}
+		if part, ok := rels[relID(se)]; ok && pkg.has(part) {
+			slides = append(slides, part)
+		}
+	}
+	return slides, nil
+}
+
+// slideNumber returns N for "ppt/slides/slideN.xml", or 0.
+func slideNumber(name string) int {
+	rest, ok := strings.CutPrefix(name, "ppt/slides/slide")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(rest, ".xml"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// pptxSlideText returns the non-empty text paragraphs (<a:p>) of a slide,
+// including those inside tables and grouped shapes.
+func pptxSlideText(pkg *ooxmlPackage, part string) ([]string, error) {
+	d, c, err := pkg.decoder(part)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	var (
+		lines  []string
+		para   strings.Builder
+		inText bool
+	)
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				para.Reset()
+			case "t":
+				inText = true
+			case "br":
+				para.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				para.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if s := strings.TrimSpace(para.String()); s != "" {
+					lines = append(lines, s)
+				}
+			}
+		}
+	}
+	return lines, nil
+}
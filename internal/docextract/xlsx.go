@@ -0,0 +1,245 @@
+package docextract
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const xlsxWorkbookPart = "xl/workbook.xml"
+
+// extractXLSX renders each worksheet as a markdown table under a "## Sheet"
+// heading, in workbook order. Cells hold their cached values: formulas show
+// their last computed result and dates show Excel serial numbers.
+func extractXLSX(data []byte, out *output) (int, error) {
+	pkg, err := openPackage(data)
+	if err != nil {
+		return 0, err
+	}
+	shared, err := xlsxSharedStrings(pkg)
+	if err != nil {
+		return 0, err
+	}
+	sheets, err := xlsxSheets(pkg)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, s := range sheets {
+		if out.full() {
+			break
+		}
+		rows, err := xlsxRows(pkg, s.part, shared, out.limit)
+		if err != nil {
+			return len(sheets), fmt.Errorf("sheet %q: %w", s.name, err)
+		}
+		out.WriteString("## Sheet: " + s.name + "\n\n")
+		if len(rows) == 0 {
+			out.WriteString("(empty)\n\n")
+			continue
+		}
+		writeTable(out, rows)
+	}
+	return len(sheets), nil
+}
+
+type xlsxSheet struct {
+	name, part string
+}
+
+// xlsxSheets lists worksheets in workbook order with their part paths.
+func xlsxSheets(pkg *ooxmlPackage) ([]xlsxSheet, error) {
+	rels, err := pkg.relationships(xlsxWorkbookPart)
+	if err != nil {
+		return nil, err
+	}
+	d, c, err := pkg.decoder(xlsxWorkbookPart)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	var sheets []xlsxSheet
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "sheet" {
+			continue
+		}
+		if part, ok := rels[relID(se)]; ok && pkg.has(part) {
+			sheets = append(sheets, xlsxSheet{name: attr(se, "name"), part: part})
+		}
+	}
+	return sheets, nil
+}
+
+// xlsxSharedStrings loads the shared string table. Workbooks without string
+// cells have none.
+func xlsxSharedStrings(pkg *ooxmlPackage) ([]string, error) {
+	const part = "xl/sharedStrings.xml"
+	if !pkg.has(part) {
+		return nil, nil
+	}
+	d, c, err := pkg.decoder(part)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	var (
+		strs   []string
+		cur    strings.Builder
+		inText bool
+		inPhon bool // phonetic hints (rPh) repeat the text in kana
+	)
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				cur.Reset()
+			case "t":
+				inText = !inPhon
+			case "rPh":
+				inPhon = true
+			}
+		case xml.CharData:
+			if inText {
+				cur.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "si":
+				strs = append(strs, cur.String())
+			case "t":
+				inText = false
+			case "rPh":
+				inPhon = false
+			}
+		}
+	}
+	return strs, nil
+}
+
+// xlsxRows reads the cell values of a worksheet. Empty rows are dropped and
+// gaps between cells are kept so columns stay aligned. Reading stops after
+// roughly limit bytes of cell text (0 = no limit).
+func xlsxRows(pkg *ooxmlPackage, part string, shared []string, limit int) ([][]string, error) {
+	d, c, err := pkg.decoder(part)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	var (
+		rows     [][]string
+		row      []string
+		col      int
+		cellType string
+		value    strings.Builder
+		inValue  bool
+		size     int
+	)
+	for limit <= 0 || size < limit {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				row = row[:0]
+				col = 0
+			case "c":
+				if ref := attr(t, "r"); ref != "" {
+					if n, ok := columnIndex(ref); ok {
+						col = n
+					}
+				}
+				cellType = attr(t, "t")
+				value.Reset()
+			case "v", "t": // <v> holds values; <is><t> holds inline strings
+				inValue = true
+			}
+		case xml.CharData:
+			if inValue {
+				value.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "v", "t":
+				inValue = false
+			case "c":
+				v := cellValue(cellType, value.String(), shared)
+				if v != "" {
+					for len(row) < col {
+						row = append(row, "")
+					}
+					row = append(row, v)
+					size += len(v)
+				}
+				col++
+			case "row":
+				if len(row) > 0 {
+					rows = append(rows, append([]string(nil), row...))
+				}
+			}
+		}
+	}
+	return rows, nil
+}
+
+func cellValue(cellType, raw string, shared []string) string {
+	switch cellType {
+	case "s":
+		i, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || i < 0 || i >= len(shared) {
+			return ""
+		}
+		return shared[i]
+	case "b":
+		if strings.TrimSpace(raw) == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	return raw
+}
+
+// columnIndex converts the column letters of a cell reference ("C7", "AB12")
+// to a zero-based index.
+func columnIndex(ref string) (int, bool) {
+	n := 0
+	i := 0
+	for ; i < len(ref); i++ {
+		ch := ref[i]
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		n = n*26 + int(ch-'A'+1)
+	}
+	if i == 0 || n > 16384 { // Excel's column limit (XFD)
+		return 0, false
+	}
+	return n - 1, true
+}
@@ -17,7 +17,7 @@ type Edition struct {
 	KGEnabled             bool           `json:"kg_enabled"`
 	RBACEnabled           bool           `json:"rbac_enabled"`
 	TeamFullMode          bool           `json:"team_full_mode"`          // false = lite task actions only
-	VectorSearch          bool           `json:"vector_search"`           // false = FTS5 only
+	VectorSearch          bool           `json:"vector_search"`           // false = text search only
 }
 
 // --- Presets ---
@@ -43,7 +43,7 @@ var Lite = Edition{
 	KGEnabled:             false,
 	RBACEnabled:           false,
 	TeamFullMode:          false,
-	VectorSearch:          false,
+	VectorSearch:          true,
 }
 
 // --- Global state ---
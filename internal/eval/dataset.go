@@ -0,0 +1,144 @@
+// Package eval replays datasets of inputs against agents and scores the
+// responses. An eval run is stored as a trace (channel "eval") whose metadata
+// holds the results; each case runs in its own session and produces a normal
+// agent trace linked to the run trace, so evals show up alongside other traces.
+package eval
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Grader names.
+const (
+	GraderExact = "exact"     // normalized equality with Expected
+	GraderRegex = "regex"     // Pattern matches the response
+	GraderJudge = "llm_judge" // a model scores the response against Rubric
+)
+
+// Case is one dataset entry.
+type Case struct {
+	ID       string   `json:"id,omitempty"`
+	Input    string   `json:"input"`
+	Expected string   `json:"expected,omitempty"`
+	Pattern  string   `json:"pattern,omitempty"`
+	Rubric   string   `json:"rubric,omitempty"`
+	Graders  []string `json:"graders,omitempty"` // default: every grader whose field is set
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// maxDatasetLine bounds a single JSONL line (long documents as inputs).
+const maxDatasetLine = 4 << 20
+
+// ParseDataset reads a JSONL dataset: one case per line, blank lines and
+// lines starting with "#" are skipped. Cases without an id get "case-N".
+func ParseDataset(r io.Reader) ([]Case, error) {
+	var cases []Case
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), maxDatasetLine)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var c Case
+		if err := json.Unmarshal([]byte(text), &c); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if c.ID == "" {
+			c.ID = fmt.Sprintf("case-%d", len(cases)+1)
+		}
+		cases = append(cases, c)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if err := ValidateCases(cases); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+// ValidateCases checks that cases are non-empty, have unique ids, and that
+// each case has something to grade against.
+func ValidateCases(cases []Case) error {
+	if len(cases) == 0 {
+		return fmt.Errorf("dataset has no cases")
+	}
+	seen := make(map[string]bool, len(cases))
+	for i := range cases {
+		c := &cases[i]
+		if c.ID == "" {
+			c.ID = fmt.Sprintf("case-%d", i+1)
+		}
+		if seen[c.ID] {
+			return fmt.Errorf("case %s: duplicate id", c.ID)
+		}
+		seen[c.ID] = true
+		if strings.TrimSpace(c.Input) == "" {
+			return fmt.Errorf("case %s: input is required", c.ID)
+		}
+		graders := c.graders()
+		if len(graders) == 0 {
+			return fmt.Errorf("case %s: needs expected, pattern or rubric", c.ID)
+		}
+		for _, g := range graders {
+			switch g {
+			case GraderExact:
+				if c.Expected == "" {
+					return fmt.Errorf("case %s: exact grader needs expected", c.ID)
+				}
+			case GraderRegex:
+				if c.Pattern == "" {
+					return fmt.Errorf("case %s: regex grader needs pattern", c.ID)
+				}
+				if _, err := regexp.Compile(c.Pattern); err != nil {
+					return fmt.Errorf("case %s: invalid pattern: %w", c.ID, err)
+				}
+			case GraderJudge:
+				if c.Rubric == "" && c.Expected == "" {
+					return fmt.Errorf("case %s: llm_judge grader needs rubric or expected", c.ID)
+				}
+			default:
+				return fmt.Errorf("case %s: unknown grader %q", c.ID, g)
+			}
+		}
+	}
+	return nil
+}
+
+// graders returns the explicit grader list, or the graders implied by the
+// fields that are set (a rubric implies the judge; expected alone implies exact).
+func (c *Case) graders() []string {
+	if len(c.Graders) > 0 {
+		return c.Graders
+	}
+	var out []string
+	if c.Expected != "" && c.Rubric == "" {
+		out = append(out, GraderExact)
+	}
+	if c.Pattern != "" {
+		out = append(out, GraderRegex)
+	}
+	if c.Rubric != "" {
+		out = append(out, GraderJudge)
+	}
+	return out
+}
+
+// needsJudge reports whether any case uses the LLM judge.
+func needsJudge(cases []Case) bool {
+	for i := range cases {
+		if slices.Contains(cases[i].graders(), GraderJudge) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestParseDataset(t *testing.T) {
+	data := `# smoke tests
+{"input":"What is 2+2?","expected":"4"}
+
+{"id":"capital","input":"Capital of France?","pattern":"(?i)paris"}
+{"id":"tone","input":"Say hi","rubric":"Friendly greeting"}
+`
+	cases, err := ParseDataset(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) != 3 || cases[0].ID != "case-1" || cases[1].ID != "capital" {
+		t.Fatalf("unexpected cases: %+v", cases)
+	}
+	if g := cases[2].graders(); len(g) != 1 || g[0] != GraderJudge {
+		t.Errorf("rubric should imply llm_judge, got %v", g)
+	}
+	if !needsJudge(cases) {
+		t.Error("needsJudge should be true")
+	}
+
+	for name, bad := range map[string]string{
+		"no grader":  `{"input":"x"}`,
+		"no input":   `{"expected":"x"}`,
+		"bad regex":  `{"input":"x","pattern":"("}`,
+		"duplicate":  `{"id":"a","input":"x","expected":"y"}` + "\n" + `{"id":"a","input":"x","expected":"y"}`,
+		"bad grader": `{"input":"x","expected":"y","graders":["fuzzy"]}`,
+		"empty":      "# nothing",
+		"bad json":   `{"input":`,
+	} {
+		if _, err := ParseDataset(strings.NewReader(bad)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestGraders(t *testing.T) {
+	if g := gradeExact("Hello  World", "hello world\n"); !g.Pass || g.Score != 1 {
+		t.Errorf("exact should ignore case and whitespace: %+v", g)
+	}
+	if g := gradeExact("4", "The answer is 4"); g.Pass {
+		t.Errorf("exact should not match substrings: %+v", g)
+	}
+	if g := gradeRegex(`\b4\b`, "The answer is 4"); !g.Pass {
+		t.Errorf("regex should match: %+v", g)
+	}
+
+	g, err := parseJudgeReply("```json\n{\"score\":0.8,\"reason\":\"good\"}\n```")
+	if err != nil || !g.Pass || g.Score != 0.8 {
+		t.Errorf("judge reply: %+v %v", g, err)
+	}
+	g, _ = parseJudgeReply(`{"score":0.9,"pass":false}`)
+	if g.Pass {
+		t.Error("explicit pass=false should win over score")
+	}
+	if _, err := parseJudgeReply("looks fine"); err == nil {
+		t.Error("expected error for non-JSON reply")
+	}
+
+	var nilJudge *Judge
+	c := &Case{Input: "x", Rubric: "y"}
+	if g := nilJudge.grade(context.Background(), c, "out"); g.Pass {
+		t.Error("missing judge must not pass")
+	}
+}
+
+func TestSummarizeComparison(t *testing.T) {
+	variants := []Variant{{Label: "base"}, {Label: "cand"}}
+	results := []CaseResult{
+		{CaseID: "a", Variant: "base", Pass: true, Score: 1},
+		{CaseID: "a", Variant: "cand", Pass: false, Score: 0},
+		{CaseID: "b", Variant: "base", Pass: false, Score: 0},
+		{CaseID: "b", Variant: "cand", Pass: true, Score: 1},
+		{CaseID: "c", Variant: "base", Pass: true, Score: 1},
+		{CaseID: "c", Variant: "cand", Error: "boom"},
+	}
+	sums, cmp := summarize(variants, results)
+	if sums[0].Passed != 2 || sums[0].PassRate != 0.67 {
+		t.Errorf("baseline summary: %+v", sums[0])
+	}
+	if sums[1].Passed != 1 || sums[1].Errors != 1 || sums[1].Failed != 1 {
+		t.Errorf("candidate summary: %+v", sums[1])
+	}
+	if cmp == nil || strings.Join(cmp.Improved, ",") != "b" || strings.Join(cmp.Regressed, ",") != "a,c" {
+		t.Fatalf("comparison: %+v", cmp)
+	}
+	if cmp.PassRateDelta != -0.34 {
+		t.Errorf("pass rate delta = %v", cmp.PassRateDelta)
+	}
+}
+
+type fakeAgent struct {
+	id    string
+	reply func(msg string) (string, error)
+
+	mu   sync.Mutex
+	reqs []agent.RunRequest
+}
+
+func (a *fakeAgent) ID() string                   { return a.id }
+func (a *fakeAgent) IsRunning() bool              { return false }
+func (a *fakeAgent) Model() string                { return "fake-model" }
+func (a *fakeAgent) ProviderName() string         { return "fake" }
+func (a *fakeAgent) Provider() providers.Provider { return nil }
+func (a *fakeAgent) Run(_ context.Context, req agent.RunRequest) (*agent.RunResult, error) {
+	a.mu.Lock()
+	a.reqs = append(a.reqs, req)
+	a.mu.Unlock()
+	out, err := a.reply(req.Message)
+	if err != nil {
+		return nil, err
+	}
+	return &agent.RunResult{Content: out, RunID: req.RunID, Usage: &providers.Usage{PromptTokens: 10, CompletionTokens: 2}}, nil
+}
+
+type fakeRouter map[string]agent.Agent
+
+func (r fakeRouter) Get(_ context.Context, id string) (agent.Agent, error) {
+	if a, ok := r[id]; ok {
+		return a, nil
+	}
+	return nil, errors.New("agent not found: " + id)
+}
+
+func TestManagerRunComparesVariants(t *testing.T) {
+	good := &fakeAgent{id: "good", reply: func(string) (string, error) { return "Paris", nil }}
+	bad := &fakeAgent{id: "bad", reply: func(msg string) (string, error) {
+		if strings.Contains(msg, "fail") {
+			return "", errors.New("provider down")
+		}
+		return "London", nil
+	}}
+	m := NewManager(fakeRouter{"good": good, "bad": bad}, nil, nil, nil)
+
+	if _, err := m.Start(context.Background(), Spec{Variants: []Variant{{Agent: "missing"}}, Cases: []Case{{Input: "x", Expected: "y"}}}); err == nil {
+		t.Fatal("expected error for unknown agent")
+	}
+
+	run, err := m.Start(context.Background(), Spec{
+		Name:     "capitals",
+		Variants: []Variant{{Agent: "good"}, {Agent: "bad"}},
+		Cases: []Case{
+			{ID: "fr", Input: "Capital of France?", Pattern: "(?i)paris"},
+			{ID: "err", Input: "please fail", Expected: "Paris"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for run.Status == store.TraceStatusRunning && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		if run, err = m.Get(context.Background(), run.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if run.Status != store.TraceStatusCompleted {
+		t.Fatalf("status = %s", run.Status)
+	}
+	if len(run.Results) != 4 || run.Results[0].CaseID != "fr" || run.Results[0].Variant != "good" {
+		t.Fatalf("results not in dataset/variant order: %+v", run.Results)
+	}
+	if run.Variants[0].Passed != 2 || run.Variants[1].Errors != 1 || run.Variants[1].Failed != 1 {
+		t.Errorf("summaries: %+v", run.Variants)
+	}
+	if run.Comparison == nil || strings.Join(run.Comparison.Regressed, ",") != "fr,err" {
+		t.Errorf("comparison: %+v", run.Comparison)
+	}
+
+	// Every case runs in its own session under the eval channel.
+	seen := map[string]bool{}
+	for _, req := range append(good.reqs, bad.reqs...) {
+		if req.Channel != Channel || req.UserID != evalUserID(run.ID) || seen[req.SessionKey] {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		seen[req.SessionKey] = true
+	}
+
+	list, total, err := m.List(context.Background(), 10, 0)
+	if err != nil || total != 1 || list[0].Results != nil {
+		t.Errorf("list: %v %d %+v", err, total, list)
+	}
+}
@@ -0,0 +1,158 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+const judgeTimeout = 60 * time.Second
+
+const judgeSystemPrompt = `You are grading the response of an AI assistant for an automated evaluation.
+
+You receive the user input, the assistant's response, and either a grading rubric or a reference answer. Judge only whether the response satisfies the rubric (or agrees with the reference answer in substance; wording may differ).
+
+Respond with ONLY a JSON object:
+{"score": <0.0-1.0>, "pass": <true|false>, "reason": "<one sentence>"}`
+
+// Grade is one grader's verdict on a response.
+type Grade struct {
+	Grader string  `json:"grader"`
+	Pass   bool    `json:"pass"`
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason,omitempty"`
+}
+
+// Judge grades responses with a model. A nil Judge fails llm_judge grades.
+type Judge struct {
+	Provider providers.Provider
+	Model    string
+}
+
+// gradeCase runs every grader of c against output.
+func gradeCase(ctx context.Context, c *Case, output string, judge *Judge) []Grade {
+	graders := c.graders()
+	grades := make([]Grade, 0, len(graders))
+	for _, g := range graders {
+		switch g {
+		case GraderExact:
+			grades = append(grades, gradeExact(c.Expected, output))
+		case GraderRegex:
+			grades = append(grades, gradeRegex(c.Pattern, output))
+		case GraderJudge:
+			grades = append(grades, judge.grade(ctx, c, output))
+		}
+	}
+	return grades
+}
+
+// gradeExact compares case-insensitively with whitespace collapsed.
+func gradeExact(expected, output string) Grade {
+	g := Grade{Grader: GraderExact}
+	if normalizeText(expected) == normalizeText(output) {
+		g.Pass, g.Score = true, 1
+	} else {
+		g.Reason = "response differs from expected"
+	}
+	return g
+}
+
+func gradeRegex(pattern, output string) Grade {
+	g := Grade{Grader: GraderRegex}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		g.Reason = "invalid pattern: " + err.Error()
+		return g
+	}
+	if re.MatchString(output) {
+		g.Pass, g.Score = true, 1
+	} else {
+		g.Reason = "pattern did not match"
+	}
+	return g
+}
+
+func (j *Judge) grade(ctx context.Context, c *Case, output string) Grade {
+	g := Grade{Grader: GraderJudge}
+	if j == nil || j.Provider == nil {
+		g.Reason = "no judge model configured"
+		return g
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## User input\n%s\n\n## Assistant response\n%s\n\n", c.Input, output)
+	if c.Rubric != "" {
+		fmt.Fprintf(&sb, "## Rubric\n%s\n", c.Rubric)
+	}
+	if c.Expected != "" {
+		fmt.Fprintf(&sb, "## Reference answer\n%s\n", c.Expected)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, judgeTimeout)
+	defer cancel()
+	resp, err := j.Provider.Chat(ctx, providers.ChatRequest{
+		Messages: []providers.Message{
+			{Role: "system", Content: judgeSystemPrompt},
+			{Role: "user", Content: sb.String()},
+		},
+		Model: j.Model,
+		Options: map[string]any{
+			providers.OptMaxTokens:   300,
+			providers.OptTemperature: 0.0,
+		},
+	})
+	if err != nil {
+		g.Reason = "judge error: " + err.Error()
+		return g
+	}
+	verdict, err := parseJudgeReply(resp.Content)
+	if err != nil {
+		g.Reason = err.Error()
+		return g
+	}
+	return verdict
+}
+
+// parseJudgeReply extracts the JSON verdict, tolerating code fences and prose.
+// A missing "pass" falls back to score >= 0.5.
+func parseJudgeReply(reply string) (Grade, error) {
+	g := Grade{Grader: GraderJudge}
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end <= start {
+		return g, fmt.Errorf("unparseable judge reply: %q", truncate(reply, 80))
+	}
+	var out struct {
+		Score  float64 `json:"score"`
+		Pass   *bool   `json:"pass"`
+		Reason string  `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &out); err != nil {
+		return g, fmt.Errorf("unparseable judge reply: %w", err)
+	}
+	g.Score = min(max(out.Score, 0), 1)
+	g.Pass = g.Score >= 0.5
+	if out.Pass != nil {
+		g.Pass = *out.Pass
+	}
+	g.Reason = truncate(out.Reason, 300)
+	return g, nil
+}
+
+func normalizeText(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	// Back off to a rune boundary.
+	for n > 0 && s[n]&0xC0 == 0x80 {
+		n--
+	}
+	return s[:n] + "…"
+}
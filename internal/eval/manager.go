@@ -0,0 +1,493 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/sessions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tracing"
+)
+
+// Channel is the trace channel of eval runs and the session channel of eval cases.
+const Channel = "eval"
+
+// maxUntracedRuns bounds finished runs kept in memory when tracing is off.
+const maxUntracedRuns = 20
+
+// ErrNotFound is returned for unknown eval runs.
+var ErrNotFound = errors.New("eval run not found")
+
+// AgentGetter resolves agents by key or UUID (implemented by agent.Router).
+type AgentGetter interface {
+	Get(ctx context.Context, agentID string) (agent.Agent, error)
+}
+
+// Manager starts eval runs and serves their results. Runs execute in the
+// background; finished runs are read back from the tracing store.
+type Manager struct {
+	agents    AgentGetter
+	providers *providers.Registry
+	tracer    *tracing.Collector // nil = results kept in memory only
+	traces    store.TracingStore
+	sessions  store.SessionStore // optional: eval sessions are deleted after each case
+
+	mu       sync.Mutex
+	runs     map[uuid.UUID]*runState
+	untraced []uuid.UUID // finished in-memory runs, oldest first
+}
+
+type runState struct {
+	tenantID uuid.UUID
+	mu       sync.Mutex
+	run      *Run
+	cancel   context.CancelFunc
+}
+
+// resolvedVariant is a variant with its agent and provider override looked up.
+type resolvedVariant struct {
+	Variant
+	agent    agent.Agent
+	provider providers.Provider
+}
+
+// NewManager creates an eval manager. tracer and traces may be nil.
+func NewManager(agents AgentGetter, providerReg *providers.Registry, tracer *tracing.Collector, traces store.TracingStore) *Manager {
+	return &Manager{
+		agents:    agents,
+		providers: providerReg,
+		tracer:    tracer,
+		traces:    traces,
+		runs:      make(map[uuid.UUID]*runState),
+	}
+}
+
+// SetSessionStore enables cleanup of per-case sessions after each case.
+func (m *Manager) SetSessionStore(s store.SessionStore) { m.sessions = s }
+
+// Start validates spec, records the run trace and executes the run in the
+// background. ctx must carry the caller's tenant; cancelling it does not stop
+// the run (use Cancel).
+func (m *Manager) Start(ctx context.Context, spec Spec) (*Run, error) {
+	if err := validateSpec(&spec); err != nil {
+		return nil, err
+	}
+	variants, judge, err := m.resolve(ctx, &spec)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	runID := store.GenNewID()
+	run := &Run{
+		ID:        runID,
+		Name:      spec.Name,
+		Status:    store.TraceStatusRunning,
+		UserID:    evalUserID(runID),
+		StartedAt: now,
+		CaseCount: len(spec.Cases),
+	}
+	for _, v := range variants {
+		run.Variants = append(run.Variants, VariantSummary{Variant: v.Variant})
+	}
+
+	traced := false
+	if m.tracer != nil {
+		trace := &store.TraceData{
+			ID:           run.ID,
+			RunID:        run.ID.String(),
+			UserID:       store.UserIDFromContext(ctx),
+			StartTime:    now,
+			Name:         "eval:" + spec.Name,
+			Channel:      Channel,
+			InputPreview: fmt.Sprintf("%d cases × %d variants", len(spec.Cases), len(variants)),
+			Status:       store.TraceStatusRunning,
+			Tags:         []string{"eval"},
+			CreatedAt:    now,
+		}
+		if err := m.tracer.CreateTrace(ctx, trace); err != nil {
+			slog.Warn("eval: create trace failed", "eval", spec.Name, "error", err)
+		} else {
+			traced = true
+		}
+	}
+
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	rs := &runState{tenantID: store.TenantIDFromContext(ctx), run: run, cancel: cancel}
+	m.mu.Lock()
+	m.runs[run.ID] = rs
+	m.mu.Unlock()
+
+	slog.Info("eval: run started", "eval_id", run.ID, "name", spec.Name, "cases", len(spec.Cases), "variants", len(variants))
+	go m.execute(runCtx, rs, spec, variants, judge, traced)
+	return rs.snapshot(), nil
+}
+
+// resolve looks up the agents, provider overrides and judge before anything runs,
+// so configuration errors surface to the caller instead of as failed cases.
+func (m *Manager) resolve(ctx context.Context, spec *Spec) ([]resolvedVariant, *Judge, error) {
+	variants := make([]resolvedVariant, 0, len(spec.Variants))
+	for _, v := range spec.Variants {
+		ag, err := m.agents.Get(ctx, v.Agent)
+		if err != nil {
+			return nil, nil, fmt.Errorf("variant %s: %w", v.Label, err)
+		}
+		rv := resolvedVariant{Variant: v, agent: ag}
+		if v.Provider != "" {
+			if m.providers == nil {
+				return nil, nil, fmt.Errorf("variant %s: provider overrides are unavailable", v.Label)
+			}
+			p, err := m.providers.Get(ctx, v.Provider)
+			if err != nil {
+				return nil, nil, fmt.Errorf("variant %s: %w", v.Label, err)
+			}
+			rv.provider = p
+		}
+		variants = append(variants, rv)
+	}
+
+	if !needsJudge(spec.Cases) {
+		return variants, nil, nil
+	}
+	if spec.Judge != nil && spec.Judge.Provider != "" {
+		if m.providers == nil {
+			return nil, nil, fmt.Errorf("judge: providers are unavailable")
+		}
+		p, err := m.providers.Get(ctx, spec.Judge.Provider)
+		if err != nil {
+			return nil, nil, fmt.Errorf("judge: %w", err)
+		}
+		model := spec.Judge.Model
+		if model == "" {
+			model = p.DefaultModel()
+		}
+		return variants, &Judge{Provider: p, Model: model}, nil
+	}
+	first := variants[0]
+	judge := &Judge{Provider: first.agent.Provider(), Model: first.agent.Model()}
+	if first.provider != nil {
+		judge.Provider = first.provider
+	}
+	if spec.Judge != nil && spec.Judge.Model != "" {
+		judge.Model = spec.Judge.Model
+	} else if first.Model != "" {
+		judge.Model = first.Model
+	}
+	return variants, judge, nil
+}
+
+type evalJob struct {
+	variant *resolvedVariant
+	c       *Case
+}
+
+func (m *Manager) execute(ctx context.Context, rs *runState, spec Spec, variants []resolvedVariant, judge *Judge, traced bool) {
+	defer rs.cancel()
+	runID := rs.run.ID
+
+	jobs := make(chan evalJob)
+	var wg sync.WaitGroup
+	for range spec.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				res := m.runCase(ctx, runID, spec, job.variant, job.c, judge, traced)
+				rs.mu.Lock()
+				rs.run.Results = append(rs.run.Results, res)
+				rs.run.Variants, rs.run.Comparison = summarize(variantsOf(variants), rs.run.Results)
+				rs.mu.Unlock()
+			}
+		}()
+	}
+	// Interleave variants per case so a cancelled run still compares like with like.
+feed:
+	for i := range spec.Cases {
+		for j := range variants {
+			select {
+			case jobs <- evalJob{variant: &variants[j], c: &spec.Cases[i]}:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Persist with a context that outlives cancellation.
+	persistCtx := context.WithoutCancel(ctx)
+	status := store.TraceStatusCompleted
+	errMsg := ""
+	if ctx.Err() != nil {
+		status = store.TraceStatusCancelled
+		errMsg = "cancelled"
+	}
+
+	rs.mu.Lock()
+	run := rs.run
+	sortResults(run.Results, spec.Cases, variants)
+	if traced {
+		m.linkCaseTraces(persistCtx, run)
+	}
+	now := time.Now().UTC()
+	run.Status, run.Error, run.FinishedAt = status, errMsg, &now
+	snapshot := *run
+	rs.mu.Unlock()
+
+	slog.Info("eval: run finished", "eval_id", runID, "status", status, "summary", snapshot.Preview())
+	if !traced {
+		m.retainUntraced(runID)
+		return
+	}
+	if b, err := json.Marshal(&snapshot); err == nil {
+		if err := m.tracer.UpdateTrace(persistCtx, runID, map[string]any{"metadata": b}); err != nil {
+			slog.Warn("eval: store results failed", "eval_id", runID, "error", err)
+		}
+	}
+	m.tracer.FinishTrace(persistCtx, runID, status, errMsg, snapshot.Preview())
+	m.mu.Lock()
+	delete(m.runs, runID)
+	m.mu.Unlock()
+}
+
+func (m *Manager) runCase(ctx context.Context, evalID uuid.UUID, spec Spec, v *resolvedVariant, c *Case, judge *Judge, traced bool) CaseResult {
+	res := CaseResult{CaseID: c.ID, Variant: v.Label, RunID: uuid.NewString()}
+	// A fresh session per case and variant keeps cases independent of each other.
+	sessionKey := sessions.BuildSessionKey(v.agent.ID(), Channel, sessions.PeerDirect, res.RunID)
+	req := agent.RunRequest{
+		SessionKey:       sessionKey,
+		Message:          c.Input,
+		Channel:          Channel,
+		ChatID:           res.RunID,
+		PeerKind:         string(sessions.PeerDirect),
+		RunID:            res.RunID,
+		UserID:           evalUserID(evalID),
+		SenderID:         evalUserID(evalID),
+		TraceName:        fmt.Sprintf("eval:%s/%s", spec.Name, c.ID),
+		TraceTags:        []string{"eval", "eval:" + v.Label},
+		ModelOverride:    v.Model,
+		ProviderOverride: v.provider,
+	}
+	if traced {
+		req.LinkedTraceID = evalID
+	}
+
+	start := time.Now()
+	result, err := v.agent.Run(ctx, req)
+	res.DurationMS = int(time.Since(start).Milliseconds())
+	if m.sessions != nil {
+		if derr := m.sessions.Delete(context.WithoutCancel(ctx), sessionKey); derr != nil {
+			slog.Debug("eval: delete session failed", "session", sessionKey, "error", derr)
+		}
+	}
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	if result.Usage != nil {
+		res.InputTokens = result.Usage.PromptTokens
+		res.OutputTokens = result.Usage.CompletionTokens
+	}
+	res.Output = truncate(result.Content, maxOutputChars)
+
+	res.Grades = gradeCase(ctx, c, result.Content, judge)
+	res.Pass = true
+	for _, g := range res.Grades {
+		res.Pass = res.Pass && g.Pass
+		res.Score += g.Score
+	}
+	if len(res.Grades) > 0 {
+		res.Score = round2(res.Score / float64(len(res.Grades)))
+	}
+	return res
+}
+
+// linkCaseTraces fills in each result's trace ID and records the grade on
+// the case trace, so the verdict is visible from the normal trace view.
+func (m *Manager) linkCaseTraces(ctx context.Context, run *Run) {
+	if m.traces == nil {
+		return
+	}
+	children, err := m.traces.ListChildTraces(ctx, run.ID)
+	if err != nil {
+		slog.Warn("eval: list case traces failed", "eval_id", run.ID, "error", err)
+		return
+	}
+	byRun := make(map[string]uuid.UUID, len(children))
+	for _, t := range children {
+		byRun[t.RunID] = t.ID
+	}
+	for i := range run.Results {
+		r := &run.Results[i]
+		id, ok := byRun[r.RunID]
+		if !ok {
+			continue
+		}
+		r.TraceID = &id
+		meta, _ := json.Marshal(map[string]any{"eval": map[string]any{
+			"eval_id": run.ID,
+			"case_id": r.CaseID,
+			"variant": r.Variant,
+			"pass":    r.Pass,
+			"score":   r.Score,
+			"grades":  r.Grades,
+		}})
+		if err := m.tracer.UpdateTrace(ctx, id, map[string]any{"metadata": meta}); err != nil {
+			slog.Debug("eval: annotate case trace failed", "trace_id", id, "error", err)
+		}
+	}
+}
+
+// retainUntraced keeps a bounded number of finished runs in memory when
+// there is no tracing store to read them back from.
+func (m *Manager) retainUntraced(id uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.untraced = append(m.untraced, id)
+	for len(m.untraced) > maxUntracedRuns {
+		delete(m.runs, m.untraced[0])
+		m.untraced = m.untraced[1:]
+	}
+}
+
+// Get returns a run: live state while it runs, the stored trace afterwards.
+func (m *Manager) Get(ctx context.Context, id uuid.UUID) (*Run, error) {
+	if r, ok := m.live(ctx, id); ok {
+		return r, nil
+	}
+	if m.traces == nil {
+		return nil, ErrNotFound
+	}
+	trace, err := m.traces.GetTrace(ctx, id)
+	if err != nil || trace == nil || trace.Channel != Channel {
+		return nil, ErrNotFound
+	}
+	return runFromTrace(trace), nil
+}
+
+// List returns eval runs (without per-case results), newest first.
+func (m *Manager) List(ctx context.Context, limit, offset int) ([]*Run, int, error) {
+	if m.traces == nil {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		out := make([]*Run, 0, len(m.runs))
+		for _, rs := range m.runs {
+			if rs.visibleFrom(ctx) {
+				out = append(out, rs.snapshot().Summary())
+			}
+		}
+		sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.After(out[j].StartedAt) })
+		return out, len(out), nil
+	}
+	opts := store.TraceListOpts{Channel: Channel, Limit: limit, Offset: offset}
+	traces, err := m.traces.ListTraces(ctx, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, _ := m.traces.CountTraces(ctx, opts)
+	out := make([]*Run, 0, len(traces))
+	for i := range traces {
+		if r, ok := m.live(ctx, traces[i].ID); ok {
+			out = append(out, r.Summary())
+			continue
+		}
+		out = append(out, runFromTrace(&traces[i]).Summary())
+	}
+	return out, total, nil
+}
+
+// Cancel stops a running eval. Finished cases keep their results.
+func (m *Manager) Cancel(ctx context.Context, id uuid.UUID) bool {
+	m.mu.Lock()
+	rs, ok := m.runs[id]
+	m.mu.Unlock()
+	if !ok || !rs.visibleFrom(ctx) {
+		return false
+	}
+	rs.mu.Lock()
+	running := rs.run.Status == store.TraceStatusRunning
+	rs.mu.Unlock()
+	if running {
+		rs.cancel()
+	}
+	return running
+}
+
+func (m *Manager) live(ctx context.Context, id uuid.UUID) (*Run, bool) {
+	m.mu.Lock()
+	rs, ok := m.runs[id]
+	m.mu.Unlock()
+	if !ok || !rs.visibleFrom(ctx) {
+		return nil, false
+	}
+	return rs.snapshot(), true
+}
+
+// visibleFrom reports whether the run belongs to the caller's tenant.
+func (rs *runState) visibleFrom(ctx context.Context) bool {
+	return store.IsCrossTenant(ctx) || rs.tenantID == store.TenantIDFromContext(ctx)
+}
+
+func (rs *runState) snapshot() *Run {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	cp := *rs.run
+	cp.Variants = append([]VariantSummary(nil), rs.run.Variants...)
+	cp.Results = append([]CaseResult(nil), rs.run.Results...)
+	return &cp
+}
+
+// runFromTrace rebuilds a run from its trace. Runs interrupted by a restart
+// have no stored results and keep the trace's status.
+func runFromTrace(t *store.TraceData) *Run {
+	run := &Run{}
+	if len(t.Metadata) > 0 && json.Unmarshal(t.Metadata, run) == nil && run.ID != uuid.Nil {
+		return run
+	}
+	return &Run{
+		ID:         t.ID,
+		Name:       strings.TrimPrefix(t.Name, "eval:"),
+		Status:     t.Status,
+		Error:      t.Error,
+		StartedAt:  t.StartTime,
+		FinishedAt: t.EndTime,
+	}
+}
+
+func variantsOf(rvs []resolvedVariant) []Variant {
+	out := make([]Variant, len(rvs))
+	for i, v := range rvs {
+		out[i] = v.Variant
+	}
+	return out
+}
+
+// sortResults orders results by dataset order, then variant order.
+func sortResults(results []CaseResult, cases []Case, variants []resolvedVariant) {
+	caseIdx := make(map[string]int, len(cases))
+	for i, c := range cases {
+		caseIdx[c.ID] = i
+	}
+	varIdx := make(map[string]int, len(variants))
+	for i, v := range variants {
+		varIdx[v.Label] = i
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if caseIdx[a.CaseID] != caseIdx[b.CaseID] {
+			return caseIdx[a.CaseID] < caseIdx[b.CaseID]
+		}
+		return varIdx[a.Variant] < varIdx[b.Variant]
+	})
+}
@@ -0,0 +1,233 @@
+package eval
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxVariants        = 2
+	defaultConcurrency = 4
+	maxConcurrency     = 16
+	maxOutputChars     = 2000 // stored response excerpt per case
+)
+
+// Spec describes an eval run: a dataset replayed against one agent config, or
+// two configs side by side.
+type Spec struct {
+	Name        string     `json:"name"`
+	Variants    []Variant  `json:"variants"`
+	Cases       []Case     `json:"cases"`
+	Concurrency int        `json:"concurrency,omitempty"` // parallel cases (default 4, max 16)
+	Judge       *JudgeSpec `json:"judge,omitempty"`       // default: the first variant's provider and model
+}
+
+// Variant is one agent config under test. Model and Provider override the
+// agent's own for the run, so the same agent can be compared across models.
+type Variant struct {
+	Label    string `json:"label"`
+	Agent    string `json:"agent"` // agent key or UUID
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+}
+
+// JudgeSpec selects the model used by the llm_judge grader.
+type JudgeSpec struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+}
+
+// Run is the state and results of an eval run. ID is also the run's trace ID.
+type Run struct {
+	ID         uuid.UUID        `json:"id"`
+	Name       string           `json:"name"`
+	Status     string           `json:"status"` // trace statuses: running, completed, error, cancelled
+	Error      string           `json:"error,omitempty"`
+	UserID     string           `json:"user_id,omitempty"` // the run's own user, see evalUserID
+	StartedAt  time.Time        `json:"started_at"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+	CaseCount  int              `json:"case_count"`
+	Variants   []VariantSummary `json:"variants"`
+	Comparison *Comparison      `json:"comparison,omitempty"`
+	Results    []CaseResult     `json:"results,omitempty"`
+}
+
+// VariantSummary aggregates a variant's case results.
+type VariantSummary struct {
+	Variant
+	Completed     int     `json:"completed"`
+	Passed        int     `json:"passed"`
+	Failed        int     `json:"failed"`
+	Errors        int     `json:"errors"`
+	PassRate      float64 `json:"pass_rate"`
+	AvgScore      float64 `json:"avg_score"`
+	AvgDurationMS int     `json:"avg_duration_ms"`
+	InputTokens   int     `json:"input_tokens"`
+	OutputTokens  int     `json:"output_tokens"`
+}
+
+// CaseResult is one case run against one variant.
+type CaseResult struct {
+	CaseID       string     `json:"case_id"`
+	Variant      string     `json:"variant"`
+	Pass         bool       `json:"pass"`
+	Score        float64    `json:"score"`
+	Output       string     `json:"output,omitempty"`
+	Error        string     `json:"error,omitempty"`
+	Grades       []Grade    `json:"grades,omitempty"`
+	DurationMS   int        `json:"duration_ms"`
+	InputTokens  int        `json:"input_tokens,omitempty"`
+	OutputTokens int        `json:"output_tokens,omitempty"`
+	RunID        string     `json:"run_id"`
+	TraceID      *uuid.UUID `json:"trace_id,omitempty"`
+}
+
+// Comparison contrasts the second variant (candidate) with the first (baseline).
+type Comparison struct {
+	Baseline      string   `json:"baseline"`
+	Candidate     string   `json:"candidate"`
+	PassRateDelta float64  `json:"pass_rate_delta"`
+	ScoreDelta    float64  `json:"score_delta"`
+	Improved      []string `json:"improved,omitempty"`  // cases failing on baseline, passing on candidate
+	Regressed     []string `json:"regressed,omitempty"` // cases passing on baseline, failing on candidate
+}
+
+// evalUserID is the user a run's cases execute as. It is unique to the run,
+// so memory and profile writes made by the agent never land on a real user.
+func evalUserID(runID uuid.UUID) string { return "eval:" + runID.String() }
+
+// validateSpec normalizes and checks a spec in place.
+func validateSpec(spec *Spec) error {
+	spec.Name = strings.TrimSpace(spec.Name)
+	if spec.Name == "" {
+		spec.Name = "dataset"
+	}
+	if len(spec.Variants) == 0 || len(spec.Variants) > maxVariants {
+		return fmt.Errorf("an eval needs 1 or %d variants, got %d", maxVariants, len(spec.Variants))
+	}
+	labels := make(map[string]bool, len(spec.Variants))
+	for i := range spec.Variants {
+		v := &spec.Variants[i]
+		v.Agent = strings.TrimSpace(v.Agent)
+		if v.Agent == "" {
+			return fmt.Errorf("variant %d: agent is required", i+1)
+		}
+		if v.Label == "" {
+			v.Label = v.Agent
+			if v.Model != "" {
+				v.Label += "/" + v.Model
+			}
+		}
+		if labels[v.Label] {
+			v.Label = fmt.Sprintf("%s#%d", v.Label, i+1)
+		}
+		labels[v.Label] = true
+	}
+	if spec.Concurrency <= 0 {
+		spec.Concurrency = defaultConcurrency
+	}
+	spec.Concurrency = min(spec.Concurrency, maxConcurrency)
+	return ValidateCases(spec.Cases)
+}
+
+// summarize computes per-variant summaries and, for two variants, the comparison.
+func summarize(variants []Variant, results []CaseResult) ([]VariantSummary, *Comparison) {
+	sums := make([]VariantSummary, len(variants))
+	index := make(map[string]int, len(variants))
+	for i, v := range variants {
+		sums[i].Variant = v
+		index[v.Label] = i
+	}
+	scoreSum := make([]float64, len(variants))
+	durSum := make([]int, len(variants))
+	outcome := make([]map[string]bool, len(variants))
+	for i := range outcome {
+		outcome[i] = make(map[string]bool)
+	}
+	for _, r := range results {
+		i, ok := index[r.Variant]
+		if !ok {
+			continue
+		}
+		s := &sums[i]
+		s.Completed++
+		switch {
+		case r.Error != "":
+			s.Errors++
+		case r.Pass:
+			s.Passed++
+		default:
+			s.Failed++
+		}
+		scoreSum[i] += r.Score
+		durSum[i] += r.DurationMS
+		s.InputTokens += r.InputTokens
+		s.OutputTokens += r.OutputTokens
+		outcome[i][r.CaseID] = r.Pass
+	}
+	for i := range sums {
+		if n := sums[i].Completed; n > 0 {
+			sums[i].PassRate = round2(float64(sums[i].Passed) / float64(n))
+			sums[i].AvgScore = round2(scoreSum[i] / float64(n))
+			sums[i].AvgDurationMS = durSum[i] / n
+		}
+	}
+	if len(variants) != 2 {
+		return sums, nil
+	}
+	cmp := &Comparison{
+		Baseline:      variants[0].Label,
+		Candidate:     variants[1].Label,
+		PassRateDelta: round2(sums[1].PassRate - sums[0].PassRate),
+		ScoreDelta:    round2(sums[1].AvgScore - sums[0].AvgScore),
+	}
+	seen := make(map[string]bool)
+	for _, r := range results {
+		if seen[r.CaseID] {
+			continue
+		}
+		base, ok0 := outcome[0][r.CaseID]
+		cand, ok1 := outcome[1][r.CaseID]
+		if !ok0 || !ok1 {
+			continue
+		}
+		seen[r.CaseID] = true
+		switch {
+		case !base && cand:
+			cmp.Improved = append(cmp.Improved, r.CaseID)
+		case base && !cand:
+			cmp.Regressed = append(cmp.Regressed, r.CaseID)
+		}
+	}
+	return sums, cmp
+}
+
+// Preview is a one-line summary used as the run trace's output preview.
+func (r *Run) Preview() string {
+	parts := make([]string, 0, len(r.Variants))
+	for _, v := range r.Variants {
+		parts = append(parts, fmt.Sprintf("%s: %d/%d passed (%.0f%%)", v.Label, v.Passed, r.CaseCount, v.PassRate*100))
+	}
+	out := strings.Join(parts, " | ")
+	if c := r.Comparison; c != nil && len(c.Regressed) > 0 {
+		out += fmt.Sprintf(" | %d regressed", len(c.Regressed))
+	}
+	return out
+}
+
+// Summary returns a copy of the run without per-case results.
+func (r *Run) Summary() *Run {
+	cp := *r
+	cp.Results = nil
+	return &cp
+}
+
+func round2(f float64) float64 {
+	if f < 0 {
+		return -round2(-f)
+	}
+	return float64(int(f*100+0.5)) / 100
+}
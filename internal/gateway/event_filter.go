@@ -111,6 +111,15 @@ func clientCanReceiveEvent(c *Client, event bus.Event) bool {
 		return true
 	}
 
+	// Tool approval events: the requesting user is notified; requests with no
+	// user (cron, system runs) stay admin-only since only admins can decide them.
+	if strings.HasPrefix(event.Name, "tool.approval.") {
+		if uid := extractMapField(event.Payload, "userId"); uid != "" {
+			return uid == c.userID
+		}
+		return false
+	}
+
 	// Zalo personal QR events: admin-only (channel management).
 	if strings.HasPrefix(event.Name, "zalo.personal.") {
 		return false
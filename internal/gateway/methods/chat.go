@@ -22,7 +22,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
 )
 
-// ChatMethods handles chat.send, chat.history, chat.abort, chat.inject.
+// ChatMethods handles chat.send, chat.history, chat.abort, chat.inject, chat.tokens.
 type ChatMethods struct {
 	agents      *agent.Router
 	sessions    store.SessionStore
@@ -30,6 +30,7 @@ type ChatMethods struct {
 	rateLimiter *gateway.RateLimiter
 	eventBus    bus.EventPublisher
 	postTurn    tools.PostTurnProcessor
+	abortRelay  func(runID, sessionKey string) // forwards aborts to other replicas (nil = single node)
 }
 
 func NewChatMethods(agents *agent.Router, sess store.SessionStore, cfg *config.Config, rl *gateway.RateLimiter, eventBus bus.EventPublisher) *ChatMethods {
@@ -41,6 +42,12 @@ func (m *ChatMethods) SetPostTurnProcessor(pt tools.PostTurnProcessor) {
 	m.postTurn = pt
 }
 
+// SetAbortRelay sets the function that forwards chat.abort to other gateway
+// replicas when the run is not active on this node.
+func (m *ChatMethods) SetAbortRelay(fn func(runID, sessionKey string)) {
+	m.abortRelay = fn
+}
+
 // Register adds chat methods to the router.
 func (m *ChatMethods) Register(router *gateway.MethodRouter) {
 	router.Register(protocol.MethodChatSend, m.handleSend)
@@ -48,6 +55,7 @@ func (m *ChatMethods) Register(router *gateway.MethodRouter) {
 	router.Register(protocol.MethodChatAbort, m.handleAbort)
 	router.Register(protocol.MethodChatInject, m.handleInject)
 	router.Register(protocol.MethodChatSessionStatus, m.handleSessionStatus)
+	router.Register(protocol.MethodChatTokens, m.handleTokens)
 }
 
 // handleSessionStatus returns the running state and activity for a session.
@@ -455,9 +463,18 @@ func (m *ChatMethods) handleAbort(ctx context.Context, client *gateway.Client, r
 		abortedIDs = m.agents.AbortRunsForSession(params.SessionKey)
 	}
 
+	// The run may live on another replica: forward the abort (authorization
+	// already passed here; the receiving node re-checks the sessionKey match).
+	forwarded := false
+	if len(abortedIDs) == 0 && m.abortRelay != nil {
+		m.abortRelay(params.RunID, params.SessionKey)
+		forwarded = true
+	}
+
 	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
-		"ok":      true,
-		"aborted": len(abortedIDs) > 0,
-		"runIds":  abortedIDs,
+		"ok":        true,
+		"aborted":   len(abortedIDs) > 0,
+		"runIds":    abortedIDs,
+		"forwarded": forwarded,
 	}))
 }
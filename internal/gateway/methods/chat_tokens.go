@@ -0,0 +1,67 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/sessions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// handleTokens counts the prompt tokens chat.send would send for a session
+// (system prompt, tool definitions, history and the optional draft message),
+// using the agent model's tokenizer. Nothing is sent to the LLM.
+func (m *ChatMethods) handleTokens(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	locale := store.LocaleFromContext(ctx)
+	var params struct {
+		SessionKey string `json:"sessionKey"`
+		AgentID    string `json:"agentId"`
+		Message    string `json:"message"` // draft to include (optional)
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.SessionKey == "" {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgRequired, "sessionKey")))
+		return
+	}
+	if m.sessions.Get(ctx, params.SessionKey) == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "session", params.SessionKey)))
+		return
+	}
+	if !requireSessionOwner(ctx, m.sessions, m.cfg, client, req.ID, params.SessionKey) {
+		return
+	}
+
+	if params.AgentID == "" {
+		params.AgentID, _ = sessions.ParseSessionKey(params.SessionKey)
+	}
+	loop, err := m.agents.Get(ctx, params.AgentID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, err.Error()))
+		return
+	}
+	counter, ok := loop.(agent.PromptTokenCounter)
+	if !ok {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, "agent does not support token counting"))
+		return
+	}
+
+	userID := client.UserID()
+	if userID != "" {
+		ctx = store.WithUserID(ctx, userID)
+	}
+	count, err := counter.CountPromptTokens(ctx, agent.RunRequest{
+		SessionKey: params.SessionKey,
+		Message:    params.Message,
+		Channel:    "ws",
+		ChatID:     userID,
+		UserID:     userID,
+	})
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, err.Error()))
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, count))
+}
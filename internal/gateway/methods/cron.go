@@ -7,8 +7,11 @@ import (
 	"log/slog"
 	"regexp"
 
+	"github.com/google/uuid"
+
 	"github.com/nextlevelbuilder/goclaw/internal/bus"
 	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/cron"
 	"github.com/nextlevelbuilder/goclaw/internal/gateway"
 	"github.com/nextlevelbuilder/goclaw/internal/i18n"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
@@ -71,6 +74,10 @@ func (m *CronMethods) handleCreate(ctx context.Context, client *gateway.Client,
 		WakeHeartbeat  bool               `json:"wakeHeartbeat"`
 		Stateless      *bool              `json:"stateless"` // default true for new crons
 		AgentID        string             `json:"agentId"`
+		WorkflowID     string             `json:"workflowId"` // start this team workflow instead of an agent turn
+		CatchUp        string             `json:"catchUp"`    // missed-run policy: skip, run-once (default), run-all
+		WebhookURL     string             `json:"webhookUrl"` // POST to this URL instead of an agent turn
+		DeliverIf      string             `json:"deliverIf"`  // deliver only when the output matches this condition
 	}
 	if req.Params != nil {
 		json.Unmarshal(req.Params, &params)
@@ -84,10 +91,35 @@ func (m *CronMethods) handleCreate(ctx context.Context, client *gateway.Client,
 		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidSlug, "name")))
 		return
 	}
-	if params.Message == "" {
+	if params.Message == "" && params.WorkflowID == "" && params.WebhookURL == "" {
 		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgMsgRequired)))
 		return
 	}
+	if params.WorkflowID != "" {
+		if _, err := uuid.Parse(params.WorkflowID); err != nil {
+			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "workflowId")))
+			return
+		}
+	}
+
+	if err := store.ValidateCronCatchUp(params.CatchUp); err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+		return
+	}
+	if params.WebhookURL != "" {
+		if err := cron.ValidateWebhookURL(params.WebhookURL); err != nil {
+			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+			return
+		}
+	}
+	if err := cron.ValidateCondition(params.DeliverIf); err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+		return
+	}
+	if !m.canChainAfter(ctx, client, &params.Schedule) {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrUnauthorized, i18n.T(locale, i18n.MsgPermissionDenied, "cron job")))
+		return
+	}
 
 	job, err := m.service.AddJob(ctx, params.Name, params.Schedule, params.Message, params.Deliver, params.DeliverChannel, params.DeliverTo, params.AgentID, client.UserID())
 	if err != nil {
@@ -106,9 +138,29 @@ func (m *CronMethods) handleCreate(ctx context.Context, client *gateway.Client,
 		if params.WakeHeartbeat {
 			patch.WakeHeartbeat = &params.WakeHeartbeat
 		}
-		if updated, pErr := m.service.UpdateJob(ctx, job.ID, patch); pErr == nil {
-			job = updated
+		if params.WorkflowID != "" {
+			patch.WorkflowID = &params.WorkflowID
+		}
+		if params.CatchUp != "" {
+			patch.CatchUp = &params.CatchUp
 		}
+		if params.WebhookURL != "" {
+			patch.WebhookURL = &params.WebhookURL
+		}
+		if params.DeliverIf != "" {
+			patch.DeliverIf = &params.DeliverIf
+		}
+		updated, pErr := m.service.UpdateJob(ctx, job.ID, patch)
+		if pErr != nil {
+			// Don't leave behind a half-configured job (e.g. a webhook job
+			// saved as an agent turn with an empty message).
+			if rmErr := m.service.RemoveJob(ctx, job.ID); rmErr != nil {
+				slog.Warn("cron.create: remove job after failed patch", "job_id", job.ID, "error", rmErr)
+			}
+			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, pErr.Error()))
+			return
+		}
+		job = updated
 	}
 
 	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
@@ -220,6 +272,10 @@ func (m *CronMethods) handleUpdate(ctx context.Context, client *gateway.Client,
 			return
 		}
 	}
+	if !m.canChainAfter(ctx, client, params.Patch.Schedule) {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrUnauthorized, i18n.T(locale, i18n.MsgPermissionDenied, "cron job")))
+		return
+	}
 
 	job, err := m.service.UpdateJob(ctx, jobID, params.Patch)
 	if err != nil {
@@ -237,6 +293,17 @@ func (m *CronMethods) handleUpdate(ctx context.Context, client *gateway.Client,
 	emitAudit(m.eventBus, client, "cron.updated", "cron", jobID)
 }
 
+// canChainAfter reports whether the client may chain a job after the
+// schedule's upstream job: users without full visibility may only chain
+// after their own jobs.
+func (m *CronMethods) canChainAfter(ctx context.Context, client *gateway.Client, schedule *store.CronSchedule) bool {
+	if schedule == nil || schedule.After == "" || canSeeAll(client.Role(), m.cfg.Gateway.OwnerIDs, client.UserID()) {
+		return true
+	}
+	upstream, ok := m.service.GetJob(ctx, schedule.After)
+	return ok && upstream.UserID == client.UserID()
+}
+
 func (m *CronMethods) handleRun(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
 	locale := store.LocaleFromContext(ctx)
 	var params struct {
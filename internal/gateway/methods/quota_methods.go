@@ -3,26 +3,43 @@ package methods
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
 	"github.com/nextlevelbuilder/goclaw/internal/channels"
 	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
 )
 
 // QuotaMethods handles quota.usage — returns per-user quota consumption for the dashboard.
 // Nil-safe: returns {enabled: false} when quotaChecker is nil (quota not configured).
 // When checker is nil but db is available, still queries today's summary from traces.
+// Also handles quota.budget — USD budget consumption and remaining headroom.
 type QuotaMethods struct {
 	checker *channels.QuotaChecker
 	db      *sql.DB
+
+	budget   *budget.Enforcer
+	agents   store.AgentStore
+	ownerIDs []string
 }
 
 func NewQuotaMethods(checker *channels.QuotaChecker, db *sql.DB) *QuotaMethods {
 	return &QuotaMethods{checker: checker, db: db}
 }
 
+// SetBudget enables quota.budget reporting. ownerIDs may view any user's budget.
+func (m *QuotaMethods) SetBudget(enforcer *budget.Enforcer, agents store.AgentStore, ownerIDs []string) {
+	m.budget = enforcer
+	m.agents = agents
+	m.ownerIDs = ownerIDs
+}
+
 func (m *QuotaMethods) Register(router *gateway.MethodRouter) {
 	router.Register(protocol.MethodQuotaUsage, m.handleUsage)
+	router.Register(protocol.MethodQuotaBudget, m.handleBudget)
 }
 
 func (m *QuotaMethods) handleUsage(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
@@ -42,3 +59,30 @@ func (m *QuotaMethods) handleUsage(ctx context.Context, client *gateway.Client,
 	result := m.checker.Usage(ctx)
 	client.SendResponse(protocol.NewOKResponse(req.ID, result))
 }
+
+// handleBudget reports USD budget consumption for the tenant and, optionally,
+// an agent and channel user. Non-admin callers only see their own user budget.
+func (m *QuotaMethods) handleBudget(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params struct {
+		AgentID string `json:"agentId"`
+		UserID  string `json:"userId"`
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if !canSeeAll(client.Role(), m.ownerIDs, client.UserID()) {
+		params.UserID = client.UserID()
+	}
+
+	var agents budget.AgentLookup
+	if m.agents != nil {
+		agents = m.agents
+	}
+	report, err := budget.BuildReport(ctx, m.budget, agents, params.AgentID, params.UserID)
+	if err != nil {
+		locale := store.LocaleFromContext(ctx)
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "agent", params.AgentID)))
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, report))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/i18n"
 	"github.com/nextlevelbuilder/goclaw/internal/permissions"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/workflow"
 	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
 )
 
@@ -24,6 +25,9 @@ type TeamsMethods struct {
 	msgBus      *bus.MessageBus      // for pub/sub cache invalidation
 	eventBus    bus.EventPublisher
 	dataDir string // workspace data directory for resolving file paths
+
+	workflowStore  store.WorkflowStore // optional: teams.workflows.*
+	workflowEngine *workflow.Engine
 }
 
 func NewTeamsMethods(teamStore store.TeamStore, agentStore store.AgentStore, linkStore store.AgentLinkStore, agentRouter *agent.Router, msgBus *bus.MessageBus, eventBus bus.EventPublisher, dataDir string) *TeamsMethods {
@@ -64,6 +68,12 @@ func (m *TeamsMethods) Register(router *gateway.MethodRouter) {
 
 	// Task detail handlers
 	m.RegisterTasks(router)
+
+	// Task template handlers
+	m.RegisterTaskTemplates(router)
+
+	// Workflow handlers
+	m.RegisterWorkflows(router)
 }
 
 // --- List ---
@@ -0,0 +1,344 @@
+package methods
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// RegisterTaskTemplates registers teams.tasks.templates.* RPC handlers.
+func (m *TeamsMethods) RegisterTaskTemplates(router *gateway.MethodRouter) {
+	router.Register(protocol.MethodTeamsTaskTemplatesList, m.handleTemplateList)
+	router.Register(protocol.MethodTeamsTaskTemplatesGet, m.handleTemplateGet)
+	router.Register(protocol.MethodTeamsTaskTemplatesCreate, m.handleTemplateCreate)
+	router.Register(protocol.MethodTeamsTaskTemplatesUpdate, m.handleTemplateUpdate)
+	router.Register(protocol.MethodTeamsTaskTemplatesDelete, m.handleTemplateDelete)
+	router.Register(protocol.MethodTeamsTaskTemplatesRun, m.handleTemplateRun)
+}
+
+// taskTemplateParams is shared by all template methods. Pointer fields are
+// optional: on update only the fields present are changed.
+type taskTemplateParams struct {
+	TeamID       string                       `json:"teamId"`
+	TemplateID   string                       `json:"templateId"`
+	Name         *string                      `json:"name"`
+	Subject      *string                      `json:"subject"`
+	Description  *string                      `json:"description"`
+	OwnerAgentID *string                      `json:"ownerAgentId"`
+	Priority     *int                         `json:"priority"`
+	Subtasks     *[]taskTemplateSubtaskParams `json:"subtasks"`
+	Schedule     *string                      `json:"schedule"` // cron expression; "" clears
+	Timezone     *string                      `json:"timezone"`
+	Enabled      *bool                        `json:"enabled"`
+}
+
+type taskTemplateSubtaskParams struct {
+	Key          string   `json:"key"`
+	Subject      string   `json:"subject"`
+	Description  string   `json:"description"`
+	OwnerAgentID string   `json:"ownerAgentId"` // "" = template owner
+	Priority     int      `json:"priority"`
+	BlockedBy    []string `json:"blockedBy"` // sibling subtask keys
+}
+
+// --- Template List ---
+
+func (m *TeamsMethods) handleTemplateList(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params taskTemplateParams
+	locale, ok := m.parseTaskParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	teamID, err := uuid.Parse(params.TeamID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "teamId")))
+		return
+	}
+
+	tpls, err := m.teamStore.ListTaskTemplates(ctx, teamID)
+	if err != nil {
+		slog.Warn("teams.tasks.templates.list failed", "team_id", teamID, "error", err)
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, "")))
+		return
+	}
+	if tpls == nil {
+		tpls = []store.TeamTaskTemplateData{}
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"templates": tpls,
+		"count":     len(tpls),
+	}))
+}
+
+// --- Template Get ---
+
+func (m *TeamsMethods) handleTemplateGet(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params taskTemplateParams
+	locale, ok := m.parseTaskParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	_, tpl, ok := m.loadTemplate(ctx, client, req, locale, params)
+	if !ok {
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"template": tpl}))
+}
+
+// --- Template Create ---
+
+func (m *TeamsMethods) handleTemplateCreate(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params taskTemplateParams
+	locale, ok := m.parseTaskParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	teamID, err := uuid.Parse(params.TeamID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "teamId")))
+		return
+	}
+	team, err := m.teamStore.GetTeam(ctx, teamID)
+	if err != nil || team == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "team", params.TeamID)))
+		return
+	}
+
+	tpl := &store.TeamTaskTemplateData{
+		TeamID:    teamID,
+		Enabled:   true,
+		CreatedBy: client.UserID(),
+	}
+	if err := m.applyTemplateParams(ctx, team, tpl, params); err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+		return
+	}
+
+	if err := m.teamStore.CreateTaskTemplate(ctx, tpl); err != nil {
+		slog.Warn("teams.tasks.templates.create failed", "team_id", teamID, "error", err)
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, "")))
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"template": tpl}))
+}
+
+// --- Template Update ---
+
+func (m *TeamsMethods) handleTemplateUpdate(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params taskTemplateParams
+	locale, ok := m.parseTaskParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	team, tpl, ok := m.loadTemplate(ctx, client, req, locale, params)
+	if !ok {
+		return
+	}
+	if err := m.applyTemplateParams(ctx, team, tpl, params); err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+		return
+	}
+	if err := m.teamStore.UpdateTaskTemplate(ctx, tpl); err != nil {
+		slog.Warn("teams.tasks.templates.update failed", "template_id", tpl.ID, "error", err)
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, "")))
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"template": tpl}))
+}
+
+// --- Template Delete ---
+
+func (m *TeamsMethods) handleTemplateDelete(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params taskTemplateParams
+	locale, ok := m.parseTaskParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	team, tpl, ok := m.loadTemplate(ctx, client, req, locale, params)
+	if !ok {
+		return
+	}
+	if err := m.teamStore.DeleteTaskTemplate(ctx, tpl.ID, team.ID); err != nil {
+		slog.Warn("teams.tasks.templates.delete failed", "template_id", tpl.ID, "error", err)
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, "")))
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"ok": true}))
+}
+
+// --- Template Run (instantiate now) ---
+
+func (m *TeamsMethods) handleTemplateRun(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params taskTemplateParams
+	locale, ok := m.parseTaskParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	team, tpl, ok := m.loadTemplate(ctx, client, req, locale, params)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	created, err := tools.InstantiateTaskTemplate(ctx, m.teamStore, team, tpl, tools.TemplateRunOptions{
+		UserID:  client.UserID(),
+		Channel: "dashboard",
+		ChatID:  team.ID.String(),
+		Now:     now,
+	})
+	if err != nil {
+		slog.Warn("teams.tasks.templates.run failed", "template_id", tpl.ID, "created", len(created), "error", err)
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+		return
+	}
+	// A manual run does not move the schedule.
+	if err := m.teamStore.MarkTaskTemplateRun(ctx, tpl.ID, now, tpl.NextRunAt); err != nil {
+		slog.Warn("teams.tasks.templates.run mark failed", "template_id", tpl.ID, "error", err)
+	}
+
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"tasks": created, "count": len(created)}))
+
+	if m.msgBus == nil {
+		return
+	}
+	// Dispatch one unblocked task per owner; the rest follow as tasks complete.
+	dispatched := make(map[uuid.UUID]bool)
+	for _, task := range created {
+		m.msgBus.Broadcast(taskBusEvent(protocol.EventTeamTaskCreated, protocol.TeamTaskEventPayload{
+			TeamID:     team.ID.String(),
+			TaskID:     task.ID.String(),
+			TaskNumber: task.TaskNumber,
+			Subject:    task.Subject,
+			Status:     task.Status,
+			UserID:     client.UserID(),
+			Channel:    task.Channel,
+			ChatID:     task.ChatID,
+			Timestamp:  taskNowUTC(),
+			ActorType:  "human",
+			ActorID:    client.UserID(),
+		}))
+		if task.Status != store.TeamTaskStatusPending || task.OwnerAgentID == nil || dispatched[*task.OwnerAgentID] {
+			continue
+		}
+		ownerID := *task.OwnerAgentID
+		if err := m.teamStore.AssignTask(ctx, task.ID, ownerID, team.ID); err != nil {
+			slog.Warn("teams.tasks.templates.run assign failed", "task_id", task.ID, "error", err)
+			continue
+		}
+		dispatched[ownerID] = true
+		m.dispatchTaskToAgent(ctx, task, task.ID, team.ID, ownerID, client.UserID())
+	}
+}
+
+// loadTemplate resolves teamId + templateId and verifies the template belongs
+// to the team (prevent IDOR). Returns false if an error response was sent.
+func (m *TeamsMethods) loadTemplate(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame, locale string, params taskTemplateParams) (*store.TeamData, *store.TeamTaskTemplateData, bool) {
+	teamID, err := uuid.Parse(params.TeamID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "teamId")))
+		return nil, nil, false
+	}
+	templateID, err := uuid.Parse(params.TemplateID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "templateId")))
+		return nil, nil, false
+	}
+	team, err := m.teamStore.GetTeam(ctx, teamID)
+	if err != nil || team == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "team", params.TeamID)))
+		return nil, nil, false
+	}
+	tpl, err := m.teamStore.GetTaskTemplate(ctx, templateID)
+	if err != nil {
+		if errors.Is(err, store.ErrTaskTemplateNotFound) {
+			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "template", params.TemplateID)))
+		} else {
+			slog.Warn("teams.tasks.templates get failed", "template_id", templateID, "error", err)
+			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, "")))
+		}
+		return nil, nil, false
+	}
+	if tpl.TeamID != teamID {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "template", params.TemplateID)))
+		return nil, nil, false
+	}
+	return team, tpl, true
+}
+
+// applyTemplateParams copies the present params onto tpl, then validates the
+// template, its owners and its schedule (setting NextRunAt).
+func (m *TeamsMethods) applyTemplateParams(ctx context.Context, team *store.TeamData, tpl *store.TeamTaskTemplateData, params taskTemplateParams) error {
+	if params.Name != nil {
+		tpl.Name = *params.Name
+	}
+	if params.Subject != nil {
+		tpl.Subject = *params.Subject
+	}
+	if params.Description != nil {
+		if len(*params.Description) > maxCommentLength {
+			return errors.New("description too long")
+		}
+		tpl.Description = *params.Description
+	}
+	if params.OwnerAgentID != nil {
+		id, err := uuid.Parse(*params.OwnerAgentID)
+		if err != nil {
+			return errors.New("invalid ownerAgentId")
+		}
+		tpl.OwnerAgentID = &id
+	}
+	if params.Priority != nil {
+		tpl.Priority = *params.Priority
+	}
+	if params.Schedule != nil {
+		tpl.Schedule = *params.Schedule
+	}
+	if params.Timezone != nil {
+		tpl.Timezone = *params.Timezone
+	}
+	if params.Enabled != nil {
+		tpl.Enabled = *params.Enabled
+	}
+	if params.Subtasks != nil {
+		subtasks := make([]store.TeamTaskTemplateSubtask, 0, len(*params.Subtasks))
+		for _, p := range *params.Subtasks {
+			sub := store.TeamTaskTemplateSubtask{
+				Key:         p.Key,
+				Subject:     p.Subject,
+				Description: p.Description,
+				Priority:    p.Priority,
+				BlockedBy:   p.BlockedBy,
+			}
+			if p.OwnerAgentID != "" {
+				id, err := uuid.Parse(p.OwnerAgentID)
+				if err != nil {
+					return errors.New("invalid subtask ownerAgentId")
+				}
+				sub.OwnerAgentID = &id
+			}
+			subtasks = append(subtasks, sub)
+		}
+		tpl.Subtasks = subtasks
+	}
+
+	if err := tools.ValidateTaskTemplate(tpl); err != nil {
+		return err
+	}
+	members, err := m.teamStore.ListMembers(ctx, team.ID)
+	if err != nil {
+		return err
+	}
+	if err := tools.ValidateTemplateOwners(tpl, team, members); err != nil {
+		return err
+	}
+	return tools.ScheduleTaskTemplate(tpl, time.Now())
+}
@@ -456,5 +456,6 @@ type taskCreateParams struct {
 	AssignTo    string `json:"assignTo"` // optional agent UUID — assign immediately after creation
 	Channel     string `json:"channel"`  // optional scope — defaults to "dashboard"
 	ChatID      string `json:"chatId"`   // optional scope — defaults to teamID
+	DueAt       string `json:"dueAt"`    // optional RFC 3339 / date / relative ("4h") — defaults to the team's priority SLA
 }
 
@@ -2,9 +2,11 @@ package methods
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/gateway"
 	"github.com/nextlevelbuilder/goclaw/internal/i18n"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
 	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
 )
 
@@ -55,6 +58,17 @@ func (m *TeamsMethods) handleTaskCreate(ctx context.Context, client *gateway.Cli
 		cid = teamID.String()
 	}
 
+	now := time.Now()
+	var dueAt *time.Time
+	if params.DueAt != "" {
+		d, err := tools.ParseDueAt(params.DueAt, now)
+		if err != nil {
+			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+			return
+		}
+		dueAt = &d
+	}
+
 	task := &store.TeamTaskData{
 		TeamID:      teamID,
 		Subject:     params.Subject,
@@ -66,6 +80,11 @@ func (m *TeamsMethods) handleTaskCreate(ctx context.Context, client *gateway.Cli
 		Channel:     ch,
 		ChatID:      cid,
 	}
+	var teamSettings json.RawMessage
+	if team, err := m.teamStore.GetTeam(ctx, teamID); err == nil && team != nil {
+		teamSettings = team.Settings
+	}
+	tools.ParseSLAConfig(teamSettings).ApplyDue(task, dueAt, now)
 
 	if err := m.teamStore.CreateTask(ctx, task); err != nil {
 		slog.Warn("teams.tasks.create failed", "team_id", teamID, "error", err)
@@ -0,0 +1,412 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/workflow"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// SetWorkflows enables the teams.workflows.* methods.
+func (m *TeamsMethods) SetWorkflows(ws store.WorkflowStore, engine *workflow.Engine) {
+	m.workflowStore = ws
+	m.workflowEngine = engine
+}
+
+// RegisterWorkflows registers teams.workflows.* RPC handlers.
+func (m *TeamsMethods) RegisterWorkflows(router *gateway.MethodRouter) {
+	router.Register(protocol.MethodTeamsWorkflowsList, m.handleWorkflowList)
+	router.Register(protocol.MethodTeamsWorkflowsGet, m.handleWorkflowGet)
+	router.Register(protocol.MethodTeamsWorkflowsCreate, m.handleWorkflowCreate)
+	router.Register(protocol.MethodTeamsWorkflowsUpdate, m.handleWorkflowUpdate)
+	router.Register(protocol.MethodTeamsWorkflowsDelete, m.handleWorkflowDelete)
+	router.Register(protocol.MethodTeamsWorkflowsRun, m.handleWorkflowRun)
+	router.Register(protocol.MethodTeamsWorkflowRunsList, m.handleWorkflowRunsList)
+	router.Register(protocol.MethodTeamsWorkflowRunsGet, m.handleWorkflowRunsGet)
+	router.Register(protocol.MethodTeamsWorkflowRunsCancel, m.handleWorkflowRunsCancel)
+}
+
+// workflowParams is shared by all workflow methods. Pointer fields are
+// optional: on update only the fields present are changed.
+type workflowParams struct {
+	TeamID      string            `json:"teamId"`
+	WorkflowID  string            `json:"workflowId"`
+	RunID       string            `json:"runId"`
+	Name        *string           `json:"name"`
+	Description *string           `json:"description"`
+	Definition  json.RawMessage   `json:"definition"` // JSON object, or a YAML/JSON document as a string
+	Enabled     *bool             `json:"enabled"`
+	Input       map[string]string `json:"input"`
+	Limit       int               `json:"limit"`
+	Reason      string            `json:"reason"`
+}
+
+// parseWorkflowParams is parseTaskParams plus the workflow store check.
+func (m *TeamsMethods) parseWorkflowParams(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame, dst *workflowParams) (string, bool) {
+	locale, ok := m.parseTaskParams(ctx, client, req, dst)
+	if !ok {
+		return locale, false
+	}
+	if m.workflowStore == nil || m.workflowEngine == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, "workflows are not configured"))
+		return locale, false
+	}
+	return locale, true
+}
+
+// --- Workflow List ---
+
+func (m *TeamsMethods) handleWorkflowList(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params workflowParams
+	locale, ok := m.parseWorkflowParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	teamID, err := uuid.Parse(params.TeamID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "teamId")))
+		return
+	}
+	wfs, err := m.workflowStore.ListWorkflows(ctx, teamID)
+	if err != nil {
+		slog.Warn("teams.workflows.list failed", "team_id", teamID, "error", err)
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, "")))
+		return
+	}
+	if wfs == nil {
+		wfs = []store.WorkflowData{}
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"workflows": wfs,
+		"count":     len(wfs),
+	}))
+}
+
+// --- Workflow Get ---
+
+func (m *TeamsMethods) handleWorkflowGet(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params workflowParams
+	locale, ok := m.parseWorkflowParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	_, wf, ok := m.loadWorkflow(ctx, client, req, locale, params)
+	if !ok {
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"workflow": wf}))
+}
+
+// --- Workflow Create ---
+
+func (m *TeamsMethods) handleWorkflowCreate(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params workflowParams
+	locale, ok := m.parseWorkflowParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	teamID, err := uuid.Parse(params.TeamID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "teamId")))
+		return
+	}
+	team, err := m.teamStore.GetTeam(ctx, teamID)
+	if err != nil || team == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "team", params.TeamID)))
+		return
+	}
+	if len(params.Definition) == 0 {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgRequired, "definition")))
+		return
+	}
+
+	wf := &store.WorkflowData{
+		TeamID:    teamID,
+		Enabled:   true,
+		CreatedBy: client.UserID(),
+	}
+	if err := m.applyWorkflowParams(ctx, team, wf, params); err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+		return
+	}
+	if err := m.workflowStore.CreateWorkflow(ctx, wf); err != nil {
+		slog.Warn("teams.workflows.create failed", "team_id", teamID, "error", err)
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, "")))
+		return
+	}
+	emitAudit(m.eventBus, client, "team.workflow.created", "team_workflow", wf.ID.String())
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"workflow": wf}))
+}
+
+// --- Workflow Update ---
+
+func (m *TeamsMethods) handleWorkflowUpdate(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params workflowParams
+	locale, ok := m.parseWorkflowParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	team, wf, ok := m.loadWorkflow(ctx, client, req, locale, params)
+	if !ok {
+		return
+	}
+	if err := m.applyWorkflowParams(ctx, team, wf, params); err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+		return
+	}
+	if err := m.workflowStore.UpdateWorkflow(ctx, wf); err != nil {
+		slog.Warn("teams.workflows.update failed", "workflow_id", wf.ID, "error", err)
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, "")))
+		return
+	}
+	emitAudit(m.eventBus, client, "team.workflow.updated", "team_workflow", wf.ID.String())
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"workflow": wf}))
+}
+
+// --- Workflow Delete ---
+
+func (m *TeamsMethods) handleWorkflowDelete(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params workflowParams
+	locale, ok := m.parseWorkflowParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	team, wf, ok := m.loadWorkflow(ctx, client, req, locale, params)
+	if !ok {
+		return
+	}
+	// Stop in-flight runs first: their rows go with the workflow.
+	if runs, err := m.workflowStore.ListWorkflowRuns(ctx, team.ID, wf.ID, 200); err == nil {
+		for _, run := range runs {
+			if run.Status == store.WorkflowRunStatusRunning {
+				_, _ = m.workflowEngine.Cancel(ctx, run.ID, "workflow deleted")
+			}
+		}
+	}
+	if err := m.workflowStore.DeleteWorkflow(ctx, wf.ID, team.ID); err != nil {
+		slog.Warn("teams.workflows.delete failed", "workflow_id", wf.ID, "error", err)
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, "")))
+		return
+	}
+	emitAudit(m.eventBus, client, "team.workflow.deleted", "team_workflow", wf.ID.String())
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"ok": true}))
+}
+
+// --- Workflow Run (start now) ---
+
+func (m *TeamsMethods) handleWorkflowRun(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params workflowParams
+	locale, ok := m.parseWorkflowParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	_, wf, ok := m.loadWorkflow(ctx, client, req, locale, params)
+	if !ok {
+		return
+	}
+	run, err := m.workflowEngine.Start(ctx, wf, workflow.RunOptions{
+		Trigger:     store.WorkflowTriggerManual,
+		TriggeredBy: client.UserID(),
+		Input:       params.Input,
+	})
+	if err != nil && run == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+		return
+	}
+	if err != nil {
+		// The run exists but could not advance; it has been marked failed.
+		slog.Warn("teams.workflows.run failed", "workflow_id", wf.ID, "run_id", run.ID, "error", err)
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"run": run}))
+}
+
+// --- Runs List ---
+
+func (m *TeamsMethods) handleWorkflowRunsList(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params workflowParams
+	locale, ok := m.parseWorkflowParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	teamID, err := uuid.Parse(params.TeamID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "teamId")))
+		return
+	}
+	workflowID := uuid.Nil
+	if params.WorkflowID != "" {
+		if workflowID, err = uuid.Parse(params.WorkflowID); err != nil {
+			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "workflowId")))
+			return
+		}
+	}
+	runs, err := m.workflowStore.ListWorkflowRuns(ctx, teamID, workflowID, params.Limit)
+	if err != nil {
+		slog.Warn("teams.workflows.runs.list failed", "team_id", teamID, "error", err)
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, "")))
+		return
+	}
+	if runs == nil {
+		runs = []store.WorkflowRunData{}
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"runs":  runs,
+		"count": len(runs),
+	}))
+}
+
+// --- Runs Get ---
+
+func (m *TeamsMethods) handleWorkflowRunsGet(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params workflowParams
+	locale, ok := m.parseWorkflowParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	run, ok := m.loadWorkflowRun(ctx, client, req, locale, params)
+	if !ok {
+		return
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"run": run}))
+}
+
+// --- Runs Cancel ---
+
+func (m *TeamsMethods) handleWorkflowRunsCancel(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	var params workflowParams
+	locale, ok := m.parseWorkflowParams(ctx, client, req, &params)
+	if !ok {
+		return
+	}
+	run, ok := m.loadWorkflowRun(ctx, client, req, locale, params)
+	if !ok {
+		return
+	}
+	reason := params.Reason
+	if reason == "" {
+		reason = "Cancelled by " + client.UserID()
+	}
+	run, err := m.workflowEngine.Cancel(ctx, run.ID, reason)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, err.Error()))
+		return
+	}
+	emitAudit(m.eventBus, client, "team.workflow.run_cancelled", "team_workflow_run", run.ID.String())
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{"run": run}))
+}
+
+// loadWorkflow resolves teamId + workflowId and verifies the workflow belongs
+// to the team (prevent IDOR). Returns false if an error response was sent.
+func (m *TeamsMethods) loadWorkflow(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame, locale string, params workflowParams) (*store.TeamData, *store.WorkflowData, bool) {
+	teamID, err := uuid.Parse(params.TeamID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "teamId")))
+		return nil, nil, false
+	}
+	workflowID, err := uuid.Parse(params.WorkflowID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "workflowId")))
+		return nil, nil, false
+	}
+	team, err := m.teamStore.GetTeam(ctx, teamID)
+	if err != nil || team == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "team", params.TeamID)))
+		return nil, nil, false
+	}
+	wf, err := m.workflowStore.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		if errors.Is(err, store.ErrWorkflowNotFound) {
+			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "workflow", params.WorkflowID)))
+		} else {
+			slog.Warn("teams.workflows get failed", "workflow_id", workflowID, "error", err)
+			client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInternal, i18n.T(locale, i18n.MsgInternalError, "")))
+		}
+		return nil, nil, false
+	}
+	if wf.TeamID != teamID {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "workflow", params.WorkflowID)))
+		return nil, nil, false
+	}
+	return team, wf, true
+}
+
+// loadWorkflowRun resolves teamId + runId, scoped to the team like loadWorkflow.
+func (m *TeamsMethods) loadWorkflowRun(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame, locale string, params workflowParams) (*store.WorkflowRunData, bool) {
+	teamID, err := uuid.Parse(params.TeamID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "teamId")))
+		return nil, false
+	}
+	runID, err := uuid.Parse(params.RunID)
+	if err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgInvalidID, "runId")))
+		return nil, false
+	}
+	run, err := m.workflowStore.GetWorkflowRun(ctx, runID)
+	if err != nil || run.TeamID != teamID {
+		if err != nil && !errors.Is(err, store.ErrWorkflowRunNotFound) {
+			slog.Warn("teams.workflows.runs get failed", "run_id", runID, "error", err)
+		}
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "workflow run", params.RunID)))
+		return nil, false
+	}
+	return run, true
+}
+
+// applyWorkflowParams copies the present params onto wf, then validates the
+// definition and checks every step agent is a team member.
+func (m *TeamsMethods) applyWorkflowParams(ctx context.Context, team *store.TeamData, wf *store.WorkflowData, params workflowParams) error {
+	if params.Name != nil {
+		name := strings.TrimSpace(*params.Name)
+		if name == "" || len(name) > 100 {
+			return errors.New("workflow name is required (max 100 chars)")
+		}
+		if existing, err := m.workflowStore.GetWorkflowByName(ctx, team.ID, name); err == nil && existing.ID != wf.ID {
+			return fmt.Errorf("a workflow named %q already exists in this team", name)
+		}
+		wf.Name = name
+	}
+	if wf.Name == "" {
+		return errors.New("workflow name is required")
+	}
+	if params.Description != nil {
+		if len(*params.Description) > maxCommentLength {
+			return errors.New("description too long")
+		}
+		wf.Description = *params.Description
+	}
+	if params.Enabled != nil {
+		wf.Enabled = *params.Enabled
+	}
+	source := []byte(wf.Definition)
+	if len(params.Definition) > 0 {
+		source = params.Definition
+		var text string
+		if json.Unmarshal(params.Definition, &text) == nil {
+			source = []byte(text) // YAML (or JSON) document passed as a string
+		}
+	}
+	def, err := workflow.Parse(source)
+	if err != nil {
+		return err
+	}
+	members, err := m.teamStore.ListMembers(ctx, team.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := workflow.ResolveAgents(def, team, members); err != nil {
+		return err
+	}
+	wf.Definition = def.JSON()
+	return nil
+}
@@ -0,0 +1,96 @@
+package methods
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/gateway"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// ToolApprovalMethods handles tool.approval.list, tool.approval.approve, tool.approval.deny.
+type ToolApprovalMethods struct {
+	manager  *tools.ToolApprovalManager
+	eventBus bus.EventPublisher
+}
+
+func NewToolApprovalMethods(manager *tools.ToolApprovalManager, eventBus bus.EventPublisher) *ToolApprovalMethods {
+	return &ToolApprovalMethods{manager: manager, eventBus: eventBus}
+}
+
+func (m *ToolApprovalMethods) Register(router *gateway.MethodRouter) {
+	router.Register(protocol.MethodToolApprovalsList, m.handleList)
+	router.Register(protocol.MethodToolApprovalsApprove, m.handleApprove)
+	router.Register(protocol.MethodToolApprovalsDeny, m.handleDeny)
+}
+
+// visible reports whether the request belongs to the caller's tenant.
+func (m *ToolApprovalMethods) visible(ctx context.Context, req *tools.ToolApprovalRequest) bool {
+	return store.IsCrossTenant(ctx) || req.TenantID == store.TenantIDFromContext(ctx)
+}
+
+func (m *ToolApprovalMethods) handleList(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	items := []*tools.ToolApprovalRequest{}
+	if m.manager != nil {
+		for _, pa := range m.manager.ListPending() {
+			if m.visible(ctx, pa) {
+				items = append(items, pa)
+			}
+		}
+	}
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"pending": items,
+	}))
+}
+
+func (m *ToolApprovalMethods) handleApprove(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	m.resolve(ctx, client, req, tools.ApprovalAllowOnce)
+}
+
+func (m *ToolApprovalMethods) handleDeny(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame) {
+	m.resolve(ctx, client, req, tools.ApprovalDeny)
+}
+
+func (m *ToolApprovalMethods) resolve(ctx context.Context, client *gateway.Client, req *protocol.RequestFrame, decision tools.ApprovalDecision) {
+	locale := store.LocaleFromContext(ctx)
+	if m.manager == nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgToolApprovalDisabled)))
+		return
+	}
+
+	var params struct {
+		ID string `json:"id"`
+	}
+	if req.Params != nil {
+		json.Unmarshal(req.Params, &params)
+	}
+	if params.ID == "" {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrInvalidRequest, i18n.T(locale, i18n.MsgRequired, "id")))
+		return
+	}
+
+	pending := m.manager.Get(params.ID)
+	if pending == nil || !m.visible(ctx, pending) {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, i18n.T(locale, i18n.MsgNotFound, "approval", params.ID)))
+		return
+	}
+
+	// The manager audits the decision together with the tool arguments.
+	if err := m.manager.Resolve(params.ID, tools.ToolApprovalResolution{
+		Decision:   decision,
+		ResolvedBy: client.UserID(),
+		Via:        "rpc",
+	}); err != nil {
+		client.SendResponse(protocol.NewErrorResponse(req.ID, protocol.ErrNotFound, err.Error()))
+		return
+	}
+
+	client.SendResponse(protocol.NewOKResponse(req.ID, map[string]any{
+		"resolved": true,
+		"decision": string(decision),
+	}))
+}
@@ -302,6 +302,12 @@ func (s *workerTeamStoreStub) MarkInReviewStaleTasks(context.Context, time.Time)
 func (s *workerTeamStoreStub) FixOrphanedBlockedTasks(context.Context) ([]store.RecoveredTaskInfo, error) {
 	return nil, nil
 }
+func (s *workerTeamStoreStub) ListAllOverdueTasks(context.Context) ([]store.RecoveredTaskInfo, error) {
+	return nil, nil
+}
+func (s *workerTeamStoreStub) MarkTaskEscalated(context.Context, uuid.UUID, *time.Time) error {
+	return nil
+}
 func (s *workerTeamStoreStub) SetTaskFollowup(context.Context, uuid.UUID, uuid.UUID, time.Time, int, string, string, string) error {
 	return nil
 }
@@ -334,6 +340,27 @@ func (s *workerTeamStoreStub) ListUserTeams(context.Context, string) ([]store.Te
 func (s *workerTeamStoreStub) HasTeamAccess(context.Context, uuid.UUID, string) (bool, error) {
 	return false, nil
 }
+func (s *workerTeamStoreStub) CreateTaskTemplate(context.Context, *store.TeamTaskTemplateData) error {
+	return nil
+}
+func (s *workerTeamStoreStub) GetTaskTemplate(context.Context, uuid.UUID) (*store.TeamTaskTemplateData, error) {
+	return nil, store.ErrTaskTemplateNotFound
+}
+func (s *workerTeamStoreStub) ListTaskTemplates(context.Context, uuid.UUID) ([]store.TeamTaskTemplateData, error) {
+	return nil, nil
+}
+func (s *workerTeamStoreStub) UpdateTaskTemplate(context.Context, *store.TeamTaskTemplateData) error {
+	return nil
+}
+func (s *workerTeamStoreStub) DeleteTaskTemplate(context.Context, uuid.UUID, uuid.UUID) error {
+	return nil
+}
+func (s *workerTeamStoreStub) ListDueTaskTemplates(context.Context, time.Time) ([]store.TeamTaskTemplateData, error) {
+	return nil, nil
+}
+func (s *workerTeamStoreStub) MarkTaskTemplateRun(context.Context, uuid.UUID, time.Time, *time.Time) error {
+	return nil
+}
 
 func TestWorkersRegister_StoresOnlineWorker(t *testing.T) {
 	tenantID := uuid.New()
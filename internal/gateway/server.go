@@ -172,6 +172,9 @@ func (s *Server) BuildMux() *http.ServeMux {
 	}
 	mux.Handle("/v1/responses", responsesHandler)
 
+	// OpenAI-compatible model listing (agents exposed as models)
+	httpapi.NewModelsHandler(s.agentStore).RegisterRoutes(mux)
+
 	// Direct tool invocation
 	if s.tools != nil {
 		toolsHandler := httpapi.NewToolsInvokeHandler(s.tools, s.agentStore)
@@ -505,6 +508,22 @@ func (s *Server) SetSystemConfigsHandler(h *httpapi.SystemConfigsHandler) {
 // SetUsageHandler sets the usage analytics handler.
 func (s *Server) SetUsageHandler(h *httpapi.UsageHandler) { s.handlers = append(s.handlers, h) }
 
+// SetEmbeddingsHandler sets the OpenAI-compatible embeddings handler.
+func (s *Server) SetEmbeddingsHandler(h *httpapi.EmbeddingsHandler) {
+	s.handlers = append(s.handlers, h)
+}
+
+// SetBudgetHandler sets the USD budget status handler.
+func (s *Server) SetBudgetHandler(h *httpapi.BudgetHandler) { s.handlers = append(s.handlers, h) }
+
+// SetKnowledgeBasesHandler sets the document knowledge base handler.
+func (s *Server) SetKnowledgeBasesHandler(h *httpapi.KnowledgeBasesHandler) {
+	s.handlers = append(s.handlers, h)
+}
+
+// SetEvalsHandler sets the agent evaluation handler.
+func (s *Server) SetEvalsHandler(h *httpapi.EvalsHandler) { s.handlers = append(s.handlers, h) }
+
 // SetDocsHandler sets the OpenAPI spec + Swagger UI handler.
 func (s *Server) SetDocsHandler(h *httpapi.DocsHandler) { s.handlers = append(s.handlers, h) }
 
@@ -562,6 +581,19 @@ func (s *Server) BroadcastEvent(event protocol.EventFrame) {
 	}
 }
 
+// DeliverEvent sends a bus event straight to connected WebSocket clients,
+// applying the same per-client filtering as bus subscriptions but skipping
+// in-process subscribers. Used for events relayed from other gateway replicas.
+func (s *Server) DeliverEvent(event bus.Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, client := range s.clients {
+		if clientCanReceiveEvent(client, event) {
+			client.SendEvent(*protocol.NewEvent(event.Name, event.Payload))
+		}
+	}
+}
+
 // DisconnectByPairing force-closes WebSocket connections authenticated via the
 // given pairing senderID and channel. Called after revoking a paired device so
 // that the revoked client cannot continue operating with its old role.
@@ -646,6 +678,7 @@ func StartTestServer(s *Server, ctx context.Context) (addr string, start func())
 		responsesHandler.SetPostTurnProcessor(s.postTurn)
 	}
 	mux.Handle("/v1/responses", responsesHandler)
+	httpapi.NewModelsHandler(s.agentStore).RegisterRoutes(mux)
 
 	if s.tools != nil {
 		toolsHandler := httpapi.NewToolsInvokeHandler(s.tools, s.agentStore)
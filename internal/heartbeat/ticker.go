@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -51,9 +52,10 @@ type Ticker struct {
 	runAgent      func(ctx context.Context, req agent.RunRequest) <-chan scheduler.RunOutcome
 	onEvent       func(store.HeartbeatEvent)
 
-	wakeCh chan uuid.UUID
-	stopCh chan struct{}
-	wg     sync.WaitGroup
+	wakeCh  chan uuid.UUID
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+	running atomic.Bool // poll loop active (in cluster mode: this node owns the ticker)
 }
 
 // NewTicker creates a new heartbeat ticker.
@@ -67,25 +69,35 @@ func NewTicker(cfg TickerConfig) *Ticker {
 		msgBus:        cfg.MsgBus,
 		sched:         cfg.Sched,
 		runAgent:      cfg.RunAgent,
-		wakeCh:   make(chan uuid.UUID, 16),
-		stopCh:   make(chan struct{}),
+		wakeCh:        make(chan uuid.UUID, 16),
+		stopCh:        make(chan struct{}),
 	}
 }
 
 // Start begins the background poll loop.
 func (t *Ticker) Start() {
+	t.stopCh = make(chan struct{}) // fresh per start so a stopped ticker can restart
 	t.wg.Add(1)
+	t.running.Store(true)
 	go t.loop()
 	slog.Info("heartbeat ticker started")
 }
 
 // Stop signals the poll loop to exit and waits for completion.
 func (t *Ticker) Stop() {
+	t.running.Store(false)
 	close(t.stopCh)
 	t.wg.Wait()
 	slog.Info("heartbeat ticker stopped")
 }
 
+// Running reports whether the poll loop is active. In cluster mode only the
+// replica holding the heartbeat singleton runs it; wakes raised elsewhere
+// must be forwarded there.
+func (t *Ticker) Running() bool {
+	return t.running.Load()
+}
+
 // SetOnEvent sets the event callback (called for lifecycle events like running/completed/error).
 func (t *Ticker) SetOnEvent(fn func(store.HeartbeatEvent)) {
 	t.onEvent = fn
@@ -98,10 +110,17 @@ func (t *Ticker) emitEvent(event store.HeartbeatEvent) {
 }
 
 // Wake triggers an immediate heartbeat run for a specific agent (wakeMode).
+// Wakes are dropped (and logged) when the ticker is not running here or its
+// wake buffer is full; the agent still runs at its next scheduled time.
 func (t *Ticker) Wake(agentID uuid.UUID) {
+	if !t.Running() {
+		slog.Warn("heartbeat.wake_dropped", "agent_id", agentID, "reason", "ticker not running on this node")
+		return
+	}
 	select {
 	case t.wakeCh <- agentID:
-	default: // channel full, skip
+	default:
+		slog.Warn("heartbeat.wake_dropped", "agent_id", agentID, "reason", "wake buffer full")
 	}
 }
 
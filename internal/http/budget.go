@@ -0,0 +1,45 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/nextlevelbuilder/goclaw/internal/budget"
+	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// BudgetHandler serves USD budget consumption and remaining headroom.
+type BudgetHandler struct {
+	enforcer *budget.Enforcer
+	agents   store.AgentStore
+}
+
+func NewBudgetHandler(enforcer *budget.Enforcer, agents store.AgentStore) *BudgetHandler {
+	return &BudgetHandler{enforcer: enforcer, agents: agents}
+}
+
+func (h *BudgetHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/budget", requireAuth("", h.handleGet))
+}
+
+// handleGet reports budgets for the caller's tenant, optionally narrowed to
+// ?agent_id= (key or UUID) and ?user_id=. Non-admin callers only see their own
+// user budget.
+func (h *BudgetHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	agentRef := r.URL.Query().Get("agent_id")
+	userID := r.URL.Query().Get("user_id")
+	if !permissions.HasMinRole(resolveAuth(r).Role, permissions.RoleAdmin) {
+		userID = store.UserIDFromContext(r.Context())
+	}
+
+	var agents budget.AgentLookup
+	if h.agents != nil {
+		agents = h.agents
+	}
+	report, err := budget.BuildReport(r.Context(), h.enforcer, agents, agentRef, userID)
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
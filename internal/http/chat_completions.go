@@ -12,6 +12,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/agent"
 	"github.com/nextlevelbuilder/goclaw/internal/i18n"
 	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/sessions"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
@@ -50,6 +51,10 @@ type chatCompletionsRequest struct {
 	Messages []chatMessage `json:"messages"`
 	Stream   bool          `json:"stream"`
 	User     string        `json:"user,omitempty"`
+
+	// ResponseFormat is passed through to the agent's final answer
+	// (json_object or json_schema; "text" is the default).
+	ResponseFormat *providers.ResponseFormat `json:"response_format,omitempty"`
 }
 
 type chatMessage struct {
@@ -129,6 +134,10 @@ func (h *ChatCompletionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 		http.Error(w, fmt.Sprintf(`{"error":{"message":"%s"}}`, i18n.T(locale, i18n.MsgMsgsRequired)), http.StatusBadRequest)
 		return
 	}
+	if err := req.ResponseFormat.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf(`{"error":{"message":"%s","type":"invalid_request_error"}}`, i18n.T(locale, i18n.MsgInvalidRequest, err.Error())), http.StatusBadRequest)
+		return
+	}
 
 	agentID := extractAgentID(r, req.Model)
 	userID := store.UserIDFromContext(r.Context()) // resolved by enrichContext (respects API key owner binding)
@@ -167,24 +176,25 @@ func (h *ChatCompletionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Reques
 	slog.Info("chat completions request", "agent", agentID, "stream", req.Stream, "user", userID)
 
 	if req.Stream {
-		h.handleStream(w, r, loop, runID, sessionKey, lastMessage, req.Model, userID)
+		h.handleStream(w, r, loop, runID, sessionKey, lastMessage, req.Model, userID, req.ResponseFormat)
 	} else {
-		h.handleNonStream(w, r, loop, runID, sessionKey, lastMessage, req.Model, userID)
+		h.handleNonStream(w, r, loop, runID, sessionKey, lastMessage, req.Model, userID, req.ResponseFormat)
 	}
 }
 
-func (h *ChatCompletionsHandler) handleNonStream(w http.ResponseWriter, r *http.Request, loop agent.Agent, runID, sessionKey, message, model, userID string) {
+func (h *ChatCompletionsHandler) handleNonStream(w http.ResponseWriter, r *http.Request, loop agent.Agent, runID, sessionKey, message, model, userID string, format *providers.ResponseFormat) {
 	ctx, drainTeamDispatch := tools.InjectTeamDispatch(r.Context(), h.postTurn)
 	defer drainTeamDispatch()
 
 	result, err := loop.Run(ctx, agent.RunRequest{
-		SessionKey: sessionKey,
-		Message:    message,
-		Channel:    "http",
-		ChatID:     "api",
-		RunID:      runID,
-		UserID:     userID,
-		Stream:     false,
+		SessionKey:     sessionKey,
+		Message:        message,
+		Channel:        "http",
+		ChatID:         "api",
+		RunID:          runID,
+		UserID:         userID,
+		Stream:         false,
+		ResponseFormat: format,
 	})
 
 	if err != nil {
@@ -217,7 +227,7 @@ func (h *ChatCompletionsHandler) handleNonStream(w http.ResponseWriter, r *http.
 	json.NewEncoder(w).Encode(resp)
 }
 
-func (h *ChatCompletionsHandler) handleStream(w http.ResponseWriter, r *http.Request, loop agent.Agent, runID, sessionKey, message, model, userID string) {
+func (h *ChatCompletionsHandler) handleStream(w http.ResponseWriter, r *http.Request, loop agent.Agent, runID, sessionKey, message, model, userID string, format *providers.ResponseFormat) {
 	flusher, ok := w.(http.Flusher)
 	if !ok {
 		locale := store.LocaleFromContext(r.Context())
@@ -239,13 +249,14 @@ func (h *ChatCompletionsHandler) handleStream(w http.ResponseWriter, r *http.Req
 	defer drainTeamDispatch()
 
 	result, err := loop.Run(ctx, agent.RunRequest{
-		SessionKey: sessionKey,
-		Message:    message,
-		Channel:    "http",
-		ChatID:     "api",
-		RunID:      runID,
-		UserID:     userID,
-		Stream:     true,
+		SessionKey:     sessionKey,
+		Message:        message,
+		Channel:        "http",
+		ChatID:         "api",
+		RunID:          runID,
+		UserID:         userID,
+		Stream:         true,
+		ResponseFormat: format,
 	})
 
 	if err != nil {
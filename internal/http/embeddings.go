@@ -0,0 +1,180 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tokenizer"
+)
+
+// maxEmbeddingInputs caps the batch size of one /v1/embeddings request
+// (same limit as the OpenAI API).
+const maxEmbeddingInputs = 2048
+
+// EmbeddingResolver returns the embedding provider configured for the tenant
+// in ctx, or nil when none is configured.
+type EmbeddingResolver func(ctx context.Context) store.EmbeddingProvider
+
+// EmbeddingsHandler handles POST /v1/embeddings (OpenAI-compatible).
+// Requests are served by the tenant's configured embedding provider so
+// callers only need a goclaw API key.
+type EmbeddingsHandler struct {
+	resolve EmbeddingResolver
+}
+
+// NewEmbeddingsHandler creates a handler for the embeddings endpoint.
+func NewEmbeddingsHandler(resolve EmbeddingResolver) *EmbeddingsHandler {
+	return &EmbeddingsHandler{resolve: resolve}
+}
+
+func (h *EmbeddingsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v1/embeddings", requireAuth(permissions.RoleOperator, h.handleCreate))
+}
+
+type embeddingsRequest struct {
+	Model          string          `json:"model"` // optional; must name the configured model when set
+	Input          json.RawMessage `json:"input"`
+	EncodingFormat string          `json:"encoding_format,omitempty"` // "float" (default) or "base64"
+	User           string          `json:"user,omitempty"`
+}
+
+type embeddingData struct {
+	Object    string `json:"object"`
+	Index     int    `json:"index"`
+	Embedding any    `json:"embedding"` // []float32 or base64 string
+}
+
+type embeddingsResponse struct {
+	Object string          `json:"object"`
+	Data   []embeddingData `json:"data"`
+	Model  string          `json:"model"`
+	Usage  embeddingsUsage `json:"usage"`
+}
+
+type embeddingsUsage struct {
+	PromptTokens int `json:"prompt_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+func (h *EmbeddingsHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	locale := store.LocaleFromContext(r.Context())
+
+	const maxRequestBodySize = 4 << 20 // 4MB: batches of long documents
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+
+	var req embeddingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, "invalid_request_error", i18n.T(locale, i18n.MsgInvalidRequest, err.Error()))
+		return
+	}
+	inputs, err := parseEmbeddingInput(req.Input)
+	if err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, "invalid_request_error", i18n.T(locale, i18n.MsgInvalidRequest, err.Error()))
+		return
+	}
+	if req.EncodingFormat != "" && req.EncodingFormat != "float" && req.EncodingFormat != "base64" {
+		writeOpenAIError(w, http.StatusBadRequest, "invalid_request_error", i18n.T(locale, i18n.MsgInvalidRequest, "encoding_format must be float or base64"))
+		return
+	}
+
+	var provider store.EmbeddingProvider
+	if h.resolve != nil {
+		provider = h.resolve(r.Context())
+	}
+	if provider == nil {
+		writeOpenAIError(w, http.StatusServiceUnavailable, "invalid_request_error", i18n.T(locale, i18n.MsgEmbeddingsDisabled))
+		return
+	}
+
+	// Only the configured model can serve the request; answering with a
+	// different model's vectors would mix incompatible embeddings.
+	if req.Model != "" && req.Model != provider.Model() {
+		writeOpenAIError(w, http.StatusBadRequest, "invalid_request_error", i18n.T(locale, i18n.MsgInvalidRequest,
+			fmt.Sprintf("model %q is not available, this endpoint serves %q", req.Model, provider.Model())))
+		return
+	}
+
+	vectors, err := provider.Embed(r.Context(), inputs)
+	if err != nil {
+		// Upstream errors can echo request details or provider account
+		// information, so the client only gets a generic message.
+		slog.Warn("embeddings request failed", "provider", provider.Name(), "model", provider.Model(), "error", err)
+		writeOpenAIError(w, http.StatusBadGateway, "api_error", i18n.T(locale, i18n.MsgInternalError, "embedding provider request failed"))
+		return
+	}
+	if len(vectors) != len(inputs) {
+		writeOpenAIError(w, http.StatusBadGateway, "api_error", i18n.T(locale, i18n.MsgInternalError,
+			fmt.Sprintf("provider returned %d embeddings for %d inputs", len(vectors), len(inputs))))
+		return
+	}
+
+	resp := embeddingsResponse{
+		Object: "list",
+		Data:   make([]embeddingData, len(vectors)),
+		Model:  provider.Model(),
+	}
+	for i, vec := range vectors {
+		var embedding any = vec
+		if req.EncodingFormat == "base64" {
+			embedding = encodeEmbeddingBase64(vec)
+		}
+		resp.Data[i] = embeddingData{Object: "embedding", Index: i, Embedding: embedding}
+	}
+	tok := tokenizer.ForModel(provider.Name(), provider.Model())
+	for _, text := range inputs {
+		resp.Usage.PromptTokens += tok.Count(text)
+	}
+	resp.Usage.TotalTokens = resp.Usage.PromptTokens
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// parseEmbeddingInput accepts a string or an array of strings. Pre-tokenized
+// (integer) input is rejected since providers are addressed by text.
+func parseEmbeddingInput(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, fmt.Errorf("input is required")
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		if single == "" {
+			return nil, fmt.Errorf("input must not be empty")
+		}
+		return []string{single}, nil
+	}
+	var batch []string
+	if err := json.Unmarshal(raw, &batch); err != nil {
+		return nil, fmt.Errorf("input must be a string or an array of strings")
+	}
+	if len(batch) == 0 {
+		return nil, fmt.Errorf("input must not be empty")
+	}
+	if len(batch) > maxEmbeddingInputs {
+		return nil, fmt.Errorf("input has %d items, maximum is %d", len(batch), maxEmbeddingInputs)
+	}
+	for i, text := range batch {
+		if text == "" {
+			return nil, fmt.Errorf("input[%d] must not be empty", i)
+		}
+	}
+	return batch, nil
+}
+
+// encodeEmbeddingBase64 packs vec as little-endian float32, the layout the
+// OpenAI SDKs decode for encoding_format=base64.
+func encodeEmbeddingBase64(vec []float32) string {
+	buf := make([]byte, 4*len(vec))
+	for i, v := range vec {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
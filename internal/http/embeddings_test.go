@@ -0,0 +1,161 @@
+package http
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+type stubEmbedder struct {
+	inputs []string
+	err    error
+}
+
+func (s *stubEmbedder) Name() string  { return "openai" }
+func (s *stubEmbedder) Model() string { return "text-embedding-3-small" }
+func (s *stubEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	s.inputs = texts
+	if s.err != nil {
+		return nil, s.err
+	}
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i] = []float32{float32(i), 0.5}
+	}
+	return out, nil
+}
+
+func serveEmbeddings(t *testing.T, resolve EmbeddingResolver, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	setupTestToken(t, "test-token")
+	mux := http.NewServeMux()
+	NewEmbeddingsHandler(resolve).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/embeddings", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-token")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestEmbeddingsHandlerBatch(t *testing.T) {
+	emb := &stubEmbedder{}
+	rec := serveEmbeddings(t, func(context.Context) store.EmbeddingProvider { return emb },
+		`{"model":"text-embedding-3-small","input":["hello","world"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		Object string `json:"object"`
+		Model  string `json:"model"`
+		Data   []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+		Usage embeddingsUsage `json:"usage"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Object != "list" || resp.Model != "text-embedding-3-small" || len(resp.Data) != 2 {
+		t.Fatalf("response = %+v", resp)
+	}
+	if resp.Data[1].Index != 1 || resp.Data[1].Embedding[0] != 1 {
+		t.Fatalf("data[1] = %+v", resp.Data[1])
+	}
+	if resp.Usage.PromptTokens == 0 || resp.Usage.TotalTokens != resp.Usage.PromptTokens {
+		t.Fatalf("usage = %+v", resp.Usage)
+	}
+	if len(emb.inputs) != 2 || emb.inputs[0] != "hello" {
+		t.Fatalf("provider inputs = %v", emb.inputs)
+	}
+}
+
+func TestEmbeddingsHandlerBase64(t *testing.T) {
+	emb := &stubEmbedder{}
+	rec := serveEmbeddings(t, func(context.Context) store.EmbeddingProvider { return emb },
+		`{"input":"hello","encoding_format":"base64"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		Data []struct {
+			Embedding string `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(resp.Data[0].Embedding)
+	if err != nil || len(raw) != 8 {
+		t.Fatalf("embedding = %q (%v)", resp.Data[0].Embedding, err)
+	}
+	if got := math.Float32frombits(binary.LittleEndian.Uint32(raw[4:])); got != 0.5 {
+		t.Fatalf("decoded[1] = %v, want 0.5", got)
+	}
+}
+
+func TestEmbeddingsHandlerErrors(t *testing.T) {
+	emb := &stubEmbedder{}
+	resolve := func(context.Context) store.EmbeddingProvider { return emb }
+
+	tests := []struct {
+		name    string
+		resolve EmbeddingResolver
+		body    string
+		want    int
+	}{
+		{"missing input", resolve, `{"model":"x"}`, http.StatusBadRequest},
+		{"other model", resolve, `{"model":"text-embedding-3-large","input":"hi"}`, http.StatusBadRequest},
+		{"token input", resolve, `{"input":[1,2,3]}`, http.StatusBadRequest},
+		{"empty item", resolve, `{"input":["ok",""]}`, http.StatusBadRequest},
+		{"bad format", resolve, `{"input":"hi","encoding_format":"int8"}`, http.StatusBadRequest},
+		{"no provider", func(context.Context) store.EmbeddingProvider { return nil }, `{"input":"hi"}`, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveEmbeddings(t, tt.resolve, tt.body)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), `"type"`) {
+				t.Fatalf("body %s is not an OpenAI error envelope", rec.Body)
+			}
+		})
+	}
+}
+
+func TestEmbeddingsHandlerHidesProviderErrors(t *testing.T) {
+	emb := &stubEmbedder{err: errors.New("401 from https://api.example.com: key sk-secret revoked")}
+	rec := serveEmbeddings(t, func(context.Context) store.EmbeddingProvider { return emb }, `{"input":"hi"}`)
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	if strings.Contains(rec.Body.String(), "sk-secret") || strings.Contains(rec.Body.String(), "example.com") {
+		t.Fatalf("provider error leaked to client: %s", rec.Body)
+	}
+}
+
+func TestEmbeddingsHandlerRequiresAuth(t *testing.T) {
+	setupTestToken(t, "test-token")
+	mux := http.NewServeMux()
+	NewEmbeddingsHandler(nil).RegisterRoutes(mux)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/embeddings", strings.NewReader(`{"input":"hi"}`))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401", rec.Code)
+	}
+}
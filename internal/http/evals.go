@@ -0,0 +1,136 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/eval"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// maxEvalBodySize bounds eval run requests (the dataset is sent inline).
+const maxEvalBodySize = 32 << 20
+
+// EvalsHandler starts agent evaluation runs and serves their results.
+type EvalsHandler struct {
+	evals  *eval.Manager
+	msgBus *bus.MessageBus
+}
+
+// NewEvalsHandler creates a handler for eval endpoints.
+func NewEvalsHandler(evals *eval.Manager, msgBus *bus.MessageBus) *EvalsHandler {
+	return &EvalsHandler{evals: evals, msgBus: msgBus}
+}
+
+// RegisterRoutes registers eval routes on the given mux. Evals run agents at
+// the caller's expense and expose their outputs, so every route is admin-only.
+func (h *EvalsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /v1/evals", requireAuth(permissions.RoleAdmin, h.handleStart))
+	mux.HandleFunc("GET /v1/evals", requireAuth(permissions.RoleAdmin, h.handleList))
+	mux.HandleFunc("GET /v1/evals/{id}", requireAuth(permissions.RoleAdmin, h.handleGet))
+	mux.HandleFunc("POST /v1/evals/{id}/cancel", requireAuth(permissions.RoleAdmin, h.handleCancel))
+}
+
+// evalStartRequest is an eval.Spec whose cases may instead be given as a
+// JSONL dataset string (the format `goclaw eval` reads from disk).
+type evalStartRequest struct {
+	eval.Spec
+	Dataset string `json:"dataset,omitempty"`
+}
+
+func (h *EvalsHandler) handleStart(w http.ResponseWriter, r *http.Request) {
+	locale := store.LocaleFromContext(r.Context())
+	r.Body = http.MaxBytesReader(w, r.Body, maxEvalBodySize)
+	var req evalStartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidJSON)})
+		return
+	}
+	if req.Dataset != "" {
+		if len(req.Cases) > 0 {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidRequest, "send either cases or dataset, not both")})
+			return
+		}
+		cases, err := eval.ParseDataset(strings.NewReader(req.Dataset))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidRequest, "dataset: "+err.Error())})
+			return
+		}
+		req.Cases = cases
+	}
+
+	run, err := h.evals.Start(r.Context(), req.Spec)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidRequest, err.Error())})
+		return
+	}
+	emitAudit(h.msgBus, r, "eval.started", "eval", run.ID.String())
+	writeJSON(w, http.StatusAccepted, map[string]any{"run": run})
+}
+
+func (h *EvalsHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	limit, offset := 50, 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 200 {
+			limit = n
+		}
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+	runs, total, err := h.evals.List(r.Context(), limit, offset)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"runs":   runs,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+func (h *EvalsHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	locale := store.LocaleFromContext(r.Context())
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidID, "eval")})
+		return
+	}
+	run, err := h.evals.Get(r.Context(), id)
+	if errors.Is(err, eval.ErrNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "eval", id.String())})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"run": run})
+}
+
+func (h *EvalsHandler) handleCancel(w http.ResponseWriter, r *http.Request) {
+	locale := store.LocaleFromContext(r.Context())
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidID, "eval")})
+		return
+	}
+	if !h.evals.Cancel(r.Context(), id) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "running eval", id.String())})
+		return
+	}
+	emitAudit(h.msgBus, r, "eval.cancelled", "eval", id.String())
+	writeJSON(w, http.StatusOK, map[string]any{"cancelled": true})
+}
@@ -0,0 +1,485 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/docextract"
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
+	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// kbTextExtensions are plain-text formats stored as-is, without extraction.
+var kbTextExtensions = map[string]bool{
+	".txt": true, ".md": true, ".markdown": true, ".rst": true, ".csv": true, ".tsv": true,
+	".json": true, ".yaml": true, ".yml": true, ".xml": true, ".html": true, ".htm": true, ".log": true,
+}
+
+const (
+	// kbMaxDocumentChars caps the text extracted from one uploaded document;
+	// the rest is dropped and the truncation logged.
+	kbMaxDocumentChars = 10 << 20
+	// kbIndexConcurrency bounds how many background indexing jobs run at
+	// once per gateway; kbIndexTimeout bounds one document.
+	kbIndexConcurrency = 2
+	kbIndexTimeout     = 30 * time.Minute
+)
+
+// KnowledgeBasesHandler manages knowledge base collections, document uploads
+// and retrieval testing. Reads are open to any tenant member; changes need
+// the operator role.
+type KnowledgeBasesHandler struct {
+	kb     store.KnowledgeBaseStore
+	agents store.AgentStore
+	msgBus *bus.MessageBus
+
+	indexSlots chan struct{} // semaphore for background indexing jobs
+}
+
+// NewKnowledgeBasesHandler creates a handler for knowledge base endpoints.
+func NewKnowledgeBasesHandler(kb store.KnowledgeBaseStore, agents store.AgentStore, msgBus *bus.MessageBus) *KnowledgeBasesHandler {
+	return &KnowledgeBasesHandler{kb: kb, agents: agents, msgBus: msgBus, indexSlots: make(chan struct{}, kbIndexConcurrency)}
+}
+
+// RegisterRoutes registers knowledge base routes on the given mux.
+func (h *KnowledgeBasesHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/kb/collections", requireAuth("", h.handleListCollections))
+	mux.HandleFunc("POST /v1/kb/collections", requireAuth(permissions.RoleOperator, h.handleCreateCollection))
+	mux.HandleFunc("GET /v1/kb/collections/{id}", requireAuth("", h.handleGetCollection))
+	mux.HandleFunc("PUT /v1/kb/collections/{id}", requireAuth(permissions.RoleOperator, h.handleUpdateCollection))
+	mux.HandleFunc("DELETE /v1/kb/collections/{id}", requireAuth(permissions.RoleOperator, h.handleDeleteCollection))
+	mux.HandleFunc("POST /v1/kb/collections/{id}/agents/{agentID}", requireAuth(permissions.RoleOperator, h.handleAttachAgent))
+	mux.HandleFunc("DELETE /v1/kb/collections/{id}/agents/{agentID}", requireAuth(permissions.RoleOperator, h.handleDetachAgent))
+	mux.HandleFunc("GET /v1/kb/collections/{id}/documents", requireAuth("", h.handleListDocuments))
+	mux.HandleFunc("POST /v1/kb/collections/{id}/documents", requireAuth(permissions.RoleOperator, h.handleUploadDocument))
+	mux.HandleFunc("DELETE /v1/kb/collections/{id}/documents/{docID}", requireAuth(permissions.RoleOperator, h.handleDeleteDocument))
+	mux.HandleFunc("POST /v1/kb/collections/{id}/reindex", requireAuth(permissions.RoleOperator, h.handleReindex))
+	mux.HandleFunc("POST /v1/kb/collections/{id}/search", requireAuth("", h.handleSearch))
+}
+
+// --- Collections ---
+
+type kbCollectionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (req *kbCollectionRequest) validate(locale string) string {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return i18n.T(locale, i18n.MsgRequired, "name")
+	}
+	if len(req.Name) > 255 {
+		return i18n.T(locale, i18n.MsgInvalidRequest, "name must be at most 255 characters")
+	}
+	return ""
+}
+
+func (h *KnowledgeBasesHandler) handleListCollections(w http.ResponseWriter, r *http.Request) {
+	cols, err := h.kb.ListCollections(r.Context())
+	if err != nil {
+		slog.Warn("kb.list_collections failed", "error", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if cols == nil {
+		cols = []store.KBCollectionData{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"collections": cols})
+}
+
+func (h *KnowledgeBasesHandler) handleCreateCollection(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	var req kbCollectionRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidJSON)})
+		return
+	}
+	if msg := req.validate(locale); msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+		return
+	}
+
+	col := &store.KBCollectionData{
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedBy:   store.UserIDFromContext(r.Context()),
+	}
+	if err := h.kb.CreateCollection(r.Context(), col); err != nil {
+		if isUniqueViolation(err) {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": i18n.T(locale, i18n.MsgAlreadyExists, "knowledge base", req.Name)})
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	col.AgentIDs = []uuid.UUID{}
+	emitAudit(h.msgBus, r, "kb.collection_created", "kb_collection", col.ID.String())
+	writeJSON(w, http.StatusCreated, col)
+}
+
+func (h *KnowledgeBasesHandler) handleGetCollection(w http.ResponseWriter, r *http.Request) {
+	col, ok := h.collectionFromPath(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, col)
+}
+
+func (h *KnowledgeBasesHandler) handleUpdateCollection(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	col, ok := h.collectionFromPath(w, r)
+	if !ok {
+		return
+	}
+	var req kbCollectionRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidJSON)})
+		return
+	}
+	if msg := req.validate(locale); msg != "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": msg})
+		return
+	}
+	col.Name = req.Name
+	col.Description = req.Description
+	if err := h.kb.UpdateCollection(r.Context(), col); err != nil {
+		if isUniqueViolation(err) {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": i18n.T(locale, i18n.MsgAlreadyExists, "knowledge base", req.Name)})
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	emitAudit(h.msgBus, r, "kb.collection_updated", "kb_collection", col.ID.String())
+	writeJSON(w, http.StatusOK, col)
+}
+
+func (h *KnowledgeBasesHandler) handleDeleteCollection(w http.ResponseWriter, r *http.Request) {
+	col, ok := h.collectionFromPath(w, r)
+	if !ok {
+		return
+	}
+	if err := h.kb.DeleteCollection(r.Context(), col.ID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	emitAudit(h.msgBus, r, "kb.collection_deleted", "kb_collection", col.ID.String())
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// --- Agent attachments ---
+
+func (h *KnowledgeBasesHandler) handleAttachAgent(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	col, ok := h.collectionFromPath(w, r)
+	if !ok {
+		return
+	}
+	agentID, err := uuid.Parse(r.PathValue("agentID"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidID, "agent")})
+		return
+	}
+	if _, err := h.agents.GetByID(r.Context(), agentID); err != nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "agent", agentID.String())})
+		return
+	}
+	if err := h.kb.AttachAgent(r.Context(), col.ID, agentID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	emitAudit(h.msgBus, r, "kb.agent_attached", "kb_collection", col.ID.String())
+	writeJSON(w, http.StatusOK, map[string]string{"status": "attached"})
+}
+
+func (h *KnowledgeBasesHandler) handleDetachAgent(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	col, ok := h.collectionFromPath(w, r)
+	if !ok {
+		return
+	}
+	agentID, err := uuid.Parse(r.PathValue("agentID"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidID, "agent")})
+		return
+	}
+	if err := h.kb.DetachAgent(r.Context(), col.ID, agentID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	emitAudit(h.msgBus, r, "kb.agent_detached", "kb_collection", col.ID.String())
+	writeJSON(w, http.StatusOK, map[string]string{"status": "detached"})
+}
+
+// --- Documents ---
+
+func (h *KnowledgeBasesHandler) handleListDocuments(w http.ResponseWriter, r *http.Request) {
+	col, ok := h.collectionFromPath(w, r)
+	if !ok {
+		return
+	}
+	docs, err := h.kb.ListDocuments(r.Context(), col.ID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if docs == nil {
+		docs = []store.KBDocumentData{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"documents": docs})
+}
+
+// handleUploadDocument stores an uploaded file's text and queues it for
+// indexing; clients poll the document's status. A file with the same name
+// replaces the existing document; if its text is unchanged the document is
+// not re-indexed.
+func (h *KnowledgeBasesHandler) handleUploadDocument(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	col, ok := h.collectionFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, docextract.MaxFileBytes+1<<20)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgFileTooLarge)})
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgMissingFileField)})
+		return
+	}
+	defer file.Close()
+
+	name := filepath.Base(header.Filename)
+	if name == "." || name == "/" || strings.Contains(name, "..") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidFilename)})
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(file, docextract.MaxFileBytes+1))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgFileTooLarge)})
+		return
+	}
+	if len(data) > docextract.MaxFileBytes {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": i18n.T(locale, i18n.MsgFileTooLarge)})
+		return
+	}
+
+	content, mimeType, pages, err := kbDocumentText(name, header.Header.Get("Content-Type"), data)
+	if err != nil {
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidRequest, err.Error())})
+		return
+	}
+	hash := memory.ContentHash(content)
+
+	if existing, err := h.kb.GetDocumentByName(r.Context(), col.ID, name); err == nil &&
+		existing.Hash == hash && existing.Status == store.KBDocStatusReady {
+		writeJSON(w, http.StatusOK, map[string]any{"document": existing, "unchanged": true})
+		return
+	}
+
+	doc := &store.KBDocumentData{
+		CollectionID: col.ID,
+		FileName:     name,
+		MimeType:     mimeType,
+		Hash:         hash,
+		SizeBytes:    int64(len(data)),
+		Pages:        pages,
+		Content:      content,
+		CreatedBy:    store.UserIDFromContext(r.Context()),
+	}
+	if err := h.kb.PutDocument(r.Context(), doc); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	h.indexInBackground(r.Context(), doc.ID)
+	emitAudit(h.msgBus, r, "kb.document_uploaded", "kb_document", doc.ID.String())
+	writeJSON(w, http.StatusAccepted, map[string]any{"document": doc})
+}
+
+// indexInBackground indexes documents outside the request, at most
+// kbIndexConcurrency jobs at a time. The request context's values (tenant,
+// user) are kept; its cancellation is not.
+func (h *KnowledgeBasesHandler) indexInBackground(ctx context.Context, ids ...uuid.UUID) {
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		h.indexSlots <- struct{}{}
+		defer func() { <-h.indexSlots }()
+		for _, id := range ids {
+			indexCtx, cancel := context.WithTimeout(ctx, kbIndexTimeout)
+			if err := h.kb.IndexDocument(indexCtx, id); err != nil {
+				slog.Warn("kb.index_document failed", "document_id", id, "error", err)
+			}
+			cancel()
+		}
+	}()
+}
+
+// kbDocumentText returns the text to index for an uploaded file: extracted
+// text for PDF and Office files (with their page count), the file itself for
+// plain-text formats.
+func kbDocumentText(name, contentType string, data []byte) (content, mimeType string, pages int, err error) {
+	if format := docextract.FormatOf(name, contentType); format != "" {
+		doc, err := docextract.Extract(data, format, kbMaxDocumentChars)
+		if errors.Is(err, docextract.ErrNoText) {
+			return "", "", 0, errors.New("document has no text layer (scanned or image-only)")
+		}
+		if err != nil {
+			return "", "", 0, err
+		}
+		if doc.Truncated {
+			slog.Warn("kb.document_truncated", "file", name, "max_chars", kbMaxDocumentChars)
+		}
+		return doc.Text, format.MIMEType(), doc.Parts, nil
+	}
+
+	ext := strings.ToLower(filepath.Ext(name))
+	if !kbTextExtensions[ext] && !strings.HasPrefix(contentType, "text/") {
+		return "", "", 0, fmt.Errorf("unsupported file type %q: upload PDF, DOCX, XLSX, PPTX or a text file", ext)
+	}
+	if !utf8.Valid(data) {
+		return "", "", 0, errors.New("text file is not valid UTF-8")
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return "", "", 0, errors.New("file is empty")
+	}
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = "text/plain"
+	}
+	return string(data), contentType, 0, nil
+}
+
+func (h *KnowledgeBasesHandler) handleDeleteDocument(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	col, ok := h.collectionFromPath(w, r)
+	if !ok {
+		return
+	}
+	docID, err := uuid.Parse(r.PathValue("docID"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidID, "document")})
+		return
+	}
+	if err := h.kb.DeleteDocument(r.Context(), col.ID, docID); err != nil {
+		if errors.Is(err, store.ErrKBDocumentNotFound) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "document", docID.String())})
+			return
+		}
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	emitAudit(h.msgBus, r, "kb.document_deleted", "kb_document", docID.String())
+	writeJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// handleReindex queues a rebuild of the chunks and embeddings of every
+// document in a collection, e.g. after changing the embedding provider.
+// Documents go back to pending; clients poll their status.
+func (h *KnowledgeBasesHandler) handleReindex(w http.ResponseWriter, r *http.Request) {
+	col, ok := h.collectionFromPath(w, r)
+	if !ok {
+		return
+	}
+	docs, err := h.kb.ListDocuments(r.Context(), col.ID)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if err := h.kb.MarkDocumentsPending(r.Context(), col.ID); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	ids := make([]uuid.UUID, len(docs))
+	for i, d := range docs {
+		ids[i] = d.ID
+	}
+	h.indexInBackground(r.Context(), ids...)
+	emitAudit(h.msgBus, r, "kb.collection_reindexed", "kb_collection", col.ID.String())
+	writeJSON(w, http.StatusAccepted, map[string]int{"queued": len(ids)})
+}
+
+// --- Search ---
+
+type kbSearchRequest struct {
+	Query      string  `json:"query"`
+	MaxResults int     `json:"max_results"`
+	MinScore   float64 `json:"min_score"`
+}
+
+// handleSearch runs a retrieval query against one collection, regardless of
+// which agents it is attached to. Used to check what kb_search would return.
+func (h *KnowledgeBasesHandler) handleSearch(w http.ResponseWriter, r *http.Request) {
+	locale := extractLocale(r)
+	col, ok := h.collectionFromPath(w, r)
+	if !ok {
+		return
+	}
+	var req kbSearchRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidJSON)})
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgRequired, "query")})
+		return
+	}
+	results, err := h.kb.Search(r.Context(), req.Query, uuid.Nil, store.KBSearchOptions{
+		CollectionIDs: []uuid.UUID{col.ID},
+		MaxResults:    min(req.MaxResults, store.KBMaxSearchResults),
+		MinScore:      req.MinScore,
+	})
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	if results == nil {
+		results = []store.KBSearchResult{}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"results": results})
+}
+
+// collectionFromPath loads the collection named by the {id} path value,
+// writing a 400/404 response when it can't.
+func (h *KnowledgeBasesHandler) collectionFromPath(w http.ResponseWriter, r *http.Request) (*store.KBCollectionData, bool) {
+	locale := extractLocale(r)
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": i18n.T(locale, i18n.MsgInvalidID, "knowledge base")})
+		return nil, false
+	}
+	col, err := h.kb.GetCollection(r.Context(), id)
+	if errors.Is(err, store.ErrKBCollectionNotFound) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "knowledge base", id.String())})
+		return nil, false
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return nil, false
+	}
+	return col, true
+}
+
+// isUniqueViolation reports whether err is a unique constraint error from
+// Postgres or SQLite.
+func isUniqueViolation(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "duplicate key") || strings.Contains(msg, "23505") ||
+		strings.Contains(msg, "UNIQUE constraint failed")
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestKBDocumentText(t *testing.T) {
+	content, mime, pages, err := kbDocumentText("faq.md", "", []byte("# FAQ\n\nReset with the side button."))
+	if err != nil || mime != "text/plain" || pages != 0 || !strings.Contains(content, "side button") {
+		t.Errorf("markdown = %q, %q, %d, %v", content, mime, pages, err)
+	}
+	if _, mime, _, err := kbDocumentText("notes", "text/csv", []byte("a,b\n1,2")); err != nil || mime != "text/csv" {
+		t.Errorf("text/* content type = %q, %v", mime, err)
+	}
+
+	for name, data := range map[string][]byte{
+		"photo.png":  {0x89, 'P', 'N', 'G'},
+		"empty.txt":  []byte("  \n"),
+		"binary.txt": {0xff, 0xfe, 0x00},
+		"broken.pdf": []byte("not a pdf"),
+	} {
+		if _, _, _, err := kbDocumentText(name, "", data); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+// fakeKBStore implements the parts of store.KnowledgeBaseStore used by reindex.
+type fakeKBStore struct {
+	store.KnowledgeBaseStore
+	docs []store.KBDocumentData
+
+	mu      sync.Mutex
+	pending bool
+	indexed chan uuid.UUID
+}
+
+func (f *fakeKBStore) GetCollection(_ context.Context, id uuid.UUID) (*store.KBCollectionData, error) {
+	return &store.KBCollectionData{BaseModel: store.BaseModel{ID: id}}, nil
+}
+
+func (f *fakeKBStore) ListDocuments(context.Context, uuid.UUID) ([]store.KBDocumentData, error) {
+	return f.docs, nil
+}
+
+func (f *fakeKBStore) MarkDocumentsPending(context.Context, uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pending = true
+	return nil
+}
+
+func (f *fakeKBStore) IndexDocument(ctx context.Context, id uuid.UUID) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	f.indexed <- id
+	return nil
+}
+
+func TestKBReindexRunsInBackground(t *testing.T) {
+	kb := &fakeKBStore{
+		docs:    []store.KBDocumentData{{BaseModel: store.BaseModel{ID: uuid.New()}}, {BaseModel: store.BaseModel{ID: uuid.New()}}},
+		indexed: make(chan uuid.UUID, 2),
+	}
+	h := NewKnowledgeBasesHandler(kb, nil, nil)
+
+	// The request context is cancelled once the handler returns; indexing
+	// must outlive it.
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/v1/kb/collections/x/reindex", nil).WithContext(ctx)
+	req.SetPathValue("id", uuid.NewString())
+	rec := httptest.NewRecorder()
+	h.handleReindex(rec, req)
+	cancel()
+
+	if rec.Code != http.StatusAccepted || !strings.Contains(rec.Body.String(), `"queued":2`) {
+		t.Fatalf("response = %d %s", rec.Code, rec.Body.String())
+	}
+	kb.mu.Lock()
+	pending := kb.pending
+	kb.mu.Unlock()
+	if !pending {
+		t.Error("documents were not marked pending")
+	}
+	for range kb.docs {
+		select {
+		case <-kb.indexed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("document was not indexed in the background")
+		}
+	}
+}
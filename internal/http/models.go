@@ -0,0 +1,102 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/nextlevelbuilder/goclaw/internal/i18n"
+	"github.com/nextlevelbuilder/goclaw/internal/permissions"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// agentModelPrefix is how agents are named as models. extractAgentID accepts
+// it in the model field of /v1/chat/completions and /v1/responses.
+const agentModelPrefix = "goclaw:"
+
+// ModelsHandler handles GET /v1/models (OpenAI-compatible), listing the
+// agents visible to the caller as models.
+type ModelsHandler struct {
+	agents store.AgentStore
+}
+
+// NewModelsHandler creates a handler for the models endpoint.
+func NewModelsHandler(agents store.AgentStore) *ModelsHandler {
+	return &ModelsHandler{agents: agents}
+}
+
+func (h *ModelsHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/models", requireAuth(permissions.RoleViewer, h.handleList))
+	mux.HandleFunc("GET /v1/models/{id}", requireAuth(permissions.RoleViewer, h.handleGet))
+}
+
+type modelObject struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+func agentModel(ag *store.AgentData) modelObject {
+	return modelObject{
+		ID:      agentModelPrefix + ag.AgentKey,
+		Object:  "model",
+		Created: ag.CreatedAt.Unix(),
+		OwnedBy: "goclaw",
+	}
+}
+
+func (h *ModelsHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	agents, err := h.visibleAgents(r)
+	if err != nil {
+		slog.Error("models.list", "error", err)
+		locale := store.LocaleFromContext(r.Context())
+		writeOpenAIError(w, http.StatusInternalServerError, "api_error", i18n.T(locale, i18n.MsgFailedToList, "models"))
+		return
+	}
+
+	data := make([]modelObject, 0, len(agents))
+	for i := range agents {
+		if agents[i].Status != "" && agents[i].Status != store.AgentStatusActive {
+			continue
+		}
+		data = append(data, agentModel(&agents[i]))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"object": "list", "data": data})
+}
+
+func (h *ModelsHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	locale := store.LocaleFromContext(r.Context())
+	id := r.PathValue("id")
+	key := strings.TrimPrefix(strings.TrimPrefix(id, agentModelPrefix), "agent:")
+
+	agents, err := h.visibleAgents(r)
+	if err != nil {
+		slog.Error("models.get", "error", err)
+		writeOpenAIError(w, http.StatusInternalServerError, "api_error", i18n.T(locale, i18n.MsgFailedToList, "models"))
+		return
+	}
+	for i := range agents {
+		if agents[i].AgentKey == key {
+			writeJSON(w, http.StatusOK, agentModel(&agents[i]))
+			return
+		}
+	}
+	writeOpenAIError(w, http.StatusNotFound, "invalid_request_error", i18n.T(locale, i18n.MsgNotFound, "model", id))
+}
+
+// visibleAgents returns every tenant agent for admins and the agents the
+// caller can access otherwise.
+func (h *ModelsHandler) visibleAgents(r *http.Request) ([]store.AgentData, error) {
+	if h.agents == nil {
+		return nil, nil
+	}
+	if permissions.HasMinRole(resolveAuth(r).Role, permissions.RoleAdmin) {
+		return h.agents.List(r.Context(), "")
+	}
+	userID := store.UserIDFromContext(r.Context())
+	if userID == "" {
+		return nil, nil
+	}
+	return h.agents.ListAccessible(r.Context(), userID)
+}
@@ -16,7 +16,7 @@ import (
 //
 //	POST /v1/providers/{id}/verify-embedding
 //	Body: {"model": "text-embedding-3-small"}  (optional, falls back to settings.embedding.model)
-//	Response: {"valid": true, "dimensions": 1536} or {"valid": false, "error": "..."}
+//	Response: {"valid": true, "dimensions": 1536, "api": "openai"} or {"valid": false, "error": "..."}
 func (h *ProvidersHandler) handleVerifyEmbedding(w http.ResponseWriter, r *http.Request) {
 	locale := extractLocale(r)
 	id, err := uuid.Parse(r.PathValue("id"))
@@ -49,14 +49,11 @@ func (h *ProvidersHandler) handleVerifyEmbedding(w http.ResponseWriter, r *http.
 	// Parse embedding settings once for model/apiBase/dimensions resolution.
 	es := store.ParseEmbeddingSettings(p.Settings)
 
-	// Resolve model: request body → settings.embedding.model → default
+	// Resolve model: request body → settings.embedding.model → the API's default
 	model := req.Model
 	if model == "" && es != nil && es.Model != "" {
 		model = es.Model
 	}
-	if model == "" {
-		model = "text-embedding-3-small"
-	}
 
 	// Resolve API base: settings.embedding.api_base → provider api_base → resolved base
 	apiBase := h.resolveAPIBase(p)
@@ -64,16 +61,21 @@ func (h *ProvidersHandler) handleVerifyEmbedding(w http.ResponseWriter, r *http.
 		apiBase = es.APIBase
 	}
 
-	ep := memory.NewOpenAIEmbeddingProvider(p.Name, p.APIKey, apiBase, model)
-
 	// Apply dimension truncation: request body → provider settings → none.
 	// Clamp to reasonable range to avoid sending absurd values upstream.
 	truncDims := req.Dimensions
 	if truncDims <= 0 && es != nil && es.Dimensions > 0 {
 		truncDims = es.Dimensions
 	}
-	if truncDims > 0 && truncDims <= 8192 {
-		ep.WithDimensions(truncDims)
+	if truncDims <= 0 || truncDims > 8192 {
+		truncDims = 0
+	}
+
+	api := store.EmbeddingAPIFor(p.ProviderType, apiBase, es)
+	ep, err := memory.NewEmbeddingProvider(api, p.Name, p.APIKey, apiBase, model, truncDims)
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]any{"valid": false, "error": err.Error()})
+		return
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
@@ -89,8 +91,10 @@ func (h *ProvidersHandler) handleVerifyEmbedding(w http.ResponseWriter, r *http.
 	if len(vectors) > 0 && len(vectors[0]) > 0 {
 		dims = len(vectors[0])
 	}
-	result := map[string]any{"valid": true, "dimensions": dims}
-	if dims > 0 && dims != 1536 {
+	// Smaller vectors are zero-padded to the schema size without loss; larger
+	// ones are truncated, which only preserves quality for Matryoshka models.
+	result := map[string]any{"valid": true, "dimensions": dims, "api": api}
+	if dims > store.RequiredMemoryEmbeddingDimensions {
 		result["dimension_mismatch"] = true
 	}
 	writeJSON(w, http.StatusOK, result)
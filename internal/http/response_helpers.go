@@ -30,3 +30,11 @@ func writeJSON(w http.ResponseWriter, status int, data any) {
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(data)
 }
+
+// writeOpenAIError writes an error in the OpenAI API envelope used by the
+// /v1 compatibility endpoints, so OpenAI SDKs surface the message.
+func writeOpenAIError(w http.ResponseWriter, status int, errType, msg string) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]string{"message": msg, "type": errType},
+	})
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/sessions"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 	"github.com/nextlevelbuilder/goclaw/internal/tools"
+	"github.com/nextlevelbuilder/goclaw/internal/workflow"
 )
 
 // WakeHandler handles POST /v1/agents/{id}/wake — external trigger API.
@@ -21,6 +22,12 @@ import (
 type WakeHandler struct {
 	agents   *agent.Router
 	postTurn tools.PostTurnProcessor
+
+	// Optional: "workflow" requests start a workflow of the team the agent leads.
+	agentStore     store.AgentStore
+	teamStore      store.TeamStore
+	workflowStore  store.WorkflowStore
+	workflowEngine *workflow.Engine
 }
 
 // SetPostTurnProcessor sets the post-turn processor for team task dispatch.
@@ -28,6 +35,14 @@ func (h *WakeHandler) SetPostTurnProcessor(pt tools.PostTurnProcessor) {
 	h.postTurn = pt
 }
 
+// SetWorkflows enables starting team workflows through the wake endpoint.
+func (h *WakeHandler) SetWorkflows(agents store.AgentStore, teams store.TeamStore, workflows store.WorkflowStore, engine *workflow.Engine) {
+	h.agentStore = agents
+	h.teamStore = teams
+	h.workflowStore = workflows
+	h.workflowEngine = engine
+}
+
 // NewWakeHandler creates a handler for the wake endpoint.
 func NewWakeHandler(agents *agent.Router) *WakeHandler {
 	return &WakeHandler{agents: agents}
@@ -43,6 +58,19 @@ type wakeRequest struct {
 	SessionKey string         `json:"session_key,omitempty"`
 	UserID     string         `json:"user_id,omitempty"`
 	Metadata   map[string]any `json:"metadata,omitempty"`
+
+	// Workflow (name or ID) starts a workflow of the team this agent leads
+	// instead of an agent turn. Message is passed as input "message".
+	Workflow string            `json:"workflow,omitempty"`
+	Input    map[string]string `json:"input,omitempty"`
+}
+
+type wakeWorkflowResponse struct {
+	WorkflowID string                              `json:"workflow_id"`
+	RunID      string                              `json:"run_id"`
+	Status     string                              `json:"status"`
+	TraceID    string                              `json:"trace_id,omitempty"`
+	Steps      map[string]*store.WorkflowStepState `json:"steps"`
 }
 
 type wakeResponse struct {
@@ -90,6 +118,11 @@ func (h *WakeHandler) handleWake(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Workflow != "" {
+		h.handleWakeWorkflow(w, r, locale, agentID, req)
+		return
+	}
+
 	if req.Message == "" {
 		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "message is required"})
 		return
@@ -156,3 +189,72 @@ func (h *WakeHandler) handleWake(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, resp)
 }
+
+// handleWakeWorkflow starts a workflow of the team led by the addressed agent.
+// It returns as soon as the run has started; the run is tracked via
+// teams.workflows.runs.get and its trace.
+func (h *WakeHandler) handleWakeWorkflow(w http.ResponseWriter, r *http.Request, locale, agentID string, req wakeRequest) {
+	if h.workflowEngine == nil || h.workflowStore == nil || h.teamStore == nil || h.agentStore == nil {
+		writeJSON(w, http.StatusNotImplemented, map[string]string{"error": "workflows are not available"})
+		return
+	}
+	ctx := r.Context()
+
+	var ag *store.AgentData
+	var err error
+	if id, parseErr := uuid.Parse(agentID); parseErr == nil {
+		ag, err = h.agentStore.GetByID(ctx, id)
+	} else {
+		ag, err = h.agentStore.GetByKey(ctx, agentID)
+	}
+	if err != nil || ag == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "agent", agentID)})
+		return
+	}
+	team, err := h.teamStore.GetTeamForAgent(ctx, ag.ID)
+	if err != nil || team == nil || team.LeadAgentID != ag.ID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "agent does not lead a team"})
+		return
+	}
+
+	var wf *store.WorkflowData
+	if id, parseErr := uuid.Parse(req.Workflow); parseErr == nil {
+		wf, err = h.workflowStore.GetWorkflow(ctx, id)
+	} else {
+		wf, err = h.workflowStore.GetWorkflowByName(ctx, team.ID, req.Workflow)
+	}
+	if err != nil || wf == nil || wf.TeamID != team.ID {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": i18n.T(locale, i18n.MsgNotFound, "workflow", req.Workflow)})
+		return
+	}
+
+	input := make(map[string]string, len(req.Input)+1)
+	for k, v := range req.Input {
+		input[k] = v
+	}
+	if req.Message != "" && input["message"] == "" {
+		input["message"] = req.Message
+	}
+	userID := store.UserIDFromContext(ctx)
+	slog.Info("wake workflow request", "agent", agentID, "workflow", wf.Name, "user", userID)
+
+	run, err := h.workflowEngine.Start(ctx, wf, workflow.RunOptions{
+		Trigger:     store.WorkflowTriggerWake,
+		TriggeredBy: userID,
+		Input:       input,
+	})
+	if err != nil && run == nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	resp := wakeWorkflowResponse{
+		WorkflowID: wf.ID.String(),
+		RunID:      run.ID.String(),
+		Status:     run.Status,
+		Steps:      run.Steps,
+	}
+	if run.TraceID != nil {
+		resp.TraceID = run.TraceID.String()
+	}
+	writeJSON(w, http.StatusAccepted, resp)
+}
@@ -44,6 +44,7 @@ func init() {
 
 		// Exec approval
 		MsgExecApprovalDisabled: "exec approval is not enabled",
+		MsgToolApprovalDisabled: "tool approval is not enabled",
 
 		// Pairing
 		MsgSenderChannelRequired: "senderId and channel are required",
@@ -66,6 +67,7 @@ func init() {
 		MsgSummoningUnavailable:  "summoning not available",
 		MsgNoDescription:         "agent has no description to resummon from",
 		MsgInvalidPath:           "invalid path",
+		MsgEmbeddingsDisabled:    "no embedding provider is configured",
 
 		// Scheduler
 		MsgQueueFull:    "session queue is full",
@@ -142,13 +144,16 @@ func init() {
 		MsgToolReadFile:        "Read the contents of a file from the agent's workspace by path",
 		MsgToolWriteFile:       "Write content to a file in the workspace, creating directories as needed",
 		MsgToolListFiles:       "List files and directories in a given path within the workspace",
+		MsgToolSearchFiles:     "Search file contents by regex or literal text and find files by glob across the workspace",
 		MsgToolEdit:            "Apply targeted search-and-replace edits to existing files without rewriting the entire file",
+		MsgToolApplyPatch:      "Apply a multi-file unified diff or Begin Patch block atomically, with dry-run and per-hunk conflict reports",
 		MsgToolExec:            "Execute a shell command in the workspace and return stdout/stderr",
 		MsgToolWebSearch:       "Search the web for information using a search engine (Brave or DuckDuckGo)",
 		MsgToolWebFetch:        "Fetch a web page or API endpoint and extract its text content",
 		MsgToolMemorySearch:    "Search through the agent's long-term memory using semantic similarity",
 		MsgToolMemoryGet:       "Retrieve a specific memory document by its file path",
 		MsgToolKGSearch:        "Search entities, relationships, and observations in the agent's knowledge graph",
+		MsgToolKBSearch:        "Search document knowledge bases attached to the agent and return cited passages",
 		MsgToolReadImage:       "Analyze images using a vision-capable LLM provider",
 		MsgToolReadDocument:    "Analyze documents (PDF, Word, Excel, PowerPoint, CSV, etc.) using a document-capable LLM provider",
 		MsgToolCreateImage:     "Generate images from text prompts using an image generation provider",
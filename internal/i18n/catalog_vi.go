@@ -44,6 +44,7 @@ func init() {
 
 		// Exec approval
 		MsgExecApprovalDisabled: "phê duyệt thực thi chưa được bật",
+		MsgToolApprovalDisabled: "phê duyệt công cụ chưa được bật",
 
 		// Pairing
 		MsgSenderChannelRequired: "senderId và channel là bắt buộc",
@@ -66,6 +67,7 @@ func init() {
 		MsgSummoningUnavailable:  "triệu hồi không khả dụng",
 		MsgNoDescription:         "agent không có mô tả để triệu hồi lại",
 		MsgInvalidPath:           "đường dẫn không hợp lệ",
+		MsgEmbeddingsDisabled:    "chưa cấu hình nhà cung cấp embedding",
 
 		// Scheduler
 		MsgQueueFull:    "hàng đợi session đã đầy",
@@ -142,13 +144,16 @@ func init() {
 		MsgToolReadFile:        "Đọc nội dung tệp từ workspace của agent theo đường dẫn",
 		MsgToolWriteFile:       "Ghi nội dung vào tệp trong workspace, tự động tạo thư mục nếu cần",
 		MsgToolListFiles:       "Liệt kê tệp và thư mục trong đường dẫn chỉ định",
+		MsgToolSearchFiles:     "Tìm kiếm nội dung tệp bằng regex hoặc văn bản và tìm tệp theo glob trong workspace",
 		MsgToolEdit:            "Chỉnh sửa tệp bằng cách tìm và thay thế đoạn văn bản cụ thể",
+		MsgToolApplyPatch:      "Áp dụng bản vá (unified diff hoặc Begin Patch) cho nhiều tệp một cách nguyên tử, hỗ trợ chạy thử và báo xung đột theo từng hunk",
 		MsgToolExec:            "Thực thi lệnh shell trong workspace và trả về kết quả",
 		MsgToolWebSearch:       "Tìm kiếm thông tin trên web bằng công cụ tìm kiếm (Brave hoặc DuckDuckGo)",
 		MsgToolWebFetch:        "Tải trang web hoặc API endpoint và trích xuất nội dung văn bản",
 		MsgToolMemorySearch:    "Tìm kiếm trong bộ nhớ dài hạn của agent bằng độ tương đồng ngữ nghĩa",
 		MsgToolMemoryGet:       "Lấy tài liệu bộ nhớ cụ thể theo đường dẫn tệp",
 		MsgToolKGSearch:        "Tìm kiếm thực thể, quan hệ và ghi chú trong đồ thị tri thức của agent",
+		MsgToolKBSearch:        "Tìm kiếm trong các cơ sở tri thức tài liệu gắn với agent và trả về đoạn trích kèm nguồn",
 		MsgToolReadImage:       "Phân tích hình ảnh bằng nhà cung cấp LLM có khả năng nhìn",
 		MsgToolReadDocument:    "Phân tích tài liệu (PDF, Word, Excel, PowerPoint, CSV, v.v.) bằng LLM",
 		MsgToolCreateImage:     "Tạo hình ảnh từ mô tả văn bản bằng nhà cung cấp tạo ảnh AI",
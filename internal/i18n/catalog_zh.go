@@ -44,6 +44,7 @@ func init() {
 
 		// Exec approval
 		MsgExecApprovalDisabled: "执行审批未启用",
+		MsgToolApprovalDisabled: "工具审批未启用",
 
 		// Pairing
 		MsgSenderChannelRequired: "senderId 和 channel 是必填项",
@@ -66,6 +67,7 @@ func init() {
 		MsgSummoningUnavailable:  "召唤功能不可用",
 		MsgNoDescription:         "Agent没有可供重新召唤的描述",
 		MsgInvalidPath:           "路径无效",
+		MsgEmbeddingsDisabled:    "未配置嵌入提供商",
 
 		// Scheduler
 		MsgQueueFull:    "Session队列已满",
@@ -142,13 +144,16 @@ func init() {
 		MsgToolReadFile:        "按路径读取代理工作区中的文件内容",
 		MsgToolWriteFile:       "将内容写入工作区中的文件，自动创建所需目录",
 		MsgToolListFiles:       "列出工作区指定路径中的文件和目录",
+		MsgToolSearchFiles:     "按正则或文本搜索文件内容，并按 glob 在工作区中查找文件",
 		MsgToolEdit:            "通过查找和替换对现有文件进行定向编辑，无需重写整个文件",
+		MsgToolApplyPatch:      "原子地对多个文件应用补丁（unified diff 或 Begin Patch 格式），支持试运行并按 hunk 报告冲突",
 		MsgToolExec:            "在工作区中执行 shell 命令并返回标准输出/错误",
 		MsgToolWebSearch:       "使用搜索引擎（Brave 或 DuckDuckGo）在网络上搜索信息",
 		MsgToolWebFetch:        "获取网页或 API 端点并提取其文本内容",
 		MsgToolMemorySearch:    "使用语义相似度搜索代理的长期记忆",
 		MsgToolMemoryGet:       "按文件路径检索特定的记忆文档",
 		MsgToolKGSearch:        "搜索代理知识图谱中的实体、关系和观察记录",
+		MsgToolKBSearch:        "搜索代理关联的文档知识库，返回带出处的段落",
 		MsgToolReadImage:       "使用具有视觉能力的 LLM 提供商分析图像",
 		MsgToolReadDocument:    "使用 LLM 分析文档（PDF、Word、Excel、PowerPoint、CSV 等）",
 		MsgToolCreateImage:     "使用 AI 图像生成提供商从文本提示生成图像",
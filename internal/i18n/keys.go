@@ -45,6 +45,7 @@ const (
 
 	// --- Exec approval ---
 	MsgExecApprovalDisabled = "error.exec_approval_disabled" // "exec approval is not enabled"
+	MsgToolApprovalDisabled = "error.tool_approval_disabled" // "tool approval is not enabled"
 
 	// --- Pairing ---
 	MsgSenderChannelRequired = "error.sender_channel_required" // "senderId and channel are required"
@@ -67,6 +68,7 @@ const (
 	MsgSummoningUnavailable = "error.summoning_unavailable"     // "summoning not available"
 	MsgNoDescription        = "error.no_description"            // "agent has no description to resummon from"
 	MsgInvalidPath          = "error.invalid_path"              // "invalid path"
+	MsgEmbeddingsDisabled   = "error.embeddings_disabled"       // "no embedding provider is configured"
 
 	// --- Scheduler ---
 	MsgQueueFull       = "error.queue_full"       // "session queue is full"
@@ -143,13 +145,16 @@ const (
 	MsgToolReadFile          = "core.tool.read_file"
 	MsgToolWriteFile         = "core.tool.write_file"
 	MsgToolListFiles         = "core.tool.list_files"
+	MsgToolSearchFiles       = "core.tool.search_files"
 	MsgToolEdit              = "core.tool.edit"
+	MsgToolApplyPatch        = "core.tool.apply_patch"
 	MsgToolExec              = "core.tool.exec"
 	MsgToolWebSearch         = "core.tool.web_search"
 	MsgToolWebFetch          = "core.tool.web_fetch"
 	MsgToolMemorySearch      = "core.tool.memory_search"
 	MsgToolMemoryGet         = "core.tool.memory_get"
 	MsgToolKGSearch          = "core.tool.knowledge_graph_search"
+	MsgToolKBSearch          = "core.tool.kb_search"
 	MsgToolReadImage         = "core.tool.read_image"
 	MsgToolReadDocument      = "core.tool.read_document"
 	MsgToolCreateImage       = "core.tool.create_image"
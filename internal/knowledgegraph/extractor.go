@@ -3,6 +3,7 @@ package knowledgegraph
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -70,9 +71,10 @@ func (e *Extractor) extractChunk(ctx context.Context, text string) (*ExtractionR
 			"max_tokens":  8192,
 			"temperature": 0.2,
 		},
+		ResponseFormat: providers.JSONSchemaResponse("knowledge_graph", extractionSchema),
 	}
 
-	resp, err := e.provider.Chat(ctx, req)
+	resp, err := e.chat(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("kg extraction LLM call: %w", err)
 	}
@@ -85,7 +87,7 @@ func (e *Extractor) extractChunk(ctx context.Context, text string) (*ExtractionR
 			text = text[:retryMaxChars] + "\n\n[...truncated]"
 		}
 		req.Messages[1].Content = text
-		resp, err = e.provider.Chat(ctx, req)
+		resp, err = e.chat(ctx, req)
 		if err != nil {
 			return nil, fmt.Errorf("kg extraction LLM retry: %w", err)
 		}
@@ -94,7 +96,8 @@ func (e *Extractor) extractChunk(ctx context.Context, text string) (*ExtractionR
 		}
 	}
 
-	// Parse JSON response
+	// Parse JSON response. Schema-validated output parses as-is; the cleanup
+	// below covers providers that ignore response_format.
 	var result ExtractionResult
 	content := strings.TrimSpace(resp.Content)
 	content = stripCodeBlock(content)
@@ -143,6 +146,18 @@ func (e *Extractor) extractChunk(ctx context.Context, text string) (*ExtractionR
 	return filtered, nil
 }
 
+// chat requests schema-constrained output. Output that still fails validation
+// after the repair retry is returned without error for lenient parsing.
+func (e *Extractor) chat(ctx context.Context, req providers.ChatRequest) (*providers.ChatResponse, error) {
+	resp, err := providers.ChatStructured(ctx, e.provider, req)
+	var invalid *providers.StructuredOutputError
+	if errors.As(err, &invalid) {
+		slog.Debug("kg extraction: output failed schema validation", "error", err)
+		return resp, nil
+	}
+	return resp, err
+}
+
 // sanitizeJSON fixes common LLM JSON issues while preserving string values.
 // It walks the JSON character-by-character, only applying fixes outside quoted strings:
 //   - Malformed decimals: "0. 85" → "0.85"
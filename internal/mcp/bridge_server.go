@@ -19,11 +19,13 @@ import (
 // Excluded: spawn (agent loop), create_forum_topic (channels).
 var BridgeToolNames = map[string]bool{
 	// Filesystem
-	"read_file":  true,
-	"write_file": true,
-	"list_files": true,
-	"edit":       true,
-	"exec":       true,
+	"read_file":    true,
+	"write_file":   true,
+	"list_files":   true,
+	"search_files": true,
+	"edit":         true,
+	"apply_patch":  true,
+	"exec":         true,
 	// Web
 	"web_search": true,
 	"web_fetch":  true,
@@ -24,6 +24,7 @@ type BridgeTool struct {
 	requiredSet    map[string]bool
 	client         *mcpclient.Client
 	timeoutSec     int
+	readOnly       bool // server declared readOnlyHint
 	connected      *atomic.Bool
 }
 
@@ -55,6 +56,7 @@ func NewBridgeTool(serverName string, mcpTool mcpgo.Tool, client *mcpclient.Clie
 		requiredSet:    reqSet,
 		client:         client,
 		timeoutSec:     timeoutSec,
+		readOnly:       mcpTool.Annotations.ReadOnlyHint != nil && *mcpTool.Annotations.ReadOnlyHint,
 		connected:      connected,
 	}
 }
@@ -94,6 +96,10 @@ func (t *BridgeTool) OriginalName() string { return t.toolName }
 // IsConnected returns whether the underlying MCP server connection is healthy.
 func (t *BridgeTool) IsConnected() bool { return t.connected.Load() }
 
+// ReadOnly reports whether the MCP server annotated this tool as read-only.
+// Implements tools.ReadOnlyTool.
+func (t *BridgeTool) ReadOnly() bool { return t.readOnly }
+
 func (t *BridgeTool) Execute(ctx context.Context, args map[string]any) *tools.Result {
 	if !t.connected.Load() {
 		return tools.ErrorResult(fmt.Sprintf("MCP server %q is disconnected", t.serverName))
@@ -1,12 +1,9 @@
 package memory
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
-	"encoding/json"
 	"fmt"
-	"io"
 	"math"
 	"net/http"
 	"strings"
@@ -137,13 +134,14 @@ type EmbeddingProvider interface {
 }
 
 // OpenAIEmbeddingProvider uses the OpenAI-compatible embedding API.
-// Works with OpenAI, OpenRouter, and any compatible endpoint.
+// Works with OpenAI, OpenRouter, Mistral, DashScope and any compatible endpoint.
 type OpenAIEmbeddingProvider struct {
 	name       string
 	model      string
 	apiKey     string
 	apiURL     string
 	dimensions int // optional: truncate output to this many dimensions (0 = use model default)
+	limits     BatchLimits
 }
 
 // NewOpenAIEmbeddingProvider creates a provider for OpenAI-compatible embedding APIs.
@@ -172,6 +170,22 @@ func (p *OpenAIEmbeddingProvider) WithDimensions(d int) *OpenAIEmbeddingProvider
 func (p *OpenAIEmbeddingProvider) Name() string  { return p.name }
 func (p *OpenAIEmbeddingProvider) Model() string { return p.model }
 
+// WithBatchLimits overrides the request size caps for compatible endpoints
+// that are stricter than OpenAI.
+func (p *OpenAIEmbeddingProvider) WithBatchLimits(l BatchLimits) *OpenAIEmbeddingProvider {
+	p.limits = l
+	return p
+}
+
+// BatchLimits reports the request caps: OpenAI's 2048 inputs and roughly
+// 300k tokens per request unless overridden.
+func (p *OpenAIEmbeddingProvider) BatchLimits() BatchLimits {
+	if p.limits.MaxTexts > 0 || p.limits.MaxChars > 0 {
+		return p.limits
+	}
+	return BatchLimits{MaxTexts: 2048, MaxChars: 600_000}
+}
+
 func (p *OpenAIEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
 	reqBody := map[string]any{
 		"input": texts,
@@ -181,28 +195,9 @@ func (p *OpenAIEmbeddingProvider) Embed(ctx context.Context, texts []string) ([]
 		reqBody["dimensions"] = p.dimensions
 	}
 
-	bodyJSON, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("marshal request: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.apiURL+"/embeddings", bytes.NewReader(bodyJSON))
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+p.apiKey)
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("embedding request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("embedding API error %d: %s", resp.StatusCode, string(body))
+	header := http.Header{}
+	if p.apiKey != "" {
+		header.Set("Authorization", "Bearer "+p.apiKey)
 	}
 
 	var result struct {
@@ -210,9 +205,8 @@ func (p *OpenAIEmbeddingProvider) Embed(ctx context.Context, texts []string) ([]
 			Embedding []float32 `json:"embedding"`
 		} `json:"data"`
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if err := postEmbeddingJSON(ctx, p.apiURL+"/embeddings", header, reqBody, &result); err != nil {
+		return nil, err
 	}
 
 	embeddings := make([][]float32, len(result.Data))
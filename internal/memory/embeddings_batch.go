@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"golang.org/x/time/rate"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+// defaultEmbedBatchTexts caps requests to providers that don't report limits.
+const defaultEmbedBatchTexts = 64
+
+// BatchOptions configures a BatchingEmbeddingProvider. Zero values fall back
+// to the wrapped provider's BatchLimits and providers.DefaultRetryConfig.
+type BatchOptions struct {
+	MaxTexts          int                   // inputs per request
+	MaxChars          int                   // total input characters per request
+	RequestsPerMinute int                   // request rate cap (0 = unlimited)
+	Dimensions        int                   // fit every vector to this size (0 = keep as returned)
+	Retry             providers.RetryConfig // retry policy for each request
+}
+
+// BatchingEmbeddingProvider splits large Embed calls into requests the
+// wrapped API accepts, paces them, retries rate limits and server errors via
+// providers.RetryDo, and fits vectors to a fixed dimension.
+type BatchingEmbeddingProvider struct {
+	inner       EmbeddingProvider
+	limits      BatchLimits
+	dims        int
+	retry       providers.RetryConfig
+	limiter     *rate.Limiter
+	fingerprint string
+}
+
+// NewBatchingEmbeddingProvider wraps inner with batching, pacing and retries.
+func NewBatchingEmbeddingProvider(inner EmbeddingProvider, opts BatchOptions) *BatchingEmbeddingProvider {
+	limits := BatchLimits{MaxTexts: opts.MaxTexts, MaxChars: opts.MaxChars}
+	if bl, ok := inner.(batchLimited); ok {
+		pl := bl.BatchLimits()
+		if limits.MaxTexts <= 0 {
+			limits.MaxTexts = pl.MaxTexts
+		}
+		if limits.MaxChars <= 0 {
+			limits.MaxChars = pl.MaxChars
+		}
+	}
+	if limits.MaxTexts <= 0 {
+		limits.MaxTexts = defaultEmbedBatchTexts
+	}
+
+	retry := opts.Retry
+	if retry.Attempts == 0 {
+		retry = providers.DefaultRetryConfig()
+	}
+
+	p := &BatchingEmbeddingProvider{inner: inner, limits: limits, dims: opts.Dimensions, retry: retry}
+	if opts.RequestsPerMinute > 0 {
+		p.limiter = rate.NewLimiter(rate.Limit(float64(opts.RequestsPerMinute)/60), 1)
+	}
+	return p
+}
+
+func (p *BatchingEmbeddingProvider) Name() string  { return p.inner.Name() }
+func (p *BatchingEmbeddingProvider) Model() string { return p.inner.Model() }
+
+// WithFingerprint records the EmbeddingFingerprint of the wrapped client.
+func (p *BatchingEmbeddingProvider) WithFingerprint(fp string) *BatchingEmbeddingProvider {
+	p.fingerprint = fp
+	return p
+}
+
+func (p *BatchingEmbeddingProvider) Fingerprint() string { return p.fingerprint }
+
+// Embed returns one vector per text, in order. It fails if any request still
+// fails after retries, so callers never get a partially embedded batch.
+func (p *BatchingEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	out := make([][]float32, 0, len(texts))
+	for _, batch := range splitEmbeddingBatches(texts, p.limits) {
+		embs, err := providers.RetryDo(ctx, p.retry, func() ([][]float32, error) {
+			if p.limiter != nil {
+				if err := p.limiter.Wait(ctx); err != nil {
+					return nil, err
+				}
+			}
+			embs, err := p.inner.Embed(ctx, batch)
+			if err == nil && len(embs) != len(batch) {
+				return nil, fmt.Errorf("embedding API returned %d vectors for %d inputs", len(embs), len(batch))
+			}
+			return embs, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, emb := range embs {
+			out = append(out, fitDimensions(emb, p.dims))
+		}
+	}
+	return out, nil
+}
+
+// splitEmbeddingBatches groups texts into consecutive batches within l.
+// A single text larger than MaxChars is sent on its own.
+func splitEmbeddingBatches(texts []string, l BatchLimits) [][]string {
+	var batches [][]string
+	start, chars := 0, 0
+	for i, t := range texts {
+		full := (l.MaxTexts > 0 && i-start >= l.MaxTexts) ||
+			(l.MaxChars > 0 && i > start && chars+len(t) > l.MaxChars)
+		if full {
+			batches = append(batches, texts[start:i])
+			start, chars = i, 0
+		}
+		chars += len(t)
+	}
+	return append(batches, texts[start:])
+}
+
+// fitDimensions resizes v to dims. Shorter vectors are zero-padded, which
+// leaves cosine similarity between them unchanged; longer ones are truncated
+// and re-normalised, which preserves ranking for Matryoshka-trained models
+// (OpenAI v3, Gemini, Voyage, Cohere v4). Empty vectors and dims <= 0 are
+// returned unchanged.
+func fitDimensions(v []float32, dims int) []float32 {
+	if dims <= 0 || len(v) == 0 || len(v) == dims {
+		return v
+	}
+	if len(v) < dims {
+		padded := make([]float32, dims)
+		copy(padded, v)
+		return padded
+	}
+
+	truncated := make([]float32, dims)
+	copy(truncated, v[:dims])
+	var norm float64
+	for _, x := range truncated {
+		norm += float64(x) * float64(x)
+	}
+	if norm = math.Sqrt(norm); norm > 0 {
+		for i := range truncated {
+			truncated[i] = float32(float64(truncated[i]) / norm)
+		}
+	}
+	return truncated
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+// countingEmbedder returns [len(text)] for each text and records each call.
+type countingEmbedder struct {
+	mu       sync.Mutex
+	calls    [][]string
+	failures []error // returned by the first calls, in order
+}
+
+func (e *countingEmbedder) Name() string  { return "counting" }
+func (e *countingEmbedder) Model() string { return "m1" }
+
+func (e *countingEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.calls = append(e.calls, texts)
+	if len(e.failures) > 0 {
+		err := e.failures[0]
+		e.failures = e.failures[1:]
+		return nil, err
+	}
+	out := make([][]float32, len(texts))
+	for i, t := range texts {
+		out[i] = []float32{float32(len(t))}
+	}
+	return out, nil
+}
+
+func fastRetry() providers.RetryConfig {
+	return providers.RetryConfig{Attempts: 3, MinDelay: time.Millisecond, MaxDelay: time.Millisecond}
+}
+
+func TestBatchingEmbeddingProviderSplitsRequests(t *testing.T) {
+	inner := &countingEmbedder{}
+	p := NewBatchingEmbeddingProvider(inner, BatchOptions{MaxTexts: 3, MaxChars: 10, Retry: fastRetry()})
+
+	texts := []string{"a", "bb", "ccc", "dddd", strings.Repeat("e", 20), "f"}
+	vecs, err := p.Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	for i, v := range vecs {
+		if v[0] != float32(len(texts[i])) {
+			t.Fatalf("vector %d = %v, out of order", i, v)
+		}
+	}
+	// 3 texts max; 10 chars max; the 20-char text goes alone.
+	want := [][]string{{"a", "bb", "ccc"}, {"dddd"}, {strings.Repeat("e", 20)}, {"f"}}
+	if !reflect.DeepEqual(inner.calls, want) {
+		t.Errorf("calls = %q, want %q", inner.calls, want)
+	}
+}
+
+func TestBatchingEmbeddingProviderRetries(t *testing.T) {
+	inner := &countingEmbedder{failures: []error{
+		fmt.Errorf("embedding API error: %w", &providers.HTTPError{Status: 429}),
+		fmt.Errorf("embedding API error: %w", &providers.HTTPError{Status: 503}),
+	}}
+	p := NewBatchingEmbeddingProvider(inner, BatchOptions{Retry: fastRetry()})
+	if _, err := p.Embed(context.Background(), []string{"x"}); err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if len(inner.calls) != 3 {
+		t.Errorf("calls = %d, want 3", len(inner.calls))
+	}
+
+	// Client errors are not retried.
+	inner = &countingEmbedder{failures: []error{&providers.HTTPError{Status: 401}}}
+	p = NewBatchingEmbeddingProvider(inner, BatchOptions{Retry: fastRetry()})
+	_, err := p.Embed(context.Background(), []string{"x"})
+	var httpErr *providers.HTTPError
+	if !errors.As(err, &httpErr) || len(inner.calls) != 1 {
+		t.Errorf("err = %v after %d calls, want 401 after 1", err, len(inner.calls))
+	}
+}
+
+func TestFitDimensions(t *testing.T) {
+	padded := fitDimensions([]float32{3, 4}, 4)
+	if !reflect.DeepEqual(padded, []float32{3, 4, 0, 0}) {
+		t.Errorf("padded = %v", padded)
+	}
+	if got := CosineSimilarity(fitDimensions([]float32{1, 2}, 4), fitDimensions([]float32{2, 1}, 4)); math.Abs(got-0.8) > 1e-6 {
+		t.Errorf("padding changed cosine similarity: %v", got)
+	}
+
+	truncated := fitDimensions([]float32{3, 4, 12}, 2)
+	if math.Abs(float64(truncated[0])-0.6) > 1e-6 || math.Abs(float64(truncated[1])-0.8) > 1e-6 {
+		t.Errorf("truncated = %v, want normalised [0.6 0.8]", truncated)
+	}
+	if fitDimensions(nil, 4) != nil {
+		t.Error("empty vector should stay empty")
+	}
+}
+
+// mapCache is an in-memory EmbeddingCache.
+type mapCache struct {
+	entries map[string][]float32
+	writes  int
+}
+
+func (c *mapCache) LookupEmbeddings(_ context.Context, hashes []string, provider, model string) (map[string][]float32, error) {
+	out := make(map[string][]float32)
+	for _, h := range hashes {
+		if v, ok := c.entries[provider+"/"+model+"/"+h]; ok {
+			out[h] = v
+		}
+	}
+	return out, nil
+}
+
+func (c *mapCache) WriteEmbeddings(_ context.Context, entries map[string][]float32, provider, model string) error {
+	for h, v := range entries {
+		c.entries[provider+"/"+model+"/"+h] = v
+		c.writes++
+	}
+	return nil
+}
+
+func TestCachedEmbeddingProvider(t *testing.T) {
+	inner := &countingEmbedder{}
+	cache := &mapCache{entries: map[string][]float32{}}
+	p := NewCachedEmbeddingProvider(inner, cache)
+	ctx := context.Background()
+
+	vecs, err := p.Embed(ctx, []string{"alpha", "be", "alpha"})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if !reflect.DeepEqual(vecs, [][]float32{{5}, {2}, {5}}) {
+		t.Errorf("vectors = %v", vecs)
+	}
+	if !reflect.DeepEqual(inner.calls, [][]string{{"alpha", "be"}}) {
+		t.Errorf("calls = %q, want duplicates embedded once", inner.calls)
+	}
+	if cache.writes != 2 {
+		t.Errorf("cache writes = %d, want 2", cache.writes)
+	}
+
+	// A second store sharing the provider only embeds what is new.
+	if _, err := p.Embed(ctx, []string{"be", "gamma"}); err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if last := inner.calls[len(inner.calls)-1]; !reflect.DeepEqual(last, []string{"gamma"}) {
+		t.Errorf("second call = %q, want only the miss", last)
+	}
+
+	// Fully cached input makes no API call.
+	before := len(inner.calls)
+	if _, err := p.Embed(ctx, []string{"alpha", "gamma"}); err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	if len(inner.calls) != before {
+		t.Error("cached texts should not be embedded again")
+	}
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// EmbeddingCache persists embeddings by content hash, per provider and model.
+type EmbeddingCache interface {
+	LookupEmbeddings(ctx context.Context, hashes []string, provider, model string) (map[string][]float32, error)
+	WriteEmbeddings(ctx context.Context, entries map[string][]float32, provider, model string) error
+}
+
+// CachedEmbeddingProvider serves previously embedded texts from an
+// EmbeddingCache and embeds only the rest. Stores sharing one instance share
+// the cache, so text embedded for memory isn't embedded again for skills,
+// team tasks, the knowledge graph or knowledge bases.
+type CachedEmbeddingProvider struct {
+	inner EmbeddingProvider
+	cache EmbeddingCache
+}
+
+// NewCachedEmbeddingProvider wraps inner with a content-hash cache.
+func NewCachedEmbeddingProvider(inner EmbeddingProvider, cache EmbeddingCache) *CachedEmbeddingProvider {
+	return &CachedEmbeddingProvider{inner: inner, cache: cache}
+}
+
+func (p *CachedEmbeddingProvider) Name() string  { return p.inner.Name() }
+func (p *CachedEmbeddingProvider) Model() string { return p.inner.Model() }
+
+// Fingerprint forwards the wrapped provider's fingerprint ("" when unknown).
+func (p *CachedEmbeddingProvider) Fingerprint() string {
+	if fp, ok := p.inner.(Fingerprinted); ok {
+		return fp.Fingerprint()
+	}
+	return ""
+}
+
+// Embed returns one vector per text. Cache failures only cost extra API
+// calls; embedding failures are returned.
+func (p *CachedEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	name, model := p.inner.Name(), p.inner.Model()
+
+	hashes := make([]string, len(texts))
+	for i, t := range texts {
+		hashes[i] = ContentHash(t)
+	}
+	cached, err := p.cache.LookupEmbeddings(ctx, hashes, name, model)
+	if err != nil {
+		slog.Warn("embedding cache lookup failed, embedding all texts", "provider", name, "error", err)
+		cached = nil
+	}
+
+	out := make([][]float32, len(texts))
+	missIdxs := make(map[string][]int)
+	var missHashes []string
+	var missTexts []string
+	for i, h := range hashes {
+		if emb, ok := cached[h]; ok {
+			out[i] = emb
+			continue
+		}
+		if _, seen := missIdxs[h]; !seen {
+			missHashes = append(missHashes, h)
+			missTexts = append(missTexts, texts[i])
+		}
+		missIdxs[h] = append(missIdxs[h], i)
+	}
+	if len(missTexts) == 0 {
+		return out, nil
+	}
+
+	fresh, err := p.inner.Embed(ctx, missTexts)
+	if err != nil {
+		return nil, err
+	}
+	if len(fresh) != len(missTexts) {
+		return nil, fmt.Errorf("embedding API returned %d vectors for %d inputs", len(fresh), len(missTexts))
+	}
+
+	entries := make(map[string][]float32, len(fresh))
+	for j, emb := range fresh {
+		h := missHashes[j]
+		for _, idx := range missIdxs[h] {
+			out[idx] = emb
+		}
+		if len(emb) > 0 {
+			entries[h] = emb
+		}
+	}
+	if err := p.cache.WriteEmbeddings(ctx, entries, name, model); err != nil {
+		slog.Warn("embedding cache write failed", "provider", name, "error", err)
+	}
+	return out, nil
+}
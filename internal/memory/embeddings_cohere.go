@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// CohereEmbeddingProvider uses the Cohere v2 embed API.
+type CohereEmbeddingProvider struct {
+	name       string
+	model      string
+	apiKey     string
+	apiURL     string
+	dimensions int
+}
+
+// NewCohereEmbeddingProvider creates a provider for the Cohere embed API.
+// apiURL is the API root without the version path (a trailing "/v1" or "/v2"
+// from an OpenAI-compatible base is trimmed).
+func NewCohereEmbeddingProvider(name, apiKey, apiURL, model string) *CohereEmbeddingProvider {
+	apiURL = strings.TrimRight(apiURL, "/")
+	apiURL = strings.TrimSuffix(strings.TrimSuffix(apiURL, "/compatibility/v1"), "/v1")
+	apiURL = strings.TrimSuffix(apiURL, "/v2")
+	if apiURL == "" {
+		apiURL = "https://api.cohere.com"
+	}
+	if model == "" {
+		model = "embed-v4.0"
+	}
+	return &CohereEmbeddingProvider{name: name, model: model, apiKey: apiKey, apiURL: apiURL}
+}
+
+// WithDimensions requests d output dimensions. Only embed-v4 models support
+// it; older models return their native size.
+func (p *CohereEmbeddingProvider) WithDimensions(d int) *CohereEmbeddingProvider {
+	p.dimensions = d
+	return p
+}
+
+func (p *CohereEmbeddingProvider) Name() string  { return p.name }
+func (p *CohereEmbeddingProvider) Model() string { return p.model }
+
+// BatchLimits reports the embed API cap of 96 texts per call.
+func (p *CohereEmbeddingProvider) BatchLimits() BatchLimits {
+	return BatchLimits{MaxTexts: 96, MaxChars: 400_000}
+}
+
+func (p *CohereEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	// The same vectors serve stored passages and search queries, so embed
+	// everything as documents; Cohere keeps both input types in one space.
+	reqBody := map[string]any{
+		"model":           p.model,
+		"texts":           texts,
+		"input_type":      "search_document",
+		"embedding_types": []string{"float"},
+	}
+	if p.dimensions > 0 && strings.HasPrefix(p.model, "embed-v4") {
+		reqBody["output_dimension"] = p.dimensions
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+p.apiKey)
+
+	var result struct {
+		Embeddings struct {
+			Float [][]float32 `json:"float"`
+		} `json:"embeddings"`
+	}
+	if err := postEmbeddingJSON(ctx, p.apiURL+"/v2/embed", header, reqBody, &result); err != nil {
+		return nil, err
+	}
+	return result.Embeddings.Float, nil
+}
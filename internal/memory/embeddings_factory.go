@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Embedding API identifiers accepted by NewEmbeddingProvider.
+const (
+	EmbeddingAPIOpenAI    = "openai"
+	EmbeddingAPIGemini    = "gemini"
+	EmbeddingAPICohere    = "cohere"
+	EmbeddingAPIVoyage    = "voyage"
+	EmbeddingAPIMistral   = "mistral"
+	EmbeddingAPIDashScope = "dashscope"
+	EmbeddingAPIOllama    = "ollama"
+)
+
+// NewEmbeddingProvider creates the client for the named embedding API.
+// Empty apiURL and model select the API's defaults. dims is passed to APIs
+// and models that can produce that size natively (0 = model default);
+// wrap the result in a BatchingEmbeddingProvider to guarantee it.
+func NewEmbeddingProvider(api, name, apiKey, apiURL, model string, dims int) (EmbeddingProvider, error) {
+	switch api {
+	case "", EmbeddingAPIOpenAI:
+		return NewOpenAIEmbeddingProvider(name, apiKey, apiURL, model).WithDimensions(dims), nil
+	case EmbeddingAPIGemini:
+		return NewGeminiEmbeddingProvider(name, apiKey, apiURL, model).WithDimensions(dims), nil
+	case EmbeddingAPICohere:
+		return NewCohereEmbeddingProvider(name, apiKey, apiURL, model).WithDimensions(dims), nil
+	case EmbeddingAPIVoyage:
+		return NewVoyageEmbeddingProvider(name, apiKey, apiURL, model), nil
+	case EmbeddingAPIMistral:
+		return NewMistralEmbeddingProvider(name, apiKey, apiURL, model), nil
+	case EmbeddingAPIDashScope:
+		p := NewDashScopeEmbeddingProvider(name, apiKey, apiURL, model)
+		if p.Model() == "text-embedding-v4" {
+			p.WithDimensions(dims)
+		}
+		return p, nil
+	case EmbeddingAPIOllama:
+		return NewOllamaEmbeddingProvider(name, apiKey, apiURL, model), nil
+	}
+	return nil, fmt.Errorf("unknown embedding API %q", api)
+}
+
+// EmbeddingFingerprint identifies the vector space a client built by
+// NewEmbeddingProvider produces. Vectors with different fingerprints are not
+// comparable. The provider's display name is not part of it, so renaming a
+// provider keeps its vectors.
+func EmbeddingFingerprint(api, apiURL, model string, dims int) string {
+	if api == "" {
+		api = EmbeddingAPIOpenAI
+	}
+	return fmt.Sprintf("%s|%s|%s|%d", api, strings.TrimRight(apiURL, "/"), model, dims)
+}
+
+// Fingerprinted is implemented by providers that know their
+// EmbeddingFingerprint.
+type Fingerprinted interface {
+	Fingerprint() string
+}
+
+// NewMistralEmbeddingProvider creates a provider for Mistral's embeddings
+// endpoint, which is OpenAI-compatible but limits a request to 16k tokens.
+func NewMistralEmbeddingProvider(name, apiKey, apiURL, model string) *OpenAIEmbeddingProvider {
+	if apiURL == "" {
+		apiURL = "https://api.mistral.ai/v1"
+	}
+	if model == "" {
+		model = "mistral-embed"
+	}
+	return NewOpenAIEmbeddingProvider(name, apiKey, apiURL, model).
+		WithBatchLimits(BatchLimits{MaxTexts: 128, MaxChars: 48_000})
+}
+
+// NewDashScopeEmbeddingProvider creates a provider for DashScope's
+// OpenAI-compatible embeddings endpoint, which accepts 10 inputs per request.
+func NewDashScopeEmbeddingProvider(name, apiKey, apiURL, model string) *OpenAIEmbeddingProvider {
+	if apiURL == "" {
+		apiURL = "https://dashscope-intl.aliyuncs.com/compatible-mode/v1"
+	}
+	if model == "" {
+		model = "text-embedding-v4"
+	}
+	return NewOpenAIEmbeddingProvider(name, apiKey, apiURL, model).
+		WithBatchLimits(BatchLimits{MaxTexts: 10, MaxChars: 80_000})
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// GeminiEmbeddingProvider uses the Gemini batchEmbedContents API.
+type GeminiEmbeddingProvider struct {
+	name       string
+	model      string
+	apiKey     string
+	apiURL     string
+	dimensions int
+}
+
+// NewGeminiEmbeddingProvider creates a provider for the Gemini embedding API.
+// apiURL is the versioned API root; an OpenAI-compatibility base
+// (".../v1beta/openai") is accepted and trimmed to its native root.
+func NewGeminiEmbeddingProvider(name, apiKey, apiURL, model string) *GeminiEmbeddingProvider {
+	apiURL = strings.TrimSuffix(strings.TrimRight(apiURL, "/"), "/openai")
+	if apiURL == "" {
+		apiURL = "https://generativelanguage.googleapis.com/v1beta"
+	}
+	if model == "" {
+		model = "gemini-embedding-001"
+	}
+	return &GeminiEmbeddingProvider{
+		name:   name,
+		model:  strings.TrimPrefix(model, "models/"),
+		apiKey: apiKey,
+		apiURL: apiURL,
+	}
+}
+
+// WithDimensions requests output truncated to d dimensions. Only the
+// gemini-embedding models support it; others return their native size.
+func (p *GeminiEmbeddingProvider) WithDimensions(d int) *GeminiEmbeddingProvider {
+	p.dimensions = d
+	return p
+}
+
+func (p *GeminiEmbeddingProvider) Name() string  { return p.name }
+func (p *GeminiEmbeddingProvider) Model() string { return p.model }
+
+// BatchLimits reports the batchEmbedContents cap of 100 requests per call.
+func (p *GeminiEmbeddingProvider) BatchLimits() BatchLimits {
+	return BatchLimits{MaxTexts: 100, MaxChars: 400_000}
+}
+
+func (p *GeminiEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	type part struct {
+		Text string `json:"text"`
+	}
+	type content struct {
+		Parts []part `json:"parts"`
+	}
+	type embedRequest struct {
+		Model                string  `json:"model"`
+		Content              content `json:"content"`
+		OutputDimensionality int     `json:"outputDimensionality,omitempty"`
+	}
+
+	dims := 0
+	if strings.HasPrefix(p.model, "gemini-embedding") {
+		dims = p.dimensions
+	}
+	requests := make([]embedRequest, len(texts))
+	for i, t := range texts {
+		requests[i] = embedRequest{
+			Model:                "models/" + p.model,
+			Content:              content{Parts: []part{{Text: t}}},
+			OutputDimensionality: dims,
+		}
+	}
+
+	header := http.Header{}
+	header.Set("x-goog-api-key", p.apiKey)
+
+	var result struct {
+		Embeddings []struct {
+			Values []float32 `json:"values"`
+		} `json:"embeddings"`
+	}
+	url := p.apiURL + "/models/" + p.model + ":batchEmbedContents"
+	if err := postEmbeddingJSON(ctx, url, header, map[string]any{"requests": requests}, &result); err != nil {
+		return nil, err
+	}
+
+	embeddings := make([][]float32, len(result.Embeddings))
+	for i, e := range result.Embeddings {
+		embeddings[i] = e.Values
+	}
+	return embeddings, nil
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+// BatchLimits caps the size of a single embedding request.
+type BatchLimits struct {
+	MaxTexts int // inputs per request (0 = no limit)
+	MaxChars int // total input characters per request (0 = no limit)
+}
+
+// batchLimited is implemented by providers whose API caps request size.
+// BatchingEmbeddingProvider uses it when no explicit limits are configured.
+type batchLimited interface {
+	BatchLimits() BatchLimits
+}
+
+// postEmbeddingJSON POSTs body as JSON and decodes a 200 response into out.
+// Other statuses are returned as *providers.HTTPError (wrapped) so RetryDo can
+// tell rate limits and server errors from permanent failures.
+func postEmbeddingJSON(ctx context.Context, url string, header http.Header, body, out any) error {
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyJSON))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("embedding request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("embedding API error: %w", &providers.HTTPError{
+			Status:     resp.StatusCode,
+			Body:       string(respBody),
+			RetryAfter: providers.ParseRetryAfter(resp.Header.Get("Retry-After")),
+		})
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
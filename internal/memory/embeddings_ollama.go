@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// OllamaEmbeddingProvider uses Ollama's native /api/embed endpoint, which
+// takes a batch of inputs in one call.
+type OllamaEmbeddingProvider struct {
+	name   string
+	model  string
+	apiKey string
+	apiURL string
+}
+
+// NewOllamaEmbeddingProvider creates a provider for a local or hosted Ollama
+// server. apiURL may be the server root or its OpenAI-compatible "/v1" base;
+// apiKey is only needed for Ollama Cloud.
+func NewOllamaEmbeddingProvider(name, apiKey, apiURL, model string) *OllamaEmbeddingProvider {
+	apiURL = strings.TrimSuffix(strings.TrimRight(apiURL, "/"), "/v1")
+	if apiURL == "" {
+		apiURL = "http://localhost:11434"
+	}
+	if model == "" {
+		model = "nomic-embed-text"
+	}
+	return &OllamaEmbeddingProvider{name: name, model: model, apiKey: apiKey, apiURL: apiURL}
+}
+
+func (p *OllamaEmbeddingProvider) Name() string  { return p.name }
+func (p *OllamaEmbeddingProvider) Model() string { return p.model }
+
+// BatchLimits keeps local requests small so one call doesn't monopolise the
+// model server.
+func (p *OllamaEmbeddingProvider) BatchLimits() BatchLimits {
+	return BatchLimits{MaxTexts: 32, MaxChars: 64_000}
+}
+
+func (p *OllamaEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	header := http.Header{}
+	if p.apiKey != "" && p.apiKey != "ollama" {
+		header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	var result struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	reqBody := map[string]any{"model": p.model, "input": texts}
+	if err := postEmbeddingJSON(ctx, p.apiURL+"/api/embed", header, reqBody, &result); err != nil {
+		return nil, err
+	}
+	return result.Embeddings, nil
+}
@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+// standIn serves one canned embedding response and records the request.
+type standIn struct {
+	path   string
+	header http.Header
+	body   map[string]any
+}
+
+func newStandIn(t *testing.T, status int, response string) (*httptest.Server, *standIn) {
+	t.Helper()
+	got := &standIn{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.path = r.URL.Path
+		got.header = r.Header.Clone()
+		if err := json.NewDecoder(r.Body).Decode(&got.body); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, got
+}
+
+func TestNativeEmbeddingProviders(t *testing.T) {
+	want := [][]float32{{0.1, 0.2}, {0.3, 0.4}}
+	tests := []struct {
+		name     string
+		api      string
+		response string
+		path     string
+		authKey  string
+		authVal  string
+		check    func(t *testing.T, body map[string]any)
+	}{
+		{
+			name:     "gemini",
+			api:      EmbeddingAPIGemini,
+			response: `{"embeddings":[{"values":[0.1,0.2]},{"values":[0.3,0.4]}]}`,
+			path:     "/models/gemini-embedding-001:batchEmbedContents",
+			authKey:  "X-Goog-Api-Key",
+			authVal:  "key",
+			check: func(t *testing.T, body map[string]any) {
+				reqs := body["requests"].([]any)
+				first := reqs[0].(map[string]any)
+				if first["model"] != "models/gemini-embedding-001" || first["outputDimensionality"] != float64(1536) {
+					t.Errorf("request = %v", first)
+				}
+			},
+		},
+		{
+			name:     "cohere",
+			api:      EmbeddingAPICohere,
+			response: `{"embeddings":{"float":[[0.1,0.2],[0.3,0.4]]}}`,
+			path:     "/v2/embed",
+			authKey:  "Authorization",
+			authVal:  "Bearer key",
+			check: func(t *testing.T, body map[string]any) {
+				if body["input_type"] != "search_document" || body["output_dimension"] != float64(1536) {
+					t.Errorf("body = %v", body)
+				}
+			},
+		},
+		{
+			name:     "voyage",
+			api:      EmbeddingAPIVoyage,
+			response: `{"data":[{"embedding":[0.3,0.4],"index":1},{"embedding":[0.1,0.2],"index":0}]}`,
+			path:     "/embeddings",
+			authKey:  "Authorization",
+			authVal:  "Bearer key",
+			check: func(t *testing.T, body map[string]any) {
+				if body["model"] != "voyage-3.5" || body["input_type"] != "document" {
+					t.Errorf("body = %v", body)
+				}
+			},
+		},
+		{
+			name:     "ollama",
+			api:      EmbeddingAPIOllama,
+			response: `{"embeddings":[[0.1,0.2],[0.3,0.4]]}`,
+			path:     "/api/embed",
+			check: func(t *testing.T, body map[string]any) {
+				if body["model"] != "nomic-embed-text" {
+					t.Errorf("body = %v", body)
+				}
+			},
+		},
+		{
+			name:     "mistral",
+			api:      EmbeddingAPIMistral,
+			response: `{"data":[{"embedding":[0.1,0.2]},{"embedding":[0.3,0.4]}]}`,
+			path:     "/embeddings",
+			authKey:  "Authorization",
+			authVal:  "Bearer key",
+			check: func(t *testing.T, body map[string]any) {
+				if body["model"] != "mistral-embed" || body["dimensions"] != nil {
+					t.Errorf("body = %v", body)
+				}
+			},
+		},
+		{
+			name:     "dashscope",
+			api:      EmbeddingAPIDashScope,
+			response: `{"data":[{"embedding":[0.1,0.2]},{"embedding":[0.3,0.4]}]}`,
+			path:     "/embeddings",
+			authKey:  "Authorization",
+			authVal:  "Bearer key",
+			check: func(t *testing.T, body map[string]any) {
+				if body["model"] != "text-embedding-v4" || body["dimensions"] != float64(1536) {
+					t.Errorf("body = %v", body)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, got := newStandIn(t, http.StatusOK, tt.response)
+			key := "key"
+			if tt.api == EmbeddingAPIOllama {
+				key = ""
+			}
+			ep, err := NewEmbeddingProvider(tt.api, "p", key, srv.URL, "", 1536)
+			if err != nil {
+				t.Fatalf("NewEmbeddingProvider: %v", err)
+			}
+			vecs, err := ep.Embed(context.Background(), []string{"a", "b"})
+			if err != nil {
+				t.Fatalf("Embed: %v", err)
+			}
+			if !reflect.DeepEqual(vecs, want) {
+				t.Errorf("vectors = %v, want %v", vecs, want)
+			}
+			if got.path != tt.path {
+				t.Errorf("path = %q, want %q", got.path, tt.path)
+			}
+			if tt.authKey != "" && got.header.Get(tt.authKey) != tt.authVal {
+				t.Errorf("%s = %q, want %q", tt.authKey, got.header.Get(tt.authKey), tt.authVal)
+			}
+			tt.check(t, got.body)
+		})
+	}
+}
+
+func TestNewEmbeddingProviderUnknownAPI(t *testing.T) {
+	if _, err := NewEmbeddingProvider("bogus", "p", "k", "", "", 0); err == nil {
+		t.Fatal("expected error for unknown API")
+	}
+}
+
+func TestEmbeddingAPIErrorIsRetryable(t *testing.T) {
+	srv, _ := newStandIn(t, http.StatusTooManyRequests, `{"error":"slow down"}`)
+	_, err := NewOllamaEmbeddingProvider("p", "", srv.URL+"/v1", "").Embed(context.Background(), []string{"a"})
+	var httpErr *providers.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Status != http.StatusTooManyRequests {
+		t.Fatalf("err = %v, want wrapped HTTPError 429", err)
+	}
+	if !providers.IsRetryableError(err) {
+		t.Error("429 should be retryable")
+	}
+}
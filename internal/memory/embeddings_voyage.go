@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// VoyageEmbeddingProvider uses the Voyage AI embeddings API.
+type VoyageEmbeddingProvider struct {
+	name   string
+	model  string
+	apiKey string
+	apiURL string
+}
+
+// NewVoyageEmbeddingProvider creates a provider for the Voyage AI embeddings API.
+func NewVoyageEmbeddingProvider(name, apiKey, apiURL, model string) *VoyageEmbeddingProvider {
+	apiURL = strings.TrimRight(apiURL, "/")
+	if apiURL == "" {
+		apiURL = "https://api.voyageai.com/v1"
+	}
+	if model == "" {
+		model = "voyage-3.5"
+	}
+	return &VoyageEmbeddingProvider{name: name, model: model, apiKey: apiKey, apiURL: apiURL}
+}
+
+func (p *VoyageEmbeddingProvider) Name() string  { return p.name }
+func (p *VoyageEmbeddingProvider) Model() string { return p.model }
+
+// BatchLimits reports a conservative request size: Voyage allows 1000 inputs
+// but caps total tokens per request at 120k-320k depending on the model.
+func (p *VoyageEmbeddingProvider) BatchLimits() BatchLimits {
+	return BatchLimits{MaxTexts: 128, MaxChars: 400_000}
+}
+
+func (p *VoyageEmbeddingProvider) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	// Voyage supports 256/512/1024/2048 output dimensions only, so the native
+	// size is kept and fitted to the schema by BatchingEmbeddingProvider.
+	reqBody := map[string]any{
+		"input":      texts,
+		"model":      p.model,
+		"input_type": "document",
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+p.apiKey)
+
+	var result struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+			Index     int       `json:"index"`
+		} `json:"data"`
+	}
+	if err := postEmbeddingJSON(ctx, p.apiURL+"/embeddings", header, reqBody, &result); err != nil {
+		return nil, err
+	}
+
+	embeddings := make([][]float32, len(texts))
+	for _, d := range result.Data {
+		if d.Index >= 0 && d.Index < len(embeddings) {
+			embeddings[d.Index] = d.Embedding
+		}
+	}
+	return embeddings, nil
+}
@@ -177,11 +177,19 @@ func isAdminMethod(method string) bool {
 		protocol.MethodTeamsTaskGet,
 		protocol.MethodTeamsTaskComments,
 		protocol.MethodTeamsTaskEvents,
+		protocol.MethodTeamsTaskTemplatesList,
+		protocol.MethodTeamsTaskTemplatesGet,
+		protocol.MethodTeamsWorkflowsList,
+		protocol.MethodTeamsWorkflowsGet,
+		protocol.MethodTeamsWorkflowRunsList,
+		protocol.MethodTeamsWorkflowRunsGet,
 		protocol.MethodAPIKeysList,
 		protocol.MethodAPIKeysCreate,
 		protocol.MethodAPIKeysRevoke,
 		protocol.MethodSkillsUpdate,
 		protocol.MethodWorkersRegister,
+		protocol.MethodToolApprovalsApprove,
+		protocol.MethodToolApprovalsDeny,
 	}
 	return slices.Contains(adminMethods, method)
 }
@@ -201,12 +209,22 @@ func isWriteMethod(method string) bool {
 		"device.pair.",
 		"approvals.",
 		"exec.approval.",
+		"tool.approval.",
 		protocol.MethodSend,
 		protocol.MethodTeamsTaskApprove,
 		protocol.MethodTeamsTaskReject,
 		protocol.MethodTeamsTaskComment,
 		protocol.MethodTeamsTaskCreate,
 		protocol.MethodTeamsTaskAssign,
+		protocol.MethodTeamsTaskTemplatesCreate,
+		protocol.MethodTeamsTaskTemplatesUpdate,
+		protocol.MethodTeamsTaskTemplatesDelete,
+		protocol.MethodTeamsTaskTemplatesRun,
+		protocol.MethodTeamsWorkflowsCreate,
+		protocol.MethodTeamsWorkflowsUpdate,
+		protocol.MethodTeamsWorkflowsDelete,
+		protocol.MethodTeamsWorkflowsRun,
+		protocol.MethodTeamsWorkflowRunsCancel,
 		protocol.MethodWorkersJobStarted,
 		protocol.MethodWorkersJobOutput,
 		protocol.MethodWorkersJobStatus,
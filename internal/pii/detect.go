@@ -0,0 +1,233 @@
+// Package pii detects personal data (emails, phone numbers, payment cards,
+// IBANs, national IDs, street addresses) in free text and replaces it with a
+// masked, hashed or reversible token form. Tokens are re-hydrated to the
+// original values only at delivery time, so providers, session history and
+// traces never see the raw data.
+package pii
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Detector finds one kind of personal data.
+type Detector struct {
+	Name string
+	re   *regexp.Regexp
+	// valid rejects regex hits that are plausible-looking but not real
+	// (checksum failures, numbers glued to surrounding identifiers).
+	valid func(s string, start, end int) bool
+}
+
+// Finding is a single detected value.
+type Finding struct {
+	Type  string
+	Start int
+	End   int
+	Value string
+}
+
+// Built-in detector names.
+const (
+	Email      = "email"
+	Phone      = "phone"
+	CreditCard = "credit_card"
+	IBAN       = "iban"
+	NationalID = "national_id"
+	Address    = "address"
+)
+
+var builtinDetectors = []*Detector{
+	{
+		Name: Email,
+		re:   regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+	},
+	{
+		Name: IBAN,
+		re:   regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`),
+		valid: func(s string, start, end int) bool {
+			return isolated(s, start, end) && ibanValid(s[start:end])
+		},
+	},
+	{
+		Name: CreditCard,
+		re:   regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		valid: func(s string, start, end int) bool {
+			return isolated(s, start, end) && luhnValid(s[start:end])
+		},
+	},
+	{
+		// US SSN (123-45-6789) — other national formats are added via custom patterns.
+		Name: NationalID,
+		re:   regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+		valid: func(s string, start, end int) bool {
+			v := s[start:end]
+			return isolated(s, start, end) && !strings.HasPrefix(v, "000") && !strings.HasPrefix(v, "666") && v[0] != '9'
+		},
+	},
+	{
+		Name: Phone,
+		re:   regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{1,4}\)[\s.-]?)?\d{2,4}(?:[\s.-]\d{2,4}){1,4}|\+\d{8,14}|\b0\d{8,10}\b`),
+		valid: func(s string, start, end int) bool {
+			n := countDigits(s[start:end])
+			return n >= 9 && n <= 15 && isolated(s, start, end)
+		},
+	},
+	{
+		// House number + street name + common street suffix ("221B Baker Street", "1600 Pennsylvania Ave NW").
+		Name: Address,
+		re: regexp.MustCompile(`\b\d{1,5}[A-Z]?\s+(?:[A-Z][A-Za-z0-9.'-]*\s+){1,4}` +
+			`(?i:street|st|avenue|ave|road|rd|boulevard|blvd|lane|ln|drive|dr|court|ct|place|pl|square|sq|way|terrace|parkway|pkwy)\b\.?` +
+			`(?:\s+(?:N|S|E|W|NE|NW|SE|SW)\b)?(?:,?\s+(?:(?i:apt|suite|unit)\.?\s*#?|#\s*)[A-Z0-9-]+)?`),
+	},
+}
+
+// DetectorNames returns the built-in detector names in evaluation order.
+func DetectorNames() []string {
+	names := make([]string, len(builtinDetectors))
+	for i, d := range builtinDetectors {
+		names[i] = d.Name
+	}
+	return names
+}
+
+// LookupDetector returns a built-in detector by name.
+func LookupDetector(name string) (*Detector, bool) {
+	for _, d := range builtinDetectors {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return nil, false
+}
+
+// NewPatternDetector creates a custom detector from a regular expression.
+// Names become part of tokens, so they are restricted to [a-z0-9_].
+func NewPatternDetector(name, pattern string) (*Detector, error) {
+	if !detectorNameRe.MatchString(name) {
+		return nil, fmt.Errorf("detector name %q must match [a-z][a-z0-9_]*", name)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("detector %s: %w", name, err)
+	}
+	return &Detector{Name: name, re: re}, nil
+}
+
+var detectorNameRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Find returns this detector's matches in s.
+func (d *Detector) Find(s string) []Finding {
+	var out []Finding
+	for _, loc := range d.re.FindAllStringIndex(s, -1) {
+		if d.valid != nil && !d.valid(s, loc[0], loc[1]) {
+			continue
+		}
+		out = append(out, Finding{Type: d.Name, Start: loc[0], End: loc[1], Value: s[loc[0]:loc[1]]})
+	}
+	return out
+}
+
+// Scan runs detectors over s and returns non-overlapping findings ordered by
+// position. On overlap the earlier detector in the list wins, so specific
+// detectors (IBAN, card) should precede generic ones (phone).
+func Scan(s string, detectors []*Detector) []Finding {
+	if s == "" || len(detectors) == 0 {
+		return nil
+	}
+	var all []Finding
+	for _, d := range detectors {
+		for _, f := range d.Find(s) {
+			if !overlapsAny(all, f) {
+				all = append(all, f)
+			}
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Start < all[j].Start })
+	return all
+}
+
+func overlapsAny(fs []Finding, f Finding) bool {
+	for _, o := range fs {
+		if f.Start < o.End && o.Start < f.End {
+			return true
+		}
+	}
+	return false
+}
+
+// isolated reports whether s[start:end] is not glued to an identifier
+// (UUIDs, hex IDs, file names), which the digit detectors would otherwise hit.
+func isolated(s string, start, end int) bool {
+	if start > 0 && isIdentChar(s[start-1]) {
+		return false
+	}
+	if end < len(s) && isIdentChar(s[end]) {
+		return false
+	}
+	return true
+}
+
+func isIdentChar(c byte) bool {
+	return c == '-' || c == '_' || c == '/' ||
+		c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func countDigits(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+// LuhnValid reports whether the digits in s pass the Luhn checksum
+// (13–19 digits, as used by payment cards).
+func LuhnValid(s string) bool { return luhnValid(s) }
+
+func luhnValid(s string) bool {
+	sum, double, n := 0, false, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
+
+// ibanValid checks the ISO 13616 mod-97 checksum.
+func ibanValid(s string) bool {
+	s = strings.ReplaceAll(s, " ", "")
+	if len(s) < 15 || len(s) > 34 {
+		return false
+	}
+	rearranged := s[4:] + s[:4]
+	var sb strings.Builder
+	for _, c := range rearranged {
+		switch {
+		case c >= '0' && c <= '9':
+			sb.WriteRune(c)
+		case c >= 'A' && c <= 'Z':
+			fmt.Fprintf(&sb, "%d", c-'A'+10)
+		default:
+			return false
+		}
+	}
+	n, ok := new(big.Int).SetString(sb.String(), 10)
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
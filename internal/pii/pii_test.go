@@ -0,0 +1,159 @@
+package pii
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestScan_Detectors(t *testing.T) {
+	cases := []struct {
+		name string
+		text string
+		want []string // detected types, in order
+	}{
+		{"email", "write to Jane.Doe+x@mail.example.co.uk today", []string{Email}},
+		{"phone intl", "call +1 (415) 555-0132 now", []string{Phone}},
+		{"phone local", "số 0912345678 nhé", []string{Phone}},
+		{"card luhn", "card 4111 1111 1111 1111 exp 12/29", []string{CreditCard}},
+		{"card bad luhn", "card 4111 1111 1111 1112", nil},
+		{"iban", "IBAN DE89 3704 0044 0532 0130 00 please", []string{IBAN}},
+		{"ssn", "SSN 123-45-6789", []string{NationalID}},
+		{"address", "ship to 221B Baker Street, London", []string{Address}},
+		{"date and order id", "on 2026-05-04 order 1234567890123", nil},
+		{"uuid", "id 0193a5b0-7000-7000-8000-000000000001", nil},
+		{"lowercase words", "I have 3 apples on the way", nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, f := range Scan(tc.text, builtinDetectors) {
+				got = append(got, f.Type)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Fatalf("Scan(%q) = %v, want %v", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRedactor_Modes(t *testing.T) {
+	text := "mail bob@example.com or call +44 20 7946 0958"
+	cases := []struct {
+		mode string
+		want string
+	}{
+		{ModeMask, "mail b***@example.com or call +** ** **** 0958"},
+		{ModeHash, "mail [EMAIL#"},
+		{ModeTokenize, "mail [EMAIL_"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.mode, func(t *testing.T) {
+			r, err := New(config.PIIConfig{Enabled: true, Mode: tc.mode, Secret: "s"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, findings := r.Redact(uuid.Nil, "", text)
+			if len(findings) != 2 || !strings.HasPrefix(got, tc.want) || strings.Contains(got, "bob@") {
+				t.Fatalf("Redact = %q (%d findings)", got, len(findings))
+			}
+		})
+	}
+}
+
+func TestRedactor_TokenizeRehydrate(t *testing.T) {
+	r, err := New(config.PIIConfig{Enabled: true, Secret: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tenant := uuid.New()
+	dest := Destination("telegram", "100")
+	redacted, _ := r.Redact(tenant, dest, "my number is +1 415-555-0132")
+	token := strings.TrimPrefix(redacted, "my number is ")
+	if !tokenRe.MatchString(token) {
+		t.Fatalf("unexpected token %q", token)
+	}
+
+	// Same value, different formatting → same token; previews agree with the LLM's view.
+	if again, _ := r.Redact(tenant, dest, "+14155550132"); again != token {
+		t.Fatalf("token not stable: %q vs %q", again, token)
+	}
+	if preview := r.RedactPreview("call +1 415-555-0132"); preview != "call "+token {
+		t.Fatalf("preview = %q", preview)
+	}
+
+	reply := "Sure, I'll text " + token + " tomorrow."
+	if got := r.Rehydrate(tenant, dest, reply); got != "Sure, I'll text +14155550132 tomorrow." {
+		t.Fatalf("Rehydrate = %q", got)
+	}
+	if got := r.Rehydrate(uuid.New(), dest, reply); got != reply {
+		t.Fatal("tokens must not re-hydrate for another tenant")
+	}
+	if got := r.Rehydrate(tenant, dest, "[PHONE_00000000]"); got != "[PHONE_00000000]" {
+		t.Fatal("unknown tokens must be left unchanged")
+	}
+
+	// The zero tenant is the master tenant.
+	r.Redact(uuid.Nil, dest, "a@b.io")
+	tok := r.RedactPreview("a@b.io")
+	if r.Rehydrate(store.MasterTenantID, dest, tok) != "a@b.io" {
+		t.Fatal("zero tenant should map to master tenant")
+	}
+}
+
+func TestRedactor_RehydrateOnlyForOriginatingChat(t *testing.T) {
+	r, err := New(config.PIIConfig{Enabled: true, Secret: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tenant := uuid.New()
+	redacted, _ := r.Redact(tenant, Destination("telegram", "alice"), "+1 415-555-0132")
+
+	// The same token reaching another chat (shared memory, team tasks, group
+	// history) must not reveal the original.
+	for _, dest := range []string{Destination("telegram", "bob"), Destination("discord", "alice"), ""} {
+		if got := r.Rehydrate(tenant, dest, redacted); got != redacted {
+			t.Fatalf("dest %q: Rehydrate = %q, want token unchanged", dest, got)
+		}
+	}
+
+	// Text redacted without a destination records nothing.
+	r.Redact(tenant, "", "bob@example.com")
+	if r.vault.Len() != 1 {
+		t.Fatalf("vault has %d entries, want 1", r.vault.Len())
+	}
+}
+
+func TestNew_Config(t *testing.T) {
+	if r, err := New(config.PIIConfig{}); r != nil || err != nil {
+		t.Fatal("disabled config should return nil redactor")
+	}
+	var nilRedactor *Redactor
+	if got, _ := nilRedactor.Redact(uuid.Nil, "", "a@b.io"); got != "a@b.io" || nilRedactor.Enabled() {
+		t.Fatal("nil redactor must be a no-op")
+	}
+	bad := []config.PIIConfig{
+		{Enabled: true, Mode: "shred"},
+		{Enabled: true, Detectors: []string{"dna"}},
+		{Enabled: true, Patterns: []config.PIIPattern{{Name: "Bad Name", Pattern: `\d+`}}},
+		{Enabled: true, Patterns: []config.PIIPattern{{Name: "x", Pattern: `(`}}},
+	}
+	for i, cfg := range bad {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("config %d: expected error", i)
+		}
+	}
+
+	r, err := New(config.PIIConfig{Enabled: true, Mode: ModeMask, Detectors: []string{Email},
+		Patterns: []config.PIIPattern{{Name: "vn_citizen_id", Pattern: `\b0\d{11}\b`}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := r.Redact(uuid.Nil, "", "CCCD 001099012345, +1 415-555-0132"); got != "CCCD ********2345, +1 415-555-0132" {
+		t.Fatalf("Redact = %q", got)
+	}
+}
@@ -0,0 +1,264 @@
+package pii
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+)
+
+// Redaction modes.
+const (
+	ModeTokenize = "tokenize" // [PHONE_1a2b3c4d], re-hydrated on delivery
+	ModeMask     = "mask"     // partial masking (a***@example.com, ********0132)
+	ModeHash     = "hash"     // [PHONE#1a2b3c4d5e6f], irreversible
+)
+
+const defaultTokenTTL = 24 * time.Hour
+
+// tokenRe matches tokens produced in tokenize mode.
+var tokenRe = regexp.MustCompile(`\[([A-Z][A-Z0-9_]*)_([0-9a-f]{8})\]`)
+
+// Redactor detects personal data and replaces it according to the configured
+// mode. A nil *Redactor is valid and leaves text unchanged.
+type Redactor struct {
+	mode        string
+	detectors   []*Detector
+	secret      []byte
+	vault       *Vault
+	toolResults bool
+	traces      bool
+}
+
+// New creates a redactor from config. Returns nil when redaction is disabled.
+func New(cfg config.PIIConfig) (*Redactor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	r := &Redactor{
+		mode:        cfg.Mode,
+		toolResults: cfg.ToolResults,
+		traces:      cfg.Traces == nil || *cfg.Traces,
+	}
+	switch r.mode {
+	case "":
+		r.mode = ModeTokenize
+	case ModeTokenize, ModeMask, ModeHash:
+	default:
+		return nil, fmt.Errorf("pii: unknown mode %q", cfg.Mode)
+	}
+
+	names := cfg.Detectors
+	if len(names) == 0 {
+		names = DetectorNames()
+	}
+	for _, name := range names {
+		d, ok := LookupDetector(name)
+		if !ok {
+			return nil, fmt.Errorf("pii: unknown detector %q", name)
+		}
+		r.detectors = append(r.detectors, d)
+	}
+	// Custom patterns run first: they are usually more specific than the built-ins.
+	custom := make([]*Detector, 0, len(cfg.Patterns))
+	for _, p := range cfg.Patterns {
+		d, err := NewPatternDetector(p.Name, p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("pii: %w", err)
+		}
+		custom = append(custom, d)
+	}
+	r.detectors = append(custom, r.detectors...)
+
+	if cfg.Secret != "" {
+		r.secret = []byte(cfg.Secret)
+	} else {
+		// Tokens and hashes stay stable for the life of the process only.
+		r.secret = make([]byte, 32)
+		if _, err := rand.Read(r.secret); err != nil {
+			return nil, fmt.Errorf("pii: generate secret: %w", err)
+		}
+		if r.mode != ModeMask {
+			slog.Info("pii: GOCLAW_PII_SECRET not set, using a per-process key")
+		}
+	}
+
+	ttl := defaultTokenTTL
+	if cfg.TokenTTLHours > 0 {
+		ttl = time.Duration(cfg.TokenTTLHours) * time.Hour
+	}
+	if r.mode == ModeTokenize {
+		r.vault = NewVault(ttl)
+	}
+	return r, nil
+}
+
+// Enabled reports whether redaction is active.
+func (r *Redactor) Enabled() bool { return r != nil }
+
+// Mode returns the redaction mode ("" when disabled).
+func (r *Redactor) Mode() string {
+	if r == nil {
+		return ""
+	}
+	return r.mode
+}
+
+// ToolResults reports whether tool results should be redacted before the LLM sees them.
+func (r *Redactor) ToolResults() bool { return r != nil && r.toolResults }
+
+// Traces reports whether trace previews should be redacted.
+func (r *Redactor) Traces() bool { return r != nil && r.traces }
+
+// Destination identifies the chat a conversation's replies are delivered to.
+// Tokens are only re-hydrated for the destination they were redacted for.
+func Destination(channel, chatID string) string {
+	if channel == "" || chatID == "" {
+		return ""
+	}
+	return channel + "\x00" + chatID
+}
+
+// Redact replaces personal data in s. In tokenize mode the originals are stored
+// in the vault under the tenant and dest (see Destination) so Rehydrate can
+// restore them when a reply is delivered to that same chat. Text without a
+// delivery destination is redacted but nothing is recorded.
+func (r *Redactor) Redact(tenantID uuid.UUID, dest, s string) (string, []Finding) {
+	if r == nil {
+		return s, nil
+	}
+	return r.replace(s, func(f Finding, token string) {
+		if r.vault != nil && dest != "" {
+			r.vault.Put(tenantID, dest, token, f.Value)
+		}
+	})
+}
+
+// RedactPreview replaces personal data without recording tokens. Tokens are
+// derived from the value alone, so a preview shows the same token the LLM saw.
+// Used for trace previews, which have no delivery path to re-hydrate.
+func (r *Redactor) RedactPreview(s string) string {
+	if r == nil {
+		return s
+	}
+	out, _ := r.replace(s, nil)
+	return out
+}
+
+// Rehydrate restores tokenize-mode tokens in s, bound for dest, to their
+// original values. Unknown or expired tokens, and tokens redacted for another
+// tenant or chat (e.g. carried over through shared memory or team tasks), are
+// left as-is.
+func (r *Redactor) Rehydrate(tenantID uuid.UUID, dest, s string) string {
+	if r == nil || r.vault == nil || dest == "" || !strings.Contains(s, "_") {
+		return s
+	}
+	return tokenRe.ReplaceAllStringFunc(s, func(tok string) string {
+		if v, ok := r.vault.Get(tenantID, dest, tok); ok {
+			return v
+		}
+		return tok
+	})
+}
+
+func (r *Redactor) replace(s string, onToken func(Finding, string)) (string, []Finding) {
+	findings := Scan(s, r.detectors)
+	if len(findings) == 0 {
+		return s, nil
+	}
+	var sb strings.Builder
+	sb.Grow(len(s))
+	last := 0
+	for _, f := range findings {
+		sb.WriteString(s[last:f.Start])
+		switch r.mode {
+		case ModeMask:
+			sb.WriteString(mask(f))
+		case ModeHash:
+			fmt.Fprintf(&sb, "[%s#%s]", strings.ToUpper(f.Type), r.digest(f)[:12])
+		default:
+			token := fmt.Sprintf("[%s_%s]", strings.ToUpper(f.Type), r.digest(f)[:8])
+			if onToken != nil {
+				onToken(f, token)
+			}
+			sb.WriteString(token)
+		}
+		last = f.End
+	}
+	sb.WriteString(s[last:])
+	return sb.String(), findings
+}
+
+// digest is a keyed hash of the normalized value, so differently formatted
+// copies of the same number map to the same token.
+func (r *Redactor) digest(f Finding) string {
+	mac := hmac.New(sha256.New, r.secret)
+	mac.Write([]byte(f.Type))
+	mac.Write([]byte{0})
+	mac.Write([]byte(normalize(f)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func normalize(f Finding) string {
+	switch f.Type {
+	case Email:
+		return strings.ToLower(f.Value)
+	case Phone, CreditCard, NationalID:
+		return digitsOnly(f.Value)
+	case IBAN:
+		return strings.ReplaceAll(f.Value, " ", "")
+	default:
+		return strings.TrimSpace(f.Value)
+	}
+}
+
+// mask keeps just enough of the value for a human to recognise it.
+func mask(f Finding) string {
+	switch f.Type {
+	case Email:
+		local, domain, _ := strings.Cut(f.Value, "@")
+		if local == "" {
+			return "***@" + domain
+		}
+		return local[:1] + "***@" + domain
+	case Address:
+		return "[ADDRESS]"
+	}
+	if countDigits(f.Value)*2 < len(f.Value) {
+		return "[" + strings.ToUpper(f.Type) + "]"
+	}
+	// Mask every alphanumeric except the last four, keeping separators.
+	keep := 4
+	b := []byte(f.Value)
+	for i := len(b) - 1; i >= 0; i-- {
+		c := b[i]
+		if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z') {
+			continue
+		}
+		if keep > 0 {
+			keep--
+			continue
+		}
+		b[i] = '*'
+	}
+	return string(b)
+}
+
+func digitsOnly(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
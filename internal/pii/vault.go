@@ -0,0 +1,86 @@
+package pii
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+const vaultSweepInterval = time.Minute
+
+// Vault maps tokenize-mode tokens back to original values, scoped per tenant
+// and delivery destination.
+// Entries live in memory and expire after the TTL (refreshed on use), so a
+// gateway restart drops them and old tokens are then delivered unchanged.
+type Vault struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	entries   map[vaultKey]vaultEntry
+	lastSweep time.Time
+}
+
+type vaultKey struct {
+	tenantID uuid.UUID
+	dest     string
+	token    string
+}
+
+type vaultEntry struct {
+	value   string
+	expires time.Time
+}
+
+// NewVault creates an empty vault with the given entry TTL.
+func NewVault(ttl time.Duration) *Vault {
+	return &Vault{ttl: ttl, entries: make(map[vaultKey]vaultEntry), lastSweep: time.Now()}
+}
+
+// Put records (or refreshes) the original value for a token.
+func (v *Vault) Put(tenantID uuid.UUID, dest, token, value string) {
+	now := time.Now()
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.entries[vaultKey{tenantKey(tenantID), dest, token}] = vaultEntry{value: value, expires: now.Add(v.ttl)}
+	if now.Sub(v.lastSweep) >= vaultSweepInterval {
+		for k, e := range v.entries {
+			if now.After(e.expires) {
+				delete(v.entries, k)
+			}
+		}
+		v.lastSweep = now
+	}
+}
+
+// Get returns the original value for a token and refreshes its TTL.
+func (v *Vault) Get(tenantID uuid.UUID, dest, token string) (string, bool) {
+	now := time.Now()
+	key := vaultKey{tenantKey(tenantID), dest, token}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	e, ok := v.entries[key]
+	if !ok || now.After(e.expires) {
+		return "", false
+	}
+	e.expires = now.Add(v.ttl)
+	v.entries[key] = e
+	return e.value, true
+}
+
+// Len returns the number of stored tokens (including expired, unswept ones).
+func (v *Vault) Len() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.entries)
+}
+
+// tenantKey maps the zero tenant (config-based channels, legacy contexts) to the master tenant.
+func tenantKey(id uuid.UUID) uuid.UUID {
+	if id == uuid.Nil {
+		return store.MasterTenantID
+	}
+	return id
+}
@@ -2,17 +2,71 @@ package providerresolve
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
 // ResolveConfiguredProvider resolves the provider an agent should actually use.
-// It applies ChatGPT OAuth routing from agent other_config when present.
+// It applies ChatGPT OAuth routing, the provider failover chain and the
+// response cache from agent other_config when present.
 func ResolveConfiguredProvider(registry *providers.Registry, agent *store.AgentData) (providers.Provider, error) {
 	if registry == nil || agent == nil {
 		return nil, fmt.Errorf("provider registry unavailable")
 	}
+	primary, err := resolvePrimaryProvider(registry, agent)
+	provider, err := wrapFailover(registry, agent, primary, err)
+	if err != nil {
+		return provider, err
+	}
+	return wrapResponseCache(registry, agent, provider), nil
+}
+
+// wrapResponseCache wraps provider in a CachingProvider when the agent enables
+// response_cache and the gateway has a response cache configured.
+// Entries are scoped per tenant and agent.
+func wrapResponseCache(registry *providers.Registry, agent *store.AgentData, provider providers.Provider) providers.Provider {
+	cfg := agent.ParseResponseCache()
+	rc := registry.ResponseCache()
+	if cfg == nil || rc == nil || provider == nil {
+		return provider
+	}
+	return providers.NewCachingProvider(
+		provider,
+		rc,
+		agent.TenantID.String()+"/"+agent.ID.String(),
+		time.Duration(cfg.TTLSeconds)*time.Second,
+		cfg.SimilarityThreshold,
+	)
+}
+
+// wrapFailover wraps the primary provider in a FailoverProvider when the agent
+// declares a provider_failover chain with at least one registered member.
+// A missing primary is tolerated as long as a fallback can serve.
+func wrapFailover(registry *providers.Registry, agent *store.AgentData, primary providers.Provider, primaryErr error) (providers.Provider, error) {
+	cfg := agent.ParseProviderFailover()
+	if cfg == nil {
+		return primary, primaryErr
+	}
+	members := make([]providers.FailoverMember, 0, len(cfg.Members))
+	for _, m := range cfg.Members {
+		members = append(members, providers.FailoverMember{ProviderName: m.Provider, Model: m.Model})
+	}
+	failover := providers.NewFailoverProvider(
+		agent.TenantID,
+		registry,
+		primary,
+		members,
+		time.Duration(cfg.CooldownSeconds)*time.Second,
+	)
+	if !failover.HasFallbacks() {
+		return primary, primaryErr
+	}
+	return failover, nil
+}
+
+func resolvePrimaryProvider(registry *providers.Registry, agent *store.AgentData) (providers.Provider, error) {
 
 	baseProvider, baseErr := registry.GetForTenant(agent.TenantID, agent.Provider)
 	if baseErr == nil {
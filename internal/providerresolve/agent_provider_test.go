@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/nextlevelbuilder/goclaw/internal/cache"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
@@ -233,3 +234,40 @@ func TestResolveConfiguredProviderReturnsRouterEvenWhenPrimaryNeedsFailover(t *t
 		t.Fatalf("ResolveConfiguredProvider() returned %T, want *providers.ChatGPTOAuthRouter", resolved)
 	}
 }
+
+func TestResolveConfiguredProviderWrapsResponseCacheWhenEnabled(t *testing.T) {
+	tenantID := uuid.New()
+	registry := providers.NewRegistry(nil)
+	base := &stubProvider{name: "anthropic", model: "claude-sonnet-4"}
+	registry.RegisterForTenant(tenantID, base)
+
+	agent := &store.AgentData{
+		TenantID:    tenantID,
+		Provider:    "anthropic",
+		OtherConfig: json.RawMessage(`{"response_cache": {"enabled": true, "ttl_seconds": 600}}`),
+	}
+
+	// No shared cache configured: the agent flag alone changes nothing.
+	resolved, err := ResolveConfiguredProvider(registry, agent)
+	if err != nil {
+		t.Fatalf("ResolveConfiguredProvider() error = %v", err)
+	}
+	if resolved != base {
+		t.Fatalf("ResolveConfiguredProvider() returned %T without a response cache, want base", resolved)
+	}
+
+	registry.SetResponseCache(providers.NewResponseCache(cache.NewInMemoryCache[providers.CachedResponse](), nil))
+	resolved, err = ResolveConfiguredProvider(registry, agent)
+	if err != nil {
+		t.Fatalf("ResolveConfiguredProvider() error = %v", err)
+	}
+	if _, ok := resolved.(*providers.CachingProvider); !ok {
+		t.Fatalf("ResolveConfiguredProvider() returned %T, want *providers.CachingProvider", resolved)
+	}
+
+	agent.OtherConfig = json.RawMessage(`{"response_cache": {"enabled": false}}`)
+	resolved, _ = ResolveConfiguredProvider(registry, agent)
+	if resolved != base {
+		t.Fatalf("ResolveConfiguredProvider() returned %T for disabled cache, want base", resolved)
+	}
+}
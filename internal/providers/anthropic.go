@@ -95,7 +95,11 @@ func (p *AnthropicProvider) Chat(ctx context.Context, req ChatRequest) (*ChatRes
 			return nil, fmt.Errorf("anthropic: decode response: %w", err)
 		}
 
-		return p.parseResponse(&resp), nil
+		result := p.parseResponse(&resp)
+		if req.ResponseFormat.IsJSON() {
+			takeStructuredOutput(result, "")
+		}
+		return result, nil
 	})
 }
 
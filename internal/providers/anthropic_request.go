@@ -209,6 +209,10 @@ func (p *AnthropicProvider) buildRequestBody(model string, req ChatRequest, stre
 		}
 	}
 
+	if req.ResponseFormat.IsJSON() {
+		applyAnthropicResponseFormat(body, req)
+	}
+
 	return body
 }
 
@@ -37,6 +37,7 @@ func (p *AnthropicProvider) ChatStream(ctx context.Context, req ChatRequest, onC
 	scanner := bufio.NewScanner(respBody)
 	scanner.Buffer(make([]byte, 0, SSEScanBufInit), SSEScanBufMax)
 	var currentEvent string
+	outputTool := req.ResponseFormat.IsJSON()
 
 	for scanner.Scan() {
 		if ctx.Err() != nil {
@@ -104,6 +105,10 @@ func (p *AnthropicProvider) ChatStream(ctx context.Context, req ChatRequest, onC
 					if len(result.ToolCalls) > 0 {
 						idx := len(result.ToolCalls) - 1
 						toolCallJSON[idx] += ev.Delta.PartialJSON
+						// Structured output arrives as tool input; stream it as content.
+						if outputTool && result.ToolCalls[idx].Name == anthropicOutputTool && onChunk != nil {
+							onChunk(StreamChunk{Content: ev.Delta.PartialJSON})
+						}
 					}
 				case "signature_delta":
 					thinkingSignature += ev.Delta.Signature
@@ -185,6 +190,17 @@ func (p *AnthropicProvider) ChatStream(ctx context.Context, req ChatRequest, onC
 
 	result.ThinkingSignature = thinkingSignature
 
+	if outputTool {
+		rawInput := ""
+		for i, tc := range result.ToolCalls {
+			if tc.Name == anthropicOutputTool {
+				rawInput = toolCallJSON[i]
+				break
+			}
+		}
+		takeStructuredOutput(result, rawInput)
+	}
+
 	if onChunk != nil {
 		onChunk(StreamChunk{Done: true})
 	}
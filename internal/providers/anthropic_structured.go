@@ -0,0 +1,80 @@
+package providers
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// anthropicOutputTool is the synthetic tool that carries structured output.
+// Anthropic has no response_format; instead the response schema is offered as
+// a tool's input_schema and the tool call's input becomes the answer.
+const anthropicOutputTool = "structured_output"
+
+const anthropicOutputToolHint = "When you have the final answer, call the " + anthropicOutputTool + " tool with it instead of replying in text."
+
+// applyAnthropicResponseFormat adds the output tool to an Anthropic request
+// body. With no other tools and no extended thinking the tool is forced via
+// tool_choice; otherwise forcing would block real tool use (or is rejected
+// alongside thinking), so the model is told to finish with it instead.
+func applyAnthropicResponseFormat(body map[string]any, req ChatRequest) {
+	rf := req.ResponseFormat
+	schema := rf.schema()
+	if schema == nil {
+		schema = map[string]any{"type": "object"} // json_object: any object
+	}
+	desc := "Return the final answer as structured JSON. The tool input is the answer itself."
+	if rf.JSONSchema != nil && rf.JSONSchema.Description != "" {
+		desc += " " + rf.JSONSchema.Description
+	}
+	tools, _ := body["tools"].([]map[string]any)
+	body["tools"] = append(tools, map[string]any{
+		"name":         anthropicOutputTool,
+		"description":  desc,
+		"input_schema": CleanSchemaForProvider("anthropic", schema),
+	})
+
+	if _, thinking := body["thinking"]; len(req.Tools) == 0 && !thinking {
+		body["tool_choice"] = map[string]any{"type": "tool", "name": anthropicOutputTool}
+		return
+	}
+	system, _ := body["system"].([]map[string]any)
+	body["system"] = append(system, map[string]any{"type": "text", "text": anthropicOutputToolHint})
+}
+
+// takeStructuredOutput moves the output tool's input into resp.Content and
+// removes the call, so callers see a plain JSON answer. rawInput, when set,
+// is the streamed input JSON (keeps the key order the client already saw).
+func takeStructuredOutput(resp *ChatResponse, rawInput string) {
+	idx := slices.IndexFunc(resp.ToolCalls, func(tc ToolCall) bool { return tc.Name == anthropicOutputTool })
+	if idx < 0 {
+		return
+	}
+	call := resp.ToolCalls[idx]
+	if s := strings.TrimSpace(rawInput); s != "" {
+		resp.Content = s
+	} else if data, err := json.Marshal(call.Arguments); err == nil {
+		resp.Content = string(data)
+	}
+	resp.ToolCalls = slices.Delete(resp.ToolCalls, idx, idx+1)
+
+	if len(resp.ToolCalls) == 0 {
+		resp.RawAssistantContent = nil
+		if resp.FinishReason == "tool_calls" {
+			resp.FinishReason = "stop"
+		}
+		return
+	}
+	// Other tool calls remain: drop the output tool_use block from the raw
+	// passback so Anthropic doesn't expect a tool_result for it.
+	var blocks []map[string]any
+	if json.Unmarshal(resp.RawAssistantContent, &blocks) != nil {
+		return
+	}
+	blocks = slices.DeleteFunc(blocks, func(b map[string]any) bool {
+		return b["type"] == "tool_use" && b["id"] == call.ID
+	})
+	if data, err := json.Marshal(blocks); err == nil {
+		resp.RawAssistantContent = data
+	}
+}
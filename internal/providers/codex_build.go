@@ -125,6 +125,24 @@ func (p *CodexProvider) buildRequestBody(req ChatRequest, stream bool) map[strin
 		body["reasoning"] = map[string]any{"effort": level}
 	}
 
+	// Structured output: Responses API takes the schema under text.format.
+	if rf := req.ResponseFormat; rf.IsJSON() {
+		format := map[string]any{"type": ResponseFormatJSONObject}
+		if rf.Type == ResponseFormatJSONSchema {
+			format = map[string]any{
+				"type":   ResponseFormatJSONSchema,
+				"name":   rf.schemaName(),
+				"schema": NormalizeSchema("codex", rf.schema()),
+			}
+			if rf.JSONSchema.Strict != nil {
+				format["strict"] = *rf.JSONSchema.Strict
+			}
+		} else if !mentionsJSON(req.Messages) {
+			body["instructions"] = strings.TrimSpace(instructions + "\n\n" + rf.Instruction())
+		}
+		body["text"] = map[string]any{"format": format}
+	}
+
 	return body
 }
 
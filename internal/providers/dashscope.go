@@ -64,6 +64,14 @@ func (p *DashScopeProvider) applyThinkingGuard(req ChatRequest) ChatRequest {
 	if !ok || level == "" || level == "off" {
 		return req
 	}
+	// Qwen thinking mode does not support structured output.
+	if req.ResponseFormat.IsJSON() {
+		slog.Debug("dashscope: response_format set, skipping enable_thinking", "model", p.resolveModel(req.Model))
+		opts := maps.Clone(req.Options)
+		delete(opts, OptThinkingLevel)
+		req.Options = opts
+		return req
+	}
 
 	if p.ModelSupportsThinking(req.Model) {
 		// Clone Options to avoid mutating caller's map
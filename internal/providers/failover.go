@@ -0,0 +1,252 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultFailoverCooldown is how long a failover member that returned a
+// retryable error is skipped before it is tried again.
+const DefaultFailoverCooldown = 60 * time.Second
+
+// FailoverMember is one entry of a provider failover chain.
+// Model overrides the request model when this member serves the call; empty
+// means "keep the request model" for the primary and "use the member's default
+// model" for fallbacks (the agent's model name rarely exists on another backend).
+type FailoverMember struct {
+	ProviderName string
+	Model        string
+}
+
+// FailoverProvider wraps an ordered chain of providers and moves to the next
+// member on retryable errors (429, 5xx, timeouts, connection failures).
+// Members that fail are marked unhealthy in the shared Registry for a cooldown
+// so that per-request instances skip them without re-probing.
+type FailoverProvider struct {
+	tenantID uuid.UUID
+	registry *Registry
+	primary  Provider
+	members  []FailoverMember
+	cooldown time.Duration
+}
+
+// NewFailoverProvider creates a composite provider. primary is the agent's
+// already-resolved provider (which may itself be a ChatGPTOAuthRouter); members
+// are the fallbacks tried in order after it.
+func NewFailoverProvider(
+	tenantID uuid.UUID,
+	registry *Registry,
+	primary Provider,
+	members []FailoverMember,
+	cooldown time.Duration,
+) *FailoverProvider {
+	if cooldown <= 0 {
+		cooldown = DefaultFailoverCooldown
+	}
+	return &FailoverProvider{
+		tenantID: tenantID,
+		registry: registry,
+		primary:  primary,
+		members:  members,
+		cooldown: cooldown,
+	}
+}
+
+type failoverCandidate struct {
+	provider Provider
+	model    string // "" = keep request model
+	fallback bool
+}
+
+// Name returns the primary provider name so pricing, reasoning capability and
+// provider-store lookups keep resolving against the configured provider.
+// The member that actually served a call is recorded via FailoverObservation.
+func (p *FailoverProvider) Name() string {
+	if p.primary != nil {
+		return p.primary.Name()
+	}
+	if len(p.members) > 0 {
+		return p.members[0].ProviderName
+	}
+	return ""
+}
+
+func (p *FailoverProvider) DefaultModel() string {
+	if p.primary != nil {
+		return p.primary.DefaultModel()
+	}
+	return ""
+}
+
+// SupportsThinking forwards the capability of the member that would serve a
+// call now, so reasoning resolution follows the chain when the primary is
+// cooling down or blocked.
+func (p *FailoverProvider) SupportsThinking() bool {
+	ordered := p.orderedCandidates(context.Background())
+	if len(ordered) == 0 {
+		return false
+	}
+	if tc, ok := ordered[0].provider.(ThinkingCapable); ok {
+		return tc.SupportsThinking()
+	}
+	return false
+}
+
+// RouteEligibility forwards the eligibility of the member that would serve a
+// call now, so routers skip the chain only when every member is blocked.
+func (p *FailoverProvider) RouteEligibility(ctx context.Context) RouteEligibility {
+	ordered := p.orderedCandidates(ctx)
+	if len(ordered) == 0 {
+		return RouteEligibility{Class: RouteEligibilityBlocked, Reason: "no failover member is eligible"}
+	}
+	if aware, ok := ordered[0].provider.(RouteEligibilityAware); ok {
+		return aware.RouteEligibility(ctx)
+	}
+	return RouteEligibility{Class: RouteEligibilityHealthy}
+}
+
+// HasFallbacks reports whether at least one fallback member is registered.
+func (p *FailoverProvider) HasFallbacks() bool {
+	for _, m := range p.members {
+		if _, err := p.lookup(m.ProviderName); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *FailoverProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	return p.call(ctx, req, func(provider Provider, r ChatRequest) (*ChatResponse, error) {
+		return provider.Chat(ctx, r)
+	})
+}
+
+// ChatStream streams through the serving member. Chunks already emitted by a
+// member that later fails are not retracted; callers see the fallback's output
+// appended, which matches how the agent loop already treats retried streams.
+func (p *FailoverProvider) ChatStream(ctx context.Context, req ChatRequest, onChunk func(StreamChunk)) (*ChatResponse, error) {
+	return p.call(ctx, req, func(provider Provider, r ChatRequest) (*ChatResponse, error) {
+		return provider.ChatStream(ctx, r, onChunk)
+	})
+}
+
+func (p *FailoverProvider) call(ctx context.Context, req ChatRequest, fn func(Provider, ChatRequest) (*ChatResponse, error)) (*ChatResponse, error) {
+	ordered := p.orderedCandidates(ctx)
+	if len(ordered) == 0 {
+		return nil, fmt.Errorf("no failover providers available for %s", p.Name())
+	}
+	observation := FailoverObservationFromContext(ctx)
+	observation.SetChain(p.chainNames())
+
+	var lastErr error
+	for i, candidate := range ordered {
+		name := candidate.provider.Name()
+		observation.RecordAttempt(name)
+
+		memberReq := req
+		if candidate.model != "" {
+			memberReq.Model = candidate.model
+		} else if candidate.fallback {
+			memberReq.Model = candidate.provider.DefaultModel()
+		}
+
+		resp, err := fn(candidate.provider, memberReq)
+		if err == nil {
+			observation.RecordSuccess(name, memberReq.Model)
+			return resp, nil
+		}
+		lastErr = err
+		if ctx.Err() != nil || !IsRetryableError(err) {
+			return nil, err
+		}
+		p.markUnhealthy(name)
+		if i == len(ordered)-1 {
+			break
+		}
+		slog.Warn("provider failover",
+			"from", name,
+			"to", ordered[i+1].provider.Name(),
+			"error", err,
+		)
+	}
+	return nil, lastErr
+}
+
+// orderedCandidates returns healthy members first (chain order), followed by
+// members still in cooldown as a last resort. Members reporting a blocked
+// RouteEligibility are dropped entirely.
+func (p *FailoverProvider) orderedCandidates(ctx context.Context) []failoverCandidate {
+	all := make([]failoverCandidate, 0, 1+len(p.members))
+	seen := make(map[string]bool, 1+len(p.members))
+	if p.primary != nil {
+		all = append(all, failoverCandidate{provider: p.primary})
+		seen[p.primary.Name()] = true
+	}
+	for _, m := range p.members {
+		if m.ProviderName == "" || seen[m.ProviderName] {
+			continue
+		}
+		provider, err := p.lookup(m.ProviderName)
+		if err != nil {
+			continue
+		}
+		seen[m.ProviderName] = true
+		all = append(all, failoverCandidate{provider: provider, model: m.Model, fallback: true})
+	}
+
+	healthy := make([]failoverCandidate, 0, len(all))
+	cooling := make([]failoverCandidate, 0, len(all))
+	for _, c := range all {
+		if aware, ok := c.provider.(RouteEligibilityAware); ok {
+			if aware.RouteEligibility(ctx).Class == RouteEligibilityBlocked {
+				continue
+			}
+		}
+		if p.isUnhealthy(c.provider.Name()) {
+			cooling = append(cooling, c)
+			continue
+		}
+		healthy = append(healthy, c)
+	}
+	return append(healthy, cooling...)
+}
+
+func (p *FailoverProvider) chainNames() []string {
+	names := make([]string, 0, 1+len(p.members))
+	if p.primary != nil {
+		names = append(names, p.primary.Name())
+	}
+	for _, m := range p.members {
+		names = append(names, m.ProviderName)
+	}
+	return names
+}
+
+func (p *FailoverProvider) lookup(name string) (Provider, error) {
+	if p.registry == nil {
+		return nil, fmt.Errorf("provider registry unavailable")
+	}
+	return p.registry.GetForTenant(p.tenantID, name)
+}
+
+func (p *FailoverProvider) healthKey(name string) string {
+	return compoundKey(p.tenantID, name)
+}
+
+func (p *FailoverProvider) markUnhealthy(name string) {
+	if p.registry == nil {
+		return
+	}
+	p.registry.MarkUnhealthy(p.healthKey(name), p.cooldown)
+}
+
+func (p *FailoverProvider) isUnhealthy(name string) bool {
+	if p.registry == nil {
+		return false
+	}
+	return p.registry.IsUnhealthy(p.healthKey(name))
+}
@@ -0,0 +1,119 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+)
+
+const FailoverMetadataKey = "provider_failover"
+
+type failoverObservationKey struct{}
+
+// FailoverEvidence records which failover chain member served an LLM call.
+// Persisted into the LLM span metadata under FailoverMetadataKey.
+type FailoverEvidence struct {
+	Chain              []string `json:"chain,omitempty"`
+	AttemptedProviders []string `json:"attempted_providers,omitempty"`
+	ServingProvider    string   `json:"serving_provider,omitempty"`
+	ServingModel       string   `json:"serving_model,omitempty"`
+	AttemptCount       int      `json:"attempt_count,omitempty"`
+}
+
+func (e FailoverEvidence) HasData() bool {
+	return e.ServingProvider != "" || e.AttemptCount > 0
+}
+
+// FailedOver reports whether a member other than the chain's primary served
+// the call. The primary is Chain[0], not the first attempt: while it is in
+// cooldown the chain starts at a fallback.
+func (e FailoverEvidence) FailedOver() bool {
+	if e.ServingProvider == "" {
+		return false
+	}
+	primary := ""
+	if len(e.Chain) > 0 {
+		primary = e.Chain[0]
+	} else if len(e.AttemptedProviders) > 0 {
+		primary = e.AttemptedProviders[0]
+	}
+	return primary != "" && primary != e.ServingProvider
+}
+
+type FailoverObservation struct {
+	mu       sync.Mutex
+	evidence FailoverEvidence
+}
+
+func NewFailoverObservation() *FailoverObservation {
+	return &FailoverObservation{}
+}
+
+func WithFailoverObservation(ctx context.Context, observation *FailoverObservation) context.Context {
+	return context.WithValue(ctx, failoverObservationKey{}, observation)
+}
+
+func FailoverObservationFromContext(ctx context.Context) *FailoverObservation {
+	observation, _ := ctx.Value(failoverObservationKey{}).(*FailoverObservation)
+	return observation
+}
+
+func (o *FailoverObservation) SetChain(chain []string) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.evidence.Chain = append([]string(nil), chain...)
+}
+
+func (o *FailoverObservation) RecordAttempt(providerName string) {
+	if o == nil || providerName == "" {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.evidence.AttemptCount++
+	if !slices.Contains(o.evidence.AttemptedProviders, providerName) {
+		o.evidence.AttemptedProviders = append(o.evidence.AttemptedProviders, providerName)
+	}
+}
+
+func (o *FailoverObservation) RecordSuccess(providerName, model string) {
+	if o == nil || providerName == "" {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.evidence.ServingProvider = providerName
+	o.evidence.ServingModel = model
+}
+
+func (o *FailoverObservation) Snapshot() FailoverEvidence {
+	if o == nil {
+		return FailoverEvidence{}
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	evidence := o.evidence
+	evidence.Chain = append([]string(nil), o.evidence.Chain...)
+	evidence.AttemptedProviders = append([]string(nil), o.evidence.AttemptedProviders...)
+	return evidence
+}
+
+func MergeFailoverMetadata(existing json.RawMessage, evidence FailoverEvidence) json.RawMessage {
+	if !evidence.HasData() {
+		return existing
+	}
+	payload := map[string]any{}
+	if len(existing) > 0 {
+		_ = json.Unmarshal(existing, &payload)
+	}
+	payload[FailoverMetadataKey] = evidence
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return existing
+	}
+	return json.RawMessage(data)
+}
@@ -0,0 +1,174 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type failoverStubProvider struct {
+	name   string
+	model  string
+	err    error
+	calls  int
+	models []string
+}
+
+func (p *failoverStubProvider) Chat(_ context.Context, req ChatRequest) (*ChatResponse, error) {
+	p.calls++
+	p.models = append(p.models, req.Model)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &ChatResponse{Content: p.name, FinishReason: "stop"}, nil
+}
+
+func (p *failoverStubProvider) ChatStream(ctx context.Context, req ChatRequest, _ func(StreamChunk)) (*ChatResponse, error) {
+	return p.Chat(ctx, req)
+}
+
+func (p *failoverStubProvider) DefaultModel() string { return p.model }
+func (p *failoverStubProvider) Name() string         { return p.name }
+
+func TestFailoverProviderFallsBackOnRetryableError(t *testing.T) {
+	tenantID := uuid.New()
+	registry := NewRegistry(nil)
+	primary := &failoverStubProvider{name: "anthropic", model: "claude-sonnet-4", err: &HTTPError{Status: 503, Body: "overloaded"}}
+	backup := &failoverStubProvider{name: "openrouter", model: "router-default"}
+	local := &failoverStubProvider{name: "ollama", model: "llama3"}
+	registry.RegisterForTenant(tenantID, primary)
+	registry.RegisterForTenant(tenantID, backup)
+	registry.RegisterForTenant(tenantID, local)
+
+	failover := NewFailoverProvider(tenantID, registry, primary, []FailoverMember{
+		{ProviderName: "openrouter", Model: "anthropic/claude-sonnet-4"},
+		{ProviderName: "ollama"},
+	}, time.Minute)
+
+	observation := NewFailoverObservation()
+	ctx := WithFailoverObservation(context.Background(), observation)
+	resp, err := failover.Chat(ctx, ChatRequest{Model: "claude-sonnet-4"})
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if resp.Content != "openrouter" {
+		t.Fatalf("served by %q, want openrouter", resp.Content)
+	}
+	if backup.models[0] != "anthropic/claude-sonnet-4" {
+		t.Fatalf("backup model = %q, want member override", backup.models[0])
+	}
+	if local.calls != 0 {
+		t.Fatalf("ollama calls = %d, want 0", local.calls)
+	}
+
+	evidence := observation.Snapshot()
+	if !evidence.FailedOver() || evidence.ServingProvider != "openrouter" {
+		t.Fatalf("evidence = %+v, want failover to openrouter", evidence)
+	}
+	if evidence.AttemptCount != 2 {
+		t.Fatalf("AttemptCount = %d, want 2", evidence.AttemptCount)
+	}
+}
+
+func TestFailoverProviderSkipsUnhealthyMemberDuringCooldown(t *testing.T) {
+	tenantID := uuid.New()
+	registry := NewRegistry(nil)
+	primary := &failoverStubProvider{name: "anthropic", err: &HTTPError{Status: 429, Body: "rate limited"}}
+	backup := &failoverStubProvider{name: "openrouter", model: "router-default"}
+	registry.RegisterForTenant(tenantID, primary)
+	registry.RegisterForTenant(tenantID, backup)
+
+	members := []FailoverMember{{ProviderName: "openrouter"}}
+	if _, err := NewFailoverProvider(tenantID, registry, primary, members, time.Minute).Chat(context.Background(), ChatRequest{}); err != nil {
+		t.Fatalf("first Chat() error = %v", err)
+	}
+	// A fresh per-request instance must see the shared cooldown and go straight to the backup.
+	observation := NewFailoverObservation()
+	ctx := WithFailoverObservation(context.Background(), observation)
+	if _, err := NewFailoverProvider(tenantID, registry, primary, members, time.Minute).Chat(ctx, ChatRequest{}); err != nil {
+		t.Fatalf("second Chat() error = %v", err)
+	}
+	// The primary was skipped, not attempted, yet the call was still served by a fallback.
+	evidence := observation.Snapshot()
+	if len(evidence.AttemptedProviders) != 1 || evidence.AttemptedProviders[0] != "openrouter" {
+		t.Fatalf("attempted = %v, want [openrouter]", evidence.AttemptedProviders)
+	}
+	if !evidence.FailedOver() || evidence.ServingProvider != "openrouter" || evidence.ServingModel != "router-default" {
+		t.Fatalf("evidence = %+v, want failover to openrouter/router-default", evidence)
+	}
+	if primary.calls != 1 {
+		t.Fatalf("primary calls = %d, want 1 (cooldown should skip it)", primary.calls)
+	}
+	if backup.calls != 2 {
+		t.Fatalf("backup calls = %d, want 2", backup.calls)
+	}
+	if backup.models[0] != "router-default" {
+		t.Fatalf("backup model = %q, want member default model", backup.models[0])
+	}
+}
+
+func TestFailoverProviderDoesNotFailOverOnClientError(t *testing.T) {
+	tenantID := uuid.New()
+	registry := NewRegistry(nil)
+	primary := &failoverStubProvider{name: "anthropic", err: &HTTPError{Status: 400, Body: "bad request"}}
+	backup := &failoverStubProvider{name: "openrouter"}
+	registry.RegisterForTenant(tenantID, primary)
+	registry.RegisterForTenant(tenantID, backup)
+
+	failover := NewFailoverProvider(tenantID, registry, primary, []FailoverMember{{ProviderName: "openrouter"}}, 0)
+	_, err := failover.Chat(context.Background(), ChatRequest{})
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Status != 400 {
+		t.Fatalf("Chat() error = %v, want HTTP 400", err)
+	}
+	if backup.calls != 0 {
+		t.Fatalf("backup calls = %d, want 0", backup.calls)
+	}
+	if registry.IsUnhealthy(compoundKey(tenantID, "anthropic")) {
+		t.Fatal("client errors must not put the primary into cooldown")
+	}
+}
+
+type capableFailoverStub struct {
+	failoverStubProvider
+	thinking    bool
+	eligibility RouteEligibility
+}
+
+func (p *capableFailoverStub) SupportsThinking() bool { return p.thinking }
+func (p *capableFailoverStub) RouteEligibility(context.Context) RouteEligibility {
+	return p.eligibility
+}
+
+func TestFailoverProviderForwardsServingMemberCapabilities(t *testing.T) {
+	tenantID := uuid.New()
+	registry := NewRegistry(nil)
+	primary := &capableFailoverStub{
+		failoverStubProvider: failoverStubProvider{name: "codex"},
+		thinking:             false,
+		eligibility:          RouteEligibility{Class: RouteEligibilityBlocked, Reason: "quota"},
+	}
+	backup := &capableFailoverStub{
+		failoverStubProvider: failoverStubProvider{name: "anthropic"},
+		thinking:             true,
+		eligibility:          RouteEligibility{Class: RouteEligibilityHealthy},
+	}
+	registry.RegisterForTenant(tenantID, primary)
+	registry.RegisterForTenant(tenantID, backup)
+
+	failover := NewFailoverProvider(tenantID, registry, primary, []FailoverMember{{ProviderName: "anthropic"}}, time.Minute)
+	if !failover.SupportsThinking() {
+		t.Fatal("SupportsThinking() = false, want the serving backup's capability")
+	}
+	if got := failover.RouteEligibility(context.Background()); got.Class != RouteEligibilityHealthy {
+		t.Fatalf("RouteEligibility() = %+v, want healthy while the backup is eligible", got)
+	}
+
+	backup.eligibility = RouteEligibility{Class: RouteEligibilityBlocked, Reason: "quota"}
+	if got := failover.RouteEligibility(context.Background()); got.Class != RouteEligibilityBlocked {
+		t.Fatalf("RouteEligibility() = %+v, want blocked when every member is blocked", got)
+	}
+}
@@ -349,16 +349,21 @@ func (p *OpenAIProvider) buildRequestBody(model string, req ChatRequest, stream
 	inputMessages := req.Messages
 
 	// Compute provider capability once: does this endpoint support Google's thought_signature?
-	// We check providerType, name, apiBase, and the model string (robust detection for proxies/OpenRouter).
-	supportsThoughtSignature := strings.Contains(strings.ToLower(p.providerType), "gemini") ||
-		strings.Contains(strings.ToLower(p.name), "gemini") ||
-		strings.Contains(strings.ToLower(p.apiBase), "generativelanguage") ||
-		strings.Contains(strings.ToLower(model), "gemini")
+	supportsThoughtSignature := p.isGeminiTarget(model)
 
 	if supportsThoughtSignature {
 		inputMessages = collapseToolCallsWithoutSig(inputMessages)
 	}
 
+	// Without native json_schema support the schema travels in the prompt; json_object
+	// additionally requires the word "JSON" somewhere in the messages.
+	if rf := req.ResponseFormat; rf.IsJSON() {
+		downgraded := rf.Type == ResponseFormatJSONSchema && !p.supportsJSONSchema()
+		if downgraded || (rf.Type == ResponseFormatJSONObject && !mentionsJSON(inputMessages)) {
+			inputMessages = withJSONInstruction(inputMessages, rf)
+		}
+	}
+
 	// Detect native OpenAI endpoint to enable developer role.
 	// GPT-4o+ models prioritize "developer" messages over "system" for instruction
 	// adherence. Non-OpenAI backends (proxies, Qwen, DeepSeek, etc.) reject "developer".
@@ -509,6 +514,10 @@ func (p *OpenAIProvider) buildRequestBody(model string, req ChatRequest, stream
 		}
 	}
 
+	if req.ResponseFormat.IsJSON() {
+		body["response_format"] = p.wireResponseFormat(model, req.ResponseFormat)
+	}
+
 	// DashScope-specific passthrough keys — never send to other OpenAI-compat hosts.
 	if p.dashScopePassthroughKeys() {
 		if v, ok := req.Options[OptEnableThinking]; ok {
@@ -522,6 +531,53 @@ func (p *OpenAIProvider) buildRequestBody(model string, req ChatRequest, stream
 	return body
 }
 
+// isGeminiTarget reports whether requests go to a Gemini model. Checks providerType,
+// name, apiBase, and the model string (robust detection for proxies/OpenRouter).
+func (p *OpenAIProvider) isGeminiTarget(model string) bool {
+	return strings.Contains(strings.ToLower(p.providerType), "gemini") ||
+		strings.Contains(strings.ToLower(p.name), "gemini") ||
+		strings.Contains(strings.ToLower(p.apiBase), "generativelanguage") ||
+		strings.Contains(strings.ToLower(model), "gemini")
+}
+
+// supportsJSONSchema is false for OpenAI-compatible hosts that only accept
+// response_format {"type":"json_object"} (DeepSeek, DashScope).
+func (p *OpenAIProvider) supportsJSONSchema() bool {
+	for _, s := range []string{p.name, p.providerType, p.apiBase} {
+		if strings.Contains(strings.ToLower(s), "deepseek") {
+			return false
+		}
+	}
+	return !p.dashScopePassthroughKeys()
+}
+
+// wireResponseFormat maps rf to the response_format request field. Gemini's
+// OpenAI-compatible endpoint turns json_schema into a native responseSchema,
+// so the schema gets the same Gemini cleanup as tool schemas.
+func (p *OpenAIProvider) wireResponseFormat(model string, rf *ResponseFormat) map[string]any {
+	if rf.Type == ResponseFormatJSONObject || !p.supportsJSONSchema() {
+		return map[string]any{"type": ResponseFormatJSONObject}
+	}
+	profileName := p.schemaProviderName()
+	if p.isGeminiTarget(model) {
+		profileName = "gemini"
+	}
+	profile := profileForProvider(profileName)
+	profile.StrictToolMode = rf.JSONSchema.Strict != nil && *rf.JSONSchema.Strict
+
+	schema := map[string]any{
+		"name":   rf.schemaName(),
+		"schema": normalizeWithProfile(profile, rf.schema()),
+	}
+	if rf.JSONSchema.Description != "" {
+		schema["description"] = rf.JSONSchema.Description
+	}
+	if rf.JSONSchema.Strict != nil {
+		schema["strict"] = *rf.JSONSchema.Strict
+	}
+	return map[string]any{"type": ResponseFormatJSONSchema, "json_schema": schema}
+}
+
 // modelFamily strips provider prefixes (for example "openai/o3-mini") so capability
 // gates apply to the actual model family rather than the transport-specific wrapper.
 func modelFamily(model string) string {
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -25,6 +26,15 @@ type Registry struct {
 	// so that ChatGPTOAuthRouter instances (created per-request) share rotation state.
 	roundRobinMu       sync.Mutex
 	roundRobinCounters map[string]int
+
+	// unhealthyUntil stores failover cooldown deadlines keyed by "tenantID/providerName"
+	// so that FailoverProvider instances (created per-request) share health state.
+	healthMu       sync.Mutex
+	unhealthyUntil map[string]time.Time
+
+	// responseCache backs CachingProvider instances (created per-request) for
+	// agents that enable response_cache. nil = caching unavailable.
+	responseCache *ResponseCache
 }
 
 // NewRegistry creates a provider registry.
@@ -34,6 +44,7 @@ func NewRegistry(tenantFromCtx func(context.Context) uuid.UUID) *Registry {
 		providers:          make(map[string]Provider),
 		tenantFromCtx:      tenantFromCtx,
 		roundRobinCounters: make(map[string]int),
+		unhealthyUntil:     make(map[string]time.Time),
 	}
 }
 
@@ -50,6 +61,41 @@ func (r *Registry) RoundRobinNext(key string, poolSize int, advance bool) int {
 	return idx
 }
 
+// MarkUnhealthy records that the provider behind key failed with a retryable error
+// and should be deprioritized by FailoverProvider until cooldown elapses.
+func (r *Registry) MarkUnhealthy(key string, cooldown time.Duration) {
+	r.healthMu.Lock()
+	defer r.healthMu.Unlock()
+	r.unhealthyUntil[key] = time.Now().Add(cooldown)
+}
+
+// IsUnhealthy reports whether the provider behind key is still in failover cooldown.
+// Expired entries are pruned on read.
+func (r *Registry) IsUnhealthy(key string) bool {
+	r.healthMu.Lock()
+	defer r.healthMu.Unlock()
+	until, ok := r.unhealthyUntil[key]
+	if !ok {
+		return false
+	}
+	if time.Now().After(until) {
+		delete(r.unhealthyUntil, key)
+		return false
+	}
+	return true
+}
+
+// SetResponseCache installs the shared response cache. Call during startup,
+// before agents are resolved.
+func (r *Registry) SetResponseCache(rc *ResponseCache) {
+	r.responseCache = rc
+}
+
+// ResponseCache returns the shared response cache, or nil if none is configured.
+func (r *Registry) ResponseCache() *ResponseCache {
+	return r.responseCache
+}
+
 // compoundKey returns "tenantID/name" for registry lookup.
 func compoundKey(tenantID uuid.UUID, name string) string {
 	return tenantID.String() + "/" + name
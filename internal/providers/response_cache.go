@@ -0,0 +1,331 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/cache"
+)
+
+const (
+	// DefaultResponseCacheTTL is how long a cached response is served when the
+	// agent does not set ttl_seconds.
+	DefaultResponseCacheTTL = 24 * time.Hour
+
+	// maxSemanticEntries caps the similarity index kept per cache scope.
+	maxSemanticEntries = 200
+)
+
+// TextEmbedder turns text into vectors for semantic cache lookups.
+// memory.EmbeddingProvider satisfies it.
+type TextEmbedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// CachedResponse is one stored LLM response.
+type CachedResponse struct {
+	Response  ChatResponse `json:"response"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// SemanticCacheEntry links the embedding of a last user message to the exact
+// cache key of the response it produced.
+type SemanticCacheEntry struct {
+	Key       string    `json:"key"`
+	Embedding []float32 `json:"embedding"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ResponseCache is the shared store behind CachingProvider instances.
+// Responses are keyed by a hash of the full request; the optional semantic
+// index groups entries by a hash of everything except the last user message
+// so that only that message is compared by embedding similarity.
+type ResponseCache struct {
+	entries  cache.Cache[CachedResponse]
+	index    cache.Cache[[]SemanticCacheEntry]
+	embedder TextEmbedder
+
+	indexMu sync.Mutex // serializes read-modify-write of index lists on this node
+}
+
+// NewResponseCache creates a response cache. index may be nil to disable
+// semantic lookups entirely.
+func NewResponseCache(entries cache.Cache[CachedResponse], index cache.Cache[[]SemanticCacheEntry]) *ResponseCache {
+	return &ResponseCache{entries: entries, index: index}
+}
+
+// SetEmbedder enables semantic lookups using embedder.
+func (c *ResponseCache) SetEmbedder(embedder TextEmbedder) {
+	c.embedder = embedder
+}
+
+func (c *ResponseCache) semanticEnabled() bool {
+	return c != nil && c.index != nil && c.embedder != nil
+}
+
+// CachingProvider serves repeated requests from a ResponseCache instead of
+// calling the wrapped provider. Only final text answers are cached: responses
+// with tool calls, truncated output or no content always go to the provider.
+type CachingProvider struct {
+	inner     Provider
+	cache     *ResponseCache
+	scope     string
+	ttl       time.Duration
+	threshold float64
+}
+
+// NewCachingProvider wraps inner. scope isolates entries (e.g. tenant/agent);
+// threshold is the minimum cosine similarity for a semantic hit, 0 = exact only.
+func NewCachingProvider(inner Provider, rc *ResponseCache, scope string, ttl time.Duration, threshold float64) *CachingProvider {
+	if ttl <= 0 {
+		ttl = DefaultResponseCacheTTL
+	}
+	return &CachingProvider{inner: inner, cache: rc, scope: scope, ttl: ttl, threshold: threshold}
+}
+
+func (p *CachingProvider) Name() string         { return p.inner.Name() }
+func (p *CachingProvider) DefaultModel() string { return p.inner.DefaultModel() }
+
+// SupportsThinking forwards the inner provider's capability so reasoning
+// resolution is unaffected by enabling the cache.
+func (p *CachingProvider) SupportsThinking() bool {
+	if tc, ok := p.inner.(ThinkingCapable); ok {
+		return tc.SupportsThinking()
+	}
+	return false
+}
+
+// RouteEligibility forwards the inner provider's eligibility so routers and
+// failover chains skip a blocked member even when it is wrapped.
+func (p *CachingProvider) RouteEligibility(ctx context.Context) RouteEligibility {
+	if aware, ok := p.inner.(RouteEligibilityAware); ok {
+		return aware.RouteEligibility(ctx)
+	}
+	return RouteEligibility{Class: RouteEligibilityHealthy}
+}
+
+func (p *CachingProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	lookup := p.lookup(ctx, req)
+	if lookup.resp != nil {
+		return lookup.resp, nil
+	}
+	resp, err := p.inner.Chat(ctx, req)
+	if err == nil {
+		p.store(ctx, lookup, resp)
+	}
+	return resp, err
+}
+
+func (p *CachingProvider) ChatStream(ctx context.Context, req ChatRequest, onChunk func(StreamChunk)) (*ChatResponse, error) {
+	lookup := p.lookup(ctx, req)
+	if lookup.resp != nil {
+		if onChunk != nil {
+			if lookup.resp.Thinking != "" {
+				onChunk(StreamChunk{Thinking: lookup.resp.Thinking})
+			}
+			if lookup.resp.Content != "" {
+				onChunk(StreamChunk{Content: lookup.resp.Content})
+			}
+			onChunk(StreamChunk{Done: true})
+		}
+		return lookup.resp, nil
+	}
+	resp, err := p.inner.ChatStream(ctx, req, onChunk)
+	if err == nil {
+		p.store(ctx, lookup, resp)
+	}
+	return resp, err
+}
+
+// cacheLookup carries the keys computed for a request from lookup to store.
+type cacheLookup struct {
+	key       string // "" = request is not cacheable
+	scopeKey  string // semantic index key; "" = no semantic lookup
+	embedding []float32
+	resp      *ChatResponse
+}
+
+func (p *CachingProvider) lookup(ctx context.Context, req ChatRequest) cacheLookup {
+	if p.cache == nil {
+		return cacheLookup{}
+	}
+	model := req.Model
+	if model == "" {
+		model = p.inner.DefaultModel()
+	}
+	key, scopeKey, lastUser, ok := responseCacheKeys(p.scope, model, req)
+	if !ok {
+		return cacheLookup{}
+	}
+	observation := ResponseCacheObservationFromContext(ctx)
+	l := cacheLookup{key: key}
+
+	if entry, found := p.cache.entries.Get(ctx, key); found {
+		observation.Record(ResponseCacheEvidence{Status: ResponseCacheHit, Key: shortKey(key)})
+		return cacheLookup{key: key, resp: cloneCachedResponse(entry.Response)}
+	}
+
+	if p.threshold > 0 && p.cache.semanticEnabled() && lastUser != "" {
+		l.scopeKey = scopeKey
+		vecs, err := p.cache.embedder.Embed(ctx, []string{lastUser})
+		if err != nil || len(vecs) == 0 {
+			slog.Debug("response cache: embedding failed", "error", err)
+		} else {
+			l.embedding = vecs[0]
+			if hitKey, sim := p.nearest(ctx, scopeKey, l.embedding); hitKey != "" {
+				if entry, found := p.cache.entries.Get(ctx, hitKey); found {
+					observation.Record(ResponseCacheEvidence{Status: ResponseCacheSemanticHit, Key: shortKey(hitKey), Similarity: sim})
+					return cacheLookup{key: key, resp: cloneCachedResponse(entry.Response)}
+				}
+			}
+		}
+	}
+
+	observation.Record(ResponseCacheEvidence{Status: ResponseCacheMiss, Key: shortKey(key)})
+	return l
+}
+
+// nearest returns the most similar live index entry at or above the threshold.
+func (p *CachingProvider) nearest(ctx context.Context, scopeKey string, vec []float32) (string, float64) {
+	entries, _ := p.cache.index.Get(ctx, scopeKey)
+	now := time.Now()
+	bestKey, best := "", 0.0
+	for _, e := range entries {
+		if now.After(e.ExpiresAt) {
+			continue
+		}
+		if sim := cosineSimilarity(vec, e.Embedding); sim >= p.threshold && sim > best {
+			bestKey, best = e.Key, sim
+		}
+	}
+	return bestKey, best
+}
+
+func (p *CachingProvider) store(ctx context.Context, l cacheLookup, resp *ChatResponse) {
+	if l.key == "" || !cacheableResponse(resp) {
+		return
+	}
+	p.cache.entries.Set(ctx, l.key, CachedResponse{Response: *resp, CreatedAt: time.Now().UTC()}, p.ttl)
+	if l.scopeKey == "" || len(l.embedding) == 0 {
+		return
+	}
+
+	p.cache.indexMu.Lock()
+	defer p.cache.indexMu.Unlock()
+	existing, _ := p.cache.index.Get(ctx, l.scopeKey)
+	now := time.Now()
+	entries := make([]SemanticCacheEntry, 0, len(existing)+1)
+	for _, e := range existing {
+		if now.Before(e.ExpiresAt) && e.Key != l.key {
+			entries = append(entries, e)
+		}
+	}
+	entries = append(entries, SemanticCacheEntry{Key: l.key, Embedding: l.embedding, ExpiresAt: now.Add(p.ttl)})
+	if len(entries) > maxSemanticEntries {
+		entries = entries[len(entries)-maxSemanticEntries:]
+	}
+	p.cache.index.Set(ctx, l.scopeKey, entries, p.ttl)
+}
+
+// cacheableResponse reports whether resp is a complete text answer.
+func cacheableResponse(resp *ChatResponse) bool {
+	return resp != nil &&
+		resp.Content != "" &&
+		len(resp.ToolCalls) == 0 &&
+		(resp.FinishReason == "" || resp.FinishReason == "stop")
+}
+
+func cloneCachedResponse(resp ChatResponse) *ChatResponse {
+	out := resp
+	if resp.Usage != nil {
+		usage := *resp.Usage
+		out.Usage = &usage
+	}
+	return &out
+}
+
+// cacheMessage is the subset of Message that identifies a request.
+// CreatedAt and other bookkeeping fields are left out so identical turns on
+// different days hash the same.
+type cacheMessage struct {
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string     `json:"tool_call_id,omitempty"`
+	IsError    bool       `json:"is_error,omitempty"`
+	MediaRefs  []MediaRef `json:"media_refs,omitempty"`
+}
+
+// responseCacheKeys returns the exact key of req, the semantic scope key
+// (the request without its final user message) and that message's text.
+// ok is false for requests that must not be cached (inline images).
+func responseCacheKeys(scope, model string, req ChatRequest) (key, scopeKey, lastUser string, ok bool) {
+	msgs := make([]cacheMessage, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		if len(m.Images) > 0 {
+			return "", "", "", false
+		}
+		msgs = append(msgs, cacheMessage{
+			Role:       m.Role,
+			Content:    m.Content,
+			ToolCalls:  m.ToolCalls,
+			ToolCallID: m.ToolCallID,
+			IsError:    m.IsError,
+			MediaRefs:  m.MediaRefs,
+		})
+	}
+
+	head := msgs
+	if n := len(msgs); n > 0 && msgs[n-1].Role == "user" {
+		head = msgs[:n-1]
+		lastUser = msgs[n-1].Content
+	}
+
+	key = hashCacheParts(scope, model, msgs, req.Tools, req.Options, req.ResponseFormat)
+	if lastUser != "" {
+		scopeKey = "sem:" + hashCacheParts(scope, model, head, req.Tools, req.Options, req.ResponseFormat)
+	}
+	return key, scopeKey, lastUser, true
+}
+
+func hashCacheParts(scope, model string, msgs []cacheMessage, tools []ToolDefinition, options map[string]any, format *ResponseFormat) string {
+	data, _ := json.Marshal(struct {
+		Scope    string           `json:"scope"`
+		Model    string           `json:"model"`
+		Messages []cacheMessage   `json:"messages"`
+		Tools    []ToolDefinition `json:"tools,omitempty"`
+		Options  map[string]any   `json:"options,omitempty"`
+		Format   *ResponseFormat  `json:"response_format,omitempty"`
+	}{scope, model, msgs, tools, options, format})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func shortKey(key string) string {
+	if len(key) > 12 {
+		return key[:12]
+	}
+	return key
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
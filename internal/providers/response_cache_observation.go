@@ -0,0 +1,87 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+const ResponseCacheMetadataKey = "response_cache"
+
+// Response cache lookup outcomes.
+const (
+	ResponseCacheHit         = "hit"
+	ResponseCacheSemanticHit = "semantic_hit"
+	ResponseCacheMiss        = "miss"
+)
+
+type responseCacheObservationKey struct{}
+
+// ResponseCacheEvidence records how the response cache handled an LLM call.
+// Persisted into the LLM span metadata under ResponseCacheMetadataKey.
+type ResponseCacheEvidence struct {
+	Status     string  `json:"status,omitempty"`
+	Key        string  `json:"key,omitempty"`
+	Similarity float64 `json:"similarity,omitempty"`
+}
+
+func (e ResponseCacheEvidence) HasData() bool {
+	return e.Status != ""
+}
+
+// Served reports whether the response came from the cache.
+func (e ResponseCacheEvidence) Served() bool {
+	return e.Status == ResponseCacheHit || e.Status == ResponseCacheSemanticHit
+}
+
+type ResponseCacheObservation struct {
+	mu       sync.Mutex
+	evidence ResponseCacheEvidence
+}
+
+func NewResponseCacheObservation() *ResponseCacheObservation {
+	return &ResponseCacheObservation{}
+}
+
+func WithResponseCacheObservation(ctx context.Context, observation *ResponseCacheObservation) context.Context {
+	return context.WithValue(ctx, responseCacheObservationKey{}, observation)
+}
+
+func ResponseCacheObservationFromContext(ctx context.Context) *ResponseCacheObservation {
+	observation, _ := ctx.Value(responseCacheObservationKey{}).(*ResponseCacheObservation)
+	return observation
+}
+
+func (o *ResponseCacheObservation) Record(evidence ResponseCacheEvidence) {
+	if o == nil {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.evidence = evidence
+}
+
+func (o *ResponseCacheObservation) Snapshot() ResponseCacheEvidence {
+	if o == nil {
+		return ResponseCacheEvidence{}
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.evidence
+}
+
+func MergeResponseCacheMetadata(existing json.RawMessage, evidence ResponseCacheEvidence) json.RawMessage {
+	if !evidence.HasData() {
+		return existing
+	}
+	payload := map[string]any{}
+	if len(existing) > 0 {
+		_ = json.Unmarshal(existing, &payload)
+	}
+	payload[ResponseCacheMetadataKey] = evidence
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return existing
+	}
+	return json.RawMessage(data)
+}
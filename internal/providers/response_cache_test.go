@@ -0,0 +1,234 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/cache"
+)
+
+// keywordEmbedder maps text onto fixed axes so similarity is predictable.
+type keywordEmbedder struct{}
+
+func (keywordEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		text = strings.ToLower(text)
+		vec := []float32{0, 0, 0}
+		if strings.Contains(text, "weather") {
+			vec[0] = 1
+		}
+		if strings.Contains(text, "today") {
+			vec[1] = 0.2
+		}
+		if strings.Contains(text, "stocks") {
+			vec[2] = 1
+		}
+		out[i] = vec
+	}
+	return out, nil
+}
+
+func newTestResponseCache() *ResponseCache {
+	return NewResponseCache(
+		cache.NewInMemoryCache[CachedResponse](),
+		cache.NewInMemoryCache[[]SemanticCacheEntry](),
+	)
+}
+
+func cacheRequest(user string) ChatRequest {
+	now := time.Now()
+	return ChatRequest{
+		Model: "claude-sonnet-4",
+		Messages: []Message{
+			{Role: "system", Content: "You are a daily briefing agent."},
+			{Role: "user", Content: user, CreatedAt: &now},
+		},
+	}
+}
+
+func TestCachingProviderExactHit(t *testing.T) {
+	inner := &failoverStubProvider{name: "anthropic", model: "claude-sonnet-4"}
+	p := NewCachingProvider(inner, newTestResponseCache(), "tenant/agent", time.Minute, 0)
+
+	first := NewResponseCacheObservation()
+	if _, err := p.Chat(WithResponseCacheObservation(context.Background(), first), cacheRequest("morning report")); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if got := first.Snapshot().Status; got != ResponseCacheMiss {
+		t.Fatalf("first status = %q, want miss", got)
+	}
+
+	second := NewResponseCacheObservation()
+	resp, err := p.Chat(WithResponseCacheObservation(context.Background(), second), cacheRequest("morning report"))
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if inner.calls != 1 {
+		t.Fatalf("inner calls = %d, want 1", inner.calls)
+	}
+	if resp.Content != "anthropic" {
+		t.Fatalf("cached content = %q", resp.Content)
+	}
+	if evidence := second.Snapshot(); !evidence.Served() || evidence.Status != ResponseCacheHit {
+		t.Fatalf("second evidence = %+v, want hit", evidence)
+	}
+}
+
+func TestCachingProviderKeyIncludesModelAndTools(t *testing.T) {
+	inner := &failoverStubProvider{name: "anthropic", model: "claude-sonnet-4"}
+	p := NewCachingProvider(inner, newTestResponseCache(), "tenant/agent", time.Minute, 0)
+
+	req := cacheRequest("morning report")
+	_, _ = p.Chat(context.Background(), req)
+
+	otherModel := cacheRequest("morning report")
+	otherModel.Model = "claude-opus-4"
+	_, _ = p.Chat(context.Background(), otherModel)
+
+	withTools := cacheRequest("morning report")
+	withTools.Tools = []ToolDefinition{{Type: "function", Function: ToolFunctionSchema{Name: "web_search"}}}
+	_, _ = p.Chat(context.Background(), withTools)
+
+	if inner.calls != 3 {
+		t.Fatalf("inner calls = %d, want 3 (model and tools change the key)", inner.calls)
+	}
+}
+
+func TestCachingProviderScopesAreIsolated(t *testing.T) {
+	rc := newTestResponseCache()
+	inner := &failoverStubProvider{name: "anthropic", model: "claude-sonnet-4"}
+	a := NewCachingProvider(inner, rc, "tenant/agent-a", time.Minute, 0)
+	b := NewCachingProvider(inner, rc, "tenant/agent-b", time.Minute, 0)
+
+	_, _ = a.Chat(context.Background(), cacheRequest("morning report"))
+	_, _ = b.Chat(context.Background(), cacheRequest("morning report"))
+	if inner.calls != 2 {
+		t.Fatalf("inner calls = %d, want 2", inner.calls)
+	}
+}
+
+func TestCachingProviderSkipsToolCallResponses(t *testing.T) {
+	inner := &toolCallStubProvider{}
+	p := NewCachingProvider(inner, newTestResponseCache(), "tenant/agent", time.Minute, 0)
+
+	_, _ = p.Chat(context.Background(), cacheRequest("search the web"))
+	_, _ = p.Chat(context.Background(), cacheRequest("search the web"))
+	if inner.calls != 2 {
+		t.Fatalf("inner calls = %d, want 2 (tool call responses are not cached)", inner.calls)
+	}
+}
+
+func TestCachingProviderSemanticHit(t *testing.T) {
+	rc := newTestResponseCache()
+	rc.SetEmbedder(keywordEmbedder{})
+	inner := &failoverStubProvider{name: "anthropic", model: "claude-sonnet-4"}
+	p := NewCachingProvider(inner, rc, "tenant/agent", time.Minute, 0.95)
+
+	_, _ = p.Chat(context.Background(), cacheRequest("What is the weather today?"))
+
+	observation := NewResponseCacheObservation()
+	resp, err := p.Chat(WithResponseCacheObservation(context.Background(), observation), cacheRequest("weather today please"))
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if inner.calls != 1 || resp.Content != "anthropic" {
+		t.Fatalf("inner calls = %d, content = %q; want semantic hit", inner.calls, resp.Content)
+	}
+	evidence := observation.Snapshot()
+	if evidence.Status != ResponseCacheSemanticHit || evidence.Similarity < 0.95 {
+		t.Fatalf("evidence = %+v, want semantic hit", evidence)
+	}
+
+	_, _ = p.Chat(context.Background(), cacheRequest("how are stocks doing"))
+	if inner.calls != 2 {
+		t.Fatalf("inner calls = %d, want 2 (dissimilar message must miss)", inner.calls)
+	}
+}
+
+func TestCachingProviderStreamReplaysHit(t *testing.T) {
+	inner := &failoverStubProvider{name: "anthropic", model: "claude-sonnet-4"}
+	p := NewCachingProvider(inner, newTestResponseCache(), "tenant/agent", time.Minute, 0)
+	_, _ = p.Chat(context.Background(), cacheRequest("morning report"))
+
+	var content string
+	var done bool
+	resp, err := p.ChatStream(context.Background(), cacheRequest("morning report"), func(chunk StreamChunk) {
+		content += chunk.Content
+		done = done || chunk.Done
+	})
+	if err != nil {
+		t.Fatalf("ChatStream() error = %v", err)
+	}
+	if inner.calls != 1 || content != resp.Content || !done {
+		t.Fatalf("calls = %d, streamed %q (done=%v), want replayed %q", inner.calls, content, done, resp.Content)
+	}
+}
+
+func TestMergeResponseCacheMetadata(t *testing.T) {
+	existing := json.RawMessage(`{"thinking_tokens":12}`)
+	merged := MergeResponseCacheMetadata(existing, ResponseCacheEvidence{Status: ResponseCacheHit, Key: "abc"})
+
+	var payload map[string]any
+	if err := json.Unmarshal(merged, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if payload["thinking_tokens"] != float64(12) {
+		t.Fatalf("existing metadata lost: %s", merged)
+	}
+	entry, _ := payload[ResponseCacheMetadataKey].(map[string]any)
+	if entry["status"] != ResponseCacheHit {
+		t.Fatalf("metadata = %s", merged)
+	}
+}
+
+type toolCallStubProvider struct{ calls int }
+
+func (p *toolCallStubProvider) Chat(context.Context, ChatRequest) (*ChatResponse, error) {
+	p.calls++
+	return &ChatResponse{
+		Content:      "searching",
+		ToolCalls:    []ToolCall{{ID: "call_1", Name: "web_search"}},
+		FinishReason: "tool_calls",
+	}, nil
+}
+
+func (p *toolCallStubProvider) ChatStream(ctx context.Context, req ChatRequest, _ func(StreamChunk)) (*ChatResponse, error) {
+	return p.Chat(ctx, req)
+}
+
+func (p *toolCallStubProvider) DefaultModel() string { return "claude-sonnet-4" }
+func (p *toolCallStubProvider) Name() string         { return "anthropic" }
+
+type eligibilityStubProvider struct {
+	testThinkingProvider
+	class RouteEligibilityClass
+}
+
+func (p eligibilityStubProvider) RouteEligibility(context.Context) RouteEligibility {
+	return RouteEligibility{Class: p.class}
+}
+
+func TestCachingProviderForwardsCapabilities(t *testing.T) {
+	inner := eligibilityStubProvider{testThinkingProvider: testThinkingProvider{thinking: true}, class: RouteEligibilityBlocked}
+	p := NewCachingProvider(inner, newTestResponseCache(), "tenant/agent", time.Minute, 0)
+
+	decision := ResolveReasoningDecision(p, "gpt-5.1-codex", "high", "downgrade", "agent")
+	if decision.EffectiveEffort != "high" {
+		t.Fatalf("EffectiveEffort = %q, want high through the cache wrapper", decision.EffectiveEffort)
+	}
+	if got := p.RouteEligibility(context.Background()).Class; got != RouteEligibilityBlocked {
+		t.Fatalf("RouteEligibility = %q, want blocked", got)
+	}
+
+	plain := NewCachingProvider(&toolCallStubProvider{}, newTestResponseCache(), "tenant/agent", time.Minute, 0)
+	if plain.SupportsThinking() {
+		t.Fatal("SupportsThinking() = true for an inner provider without reasoning controls")
+	}
+	if got := plain.RouteEligibility(context.Background()).Class; got != RouteEligibilityHealthy {
+		t.Fatalf("RouteEligibility = %q, want healthy", got)
+	}
+}
@@ -0,0 +1,242 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Response format types (mirrors OpenAI's response_format.type).
+const (
+	ResponseFormatText       = "text"
+	ResponseFormatJSONObject = "json_object"
+	ResponseFormatJSONSchema = "json_schema"
+)
+
+// defaultSchemaName is used when a json_schema format has no name.
+const defaultSchemaName = "response"
+
+// schemaNameRe is the name constraint shared by OpenAI schema names and
+// Anthropic tool names.
+var schemaNameRe = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// ResponseFormat constrains the assistant's final message to JSON. The wire
+// shape matches OpenAI's response_format so it can be passed through from
+// /v1/chat/completions unchanged; each provider maps it to its native
+// mechanism (response_format, tool forcing, responseSchema, text.format).
+type ResponseFormat struct {
+	Type       string            `json:"type"` // "text", "json_object", "json_schema"
+	JSONSchema *JSONSchemaFormat `json:"json_schema,omitempty"`
+}
+
+// JSONSchemaFormat is the schema of a json_schema response format.
+type JSONSchemaFormat struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Schema      map[string]any `json:"schema"`
+	Strict      *bool          `json:"strict,omitempty"` // OpenAI constrained decoding
+}
+
+// JSONSchemaResponse is shorthand for a json_schema response format.
+func JSONSchemaResponse(name string, schema map[string]any) *ResponseFormat {
+	return &ResponseFormat{
+		Type:       ResponseFormatJSONSchema,
+		JSONSchema: &JSONSchemaFormat{Name: name, Schema: schema},
+	}
+}
+
+// IsJSON reports whether rf asks for JSON output (nil and "text" do not).
+func (rf *ResponseFormat) IsJSON() bool {
+	return rf != nil && (rf.Type == ResponseFormatJSONObject || rf.Type == ResponseFormatJSONSchema)
+}
+
+// schema returns the JSON schema of a json_schema format, or nil.
+func (rf *ResponseFormat) schema() map[string]any {
+	if rf == nil || rf.Type != ResponseFormatJSONSchema || rf.JSONSchema == nil {
+		return nil
+	}
+	return rf.JSONSchema.Schema
+}
+
+// schemaName returns the schema name, defaulting to "response".
+func (rf *ResponseFormat) schemaName() string {
+	if rf != nil && rf.JSONSchema != nil && rf.JSONSchema.Name != "" {
+		return rf.JSONSchema.Name
+	}
+	return defaultSchemaName
+}
+
+// Validate checks that rf is well-formed. Used on formats supplied by API callers.
+func (rf *ResponseFormat) Validate() error {
+	if rf == nil {
+		return nil
+	}
+	switch rf.Type {
+	case ResponseFormatText, ResponseFormatJSONObject:
+		return nil
+	case ResponseFormatJSONSchema:
+		if rf.JSONSchema == nil || len(rf.JSONSchema.Schema) == 0 {
+			return errors.New("response_format: json_schema.schema is required")
+		}
+		if n := rf.JSONSchema.Name; n != "" && !schemaNameRe.MatchString(n) {
+			return fmt.Errorf("response_format: invalid json_schema.name '%s' (letters, digits, _ and -, max 64)", n)
+		}
+		if t, ok := rf.JSONSchema.Schema["type"].(string); ok && t != "object" {
+			return errors.New("response_format: json_schema.schema must have type 'object'")
+		}
+		return nil
+	default:
+		return fmt.Errorf("response_format: unsupported type '%s'", rf.Type)
+	}
+}
+
+// Instruction returns a prompt describing the required output. Providers
+// without native schema support append it as a system message; it also
+// satisfies OpenAI's rule that json_object requests mention "JSON".
+func (rf *ResponseFormat) Instruction() string {
+	if !rf.IsJSON() {
+		return ""
+	}
+	if s := rf.schema(); s != nil {
+		data, _ := json.Marshal(s)
+		return fmt.Sprintf("Respond with a single JSON object that conforms to this JSON Schema (%s), with no prose or code fences:\n%s", rf.schemaName(), data)
+	}
+	return "Respond with a single valid JSON object, with no prose or code fences."
+}
+
+// StructuredOutputError reports final content that does not satisfy the
+// requested response format.
+type StructuredOutputError struct {
+	Err error
+}
+
+func (e *StructuredOutputError) Error() string { return "invalid structured output: " + e.Err.Error() }
+func (e *StructuredOutputError) Unwrap() error { return e.Err }
+
+// CheckStructuredOutput validates content against rf and returns it with any
+// surrounding code fence removed. Non-JSON formats pass through unchanged.
+func CheckStructuredOutput(rf *ResponseFormat, content string) (string, error) {
+	if !rf.IsJSON() {
+		return content, nil
+	}
+	cleaned := stripJSONFence(content)
+	if cleaned == "" {
+		return content, &StructuredOutputError{Err: errors.New("response is empty")}
+	}
+	var v any
+	if err := json.Unmarshal([]byte(cleaned), &v); err != nil {
+		return content, &StructuredOutputError{Err: fmt.Errorf("response is not valid JSON: %w", err)}
+	}
+	if _, ok := v.(map[string]any); !ok {
+		return content, &StructuredOutputError{Err: errors.New("response must be a JSON object")}
+	}
+	if err := ValidateJSONSchema(rf.schema(), v); err != nil {
+		return content, &StructuredOutputError{Err: err}
+	}
+	return cleaned, nil
+}
+
+// RepairPrompt is the follow-up message asking the model to fix output that
+// failed CheckStructuredOutput.
+func RepairPrompt(rf *ResponseFormat, err error) string {
+	var ve *StructuredOutputError
+	if errors.As(err, &ve) {
+		err = ve.Err
+	}
+	return fmt.Sprintf("[System] Your previous response did not satisfy the required output format: %v\n%s", err, rf.Instruction())
+}
+
+// ChatStructured calls p.Chat and validates the response against
+// req.ResponseFormat. Invalid output gets one repair attempt: the model sees
+// its response and the validation error and is asked to answer again. If the
+// repaired response is still invalid it is returned with a
+// *StructuredOutputError so callers can fall back to lenient parsing.
+func ChatStructured(ctx context.Context, p Provider, req ChatRequest) (*ChatResponse, error) {
+	resp, err := p.Chat(ctx, req)
+	if err != nil || !req.ResponseFormat.IsJSON() || len(resp.ToolCalls) > 0 {
+		return resp, err
+	}
+	content, verr := CheckStructuredOutput(req.ResponseFormat, resp.Content)
+	if verr == nil {
+		resp.Content = content
+		return resp, nil
+	}
+	if resp.FinishReason == "length" {
+		return resp, verr // truncated output: a repair would be truncated too
+	}
+
+	slog.Debug("structured output invalid, retrying with repair prompt", "provider", p.Name(), "error", verr)
+	repair := req
+	repair.Messages = append(append([]Message(nil), req.Messages...),
+		Message{Role: "assistant", Content: resp.Content},
+		Message{Role: "user", Content: RepairPrompt(req.ResponseFormat, verr)},
+	)
+	retry, err := p.Chat(ctx, repair)
+	if err != nil {
+		return resp, verr
+	}
+	mergeUsage(retry, resp.Usage)
+	content, verr = CheckStructuredOutput(req.ResponseFormat, retry.Content)
+	if verr != nil {
+		return retry, verr
+	}
+	retry.Content = content
+	return retry, nil
+}
+
+// withJSONInstruction returns msgs with rf's instruction appended as a system
+// message, for providers that have no native schema enforcement.
+func withJSONInstruction(msgs []Message, rf *ResponseFormat) []Message {
+	// Insert after the leading system block so prompt-cache prefixes stay stable.
+	i := 0
+	for i < len(msgs) && msgs[i].Role == "system" {
+		i++
+	}
+	return slices.Insert(slices.Clone(msgs), i, Message{Role: "system", Content: rf.Instruction()})
+}
+
+// mentionsJSON reports whether any message mentions JSON (OpenAI rejects
+// json_object requests otherwise).
+func mentionsJSON(msgs []Message) bool {
+	for _, m := range msgs {
+		if strings.Contains(strings.ToLower(m.Content), "json") {
+			return true
+		}
+	}
+	return false
+}
+
+// stripJSONFence removes a ```json ... ``` wrapper and surrounding whitespace.
+func stripJSONFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+	if idx := strings.Index(s, "\n"); idx >= 0 {
+		s = s[idx+1:]
+	}
+	if idx := strings.LastIndex(s, "```"); idx >= 0 {
+		s = s[:idx]
+	}
+	return strings.TrimSpace(s)
+}
+
+func mergeUsage(dst *ChatResponse, prev *Usage) {
+	if prev == nil {
+		return
+	}
+	if dst.Usage == nil {
+		dst.Usage = &Usage{}
+	}
+	dst.Usage.PromptTokens += prev.PromptTokens
+	dst.Usage.CompletionTokens += prev.CompletionTokens
+	dst.Usage.TotalTokens += prev.TotalTokens
+	dst.Usage.CacheCreationTokens += prev.CacheCreationTokens
+	dst.Usage.CacheReadTokens += prev.CacheReadTokens
+	dst.Usage.ThinkingTokens += prev.ThinkingTokens
+}
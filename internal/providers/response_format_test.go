@@ -0,0 +1,299 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var testPersonSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"name": map[string]any{"type": "string", "minLength": 1},
+		"age":  map[string]any{"type": "integer", "minimum": 0},
+		"role": map[string]any{"type": "string", "enum": []string{"admin", "member"}},
+		"tags": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+	},
+	"required":             []string{"name", "age"},
+	"additionalProperties": false,
+}
+
+func TestValidateJSONSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{"valid", `{"name":"An","age":30,"role":"admin","tags":["x"]}`, ""},
+		{"null optional", `{"name":"An","age":30,"role":null}`, ""},
+		{"missing required", `{"name":"An"}`, `missing required property "age"`},
+		{"wrong type", `{"name":"An","age":"30"}`, "$.age: expected integer, got string"},
+		{"non-integer", `{"name":"An","age":1.5}`, "expected integer"},
+		{"enum", `{"name":"An","age":1,"role":"owner"}`, "is not one of"},
+		{"extra property", `{"name":"An","age":1,"email":"a@b"}`, "$.email: unexpected property"},
+		{"array item", `{"name":"An","age":1,"tags":[1]}`, "$.tags[0]: expected string"},
+		{"min length", `{"name":"","age":1}`, "at least 1 characters"},
+		{"minimum", `{"name":"An","age":-1}`, "below minimum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			if err := json.Unmarshal([]byte(tt.json), &v); err != nil {
+				t.Fatal(err)
+			}
+			err := ValidateJSONSchema(testPersonSchema, v)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateJSONSchemaRefsAndAnyOf(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"$defs": map[string]any{
+			"id": map[string]any{"anyOf": []any{
+				map[string]any{"type": "string"},
+				map[string]any{"type": "integer"},
+			}},
+		},
+		"properties": map[string]any{"id": map[string]any{"$ref": "#/$defs/id"}},
+	}
+	if err := ValidateJSONSchema(schema, map[string]any{"id": float64(7)}); err != nil {
+		t.Fatalf("integer id: %v", err)
+	}
+	if err := ValidateJSONSchema(schema, map[string]any{"id": true}); err == nil {
+		t.Fatal("boolean id should not match anyOf")
+	}
+}
+
+func TestCheckStructuredOutput(t *testing.T) {
+	rf := JSONSchemaResponse("person", testPersonSchema)
+
+	got, err := CheckStructuredOutput(rf, "```json\n{\"name\":\"An\",\"age\":3}\n```")
+	if err != nil {
+		t.Fatalf("fenced output: %v", err)
+	}
+	if got != `{"name":"An","age":3}` {
+		t.Errorf("content = %q, want fence stripped", got)
+	}
+
+	for _, bad := range []string{"", "not json", `["a"]`, `{"name":"An"}`} {
+		_, err := CheckStructuredOutput(rf, bad)
+		var soe *StructuredOutputError
+		if !errors.As(err, &soe) {
+			t.Errorf("CheckStructuredOutput(%q) error = %v, want *StructuredOutputError", bad, err)
+		}
+	}
+
+	if got, err := CheckStructuredOutput(nil, "plain text"); err != nil || got != "plain text" {
+		t.Errorf("nil format = (%q, %v), want passthrough", got, err)
+	}
+	if _, err := CheckStructuredOutput(&ResponseFormat{Type: ResponseFormatJSONObject}, `{"any":1}`); err != nil {
+		t.Errorf("json_object: %v", err)
+	}
+}
+
+func TestResponseFormatValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		rf   *ResponseFormat
+		ok   bool
+	}{
+		{"nil", nil, true},
+		{"text", &ResponseFormat{Type: "text"}, true},
+		{"json_object", &ResponseFormat{Type: "json_object"}, true},
+		{"json_schema", JSONSchemaResponse("person", testPersonSchema), true},
+		{"missing schema", &ResponseFormat{Type: "json_schema"}, false},
+		{"bad name", JSONSchemaResponse("my schema", testPersonSchema), false},
+		{"array root", JSONSchemaResponse("list", map[string]any{"type": "array"}), false},
+		{"unknown type", &ResponseFormat{Type: "xml"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rf.Validate()
+			if (err == nil) != tt.ok {
+				t.Fatalf("Validate() = %v, want ok=%v", err, tt.ok)
+			}
+			if err != nil && strings.Contains(err.Error(), `"`) {
+				t.Errorf("error %q contains a double quote (breaks JSON error bodies)", err)
+			}
+		})
+	}
+}
+
+func TestOpenAIBuildRequestBodyResponseFormat(t *testing.T) {
+	req := ChatRequest{
+		Messages:       []Message{{Role: "system", Content: "sys"}, {Role: "user", Content: "who?"}},
+		ResponseFormat: JSONSchemaResponse("person", testPersonSchema),
+	}
+
+	p := NewOpenAIProvider("openai", "key", "https://api.openai.com/v1", "gpt-4o")
+	body := p.buildRequestBody("gpt-4o", req, false)
+	rf, _ := body["response_format"].(map[string]any)
+	if rf["type"] != "json_schema" {
+		t.Fatalf("response_format = %v, want json_schema", body["response_format"])
+	}
+	if js, _ := rf["json_schema"].(map[string]any); js["name"] != "person" || js["schema"] == nil {
+		t.Errorf("json_schema = %v, want name and schema", js)
+	}
+	if msgs := body["messages"].([]map[string]any); len(msgs) != 2 {
+		t.Errorf("native json_schema should not inject an instruction, got %d messages", len(msgs))
+	}
+
+	// DeepSeek only accepts json_object: the schema moves into a system message.
+	ds := NewOpenAIProvider("deepseek", "key", "https://api.deepseek.com/v1", "deepseek-chat")
+	body = ds.buildRequestBody("deepseek-chat", req, false)
+	if rf, _ := body["response_format"].(map[string]any); rf["type"] != "json_object" {
+		t.Fatalf("deepseek response_format = %v, want json_object", body["response_format"])
+	}
+	msgs := body["messages"].([]map[string]any)
+	if len(msgs) != 3 || msgs[1]["role"] != "system" || !strings.Contains(msgs[1]["content"].(string), `"age"`) {
+		t.Errorf("deepseek messages = %v, want schema instruction after the system prompt", msgs)
+	}
+}
+
+func TestAnthropicBuildRequestBodyResponseFormat(t *testing.T) {
+	p := NewAnthropicProvider("key")
+	req := ChatRequest{
+		Messages:       []Message{{Role: "user", Content: "who?"}},
+		ResponseFormat: JSONSchemaResponse("person", testPersonSchema),
+	}
+
+	body := p.buildRequestBody("claude-sonnet-4", req, false)
+	tools, _ := body["tools"].([]map[string]any)
+	if len(tools) != 1 || tools[0]["name"] != anthropicOutputTool {
+		t.Fatalf("tools = %v, want only the output tool", body["tools"])
+	}
+	if tc, _ := body["tool_choice"].(map[string]any); tc["name"] != anthropicOutputTool {
+		t.Errorf("tool_choice = %v, want forced output tool", body["tool_choice"])
+	}
+
+	// With real tools the output tool must not be forced.
+	req.Tools = []ToolDefinition{{Type: "function", Function: ToolFunctionSchema{Name: "search", Parameters: map[string]any{"type": "object"}}}}
+	body = p.buildRequestBody("claude-sonnet-4", req, false)
+	if _, forced := body["tool_choice"]; forced {
+		t.Errorf("tool_choice = %v, want unset alongside other tools", body["tool_choice"])
+	}
+	if tools, _ := body["tools"].([]map[string]any); len(tools) != 2 {
+		t.Errorf("tools = %d, want 2", len(tools))
+	}
+}
+
+func TestTakeStructuredOutput(t *testing.T) {
+	resp := &ChatResponse{
+		ToolCalls: []ToolCall{
+			{ID: "toolu_1", Name: "search", Arguments: map[string]any{"q": "x"}},
+			{ID: "toolu_2", Name: anthropicOutputTool, Arguments: map[string]any{"name": "An"}},
+		},
+		FinishReason:        "tool_calls",
+		RawAssistantContent: json.RawMessage(`[{"type":"tool_use","id":"toolu_1"},{"type":"tool_use","id":"toolu_2"}]`),
+	}
+	takeStructuredOutput(resp, "")
+	if resp.Content != `{"name":"An"}` {
+		t.Errorf("content = %q", resp.Content)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Name != "search" {
+		t.Errorf("tool calls = %v, want only search", resp.ToolCalls)
+	}
+	if strings.Contains(string(resp.RawAssistantContent), "toolu_2") {
+		t.Errorf("raw content still has output tool block: %s", resp.RawAssistantContent)
+	}
+
+	resp = &ChatResponse{
+		ToolCalls:    []ToolCall{{ID: "toolu_3", Name: anthropicOutputTool}},
+		FinishReason: "tool_calls",
+	}
+	takeStructuredOutput(resp, ` {"b":1,"a":2} `)
+	if resp.Content != `{"b":1,"a":2}` || resp.FinishReason != "stop" || len(resp.ToolCalls) != 0 {
+		t.Errorf("resp = %+v, want streamed JSON as content and stop", resp)
+	}
+}
+
+func TestCodexBuildRequestBodyResponseFormat(t *testing.T) {
+	p := NewCodexProvider("test", &staticTokenSource{token: "test"}, "", "gpt-5")
+	req := ChatRequest{
+		Messages:       []Message{{Role: "user", Content: "who?"}},
+		ResponseFormat: JSONSchemaResponse("person", testPersonSchema),
+	}
+	body := p.buildRequestBody(req, false)
+	text, _ := body["text"].(map[string]any)
+	format, _ := text["format"].(map[string]any)
+	if format["type"] != "json_schema" || format["name"] != "person" || format["schema"] == nil {
+		t.Fatalf("text.format = %v", text["format"])
+	}
+
+	req.ResponseFormat = &ResponseFormat{Type: ResponseFormatJSONObject}
+	body = p.buildRequestBody(req, false)
+	if !strings.Contains(body["instructions"].(string), "JSON") {
+		t.Errorf("instructions = %q, want JSON mention for json_object", body["instructions"])
+	}
+}
+
+type structuredStubProvider struct {
+	replies []string
+	reqs    []ChatRequest
+}
+
+func (p *structuredStubProvider) Chat(_ context.Context, req ChatRequest) (*ChatResponse, error) {
+	p.reqs = append(p.reqs, req)
+	content := p.replies[len(p.reqs)-1]
+	return &ChatResponse{Content: content, FinishReason: "stop", Usage: &Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}}, nil
+}
+
+func (p *structuredStubProvider) ChatStream(ctx context.Context, req ChatRequest, _ func(StreamChunk)) (*ChatResponse, error) {
+	return p.Chat(ctx, req)
+}
+
+func (p *structuredStubProvider) DefaultModel() string { return "stub" }
+func (p *structuredStubProvider) Name() string         { return "stub" }
+
+func TestChatStructuredRepairsInvalidOutput(t *testing.T) {
+	p := &structuredStubProvider{replies: []string{`{"name":"An"}`, "```json\n{\"name\":\"An\",\"age\":3}\n```"}}
+	req := ChatRequest{
+		Messages:       []Message{{Role: "user", Content: "who?"}},
+		ResponseFormat: JSONSchemaResponse("person", testPersonSchema),
+	}
+	resp, err := ChatStructured(context.Background(), p, req)
+	if err != nil {
+		t.Fatalf("ChatStructured: %v", err)
+	}
+	if resp.Content != `{"name":"An","age":3}` {
+		t.Errorf("content = %q", resp.Content)
+	}
+	if len(p.reqs) != 2 {
+		t.Fatalf("calls = %d, want 2", len(p.reqs))
+	}
+	repair := p.reqs[1].Messages
+	if len(repair) != 3 || repair[1].Role != "assistant" || !strings.Contains(repair[2].Content, `missing required property "age"`) {
+		t.Errorf("repair messages = %+v", repair)
+	}
+	if resp.Usage.TotalTokens != 30 {
+		t.Errorf("usage total = %d, want both calls merged", resp.Usage.TotalTokens)
+	}
+}
+
+func TestChatStructuredGivesUpAfterOneRepair(t *testing.T) {
+	p := &structuredStubProvider{replies: []string{"nope", "still nope"}}
+	req := ChatRequest{
+		Messages:       []Message{{Role: "user", Content: "who?"}},
+		ResponseFormat: JSONSchemaResponse("person", testPersonSchema),
+	}
+	resp, err := ChatStructured(context.Background(), p, req)
+	var soe *StructuredOutputError
+	if !errors.As(err, &soe) {
+		t.Fatalf("error = %v, want *StructuredOutputError", err)
+	}
+	if resp == nil || resp.Content != "still nope" || len(p.reqs) != 2 {
+		t.Errorf("resp = %+v after %d calls", resp, len(p.reqs))
+	}
+}
@@ -0,0 +1,276 @@
+package providers
+
+// schema_validate.go — JSON Schema validation of structured model output.
+// Covers the subset of JSON Schema that providers accept for response schemas:
+// type, enum, const, properties, required, additionalProperties, items,
+// min/max items, lengths and bounds, anyOf/oneOf/allOf, and local $refs.
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSchemaErrors caps how many violations are reported back to the model.
+const maxSchemaErrors = 5
+
+// ValidateJSONSchema checks a decoded JSON value against schema and returns
+// an error listing the first few violations. Refs to $defs/definitions are
+// resolved. A null value for a non-required property counts as absent, so
+// output produced under OpenAI strict mode (optional → nullable) validates
+// against the caller's original schema.
+func ValidateJSONSchema(schema map[string]any, value any) error {
+	if len(schema) == 0 {
+		return nil
+	}
+	resolved := copySchema(schema)
+	resolved = resolveRefs(resolved, collectDefs(resolved), nil, 0)
+
+	var errs []string
+	validateValue(resolved, value, "$", 0, &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	if len(errs) > maxSchemaErrors {
+		errs = append(errs[:maxSchemaErrors], fmt.Sprintf("… and %d more", len(errs)-maxSchemaErrors))
+	}
+	return fmt.Errorf("schema violations: %s", strings.Join(errs, "; "))
+}
+
+func validateValue(schema map[string]any, v any, path string, depth int, errs *[]string) {
+	if schema == nil || depth > maxSchemaDepth {
+		return
+	}
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if types := schemaTypes(schema); len(types) > 0 && !slices.ContainsFunc(types, func(t string) bool { return matchesType(t, v) }) {
+		fail("expected %s, got %s", strings.Join(types, " or "), jsonTypeName(v))
+		return
+	}
+	if enum := schemaList(schema["enum"]); enum != nil && !slices.ContainsFunc(enum, func(e any) bool { return jsonEqual(e, v) }) {
+		fail("value %s is not one of %s", compactJSON(v), compactJSON(enum))
+	}
+	if c, ok := schema["const"]; ok && !jsonEqual(c, v) {
+		fail("value must be %s", compactJSON(c))
+	}
+
+	for _, key := range []string{"anyOf", "oneOf"} {
+		variants, ok := schema[key].([]any)
+		if !ok || len(variants) == 0 {
+			continue
+		}
+		matched := false
+		for _, variant := range variants {
+			vm, ok := variant.(map[string]any)
+			if !ok {
+				continue
+			}
+			var sub []string
+			validateValue(vm, v, path, depth+1, &sub)
+			if len(sub) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("value does not match any allowed schema")
+		}
+	}
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, variant := range all {
+			if vm, ok := variant.(map[string]any); ok {
+				validateValue(vm, v, path, depth+1, errs)
+			}
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		validateObject(schema, val, path, depth, errs)
+	case []any:
+		if n, ok := schemaInt(schema, "minItems"); ok && len(val) < n {
+			fail("expected at least %d items, got %d", n, len(val))
+		}
+		if n, ok := schemaInt(schema, "maxItems"); ok && len(val) > n {
+			fail("expected at most %d items, got %d", n, len(val))
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range val {
+				validateValue(items, item, fmt.Sprintf("%s[%d]", path, i), depth+1, errs)
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(val)
+		if m, ok := schemaInt(schema, "minLength"); ok && n < m {
+			fail("expected at least %d characters", m)
+		}
+		if m, ok := schemaInt(schema, "maxLength"); ok && n > m {
+			fail("expected at most %d characters", m)
+		}
+		if p, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(p); err == nil && !re.MatchString(val) {
+				fail("value does not match pattern %q", p)
+			}
+		}
+	case float64:
+		if m, ok := schemaFloat(schema, "minimum"); ok && val < m {
+			fail("value %v is below minimum %v", val, m)
+		}
+		if m, ok := schemaFloat(schema, "maximum"); ok && val > m {
+			fail("value %v is above maximum %v", val, m)
+		}
+	}
+}
+
+func validateObject(schema map[string]any, obj map[string]any, path string, depth int, errs *[]string) {
+	props, _ := schema["properties"].(map[string]any)
+	required := schemaStrings(schema["required"])
+	for _, name := range required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, fmt.Sprintf("%s: missing required property %q", path, name))
+		}
+	}
+
+	extra, _ := schema["additionalProperties"].(map[string]any)
+	closed := schema["additionalProperties"] == false
+	for _, name := range slices.Sorted(maps.Keys(obj)) {
+		val := obj[name]
+		child := path + "." + name
+		ps, known := props[name].(map[string]any)
+		switch {
+		case known:
+			if val == nil && !slices.Contains(required, name) {
+				continue
+			}
+			validateValue(ps, val, child, depth+1, errs)
+		case closed:
+			*errs = append(*errs, fmt.Sprintf("%s: unexpected property", child))
+		case extra != nil:
+			validateValue(extra, val, child, depth+1, errs)
+		}
+	}
+}
+
+// schemaTypes returns the allowed types of a schema ("type": "x" or ["x", "null"]).
+func schemaTypes(schema map[string]any) []string {
+	switch t := schema["type"].(type) {
+	case string:
+		return []string{t}
+	case []any:
+		return schemaStrings(t)
+	case []string:
+		return t
+	}
+	return nil
+}
+
+func schemaStrings(v any) []string {
+	switch arr := v.(type) {
+	case []string:
+		return arr
+	case []any:
+		out := make([]string, 0, len(arr))
+		for _, item := range arr {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// schemaList accepts both decoded JSON arrays and Go string slices.
+func schemaList(v any) []any {
+	switch arr := v.(type) {
+	case []any:
+		return arr
+	case []string:
+		out := make([]any, len(arr))
+		for i, s := range arr {
+			out[i] = s
+		}
+		return out
+	}
+	return nil
+}
+
+func schemaInt(schema map[string]any, key string) (int, bool) {
+	f, ok := schemaFloat(schema, key)
+	return int(f), ok
+}
+
+func schemaFloat(schema map[string]any, key string) (float64, bool) {
+	switch n := schema[key].(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func matchesType(t string, v any) bool {
+	switch t {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	}
+	return true // unknown type keyword: don't reject
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// jsonEqual compares two values by their JSON encoding, so schema literals
+// (possibly Go ints) compare equal to decoded JSON numbers.
+func jsonEqual(a, b any) bool {
+	return compactJSON(a) == compactJSON(b)
+}
+
+func compactJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
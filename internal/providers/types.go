@@ -67,6 +67,9 @@ type ChatRequest struct {
 	Tools    []ToolDefinition `json:"tools,omitempty"`
 	Model    string           `json:"model,omitempty"`
 	Options  map[string]any   `json:"options,omitempty"`
+
+	// ResponseFormat constrains the final assistant message to JSON (nil = free text).
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
 }
 
 // ChatResponse is the result from an LLM call.
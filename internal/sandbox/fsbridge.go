@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -77,6 +78,21 @@ func (b *FsBridge) WriteFile(ctx context.Context, path, content string, appendMo
 	return nil
 }
 
+// Remove deletes a file inside the container. A missing file is not an error.
+func (b *FsBridge) Remove(ctx context.Context, path string) error {
+	resolved := b.resolvePath(path)
+
+	_, stderr, exitCode, err := b.dockerExec(ctx, nil, "rm", "-f", "--", resolved)
+	if err != nil {
+		return fmt.Errorf("fsbridge remove: %w", err)
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("remove failed: %s", strings.TrimSpace(stderr))
+	}
+
+	return nil
+}
+
 // ListDir lists files and directories inside the container.
 // Matching TS FsBridge.readdir().
 func (b *FsBridge) ListDir(ctx context.Context, path string) (string, error) {
@@ -109,6 +125,82 @@ func (b *FsBridge) Stat(ctx context.Context, path string) (string, error) {
 	return stdout, nil
 }
 
+// SearchOptions controls FsBridge.Search.
+type SearchOptions struct {
+	Pattern     string                // content prefilter (PCRE); "" = list files only
+	Literal     bool                  // treat Pattern as fixed text
+	IgnoreCase  bool                  // case-insensitive Pattern
+	Match       func(rel string) bool // path filter relative to the search root; nil = all files
+	MaxScan     int                   // files listed in the container before Match is applied
+	MaxFiles    int                   // files returned
+	MaxFileSize int64                 // larger files are skipped; 0 = no limit
+}
+
+// Search returns the container paths of regular files under path that pass
+// opts.Match and, when opts.Pattern is set, contain a matching line. .git and
+// node_modules are never descended into and binary files are skipped. Both
+// listings are cut with head in the container, so large trees never come
+// back in full. grep -P only narrows the candidates: callers re-check the
+// content with their own regexp engine.
+func (b *FsBridge) Search(ctx context.Context, path string, opts SearchOptions) ([]string, error) {
+	resolved := b.resolvePath(path)
+
+	sizeFilter := ""
+	if opts.MaxFileSize > 0 {
+		sizeFilter = fmt.Sprintf("-size -%dc ", opts.MaxFileSize+1)
+	}
+	script := `find "$1" \( -name .git -o -name node_modules \) -prune -o -type f ` + sizeFilter + `-print | head -n "$2"`
+	stdout, stderr, exitCode, err := b.dockerExec(ctx, nil, "sh", "-c", script, "sh", resolved, strconv.Itoa(max(opts.MaxScan, 1)))
+	if err != nil {
+		return nil, fmt.Errorf("fsbridge search: %w", err)
+	}
+	if exitCode != 0 || (stdout == "" && stderr != "") {
+		return nil, fmt.Errorf("search failed: %s", strings.TrimSpace(stderr))
+	}
+
+	var files []string
+	for _, p := range strings.Split(strings.TrimRight(stdout, "\n"), "\n") {
+		if p == "" {
+			continue
+		}
+		// A file given as the search root is searched regardless of the filter.
+		if p != resolved && opts.Match != nil && !opts.Match(strings.TrimPrefix(p, resolved+"/")) {
+			continue
+		}
+		files = append(files, p)
+	}
+	if opts.Pattern == "" || len(files) == 0 {
+		return files[:min(len(files), max(opts.MaxFiles, 1))], nil
+	}
+
+	flags := "-lI -P"
+	if opts.Literal {
+		flags = "-lI -F"
+	}
+	if opts.IgnoreCase {
+		flags += " -i"
+	}
+	script = `xargs -0 grep ` + flags + ` -e "$1" -- | head -n "$2"`
+	stdin := []byte(strings.Join(files, "\x00"))
+	stdout, stderr, _, err = b.dockerExec(ctx, stdin, "sh", "-c", script, "sh", opts.Pattern, strconv.Itoa(max(opts.MaxFiles, 1)))
+	if err != nil {
+		return nil, fmt.Errorf("fsbridge search: %w", err)
+	}
+	// The pipeline's status is head's; grep's "no match" and per-file errors
+	// only show up as empty output with a diagnostic.
+	if stdout == "" && stderr != "" {
+		return nil, fmt.Errorf("search failed: %s", strings.TrimSpace(stderr))
+	}
+
+	var matched []string
+	for _, p := range strings.Split(strings.TrimRight(stdout, "\n"), "\n") {
+		if p != "" {
+			matched = append(matched, p)
+		}
+	}
+	return matched, nil
+}
+
 // resolvePath resolves a path relative to the container workdir.
 // Validates that absolute paths stay within the workdir (defense in depth).
 func (b *FsBridge) resolvePath(path string) string {
@@ -61,6 +61,11 @@ type RunFunc func(ctx context.Context, req agent.RunRequest) (*agent.RunResult,
 // Used by adaptive throttle to reduce concurrency near the summary threshold.
 type TokenEstimateFunc func(sessionKey string) (tokens int, contextWindow int)
 
+// SessionLockFunc acquires a cross-node run lock for a session and returns its
+// release function. The run executes under runCtx, which is cancelled if the
+// lock is lost mid-run. Used when several gateway replicas share sessions.
+type SessionLockFunc func(ctx context.Context, sessionKey string) (runCtx context.Context, unlock func(), err error)
+
 // PendingRequest is a queued agent run awaiting execution.
 type PendingRequest struct {
 	Req        agent.RunRequest
@@ -100,6 +105,7 @@ type SessionQueue struct {
 	generation      uint64                    // bumped on Reset() to ignore stale completions
 
 	tokenEstimateFn TokenEstimateFunc // optional: for adaptive throttle
+	sessionLockFn   SessionLockFunc   // optional: cross-node serialization
 }
 
 // NewSessionQueue creates a queue for a specific session.
@@ -312,7 +318,7 @@ func (sq *SessionQueue) executeRun(ctx context.Context, runID string, runGenerat
 		}
 	}()
 
-	result, err := sq.runFn(ctx, pending.Req)
+	result, err := sq.run(ctx, pending.Req)
 	pending.ResultCh <- RunOutcome{Result: result, Err: err}
 	close(pending.ResultCh)
 
@@ -334,6 +340,26 @@ func (sq *SessionQueue) executeRun(ctx context.Context, runID string, runGenerat
 	sq.mu.Unlock()
 }
 
+// run executes the request, holding the cross-node session lock when one is
+// configured and the session is serial. Concurrent (group) sessions are not
+// locked since they already allow parallel runs.
+func (sq *SessionQueue) run(ctx context.Context, req agent.RunRequest) (*agent.RunResult, error) {
+	sq.mu.Lock()
+	lockFn := sq.sessionLockFn
+	serial := sq.maxConcurrent <= 1
+	sq.mu.Unlock()
+
+	if lockFn == nil || !serial {
+		return sq.runFn(ctx, req)
+	}
+	runCtx, unlock, err := lockFn(ctx, sq.key)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return sq.runFn(runCtx, req)
+}
+
 // removeFromOrder removes a runID from the activeOrder slice.
 // Must be called with sq.mu held.
 func (sq *SessionQueue) removeFromOrder(runID string) {
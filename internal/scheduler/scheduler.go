@@ -26,6 +26,7 @@ type Scheduler struct {
 	mu              sync.RWMutex
 	draining        atomic.Bool       // set during graceful shutdown to reject new requests
 	tokenEstimateFn TokenEstimateFunc // optional: for adaptive throttle
+	sessionLockFn   SessionLockFunc   // optional: cross-node session serialization
 }
 
 // NewScheduler creates a scheduler with the given lane and queue config.
@@ -48,6 +49,12 @@ func (s *Scheduler) SetTokenEstimateFunc(fn TokenEstimateFunc) {
 	s.tokenEstimateFn = fn
 }
 
+// SetSessionLockFunc sets the cross-node run lock acquired around each serial
+// session run. Must be called before any Schedule calls.
+func (s *Scheduler) SetSessionLockFunc(fn SessionLockFunc) {
+	s.sessionLockFn = fn
+}
+
 // MarkDraining signals that the gateway is shutting down.
 // New Schedule/ScheduleWithOpts calls will return ErrGatewayDraining immediately.
 // Active runs continue to completion.
@@ -106,6 +113,7 @@ func (s *Scheduler) getOrCreateSession(sessionKey, lane string) *SessionQueue {
 	if s.tokenEstimateFn != nil {
 		sq.tokenEstimateFn = s.tokenEstimateFn
 	}
+	sq.sessionLockFn = s.sessionLockFn
 	s.sessions[sessionKey] = sq
 
 	slog.Debug("session queue created", "session", sessionKey, "lane", lane)
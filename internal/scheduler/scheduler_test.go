@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/nextlevelbuilder/goclaw/internal/agent"
+	"github.com/nextlevelbuilder/goclaw/internal/cluster"
 )
 
 func TestLane_ConcurrencyLimit(t *testing.T) {
@@ -372,3 +373,52 @@ func TestScheduler_InterruptMode(t *testing.T) {
 		t.Error("second run timed out")
 	}
 }
+
+func TestScheduler_SessionLockAcrossSchedulers(t *testing.T) {
+	var active atomic.Int32
+	var maxActive atomic.Int32
+
+	runFn := func(_ context.Context, req agent.RunRequest) (*agent.RunResult, error) {
+		cur := active.Add(1)
+		for {
+			old := maxActive.Load()
+			if cur <= old || maxActive.CompareAndSwap(old, cur) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+		active.Add(-1)
+		return &agent.RunResult{Content: "ok", RunID: req.RunID}, nil
+	}
+
+	// Two schedulers stand in for two gateway replicas sharing one locker.
+	locker := cluster.NewLocalLocker()
+	cfg := QueueConfig{Mode: QueueModeQueue, Cap: 10, Drop: DropOld}
+	var outcomes []<-chan RunOutcome
+	for i := range 2 {
+		coord := cluster.NewCoordinator("node-"+string(rune('a'+i)), locker, nil)
+		sched := NewScheduler(DefaultLanes(), cfg, runFn)
+		sched.SetSessionLockFunc(coord.LockSession)
+		defer sched.Stop()
+		for j := range 2 {
+			outcomes = append(outcomes, sched.Schedule(context.Background(), "main", agent.RunRequest{
+				SessionKey: "agent:default:shared",
+				RunID:      "run-" + string(rune('a'+i)) + string(rune('0'+j)),
+			}))
+		}
+	}
+
+	for i, ch := range outcomes {
+		select {
+		case out := <-ch:
+			if out.Err != nil {
+				t.Errorf("run %d error: %v", i, out.Err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("run %d timed out", i)
+		}
+	}
+	if m := maxActive.Load(); m > 1 {
+		t.Errorf("shared session max active across schedulers = %d, want 1", m)
+	}
+}
@@ -721,6 +721,146 @@ func normalizeProviderNames(names []string) []string {
 	return out
 }
 
+// ProviderFailoverMember is one fallback entry in an agent's provider failover chain.
+type ProviderFailoverMember struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+}
+
+// ProviderFailoverConfig declares an ordered fallback chain tried after the
+// agent's primary provider on rate limits, 5xx and timeouts.
+type ProviderFailoverConfig struct {
+	Members         []ProviderFailoverMember `json:"members"`
+	CooldownSeconds int                      `json:"cooldown_seconds,omitempty"`
+}
+
+// ParseProviderFailover extracts provider_failover from other_config JSONB.
+// Returns nil when no fallback members are configured. Entries naming the
+// agent's own provider or repeating an earlier entry are dropped.
+func (a *AgentData) ParseProviderFailover() *ProviderFailoverConfig {
+	if len(a.OtherConfig) == 0 {
+		return nil
+	}
+	var cfg struct {
+		Failover *ProviderFailoverConfig `json:"provider_failover"`
+	}
+	if json.Unmarshal(a.OtherConfig, &cfg) != nil || cfg.Failover == nil {
+		return nil
+	}
+	seen := map[string]bool{a.Provider: true}
+	members := make([]ProviderFailoverMember, 0, len(cfg.Failover.Members))
+	for _, m := range cfg.Failover.Members {
+		m.Provider = strings.TrimSpace(m.Provider)
+		m.Model = strings.TrimSpace(m.Model)
+		if m.Provider == "" || seen[m.Provider] {
+			continue
+		}
+		seen[m.Provider] = true
+		members = append(members, m)
+	}
+	if len(members) == 0 {
+		return nil
+	}
+	cooldown := cfg.Failover.CooldownSeconds
+	if cooldown < 0 {
+		cooldown = 0
+	}
+	return &ProviderFailoverConfig{Members: members, CooldownSeconds: cooldown}
+}
+
+// ResponseCacheConfig enables the LLM response cache for an agent.
+// SimilarityThreshold > 0 also serves responses whose last user message is
+// semantically close (cosine similarity) to a cached one.
+type ResponseCacheConfig struct {
+	Enabled             bool    `json:"enabled"`
+	TTLSeconds          int     `json:"ttl_seconds,omitempty"`
+	SimilarityThreshold float64 `json:"similarity_threshold,omitempty"`
+}
+
+// ParseResponseCache extracts response_cache from other_config JSONB.
+// Returns nil unless the cache is enabled. Thresholds outside (0,1] disable
+// semantic matching.
+func (a *AgentData) ParseResponseCache() *ResponseCacheConfig {
+	if len(a.OtherConfig) == 0 {
+		return nil
+	}
+	var cfg struct {
+		Cache *ResponseCacheConfig `json:"response_cache"`
+	}
+	if json.Unmarshal(a.OtherConfig, &cfg) != nil || cfg.Cache == nil || !cfg.Cache.Enabled {
+		return nil
+	}
+	out := *cfg.Cache
+	if out.TTLSeconds < 0 {
+		out.TTLSeconds = 0
+	}
+	if out.SimilarityThreshold <= 0 || out.SimilarityThreshold > 1 {
+		out.SimilarityThreshold = 0
+	}
+	return &out
+}
+
+// Injection classifier modes and actions.
+const (
+	InjectionClassifierHeuristic = "heuristic" // local pattern scoring only
+	InjectionClassifierLLM       = "llm"       // small model via a registered provider, heuristics as fallback
+
+	InjectionActionLog   = "log"   // record the verdict only
+	InjectionActionWarn  = "warn"  // wrap suspicious content in untrusted-data delimiters
+	InjectionActionBlock = "block" // quarantine: replace suspicious content with a notice
+)
+
+// DefaultInjectionClassifierTools are the tools whose results carry untrusted
+// third-party content when no explicit list is configured.
+var DefaultInjectionClassifierTools = []string{"web_fetch", "web_search", "read_document", "browser", "mcp_*"}
+
+// InjectionClassifierConfig enables second-stage prompt-injection scoring of
+// untrusted tool results for an agent.
+type InjectionClassifierConfig struct {
+	Enabled   bool     `json:"enabled"`
+	Mode      string   `json:"mode,omitempty"`      // "heuristic" (default) or "llm"
+	Provider  string   `json:"provider,omitempty"`  // llm mode: provider name (default: agent's provider)
+	Model     string   `json:"model,omitempty"`     // llm mode: model (default: agent's model)
+	Tools     []string `json:"tools,omitempty"`     // tool names, globs or "group:xxx" (default: DefaultInjectionClassifierTools)
+	Threshold float64  `json:"threshold,omitempty"` // score in (0,1] at which content is suspicious (default 0.5)
+	Action    string   `json:"action,omitempty"`    // "log", "warn" (default) or "block"
+	MaxChars  int      `json:"max_chars,omitempty"` // llm mode: content sent to the classifier (default 8000)
+}
+
+// ParseInjectionClassifier extracts injection_classifier from other_config JSONB.
+// Returns nil unless enabled. Unknown modes and actions fall back to the
+// defaults so a typo never disables the classifier silently.
+func (a *AgentData) ParseInjectionClassifier() *InjectionClassifierConfig {
+	if len(a.OtherConfig) == 0 {
+		return nil
+	}
+	var cfg struct {
+		Classifier *InjectionClassifierConfig `json:"injection_classifier"`
+	}
+	if json.Unmarshal(a.OtherConfig, &cfg) != nil || cfg.Classifier == nil || !cfg.Classifier.Enabled {
+		return nil
+	}
+	out := *cfg.Classifier
+	if out.Mode != InjectionClassifierLLM {
+		out.Mode = InjectionClassifierHeuristic
+	}
+	switch out.Action {
+	case InjectionActionLog, InjectionActionWarn, InjectionActionBlock:
+	default:
+		out.Action = InjectionActionWarn
+	}
+	if out.Threshold <= 0 || out.Threshold > 1 {
+		out.Threshold = 0.5
+	}
+	if len(out.Tools) == 0 {
+		out.Tools = DefaultInjectionClassifierTools
+	}
+	if out.MaxChars <= 0 {
+		out.MaxChars = 8000
+	}
+	return &out
+}
+
 // ParseShellDenyGroups extracts shell_deny_groups from other_config JSONB.
 // Returns nil if not configured (all defaults apply).
 func (a *AgentData) ParseShellDenyGroups() map[string]bool {
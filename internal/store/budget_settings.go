@@ -0,0 +1,58 @@
+package store
+
+import "encoding/json"
+
+// BudgetSettings declares USD spend caps for a tenant (tenants.settings.budget)
+// or an agent (other_config.budget). Zero means unlimited.
+// The user_* caps apply to each channel user separately.
+type BudgetSettings struct {
+	DailyUSD       float64 `json:"daily_usd,omitempty"`
+	MonthlyUSD     float64 `json:"monthly_usd,omitempty"`
+	UserDailyUSD   float64 `json:"user_daily_usd,omitempty"`
+	UserMonthlyUSD float64 `json:"user_monthly_usd,omitempty"`
+
+	// SoftLimitPercent is the share of a cap at which a warning is sent
+	// (0 = default 80, negative = no warnings).
+	SoftLimitPercent int `json:"soft_limit_percent,omitempty"`
+
+	// NotifyChannel/NotifyChatID address the owner chat that receives
+	// soft-limit warnings. Empty = bus event only.
+	NotifyChannel string `json:"notify_channel,omitempty"`
+	NotifyChatID  string `json:"notify_chat_id,omitempty"`
+}
+
+// IsZero reports whether no cap is configured.
+func (b *BudgetSettings) IsZero() bool {
+	return b == nil || (b.DailyUSD <= 0 && b.MonthlyUSD <= 0 && b.UserDailyUSD <= 0 && b.UserMonthlyUSD <= 0)
+}
+
+// ParseBudgetSettings extracts the "budget" object from a settings JSONB
+// document. Returns nil if absent or no cap is configured.
+func ParseBudgetSettings(raw json.RawMessage) *BudgetSettings {
+	if len(raw) == 0 {
+		return nil
+	}
+	var doc struct {
+		Budget *BudgetSettings `json:"budget"`
+	}
+	if json.Unmarshal(raw, &doc) != nil || doc.Budget.IsZero() {
+		return nil
+	}
+	return doc.Budget
+}
+
+// ParseBudget returns the agent's budget from other_config.budget. The legacy
+// budget_monthly_cents column is used as the monthly cap when other_config
+// does not set one. Returns nil if no cap is configured.
+func (a *AgentData) ParseBudget() *BudgetSettings {
+	b := ParseBudgetSettings(a.OtherConfig)
+	if a.BudgetMonthlyCents != nil && *a.BudgetMonthlyCents > 0 {
+		if b == nil {
+			b = &BudgetSettings{}
+		}
+		if b.MonthlyUSD <= 0 {
+			b.MonthlyUSD = float64(*a.BudgetMonthlyCents) / 100
+		}
+	}
+	return b
+}
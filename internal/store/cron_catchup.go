@@ -0,0 +1,113 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/adhocore/gronx"
+)
+
+// Cron catch-up policies decide what happens to runs missed while the gateway was down.
+const (
+	CronCatchUpSkip    = "skip"     // drop missed runs and wait for the next scheduled one
+	CronCatchUpRunOnce = "run-once" // run once for all missed runs (default)
+	CronCatchUpRunAll  = "run-all"  // replay every missed run, capped at MaxCronCatchUpRuns
+)
+
+// MaxCronCatchUpRuns caps how many missed runs are counted (and replayed under
+// run-all) so a long outage on a minutely job cannot flood the agent.
+const MaxCronCatchUpRuns = 50
+
+// CronCatchUpGrace is how late a job may be on startup before it counts as
+// missed. Shorter delays are ordinary scheduler latency (or another replica
+// about to run the job) and are left to the scheduler.
+const CronCatchUpGrace = time.Minute
+
+// CronCatchUpPlan is the outcome of applying a job's catch-up policy on startup.
+type CronCatchUpPlan struct {
+	Policy  string     // effective policy ("" resolved to run-once)
+	Missed  int        // missed runs detected, capped at MaxCronCatchUpRuns
+	Pending int        // replays still owed after the overdue run fires (run-all)
+	NextRun *time.Time // replacement next_run_at (skip); nil with Disable unset keeps the overdue run
+	Disable bool       // skip left nothing to run: disable the job
+	Summary string     // human-readable decision for the run log
+}
+
+// ValidateCronCatchUp checks a catch-up policy name. Empty means run-once.
+func ValidateCronCatchUp(policy string) error {
+	switch policy {
+	case "", CronCatchUpSkip, CronCatchUpRunOnce, CronCatchUpRunAll:
+		return nil
+	default:
+		return fmt.Errorf("invalid catch-up policy: %s (want skip, run-once or run-all)", policy)
+	}
+}
+
+// CountMissedCronRuns returns how many scheduled runs fell in [nextRun, now],
+// capped at MaxCronCatchUpRuns. nextRun itself counts as the first missed run.
+// Cron expressions are walked in the job's timezone (falling back to defaultTZ)
+// so DST shifts are counted the same way ComputeNextRun schedules them.
+func CountMissedCronRuns(schedule *CronSchedule, nextRun, now time.Time, defaultTZ string) int {
+	if nextRun.After(now) {
+		return 0
+	}
+	switch schedule.Kind {
+	case "every":
+		if schedule.EveryMS == nil || *schedule.EveryMS <= 0 {
+			return 1
+		}
+		return int(min(now.Sub(nextRun).Milliseconds() / *schedule.EveryMS + 1, MaxCronCatchUpRuns))
+	case "cron":
+		tz := schedule.TZ
+		if tz == "" {
+			tz = defaultTZ
+		}
+		t := nextRun
+		if tz != "" {
+			if loc, err := time.LoadLocation(tz); err == nil {
+				t = t.In(loc)
+			}
+		}
+		missed := 1
+		for missed < MaxCronCatchUpRuns {
+			next, err := gronx.NextTickAfter(schedule.Expr, t, false)
+			if err != nil || next.After(now) {
+				break
+			}
+			missed++
+			t = next
+		}
+		return missed
+	default:
+		return 1
+	}
+}
+
+// PlanCronCatchUp decides how to resolve an enabled job whose next run passed
+// while the gateway was down. Callers persist the plan and log its summary.
+func PlanCronCatchUp(job *CronJob, now time.Time, defaultTZ string) CronCatchUpPlan {
+	plan := CronCatchUpPlan{Policy: job.CatchUp}
+	if plan.Policy == "" {
+		plan.Policy = CronCatchUpRunOnce
+	}
+	if job.State.NextRunAtMS == nil {
+		return plan
+	}
+	plan.Missed = CountMissedCronRuns(&job.Schedule, time.UnixMilli(*job.State.NextRunAtMS), now, defaultTZ)
+
+	switch plan.Policy {
+	case CronCatchUpSkip:
+		plan.Summary = fmt.Sprintf("skipped %d missed run(s)", plan.Missed)
+		plan.NextRun = ComputeNextRun(&job.Schedule, now, defaultTZ)
+		plan.Disable = plan.NextRun == nil
+	case CronCatchUpRunAll:
+		// The overdue next_run_at fires the first replay; the scheduler makes
+		// the rest due one after another. Replays interrupted by a previous
+		// restart are carried over.
+		plan.Pending = min(plan.Missed-1+job.State.CatchUpPending, MaxCronCatchUpRuns-1)
+		plan.Summary = fmt.Sprintf("replaying %d missed run(s)", plan.Pending+1)
+	default:
+		plan.Summary = fmt.Sprintf("running once for %d missed run(s)", plan.Missed)
+	}
+	return plan
+}
@@ -0,0 +1,85 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountMissedCronRuns(t *testing.T) {
+	now := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+
+	hourly := &CronSchedule{Kind: "every", EveryMS: new(int64(time.Hour / time.Millisecond))}
+	if got := CountMissedCronRuns(hourly, now.Add(-3*time.Hour), now, ""); got != 4 {
+		t.Fatalf("every: missed = %d, want 4", got)
+	}
+	minutely := &CronSchedule{Kind: "every", EveryMS: new(int64(time.Minute / time.Millisecond))}
+	if got := CountMissedCronRuns(minutely, now.Add(-24*time.Hour), now, ""); got != MaxCronCatchUpRuns {
+		t.Fatalf("every: missed = %d, want cap %d", got, MaxCronCatchUpRuns)
+	}
+
+	daily := &CronSchedule{Kind: "cron", Expr: "0 9 * * *", TZ: "UTC"}
+	first := time.Date(2026, time.January, 7, 9, 0, 0, 0, time.UTC)
+	if got := CountMissedCronRuns(daily, first, now, ""); got != 4 {
+		t.Fatalf("cron: missed = %d, want 4 (7th..10th)", got)
+	}
+	if got := CountMissedCronRuns(daily, now.Add(time.Hour), now, ""); got != 0 {
+		t.Fatalf("future next run: missed = %d, want 0", got)
+	}
+}
+
+func TestCountMissedCronRuns_UsesTimezoneAcrossDST(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	// 2026-03-08 is the US spring-forward day: 09:00 EST is 14:00 UTC,
+	// 09:00 EDT is 13:00 UTC.
+	daily := &CronSchedule{Kind: "cron", Expr: "0 9 * * *"}
+	first := time.Date(2026, time.March, 7, 14, 0, 0, 0, time.UTC)
+	now := time.Date(2026, time.March, 8, 13, 30, 0, 0, time.UTC)
+
+	if got := CountMissedCronRuns(daily, first, now, "America/New_York"); got != 2 {
+		t.Fatalf("missed = %d, want 2 with default timezone", got)
+	}
+	if got := CountMissedCronRuns(daily, first, now.Add(-time.Hour), "America/New_York"); got != 1 {
+		t.Fatalf("missed = %d, want 1 before 09:00 EDT", got)
+	}
+}
+
+func TestPlanCronCatchUp(t *testing.T) {
+	now := time.Date(2026, time.January, 10, 12, 0, 0, 0, time.UTC)
+	hour := int64(time.Hour / time.Millisecond)
+	overdue := now.Add(-2*time.Hour - time.Second).UnixMilli()
+	every := CronSchedule{Kind: "every", EveryMS: &hour}
+
+	runOnce := PlanCronCatchUp(&CronJob{Schedule: every, State: CronJobState{NextRunAtMS: &overdue}}, now, "")
+	if runOnce.Policy != CronCatchUpRunOnce || runOnce.Missed != 3 || runOnce.Pending != 0 || runOnce.NextRun != nil || runOnce.Disable {
+		t.Fatalf("run-once plan = %+v", runOnce)
+	}
+
+	runAll := PlanCronCatchUp(&CronJob{CatchUp: CronCatchUpRunAll, Schedule: every, State: CronJobState{NextRunAtMS: &overdue}}, now, "")
+	if runAll.Missed != 3 || runAll.Pending != 2 || runAll.NextRun != nil {
+		t.Fatalf("run-all plan = %+v", runAll)
+	}
+
+	skip := PlanCronCatchUp(&CronJob{CatchUp: CronCatchUpSkip, Schedule: every, State: CronJobState{NextRunAtMS: &overdue}}, now, "")
+	if skip.NextRun == nil || !skip.NextRun.Equal(now.Add(time.Hour)) || skip.Disable {
+		t.Fatalf("skip plan = %+v, want next run in an hour", skip)
+	}
+
+	past := now.Add(-time.Hour).UnixMilli()
+	at := CronSchedule{Kind: "at", AtMS: &past}
+	skipAt := PlanCronCatchUp(&CronJob{CatchUp: CronCatchUpSkip, Schedule: at, State: CronJobState{NextRunAtMS: &past}}, now, "")
+	if !skipAt.Disable || skipAt.Missed != 1 {
+		t.Fatalf("skip at plan = %+v, want disable", skipAt)
+	}
+}
+
+func TestValidateCronSchedule_AtTimezone(t *testing.T) {
+	at := time.Now().Add(time.Hour).UnixMilli()
+	if err := ValidateCronSchedule(&CronSchedule{Kind: "at", AtMS: &at, TZ: "Invalid/Zone"}); err == nil {
+		t.Fatal("expected invalid timezone error for at schedule")
+	}
+	if err := ValidateCronSchedule(&CronSchedule{Kind: "at", AtMS: &at, TZ: "Europe/Berlin"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
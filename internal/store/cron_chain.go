@@ -0,0 +1,41 @@
+package store
+
+import "fmt"
+
+// MaxCronChainDepth bounds how many "after" links a chain may have, so a
+// misconfigured chain cannot fan a single run out indefinitely.
+const MaxCronChainDepth = 32
+
+// ValidateCronChain checks that making jobID run after upstream keeps the
+// chain acyclic and within MaxCronChainDepth. lookup returns the upstream
+// job's own "after" target ("" when it is not chained) and whether it exists
+// in the caller's tenant. jobID is empty for jobs that do not exist yet.
+func ValidateCronChain(jobID, upstream string, lookup func(id string) (after string, found bool, err error)) error {
+	seen := map[string]bool{}
+	for id, depth := upstream, 0; id != ""; depth++ {
+		if id == jobID {
+			return fmt.Errorf("cron chain cycle: job %s would run after itself", jobID)
+		}
+		if seen[id] || depth >= MaxCronChainDepth {
+			return fmt.Errorf("cron chain too deep (max %d)", MaxCronChainDepth)
+		}
+		seen[id] = true
+		after, found, err := lookup(id)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("upstream cron job %s not found", id)
+		}
+		id = after
+	}
+	return nil
+}
+
+// CronChainedMessage appends a chained job's upstream output to its message.
+func CronChainedMessage(message, input string) string {
+	if input == "" {
+		return message
+	}
+	return message + "\n\n--- Output from previous step ---\n" + input
+}
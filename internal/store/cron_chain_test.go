@@ -0,0 +1,39 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCronChain(t *testing.T) {
+	// c runs after b, b runs after a.
+	links := map[string]string{"a": "", "b": "a", "c": "b"}
+	lookup := func(id string) (string, bool, error) {
+		after, ok := links[id]
+		return after, ok, nil
+	}
+
+	if err := ValidateCronChain("d", "c", lookup); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ValidateCronChain("a", "c", lookup); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+	if err := ValidateCronChain("d", "missing", lookup); err == nil {
+		t.Fatal("expected missing upstream error")
+	}
+
+	deep := func(id string) (string, bool, error) { return id + "x", true, nil }
+	if err := ValidateCronChain("d", "a", deep); err == nil || !strings.Contains(err.Error(), "too deep") {
+		t.Fatalf("expected depth error, got %v", err)
+	}
+}
+
+func TestCronChainedMessage(t *testing.T) {
+	if got := CronChainedMessage("summarize", ""); got != "summarize" {
+		t.Errorf("no input: %q", got)
+	}
+	if got := CronChainedMessage("summarize", "3 new"); !strings.HasSuffix(got, "previous step ---\n3 new") || !strings.HasPrefix(got, "summarize\n\n") {
+		t.Errorf("with input: %q", got)
+	}
+}
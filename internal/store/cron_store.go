@@ -9,6 +9,8 @@ import (
 
 	"github.com/adhocore/gronx"
 	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/cron"
 )
 
 var (
@@ -35,30 +37,49 @@ type CronJob struct {
 	DeliverChannel string       `json:"deliverChannel"`
 	DeliverTo      string       `json:"deliverTo"`
 	WakeHeartbeat  bool         `json:"wakeHeartbeat"`
+	CatchUp        string       `json:"catchUp,omitempty"`   // CronCatchUp* policy; "" = run-once
+	DeliverIf      string       `json:"deliverIf,omitempty"` // condition on the run output gating delivery (cron.EvalCondition)
 }
 
 // CronSchedule defines when a job should run.
 type CronSchedule struct {
-	Kind    string `json:"kind"` // "at", "every", "cron"
+	Kind    string `json:"kind"` // "at", "every", "cron", "after"
 	AtMS    *int64 `json:"atMs,omitempty"`
 	EveryMS *int64 `json:"everyMs,omitempty"`
 	Expr    string `json:"expr,omitempty"`
-	TZ      string `json:"tz,omitempty"`
+	TZ      string `json:"tz,omitempty"`    // IANA timezone for "cron" (empty = gateway default)
+	After   string `json:"after,omitempty"` // upstream job ID (for "after": run when it succeeds)
 }
 
 // CronPayload describes what a job does when triggered.
 type CronPayload struct {
-	Kind    string `json:"kind"`
-	Message string `json:"message"`
-	Command string `json:"command,omitempty"`
+	Kind       string            `json:"kind"` // CronPayloadKind*
+	Message    string            `json:"message"`
+	Command    string            `json:"command,omitempty"`
+	WorkflowID string            `json:"workflowId,omitempty"` // team workflow started by kind "workflow"
+	URL        string            `json:"url,omitempty"`        // POST target for kind "webhook"
+	Headers    map[string]string `json:"headers,omitempty"`    // extra request headers for kind "webhook"
 }
 
+// Cron payload kinds.
+const (
+	CronPayloadKindAgentTurn = "agent_turn" // run the job's agent with Message
+	CronPayloadKindWorkflow  = "workflow"   // start WorkflowID with input {"message": Message}
+	CronPayloadKindWebhook   = "webhook"    // POST Message (and chained input) as JSON to URL
+)
+
 // CronJobState tracks runtime state for a job.
 type CronJobState struct {
 	NextRunAtMS *int64 `json:"nextRunAtMs,omitempty"`
 	LastRunAtMS *int64 `json:"lastRunAtMs,omitempty"`
 	LastStatus  string `json:"lastStatus,omitempty"`
 	LastError   string `json:"lastError,omitempty"`
+	// CatchUpPending counts missed runs still to replay under the run-all policy.
+	CatchUpPending int `json:"catchUpPending,omitempty"`
+	// Input is the upstream job's output for a pending chained ("after") run.
+	Input string `json:"input,omitempty"`
+	// LastOutput is the previous successful run's output, used by the "changed" condition.
+	LastOutput string `json:"lastOutput,omitempty"`
 }
 
 // CronRunLogEntry records a job execution.
@@ -71,6 +92,8 @@ type CronRunLogEntry struct {
 	DurationMS   int64  `json:"durationMs,omitempty"`
 	InputTokens  int    `json:"inputTokens,omitempty"`
 	OutputTokens int    `json:"outputTokens,omitempty"`
+	CatchUp      string `json:"catchUp,omitempty"` // policy applied to missed runs (status "catchup")
+	Missed       int    `json:"missed,omitempty"`  // missed runs detected on startup
 }
 
 // CronJobResult is the output of a cron job handler execution.
@@ -94,6 +117,10 @@ type CronJobPatch struct {
 	DeliverChannel *string       `json:"deliverChannel,omitempty"`
 	DeliverTo      *string       `json:"deliverTo,omitempty"`
 	WakeHeartbeat  *bool         `json:"wakeHeartbeat,omitempty"`
+	WorkflowID     *string       `json:"workflowId,omitempty"` // "" turns the job back into an agent turn
+	CatchUp        *string       `json:"catchUp,omitempty"`
+	DeliverIf      *string       `json:"deliverIf,omitempty"`
+	WebhookURL     *string       `json:"webhookUrl,omitempty"` // non-empty switches the payload to "webhook"; "" back to an agent turn
 }
 
 // CronEvent represents a job lifecycle event sent to subscribers.
@@ -163,6 +190,9 @@ func ComputeNextRun(schedule *CronSchedule, now time.Time, defaultTZ string) *ti
 			return &t
 		}
 		return nil
+	case "after":
+		// Triggered by the upstream job, never by the clock.
+		return nil
 	case "cron":
 		if schedule.Expr == "" {
 			return nil
@@ -190,7 +220,7 @@ func ComputeNextRun(schedule *CronSchedule, now time.Time, defaultTZ string) *ti
 
 // NextRunForSchedule resolves the persisted next_run_at for a given schedule state.
 func NextRunForSchedule(schedule *CronSchedule, enabled bool, now time.Time, defaultTZ string) (*time.Time, error) {
-	if !enabled {
+	if !enabled || schedule.Kind == "after" {
 		return nil, nil
 	}
 
@@ -258,6 +288,12 @@ func MergeCronSchedule(current CronSchedule, patch *CronSchedule) CronSchedule {
 		} else if current.Kind == newKind {
 			merged.AtMS = current.AtMS
 		}
+	case "after":
+		if patch.After != "" {
+			merged.After = patch.After
+		} else if current.Kind == newKind {
+			merged.After = current.After
+		}
 	}
 
 	return merged
@@ -273,11 +309,6 @@ func ValidateCronSchedule(schedule *CronSchedule) error {
 		if !gronx.New().IsValid(schedule.Expr) {
 			return fmt.Errorf("invalid cron expression: %s", schedule.Expr)
 		}
-		if schedule.TZ != "" {
-			if _, err := time.LoadLocation(schedule.TZ); err != nil {
-				return fmt.Errorf("invalid timezone: %s", schedule.TZ)
-			}
-		}
 	case "every":
 		if schedule.EveryMS == nil || *schedule.EveryMS <= 0 {
 			return fmt.Errorf("every schedule requires positive everyMs")
@@ -286,9 +317,37 @@ func ValidateCronSchedule(schedule *CronSchedule) error {
 		if schedule.AtMS == nil {
 			return fmt.Errorf("at schedule requires atMs")
 		}
+	case "after":
+		if _, err := uuid.Parse(schedule.After); err != nil {
+			return fmt.Errorf("after schedule requires upstream job id")
+		}
 	default:
 		return fmt.Errorf("invalid schedule kind: %s", schedule.Kind)
 	}
+	if schedule.TZ != "" && (schedule.Kind == "cron" || schedule.Kind == "at") {
+		if _, err := time.LoadLocation(schedule.TZ); err != nil {
+			return fmt.Errorf("invalid timezone: %s", schedule.TZ)
+		}
+	}
+	return nil
+}
+
+// ApplyCronWebhookURL applies a webhookUrl patch to a payload: a URL turns
+// the job into a webhook POST, "" turns a webhook job back into an agent turn.
+func ApplyCronWebhookURL(payload *CronPayload, rawURL string) error {
+	if rawURL == "" {
+		payload.URL = ""
+		if payload.Kind == CronPayloadKindWebhook {
+			payload.Kind = CronPayloadKindAgentTurn
+		}
+		return nil
+	}
+	if err := cron.ValidateWebhookURL(rawURL); err != nil {
+		return err
+	}
+	payload.Kind = CronPayloadKindWebhook
+	payload.URL = rawURL
+	payload.WorkflowID = ""
 	return nil
 }
 
@@ -304,6 +363,7 @@ func ApplyCronScheduleUpdates(updates map[string]any, schedule CronSchedule) {
 		updates["timezone"] = nil
 	}
 
+	updates["after_job_id"] = nil
 	switch schedule.Kind {
 	case "cron":
 		updates["cron_expression"] = schedule.Expr
@@ -318,6 +378,11 @@ func ApplyCronScheduleUpdates(updates map[string]any, schedule CronSchedule) {
 		updates["cron_expression"] = nil
 		updates["interval_ms"] = nil
 		updates["run_at"] = runAt
+	case "after":
+		updates["cron_expression"] = nil
+		updates["interval_ms"] = nil
+		updates["run_at"] = nil
+		updates["after_job_id"] = uuid.MustParse(schedule.After).String()
 	}
 }
 
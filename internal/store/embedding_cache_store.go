@@ -0,0 +1,19 @@
+package store
+
+import "context"
+
+// EmbeddingCacheStore is the content-hash embedding cache shared by every
+// store that embeds text (memory, skills, team tasks, knowledge graph,
+// knowledge bases). It satisfies memory.EmbeddingCache.
+type EmbeddingCacheStore interface {
+	// LookupEmbeddings returns cached vectors keyed by content hash; missing
+	// hashes are absent from the map.
+	LookupEmbeddings(ctx context.Context, hashes []string, provider, model string) (map[string][]float32, error)
+	// WriteEmbeddings upserts vectors keyed by content hash.
+	WriteEmbeddings(ctx context.Context, entries map[string][]float32, provider, model string) error
+
+	// ClearEmbeddings drops the stored vectors of every embedded table so the
+	// backfills regenerate them with the current provider. Cache rows are kept:
+	// they are keyed by provider and model. Returns the number of rows cleared.
+	ClearEmbeddings(ctx context.Context) (int64, error)
+}
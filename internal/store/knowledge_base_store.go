@@ -0,0 +1,155 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/docextract"
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
+)
+
+var (
+	ErrKBCollectionNotFound = errors.New("knowledge base collection not found")
+	ErrKBDocumentNotFound   = errors.New("knowledge base document not found")
+)
+
+// Knowledge base document status constants.
+const (
+	KBDocStatusPending  = "pending"  // stored, not indexed yet
+	KBDocStatusIndexing = "indexing" // chunking/embedding in progress
+	KBDocStatusReady    = "ready"
+	KBDocStatusFailed   = "failed"
+)
+
+// KBCollectionData is a named knowledge base: a set of documents shared by
+// all agents it is attached to. Collections belong to a tenant.
+type KBCollectionData struct {
+	BaseModel
+	TenantID    uuid.UUID `json:"tenant_id,omitempty"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	CreatedBy   string    `json:"created_by"`
+
+	// Joined
+	DocumentCount int         `json:"document_count"`
+	AgentIDs      []uuid.UUID `json:"agent_ids"`
+}
+
+// KBDocumentData is one uploaded file of a collection. Content holds the text
+// extracted at upload so the document can be re-chunked and re-embedded
+// without the original file.
+type KBDocumentData struct {
+	BaseModel
+	CollectionID uuid.UUID `json:"collection_id"`
+	TenantID     uuid.UUID `json:"tenant_id,omitempty"`
+	FileName     string    `json:"file_name"`
+	MimeType     string    `json:"mime_type"`
+	Hash         string    `json:"hash"`       // hash of Content
+	SizeBytes    int64     `json:"size_bytes"` // original file size
+	Pages        int       `json:"pages"`      // pages/slides/sheets; 0 when not paged
+	Content      string    `json:"-"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	ChunkCount   int       `json:"chunk_count"`
+	CreatedBy    string    `json:"created_by"`
+}
+
+// Chunking parameters for knowledge base documents (same as memory defaults).
+const (
+	KBChunkLen     = 1000
+	KBChunkOverlap = 200
+)
+
+// KBChunk is one indexed passage of a knowledge base document.
+type KBChunk struct {
+	Page      int // 1-based PDF page or PPTX slide; 0 when not paged
+	StartLine int // lines are relative to the page
+	EndLine   int
+	Text      string
+	Hash      string
+}
+
+// ChunkKBContent splits extracted document text into pages and chunks each
+// page with memory.ChunkText, so every chunk can be cited with its page.
+func ChunkKBContent(content string) []KBChunk {
+	var chunks []KBChunk
+	for _, p := range docextract.SplitPages(content) {
+		for _, c := range memory.ChunkText(p.Text, KBChunkLen, KBChunkOverlap) {
+			chunks = append(chunks, KBChunk{
+				Page:      p.Number,
+				StartLine: c.StartLine,
+				EndLine:   c.EndLine,
+				Text:      c.Text,
+				Hash:      memory.ContentHash(c.Text),
+			})
+		}
+	}
+	return chunks
+}
+
+// KBMaxSearchResults caps KBSearchOptions.MaxResults.
+const KBMaxSearchResults = 50
+
+// KBSearchOptions configures a knowledge base search.
+type KBSearchOptions struct {
+	// CollectionIDs restricts the search. Empty = every collection attached
+	// to the agent. With a nil agent ID only CollectionIDs are searched.
+	CollectionIDs []uuid.UUID
+	MaxResults    int // 0 = store default; clamped to KBMaxSearchResults
+	MinScore      float64
+}
+
+// KBSearchResult is one passage returned by a knowledge base search, with
+// enough source information to cite it.
+type KBSearchResult struct {
+	CollectionID   uuid.UUID `json:"collection_id"`
+	CollectionName string    `json:"collection_name"`
+	DocumentID     uuid.UUID `json:"document_id"`
+	FileName       string    `json:"file_name"`
+	Page           int       `json:"page,omitempty"` // 0 when the document is not paged
+	StartLine      int       `json:"start_line"`
+	EndLine        int       `json:"end_line"`
+	Score          float64   `json:"score"`
+	Snippet        string    `json:"snippet"`
+}
+
+// KnowledgeBaseStore manages knowledge base collections, their documents and
+// chunk search. Chunking and embedding happen in IndexDocument, like MemoryStore.
+type KnowledgeBaseStore interface {
+	CreateCollection(ctx context.Context, c *KBCollectionData) error
+	GetCollection(ctx context.Context, id uuid.UUID) (*KBCollectionData, error)
+	GetCollectionByName(ctx context.Context, name string) (*KBCollectionData, error)
+	ListCollections(ctx context.Context) ([]KBCollectionData, error)
+	// UpdateCollection writes name and description.
+	UpdateCollection(ctx context.Context, c *KBCollectionData) error
+	DeleteCollection(ctx context.Context, id uuid.UUID) error
+
+	// AttachAgent makes a collection searchable by an agent (idempotent).
+	AttachAgent(ctx context.Context, collectionID, agentID uuid.UUID) error
+	DetachAgent(ctx context.Context, collectionID, agentID uuid.UUID) error
+	// ListAgentCollections returns the collections attached to an agent.
+	ListAgentCollections(ctx context.Context, agentID uuid.UUID) ([]KBCollectionData, error)
+
+	// PutDocument inserts a document, or replaces the one with the same file
+	// name in the collection (doc.ID is set to the existing row's ID). Status
+	// is reset to pending; call IndexDocument to rebuild its chunks.
+	PutDocument(ctx context.Context, doc *KBDocumentData) error
+	GetDocument(ctx context.Context, id uuid.UUID) (*KBDocumentData, error)
+	GetDocumentByName(ctx context.Context, collectionID uuid.UUID, fileName string) (*KBDocumentData, error)
+	ListDocuments(ctx context.Context, collectionID uuid.UUID) ([]KBDocumentData, error)
+	DeleteDocument(ctx context.Context, collectionID, id uuid.UUID) error
+	// MarkDocumentsPending resets the status of every document in a collection
+	// to pending, before they are re-indexed in the background.
+	MarkDocumentsPending(ctx context.Context, collectionID uuid.UUID) error
+	// IndexDocument replaces a document's chunks: the content is split by page,
+	// chunked and embedded. Status ends as ready or failed.
+	IndexDocument(ctx context.Context, id uuid.UUID) error
+
+	// Search runs a hybrid text + vector search over the chunks of the
+	// collections attached to agentID.
+	Search(ctx context.Context, query string, agentID uuid.UUID, opts KBSearchOptions) ([]KBSearchResult, error)
+
+	SetEmbeddingProvider(provider EmbeddingProvider)
+}
@@ -70,6 +70,7 @@ func (s *PGCronStore) Start() error {
 	s.stop = make(chan struct{})
 	s.running = true
 	s.recomputeStaleJobs()
+	s.applyCatchUp()
 	go s.runLoop()
 	slog.Info("pg cron service started")
 	return nil
@@ -22,9 +22,20 @@ func (s *PGCronStore) AddJob(ctx context.Context, name string, schedule store.Cr
 			return nil, fmt.Errorf("invalid timezone: %s", schedule.TZ)
 		}
 	}
+	var afterJobID *uuid.UUID
+	if schedule.Kind == "after" {
+		if err := store.ValidateCronSchedule(&schedule); err != nil {
+			return nil, err
+		}
+		if err := store.ValidateCronChain("", schedule.After, s.chainLookup(ctx, s.db)); err != nil {
+			return nil, err
+		}
+		aid := uuid.MustParse(schedule.After)
+		afterJobID = &aid
+	}
 
 	payload := store.CronPayload{
-		Kind: "agent_turn", Message: message,
+		Kind: store.CronPayloadKindAgentTurn, Message: message,
 	}
 	payloadJSON, _ := json.Marshal(payload)
 
@@ -68,10 +79,10 @@ func (s *PGCronStore) AddJob(ctx context.Context, name string, schedule store.Cr
 
 	_, err := s.db.ExecContext(ctx,
 		`INSERT INTO cron_jobs (id, tenant_id, agent_id, user_id, name, enabled, schedule_kind, cron_expression, run_at, timezone,
-		 interval_ms, payload, delete_after_run, deliver, deliver_channel, deliver_to, wake_heartbeat, next_run_at, created_at, updated_at)
-		 VALUES ($1, $2, $3, $4, $5, true, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)`,
+		 interval_ms, payload, delete_after_run, deliver, deliver_channel, deliver_to, wake_heartbeat, next_run_at, created_at, updated_at, after_job_id)
+		 VALUES ($1, $2, $3, $4, $5, true, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`,
 		id, tenantIDForInsert(ctx), agentUUID, userIDPtr, name, scheduleKind, cronExpr, runAt, tz,
-		intervalMS, payloadJSON, deleteAfterRun, deliver, channel, to, false, nextRun, now, now,
+		intervalMS, payloadJSON, deleteAfterRun, deliver, channel, to, false, nextRun, now, now, afterJobID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("create cron job: %w", err)
@@ -96,10 +107,7 @@ func (s *PGCronStore) GetJob(ctx context.Context, jobID string) (*store.CronJob,
 }
 
 func (s *PGCronStore) ListJobs(ctx context.Context, includeDisabled bool, agentID, userID string) []store.CronJob {
-	q := `SELECT id, tenant_id, agent_id, user_id, name, enabled, schedule_kind, cron_expression, run_at, timezone,
-		 interval_ms, payload, delete_after_run, stateless, deliver, deliver_channel, deliver_to, wake_heartbeat,
-		 next_run_at, last_run_at, last_status, last_error,
-		 created_at, updated_at FROM cron_jobs WHERE 1=1`
+	q := `SELECT ` + cronJobColumns + ` FROM cron_jobs WHERE 1=1`
 
 	var args []any
 	argIdx := 1
@@ -203,11 +211,16 @@ func (s *PGCronStore) EnableJob(ctx context.Context, jobID string, enabled bool)
 		return err
 	}
 
-	if err := execCronJobUpdateTx(ctx, tx, id, map[string]any{
+	updates := map[string]any{
 		"enabled":     enabled,
 		"next_run_at": nextRun,
 		"updated_at":  now,
-	}); err != nil {
+	}
+	if !enabled {
+		// Disabling drops any run-all replays still owed.
+		updates["catch_up_pending"] = 0
+	}
+	if err := execCronJobUpdateTx(ctx, tx, id, updates); err != nil {
 		return err
 	}
 
@@ -65,7 +65,7 @@ func (s *PGCronStore) GetRunLog(ctx context.Context, jobID string, limit, offset
 		offset = 0
 	}
 
-	const cols = "r.job_id, r.status, r.error, r.summary, r.ran_at, COALESCE(r.duration_ms, 0), COALESCE(r.input_tokens, 0), COALESCE(r.output_tokens, 0)"
+	const cols = "r.job_id, r.status, r.error, r.summary, r.ran_at, COALESCE(r.duration_ms, 0), COALESCE(r.input_tokens, 0), COALESCE(r.output_tokens, 0), r.catch_up, COALESCE(r.missed, 0)"
 
 	// Build tenant-aware WHERE clause via JOIN with cron_jobs.
 	var tenantJoin, tenantWhere string
@@ -119,8 +119,9 @@ func (s *PGCronStore) GetRunLog(ctx context.Context, jobID string, limit, offset
 		var errStr, summary *string
 		var ranAt time.Time
 		var durationMS int64
-		var inputTokens, outputTokens int
-		if err := rows.Scan(&jobUUID, &status, &errStr, &summary, &ranAt, &durationMS, &inputTokens, &outputTokens); err != nil {
+		var inputTokens, outputTokens, missed int
+		var catchUp *string
+		if err := rows.Scan(&jobUUID, &status, &errStr, &summary, &ranAt, &durationMS, &inputTokens, &outputTokens, &catchUp, &missed); err != nil {
 			continue
 		}
 		result = append(result, store.CronRunLogEntry{
@@ -132,6 +133,8 @@ func (s *PGCronStore) GetRunLog(ctx context.Context, jobID string, limit, offset
 			DurationMS:   durationMS,
 			InputTokens:  inputTokens,
 			OutputTokens: outputTokens,
+			CatchUp:      derefStr(catchUp),
+			Missed:       missed,
 		})
 	}
 	return result, total
@@ -12,12 +12,15 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
-// scanJob fetches a single cron job by ID with tenant filtering.
-func (s *PGCronStore) scanJob(ctx context.Context, id uuid.UUID) (*store.CronJob, error) {
-	q := `SELECT id, tenant_id, agent_id, user_id, name, enabled, schedule_kind, cron_expression, run_at, timezone,
+// cronJobColumns is the cron_jobs select list read by scanCronRow.
+const cronJobColumns = `id, tenant_id, agent_id, user_id, name, enabled, schedule_kind, cron_expression, run_at, timezone,
 		 interval_ms, payload, delete_after_run, stateless, deliver, deliver_channel, deliver_to, wake_heartbeat,
 		 next_run_at, last_run_at, last_status, last_error,
-		 created_at, updated_at FROM cron_jobs WHERE id = $1`
+		 created_at, updated_at, catch_up, catch_up_pending, after_job_id, deliver_if, chain_input, last_output`
+
+// scanJob fetches a single cron job by ID with tenant filtering.
+func (s *PGCronStore) scanJob(ctx context.Context, id uuid.UUID) (*store.CronJob, error) {
+	q := `SELECT ` + cronJobColumns + ` FROM cron_jobs WHERE id = $1`
 	args := []any{id}
 
 	if !store.IsCrossTenant(ctx) {
@@ -53,11 +56,16 @@ func scanCronRow(row cronRowScanner) (*store.CronJob, error) {
 	var intervalMS *int64
 	var payloadJSON []byte
 	var createdAt, updatedAt time.Time
+	var catchUp string
+	var catchUpPending int
+	var afterJobID *uuid.UUID
+	var deliverIf string
+	var chainInput, lastOutput *string
 
 	err := row.Scan(&id, &tenantID, &agentID, &userID, &name, &enabled, &scheduleKind, &cronExpr, &runAt, &tz,
 		&intervalMS, &payloadJSON, &deleteAfterRun, &stateless, &deliver, &deliverChannel, &deliverTo, &wakeHeartbeat,
 		&nextRunAt, &lastRunAt, &lastStatus, &lastError,
-		&createdAt, &updatedAt)
+		&createdAt, &updatedAt, &catchUp, &catchUpPending, &afterJobID, &deliverIf, &chainInput, &lastOutput)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +94,10 @@ func scanCronRow(row cronRowScanner) (*store.CronJob, error) {
 		DeliverChannel: deliverChannel,
 		DeliverTo:      deliverTo,
 		WakeHeartbeat:  wakeHeartbeat,
+		CatchUp:        catchUp,
+		DeliverIf:      deliverIf,
 	}
+	job.State.CatchUpPending = catchUpPending
 
 	if agentID != nil {
 		job.AgentID = agentID.String()
@@ -107,6 +118,15 @@ func scanCronRow(row cronRowScanner) (*store.CronJob, error) {
 	if tz != nil {
 		job.Schedule.TZ = *tz
 	}
+	if afterJobID != nil {
+		job.Schedule.After = afterJobID.String()
+	}
+	if chainInput != nil {
+		job.State.Input = *chainInput
+	}
+	if lastOutput != nil {
+		job.State.LastOutput = *lastOutput
+	}
 	if nextRunAt != nil {
 		ms := nextRunAt.UnixMilli()
 		job.State.NextRunAtMS = &ms
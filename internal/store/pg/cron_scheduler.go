@@ -36,10 +36,7 @@ func (s *PGCronStore) GetDueJobs(now time.Time) []store.CronJob {
 // refreshJobCache reloads all enabled jobs from DB. Must be called with mu held.
 func (s *PGCronStore) refreshJobCache() {
 	rows, err := s.db.QueryContext(s.baseCtx,
-		`SELECT id, tenant_id, agent_id, user_id, name, enabled, schedule_kind, cron_expression, run_at, timezone,
-		 interval_ms, payload, delete_after_run, stateless, deliver, deliver_channel, deliver_to, wake_heartbeat,
-		 next_run_at, last_run_at, last_status, last_error,
-		 created_at, updated_at FROM cron_jobs WHERE enabled = true`)
+		`SELECT `+cronJobColumns+` FROM cron_jobs WHERE enabled = true`)
 	if err != nil {
 		return
 	}
@@ -132,6 +129,74 @@ func (s *PGCronStore) recomputeStaleJobs() {
 	}
 }
 
+// applyCatchUp resolves enabled jobs whose next_run_at passed while the
+// gateway was down, according to each job's catch-up policy, and records the
+// decision in cron_run_logs with status 'catchup'. Each update re-checks that
+// the job is still overdue and unclaimed, so replicas starting together apply
+// a policy once.
+func (s *PGCronStore) applyCatchUp() {
+	now := time.Now()
+	cutoff := now.Add(-store.CronCatchUpGrace)
+	rows, err := s.db.QueryContext(s.baseCtx,
+		`SELECT `+cronJobColumns+` FROM cron_jobs WHERE enabled = true AND next_run_at < $1 AND schedule_kind <> 'after'`, cutoff)
+	if err != nil {
+		slog.Warn("cron: failed to query missed jobs", "error", err)
+		return
+	}
+	var missed []store.CronJob
+	for rows.Next() {
+		job, err := scanCronRow(rows)
+		if err != nil {
+			continue
+		}
+		missed = append(missed, *job)
+	}
+	rows.Close()
+
+	for i := range missed {
+		job := &missed[i]
+		id, parseErr := uuid.Parse(job.ID)
+		if parseErr != nil {
+			continue
+		}
+		plan := store.PlanCronCatchUp(job, now, s.defaultTZ)
+
+		var res sql.Result
+		switch {
+		case plan.Disable:
+			res, err = s.db.ExecContext(s.baseCtx,
+				`UPDATE cron_jobs SET enabled = false, next_run_at = NULL, catch_up_pending = 0, updated_at = $1
+				 WHERE id = $2 AND enabled = true AND next_run_at < $3`, now, id, cutoff)
+		case plan.NextRun != nil:
+			res, err = s.db.ExecContext(s.baseCtx,
+				`UPDATE cron_jobs SET next_run_at = $1, catch_up_pending = 0, updated_at = $2
+				 WHERE id = $3 AND enabled = true AND next_run_at < $4`, *plan.NextRun, now, id, cutoff)
+		default:
+			res, err = s.db.ExecContext(s.baseCtx,
+				`UPDATE cron_jobs SET catch_up_pending = $1, updated_at = $2
+				 WHERE id = $3 AND enabled = true AND next_run_at < $4`, plan.Pending, now, id, cutoff)
+		}
+		if err != nil {
+			slog.Warn("cron: failed to apply catch-up policy", "id", job.ID, "error", err)
+			continue
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			continue
+		}
+
+		var agentUUID *uuid.UUID
+		if aid, aidErr := uuid.Parse(job.AgentID); aidErr == nil {
+			agentUUID = &aid
+		}
+		s.db.ExecContext(s.baseCtx,
+			`INSERT INTO cron_run_logs (id, job_id, agent_id, status, summary, catch_up, missed, ran_at)
+			 VALUES ($1, $2, $3, 'catchup', $4, $5, $6, $7)`,
+			uuid.Must(uuid.NewV7()), id, agentUUID, plan.Summary, plan.Policy, plan.Missed, now,
+		)
+		slog.Info("cron catch-up", "id", job.ID, "policy", plan.Policy, "missed", plan.Missed)
+	}
+}
+
 func (s *PGCronStore) runLoop() {
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
@@ -261,8 +326,29 @@ func (s *PGCronStore) executeOneJob(job store.CronJob, handler func(job *store.C
 		)
 	}
 
-	// Recompute next run or delete
-	if job.DeleteAfterRun {
+	// Trigger jobs chained after this one, passing the output as their input.
+	// Runs before the delete below, which would unlink them.
+	var lastOutput any
+	if err == nil {
+		output := cron.TruncateOutput(resultStr)
+		lastOutput = output
+		if id, parseErr := uuid.Parse(job.ID); parseErr == nil {
+			if res, trigErr := s.db.ExecContext(s.baseCtx,
+				`UPDATE cron_jobs SET chain_input = $1, next_run_at = $2, updated_at = $2
+				 WHERE after_job_id = $3 AND enabled = true AND schedule_kind = 'after'`,
+				output, now, id,
+			); trigErr != nil {
+				slog.Warn("cron: failed to trigger chained jobs", "id", job.ID, "error", trigErr)
+			} else if n, _ := res.RowsAffected(); n > 0 {
+				slog.Info("cron: triggered chained jobs", "id", job.ID, "count", n)
+			}
+		}
+	}
+
+	// Recompute next run or delete. While run-all catch-up replays are
+	// pending, the next missed run becomes due immediately instead. The
+	// consumed chain input is cleared unless a new trigger arrived mid-run.
+	if job.DeleteAfterRun && job.State.CatchUpPending == 0 {
 		if id, parseErr := uuid.Parse(job.ID); parseErr == nil {
 			s.db.ExecContext(s.baseCtx, "DELETE FROM cron_jobs WHERE id = $1", id)
 		}
@@ -276,9 +362,14 @@ func (s *PGCronStore) executeOneJob(job store.CronJob, handler func(job *store.C
 		s.db.ExecContext(s.baseCtx,
 			`UPDATE cron_jobs SET
 			 last_run_at = $1, last_status = $2, last_error = $3, updated_at = $4,
-			 next_run_at = CASE WHEN enabled = true AND next_run_at IS NULL THEN $5 ELSE next_run_at END
-			 WHERE id = $6`,
-			now, status, lastError, now, nextRunValue, id,
+			 next_run_at = CASE WHEN enabled = true AND next_run_at IS NULL
+			   THEN CASE WHEN catch_up_pending > 0 THEN $4 ELSE $5 END
+			   ELSE next_run_at END,
+			 catch_up_pending = GREATEST(catch_up_pending - 1, 0),
+			 chain_input = CASE WHEN next_run_at IS NULL THEN NULL ELSE chain_input END,
+			 last_output = COALESCE($6, last_output)
+			 WHERE id = $7`,
+			now, status, lastError, now, nextRunValue, lastOutput, id,
 		)
 	}
 
@@ -312,10 +403,7 @@ func (s *PGCronStore) claimDueJob(id uuid.UUID, now time.Time) bool {
 func (s *PGCronStore) loadClaimedJob(id uuid.UUID) (*store.CronJob, bool) {
 	row := s.db.QueryRowContext(
 		s.baseCtx,
-		`SELECT id, tenant_id, agent_id, user_id, name, enabled, schedule_kind, cron_expression, run_at, timezone,
-		 interval_ms, payload, delete_after_run, stateless, deliver, deliver_channel, deliver_to, wake_heartbeat,
-		 next_run_at, last_run_at, last_status, last_error,
-		 created_at, updated_at
+		`SELECT `+cronJobColumns+`
 		 FROM cron_jobs
 		 WHERE id = $1 AND enabled = true AND next_run_at IS NULL`,
 		id,
@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/nextlevelbuilder/goclaw/internal/cron"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
@@ -58,6 +59,11 @@ func (s *PGCronStore) UpdateJob(ctx context.Context, jobID string, patch store.C
 		if err := store.ValidateCronSchedule(&merged); err != nil {
 			return nil, err
 		}
+		if merged.Kind == "after" {
+			if err := store.ValidateCronChain(jobID, merged.After, s.chainLookup(ctx, tx)); err != nil {
+				return nil, err
+			}
+		}
 
 		store.ApplyCronScheduleUpdates(updates, merged)
 
@@ -66,12 +72,17 @@ func (s *PGCronStore) UpdateJob(ctx context.Context, jobID string, patch store.C
 			return nil, err
 		}
 		updates["next_run_at"] = nextRun
+		// A new schedule invalidates run-all replays owed under the old one.
+		updates["catch_up_pending"] = 0
 	} else if patch.Enabled != nil {
 		nextRun, err := store.NextRunForToggle(&current.Schedule, effectiveEnabled, current.Enabled, current.NextRunAt, now, s.defaultTZ)
 		if err != nil {
 			return nil, err
 		}
 		updates["next_run_at"] = nextRun
+		if !effectiveEnabled {
+			updates["catch_up_pending"] = 0
+		}
 	}
 
 	if patch.Stateless != nil {
@@ -89,10 +100,36 @@ func (s *PGCronStore) UpdateJob(ctx context.Context, jobID string, patch store.C
 	if patch.WakeHeartbeat != nil {
 		updates["wake_heartbeat"] = *patch.WakeHeartbeat
 	}
+	if patch.CatchUp != nil {
+		if err := store.ValidateCronCatchUp(*patch.CatchUp); err != nil {
+			return nil, err
+		}
+		updates["catch_up"] = *patch.CatchUp
+	}
+	if patch.DeliverIf != nil {
+		if err := cron.ValidateCondition(*patch.DeliverIf); err != nil {
+			return nil, err
+		}
+		updates["deliver_if"] = *patch.DeliverIf
+	}
 
-	if patch.Message != "" {
+	if patch.Message != "" || patch.WorkflowID != nil || patch.WebhookURL != nil {
 		payload := current.Payload
-		payload.Message = patch.Message
+		if patch.Message != "" {
+			payload.Message = patch.Message
+		}
+		if patch.WorkflowID != nil {
+			payload.WorkflowID = *patch.WorkflowID
+			payload.Kind = store.CronPayloadKindAgentTurn
+			if payload.WorkflowID != "" {
+				payload.Kind = store.CronPayloadKindWorkflow
+			}
+		}
+		if patch.WebhookURL != nil {
+			if err := store.ApplyCronWebhookURL(&payload, *patch.WebhookURL); err != nil {
+				return nil, err
+			}
+		}
 		mergedPayload, err := json.Marshal(payload)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal payload for job %s: %w", jobID, err)
@@ -115,7 +152,7 @@ func (s *PGCronStore) UpdateJob(ctx context.Context, jobID string, patch store.C
 }
 
 func (s *PGCronStore) lockCronJobForMutation(ctx context.Context, tx *sql.Tx, id uuid.UUID, loadPayload bool) (*store.CronJobMutableState, error) {
-	q := `SELECT enabled, schedule_kind, cron_expression, run_at, timezone, interval_ms, next_run_at, payload
+	q := `SELECT enabled, schedule_kind, cron_expression, run_at, timezone, interval_ms, next_run_at, payload, after_job_id
 		FROM cron_jobs WHERE id = $1`
 	args := []any{id}
 
@@ -138,6 +175,7 @@ func (s *PGCronStore) lockCronJobForMutation(ctx context.Context, tx *sql.Tx, id
 		intervalMS   *int64
 		nextRunAt    *time.Time
 		payloadJSON  []byte
+		afterJobID   *uuid.UUID
 	)
 
 	if err := tx.QueryRowContext(ctx, q, args...).Scan(
@@ -149,6 +187,7 @@ func (s *PGCronStore) lockCronJobForMutation(ctx context.Context, tx *sql.Tx, id
 		&intervalMS,
 		&nextRunAt,
 		&payloadJSON,
+		&afterJobID,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, store.ErrCronJobNotFound
@@ -170,6 +209,9 @@ func (s *PGCronStore) lockCronJobForMutation(ctx context.Context, tx *sql.Tx, id
 	if intervalMS != nil {
 		state.Schedule.EveryMS = intervalMS
 	}
+	if afterJobID != nil {
+		state.Schedule.After = afterJobID.String()
+	}
 	state.NextRunAt = nextRunAt
 
 	if loadPayload && len(payloadJSON) > 0 {
@@ -181,6 +223,38 @@ func (s *PGCronStore) lockCronJobForMutation(ctx context.Context, tx *sql.Tx, id
 	return &state, nil
 }
 
+// cronRowQueryer is satisfied by both *sql.DB and *sql.Tx.
+type cronRowQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// chainLookup resolves a cron job's upstream "after" job within the
+// caller's tenant, for store.ValidateCronChain.
+func (s *PGCronStore) chainLookup(ctx context.Context, db cronRowQueryer) func(string) (string, bool, error) {
+	return func(jobID string) (string, bool, error) {
+		id, err := uuid.Parse(jobID)
+		if err != nil {
+			return "", false, nil
+		}
+		q := `SELECT after_job_id FROM cron_jobs WHERE id = $1`
+		args := []any{id}
+		if !store.IsCrossTenant(ctx) {
+			q += ` AND tenant_id = $2`
+			args = append(args, store.TenantIDFromContext(ctx))
+		}
+		var after *uuid.UUID
+		if err := db.QueryRowContext(ctx, q, args...).Scan(&after); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+		if after == nil {
+			return "", true, nil
+		}
+		return after.String(), true, nil
+	}
+}
 
 func execCronJobUpdateTx(ctx context.Context, tx *sql.Tx, id uuid.UUID, updates map[string]any) error {
 	if len(updates) == 0 {
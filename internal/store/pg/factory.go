@@ -36,9 +36,12 @@ func NewPGStores(cfg store.StoreConfig) (*store.Stores, error) {
 		ConfigSecrets:         NewPGConfigSecretsStore(db, cfg.EncryptionKey),
 		AgentLinks:            NewPGAgentLinkStore(db),
 		Teams:                 NewPGTeamStore(db),
+		Workflows:             NewPGWorkflowStore(db),
 		BuiltinTools:          NewPGBuiltinToolStore(db),
 		PendingMessages:       NewPGPendingMessageStore(db),
 		KnowledgeGraph:        NewPGKnowledgeGraphStore(db),
+		KnowledgeBases:        NewPGKnowledgeBaseStore(db),
+		EmbeddingCache:        NewPGEmbeddingCacheStore(db),
 		Contacts:              NewPGContactStore(db),
 		Activity:              NewPGActivityStore(db),
 		Snapshots:             NewPGSnapshotStore(db),
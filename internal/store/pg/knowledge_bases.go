@@ -0,0 +1,619 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// kbEmbedBatchSize is the number of chunks sent per embedding request.
+const kbEmbedBatchSize = 64
+
+// PGKnowledgeBaseStore implements store.KnowledgeBaseStore backed by Postgres.
+type PGKnowledgeBaseStore struct {
+	db       *sql.DB
+	mu       sync.RWMutex
+	provider store.EmbeddingProvider
+}
+
+func NewPGKnowledgeBaseStore(db *sql.DB) *PGKnowledgeBaseStore {
+	return &PGKnowledgeBaseStore{db: db}
+}
+
+func (s *PGKnowledgeBaseStore) SetEmbeddingProvider(provider store.EmbeddingProvider) {
+	s.mu.Lock()
+	s.provider = provider
+	s.mu.Unlock()
+}
+
+func (s *PGKnowledgeBaseStore) embeddingProvider() store.EmbeddingProvider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.provider
+}
+
+// ============================================================
+// Collections
+// ============================================================
+
+const kbCollectionSelectCols = `c.id, c.tenant_id, c.name, c.description, c.created_by, c.created_at, c.updated_at,
+		 (SELECT COUNT(*) FROM kb_documents d WHERE d.collection_id = c.id)`
+
+func (s *PGKnowledgeBaseStore) CreateCollection(ctx context.Context, c *store.KBCollectionData) error {
+	if c.ID == uuid.Nil {
+		c.ID = store.GenNewID()
+	}
+	now := time.Now()
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	c.TenantID = tenantIDForInsert(ctx)
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO kb_collections (id, name, description, created_by, tenant_id, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		c.ID, c.Name, c.Description, c.CreatedBy, c.TenantID, now, now,
+	)
+	return err
+}
+
+func (s *PGKnowledgeBaseStore) GetCollection(ctx context.Context, id uuid.UUID) (*store.KBCollectionData, error) {
+	where, args, err := kbTenantWhere(ctx, "c.tenant_id", "c.id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	return s.getCollection(ctx, where, args)
+}
+
+func (s *PGKnowledgeBaseStore) GetCollectionByName(ctx context.Context, name string) (*store.KBCollectionData, error) {
+	where, args, err := kbTenantWhere(ctx, "c.tenant_id", "LOWER(c.name) = LOWER($1)", name)
+	if err != nil {
+		return nil, err
+	}
+	return s.getCollection(ctx, where, args)
+}
+
+func (s *PGKnowledgeBaseStore) getCollection(ctx context.Context, where string, args []any) (*store.KBCollectionData, error) {
+	cols, err := s.queryCollections(ctx, `SELECT `+kbCollectionSelectCols+` FROM kb_collections c WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(cols) == 0 {
+		return nil, store.ErrKBCollectionNotFound
+	}
+	return &cols[0], nil
+}
+
+func (s *PGKnowledgeBaseStore) ListCollections(ctx context.Context) ([]store.KBCollectionData, error) {
+	where, args, err := kbTenantWhere(ctx, "c.tenant_id", "TRUE")
+	if err != nil {
+		return nil, err
+	}
+	return s.queryCollections(ctx, `SELECT `+kbCollectionSelectCols+` FROM kb_collections c WHERE `+where+` ORDER BY c.name`, args...)
+}
+
+func (s *PGKnowledgeBaseStore) UpdateCollection(ctx context.Context, c *store.KBCollectionData) error {
+	c.UpdatedAt = time.Now()
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "id = $1", c.ID)
+	if err != nil {
+		return err
+	}
+	n := len(args)
+	res, err := s.db.ExecContext(ctx,
+		fmt.Sprintf(`UPDATE kb_collections SET name = $%d, description = $%d, updated_at = $%d WHERE %s`, n+1, n+2, n+3, where),
+		append(args, c.Name, c.Description, c.UpdatedAt)...,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrKBCollectionNotFound
+	}
+	return nil
+}
+
+func (s *PGKnowledgeBaseStore) DeleteCollection(ctx context.Context, id uuid.UUID) error {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "id = $1", id)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx, `DELETE FROM kb_collections WHERE `+where, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrKBCollectionNotFound
+	}
+	return nil
+}
+
+func (s *PGKnowledgeBaseStore) queryCollections(ctx context.Context, query string, args ...any) ([]store.KBCollectionData, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var cols []store.KBCollectionData
+	for rows.Next() {
+		var c store.KBCollectionData
+		if err := rows.Scan(&c.ID, &c.TenantID, &c.Name, &c.Description, &c.CreatedBy, &c.CreatedAt, &c.UpdatedAt,
+			&c.DocumentCount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		cols = append(cols, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.loadCollectionAgents(ctx, cols); err != nil {
+		return nil, err
+	}
+	return cols, nil
+}
+
+// loadCollectionAgents fills AgentIDs of the given collections.
+func (s *PGKnowledgeBaseStore) loadCollectionAgents(ctx context.Context, cols []store.KBCollectionData) error {
+	if len(cols) == 0 {
+		return nil
+	}
+	ids := make([]string, len(cols))
+	byID := make(map[uuid.UUID]*store.KBCollectionData, len(cols))
+	for i := range cols {
+		ids[i] = cols[i].ID.String()
+		cols[i].AgentIDs = []uuid.UUID{}
+		byID[cols[i].ID] = &cols[i]
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT collection_id, agent_id FROM kb_collection_agents WHERE collection_id = ANY($1::uuid[]) ORDER BY created_at`,
+		pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var colID, agentID uuid.UUID
+		if err := rows.Scan(&colID, &agentID); err != nil {
+			return err
+		}
+		if c := byID[colID]; c != nil {
+			c.AgentIDs = append(c.AgentIDs, agentID)
+		}
+	}
+	return rows.Err()
+}
+
+// ============================================================
+// Agent attachments
+// ============================================================
+
+func (s *PGKnowledgeBaseStore) AttachAgent(ctx context.Context, collectionID, agentID uuid.UUID) error {
+	c, err := s.GetCollection(ctx, collectionID)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO kb_collection_agents (collection_id, agent_id, tenant_id, created_at)
+		 VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING`,
+		collectionID, agentID, c.TenantID, time.Now(),
+	)
+	return err
+}
+
+func (s *PGKnowledgeBaseStore) DetachAgent(ctx context.Context, collectionID, agentID uuid.UUID) error {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "collection_id = $1 AND agent_id = $2", collectionID, agentID)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `DELETE FROM kb_collection_agents WHERE `+where, args...)
+	return err
+}
+
+func (s *PGKnowledgeBaseStore) ListAgentCollections(ctx context.Context, agentID uuid.UUID) ([]store.KBCollectionData, error) {
+	where, args, err := kbTenantWhere(ctx, "c.tenant_id",
+		"c.id IN (SELECT collection_id FROM kb_collection_agents WHERE agent_id = $1)", agentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.queryCollections(ctx, `SELECT `+kbCollectionSelectCols+` FROM kb_collections c WHERE `+where+` ORDER BY c.name`, args...)
+}
+
+// ============================================================
+// Documents
+// ============================================================
+
+const kbDocumentSelectCols = `id, collection_id, tenant_id, file_name, mime_type, hash, size_bytes, pages,
+		 status, error, chunk_count, created_by, created_at, updated_at`
+
+func (s *PGKnowledgeBaseStore) PutDocument(ctx context.Context, doc *store.KBDocumentData) error {
+	c, err := s.GetCollection(ctx, doc.CollectionID)
+	if err != nil {
+		return err
+	}
+	if doc.ID == uuid.Nil {
+		doc.ID = store.GenNewID()
+	}
+	now := time.Now()
+	doc.TenantID = c.TenantID
+	doc.Status = store.KBDocStatusPending
+	doc.Error = ""
+	doc.UpdatedAt = now
+	return s.db.QueryRowContext(ctx,
+		`INSERT INTO kb_documents (id, collection_id, file_name, mime_type, hash, size_bytes, pages, content,
+		   status, created_by, tenant_id, created_at, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		 ON CONFLICT (collection_id, file_name) DO UPDATE SET
+		   mime_type = EXCLUDED.mime_type, hash = EXCLUDED.hash, size_bytes = EXCLUDED.size_bytes,
+		   pages = EXCLUDED.pages, content = EXCLUDED.content, status = EXCLUDED.status, error = '',
+		   updated_at = EXCLUDED.updated_at
+		 RETURNING id, chunk_count, created_by, created_at`,
+		doc.ID, doc.CollectionID, doc.FileName, doc.MimeType, doc.Hash, doc.SizeBytes, doc.Pages, doc.Content,
+		doc.Status, doc.CreatedBy, doc.TenantID, now, now,
+	).Scan(&doc.ID, &doc.ChunkCount, &doc.CreatedBy, &doc.CreatedAt)
+}
+
+func (s *PGKnowledgeBaseStore) GetDocument(ctx context.Context, id uuid.UUID) (*store.KBDocumentData, error) {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	return s.getDocument(ctx, where, args)
+}
+
+func (s *PGKnowledgeBaseStore) GetDocumentByName(ctx context.Context, collectionID uuid.UUID, fileName string) (*store.KBDocumentData, error) {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "collection_id = $1 AND file_name = $2", collectionID, fileName)
+	if err != nil {
+		return nil, err
+	}
+	return s.getDocument(ctx, where, args)
+}
+
+// getDocument loads one document including its content.
+func (s *PGKnowledgeBaseStore) getDocument(ctx context.Context, where string, args []any) (*store.KBDocumentData, error) {
+	var d store.KBDocumentData
+	err := s.db.QueryRowContext(ctx,
+		`SELECT `+kbDocumentSelectCols+`, content FROM kb_documents WHERE `+where, args...,
+	).Scan(&d.ID, &d.CollectionID, &d.TenantID, &d.FileName, &d.MimeType, &d.Hash, &d.SizeBytes, &d.Pages,
+		&d.Status, &d.Error, &d.ChunkCount, &d.CreatedBy, &d.CreatedAt, &d.UpdatedAt, &d.Content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrKBDocumentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ListDocuments returns the documents of a collection without their content.
+func (s *PGKnowledgeBaseStore) ListDocuments(ctx context.Context, collectionID uuid.UUID) ([]store.KBDocumentData, error) {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "collection_id = $1", collectionID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+kbDocumentSelectCols+` FROM kb_documents WHERE `+where+` ORDER BY file_name`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var docs []store.KBDocumentData
+	for rows.Next() {
+		var d store.KBDocumentData
+		if err := rows.Scan(&d.ID, &d.CollectionID, &d.TenantID, &d.FileName, &d.MimeType, &d.Hash, &d.SizeBytes, &d.Pages,
+			&d.Status, &d.Error, &d.ChunkCount, &d.CreatedBy, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, err
+		}
+		docs = append(docs, d)
+	}
+	return docs, rows.Err()
+}
+
+func (s *PGKnowledgeBaseStore) DeleteDocument(ctx context.Context, collectionID, id uuid.UUID) error {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "id = $1 AND collection_id = $2", id, collectionID)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx, `DELETE FROM kb_documents WHERE `+where, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrKBDocumentNotFound
+	}
+	return nil
+}
+
+func (s *PGKnowledgeBaseStore) MarkDocumentsPending(ctx context.Context, collectionID uuid.UUID) error {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "collection_id = $3", store.KBDocStatusPending, time.Now(), collectionID)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `UPDATE kb_documents SET status = $1, error = '', updated_at = $2 WHERE `+where, args...)
+	return err
+}
+
+// IndexDocument rebuilds a document's chunks. Embeddings of chunks whose text
+// is unchanged are carried over, so re-indexing an edited document only
+// embeds the passages that changed. Embedding failures leave chunks without
+// vectors (text search still finds them), as in the memory store.
+func (s *PGKnowledgeBaseStore) IndexDocument(ctx context.Context, id uuid.UUID) error {
+	doc, err := s.GetDocument(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.db.ExecContext(ctx, `UPDATE kb_documents SET status = $1, updated_at = $2 WHERE id = $3`,
+		store.KBDocStatusIndexing, time.Now(), id)
+
+	if err := s.indexDocument(ctx, doc); err != nil {
+		s.db.ExecContext(ctx, `UPDATE kb_documents SET status = $1, error = $2, updated_at = $3 WHERE id = $4`,
+			store.KBDocStatusFailed, err.Error(), time.Now(), id)
+		return err
+	}
+	return nil
+}
+
+func (s *PGKnowledgeBaseStore) indexDocument(ctx context.Context, doc *store.KBDocumentData) error {
+	chunks := store.ChunkKBContent(doc.Content)
+
+	previous, err := s.existingEmbeddings(ctx, doc.ID)
+	if err != nil {
+		return fmt.Errorf("load existing embeddings: %w", err)
+	}
+	embeddings := embedKBChunks(ctx, s.embeddingProvider(), doc.FileName, chunks, previous)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM kb_chunks WHERE document_id = $1`, doc.ID); err != nil {
+		return err
+	}
+	now := time.Now()
+	for i, c := range chunks {
+		var vec *string
+		if emb := embeddings[i]; len(emb) > 0 {
+			v := vectorToString(emb)
+			vec = &v
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO kb_chunks (id, collection_id, document_id, page, start_line, end_line, hash, text, embedding, tenant_id, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::vector, $10, $11)`,
+			uuid.Must(uuid.NewV7()), doc.CollectionID, doc.ID, c.Page, c.StartLine, c.EndLine, c.Hash, c.Text,
+			vec, doc.TenantID, now,
+		); err != nil {
+			return fmt.Errorf("insert chunk: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE kb_documents SET status = $1, error = '', chunk_count = $2, updated_at = $3 WHERE id = $4`,
+		store.KBDocStatusReady, len(chunks), now, doc.ID,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// existingEmbeddings maps chunk hash → embedding for a document's current chunks.
+func (s *PGKnowledgeBaseStore) existingEmbeddings(ctx context.Context, docID uuid.UUID) (map[string][]float32, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT hash, embedding::text FROM kb_chunks WHERE document_id = $1 AND embedding IS NOT NULL`, docID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[string][]float32)
+	for rows.Next() {
+		var hash, vec string
+		if err := rows.Scan(&hash, &vec); err != nil {
+			return nil, err
+		}
+		if emb, err := parseVector(vec); err == nil {
+			out[hash] = emb
+		}
+	}
+	return out, rows.Err()
+}
+
+// embedKBChunks returns one embedding per chunk (nil where unavailable),
+// reusing previous embeddings by content hash and embedding the rest in
+// batches.
+func embedKBChunks(ctx context.Context, provider store.EmbeddingProvider, fileName string, chunks []store.KBChunk, previous map[string][]float32) [][]float32 {
+	embeddings := make([][]float32, len(chunks))
+	var pending []int
+	for i, c := range chunks {
+		if emb, ok := previous[c.Hash]; ok {
+			embeddings[i] = emb
+			continue
+		}
+		pending = append(pending, i)
+	}
+	if provider == nil {
+		return embeddings
+	}
+	for start := 0; start < len(pending); start += kbEmbedBatchSize {
+		batch := pending[start:min(start+kbEmbedBatchSize, len(pending))]
+		texts := make([]string, len(batch))
+		for j, idx := range batch {
+			texts[j] = chunks[idx].Text
+		}
+		embs, err := provider.Embed(ctx, texts)
+		if err != nil {
+			slog.Warn("kb embedding failed, storing chunks without vectors",
+				"file", fileName, "chunks", len(batch), "error", err)
+			continue
+		}
+		for j, emb := range embs {
+			if j < len(batch) {
+				embeddings[batch[j]] = emb
+			}
+		}
+	}
+	return embeddings
+}
+
+// ============================================================
+// Search
+// ============================================================
+
+const kbDefaultMaxResults = 6
+
+type kbScoredChunk struct {
+	store.KBSearchResult
+	chunkID uuid.UUID
+}
+
+// Search runs full-text and vector search over the selected collections and
+// merges them with the memory store's weights (0.3 text, 0.7 vector).
+func (s *PGKnowledgeBaseStore) Search(ctx context.Context, query string, agentID uuid.UUID, opts store.KBSearchOptions) ([]store.KBSearchResult, error) {
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = kbDefaultMaxResults
+	}
+	maxResults = min(maxResults, store.KBMaxSearchResults)
+	colIDs, err := s.searchCollections(ctx, agentID, opts.CollectionIDs)
+	if err != nil || len(colIDs) == 0 {
+		return nil, err
+	}
+
+	fts, err := s.kbSearchQuery(ctx, `ts_rank(ch.tsv, plainto_tsquery('simple', $1))`,
+		`ch.tsv @@ plainto_tsquery('simple', $1)`, `score DESC`, query, colIDs, maxResults*2)
+	if err != nil {
+		return nil, err
+	}
+	var vec []kbScoredChunk
+	if provider := s.embeddingProvider(); provider != nil {
+		embeddings, err := provider.Embed(ctx, []string{query})
+		if err == nil && len(embeddings) > 0 && len(embeddings[0]) > 0 {
+			vec, err = s.kbSearchQuery(ctx, `1 - (ch.embedding <=> $1::vector)`,
+				`ch.embedding IS NOT NULL`, `ch.embedding <=> $1::vector`, vectorToString(embeddings[0]), colIDs, maxResults*2)
+			if err != nil {
+				slog.Warn("kb vector search failed", "error", err)
+				vec = nil
+			}
+		}
+	}
+	return kbMerge(fts, vec, opts.MinScore, maxResults), nil
+}
+
+// searchCollections resolves the collections to search: those attached to
+// the agent, narrowed to the requested ones when given. Without an agent only
+// the requested collections are searched.
+func (s *PGKnowledgeBaseStore) searchCollections(ctx context.Context, agentID uuid.UUID, requested []uuid.UUID) ([]string, error) {
+	if agentID == uuid.Nil && len(requested) == 0 {
+		return nil, nil
+	}
+	var cols []store.KBCollectionData
+	var err error
+	if agentID != uuid.Nil {
+		cols, err = s.ListAgentCollections(ctx, agentID)
+	} else {
+		cols, err = s.ListCollections(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	want := make(map[uuid.UUID]bool, len(requested))
+	for _, id := range requested {
+		want[id] = true
+	}
+	var ids []string
+	for _, c := range cols {
+		if len(want) > 0 && !want[c.ID] {
+			continue
+		}
+		ids = append(ids, c.ID.String())
+	}
+	return ids, nil
+}
+
+// kbSearchQuery runs one ranked chunk query. score, cond and order are fixed
+// SQL fragments that may reference $1 (the query text or vector).
+func (s *PGKnowledgeBaseStore) kbSearchQuery(ctx context.Context, score, cond, order string, arg any, colIDs []string, limit int) ([]kbScoredChunk, error) {
+	q := `SELECT ch.id, ch.collection_id, k.name, ch.document_id, d.file_name, ch.page, ch.start_line, ch.end_line, ch.text,
+			` + score + ` AS score
+		FROM kb_chunks ch
+		JOIN kb_documents d ON d.id = ch.document_id
+		JOIN kb_collections k ON k.id = ch.collection_id
+		WHERE ` + cond + ` AND ch.collection_id = ANY($2::uuid[])
+		ORDER BY ` + order + ` LIMIT $3`
+	rows, err := s.db.QueryContext(ctx, q, arg, pq.Array(colIDs), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []kbScoredChunk
+	for rows.Next() {
+		var r kbScoredChunk
+		if err := rows.Scan(&r.chunkID, &r.CollectionID, &r.CollectionName, &r.DocumentID, &r.FileName,
+			&r.Page, &r.StartLine, &r.EndLine, &r.Snippet, &r.Score); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// kbMerge combines text and vector hits with weighted scores. When one side
+// has no hits the other gets the full weight.
+func kbMerge(fts, vec []kbScoredChunk, minScore float64, maxResults int) []store.KBSearchResult {
+	textW, vecW := 0.3, 0.7
+	if len(fts) == 0 {
+		textW, vecW = 0, 1.0
+	} else if len(vec) == 0 {
+		textW, vecW = 1.0, 0
+	}
+	merged := make(map[uuid.UUID]*store.KBSearchResult)
+	var order []uuid.UUID
+	add := func(r kbScoredChunk, w float64) {
+		if m, ok := merged[r.chunkID]; ok {
+			m.Score += r.Score * w
+			return
+		}
+		res := r.KBSearchResult
+		res.Score = r.Score * w
+		merged[r.chunkID] = &res
+		order = append(order, r.chunkID)
+	}
+	for _, r := range fts {
+		add(r, textW)
+	}
+	for _, r := range vec {
+		add(r, vecW)
+	}
+
+	results := make([]store.KBSearchResult, 0, len(order))
+	for _, id := range order {
+		if r := merged[id]; minScore <= 0 || r.Score >= minScore {
+			results = append(results, *r)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > maxResults {
+		results = results[:maxResults]
+	}
+	return results
+}
+
+// kbTenantWhere appends the tenant filter to cond unless the context is
+// cross-tenant. args are the parameters already referenced by cond.
+func kbTenantWhere(ctx context.Context, col, cond string, args ...any) (string, []any, error) {
+	if store.IsCrossTenant(ctx) {
+		return cond, args, nil
+	}
+	tid, err := requireTenantID(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, tid)
+	return cond + fmt.Sprintf(" AND %s = $%d", col, len(args)), args, nil
+}
@@ -276,12 +276,18 @@ func (s *PGMemoryStore) IndexDocument(ctx context.Context, agentID, userID, path
 			hashes[i] = memory.ContentHash(c.Text)
 		}
 
-		// Batch lookup cached embeddings
-		cached, cacheErr := s.lookupEmbeddingCache(ctx, hashes, providerName, providerModel)
-		if cacheErr != nil {
-			slog.Warn("embedding cache lookup failed, falling back to full API call",
-				"path", path, "error", cacheErr)
-			cached = nil
+		// Batch lookup cached embeddings, unless the provider is the shared
+		// cached provider, which already serves repeats from the same table.
+		_, sharedCache := s.provider.(*memory.CachedEmbeddingProvider)
+		var cached map[string][]float32
+		if !sharedCache {
+			var cacheErr error
+			cached, cacheErr = s.lookupEmbeddingCache(ctx, hashes, providerName, providerModel)
+			if cacheErr != nil {
+				slog.Warn("embedding cache lookup failed, falling back to full API call",
+					"path", path, "error", cacheErr)
+				cached = nil
+			}
 		}
 
 		// Determine which chunks need fresh embeddings
@@ -314,7 +320,7 @@ func (s *PGMemoryStore) IndexDocument(ctx context.Context, agentID, userID, path
 		}
 
 		// Write fresh embeddings back to cache
-		if len(freshEmbeddings) > 0 {
+		if len(freshEmbeddings) > 0 && !sharedCache {
 			if len(freshEmbeddings) != len(uncachedTexts) {
 				slog.Warn("embedding API returned mismatched count",
 					"expected", len(uncachedTexts), "got", len(freshEmbeddings))
@@ -2,8 +2,10 @@ package pg
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log/slog"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,9 +17,33 @@ type embeddingCacheEntry struct {
 	Embedding []float32
 }
 
+// PGEmbeddingCacheStore implements store.EmbeddingCacheStore on the
+// embedding_cache table.
+type PGEmbeddingCacheStore struct {
+	db *sql.DB
+}
+
+func NewPGEmbeddingCacheStore(db *sql.DB) *PGEmbeddingCacheStore {
+	return &PGEmbeddingCacheStore{db: db}
+}
+
 // lookupEmbeddingCache fetches cached embeddings for the given content hashes.
-// Returns a map from hash -> embedding vector. Missing hashes are simply absent.
 func (s *PGMemoryStore) lookupEmbeddingCache(ctx context.Context, hashes []string, provider, model string) (map[string][]float32, error) {
+	return NewPGEmbeddingCacheStore(s.db).LookupEmbeddings(ctx, hashes, provider, model)
+}
+
+// writeEmbeddingCache batch-upserts embedding cache entries.
+func (s *PGMemoryStore) writeEmbeddingCache(ctx context.Context, entries []embeddingCacheEntry, provider, model string) error {
+	m := make(map[string][]float32, len(entries))
+	for _, e := range entries {
+		m[e.Hash] = e.Embedding
+	}
+	return NewPGEmbeddingCacheStore(s.db).WriteEmbeddings(ctx, m, provider, model)
+}
+
+// LookupEmbeddings fetches cached embeddings for the given content hashes.
+// Returns a map from hash -> embedding vector. Missing hashes are simply absent.
+func (s *PGEmbeddingCacheStore) LookupEmbeddings(ctx context.Context, hashes []string, provider, model string) (map[string][]float32, error) {
 	if len(hashes) == 0 {
 		return nil, nil
 	}
@@ -32,7 +58,7 @@ func (s *PGMemoryStore) lookupEmbeddingCache(ctx context.Context, hashes []strin
 	args = append(args, provider, model)
 
 	query := fmt.Sprintf(
-		"SELECT hash, embedding FROM embedding_cache WHERE hash IN (%s) AND provider = $%d AND model = $%d",
+		"SELECT hash, embedding FROM embedding_cache WHERE hash IN (%s) AND provider = $%d AND model = $%d AND embedding IS NOT NULL",
 		strings.Join(placeholders, ","), len(hashes)+1, len(hashes)+2,
 	)
 
@@ -59,33 +85,39 @@ func (s *PGMemoryStore) lookupEmbeddingCache(ctx context.Context, hashes []strin
 	return result, rows.Err()
 }
 
-// writeEmbeddingCache batch-upserts embedding cache entries.
+// WriteEmbeddings batch-upserts embedding cache entries.
 // Gracefully skips on dimension mismatch (schema uses vector(1536)).
-func (s *PGMemoryStore) writeEmbeddingCache(ctx context.Context, entries []embeddingCacheEntry, provider, model string) error {
+func (s *PGEmbeddingCacheStore) WriteEmbeddings(ctx context.Context, entries map[string][]float32, provider, model string) error {
 	if len(entries) == 0 {
 		return nil
 	}
 
+	hashes := make([]string, 0, len(entries))
+	for h := range entries {
+		hashes = append(hashes, h)
+	}
+	sort.Strings(hashes) // stable row order keeps concurrent upserts from deadlocking
+
 	now := time.Now()
 	tenantID := tenantIDForInsert(ctx)
 
 	// Process in batches of 100 to avoid exceeding max query params
 	const batchSize = 100
-	for start := 0; start < len(entries); start += batchSize {
-		end := min(start+batchSize, len(entries))
-		batch := entries[start:end]
+	for start := 0; start < len(hashes); start += batchSize {
+		end := min(start+batchSize, len(hashes))
+		batch := hashes[start:end]
 
 		var sb strings.Builder
 		sb.WriteString(`INSERT INTO embedding_cache (hash, provider, model, embedding, dims, created_at, updated_at, tenant_id) VALUES `)
 		args := make([]any, 0, len(batch)*7)
-		for i, e := range batch {
+		for i, h := range batch {
 			if i > 0 {
 				sb.WriteByte(',')
 			}
 			base := i * 7
 			fmt.Fprintf(&sb, "($%d,$%d,$%d,$%d::vector,$%d,$%d,$%d,$%d)",
 				base+1, base+2, base+3, base+4, base+5, base+6, base+6, base+7)
-			args = append(args, e.Hash, provider, model, vectorToString(e.Embedding), len(e.Embedding), now, tenantID)
+			args = append(args, h, provider, model, vectorToString(entries[h]), len(entries[h]), now, tenantID)
 		}
 		sb.WriteString(` ON CONFLICT (hash, provider, model) DO UPDATE SET embedding = EXCLUDED.embedding, dims = EXCLUDED.dims, updated_at = EXCLUDED.updated_at`)
 
@@ -95,7 +127,7 @@ func (s *PGMemoryStore) writeEmbeddingCache(ctx context.Context, entries []embed
 			if strings.Contains(err.Error(), "dimensions") {
 				slog.Warn("embedding cache skipped: vector dimension mismatch",
 					"provider", provider, "model", model,
-					"actual_dims", len(batch[0].Embedding), "error", err)
+					"actual_dims", len(entries[batch[0]]), "error", err)
 				return nil
 			}
 			return fmt.Errorf("batch write embedding cache: %w", err)
@@ -104,6 +136,35 @@ func (s *PGMemoryStore) writeEmbeddingCache(ctx context.Context, entries []embed
 	return nil
 }
 
+// embeddedTables lists the vector columns regenerated by the backfills, with
+// the filter each backfill applies so rows it would skip keep their vectors.
+var embeddedTables = []struct{ table, where string }{
+	{"memory_chunks", ""},
+	{"skills", "status = 'active' AND enabled = true"},
+	{"team_tasks", "status NOT IN ('cancelled')"},
+	{"kg_entities", ""},
+	{"kb_chunks", ""},
+}
+
+// ClearEmbeddings drops stored vectors across tenants so the backfills
+// re-embed them with the current provider.
+func (s *PGEmbeddingCacheStore) ClearEmbeddings(ctx context.Context) (int64, error) {
+	var total int64
+	for _, t := range embeddedTables {
+		q := "UPDATE " + t.table + " SET embedding = NULL WHERE embedding IS NOT NULL"
+		if t.where != "" {
+			q += " AND " + t.where
+		}
+		res, err := s.db.ExecContext(ctx, q)
+		if err != nil {
+			return total, fmt.Errorf("clear %s embeddings: %w", t.table, err)
+		}
+		n, _ := res.RowsAffected()
+		total += n
+	}
+	return total, nil
+}
+
 // parseVector converts a pgvector string like "[0.1,0.2,0.3]" into []float32.
 func parseVector(s string) ([]float32, error) {
 	s = strings.TrimSpace(s)
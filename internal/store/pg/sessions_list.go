@@ -198,7 +198,7 @@ func (s *PGSessionStore) ListPagedRich(ctx context.Context, opts store.SessionLi
 		s.label, s.channel, s.user_id, COALESCE(s.metadata, '{}'),
 		s.model, s.provider, s.input_tokens, s.output_tokens,
 		COALESCE(a.display_name, ''),
-		octet_length(s.messages::text) / 4 + 12000,
+		s.estimated_tokens,
 		COALESCE(a.context_window, 200000), -- config.DefaultContextWindow
 		s.compaction_count`
 
@@ -276,6 +276,7 @@ func (s *PGSessionStore) Save(ctx context.Context, key string) error {
 	s.mu.RUnlock()
 
 	msgsJSON, _ := json.Marshal(snapshot.Messages)
+	estimatedTokens := store.EstimateSessionTokens(&snapshot)
 	metaJSON := []byte("{}")
 	if len(snapshot.Metadata) > 0 {
 		metaJSON, _ = json.Marshal(snapshot.Metadata)
@@ -288,14 +289,14 @@ func (s *PGSessionStore) Save(ctx context.Context, key string) error {
 			memory_flush_compaction_count = $9, memory_flush_at = $10,
 			label = $11, spawned_by = $12, spawn_depth = $13,
 			agent_id = $14, user_id = $15, metadata = $16, updated_at = $17,
-			team_id = $18
-		 WHERE session_key = $19 AND tenant_id = $20`,
+			team_id = $18, estimated_tokens = $19
+		 WHERE session_key = $20 AND tenant_id = $21`,
 		msgsJSON, nilStr(snapshot.Summary), nilStr(snapshot.Model), nilStr(snapshot.Provider), nilStr(snapshot.Channel),
 		snapshot.InputTokens, snapshot.OutputTokens, snapshot.CompactionCount,
 		snapshot.MemoryFlushCompactionCount, snapshot.MemoryFlushAt,
 		nilStr(snapshot.Label), nilStr(snapshot.SpawnedBy), snapshot.SpawnDepth,
 		nilSessionUUID(snapshot.AgentUUID), nilStr(snapshot.UserID), metaJSON, snapshot.Updated,
-		snapshot.TeamID,
+		snapshot.TeamID, estimatedTokens,
 		key, tenantIDForInsert(ctx),
 	)
 	if err != nil {
@@ -307,8 +308,9 @@ func (s *PGSessionStore) Save(ctx context.Context, key string) error {
 			`INSERT INTO sessions (id, session_key, messages, summary, model, provider, channel,
 				input_tokens, output_tokens, compaction_count,
 				memory_flush_compaction_count, memory_flush_at,
-				label, spawned_by, spawn_depth, agent_id, user_id, metadata, updated_at, team_id, tenant_id, created_at)
-			 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22)
+				label, spawned_by, spawn_depth, agent_id, user_id, metadata, updated_at, team_id, tenant_id, created_at,
+				estimated_tokens)
+			 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23)
 			 ON CONFLICT (tenant_id, session_key) DO UPDATE SET
 				messages = EXCLUDED.messages, summary = EXCLUDED.summary, model = EXCLUDED.model,
 				provider = EXCLUDED.provider, channel = EXCLUDED.channel,
@@ -318,7 +320,8 @@ func (s *PGSessionStore) Save(ctx context.Context, key string) error {
 				memory_flush_at = EXCLUDED.memory_flush_at,
 				label = EXCLUDED.label, spawned_by = EXCLUDED.spawned_by, spawn_depth = EXCLUDED.spawn_depth,
 				agent_id = EXCLUDED.agent_id, user_id = EXCLUDED.user_id, metadata = EXCLUDED.metadata,
-				updated_at = EXCLUDED.updated_at, team_id = EXCLUDED.team_id`,
+				updated_at = EXCLUDED.updated_at, team_id = EXCLUDED.team_id,
+				estimated_tokens = EXCLUDED.estimated_tokens`,
 			uuid.Must(uuid.NewV7()), key, msgsJSON,
 			nilStr(snapshot.Summary), nilStr(snapshot.Model), nilStr(snapshot.Provider), nilStr(snapshot.Channel),
 			snapshot.InputTokens, snapshot.OutputTokens, snapshot.CompactionCount,
@@ -326,6 +329,7 @@ func (s *PGSessionStore) Save(ctx context.Context, key string) error {
 			nilStr(snapshot.Label), nilStr(snapshot.SpawnedBy), snapshot.SpawnDepth,
 			nilSessionUUID(snapshot.AgentUUID), nilStr(snapshot.UserID), metaJSON, snapshot.Updated,
 			snapshot.TeamID, tenantIDForInsert(ctx), snapshot.Updated,
+			estimatedTokens,
 		)
 		return err
 	}
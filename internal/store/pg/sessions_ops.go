@@ -45,7 +45,7 @@ func (s *PGSessionStore) Reset(ctx context.Context, key string) {
 	// so the next GetOrCreate loads a clean session instead of stale history.
 	tid := tenantIDForInsert(ctx)
 	if _, err := s.db.ExecContext(ctx,
-		`UPDATE sessions SET messages = '[]', summary = '', updated_at = $1
+		`UPDATE sessions SET messages = '[]', summary = '', estimated_tokens = 0, updated_at = $1
 		 WHERE session_key = $2 AND tenant_id = $3`,
 		time.Now(), key, tid,
 	); err != nil {
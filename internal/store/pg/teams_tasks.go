@@ -26,6 +26,7 @@ const taskSelectCols = `t.id, t.team_id, t.subject, t.description, t.status, t.o
 		 t.task_type, t.task_number, COALESCE(t.identifier,''), t.created_by_agent_id, COALESCE(t.assignee_user_id,''), t.parent_id,
 		 COALESCE(t.chat_id,''), t.metadata, t.locked_at, t.lock_expires_at, COALESCE(t.progress_percent,0), COALESCE(t.progress_step,''),
 		 t.followup_at, COALESCE(t.followup_count,0), COALESCE(t.followup_max,0), COALESCE(t.followup_message,''), COALESCE(t.followup_channel,''), COALESCE(t.followup_chat_id,''),
+		 t.due_at, COALESCE(t.sla_minutes,0), t.at_risk_at, COALESCE(t.escalation_count,0), t.next_escalation_at,
 		 COALESCE(t.comment_count,0), COALESCE(t.attachment_count,0),
 		 t.created_at, t.updated_at,
 		 COALESCE(a.agent_key, '') AS owner_agent_key,
@@ -129,8 +130,9 @@ func (s *PGTeamStore) CreateTask(ctx context.Context, task *store.TeamTaskData)
 	// INSERT with all fields in one statement.
 	_, err = tx.ExecContext(ctx,
 		`INSERT INTO team_tasks (id, team_id, subject, description, status, owner_agent_id, blocked_by, priority, result, user_id, channel,
-		 task_type, task_number, identifier, created_by_agent_id, parent_id, chat_id, metadata, locked_at, lock_expires_at, created_at, updated_at, tenant_id)
-		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)`,
+		 task_type, task_number, identifier, created_by_agent_id, parent_id, chat_id, metadata, locked_at, lock_expires_at,
+		 due_at, sla_minutes, at_risk_at, created_at, updated_at, tenant_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26)`,
 		task.ID, task.TeamID, task.Subject, task.Description,
 		task.Status, task.OwnerAgentID, pq.Array(task.BlockedBy),
 		task.Priority, task.Result,
@@ -141,6 +143,7 @@ func (s *PGTeamStore) CreateTask(ctx context.Context, task *store.TeamTaskData)
 		sql.NullString{String: task.ChatID, Valid: task.ChatID != ""},
 		metaJSON,
 		task.LockedAt, task.LockExpiresAt,
+		task.DueAt, task.SLAMinutes, task.AtRiskAt,
 		now, now, tenantIDForInsert(ctx),
 	)
 	if err != nil {
@@ -166,6 +169,12 @@ var allowedTaskUpdateCols = map[string]bool{
 	"metadata":         true,
 	"blocked_by":       true,
 	"updated_at":       true,
+	// Due date / SLA (set together by the tool and RPC layers).
+	"due_at":             true,
+	"sla_minutes":        true,
+	"at_risk_at":         true,
+	"escalation_count":   true,
+	"next_escalation_at": true,
 }
 
 func (s *PGTeamStore) UpdateTask(ctx context.Context, taskID uuid.UUID, updates map[string]any) error {
@@ -219,6 +228,10 @@ func (s *PGTeamStore) ListTasks(ctx context.Context, teamID uuid.UUID, orderBy s
 		statusWhere = "AND t.status = 'in_review'"
 	case store.TeamTaskFilterCompleted:
 		statusWhere = "AND t.status IN ('completed','cancelled')"
+	case store.TeamTaskFilterOverdue:
+		statusWhere = "AND t.status NOT IN ('completed','cancelled') AND t.due_at IS NOT NULL AND t.due_at <= NOW()"
+	case store.TeamTaskFilterAtRisk:
+		statusWhere = "AND t.status NOT IN ('completed','cancelled') AND t.at_risk_at IS NOT NULL AND t.at_risk_at <= NOW() AND t.due_at > NOW()"
 	// "", store.TeamTaskFilterAll ("all") → no filter (all statuses)
 	}
 
@@ -500,6 +513,7 @@ func scanTaskRowsJoined(rows *sql.Rows) ([]store.TeamTaskData, error) {
 		var lockedAt, lockExpiresAt, followupAt *time.Time
 		var followupCount, followupMax int
 		var followupMessage, followupChannel, followupChatID string
+		var dueAt, atRiskAt, nextEscalationAt *time.Time
 		if err := rows.Scan(
 			&d.ID, &d.TeamID, &d.Subject, &desc, &d.Status,
 			&ownerID, pq.Array(&blockedBy), &d.Priority, &result,
@@ -507,6 +521,7 @@ func scanTaskRowsJoined(rows *sql.Rows) ([]store.TeamTaskData, error) {
 			&d.TaskType, &d.TaskNumber, &identifier, &createdByAgentID, &assigneeUserID, &parentID,
 			&chatID, &metadataJSON, &lockedAt, &lockExpiresAt, &d.ProgressPercent, &progressStep,
 			&followupAt, &followupCount, &followupMax, &followupMessage, &followupChannel, &followupChatID,
+			&dueAt, &d.SLAMinutes, &atRiskAt, &d.EscalationCount, &nextEscalationAt,
 			&d.CommentCount, &d.AttachmentCount,
 			&d.CreatedAt, &d.UpdatedAt,
 			&d.OwnerAgentKey,
@@ -545,6 +560,9 @@ func scanTaskRowsJoined(rows *sql.Rows) ([]store.TeamTaskData, error) {
 		d.FollowupMessage = followupMessage
 		d.FollowupChannel = followupChannel
 		d.FollowupChatID = followupChatID
+		d.DueAt = dueAt
+		d.AtRiskAt = atRiskAt
+		d.NextEscalationAt = nextEscalationAt
 		tasks = append(tasks, d)
 	}
 	return tasks, rows.Err()
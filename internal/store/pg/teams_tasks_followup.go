@@ -136,3 +136,39 @@ func (s *PGTeamStore) HasActiveMemberTasks(ctx context.Context, teamID uuid.UUID
 	).Scan(&exists)
 	return exists, err
 }
+
+// ============================================================
+// Overdue escalation
+// ============================================================
+
+func (s *PGTeamStore) ListAllOverdueTasks(ctx context.Context) ([]store.RecoveredTaskInfo, error) {
+	now := time.Now()
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT t.id, t.team_id, t.tenant_id, t.task_number, t.subject, COALESCE(t.channel, ''), COALESCE(t.chat_id, '')
+		 FROM team_tasks t
+		 `+v2ActiveTeamJoin+`
+		 WHERE t.due_at IS NOT NULL
+		   AND t.due_at <= $1
+		   AND t.status NOT IN ($2, $3)
+		   AND ((t.escalation_count = 0 AND t.next_escalation_at IS NULL)
+		        OR t.next_escalation_at <= $1)
+		 ORDER BY t.due_at
+		 LIMIT 100`,
+		now, store.TeamTaskStatusCompleted, store.TeamTaskStatusCancelled,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRecoveredTaskInfoRows(rows)
+}
+
+func (s *PGTeamStore) MarkTaskEscalated(ctx context.Context, taskID uuid.UUID, nextAt *time.Time) error {
+	tid := tenantIDForInsert(ctx)
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE team_tasks SET escalation_count = escalation_count + 1, next_escalation_at = $1, updated_at = $2
+		 WHERE id = $3 AND tenant_id = $4`,
+		nextAt, time.Now(), taskID, tid,
+	)
+	return err
+}
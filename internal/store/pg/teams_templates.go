@@ -0,0 +1,201 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// ============================================================
+// Task templates
+// ============================================================
+
+const templateSelectCols = `tt.id, tt.team_id, tt.tenant_id, tt.name, tt.subject, tt.description, tt.owner_agent_id,
+		 tt.priority, tt.subtasks, tt.schedule, tt.timezone, tt.enabled, tt.next_run_at, tt.last_run_at, tt.run_count,
+		 tt.created_by, tt.created_at, tt.updated_at, COALESCE(a.agent_key, '')`
+
+const templateJoinClause = `FROM team_task_templates tt
+		 LEFT JOIN agents a ON a.id = tt.owner_agent_id`
+
+func (s *PGTeamStore) CreateTaskTemplate(ctx context.Context, tpl *store.TeamTaskTemplateData) error {
+	if tpl.ID == uuid.Nil {
+		tpl.ID = store.GenNewID()
+	}
+	now := time.Now()
+	tpl.CreatedAt = now
+	tpl.UpdatedAt = now
+	tpl.TenantID = tenantIDForInsert(ctx)
+
+	subtasks, err := marshalTemplateSubtasks(tpl.Subtasks)
+	if err != nil {
+		return fmt.Errorf("marshal subtasks: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO team_task_templates (id, team_id, name, subject, description, owner_agent_id, priority, subtasks,
+		 schedule, timezone, enabled, next_run_at, created_by, created_at, updated_at, tenant_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
+		tpl.ID, tpl.TeamID, tpl.Name, tpl.Subject, tpl.Description, tpl.OwnerAgentID, tpl.Priority,
+		subtasks, tpl.Schedule, tpl.Timezone, tpl.Enabled, tpl.NextRunAt,
+		tpl.CreatedBy, now, now, tpl.TenantID,
+	)
+	return err
+}
+
+func (s *PGTeamStore) GetTaskTemplate(ctx context.Context, templateID uuid.UUID) (*store.TeamTaskTemplateData, error) {
+	args := []any{templateID}
+	tenantWhere := ""
+	if !store.IsCrossTenant(ctx) {
+		tid := store.TenantIDFromContext(ctx)
+		if tid == uuid.Nil {
+			return nil, fmt.Errorf("tenant_id required")
+		}
+		tenantWhere = " AND tt.tenant_id = $2"
+		args = append(args, tid)
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+templateSelectCols+`
+		 `+templateJoinClause+`
+		 WHERE tt.id = $1`+tenantWhere, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tpls, err := scanTemplateRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(tpls) == 0 {
+		return nil, store.ErrTaskTemplateNotFound
+	}
+	return &tpls[0], nil
+}
+
+func (s *PGTeamStore) ListTaskTemplates(ctx context.Context, teamID uuid.UUID) ([]store.TeamTaskTemplateData, error) {
+	args := []any{teamID}
+	tenantWhere := ""
+	if !store.IsCrossTenant(ctx) {
+		tid := store.TenantIDFromContext(ctx)
+		if tid == uuid.Nil {
+			return nil, fmt.Errorf("tenant_id required")
+		}
+		tenantWhere = " AND tt.tenant_id = $2"
+		args = append(args, tid)
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+templateSelectCols+`
+		 `+templateJoinClause+`
+		 WHERE tt.team_id = $1`+tenantWhere+`
+		 ORDER BY tt.name`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTemplateRows(rows)
+}
+
+func (s *PGTeamStore) UpdateTaskTemplate(ctx context.Context, tpl *store.TeamTaskTemplateData) error {
+	subtasks, err := marshalTemplateSubtasks(tpl.Subtasks)
+	if err != nil {
+		return fmt.Errorf("marshal subtasks: %w", err)
+	}
+	tpl.UpdatedAt = time.Now()
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE team_task_templates
+		 SET name = $1, subject = $2, description = $3, owner_agent_id = $4, priority = $5, subtasks = $6,
+		     schedule = $7, timezone = $8, enabled = $9, next_run_at = $10, updated_at = $11
+		 WHERE id = $12 AND team_id = $13 AND tenant_id = $14`,
+		tpl.Name, tpl.Subject, tpl.Description, tpl.OwnerAgentID, tpl.Priority, subtasks,
+		tpl.Schedule, tpl.Timezone, tpl.Enabled, tpl.NextRunAt, tpl.UpdatedAt,
+		tpl.ID, tpl.TeamID, tenantIDForInsert(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrTaskTemplateNotFound
+	}
+	return nil
+}
+
+func (s *PGTeamStore) DeleteTaskTemplate(ctx context.Context, templateID, teamID uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM team_task_templates WHERE id = $1 AND team_id = $2 AND tenant_id = $3`,
+		templateID, teamID, tenantIDForInsert(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrTaskTemplateNotFound
+	}
+	return nil
+}
+
+// ListDueTaskTemplates returns enabled templates with a due next_run_at across all v2 active teams.
+func (s *PGTeamStore) ListDueTaskTemplates(ctx context.Context, now time.Time) ([]store.TeamTaskTemplateData, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+templateSelectCols+`
+		 `+templateJoinClause+`
+		 JOIN agent_teams tm ON tm.id = tt.team_id
+		   AND tm.status = 'active'
+		   AND COALESCE((tm.settings->>'version')::int, 0) >= 2
+		 WHERE tt.enabled AND tt.next_run_at IS NOT NULL AND tt.next_run_at <= $1
+		 ORDER BY tt.next_run_at
+		 LIMIT 100`,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTemplateRows(rows)
+}
+
+func (s *PGTeamStore) MarkTaskTemplateRun(ctx context.Context, templateID uuid.UUID, ranAt time.Time, nextRunAt *time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE team_task_templates SET last_run_at = $1, next_run_at = $2, run_count = run_count + 1, updated_at = $3
+		 WHERE id = $4 AND tenant_id = $5`,
+		ranAt, nextRunAt, time.Now(), templateID, tenantIDForInsert(ctx),
+	)
+	return err
+}
+
+func marshalTemplateSubtasks(subtasks []store.TeamTaskTemplateSubtask) ([]byte, error) {
+	if len(subtasks) == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(subtasks)
+}
+
+func scanTemplateRows(rows *sql.Rows) ([]store.TeamTaskTemplateData, error) {
+	var tpls []store.TeamTaskTemplateData
+	for rows.Next() {
+		var d store.TeamTaskTemplateData
+		var subtasksJSON []byte
+		var nextRunAt, lastRunAt sql.NullTime
+		if err := rows.Scan(
+			&d.ID, &d.TeamID, &d.TenantID, &d.Name, &d.Subject, &d.Description, &d.OwnerAgentID,
+			&d.Priority, &subtasksJSON, &d.Schedule, &d.Timezone, &d.Enabled, &nextRunAt, &lastRunAt, &d.RunCount,
+			&d.CreatedBy, &d.CreatedAt, &d.UpdatedAt, &d.OwnerAgentKey,
+		); err != nil {
+			return nil, err
+		}
+		if len(subtasksJSON) > 0 {
+			_ = json.Unmarshal(subtasksJSON, &d.Subtasks)
+		}
+		if nextRunAt.Valid {
+			d.NextRunAt = &nextRunAt.Time
+		}
+		if lastRunAt.Valid {
+			d.LastRunAt = &lastRunAt.Time
+		}
+		tpls = append(tpls, d)
+	}
+	return tpls, rows.Err()
+}
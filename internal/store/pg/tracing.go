@@ -548,6 +548,43 @@ func (s *PGTracingStore) GetCostSummary(ctx context.Context, opts store.CostSumm
 	return result, nil
 }
 
+// GetSpend sums LLM span costs matching q. The user filter joins the owning
+// trace since spans do not carry user_id.
+func (s *PGTracingStore) GetSpend(ctx context.Context, q store.SpendQuery) (float64, error) {
+	conditions := []string{"s.total_cost IS NOT NULL", "s.created_at >= $1"}
+	args := []any{q.From}
+
+	tenantID := q.TenantID
+	if tenantID == uuid.Nil && !store.IsCrossTenant(ctx) {
+		tenantID = store.TenantIDFromContext(ctx)
+	}
+	if tenantID != uuid.Nil {
+		args = append(args, tenantID)
+		conditions = append(conditions, fmt.Sprintf("s.tenant_id = $%d", len(args)))
+	}
+	if !q.To.IsZero() {
+		args = append(args, q.To)
+		conditions = append(conditions, fmt.Sprintf("s.created_at < $%d", len(args)))
+	}
+	if q.AgentID != nil {
+		args = append(args, *q.AgentID)
+		conditions = append(conditions, fmt.Sprintf("s.agent_id = $%d", len(args)))
+	}
+	from := "spans s"
+	if q.UserID != "" {
+		from = "spans s JOIN traces t ON t.id = s.trace_id"
+		args = append(args, q.UserID)
+		conditions = append(conditions, fmt.Sprintf("t.user_id = $%d", len(args)))
+	}
+
+	var cost float64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(s.total_cost), 0) FROM `+from+` WHERE `+strings.Join(conditions, " AND "),
+		args...,
+	).Scan(&cost)
+	return cost, err
+}
+
 // DeleteTracesOlderThan deletes traces and their spans older than cutoff.
 // Spans are deleted first (FK), then traces. Returns total traces deleted.
 func (s *PGTracingStore) DeleteTracesOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
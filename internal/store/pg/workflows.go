@@ -0,0 +1,332 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// PGWorkflowStore implements store.WorkflowStore backed by Postgres.
+type PGWorkflowStore struct {
+	db *sql.DB
+}
+
+func NewPGWorkflowStore(db *sql.DB) *PGWorkflowStore {
+	return &PGWorkflowStore{db: db}
+}
+
+// ============================================================
+// Workflows
+// ============================================================
+
+const workflowSelectCols = `id, team_id, tenant_id, name, description, definition, enabled, created_by, created_at, updated_at`
+
+func (s *PGWorkflowStore) CreateWorkflow(ctx context.Context, wf *store.WorkflowData) error {
+	if wf.ID == uuid.Nil {
+		wf.ID = store.GenNewID()
+	}
+	now := time.Now()
+	wf.CreatedAt = now
+	wf.UpdatedAt = now
+	wf.TenantID = tenantIDForInsert(ctx)
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO team_workflows (id, team_id, name, description, definition, enabled, created_by, created_at, updated_at, tenant_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		wf.ID, wf.TeamID, wf.Name, wf.Description, jsonOrEmpty(wf.Definition), wf.Enabled, wf.CreatedBy, now, now, wf.TenantID,
+	)
+	return err
+}
+
+func (s *PGWorkflowStore) GetWorkflow(ctx context.Context, id uuid.UUID) (*store.WorkflowData, error) {
+	where, args, err := workflowTenantWhere(ctx, "tenant_id", "id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	wfs, err := s.queryWorkflows(ctx, `SELECT `+workflowSelectCols+` FROM team_workflows WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(wfs) == 0 {
+		return nil, store.ErrWorkflowNotFound
+	}
+	return &wfs[0], nil
+}
+
+func (s *PGWorkflowStore) GetWorkflowByName(ctx context.Context, teamID uuid.UUID, name string) (*store.WorkflowData, error) {
+	where, args, err := workflowTenantWhere(ctx, "tenant_id", "team_id = $1 AND LOWER(name) = LOWER($2)", teamID, name)
+	if err != nil {
+		return nil, err
+	}
+	wfs, err := s.queryWorkflows(ctx, `SELECT `+workflowSelectCols+` FROM team_workflows WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(wfs) == 0 {
+		return nil, store.ErrWorkflowNotFound
+	}
+	return &wfs[0], nil
+}
+
+func (s *PGWorkflowStore) ListWorkflows(ctx context.Context, teamID uuid.UUID) ([]store.WorkflowData, error) {
+	where, args, err := workflowTenantWhere(ctx, "tenant_id", "team_id = $1", teamID)
+	if err != nil {
+		return nil, err
+	}
+	return s.queryWorkflows(ctx, `SELECT `+workflowSelectCols+` FROM team_workflows WHERE `+where+` ORDER BY name`, args...)
+}
+
+func (s *PGWorkflowStore) UpdateWorkflow(ctx context.Context, wf *store.WorkflowData) error {
+	wf.UpdatedAt = time.Now()
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE team_workflows SET name = $1, description = $2, definition = $3, enabled = $4, updated_at = $5
+		 WHERE id = $6 AND team_id = $7 AND tenant_id = $8`,
+		wf.Name, wf.Description, jsonOrEmpty(wf.Definition), wf.Enabled, wf.UpdatedAt,
+		wf.ID, wf.TeamID, tenantIDForInsert(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrWorkflowNotFound
+	}
+	return nil
+}
+
+func (s *PGWorkflowStore) DeleteWorkflow(ctx context.Context, id, teamID uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM team_workflows WHERE id = $1 AND team_id = $2 AND tenant_id = $3`,
+		id, teamID, tenantIDForInsert(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrWorkflowNotFound
+	}
+	return nil
+}
+
+func (s *PGWorkflowStore) queryWorkflows(ctx context.Context, query string, args ...any) ([]store.WorkflowData, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var wfs []store.WorkflowData
+	for rows.Next() {
+		var d store.WorkflowData
+		var def []byte
+		if err := rows.Scan(&d.ID, &d.TeamID, &d.TenantID, &d.Name, &d.Description, &def, &d.Enabled,
+			&d.CreatedBy, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return nil, err
+		}
+		d.Definition = json.RawMessage(def)
+		wfs = append(wfs, d)
+	}
+	return wfs, rows.Err()
+}
+
+// ============================================================
+// Workflow runs
+// ============================================================
+
+const workflowRunSelectCols = `r.id, r.workflow_id, r.team_id, r.tenant_id, r.status, r.trigger_kind, r.triggered_by,
+		 r.input, r.definition, r.steps, r.output, r.error, r.trace_id, r.started_at, r.finished_at,
+		 r.created_at, r.updated_at, COALESCE(w.name, '')`
+
+const workflowRunJoinClause = `FROM team_workflow_runs r
+		 LEFT JOIN team_workflows w ON w.id = r.workflow_id`
+
+func (s *PGWorkflowStore) CreateWorkflowRun(ctx context.Context, run *store.WorkflowRunData) error {
+	if run.ID == uuid.Nil {
+		run.ID = store.GenNewID()
+	}
+	now := time.Now()
+	run.CreatedAt = now
+	run.UpdatedAt = now
+	if run.StartedAt.IsZero() {
+		run.StartedAt = now
+	}
+	run.TenantID = tenantIDForInsert(ctx)
+	input, steps, err := marshalWorkflowRunJSON(run)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO team_workflow_runs (id, workflow_id, team_id, status, trigger_kind, triggered_by, input, definition, steps,
+		 output, error, trace_id, started_at, finished_at, created_at, updated_at, tenant_id)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`,
+		run.ID, run.WorkflowID, run.TeamID, run.Status, run.Trigger, run.TriggeredBy, input, jsonOrEmpty(run.Definition), steps,
+		run.Output, run.Error, nilUUID(run.TraceID), run.StartedAt, nilTime(run.FinishedAt), now, now, run.TenantID,
+	)
+	return err
+}
+
+func (s *PGWorkflowStore) GetWorkflowRun(ctx context.Context, id uuid.UUID) (*store.WorkflowRunData, error) {
+	where, args, err := workflowTenantWhere(ctx, "r.tenant_id", "r.id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := s.queryRuns(ctx, `SELECT `+workflowRunSelectCols+` `+workflowRunJoinClause+` WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, store.ErrWorkflowRunNotFound
+	}
+	return &runs[0], nil
+}
+
+func (s *PGWorkflowStore) ListWorkflowRuns(ctx context.Context, teamID, workflowID uuid.UUID, limit int) ([]store.WorkflowRunData, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	cond, args := "r.team_id = $1", []any{teamID}
+	if workflowID != uuid.Nil {
+		cond, args = "r.team_id = $1 AND r.workflow_id = $2", []any{teamID, workflowID}
+	}
+	where, args, err := workflowTenantWhere(ctx, "r.tenant_id", cond, args...)
+	if err != nil {
+		return nil, err
+	}
+	return s.queryRuns(ctx,
+		`SELECT `+workflowRunSelectCols+` `+workflowRunJoinClause+` WHERE `+where+
+			fmt.Sprintf(` ORDER BY r.created_at DESC LIMIT %d`, limit), args...)
+}
+
+func (s *PGWorkflowStore) UpdateWorkflowRun(ctx context.Context, run *store.WorkflowRunData) error {
+	return updateWorkflowRun(ctx, s.db, run)
+}
+
+func (s *PGWorkflowStore) LockWorkflowRun(ctx context.Context, id uuid.UUID, fn func(run *store.WorkflowRunData) error) (*store.WorkflowRunData, error) {
+	where, args, err := workflowTenantWhere(ctx, "r.tenant_id", "r.id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	runs, err := queryWorkflowRuns(ctx, tx,
+		`SELECT `+workflowRunSelectCols+` `+workflowRunJoinClause+` WHERE `+where+` FOR UPDATE OF r`, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, store.ErrWorkflowRunNotFound
+	}
+	run := &runs[0]
+	if err := fn(run); err != nil {
+		return run, err
+	}
+	if err := updateWorkflowRun(ctx, tx, run); err != nil {
+		return run, err
+	}
+	return run, tx.Commit()
+}
+
+// workflowRunDB is satisfied by both *sql.DB and *sql.Tx.
+type workflowRunDB interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func updateWorkflowRun(ctx context.Context, db workflowRunDB, run *store.WorkflowRunData) error {
+	_, steps, err := marshalWorkflowRunJSON(run)
+	if err != nil {
+		return err
+	}
+	run.UpdatedAt = time.Now()
+	res, err := db.ExecContext(ctx,
+		`UPDATE team_workflow_runs SET status = $1, steps = $2, output = $3, error = $4, trace_id = $5, finished_at = $6, updated_at = $7
+		 WHERE id = $8 AND tenant_id = $9`,
+		run.Status, steps, run.Output, run.Error, nilUUID(run.TraceID), nilTime(run.FinishedAt), run.UpdatedAt,
+		run.ID, tenantIDForInsert(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrWorkflowRunNotFound
+	}
+	return nil
+}
+
+func (s *PGWorkflowStore) ListActiveWorkflowRuns(ctx context.Context) ([]store.WorkflowRunData, error) {
+	return s.queryRuns(ctx,
+		`SELECT `+workflowRunSelectCols+` `+workflowRunJoinClause+`
+		 WHERE r.status = $1
+		 ORDER BY r.started_at
+		 LIMIT 200`, store.WorkflowRunStatusRunning)
+}
+
+func (s *PGWorkflowStore) queryRuns(ctx context.Context, query string, args ...any) ([]store.WorkflowRunData, error) {
+	return queryWorkflowRuns(ctx, s.db, query, args...)
+}
+
+func queryWorkflowRuns(ctx context.Context, db workflowRunDB, query string, args ...any) ([]store.WorkflowRunData, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var runs []store.WorkflowRunData
+	for rows.Next() {
+		var d store.WorkflowRunData
+		var input, def, steps []byte
+		var traceID *uuid.UUID
+		var finishedAt *time.Time
+		if err := rows.Scan(&d.ID, &d.WorkflowID, &d.TeamID, &d.TenantID, &d.Status, &d.Trigger, &d.TriggeredBy,
+			&input, &def, &steps, &d.Output, &d.Error, &traceID, &d.StartedAt, &finishedAt,
+			&d.CreatedAt, &d.UpdatedAt, &d.WorkflowName); err != nil {
+			return nil, err
+		}
+		d.TraceID = traceID
+		d.FinishedAt = finishedAt
+		d.Definition = json.RawMessage(def)
+		_ = json.Unmarshal(input, &d.Input)
+		_ = json.Unmarshal(steps, &d.Steps)
+		runs = append(runs, d)
+	}
+	return runs, rows.Err()
+}
+
+// workflowTenantWhere appends "AND <col> = $n" tenant scoping to cond, whose
+// placeholders are $1..$len(args). Cross-tenant contexts are left unscoped.
+func workflowTenantWhere(ctx context.Context, col, cond string, args ...any) (string, []any, error) {
+	if store.IsCrossTenant(ctx) {
+		return cond, args, nil
+	}
+	tid := store.TenantIDFromContext(ctx)
+	if tid == uuid.Nil {
+		return "", nil, fmt.Errorf("tenant_id required")
+	}
+	args = append(args, tid)
+	return cond + fmt.Sprintf(" AND %s = $%d", col, len(args)), args, nil
+}
+
+func marshalWorkflowRunJSON(run *store.WorkflowRunData) (input, steps []byte, err error) {
+	in := run.Input
+	if in == nil {
+		in = map[string]string{}
+	}
+	if input, err = json.Marshal(in); err != nil {
+		return nil, nil, fmt.Errorf("marshal input: %w", err)
+	}
+	st := run.Steps
+	if st == nil {
+		st = map[string]*store.WorkflowStepState{}
+	}
+	if steps, err = json.Marshal(st); err != nil {
+		return nil, nil, fmt.Errorf("marshal steps: %w", err)
+	}
+	return input, steps, nil
+}
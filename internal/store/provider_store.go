@@ -3,8 +3,11 @@ package store
 import (
 	"context"
 	"encoding/json"
+	"strings"
 
 	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
 )
 
 // Provider type constants.
@@ -97,6 +100,36 @@ type EmbeddingSettings struct {
 	Model      string `json:"model,omitempty"`      // e.g. "text-embedding-3-small"
 	APIBase    string `json:"api_base,omitempty"`   // override if embedding endpoint differs from chat
 	Dimensions int    `json:"dimensions,omitempty"` // truncate output to N dims (e.g. 1536); 0 = model default
+	// API selects the embedding wire format (memory.EmbeddingAPI*); empty =
+	// the provider type's native API. Set it for e.g. a Voyage endpoint
+	// registered as an OpenAI-compatible provider.
+	API               string `json:"api,omitempty"`
+	RequestsPerMinute int    `json:"requests_per_minute,omitempty"` // embedding request rate cap; 0 = unlimited
+}
+
+// EmbeddingAPIFor returns the embedding API to use for a provider: the
+// explicit settings.embedding.api, else the provider type's native API,
+// else the OpenAI-compatible API.
+func EmbeddingAPIFor(providerType, apiBase string, es *EmbeddingSettings) string {
+	if es != nil && es.API != "" {
+		return es.API
+	}
+	switch providerType {
+	case ProviderGeminiNative:
+		return memory.EmbeddingAPIGemini
+	case ProviderCohere:
+		return memory.EmbeddingAPICohere
+	case ProviderMistral:
+		return memory.EmbeddingAPIMistral
+	case ProviderDashScope:
+		return memory.EmbeddingAPIDashScope
+	case ProviderOllama, ProviderOllamaCloud:
+		return memory.EmbeddingAPIOllama
+	}
+	if strings.Contains(apiBase, "voyageai.com") {
+		return memory.EmbeddingAPIVoyage
+	}
+	return memory.EmbeddingAPIOpenAI
 }
 
 // ProviderReasoningConfig holds provider-owned default reasoning settings.
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/tokenizer"
 )
 
 // SessionData holds conversation state for one session.
@@ -75,11 +76,31 @@ type SessionInfoRich struct {
 	InputTokens     int64  `json:"inputTokens,omitempty"`
 	OutputTokens    int64  `json:"outputTokens,omitempty"`
 	AgentName       string `json:"agentName,omitempty"`
-	EstimatedTokens int    `json:"estimatedTokens,omitempty"` // estimated current context tokens (messages bytes/4 + 12k system prompt)
+	EstimatedTokens int    `json:"estimatedTokens,omitempty"` // estimated prompt tokens of the next turn, as of the last save (EstimateSessionTokens)
 	ContextWindow   int    `json:"contextWindow,omitempty"`   // agent's context window size
 	CompactionCount int    `json:"compactionCount,omitempty"` // number of compactions performed
 }
 
+// SessionPromptOverhead approximates the system prompt and tool definitions
+// sent with every turn, added to uncalibrated estimates.
+const SessionPromptOverhead = 12000
+
+// EstimateSessionTokens estimates a session's prompt tokens: system prompt,
+// tools and history. With calibration data (the last LLM call's actual prompt
+// tokens) only the messages added since are counted with the model's
+// tokenizer; otherwise the history is counted and SessionPromptOverhead
+// stands in for the rest. Stores persist it on save for cheap listing.
+func EstimateSessionTokens(data *SessionData) int {
+	if len(data.Messages) == 0 {
+		return 0
+	}
+	tok := tokenizer.ForModel(data.Provider, data.Model)
+	if data.LastPromptTokens > 0 && data.LastMessageCount > 0 {
+		return tokenizer.CountCalibrated(tok, data.Messages, data.LastPromptTokens, data.LastMessageCount)
+	}
+	return tokenizer.CountMessages(tok, data.Messages) + SessionPromptOverhead
+}
+
 // SessionListRichResult is the paginated result of ListPagedRich.
 type SessionListRichResult struct {
 	Sessions []SessionInfoRich `json:"sessions"`
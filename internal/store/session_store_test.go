@@ -0,0 +1,30 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/tokenizer"
+)
+
+func TestEstimateSessionTokens(t *testing.T) {
+	msgs := []providers.Message{
+		{Role: "user", Content: "What changed in the release?"},
+		{Role: "assistant", Content: "The scheduler now serializes runs per session."},
+	}
+	if got := EstimateSessionTokens(&SessionData{}); got != 0 {
+		t.Errorf("empty session = %d, want 0", got)
+	}
+
+	data := &SessionData{Messages: msgs, Provider: "openai", Model: "gpt-4o"}
+	history := tokenizer.CountMessages(tokenizer.ForModel("openai", "gpt-4o"), msgs)
+	if got := EstimateSessionTokens(data); got != history+SessionPromptOverhead {
+		t.Errorf("uncalibrated = %d, want history %d + overhead", got, history)
+	}
+
+	// The calibrated count already includes system prompt and tools.
+	data.LastPromptTokens, data.LastMessageCount = 15000, 2
+	if got := EstimateSessionTokens(data); got != 15000 {
+		t.Errorf("calibrated = %d, want 15000", got)
+	}
+}
@@ -0,0 +1,297 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SQLiteAgentLinkStore implements store.AgentLinkStore backed by SQLite.
+type SQLiteAgentLinkStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteAgentLinkStore(db *sql.DB) *SQLiteAgentLinkStore {
+	return &SQLiteAgentLinkStore{db: db}
+}
+
+const linkSelectCols = `id, source_agent_id, target_agent_id, direction, team_id, description,
+	max_concurrent, settings, status, created_by, created_at, updated_at`
+
+// linkSelectColsJoined prefixes every column with l. to avoid ambiguity in JOINs.
+const linkSelectColsJoined = `l.id, l.source_agent_id, l.target_agent_id, l.direction, l.team_id, l.description,
+	l.max_concurrent, l.settings, l.status, l.created_by, l.created_at, l.updated_at`
+
+// linkJoinedColsFixedTarget resolves joined columns when the target side of the link is fixed.
+const linkJoinedColsFixedTarget = `sa.agent_key AS source_agent_key,
+	 ta.agent_key AS target_agent_key,
+	 COALESCE(ta.display_name, '') AS target_display_name,
+	 COALESCE(ta.frontmatter, '') AS target_description,
+	 COALESCE(tm.name, '') AS team_name,
+	 EXISTS(SELECT 1 FROM agent_teams tl WHERE tl.lead_agent_id = l.target_agent_id AND tl.status = 'active') AS target_is_team_lead,
+	 COALESCE((SELECT tl.name FROM agent_teams tl WHERE tl.lead_agent_id = l.target_agent_id AND tl.status = 'active' LIMIT 1), '') AS target_team_name`
+
+// linkJoinedColsOtherSide resolves joined columns relative to the delegating agent:
+// "target" always refers to the other side of the link. Uses ?1 = fromAgentID.
+const linkJoinedColsOtherSide = `CASE WHEN l.source_agent_id = ?1 THEN sa.agent_key ELSE ta.agent_key END AS source_agent_key,
+	 CASE WHEN l.source_agent_id = ?1 THEN ta.agent_key ELSE sa.agent_key END AS target_agent_key,
+	 CASE WHEN l.source_agent_id = ?1 THEN COALESCE(ta.display_name, '') ELSE COALESCE(sa.display_name, '') END AS target_display_name,
+	 CASE WHEN l.source_agent_id = ?1 THEN COALESCE(ta.frontmatter, '') ELSE COALESCE(sa.frontmatter, '') END AS target_description,
+	 COALESCE(tm.name, '') AS team_name,
+	 EXISTS(
+		SELECT 1 FROM agent_teams tl
+		WHERE tl.lead_agent_id = CASE WHEN l.source_agent_id = ?1 THEN l.target_agent_id ELSE l.source_agent_id END
+		  AND tl.status = 'active'
+	 ) AS target_is_team_lead,
+	 COALESCE((
+		SELECT tl.name FROM agent_teams tl
+		WHERE tl.lead_agent_id = CASE WHEN l.source_agent_id = ?1 THEN l.target_agent_id ELSE l.source_agent_id END
+		  AND tl.status = 'active'
+		LIMIT 1
+	 ), '') AS target_team_name`
+
+const linkJoins = ` FROM agent_links l
+	 JOIN agents sa ON sa.id = l.source_agent_id
+	 JOIN agents ta ON ta.id = l.target_agent_id
+	 LEFT JOIN agent_teams tm ON tm.id = l.team_id`
+
+// linkDelegableWhere matches active links that let ?1 delegate to an active agent.
+const linkDelegableWhere = ` WHERE l.status = 'active'
+	   AND CASE WHEN l.source_agent_id = ?1 THEN ta.status ELSE sa.status END = 'active'
+	   AND (
+		(l.source_agent_id = ?1 AND l.direction IN ('outbound', 'bidirectional'))
+		OR
+		(l.target_agent_id = ?1 AND l.direction IN ('inbound', 'bidirectional'))
+	   )`
+
+func (s *SQLiteAgentLinkStore) CreateLink(ctx context.Context, link *store.AgentLinkData) error {
+	if link.ID == uuid.Nil {
+		link.ID = store.GenNewID()
+	}
+	now := time.Now().UTC()
+	link.CreatedAt = now
+	link.UpdatedAt = now
+
+	settings := link.Settings
+	if len(settings) == 0 {
+		settings = json.RawMessage(`{}`)
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO agent_links (id, source_agent_id, target_agent_id, direction, team_id, description,
+		 max_concurrent, settings, status, created_by, created_at, updated_at, tenant_id)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		link.ID, link.SourceAgentID, link.TargetAgentID, link.Direction, nilUUID(link.TeamID), link.Description,
+		link.MaxConcurrent, []byte(settings), link.Status, link.CreatedBy, now, now, tenantIDForInsert(ctx),
+	)
+	return err
+}
+
+func (s *SQLiteAgentLinkStore) DeleteLink(ctx context.Context, id uuid.UUID) error {
+	if store.IsCrossTenant(ctx) {
+		_, err := s.db.ExecContext(ctx, `DELETE FROM agent_links WHERE id = ?`, id)
+		return err
+	}
+	tid := store.TenantIDFromContext(ctx)
+	if tid == uuid.Nil {
+		return fmt.Errorf("tenant_id required for delete")
+	}
+	_, err := s.db.ExecContext(ctx, `DELETE FROM agent_links WHERE id = ? AND tenant_id = ?`, id, tid)
+	return err
+}
+
+func (s *SQLiteAgentLinkStore) UpdateLink(ctx context.Context, id uuid.UUID, updates map[string]any) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	updates["updated_at"] = time.Now().UTC()
+	if store.IsCrossTenant(ctx) {
+		return execMapUpdate(ctx, s.db, "agent_links", id, updates)
+	}
+	tid := store.TenantIDFromContext(ctx)
+	if tid == uuid.Nil {
+		return fmt.Errorf("tenant_id required for update")
+	}
+	return execMapUpdateWhere(ctx, s.db, "agent_links", updates, "id = ? AND tenant_id = ?", id, tid)
+}
+
+func (s *SQLiteAgentLinkStore) GetLink(ctx context.Context, id uuid.UUID) (*store.AgentLinkData, error) {
+	if store.IsCrossTenant(ctx) {
+		row := s.db.QueryRowContext(ctx,
+			`SELECT `+linkSelectCols+` FROM agent_links WHERE id = ?`, id)
+		return scanLinkRow(row)
+	}
+	tenantID := store.TenantIDFromContext(ctx)
+	if tenantID == uuid.Nil {
+		return nil, fmt.Errorf("link not found: %w", sql.ErrNoRows)
+	}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+linkSelectCols+` FROM agent_links WHERE id = ? AND tenant_id = ?`, id, tenantID)
+	return scanLinkRow(row)
+}
+
+func (s *SQLiteAgentLinkStore) ListLinksFrom(ctx context.Context, agentID uuid.UUID) ([]store.AgentLinkData, error) {
+	where, qArgs := linkTenantClause(ctx, agentID, "l.source_agent_id = ?")
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+linkSelectColsJoined+`, `+linkJoinedColsFixedTarget+linkJoins+`
+		 WHERE `+where+`
+		 ORDER BY l.created_at`, qArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanLinkRowsJoined(rows)
+}
+
+func (s *SQLiteAgentLinkStore) ListLinksTo(ctx context.Context, agentID uuid.UUID) ([]store.AgentLinkData, error) {
+	where, qArgs := linkTenantClause(ctx, agentID, "l.target_agent_id = ?")
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+linkSelectColsJoined+`, `+linkJoinedColsFixedTarget+linkJoins+`
+		 WHERE `+where+`
+		 ORDER BY l.created_at`, qArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanLinkRowsJoined(rows)
+}
+
+// linkTenantClause builds the WHERE clause for agent_links queries.
+// baseCondition must use a single ? for the agentID parameter.
+func linkTenantClause(ctx context.Context, agentID uuid.UUID, baseCondition string) (string, []any) {
+	args := []any{agentID}
+	if store.IsCrossTenant(ctx) {
+		return baseCondition, args
+	}
+	// uuid.Nil tenant is fail-closed: matches nothing.
+	return baseCondition + " AND l.tenant_id = ?", append(args, store.TenantIDFromContext(ctx))
+}
+
+func (s *SQLiteAgentLinkStore) CanDelegate(ctx context.Context, fromAgentID, toAgentID uuid.UUID) (bool, error) {
+	var exists bool
+	err := s.db.QueryRowContext(ctx,
+		`SELECT EXISTS(
+			SELECT 1 FROM agent_links WHERE status = 'active' AND (
+				(source_agent_id = ?1 AND target_agent_id = ?2 AND direction IN ('outbound', 'bidirectional'))
+				OR
+				(source_agent_id = ?2 AND target_agent_id = ?1 AND direction IN ('inbound', 'bidirectional'))
+			)
+		)`, fromAgentID, toAgentID).Scan(&exists)
+	return exists, err
+}
+
+func (s *SQLiteAgentLinkStore) DelegateTargets(ctx context.Context, fromAgentID uuid.UUID) ([]store.AgentLinkData, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+linkSelectColsJoined+`, `+linkJoinedColsOtherSide+linkJoins+linkDelegableWhere+`
+		 ORDER BY CASE WHEN l.source_agent_id = ?1 THEN ta.agent_key ELSE sa.agent_key END`, fromAgentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanLinkRowsJoined(rows)
+}
+
+func (s *SQLiteAgentLinkStore) GetLinkBetween(ctx context.Context, fromAgentID, toAgentID uuid.UUID) (*store.AgentLinkData, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+linkSelectCols+`
+		 FROM agent_links WHERE status = 'active' AND (
+			(source_agent_id = ?1 AND target_agent_id = ?2 AND direction IN ('outbound', 'bidirectional'))
+			OR
+			(source_agent_id = ?2 AND target_agent_id = ?1 AND direction IN ('inbound', 'bidirectional'))
+		 ) LIMIT 1`, fromAgentID, toAgentID)
+	d, err := scanLinkRow(row)
+	if err != nil {
+		return nil, nil // no link found
+	}
+	return d, nil
+}
+
+// SearchDelegateTargets matches the query against the other agent's key, display
+// name and frontmatter with LIKE (no tsvector in SQLite).
+func (s *SQLiteAgentLinkStore) SearchDelegateTargets(ctx context.Context, fromAgentID uuid.UUID, query string, limit int) ([]store.AgentLinkData, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+	pattern := "%" + escapeLike(query) + "%"
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+linkSelectColsJoined+`, `+linkJoinedColsOtherSide+linkJoins+linkDelegableWhere+`
+		   AND (
+		     CASE WHEN l.source_agent_id = ?1 THEN ta.agent_key ELSE sa.agent_key END LIKE ?2 ESCAPE '\'
+		     OR CASE WHEN l.source_agent_id = ?1 THEN COALESCE(ta.display_name, '') ELSE COALESCE(sa.display_name, '') END LIKE ?2 ESCAPE '\'
+		     OR CASE WHEN l.source_agent_id = ?1 THEN COALESCE(ta.frontmatter, '') ELSE COALESCE(sa.frontmatter, '') END LIKE ?2 ESCAPE '\'
+		   )
+		 ORDER BY CASE WHEN l.source_agent_id = ?1 THEN ta.agent_key ELSE sa.agent_key END
+		 LIMIT ?3`, fromAgentID, pattern, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanLinkRowsJoined(rows)
+}
+
+// SearchDelegateTargetsByEmbedding is not supported: SQLite agents carry no embeddings.
+// Returns nil so callers fall back to keyword search.
+func (s *SQLiteAgentLinkStore) SearchDelegateTargetsByEmbedding(_ context.Context, _ uuid.UUID, _ []float32, _ int) ([]store.AgentLinkData, error) {
+	return nil, nil
+}
+
+func (s *SQLiteAgentLinkStore) DeleteTeamLinksForAgent(ctx context.Context, teamID, agentID uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM agent_links WHERE team_id = ?1 AND (source_agent_id = ?2 OR target_agent_id = ?2)`,
+		teamID, agentID,
+	)
+	return err
+}
+
+// --- scan helpers ---
+
+func scanLinkRow(row *sql.Row) (*store.AgentLinkData, error) {
+	var d store.AgentLinkData
+	var desc sql.NullString
+	var settings []byte
+	createdAt, updatedAt := scanTimePair()
+	err := row.Scan(
+		&d.ID, &d.SourceAgentID, &d.TargetAgentID, &d.Direction, &d.TeamID, &desc,
+		&d.MaxConcurrent, &settings, &d.Status, &d.CreatedBy, createdAt, updatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+	d.Description = desc.String
+	d.Settings = settings
+	d.CreatedAt = createdAt.Time
+	d.UpdatedAt = updatedAt.Time
+	return &d, nil
+}
+
+func scanLinkRowsJoined(rows *sql.Rows) ([]store.AgentLinkData, error) {
+	var links []store.AgentLinkData
+	for rows.Next() {
+		var d store.AgentLinkData
+		var desc sql.NullString
+		var settings []byte
+		createdAt, updatedAt := scanTimePair()
+		if err := rows.Scan(
+			&d.ID, &d.SourceAgentID, &d.TargetAgentID, &d.Direction, &d.TeamID, &desc,
+			&d.MaxConcurrent, &settings, &d.Status, &d.CreatedBy, createdAt, updatedAt,
+			&d.SourceAgentKey, &d.TargetAgentKey, &d.TargetDisplayName, &d.TargetDescription,
+			&d.TeamName, &d.TargetIsTeamLead, &d.TargetTeamName,
+		); err != nil {
+			return nil, err
+		}
+		d.Description = desc.String
+		d.Settings = settings
+		d.CreatedAt = createdAt.Time
+		d.UpdatedAt = updatedAt.Time
+		links = append(links, d)
+	}
+	return links, rows.Err()
+}
@@ -72,6 +72,7 @@ func (s *SQLiteCronStore) Start() error {
 	s.stop = make(chan struct{})
 	s.running = true
 	s.recomputeStaleJobs()
+	s.applyCatchUp()
 	go s.runLoop()
 	slog.Info("sqlite cron service started")
 	return nil
@@ -113,6 +114,12 @@ func (s *SQLiteCronStore) emitEvent(event store.CronEvent) {
 
 // --- Scan helpers ---
 
+// cronJobColumns is the cron_jobs select list read by scanCronRow.
+const cronJobColumns = `id, tenant_id, agent_id, user_id, name, enabled, schedule_kind, cron_expression, run_at, timezone,
+		 interval_ms, payload, delete_after_run, stateless, deliver, deliver_channel, deliver_to, wake_heartbeat,
+		 next_run_at, last_run_at, last_status, last_error,
+		 created_at, updated_at, catch_up, catch_up_pending, after_job_id, deliver_if, chain_input, last_output`
+
 type cronRowScanner interface {
 	Scan(dest ...any) error
 }
@@ -131,11 +138,16 @@ func scanCronRow(row cronRowScanner) (*store.CronJob, error) {
 	var intervalMS *int64
 	var payloadJSON []byte
 	createdAt, updatedAt := scanTimePair()
+	var catchUp string
+	var catchUpPending int
+	var afterJobID *uuid.UUID
+	var deliverIf string
+	var chainInput, lastOutput *string
 
 	err := row.Scan(&id, &tenantID, &agentID, &userID, &name, &enabled, &scheduleKind, &cronExpr, &runAt, &tz,
 		&intervalMS, &payloadJSON, &deleteAfterRun, &stateless, &deliver, &deliverChannel, &deliverTo, &wakeHeartbeat,
 		&nextRunAt, &lastRunAt, &lastStatus, &lastError,
-		createdAt, updatedAt)
+		createdAt, updatedAt, &catchUp, &catchUpPending, &afterJobID, &deliverIf, &chainInput, &lastOutput)
 	if err != nil {
 		return nil, err
 	}
@@ -162,7 +174,10 @@ func scanCronRow(row cronRowScanner) (*store.CronJob, error) {
 		DeliverChannel: deliverChannel,
 		DeliverTo:      deliverTo,
 		WakeHeartbeat:  wakeHeartbeat,
+		CatchUp:        catchUp,
+		DeliverIf:      deliverIf,
 	}
+	job.State.CatchUpPending = catchUpPending
 
 	if agentID != nil {
 		job.AgentID = agentID.String()
@@ -183,6 +198,15 @@ func scanCronRow(row cronRowScanner) (*store.CronJob, error) {
 	if tz != nil {
 		job.Schedule.TZ = *tz
 	}
+	if afterJobID != nil {
+		job.Schedule.After = afterJobID.String()
+	}
+	if chainInput != nil {
+		job.State.Input = *chainInput
+	}
+	if lastOutput != nil {
+		job.State.LastOutput = *lastOutput
+	}
 	if nextRunAt.Valid {
 		ms := nextRunAt.Time.UnixMilli()
 		job.State.NextRunAtMS = &ms
@@ -207,10 +231,7 @@ func computeNextRun(schedule *store.CronSchedule, now time.Time, defaultTZ strin
 }
 
 func (s *SQLiteCronStore) scanJob(ctx context.Context, id uuid.UUID) (*store.CronJob, error) {
-	q := `SELECT id, tenant_id, agent_id, user_id, name, enabled, schedule_kind, cron_expression, run_at, timezone,
-		 interval_ms, payload, delete_after_run, stateless, deliver, deliver_channel, deliver_to, wake_heartbeat,
-		 next_run_at, last_run_at, last_status, last_error,
-		 created_at, updated_at FROM cron_jobs WHERE id = ?`
+	q := `SELECT ` + cronJobColumns + ` FROM cron_jobs WHERE id = ?`
 	args := []any{id}
 
 	if !store.IsCrossTenant(ctx) {
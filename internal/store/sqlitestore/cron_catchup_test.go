@@ -0,0 +1,76 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestSQLiteCronStore_CatchUpPolicies(t *testing.T) {
+	cronStore, ctx, db := newTestSQLiteCronStore(t)
+	hourMS := int64(time.Hour / time.Millisecond)
+	overdue := time.Now().Add(-2*time.Hour - time.Minute)
+
+	jobs := map[string]string{}
+	for _, policy := range []string{store.CronCatchUpSkip, store.CronCatchUpRunOnce, store.CronCatchUpRunAll} {
+		job, err := cronStore.AddJob(ctx, "job-"+policy, store.CronSchedule{Kind: "every", EveryMS: &hourMS},
+			"hello", false, "", "", "", "user-1")
+		if err != nil {
+			t.Fatalf("AddJob error: %v", err)
+		}
+		if _, err := cronStore.UpdateJob(ctx, job.ID, store.CronJobPatch{CatchUp: &policy}); err != nil {
+			t.Fatalf("UpdateJob catchUp error: %v", err)
+		}
+		if _, err := db.ExecContext(ctx, "UPDATE cron_jobs SET next_run_at = ? WHERE id = ?", overdue, uuid.MustParse(job.ID)); err != nil {
+			t.Fatalf("mark overdue error: %v", err)
+		}
+		jobs[policy] = job.ID
+	}
+
+	cronStore.applyCatchUp()
+
+	skip, _ := cronStore.GetJob(ctx, jobs[store.CronCatchUpSkip])
+	if skip.State.NextRunAtMS == nil || time.UnixMilli(*skip.State.NextRunAtMS).Before(time.Now()) {
+		t.Fatalf("skip: expected a future next run, got %v", skip.State.NextRunAtMS)
+	}
+	runOnce, _ := cronStore.GetJob(ctx, jobs[store.CronCatchUpRunOnce])
+	if runOnce.State.NextRunAtMS == nil || *runOnce.State.NextRunAtMS != overdue.UnixMilli() || runOnce.State.CatchUpPending != 0 {
+		t.Fatalf("run-once: expected overdue run kept with nothing pending, got %+v", runOnce.State)
+	}
+	runAll, _ := cronStore.GetJob(ctx, jobs[store.CronCatchUpRunAll])
+	if runAll.State.CatchUpPending != 2 {
+		t.Fatalf("run-all: pending = %d, want 2", runAll.State.CatchUpPending)
+	}
+
+	for policy, id := range jobs {
+		entries, _ := cronStore.GetRunLog(ctx, id, 10, 0)
+		if len(entries) != 1 || entries[0].Status != "catchup" || entries[0].CatchUp != policy || entries[0].Missed != 3 {
+			t.Fatalf("%s: run log = %+v, want one catchup entry with 3 missed", policy, entries)
+		}
+	}
+
+	// The overdue run fires first; each run then makes the next missed run due
+	// immediately until none are owed and the regular schedule resumes.
+	runAllID := uuid.MustParse(jobs[store.CronCatchUpRunAll])
+	handler := func(job *store.CronJob) (*store.CronJobResult, error) {
+		return &store.CronJobResult{Content: "ok"}, nil
+	}
+	for runs := 1; runs <= 3; runs++ {
+		if !cronStore.claimDueJob(runAllID, time.Now()) {
+			t.Fatalf("run %d: expected run-all job to be due", runs)
+		}
+		cronStore.executeOneJob(*runAll, handler)
+	}
+	current, _ := cronStore.GetJob(ctx, runAll.ID)
+	if current.State.CatchUpPending != 0 {
+		t.Fatalf("pending = %d after replays, want 0", current.State.CatchUpPending)
+	}
+	if cronStore.claimDueJob(runAllID, time.Now()) {
+		t.Fatal("expected regular schedule to resume after replays")
+	}
+}
@@ -0,0 +1,98 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestSQLiteCronStore_ChainedJobRunsWithUpstreamOutput(t *testing.T) {
+	cronStore, ctx, _ := newTestSQLiteCronStore(t)
+	hourMS := int64(time.Hour / time.Millisecond)
+
+	upstream, err := cronStore.AddJob(ctx, "fetch", store.CronSchedule{Kind: "every", EveryMS: &hourMS},
+		"fetch data", false, "", "", "", "user-1")
+	if err != nil {
+		t.Fatalf("AddJob upstream error: %v", err)
+	}
+	downstream, err := cronStore.AddJob(ctx, "summarize", store.CronSchedule{Kind: "after", After: upstream.ID},
+		"summarize", false, "", "", "", "user-1")
+	if err != nil {
+		t.Fatalf("AddJob downstream error: %v", err)
+	}
+	if downstream.Schedule.After != upstream.ID || downstream.State.NextRunAtMS != nil {
+		t.Fatalf("downstream = %+v, want linked to upstream with no scheduled run", downstream.Schedule)
+	}
+
+	// Chaining the upstream job after its own downstream would loop.
+	if _, err := cronStore.UpdateJob(ctx, upstream.ID, store.CronJobPatch{
+		Schedule: &store.CronSchedule{Kind: "after", After: downstream.ID},
+	}); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+	if _, err := cronStore.AddJob(ctx, "orphan", store.CronSchedule{Kind: "after", After: uuid.NewString()},
+		"x", false, "", "", "", "user-1"); err == nil {
+		t.Fatal("expected error for missing upstream job")
+	}
+
+	changed := "changed"
+	if _, err := cronStore.UpdateJob(ctx, downstream.ID, store.CronJobPatch{DeliverIf: &changed}); err != nil {
+		t.Fatalf("UpdateJob deliverIf error: %v", err)
+	}
+	bad := "sometimes"
+	if _, err := cronStore.UpdateJob(ctx, downstream.ID, store.CronJobPatch{DeliverIf: &bad}); err == nil {
+		t.Fatal("expected invalid deliverIf error")
+	}
+
+	var gotInput string
+	handler := func(job *store.CronJob) (*store.CronJobResult, error) {
+		if job.ID == downstream.ID {
+			gotInput = job.State.Input
+			return &store.CronJobResult{Content: "summary"}, nil
+		}
+		return &store.CronJobResult{Content: "raw data"}, nil
+	}
+
+	upstreamID := uuid.MustParse(upstream.ID)
+	if _, err := cronStore.db.ExecContext(ctx, "UPDATE cron_jobs SET next_run_at = ? WHERE id = ?", time.Now(), upstreamID); err != nil {
+		t.Fatalf("mark due error: %v", err)
+	}
+	if !cronStore.claimDueJob(upstreamID, time.Now()) {
+		t.Fatal("expected upstream job to be due")
+	}
+	cronStore.executeOneJob(*upstream, handler)
+
+	triggered, _ := cronStore.GetJob(ctx, downstream.ID)
+	if triggered.State.NextRunAtMS == nil || triggered.State.Input != "raw data" {
+		t.Fatalf("downstream state = %+v, want due with upstream output", triggered.State)
+	}
+
+	downstreamID := uuid.MustParse(downstream.ID)
+	if !cronStore.claimDueJob(downstreamID, time.Now()) {
+		t.Fatal("expected downstream job to be due")
+	}
+	cronStore.executeOneJob(*triggered, handler)
+	if gotInput != "raw data" {
+		t.Fatalf("downstream input = %q, want upstream output", gotInput)
+	}
+
+	done, _ := cronStore.GetJob(ctx, downstream.ID)
+	if done.State.NextRunAtMS != nil || done.State.Input != "" || done.State.LastOutput != "summary" || done.DeliverIf != "changed" {
+		t.Fatalf("downstream after run = %+v (deliverIf %q), want idle with last output recorded", done.State, done.DeliverIf)
+	}
+
+	// Deleting the upstream job removes the chain; the downstream job could
+	// never run again.
+	if err := cronStore.RemoveJob(ctx, upstream.ID); err != nil {
+		t.Fatalf("RemoveJob upstream error: %v", err)
+	}
+	if _, ok := cronStore.GetJob(ctx, downstream.ID); ok {
+		t.Fatal("downstream job survived upstream deletion")
+	}
+}
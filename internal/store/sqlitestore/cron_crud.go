@@ -14,6 +14,7 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/nextlevelbuilder/goclaw/internal/cron"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
@@ -26,9 +27,20 @@ func (s *SQLiteCronStore) AddJob(ctx context.Context, name string, schedule stor
 			return nil, fmt.Errorf("invalid timezone: %s", schedule.TZ)
 		}
 	}
+	var afterJobID *uuid.UUID
+	if schedule.Kind == "after" {
+		if err := store.ValidateCronSchedule(&schedule); err != nil {
+			return nil, err
+		}
+		if err := store.ValidateCronChain("", schedule.After, s.chainLookup(ctx, s.db)); err != nil {
+			return nil, err
+		}
+		aid := uuid.MustParse(schedule.After)
+		afterJobID = &aid
+	}
 
 	payload := store.CronPayload{
-		Kind: "agent_turn", Message: message,
+		Kind: store.CronPayloadKindAgentTurn, Message: message,
 	}
 	payloadJSON, _ := json.Marshal(payload)
 
@@ -71,10 +83,10 @@ func (s *SQLiteCronStore) AddJob(ctx context.Context, name string, schedule stor
 
 	_, err := s.db.ExecContext(ctx,
 		`INSERT INTO cron_jobs (id, tenant_id, agent_id, user_id, name, enabled, schedule_kind, cron_expression, run_at, timezone,
-		 interval_ms, payload, delete_after_run, deliver, deliver_channel, deliver_to, wake_heartbeat, next_run_at, created_at, updated_at)
-		 VALUES (?,?,?,?,?,1,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+		 interval_ms, payload, delete_after_run, deliver, deliver_channel, deliver_to, wake_heartbeat, next_run_at, created_at, updated_at, after_job_id)
+		 VALUES (?,?,?,?,?,1,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
 		id, tenantIDForInsert(ctx), agentUUID, userIDPtr, name, scheduleKind, cronExpr, runAt, tz,
-		intervalMS, payloadJSON, deleteAfterRun, deliver, channel, to, false, nextRun, now, now,
+		intervalMS, payloadJSON, deleteAfterRun, deliver, channel, to, false, nextRun, now, now, afterJobID,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("create cron job: %w", err)
@@ -98,10 +110,7 @@ func (s *SQLiteCronStore) GetJob(ctx context.Context, jobID string) (*store.Cron
 }
 
 func (s *SQLiteCronStore) ListJobs(ctx context.Context, includeDisabled bool, agentID, userID string) []store.CronJob {
-	q := `SELECT id, tenant_id, agent_id, user_id, name, enabled, schedule_kind, cron_expression, run_at, timezone,
-		 interval_ms, payload, delete_after_run, stateless, deliver, deliver_channel, deliver_to, wake_heartbeat,
-		 next_run_at, last_run_at, last_status, last_error,
-		 created_at, updated_at FROM cron_jobs WHERE 1=1`
+	q := `SELECT ` + cronJobColumns + ` FROM cron_jobs WHERE 1=1`
 
 	var args []any
 
@@ -207,11 +216,16 @@ func (s *SQLiteCronStore) EnableJob(ctx context.Context, jobID string, enabled b
 		return err
 	}
 
-	if err := execCronJobUpdateTx(ctx, tx, id, map[string]any{
+	updates := map[string]any{
 		"enabled":     enabled,
 		"next_run_at": nextRun,
 		"updated_at":  now,
-	}); err != nil {
+	}
+	if !enabled {
+		// Disabling drops any run-all replays still owed.
+		updates["catch_up_pending"] = 0
+	}
+	if err := execCronJobUpdateTx(ctx, tx, id, updates); err != nil {
 		return err
 	}
 
@@ -270,6 +284,11 @@ func (s *SQLiteCronStore) UpdateJob(ctx context.Context, jobID string, patch sto
 		if err := store.ValidateCronSchedule(&merged); err != nil {
 			return nil, err
 		}
+		if merged.Kind == "after" {
+			if err := store.ValidateCronChain(jobID, merged.After, s.chainLookup(ctx, tx)); err != nil {
+				return nil, err
+			}
+		}
 
 		store.ApplyCronScheduleUpdates(updates, merged)
 
@@ -278,12 +297,17 @@ func (s *SQLiteCronStore) UpdateJob(ctx context.Context, jobID string, patch sto
 			return nil, err
 		}
 		updates["next_run_at"] = nextRun
+		// A new schedule invalidates run-all replays owed under the old one.
+		updates["catch_up_pending"] = 0
 	} else if patch.Enabled != nil {
 		nextRun, err := store.NextRunForToggle(&current.Schedule, effectiveEnabled, current.Enabled, current.NextRunAt, now, s.defaultTZ)
 		if err != nil {
 			return nil, err
 		}
 		updates["next_run_at"] = nextRun
+		if !effectiveEnabled {
+			updates["catch_up_pending"] = 0
+		}
 	}
 
 	if patch.Stateless != nil {
@@ -301,10 +325,36 @@ func (s *SQLiteCronStore) UpdateJob(ctx context.Context, jobID string, patch sto
 	if patch.WakeHeartbeat != nil {
 		updates["wake_heartbeat"] = *patch.WakeHeartbeat
 	}
+	if patch.CatchUp != nil {
+		if err := store.ValidateCronCatchUp(*patch.CatchUp); err != nil {
+			return nil, err
+		}
+		updates["catch_up"] = *patch.CatchUp
+	}
+	if patch.DeliverIf != nil {
+		if err := cron.ValidateCondition(*patch.DeliverIf); err != nil {
+			return nil, err
+		}
+		updates["deliver_if"] = *patch.DeliverIf
+	}
 
-	if patch.Message != "" {
+	if patch.Message != "" || patch.WorkflowID != nil || patch.WebhookURL != nil {
 		payload := current.Payload
-		payload.Message = patch.Message
+		if patch.Message != "" {
+			payload.Message = patch.Message
+		}
+		if patch.WorkflowID != nil {
+			payload.WorkflowID = *patch.WorkflowID
+			payload.Kind = store.CronPayloadKindAgentTurn
+			if payload.WorkflowID != "" {
+				payload.Kind = store.CronPayloadKindWorkflow
+			}
+		}
+		if patch.WebhookURL != nil {
+			if err := store.ApplyCronWebhookURL(&payload, *patch.WebhookURL); err != nil {
+				return nil, err
+			}
+		}
 		mergedPayload, err := json.Marshal(payload)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal payload for job %s: %w", jobID, err)
@@ -327,7 +377,7 @@ func (s *SQLiteCronStore) UpdateJob(ctx context.Context, jobID string, patch sto
 }
 
 func (s *SQLiteCronStore) lockCronJobForMutation(ctx context.Context, tx *sql.Tx, id uuid.UUID, loadPayload bool) (*store.CronJobMutableState, error) {
-	q := `SELECT enabled, schedule_kind, cron_expression, run_at, timezone, interval_ms, next_run_at, payload
+	q := `SELECT enabled, schedule_kind, cron_expression, run_at, timezone, interval_ms, next_run_at, payload, after_job_id
 		FROM cron_jobs WHERE id = ?`
 	args := []any{id}
 
@@ -349,6 +399,7 @@ func (s *SQLiteCronStore) lockCronJobForMutation(ctx context.Context, tx *sql.Tx
 		intervalMS   *int64
 		nextRunAt    nullSqliteTime
 		payloadJSON  []byte
+		afterJobID   *uuid.UUID
 	)
 
 	if err := tx.QueryRowContext(ctx, q, args...).Scan(
@@ -360,6 +411,7 @@ func (s *SQLiteCronStore) lockCronJobForMutation(ctx context.Context, tx *sql.Tx
 		&intervalMS,
 		&nextRunAt,
 		&payloadJSON,
+		&afterJobID,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, store.ErrCronJobNotFound
@@ -381,6 +433,9 @@ func (s *SQLiteCronStore) lockCronJobForMutation(ctx context.Context, tx *sql.Tx
 	if intervalMS != nil {
 		state.Schedule.EveryMS = intervalMS
 	}
+	if afterJobID != nil {
+		state.Schedule.After = afterJobID.String()
+	}
 	if nextRunAt.Valid {
 		next := nextRunAt.Time
 		state.NextRunAt = &next
@@ -395,6 +450,38 @@ func (s *SQLiteCronStore) lockCronJobForMutation(ctx context.Context, tx *sql.Tx
 	return &state, nil
 }
 
+// cronRowQueryer is satisfied by both *sql.DB and *sql.Tx.
+type cronRowQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// chainLookup resolves a cron job's upstream "after" job within the
+// caller's tenant, for store.ValidateCronChain.
+func (s *SQLiteCronStore) chainLookup(ctx context.Context, db cronRowQueryer) func(string) (string, bool, error) {
+	return func(jobID string) (string, bool, error) {
+		id, err := uuid.Parse(jobID)
+		if err != nil {
+			return "", false, nil
+		}
+		q := `SELECT after_job_id FROM cron_jobs WHERE id = ?`
+		args := []any{id}
+		if !store.IsCrossTenant(ctx) {
+			q += ` AND tenant_id = ?`
+			args = append(args, store.TenantIDFromContext(ctx))
+		}
+		var after *uuid.UUID
+		if err := db.QueryRowContext(ctx, q, args...).Scan(&after); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return "", false, nil
+			}
+			return "", false, err
+		}
+		if after == nil {
+			return "", true, nil
+		}
+		return after.String(), true, nil
+	}
+}
 
 func execCronJobUpdateTx(ctx context.Context, tx *sql.Tx, id uuid.UUID, updates map[string]any) error {
 	if len(updates) == 0 {
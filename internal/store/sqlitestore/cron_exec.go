@@ -52,7 +52,7 @@ func (s *SQLiteCronStore) GetRunLog(ctx context.Context, jobID string, limit, of
 		offset = 0
 	}
 
-	const cols = "r.job_id, r.status, r.error, r.summary, r.ran_at, COALESCE(r.duration_ms, 0), COALESCE(r.input_tokens, 0), COALESCE(r.output_tokens, 0)"
+	const cols = "r.job_id, r.status, r.error, r.summary, r.ran_at, COALESCE(r.duration_ms, 0), COALESCE(r.input_tokens, 0), COALESCE(r.output_tokens, 0), r.catch_up, COALESCE(r.missed, 0)"
 
 	// Tenant isolation via JOIN with cron_jobs.
 	var tenantJoin, tenantWhere string
@@ -102,14 +102,15 @@ func (s *SQLiteCronStore) GetRunLog(ctx context.Context, jobID string, limit, of
 		var jobUUID uuid.UUID
 		var status string
 		var errStr, summary *string
-		var ranAt time.Time
+		var ranAt sqliteTime
 		var durationMS int64
-		var inputTokens, outputTokens int
-		if err := rows.Scan(&jobUUID, &status, &errStr, &summary, &ranAt, &durationMS, &inputTokens, &outputTokens); err != nil {
+		var inputTokens, outputTokens, missed int
+		var catchUp *string
+		if err := rows.Scan(&jobUUID, &status, &errStr, &summary, &ranAt, &durationMS, &inputTokens, &outputTokens, &catchUp, &missed); err != nil {
 			continue
 		}
 		result = append(result, store.CronRunLogEntry{
-			Ts:           ranAt.UnixMilli(),
+			Ts:           ranAt.Time.UnixMilli(),
 			JobID:        jobUUID.String(),
 			Status:       status,
 			Error:        derefStr(errStr),
@@ -117,6 +118,8 @@ func (s *SQLiteCronStore) GetRunLog(ctx context.Context, jobID string, limit, of
 			DurationMS:   durationMS,
 			InputTokens:  inputTokens,
 			OutputTokens: outputTokens,
+			CatchUp:      derefStr(catchUp),
+			Missed:       missed,
 		})
 	}
 	if rErr := rows.Err(); rErr != nil {
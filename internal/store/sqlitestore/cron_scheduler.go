@@ -38,10 +38,7 @@ func (s *SQLiteCronStore) GetDueJobs(now time.Time) []store.CronJob {
 // refreshJobCache reloads all enabled jobs from DB. Must be called with mu held.
 func (s *SQLiteCronStore) refreshJobCache() {
 	rows, err := s.db.QueryContext(s.baseCtx,
-		`SELECT id, tenant_id, agent_id, user_id, name, enabled, schedule_kind, cron_expression, run_at, timezone,
-		 interval_ms, payload, delete_after_run, stateless, deliver, deliver_channel, deliver_to, wake_heartbeat,
-		 next_run_at, last_run_at, last_status, last_error,
-		 created_at, updated_at FROM cron_jobs WHERE enabled = 1`)
+		`SELECT `+cronJobColumns+` FROM cron_jobs WHERE enabled = 1`)
 	if err != nil {
 		return
 	}
@@ -128,6 +125,68 @@ func (s *SQLiteCronStore) recomputeStaleJobs() {
 	}
 }
 
+// applyCatchUp resolves enabled jobs whose next_run_at passed while the
+// gateway was down, according to each job's catch-up policy, and records the
+// decision in cron_run_logs with status 'catchup'.
+func (s *SQLiteCronStore) applyCatchUp() {
+	now := time.Now()
+	cutoff := now.Add(-store.CronCatchUpGrace)
+	rows, err := s.db.QueryContext(s.baseCtx,
+		`SELECT `+cronJobColumns+` FROM cron_jobs WHERE enabled = 1 AND next_run_at < ? AND schedule_kind <> 'after'`, cutoff)
+	if err != nil {
+		slog.Warn("cron: failed to query missed jobs", "error", err)
+		return
+	}
+	var missed []store.CronJob
+	for rows.Next() {
+		job, err := scanCronRow(rows)
+		if err != nil {
+			continue
+		}
+		missed = append(missed, *job)
+	}
+	if err := rows.Err(); err != nil {
+		slog.Warn("cron: missed jobs iteration error", "error", err)
+	}
+	rows.Close()
+
+	for i := range missed {
+		job := &missed[i]
+		id, parseErr := uuid.Parse(job.ID)
+		if parseErr != nil {
+			continue
+		}
+		plan := store.PlanCronCatchUp(job, now, s.defaultTZ)
+
+		switch {
+		case plan.Disable:
+			_, err = s.db.ExecContext(s.baseCtx,
+				"UPDATE cron_jobs SET enabled = 0, next_run_at = NULL, catch_up_pending = 0, updated_at = ? WHERE id = ?", now, id)
+		case plan.NextRun != nil:
+			_, err = s.db.ExecContext(s.baseCtx,
+				"UPDATE cron_jobs SET next_run_at = ?, catch_up_pending = 0, updated_at = ? WHERE id = ?", *plan.NextRun, now, id)
+		default:
+			_, err = s.db.ExecContext(s.baseCtx,
+				"UPDATE cron_jobs SET catch_up_pending = ?, updated_at = ? WHERE id = ?", plan.Pending, now, id)
+		}
+		if err != nil {
+			slog.Warn("cron: failed to apply catch-up policy", "id", job.ID, "error", err)
+			continue
+		}
+
+		var agentUUID *uuid.UUID
+		if aid, aidErr := uuid.Parse(job.AgentID); aidErr == nil {
+			agentUUID = &aid
+		}
+		s.db.ExecContext(s.baseCtx,
+			`INSERT INTO cron_run_logs (id, job_id, agent_id, status, summary, catch_up, missed, ran_at)
+			 VALUES (?,?,?,'catchup',?,?,?,?)`,
+			uuid.Must(uuid.NewV7()), id, agentUUID, plan.Summary, plan.Policy, plan.Missed, now,
+		)
+		slog.Info("cron catch-up", "id", job.ID, "policy", plan.Policy, "missed", plan.Missed)
+	}
+}
+
 func (s *SQLiteCronStore) runLoop() {
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
@@ -247,7 +306,29 @@ func (s *SQLiteCronStore) executeOneJob(job store.CronJob, handler func(job *sto
 		)
 	}
 
-	if job.DeleteAfterRun {
+	// Trigger jobs chained after this one, passing the output as their input.
+	// Runs before the delete below, which would unlink them.
+	var lastOutput any
+	if err == nil {
+		output := cron.TruncateOutput(resultStr)
+		lastOutput = output
+		if id, parseErr := uuid.Parse(job.ID); parseErr == nil {
+			if res, trigErr := s.db.ExecContext(s.baseCtx,
+				`UPDATE cron_jobs SET chain_input = ?, next_run_at = ?, updated_at = ?
+				 WHERE after_job_id = ? AND enabled = 1 AND schedule_kind = 'after'`,
+				output, now, now, id,
+			); trigErr != nil {
+				slog.Warn("cron: failed to trigger chained jobs", "id", job.ID, "error", trigErr)
+			} else if n, _ := res.RowsAffected(); n > 0 {
+				slog.Info("cron: triggered chained jobs", "id", job.ID, "count", n)
+			}
+		}
+	}
+
+	// While run-all catch-up replays are pending, the next missed run
+	// becomes due immediately instead of the scheduled one. The consumed
+	// chain input is cleared unless a new trigger arrived mid-run.
+	if job.DeleteAfterRun && job.State.CatchUpPending == 0 {
 		if id, parseErr := uuid.Parse(job.ID); parseErr == nil {
 			s.db.ExecContext(s.baseCtx, "DELETE FROM cron_jobs WHERE id = ?", id)
 		}
@@ -261,9 +342,14 @@ func (s *SQLiteCronStore) executeOneJob(job store.CronJob, handler func(job *sto
 		s.db.ExecContext(s.baseCtx,
 			`UPDATE cron_jobs SET
 			 last_run_at = ?, last_status = ?, last_error = ?, updated_at = ?,
-			 next_run_at = CASE WHEN enabled = 1 AND next_run_at IS NULL THEN ? ELSE next_run_at END
+			 next_run_at = CASE WHEN enabled = 1 AND next_run_at IS NULL
+			   THEN CASE WHEN catch_up_pending > 0 THEN ? ELSE ? END
+			   ELSE next_run_at END,
+			 catch_up_pending = MAX(catch_up_pending - 1, 0),
+			 chain_input = CASE WHEN next_run_at IS NULL THEN NULL ELSE chain_input END,
+			 last_output = COALESCE(?, last_output)
 			 WHERE id = ?`,
-			now, status, lastError, now, nextRunValue, id,
+			now, status, lastError, now, now, nextRunValue, lastOutput, id,
 		)
 	}
 
@@ -296,10 +382,7 @@ func (s *SQLiteCronStore) claimDueJob(id uuid.UUID, now time.Time) bool {
 func (s *SQLiteCronStore) loadClaimedJob(id uuid.UUID) (*store.CronJob, bool) {
 	row := s.db.QueryRowContext(
 		s.baseCtx,
-		`SELECT id, tenant_id, agent_id, user_id, name, enabled, schedule_kind, cron_expression, run_at, timezone,
-		 interval_ms, payload, delete_after_run, stateless, deliver, deliver_channel, deliver_to, wake_heartbeat,
-		 next_run_at, last_run_at, last_status, last_error,
-		 created_at, updated_at
+		`SELECT `+cronJobColumns+`
 		 FROM cron_jobs
 		 WHERE id = ? AND enabled = 1 AND next_run_at IS NULL`,
 		id,
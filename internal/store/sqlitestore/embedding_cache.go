@@ -0,0 +1,99 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SQLiteEmbeddingCacheStore implements store.EmbeddingCacheStore on the
+// embedding_cache table.
+type SQLiteEmbeddingCacheStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteEmbeddingCacheStore(db *sql.DB) *SQLiteEmbeddingCacheStore {
+	return &SQLiteEmbeddingCacheStore{db: db}
+}
+
+// LookupEmbeddings fetches cached embeddings for the given content hashes.
+func (s *SQLiteEmbeddingCacheStore) LookupEmbeddings(ctx context.Context, hashes []string, provider, model string) (map[string][]float32, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+	placeholders := make([]string, len(hashes))
+	args := make([]any, 0, len(hashes)+2)
+	for i, h := range hashes {
+		placeholders[i] = "?"
+		args = append(args, h)
+	}
+	args = append(args, provider, model)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT hash, embedding FROM embedding_cache
+		 WHERE hash IN (`+strings.Join(placeholders, ",")+`) AND provider = ? AND model = ? AND embedding IS NOT NULL`,
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("lookup embedding cache: %w", err)
+	}
+	defer rows.Close()
+
+	result := make(map[string][]float32, len(hashes))
+	for rows.Next() {
+		var hash string
+		var blob []byte
+		if err := rows.Scan(&hash, &blob); err != nil {
+			continue
+		}
+		if vec := decodeVector(blob); vec != nil {
+			result[hash] = vec
+		}
+	}
+	return result, rows.Err()
+}
+
+// WriteEmbeddings upserts embedding cache entries in one transaction.
+func (s *SQLiteEmbeddingCacheStore) WriteEmbeddings(ctx context.Context, entries map[string][]float32, provider, model string) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	tid := tenantIDForInsert(ctx).String()
+	for hash, emb := range entries {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO embedding_cache (hash, provider, model, embedding, dims, tenant_id, created_at, updated_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			 ON CONFLICT (hash, provider, model) DO UPDATE SET embedding = excluded.embedding,
+			   dims = excluded.dims, updated_at = excluded.updated_at`,
+			hash, provider, model, encodeVector(emb), len(emb), tid, now, now,
+		); err != nil {
+			return fmt.Errorf("write embedding cache: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ClearEmbeddings drops stored vectors so the backfills re-embed them with
+// the current provider. Skills and team tasks have no vectors in SQLite.
+func (s *SQLiteEmbeddingCacheStore) ClearEmbeddings(ctx context.Context) (int64, error) {
+	var total int64
+	for _, table := range []string{"memory_chunks", "kg_entities", "kb_chunks"} {
+		res, err := s.db.ExecContext(ctx, "UPDATE "+table+" SET embedding = NULL WHERE embedding IS NOT NULL")
+		if err != nil {
+			return total, fmt.Errorf("clear %s embeddings: %w", table, err)
+		}
+		n, _ := res.RowsAffected()
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,86 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestSQLiteEmbeddingCacheStore_SharedCacheAndReembed(t *testing.T) {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "emb.db"))
+	if err != nil {
+		t.Fatalf("OpenDB error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if err := EnsureSchema(db); err != nil {
+		t.Fatalf("EnsureSchema error: %v", err)
+	}
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+	cache := NewSQLiteEmbeddingCacheStore(db)
+
+	if err := cache.WriteEmbeddings(ctx, map[string][]float32{"h1": {1, 0}}, "test", "m1"); err != nil {
+		t.Fatalf("WriteEmbeddings: %v", err)
+	}
+	got, err := cache.LookupEmbeddings(ctx, []string{"h1", "h2"}, "test", "m1")
+	if err != nil || !reflect.DeepEqual(got, map[string][]float32{"h1": {1, 0}}) {
+		t.Fatalf("LookupEmbeddings = %v, %v", got, err)
+	}
+	if got, _ := cache.LookupEmbeddings(ctx, []string{"h1"}, "test", "m2"); len(got) != 0 {
+		t.Fatalf("cache must be keyed by model, got %v", got)
+	}
+
+	// Index a document through the shared cached provider.
+	emb := &keywordEmbedder{}
+	shared := memory.NewCachedEmbeddingProvider(emb, cache)
+	kb := NewSQLiteKnowledgeBaseStore(db)
+	kb.SetEmbeddingProvider(shared)
+	col := &store.KBCollectionData{Name: "Manuals", CreatedBy: "user-1"}
+	if err := kb.CreateCollection(ctx, col); err != nil {
+		t.Fatalf("CreateCollection: %v", err)
+	}
+	doc := &store.KBDocumentData{
+		CollectionID: col.ID,
+		FileName:     "guide.md",
+		Hash:         "d1",
+		Content:      "The sedan engine needs oil every year.",
+		CreatedBy:    "user-1",
+	}
+	if err := kb.PutDocument(ctx, doc); err != nil {
+		t.Fatalf("PutDocument: %v", err)
+	}
+	if err := kb.IndexDocument(ctx, doc.ID); err != nil {
+		t.Fatalf("IndexDocument: %v", err)
+	}
+	if emb.calls != 1 {
+		t.Fatalf("embed calls = %d, want 1", emb.calls)
+	}
+
+	// A provider change clears stored vectors; the backfill restores them,
+	// served from the cache without calling the API again.
+	cleared, err := cache.ClearEmbeddings(ctx)
+	if err != nil || cleared != 1 {
+		t.Fatalf("ClearEmbeddings = %d, %v; want 1 row", cleared, err)
+	}
+	var missing int
+	db.QueryRow(`SELECT COUNT(*) FROM kb_chunks WHERE embedding IS NULL`).Scan(&missing)
+	if missing != 1 {
+		t.Fatalf("chunks without vectors = %d, want 1", missing)
+	}
+	n, err := kb.BackfillKBEmbeddings(ctx)
+	if err != nil || n != 1 {
+		t.Fatalf("BackfillKBEmbeddings = %d, %v; want 1", n, err)
+	}
+	if emb.calls != 1 {
+		t.Errorf("embed calls = %d, want cache hit", emb.calls)
+	}
+	db.QueryRow(`SELECT COUNT(*) FROM kb_chunks WHERE embedding IS NULL`).Scan(&missing)
+	if missing != 0 {
+		t.Errorf("chunks without vectors after backfill = %d, want 0", missing)
+	}
+}
@@ -45,6 +45,7 @@ func NewSQLiteStores(cfg store.StoreConfig) (*store.Stores, error) {
 		PendingMessages:       NewSQLitePendingMessageStore(db),
 		Contacts:              NewSQLiteContactStore(db),
 		Teams:                 NewSQLiteTeamStore(db),
+		Workflows:             NewSQLiteWorkflowStore(db),
 		Skills:                NewSQLiteSkillStore(db, cfg.SkillsStorageDir),
 		MCP:                   NewSQLiteMCPServerStore(db, cfg.EncryptionKey),
 		Activity:              NewSQLiteActivityStore(db),
@@ -54,7 +55,11 @@ func NewSQLiteStores(cfg store.StoreConfig) (*store.Stores, error) {
 		SubagentTasks:         NewSQLiteSubagentTaskStore(),
 		Workers:               NewSQLiteWorkerStore(db),
 		WorkerEndpoints:       NewSQLiteWorkerEndpointStore(db),
-		// Phase 2 Batch B+C stores (nil = gracefully skipped by gateway):
-		// AgentLinks, KnowledgeGraph, SecureCLI
+		AgentLinks:            NewSQLiteAgentLinkStore(db),
+		KnowledgeGraph:        NewSQLiteKnowledgeGraphStore(db),
+		KnowledgeBases:        NewSQLiteKnowledgeBaseStore(db),
+		EmbeddingCache:        NewSQLiteEmbeddingCacheStore(db),
+		SecureCLI:             NewSQLiteSecureCLIStore(db, cfg.EncryptionKey),
+		SecureCLIGrants:       NewSQLiteSecureCLIAgentGrantStore(db),
 	}, nil
 }
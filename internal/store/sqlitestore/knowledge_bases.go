@@ -0,0 +1,717 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// kbEmbedBatchSize is the number of chunks sent per embedding request.
+const kbEmbedBatchSize = 64
+
+const kbDefaultMaxResults = 6
+
+// SQLiteKnowledgeBaseStore implements store.KnowledgeBaseStore backed by SQLite.
+// Text search matches query terms with LIKE; vector search is brute-force
+// cosine similarity, as in the SQLite memory store.
+type SQLiteKnowledgeBaseStore struct {
+	db       *sql.DB
+	mu       sync.RWMutex
+	provider store.EmbeddingProvider
+}
+
+func NewSQLiteKnowledgeBaseStore(db *sql.DB) *SQLiteKnowledgeBaseStore {
+	return &SQLiteKnowledgeBaseStore{db: db}
+}
+
+func (s *SQLiteKnowledgeBaseStore) SetEmbeddingProvider(provider store.EmbeddingProvider) {
+	s.mu.Lock()
+	s.provider = provider
+	s.mu.Unlock()
+}
+
+func (s *SQLiteKnowledgeBaseStore) embeddingProvider() store.EmbeddingProvider {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.provider
+}
+
+// ============================================================
+// Collections
+// ============================================================
+
+const kbCollectionSelectCols = `c.id, c.tenant_id, c.name, c.description, c.created_by, c.created_at, c.updated_at,
+		 (SELECT COUNT(*) FROM kb_documents d WHERE d.collection_id = c.id)`
+
+func (s *SQLiteKnowledgeBaseStore) CreateCollection(ctx context.Context, c *store.KBCollectionData) error {
+	if c.ID == uuid.Nil {
+		c.ID = store.GenNewID()
+	}
+	now := time.Now()
+	c.CreatedAt = now
+	c.UpdatedAt = now
+	c.TenantID = tenantIDForInsert(ctx)
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO kb_collections (id, name, description, created_by, tenant_id, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		c.ID, c.Name, c.Description, c.CreatedBy, c.TenantID, now, now,
+	)
+	return err
+}
+
+func (s *SQLiteKnowledgeBaseStore) GetCollection(ctx context.Context, id uuid.UUID) (*store.KBCollectionData, error) {
+	where, args, err := kbTenantWhere(ctx, "c.tenant_id", "c.id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	return s.getCollection(ctx, where, args)
+}
+
+func (s *SQLiteKnowledgeBaseStore) GetCollectionByName(ctx context.Context, name string) (*store.KBCollectionData, error) {
+	where, args, err := kbTenantWhere(ctx, "c.tenant_id", "LOWER(c.name) = LOWER(?)", name)
+	if err != nil {
+		return nil, err
+	}
+	return s.getCollection(ctx, where, args)
+}
+
+func (s *SQLiteKnowledgeBaseStore) getCollection(ctx context.Context, where string, args []any) (*store.KBCollectionData, error) {
+	cols, err := s.queryCollections(ctx, `SELECT `+kbCollectionSelectCols+` FROM kb_collections c WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(cols) == 0 {
+		return nil, store.ErrKBCollectionNotFound
+	}
+	return &cols[0], nil
+}
+
+func (s *SQLiteKnowledgeBaseStore) ListCollections(ctx context.Context) ([]store.KBCollectionData, error) {
+	where, args, err := kbTenantWhere(ctx, "c.tenant_id", "1 = 1")
+	if err != nil {
+		return nil, err
+	}
+	return s.queryCollections(ctx, `SELECT `+kbCollectionSelectCols+` FROM kb_collections c WHERE `+where+` ORDER BY c.name`, args...)
+}
+
+func (s *SQLiteKnowledgeBaseStore) UpdateCollection(ctx context.Context, c *store.KBCollectionData) error {
+	c.UpdatedAt = time.Now()
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "id = ?", c.ID)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE kb_collections SET name = ?, description = ?, updated_at = ? WHERE `+where,
+		append([]any{c.Name, c.Description, c.UpdatedAt}, args...)...,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrKBCollectionNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteKnowledgeBaseStore) DeleteCollection(ctx context.Context, id uuid.UUID) error {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "id = ?", id)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx, `DELETE FROM kb_collections WHERE `+where, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrKBCollectionNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteKnowledgeBaseStore) queryCollections(ctx context.Context, query string, args ...any) ([]store.KBCollectionData, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var cols []store.KBCollectionData
+	for rows.Next() {
+		var c store.KBCollectionData
+		createdAt, updatedAt := scanTimePair()
+		if err := rows.Scan(&c.ID, &c.TenantID, &c.Name, &c.Description, &c.CreatedBy, createdAt, updatedAt,
+			&c.DocumentCount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		c.CreatedAt = createdAt.Time
+		c.UpdatedAt = updatedAt.Time
+		cols = append(cols, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.loadCollectionAgents(ctx, cols); err != nil {
+		return nil, err
+	}
+	return cols, nil
+}
+
+// loadCollectionAgents fills AgentIDs of the given collections.
+func (s *SQLiteKnowledgeBaseStore) loadCollectionAgents(ctx context.Context, cols []store.KBCollectionData) error {
+	if len(cols) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(cols))
+	byID := make(map[uuid.UUID]*store.KBCollectionData, len(cols))
+	for i := range cols {
+		ids[i] = cols[i].ID
+		cols[i].AgentIDs = []uuid.UUID{}
+		byID[cols[i].ID] = &cols[i]
+	}
+	in, inArgs := kbInClause(ids)
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT collection_id, agent_id FROM kb_collection_agents WHERE collection_id IN (`+in+`) ORDER BY created_at`,
+		inArgs...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var colID, agentID uuid.UUID
+		if err := rows.Scan(&colID, &agentID); err != nil {
+			return err
+		}
+		if c := byID[colID]; c != nil {
+			c.AgentIDs = append(c.AgentIDs, agentID)
+		}
+	}
+	return rows.Err()
+}
+
+// ============================================================
+// Agent attachments
+// ============================================================
+
+func (s *SQLiteKnowledgeBaseStore) AttachAgent(ctx context.Context, collectionID, agentID uuid.UUID) error {
+	c, err := s.GetCollection(ctx, collectionID)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO kb_collection_agents (collection_id, agent_id, tenant_id, created_at)
+		 VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING`,
+		collectionID, agentID, c.TenantID, time.Now(),
+	)
+	return err
+}
+
+func (s *SQLiteKnowledgeBaseStore) DetachAgent(ctx context.Context, collectionID, agentID uuid.UUID) error {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "collection_id = ? AND agent_id = ?", collectionID, agentID)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `DELETE FROM kb_collection_agents WHERE `+where, args...)
+	return err
+}
+
+func (s *SQLiteKnowledgeBaseStore) ListAgentCollections(ctx context.Context, agentID uuid.UUID) ([]store.KBCollectionData, error) {
+	where, args, err := kbTenantWhere(ctx, "c.tenant_id",
+		"c.id IN (SELECT collection_id FROM kb_collection_agents WHERE agent_id = ?)", agentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.queryCollections(ctx, `SELECT `+kbCollectionSelectCols+` FROM kb_collections c WHERE `+where+` ORDER BY c.name`, args...)
+}
+
+// ============================================================
+// Documents
+// ============================================================
+
+const kbDocumentSelectCols = `id, collection_id, tenant_id, file_name, mime_type, hash, size_bytes, pages,
+		 status, error, chunk_count, created_by, created_at, updated_at`
+
+func (s *SQLiteKnowledgeBaseStore) PutDocument(ctx context.Context, doc *store.KBDocumentData) error {
+	c, err := s.GetCollection(ctx, doc.CollectionID)
+	if err != nil {
+		return err
+	}
+	if doc.ID == uuid.Nil {
+		doc.ID = store.GenNewID()
+	}
+	now := time.Now()
+	doc.TenantID = c.TenantID
+	doc.Status = store.KBDocStatusPending
+	doc.Error = ""
+	doc.UpdatedAt = now
+	createdAt := &sqliteTime{}
+	if err := s.db.QueryRowContext(ctx,
+		`INSERT INTO kb_documents (id, collection_id, file_name, mime_type, hash, size_bytes, pages, content,
+		   status, created_by, tenant_id, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT (collection_id, file_name) DO UPDATE SET
+		   mime_type = excluded.mime_type, hash = excluded.hash, size_bytes = excluded.size_bytes,
+		   pages = excluded.pages, content = excluded.content, status = excluded.status, error = '',
+		   updated_at = excluded.updated_at
+		 RETURNING id, chunk_count, created_by, created_at`,
+		doc.ID, doc.CollectionID, doc.FileName, doc.MimeType, doc.Hash, doc.SizeBytes, doc.Pages, doc.Content,
+		doc.Status, doc.CreatedBy, doc.TenantID, now, now,
+	).Scan(&doc.ID, &doc.ChunkCount, &doc.CreatedBy, createdAt); err != nil {
+		return err
+	}
+	doc.CreatedAt = createdAt.Time
+	return nil
+}
+
+func (s *SQLiteKnowledgeBaseStore) GetDocument(ctx context.Context, id uuid.UUID) (*store.KBDocumentData, error) {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	return s.getDocument(ctx, where, args)
+}
+
+func (s *SQLiteKnowledgeBaseStore) GetDocumentByName(ctx context.Context, collectionID uuid.UUID, fileName string) (*store.KBDocumentData, error) {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "collection_id = ? AND file_name = ?", collectionID, fileName)
+	if err != nil {
+		return nil, err
+	}
+	return s.getDocument(ctx, where, args)
+}
+
+// getDocument loads one document including its content.
+func (s *SQLiteKnowledgeBaseStore) getDocument(ctx context.Context, where string, args []any) (*store.KBDocumentData, error) {
+	var d store.KBDocumentData
+	createdAt, updatedAt := scanTimePair()
+	err := s.db.QueryRowContext(ctx,
+		`SELECT `+kbDocumentSelectCols+`, content FROM kb_documents WHERE `+where, args...,
+	).Scan(&d.ID, &d.CollectionID, &d.TenantID, &d.FileName, &d.MimeType, &d.Hash, &d.SizeBytes, &d.Pages,
+		&d.Status, &d.Error, &d.ChunkCount, &d.CreatedBy, createdAt, updatedAt, &d.Content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, store.ErrKBDocumentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	d.CreatedAt = createdAt.Time
+	d.UpdatedAt = updatedAt.Time
+	return &d, nil
+}
+
+// ListDocuments returns the documents of a collection without their content.
+func (s *SQLiteKnowledgeBaseStore) ListDocuments(ctx context.Context, collectionID uuid.UUID) ([]store.KBDocumentData, error) {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "collection_id = ?", collectionID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+kbDocumentSelectCols+` FROM kb_documents WHERE `+where+` ORDER BY file_name`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var docs []store.KBDocumentData
+	for rows.Next() {
+		var d store.KBDocumentData
+		createdAt, updatedAt := scanTimePair()
+		if err := rows.Scan(&d.ID, &d.CollectionID, &d.TenantID, &d.FileName, &d.MimeType, &d.Hash, &d.SizeBytes, &d.Pages,
+			&d.Status, &d.Error, &d.ChunkCount, &d.CreatedBy, createdAt, updatedAt); err != nil {
+			return nil, err
+		}
+		d.CreatedAt = createdAt.Time
+		d.UpdatedAt = updatedAt.Time
+		docs = append(docs, d)
+	}
+	return docs, rows.Err()
+}
+
+func (s *SQLiteKnowledgeBaseStore) DeleteDocument(ctx context.Context, collectionID, id uuid.UUID) error {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "id = ? AND collection_id = ?", id, collectionID)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx, `DELETE FROM kb_documents WHERE `+where, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrKBDocumentNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteKnowledgeBaseStore) MarkDocumentsPending(ctx context.Context, collectionID uuid.UUID) error {
+	where, args, err := kbTenantWhere(ctx, "tenant_id", "collection_id = ?", store.KBDocStatusPending, time.Now(), collectionID)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `UPDATE kb_documents SET status = ?, error = '', updated_at = ? WHERE `+where, args...)
+	return err
+}
+
+// IndexDocument rebuilds a document's chunks. Embeddings of chunks whose text
+// is unchanged are carried over, so re-indexing an edited document only
+// embeds the passages that changed. Embedding failures leave chunks without
+// vectors (text search still finds them), as in the memory store.
+func (s *SQLiteKnowledgeBaseStore) IndexDocument(ctx context.Context, id uuid.UUID) error {
+	doc, err := s.GetDocument(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.db.ExecContext(ctx, `UPDATE kb_documents SET status = ?, updated_at = ? WHERE id = ?`,
+		store.KBDocStatusIndexing, time.Now(), id)
+
+	if err := s.indexDocument(ctx, doc); err != nil {
+		s.db.ExecContext(ctx, `UPDATE kb_documents SET status = ?, error = ?, updated_at = ? WHERE id = ?`,
+			store.KBDocStatusFailed, err.Error(), time.Now(), id)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLiteKnowledgeBaseStore) indexDocument(ctx context.Context, doc *store.KBDocumentData) error {
+	chunks := store.ChunkKBContent(doc.Content)
+
+	previous, err := s.existingEmbeddings(ctx, doc.ID)
+	if err != nil {
+		return fmt.Errorf("load existing embeddings: %w", err)
+	}
+	embeddings := embedKBChunks(ctx, s.embeddingProvider(), doc.FileName, chunks, previous)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM kb_chunks WHERE document_id = ?`, doc.ID); err != nil {
+		return err
+	}
+	now := time.Now()
+	for i, c := range chunks {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO kb_chunks (id, collection_id, document_id, page, start_line, end_line, hash, text, embedding, tenant_id, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			uuid.Must(uuid.NewV7()), doc.CollectionID, doc.ID, c.Page, c.StartLine, c.EndLine, c.Hash, c.Text,
+			encodeVector(embeddings[i]), doc.TenantID, now,
+		); err != nil {
+			return fmt.Errorf("insert chunk: %w", err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE kb_documents SET status = ?, error = '', chunk_count = ?, updated_at = ? WHERE id = ?`,
+		store.KBDocStatusReady, len(chunks), now, doc.ID,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// existingEmbeddings maps chunk hash → embedding for a document's current chunks.
+func (s *SQLiteKnowledgeBaseStore) existingEmbeddings(ctx context.Context, docID uuid.UUID) (map[string][]float32, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT hash, embedding FROM kb_chunks WHERE document_id = ? AND embedding IS NOT NULL`, docID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := make(map[string][]float32)
+	for rows.Next() {
+		var hash string
+		var blob []byte
+		if err := rows.Scan(&hash, &blob); err != nil {
+			return nil, err
+		}
+		if emb := decodeVector(blob); emb != nil {
+			out[hash] = emb
+		}
+	}
+	return out, rows.Err()
+}
+
+// embedKBChunks returns one embedding per chunk (nil where unavailable),
+// reusing previous embeddings by content hash and embedding the rest in
+// batches.
+func embedKBChunks(ctx context.Context, provider store.EmbeddingProvider, fileName string, chunks []store.KBChunk, previous map[string][]float32) [][]float32 {
+	embeddings := make([][]float32, len(chunks))
+	var pending []int
+	for i, c := range chunks {
+		if emb, ok := previous[c.Hash]; ok {
+			embeddings[i] = emb
+			continue
+		}
+		pending = append(pending, i)
+	}
+	if provider == nil {
+		return embeddings
+	}
+	for start := 0; start < len(pending); start += kbEmbedBatchSize {
+		batch := pending[start:min(start+kbEmbedBatchSize, len(pending))]
+		texts := make([]string, len(batch))
+		for j, idx := range batch {
+			texts[j] = chunks[idx].Text
+		}
+		embs, err := provider.Embed(ctx, texts)
+		if err != nil {
+			slog.Warn("kb embedding failed, storing chunks without vectors",
+				"file", fileName, "chunks", len(batch), "error", err)
+			continue
+		}
+		for j, emb := range embs {
+			if j < len(batch) {
+				embeddings[batch[j]] = emb
+			}
+		}
+	}
+	return embeddings
+}
+
+// ============================================================
+// Search
+// ============================================================
+
+type kbScoredChunk struct {
+	store.KBSearchResult
+	chunkID uuid.UUID
+}
+
+// Search matches query terms with LIKE and ranks chunks by embedding
+// similarity, merging both with the memory store's weights (0.3 text,
+// 0.7 vector).
+func (s *SQLiteKnowledgeBaseStore) Search(ctx context.Context, query string, agentID uuid.UUID, opts store.KBSearchOptions) ([]store.KBSearchResult, error) {
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = kbDefaultMaxResults
+	}
+	maxResults = min(maxResults, store.KBMaxSearchResults)
+	colIDs, err := s.searchCollections(ctx, agentID, opts.CollectionIDs)
+	if err != nil || len(colIDs) == 0 {
+		return nil, err
+	}
+
+	text, err := s.termSearch(ctx, query, colIDs, maxResults*2)
+	if err != nil {
+		return nil, err
+	}
+	var vec []kbScoredChunk
+	if provider := s.embeddingProvider(); provider != nil {
+		embeddings, err := provider.Embed(ctx, []string{query})
+		if err == nil && len(embeddings) > 0 && len(embeddings[0]) > 0 {
+			vec, err = s.vectorSearch(ctx, embeddings[0], colIDs, maxResults*2)
+			if err != nil {
+				slog.Warn("kb vector search failed", "error", err)
+				vec = nil
+			}
+		}
+	}
+	return kbMerge(text, vec, opts.MinScore, maxResults), nil
+}
+
+// searchCollections resolves the collections to search: those attached to
+// the agent, narrowed to the requested ones when given. Without an agent only
+// the requested collections are searched.
+func (s *SQLiteKnowledgeBaseStore) searchCollections(ctx context.Context, agentID uuid.UUID, requested []uuid.UUID) ([]uuid.UUID, error) {
+	if agentID == uuid.Nil && len(requested) == 0 {
+		return nil, nil
+	}
+	var cols []store.KBCollectionData
+	var err error
+	if agentID != uuid.Nil {
+		cols, err = s.ListAgentCollections(ctx, agentID)
+	} else {
+		cols, err = s.ListCollections(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	want := make(map[uuid.UUID]bool, len(requested))
+	for _, id := range requested {
+		want[id] = true
+	}
+	var ids []uuid.UUID
+	for _, c := range cols {
+		if len(want) > 0 && !want[c.ID] {
+			continue
+		}
+		ids = append(ids, c.ID)
+	}
+	return ids, nil
+}
+
+const kbChunkSelect = `SELECT ch.id, ch.collection_id, k.name, ch.document_id, d.file_name, ch.page, ch.start_line, ch.end_line, ch.text`
+
+const kbChunkFrom = ` FROM kb_chunks ch
+		JOIN kb_documents d ON d.id = ch.document_id
+		JOIN kb_collections k ON k.id = ch.collection_id`
+
+// termSearch finds chunks containing any query term. The score is the
+// fraction of terms a chunk contains.
+func (s *SQLiteKnowledgeBaseStore) termSearch(ctx context.Context, query string, colIDs []uuid.UUID, limit int) ([]kbScoredChunk, error) {
+	terms := kbQueryTerms(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	in, args := kbInClause(colIDs)
+	likes := make([]string, len(terms))
+	for i, t := range terms {
+		likes[i] = `LOWER(ch.text) LIKE ? ESCAPE '\'`
+		args = append(args, "%"+escapeLike(t)+"%")
+	}
+	rows, err := s.db.QueryContext(ctx,
+		kbChunkSelect+kbChunkFrom+` WHERE ch.collection_id IN (`+in+`) AND (`+strings.Join(likes, " OR ")+`)`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []kbScoredChunk
+	for rows.Next() {
+		var r kbScoredChunk
+		if err := rows.Scan(&r.chunkID, &r.CollectionID, &r.CollectionName, &r.DocumentID, &r.FileName,
+			&r.Page, &r.StartLine, &r.EndLine, &r.Snippet); err != nil {
+			return nil, err
+		}
+		lower := strings.ToLower(r.Snippet)
+		matched := 0
+		for _, t := range terms {
+			if strings.Contains(lower, t) {
+				matched++
+			}
+		}
+		r.Score = float64(matched) / float64(len(terms))
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (s *SQLiteKnowledgeBaseStore) vectorSearch(ctx context.Context, embedding []float32, colIDs []uuid.UUID, limit int) ([]kbScoredChunk, error) {
+	in, args := kbInClause(colIDs)
+	rows, err := s.db.QueryContext(ctx,
+		kbChunkSelect+`, ch.embedding`+kbChunkFrom+` WHERE ch.collection_id IN (`+in+`) AND ch.embedding IS NOT NULL`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []kbScoredChunk
+	for rows.Next() {
+		var r kbScoredChunk
+		var blob []byte
+		if err := rows.Scan(&r.chunkID, &r.CollectionID, &r.CollectionName, &r.DocumentID, &r.FileName,
+			&r.Page, &r.StartLine, &r.EndLine, &r.Snippet, &blob); err != nil {
+			return nil, err
+		}
+		r.Score = cosineSimilarity(embedding, decodeVector(blob))
+		if r.Score <= 0 {
+			continue
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// kbMerge combines text and vector hits with weighted scores. When one side
+// has no hits the other gets the full weight.
+func kbMerge(text, vec []kbScoredChunk, minScore float64, maxResults int) []store.KBSearchResult {
+	textW, vecW := 0.3, 0.7
+	if len(text) == 0 {
+		textW, vecW = 0, 1.0
+	} else if len(vec) == 0 {
+		textW, vecW = 1.0, 0
+	}
+	merged := make(map[uuid.UUID]*store.KBSearchResult)
+	var order []uuid.UUID
+	add := func(r kbScoredChunk, w float64) {
+		if m, ok := merged[r.chunkID]; ok {
+			m.Score += r.Score * w
+			return
+		}
+		res := r.KBSearchResult
+		res.Score = r.Score * w
+		merged[r.chunkID] = &res
+		order = append(order, r.chunkID)
+	}
+	for _, r := range text {
+		add(r, textW)
+	}
+	for _, r := range vec {
+		add(r, vecW)
+	}
+
+	results := make([]store.KBSearchResult, 0, len(order))
+	for _, id := range order {
+		if r := merged[id]; minScore <= 0 || r.Score >= minScore {
+			results = append(results, *r)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > maxResults {
+		results = results[:maxResults]
+	}
+	return results
+}
+
+// kbQueryTerms lowercases the query and splits it into distinct words,
+// dropping one-letter words.
+func kbQueryTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, w := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		if len([]rune(w)) < 2 || seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+// kbInClause returns "?, ?, ..." and the matching args for an IN list.
+func kbInClause(ids []uuid.UUID) (string, []any) {
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "), args
+}
+
+// kbTenantWhere appends the tenant filter to cond unless the context is
+// cross-tenant. args are the parameters already referenced by cond.
+func kbTenantWhere(ctx context.Context, col, cond string, args ...any) (string, []any, error) {
+	if store.IsCrossTenant(ctx) {
+		return cond, args, nil
+	}
+	tid, err := requireTenantID(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	return cond + " AND " + col + " = ?", append(args, tid), nil
+}
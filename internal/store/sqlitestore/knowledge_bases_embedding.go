@@ -0,0 +1,95 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+)
+
+// BackfillKBEmbeddings embeds knowledge base chunks stored without vectors
+// (indexed while no provider was configured, while it was failing, or
+// cleared after a provider change). Processes batches of kbEmbedBatchSize
+// across tenants and stops after 3 consecutive batch failures.
+func (s *SQLiteKnowledgeBaseStore) BackfillKBEmbeddings(ctx context.Context) (int, error) {
+	provider := s.embeddingProvider()
+	if provider == nil {
+		return 0, nil
+	}
+
+	const maxConsecutiveErrors = 3
+	total := 0
+	consecutiveErrors := 0
+	failedIDs := make(map[uuid.UUID]bool)
+
+	for {
+		// Over-fetch by the number of failed chunks so they don't starve the batch.
+		limit := kbEmbedBatchSize + len(failedIDs)
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT id, text FROM kb_chunks WHERE embedding IS NULL ORDER BY id LIMIT ?`, limit)
+		if err != nil {
+			return total, err
+		}
+		var ids []uuid.UUID
+		var texts []string
+		fetched := 0
+		for rows.Next() {
+			var id uuid.UUID
+			var text string
+			if err := rows.Scan(&id, &text); err != nil {
+				continue
+			}
+			fetched++
+			if failedIDs[id] || len(ids) >= kbEmbedBatchSize {
+				continue
+			}
+			ids = append(ids, id)
+			texts = append(texts, text)
+		}
+		rows.Close()
+
+		if len(ids) == 0 {
+			break
+		}
+
+		embeddings, err := provider.Embed(ctx, texts)
+		if err != nil {
+			slog.Warn("kb chunk embedding batch failed, skipping batch", "error", err, "batch_size", len(ids))
+			for _, id := range ids {
+				failedIDs[id] = true
+			}
+			consecutiveErrors++
+			if consecutiveErrors >= maxConsecutiveErrors {
+				slog.Warn("kb backfill: too many consecutive errors, stopping", "errors", consecutiveErrors)
+				break
+			}
+			continue
+		}
+		consecutiveErrors = 0
+
+		for i, emb := range embeddings {
+			if i >= len(ids) {
+				break
+			}
+			if len(emb) == 0 {
+				failedIDs[ids[i]] = true
+				continue
+			}
+			if _, err := s.db.ExecContext(ctx,
+				`UPDATE kb_chunks SET embedding = ? WHERE id = ?`, encodeVector(emb), ids[i],
+			); err != nil {
+				slog.Warn("kb chunk embedding update failed", "chunk_id", ids[i], "error", err)
+				failedIDs[ids[i]] = true
+				continue
+			}
+			total++
+		}
+
+		if fetched < limit {
+			break
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,143 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestSQLiteKnowledgeBaseStore_IndexAndSearch(t *testing.T) {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "kb.db"))
+	if err != nil {
+		t.Fatalf("OpenDB error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if err := EnsureSchema(db); err != nil {
+		t.Fatalf("EnsureSchema error: %v", err)
+	}
+
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+	agentID, otherAgentID := uuid.New(), uuid.New()
+	for key, id := range map[string]uuid.UUID{"support": agentID, "sales": otherAgentID} {
+		if _, err := db.ExecContext(ctx, `INSERT INTO agents (id, agent_key, owner_id, provider, model, tenant_id) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, key, "user-1", "openai", "gpt-4.1-mini", store.MasterTenantID); err != nil {
+			t.Fatalf("insert agent: %v", err)
+		}
+	}
+
+	emb := &keywordEmbedder{}
+	kb := NewSQLiteKnowledgeBaseStore(db)
+	kb.SetEmbeddingProvider(emb)
+
+	col := &store.KBCollectionData{Name: "Manuals", CreatedBy: "user-1"}
+	if err := kb.CreateCollection(ctx, col); err != nil {
+		t.Fatalf("CreateCollection: %v", err)
+	}
+	if err := kb.AttachAgent(ctx, col.ID, agentID); err != nil {
+		t.Fatalf("AttachAgent: %v", err)
+	}
+	if err := kb.AttachAgent(ctx, col.ID, agentID); err != nil {
+		t.Fatalf("AttachAgent is not idempotent: %v", err)
+	}
+
+	doc := &store.KBDocumentData{
+		CollectionID: col.ID,
+		FileName:     "guide.pdf",
+		MimeType:     "application/pdf",
+		Hash:         "h1",
+		Pages:        2,
+		Content:      "--- Page 1 ---\nThe sedan engine needs oil every year.\n\n--- Page 2 ---\nA kitten should see the vet twice.",
+		CreatedBy:    "user-1",
+	}
+	if err := kb.PutDocument(ctx, doc); err != nil {
+		t.Fatalf("PutDocument: %v", err)
+	}
+	if err := kb.IndexDocument(ctx, doc.ID); err != nil {
+		t.Fatalf("IndexDocument: %v", err)
+	}
+	got, err := kb.GetDocument(ctx, doc.ID)
+	if err != nil || got.Status != store.KBDocStatusReady || got.ChunkCount != 2 {
+		t.Fatalf("GetDocument = %+v, %v", got, err)
+	}
+
+	// "feline" matches no text, only the page-2 embedding.
+	results, err := kb.Search(ctx, "feline care", agentID, store.KBSearchOptions{})
+	if err != nil || len(results) == 0 {
+		t.Fatalf("Search = %v, %v", results, err)
+	}
+	if r := results[0]; r.Page != 2 || r.FileName != "guide.pdf" || r.CollectionName != "Manuals" {
+		t.Errorf("top result = %+v, want guide.pdf page 2", r)
+	}
+	results, err = kb.Search(ctx, "oil", agentID, store.KBSearchOptions{})
+	if err != nil || len(results) != 1 || results[0].Page != 1 {
+		t.Errorf("text Search = %+v, %v", results, err)
+	}
+	if results, _ := kb.Search(ctx, "oil", otherAgentID, store.KBSearchOptions{}); len(results) != 0 {
+		t.Errorf("unattached agent found %d results", len(results))
+	}
+	if results, _ := kb.Search(ctx, "oil", uuid.Nil, store.KBSearchOptions{CollectionIDs: []uuid.UUID{col.ID}}); len(results) != 1 {
+		t.Errorf("collection search found %d results, want 1", len(results))
+	}
+
+	// Re-uploading under the same name replaces the document; only the
+	// changed page is embedded again.
+	calls := emb.calls
+	update := &store.KBDocumentData{
+		CollectionID: col.ID,
+		FileName:     "guide.pdf",
+		Hash:         "h2",
+		Content:      "--- Page 1 ---\nThe sedan engine needs oil every year.\n\n--- Page 2 ---\nA cat should see the vet once.",
+	}
+	if err := kb.PutDocument(ctx, update); err != nil {
+		t.Fatalf("PutDocument update: %v", err)
+	}
+	if update.ID != doc.ID || update.CreatedBy != "user-1" {
+		t.Errorf("update = %+v, want existing document %s", update, doc.ID)
+	}
+	if err := kb.IndexDocument(ctx, update.ID); err != nil {
+		t.Fatalf("IndexDocument update: %v", err)
+	}
+	if emb.calls != calls+1 {
+		t.Errorf("embedding calls = %d, want %d", emb.calls, calls+1)
+	}
+	if results, _ := kb.Search(ctx, "twice", agentID, store.KBSearchOptions{}); len(results) != 0 {
+		t.Errorf("stale chunk still found: %+v", results)
+	}
+
+	if err := kb.MarkDocumentsPending(ctx, col.ID); err != nil {
+		t.Fatalf("MarkDocumentsPending: %v", err)
+	}
+	if got, _ := kb.GetDocument(ctx, doc.ID); got.Status != store.KBDocStatusPending {
+		t.Errorf("status after MarkDocumentsPending = %q, want pending", got.Status)
+	}
+
+	cols, err := kb.ListAgentCollections(ctx, agentID)
+	if err != nil || len(cols) != 1 || cols[0].DocumentCount != 1 || len(cols[0].AgentIDs) != 1 {
+		t.Fatalf("ListAgentCollections = %+v, %v", cols, err)
+	}
+	if err := kb.DetachAgent(ctx, col.ID, agentID); err != nil {
+		t.Fatalf("DetachAgent: %v", err)
+	}
+	if results, _ := kb.Search(ctx, "oil", agentID, store.KBSearchOptions{}); len(results) != 0 {
+		t.Errorf("detached agent found %d results", len(results))
+	}
+
+	if err := kb.DeleteCollection(ctx, col.ID); err != nil {
+		t.Fatalf("DeleteCollection: %v", err)
+	}
+	if _, err := kb.GetDocument(ctx, doc.ID); !errors.Is(err, store.ErrKBDocumentNotFound) {
+		t.Errorf("document after collection delete: %v", err)
+	}
+	var n int
+	db.QueryRow(`SELECT COUNT(*) FROM kb_chunks`).Scan(&n)
+	if n != 0 {
+		t.Errorf("%d chunks left after collection delete", n)
+	}
+}
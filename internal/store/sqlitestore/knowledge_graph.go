@@ -0,0 +1,348 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SQLiteKnowledgeGraphStore implements store.KnowledgeGraphStore backed by SQLite.
+// Entity text search uses LIKE instead of tsvector; entity embeddings are float32
+// BLOBs compared by brute-force cosine similarity (see memory_vector.go).
+type SQLiteKnowledgeGraphStore struct {
+	db          *sql.DB
+	embProvider store.EmbeddingProvider
+}
+
+// NewSQLiteKnowledgeGraphStore creates a new SQLite-backed knowledge graph store.
+func NewSQLiteKnowledgeGraphStore(db *sql.DB) *SQLiteKnowledgeGraphStore {
+	return &SQLiteKnowledgeGraphStore{db: db}
+}
+
+// SetEmbeddingProvider configures the embedding provider for semantic search.
+func (s *SQLiteKnowledgeGraphStore) SetEmbeddingProvider(provider store.EmbeddingProvider) {
+	s.embProvider = provider
+}
+
+func (s *SQLiteKnowledgeGraphStore) Close() error { return nil }
+
+const entitySelectCols = `id, agent_id, user_id, external_id, name, entity_type, COALESCE(description, ''),
+	COALESCE(properties, '{}'), COALESCE(source_id, ''), confidence, created_at, updated_at`
+
+// entityUpsertSQL inserts an entity or refreshes it on (agent_id, user_id, external_id) conflict.
+const entityUpsertSQL = `
+	INSERT INTO kg_entities
+		(id, agent_id, user_id, external_id, name, entity_type, description, properties, source_id, confidence, tenant_id, created_at, updated_at)
+	VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?11, ?12, ?12)
+	ON CONFLICT (agent_id, user_id, external_id) DO UPDATE SET
+		name        = excluded.name,
+		entity_type = excluded.entity_type,
+		description = excluded.description,
+		properties  = excluded.properties,
+		source_id   = excluded.source_id,
+		confidence  = excluded.confidence,
+		tenant_id   = excluded.tenant_id,
+		updated_at  = excluded.updated_at
+	RETURNING id`
+
+func (s *SQLiteKnowledgeGraphStore) UpsertEntity(ctx context.Context, entity *store.Entity) error {
+	props, err := json.Marshal(entity.Properties)
+	if err != nil {
+		props = []byte("{}")
+	}
+	var actualID string
+	if err := s.db.QueryRowContext(ctx, entityUpsertSQL,
+		uuid.Must(uuid.NewV7()), entity.AgentID, entity.UserID, entity.ExternalID, entity.Name, entity.EntityType,
+		entity.Description, string(props), entity.SourceID, entity.Confidence, tenantIDForInsert(ctx), time.Now().UTC(),
+	).Scan(&actualID); err != nil {
+		return err
+	}
+
+	// Generate embedding in background (best-effort, non-blocking)
+	go s.EmbedEntity(context.WithoutCancel(ctx), actualID, entity.Name, entity.Description)
+	return nil
+}
+
+func (s *SQLiteKnowledgeGraphStore) GetEntity(ctx context.Context, agentID, userID, entityID string) (*store.Entity, error) {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	where := "id = ? AND agent_id = ?"
+	args := []any{entityID, agentID}
+	if !store.IsSharedKG(ctx) {
+		where += " AND user_id = ?"
+		args = append(args, userID)
+	}
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+entitySelectCols+` FROM kg_entities WHERE `+where+tc,
+		append(args, tcArgs...)...,
+	)
+	return scanEntity(row)
+}
+
+func (s *SQLiteKnowledgeGraphStore) DeleteEntity(ctx context.Context, agentID, userID, entityID string) error {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return err
+	}
+	where := "id = ? AND agent_id = ?"
+	args := []any{entityID, agentID}
+	if !store.IsSharedKG(ctx) {
+		where += " AND user_id = ?"
+		args = append(args, userID)
+	}
+	_, err = s.db.ExecContext(ctx, `DELETE FROM kg_entities WHERE `+where+tc, append(args, tcArgs...)...)
+	return err
+}
+
+func (s *SQLiteKnowledgeGraphStore) ListEntities(ctx context.Context, agentID, userID string, opts store.EntityListOptions) ([]store.Entity, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	where, args, err := kgScopeWhere(ctx, "", agentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if opts.EntityType != "" {
+		where += " AND entity_type = ?"
+		args = append(args, opts.EntityType)
+	}
+	args = append(args, limit, opts.Offset)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+entitySelectCols+` FROM kg_entities WHERE `+where+`
+		 ORDER BY updated_at DESC LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanEntities(rows)
+}
+
+func (s *SQLiteKnowledgeGraphStore) SearchEntities(ctx context.Context, agentID, userID, query string, limit int) ([]store.Entity, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	textResults, err := s.likeSearchEntities(ctx, agentID, userID, query, limit*2)
+	if err != nil {
+		return nil, err
+	}
+
+	// Vector search if provider available
+	var vecResults []scoredEntity
+	if s.embProvider != nil {
+		embeddings, embErr := s.embProvider.Embed(ctx, []string{query})
+		if embErr == nil && len(embeddings) > 0 {
+			vecResults, err = s.vectorSearchEntities(ctx, embeddings[0], agentID, userID, limit*2)
+			if err != nil {
+				vecResults = nil
+			}
+		}
+	}
+
+	// If no vector results, fall back to text-only
+	if len(vecResults) == 0 {
+		if len(textResults) > limit {
+			textResults = textResults[:limit]
+		}
+		entities := make([]store.Entity, len(textResults))
+		for i, r := range textResults {
+			entities[i] = r.Entity
+		}
+		return entities, nil
+	}
+
+	// Hybrid merge with weights: 0.3 text, 0.7 vector
+	textW, vecW := 0.3, 0.7
+	if len(textResults) == 0 {
+		textW, vecW = 0, 1.0
+	}
+	merged := hybridMergeEntities(textResults, vecResults, textW, vecW)
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged, nil
+}
+
+type scoredEntity struct {
+	Entity store.Entity
+	Score  float64
+}
+
+// likeSearchEntities matches the query against entity names (score 1.0) and
+// descriptions (score 0.5).
+func (s *SQLiteKnowledgeGraphStore) likeSearchEntities(ctx context.Context, agentID, userID, query string, limit int) ([]scoredEntity, error) {
+	where, args, err := kgScopeWhere(ctx, "", agentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	pattern := "%" + escapeLike(query) + "%"
+	where += ` AND (name LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\')`
+	args = append([]any{pattern}, args...)
+	args = append(args, pattern, pattern, limit)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+entitySelectCols+`,
+		        CASE WHEN name LIKE ? ESCAPE '\' THEN 1.0 ELSE 0.5 END AS score
+		 FROM kg_entities
+		 WHERE `+where+`
+		 ORDER BY score DESC, updated_at DESC LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []scoredEntity
+	for rows.Next() {
+		var score float64
+		e, err := scanEntityWith(rows, &score)
+		if err != nil {
+			continue
+		}
+		results = append(results, scoredEntity{Entity: *e, Score: score})
+	}
+	return results, rows.Err()
+}
+
+// vectorSearchEntities scores every embedded entity in scope by cosine similarity
+// and returns the top `limit`.
+func (s *SQLiteKnowledgeGraphStore) vectorSearchEntities(ctx context.Context, embedding []float32, agentID, userID string, limit int) ([]scoredEntity, error) {
+	where, args, err := kgScopeWhere(ctx, "", agentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+entitySelectCols+`, embedding FROM kg_entities
+		 WHERE `+where+` AND embedding IS NOT NULL`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []scoredEntity
+	for rows.Next() {
+		var blob []byte
+		e, err := scanEntityWith(rows, &blob)
+		if err != nil {
+			continue
+		}
+		if score := cosineSimilarity(embedding, decodeVector(blob)); score > 0 {
+			results = append(results, scoredEntity{Entity: *e, Score: score})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(results, func(a, b scoredEntity) int { return cmp.Compare(b.Score, a.Score) })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// hybridMergeEntities combines text and vector results with weighted scoring.
+func hybridMergeEntities(text, vec []scoredEntity, textWeight, vectorWeight float64) []store.Entity {
+	type mergedEntry struct {
+		Entity store.Entity
+		Score  float64
+	}
+	seen := make(map[string]*mergedEntry)
+	add := func(r scoredEntity, weight float64) {
+		if existing, ok := seen[r.Entity.ID]; ok {
+			existing.Score += r.Score * weight
+			return
+		}
+		seen[r.Entity.ID] = &mergedEntry{Entity: r.Entity, Score: r.Score * weight}
+	}
+	for _, r := range text {
+		add(r, textWeight)
+	}
+	for _, r := range vec {
+		add(r, vectorWeight)
+	}
+
+	results := make([]store.Entity, 0, len(seen))
+	for _, entry := range seen {
+		results = append(results, entry.Entity)
+	}
+	slices.SortFunc(results, func(a, b store.Entity) int {
+		return cmp.Compare(seen[b.ID].Score, seen[a.ID].Score) // descending
+	})
+	return results
+}
+
+// kgScopeWhere builds the common "agent [+ user] + tenant" filter for KG tables.
+// alias qualifies columns (e.g. "a."); pass "" for unaliased queries.
+// The user filter is skipped in shared-KG mode or when userID is empty.
+func kgScopeWhere(ctx context.Context, alias, agentID, userID string) (string, []any, error) {
+	where := alias + "agent_id = ?"
+	args := []any{agentID}
+	if !store.IsSharedKG(ctx) && userID != "" {
+		where += " AND " + alias + "user_id = ?"
+		args = append(args, userID)
+	}
+	var tc string
+	var tcArgs []any
+	var err error
+	if alias == "" {
+		tc, tcArgs, err = scopeClause(ctx)
+	} else {
+		tc, tcArgs, err = scopeClauseAlias(ctx, alias[:len(alias)-1])
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return where + tc, append(args, tcArgs...), nil
+}
+
+// --- scan helpers ---
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEntity(row rowScanner) (*store.Entity, error) {
+	return scanEntityWith(row)
+}
+
+// scanEntityWith scans entitySelectCols followed by any extra trailing columns.
+func scanEntityWith(row rowScanner, extra ...any) (*store.Entity, error) {
+	var e store.Entity
+	var props []byte
+	createdAt, updatedAt := scanTimePair()
+	dest := []any{
+		&e.ID, &e.AgentID, &e.UserID, &e.ExternalID, &e.Name, &e.EntityType,
+		&e.Description, &props, &e.SourceID, &e.Confidence, createdAt, updatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	json.Unmarshal(props, &e.Properties) //nolint:errcheck
+	e.CreatedAt = createdAt.Time.UnixMilli()
+	e.UpdatedAt = updatedAt.Time.UnixMilli()
+	return &e, nil
+}
+
+func scanEntities(rows *sql.Rows) ([]store.Entity, error) {
+	var result []store.Entity
+	for rows.Next() {
+		e, err := scanEntity(rows)
+		if err != nil {
+			continue
+		}
+		result = append(result, *e)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,379 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	kg "github.com/nextlevelbuilder/goclaw/internal/knowledgegraph"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+const (
+	dedupAutoMergeThreshold = 0.98
+	dedupCandidateThreshold = 0.90
+	dedupNameMatchThreshold = 0.85
+)
+
+// DedupAfterExtraction checks newly upserted entities for duplicates using
+// embedding similarity (brute-force KNN) and name similarity (Jaro-Winkler).
+// Auto-merges near-certain duplicates (>0.98 + name match), flags possible
+// duplicates (>0.90) as candidates for manual review.
+func (s *SQLiteKnowledgeGraphStore) DedupAfterExtraction(ctx context.Context, agentID, userID string, newEntityIDs []string) (int, int, error) {
+	if len(newEntityIDs) == 0 {
+		return 0, 0, nil
+	}
+
+	var merged, flagged int
+	for _, eid := range newEntityIDs {
+		tc, tcArgs, err := scopeClause(ctx)
+		if err != nil {
+			continue
+		}
+		var name, entityType string
+		var confidence float64
+		var blob []byte
+		if err := s.db.QueryRowContext(ctx,
+			`SELECT name, entity_type, confidence, embedding
+			 FROM kg_entities WHERE id = ? AND agent_id = ?`+tc,
+			append([]any{eid, agentID}, tcArgs...)...,
+		).Scan(&name, &entityType, &confidence, &blob); err != nil {
+			continue // entity may have been deleted/merged already
+		}
+		if len(blob) == 0 {
+			continue // no embedding → can't compute similarity
+		}
+
+		// KNN: find top-3 nearest existing entities of same type (exclude self)
+		neighbors, err := s.knnNeighbors(ctx, agentID, userID, eid, entityType, decodeVector(blob), 3)
+		if err != nil {
+			slog.Warn("kg.dedup: knn query failed", "entity_id", eid, "error", err)
+			continue
+		}
+
+		for _, n := range neighbors {
+			nameSim := kg.JaroWinkler(name, n.name)
+
+			if n.similarity >= dedupAutoMergeThreshold && nameSim >= dedupNameMatchThreshold {
+				// Auto-merge: keep the one with higher confidence
+				targetID, sourceID := eid, n.id
+				if n.confidence > confidence {
+					targetID, sourceID = n.id, eid
+				}
+				if err := s.MergeEntities(ctx, agentID, userID, targetID, sourceID); err != nil {
+					slog.Warn("kg.dedup: auto-merge failed", "target", targetID, "source", sourceID, "error", err)
+					continue
+				}
+				merged++
+				break // entity merged, stop checking neighbors
+			} else if n.similarity >= dedupCandidateThreshold {
+				if err := s.insertDedupCandidate(ctx, agentID, userID, eid, n.id, n.similarity); err != nil {
+					slog.Warn("kg.dedup: flag candidate failed", "error", err)
+				} else {
+					flagged++
+				}
+			}
+		}
+	}
+
+	return merged, flagged, nil
+}
+
+type knnNeighbor struct {
+	id         string
+	name       string
+	confidence float64
+	similarity float64
+}
+
+// knnNeighbors finds the top-K most similar entities of the same type by
+// scanning every embedded entity in scope.
+func (s *SQLiteKnowledgeGraphStore) knnNeighbors(ctx context.Context, agentID, userID, excludeID, entityType string, embedding []float32, limit int) ([]knnNeighbor, error) {
+	where, args, err := kgScopeWhere(ctx, "", agentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, name, confidence, embedding FROM kg_entities
+		 WHERE `+where+` AND entity_type = ? AND id != ? AND embedding IS NOT NULL`,
+		append(args, entityType, excludeID)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []knnNeighbor
+	for rows.Next() {
+		var n knnNeighbor
+		var blob []byte
+		if err := rows.Scan(&n.id, &n.name, &n.confidence, &blob); err != nil {
+			continue
+		}
+		n.similarity = cosineSimilarity(embedding, decodeVector(blob))
+		results = append(results, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortFunc(results, func(a, b knnNeighbor) int { return cmp.Compare(b.similarity, a.similarity) })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (s *SQLiteKnowledgeGraphStore) insertDedupCandidate(ctx context.Context, agentID, userID, entityAID, entityBID string, similarity float64) error {
+	// Ensure consistent ordering (smaller ID first) to avoid duplicates
+	if entityAID > entityBID {
+		entityAID, entityBID = entityBID, entityAID
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO kg_dedup_candidates (id, tenant_id, agent_id, user_id, entity_a_id, entity_b_id, similarity, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (entity_a_id, entity_b_id) DO NOTHING`,
+		uuid.Must(uuid.NewV7()), tenantIDForInsert(ctx), agentID, userID, entityAID, entityBID, similarity, time.Now().UTC(),
+	)
+	return err
+}
+
+// ScanDuplicates compares every pair of same-type embedded entities in scope
+// and records pairs above threshold in kg_dedup_candidates.
+// Returns number of candidates found.
+func (s *SQLiteKnowledgeGraphStore) ScanDuplicates(ctx context.Context, agentID, userID string, threshold float64, limit int) (int, error) {
+	if threshold <= 0 {
+		threshold = dedupCandidateThreshold
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+
+	where, args, err := kgScopeWhere(ctx, "", agentID, userID)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, entity_type, embedding FROM kg_entities
+		 WHERE `+where+` AND embedding IS NOT NULL
+		 ORDER BY id`, args...)
+	if err != nil {
+		return 0, fmt.Errorf("kg.scan_duplicates: query failed: %w", err)
+	}
+
+	type embedded struct {
+		id  string
+		vec []float32
+	}
+	byType := make(map[string][]embedded)
+	for rows.Next() {
+		var id, entityType string
+		var blob []byte
+		if err := rows.Scan(&id, &entityType, &blob); err != nil {
+			continue
+		}
+		byType[entityType] = append(byType[entityType], embedded{id: id, vec: decodeVector(blob)})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	type pair struct {
+		a, b string
+		sim  float64
+	}
+	var pairs []pair
+	for _, group := range byType {
+		for i := range group {
+			for j := i + 1; j < len(group); j++ {
+				if sim := cosineSimilarity(group[i].vec, group[j].vec); sim > threshold {
+					pairs = append(pairs, pair{a: group[i].id, b: group[j].id, sim: sim})
+				}
+			}
+		}
+	}
+	slices.SortFunc(pairs, func(x, y pair) int { return cmp.Compare(y.sim, x.sim) })
+	if len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+
+	found := 0
+	for _, p := range pairs {
+		if err := s.insertDedupCandidate(ctx, agentID, userID, p.a, p.b, p.sim); err != nil {
+			slog.Warn("kg.scan_duplicates: insert candidate failed", "error", err)
+			continue
+		}
+		found++
+	}
+	return found, nil
+}
+
+// MergeEntities merges sourceID into targetID: re-points all relations from
+// source to target, deletes the source entity. SQLite serialises writers, so
+// the transaction alone prevents concurrent merges.
+func (s *SQLiteKnowledgeGraphStore) MergeEntities(ctx context.Context, agentID, userID, targetID, sourceID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Verify both entities exist and belong to the same agent + tenant scope.
+	// When userID is empty, skip user_id filter (admin/shared view).
+	shared := store.IsSharedKG(ctx) || userID == ""
+	for _, eid := range []string{targetID, sourceID} {
+		q := `SELECT EXISTS(SELECT 1 FROM kg_entities WHERE id = ? AND agent_id = ?`
+		args := []any{eid, agentID}
+		if !shared {
+			q += ` AND user_id = ?`
+			args = append(args, userID)
+		}
+		var exists bool
+		if err := tx.QueryRowContext(ctx, q+tc+`)`, append(args, tcArgs...)...).Scan(&exists); err != nil {
+			return fmt.Errorf("kg.merge: entity check failed: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("kg.merge: entity %s not found or access denied", eid)
+		}
+	}
+
+	// Re-point relations from source to target.
+	// First delete relations that would become duplicates after re-pointing,
+	// then update the remaining ones.
+	for _, cols := range [][2]string{
+		{"source_entity_id", "target_entity_id"},
+		{"target_entity_id", "source_entity_id"},
+	} {
+		col, otherCol := cols[0], cols[1]
+		// SQLite DELETE takes no table alias, so the outer row is referenced by table name.
+		delQ := fmt.Sprintf(`
+			DELETE FROM kg_relations
+			WHERE %s = ? AND agent_id = ?
+			AND EXISTS (
+				SELECT 1 FROM kg_relations r2
+				WHERE r2.%s = ?
+				AND r2.agent_id = kg_relations.agent_id
+				AND r2.user_id = kg_relations.user_id
+				AND r2.relation_type = kg_relations.relation_type
+				AND r2.%s = kg_relations.%s
+			)`, col, col, otherCol, otherCol)
+		if _, err := tx.ExecContext(ctx, delQ+tc, append([]any{sourceID, agentID, targetID}, tcArgs...)...); err != nil {
+			return fmt.Errorf("kg.merge: dedup relations %s failed: %w", col, err)
+		}
+		updQ := fmt.Sprintf(`UPDATE kg_relations SET %s = ? WHERE %s = ? AND agent_id = ?`, col, col)
+		if _, err := tx.ExecContext(ctx, updQ+tc, append([]any{targetID, sourceID, agentID}, tcArgs...)...); err != nil {
+			return fmt.Errorf("kg.merge: re-point %s failed: %w", col, err)
+		}
+	}
+
+	// Delete the source entity (CASCADE removes any remaining orphan relations)
+	if _, err := tx.ExecContext(ctx, `DELETE FROM kg_entities WHERE id = ?`, sourceID); err != nil {
+		return fmt.Errorf("kg.merge: delete source failed: %w", err)
+	}
+
+	// Mark any dedup candidates referencing the source as merged
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE kg_dedup_candidates SET status = 'merged'
+		WHERE (entity_a_id = ?1 OR entity_b_id = ?1) AND status = 'pending'`, sourceID); err != nil {
+		slog.Warn("kg.merge: update candidates failed", "error", err)
+	}
+
+	return tx.Commit()
+}
+
+// dedupEntityCols returns the entity columns for a joined alias, in entitySelectCols order.
+func dedupEntityCols(alias string) string {
+	return alias + `.id, ` + alias + `.agent_id, ` + alias + `.user_id, ` + alias + `.external_id, ` +
+		alias + `.name, ` + alias + `.entity_type, COALESCE(` + alias + `.description, ''), ` +
+		`COALESCE(` + alias + `.properties, '{}'), COALESCE(` + alias + `.source_id, ''), ` +
+		alias + `.confidence, ` + alias + `.created_at, ` + alias + `.updated_at`
+}
+
+// ListDedupCandidates returns pending dedup candidates for review.
+func (s *SQLiteKnowledgeGraphStore) ListDedupCandidates(ctx context.Context, agentID, userID string, limit int) ([]store.DedupCandidate, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	where, args, err := kgScopeWhere(ctx, "c.", agentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.id, c.similarity, c.status, c.created_at,
+		       `+dedupEntityCols("a")+`,
+		       `+dedupEntityCols("b")+`
+		FROM kg_dedup_candidates c
+		JOIN kg_entities a ON c.entity_a_id = a.id
+		JOIN kg_entities b ON c.entity_b_id = b.id
+		WHERE `+where+` AND c.status = 'pending'
+		ORDER BY c.similarity DESC, c.created_at DESC
+		LIMIT ?`, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []store.DedupCandidate
+	for rows.Next() {
+		var dc store.DedupCandidate
+		var propsA, propsB []byte
+		createdAt := &sqliteTime{}
+		caA, uaA := scanTimePair()
+		caB, uaB := scanTimePair()
+		if err := rows.Scan(
+			&dc.ID, &dc.Similarity, &dc.Status, createdAt,
+			&dc.EntityA.ID, &dc.EntityA.AgentID, &dc.EntityA.UserID, &dc.EntityA.ExternalID,
+			&dc.EntityA.Name, &dc.EntityA.EntityType, &dc.EntityA.Description, &propsA,
+			&dc.EntityA.SourceID, &dc.EntityA.Confidence, caA, uaA,
+			&dc.EntityB.ID, &dc.EntityB.AgentID, &dc.EntityB.UserID, &dc.EntityB.ExternalID,
+			&dc.EntityB.Name, &dc.EntityB.EntityType, &dc.EntityB.Description, &propsB,
+			&dc.EntityB.SourceID, &dc.EntityB.Confidence, caB, uaB,
+		); err != nil {
+			continue
+		}
+		json.Unmarshal(propsA, &dc.EntityA.Properties) //nolint:errcheck
+		json.Unmarshal(propsB, &dc.EntityB.Properties) //nolint:errcheck
+		dc.EntityA.CreatedAt = caA.Time.UnixMilli()
+		dc.EntityA.UpdatedAt = uaA.Time.UnixMilli()
+		dc.EntityB.CreatedAt = caB.Time.UnixMilli()
+		dc.EntityB.UpdatedAt = uaB.Time.UnixMilli()
+		dc.CreatedAt = createdAt.Time.Unix()
+		results = append(results, dc)
+	}
+	return results, rows.Err()
+}
+
+// DismissCandidate marks a dedup candidate as dismissed.
+// Scoped by agent_id + tenant to prevent cross-agent/cross-tenant dismissal.
+func (s *SQLiteKnowledgeGraphStore) DismissCandidate(ctx context.Context, agentID, candidateID string) error {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE kg_dedup_candidates SET status = 'dismissed' WHERE id = ? AND agent_id = ? AND status = 'pending'`+tc,
+		append([]any{candidateID, agentID}, tcArgs...)...,
+	)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
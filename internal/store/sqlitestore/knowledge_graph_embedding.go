@@ -0,0 +1,123 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"log/slog"
+)
+
+// BackfillKGEmbeddings generates embeddings for all KG entities that don't have one yet.
+// Processes in batches of 50. Returns total number of entities updated.
+// On batch-level embedding failure, skips the batch and continues (up to 3 consecutive failures).
+func (s *SQLiteKnowledgeGraphStore) BackfillKGEmbeddings(ctx context.Context) (int, error) {
+	if s.embProvider == nil {
+		return 0, nil
+	}
+
+	const batchSize = 50
+	const maxConsecutiveErrors = 3
+	total := 0
+	consecutiveErrors := 0
+
+	// Failed entities stay NULL, so widen each page by their count and skip them.
+	failedIDs := make(map[string]bool)
+
+	for {
+		// Backfill is a cross-tenant admin operation — no tenant scoping.
+		rows, err := s.db.QueryContext(ctx,
+			`SELECT id, name, COALESCE(description, '') FROM kg_entities
+			 WHERE embedding IS NULL
+			 ORDER BY created_at DESC
+			 LIMIT ?`, batchSize+len(failedIDs))
+		if err != nil {
+			return total, err
+		}
+
+		type entityRow struct {
+			id   string
+			text string
+		}
+		var pending []entityRow
+		for rows.Next() {
+			var id, name, desc string
+			if err := rows.Scan(&id, &name, &desc); err != nil {
+				continue
+			}
+			if failedIDs[id] || len(pending) >= batchSize {
+				continue
+			}
+			pending = append(pending, entityRow{id: id, text: name + " " + desc})
+		}
+		rows.Close()
+
+		if len(pending) == 0 {
+			break
+		}
+
+		slog.Info("backfilling KG entity embeddings", "batch", len(pending), "total_so_far", total)
+
+		texts := make([]string, len(pending))
+		for i, p := range pending {
+			texts[i] = p.text
+		}
+		embeddings, err := s.embProvider.Embed(ctx, texts)
+		if err != nil {
+			slog.Warn("kg entity embedding batch failed, skipping batch", "error", err, "batch_size", len(pending))
+			for _, p := range pending {
+				failedIDs[p.id] = true
+			}
+			consecutiveErrors++
+			if consecutiveErrors >= maxConsecutiveErrors {
+				slog.Warn("kg backfill: too many consecutive errors, stopping", "errors", consecutiveErrors)
+				break
+			}
+			continue
+		}
+		consecutiveErrors = 0
+
+		for i, emb := range embeddings {
+			if i >= len(pending) {
+				break
+			}
+			if len(emb) == 0 {
+				failedIDs[pending[i].id] = true
+				continue
+			}
+			if _, err := s.db.ExecContext(ctx,
+				`UPDATE kg_entities SET embedding = ? WHERE id = ?`, encodeVector(emb), pending[i].id,
+			); err != nil {
+				slog.Warn("kg entity embedding update failed", "entity_id", pending[i].id, "error", err)
+				failedIDs[pending[i].id] = true
+				continue
+			}
+			total++
+		}
+
+		if len(pending) < batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		slog.Info("KG entity embeddings backfill complete", "updated", total)
+	}
+	return total, nil
+}
+
+// EmbedEntity generates and stores an embedding for a single entity.
+// Called by UpsertEntity to ensure entities created via HTTP API also get embeddings.
+func (s *SQLiteKnowledgeGraphStore) EmbedEntity(ctx context.Context, entityID, name, description string) {
+	if s.embProvider == nil {
+		return
+	}
+	embeddings, err := s.embProvider.Embed(ctx, []string{name + " " + description})
+	if err != nil || len(embeddings) == 0 || len(embeddings[0]) == 0 {
+		return // best-effort, don't fail the upsert
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE kg_entities SET embedding = ? WHERE id = ?`, encodeVector(embeddings[0]), entityID,
+	); err != nil {
+		slog.Warn("kg entity embedding failed", "entity_id", entityID, "error", err)
+	}
+}
@@ -0,0 +1,282 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+const relationSelectCols = `id, agent_id, user_id, source_entity_id, relation_type, target_entity_id,
+	confidence, COALESCE(properties, '{}'), created_at`
+
+// relationUpsertSQL inserts a relation or refreshes it when the same edge already exists.
+const relationUpsertSQL = `
+	INSERT INTO kg_relations
+		(id, agent_id, user_id, source_entity_id, relation_type, target_entity_id, confidence, properties, tenant_id, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (agent_id, user_id, source_entity_id, relation_type, target_entity_id) DO UPDATE SET
+		confidence  = excluded.confidence,
+		properties  = excluded.properties,
+		tenant_id   = excluded.tenant_id`
+
+func (s *SQLiteKnowledgeGraphStore) UpsertRelation(ctx context.Context, relation *store.Relation) error {
+	props, err := json.Marshal(relation.Properties)
+	if err != nil {
+		props = []byte("{}")
+	}
+	_, err = s.db.ExecContext(ctx, relationUpsertSQL,
+		uuid.Must(uuid.NewV7()), relation.AgentID, relation.UserID, relation.SourceEntityID, relation.RelationType,
+		relation.TargetEntityID, relation.Confidence, string(props), tenantIDForInsert(ctx), time.Now().UTC(),
+	)
+	return err
+}
+
+func (s *SQLiteKnowledgeGraphStore) DeleteRelation(ctx context.Context, agentID, userID, relationID string) error {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return err
+	}
+	where := "id = ? AND agent_id = ?"
+	args := []any{relationID, agentID}
+	if !store.IsSharedKG(ctx) {
+		where += " AND user_id = ?"
+		args = append(args, userID)
+	}
+	_, err = s.db.ExecContext(ctx, `DELETE FROM kg_relations WHERE `+where+tc, append(args, tcArgs...)...)
+	return err
+}
+
+func (s *SQLiteKnowledgeGraphStore) ListRelations(ctx context.Context, agentID, userID, entityID string) ([]store.Relation, error) {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	where := "agent_id = ?"
+	args := []any{agentID}
+	if !store.IsSharedKG(ctx) {
+		where += " AND user_id = ?"
+		args = append(args, userID)
+	}
+	where += " AND (source_entity_id = ? OR target_entity_id = ?)"
+	args = append(args, entityID, entityID)
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+relationSelectCols+` FROM kg_relations WHERE `+where+tc+`
+		 ORDER BY created_at DESC`, append(args, tcArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRelations(rows)
+}
+
+func (s *SQLiteKnowledgeGraphStore) ListAllRelations(ctx context.Context, agentID, userID string, limit int) ([]store.Relation, error) {
+	if limit <= 0 {
+		limit = 200
+	}
+	where, args, err := kgScopeWhere(ctx, "", agentID, userID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+relationSelectCols+` FROM kg_relations WHERE `+where+`
+		 ORDER BY created_at DESC LIMIT ?`, append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRelations(rows)
+}
+
+func (s *SQLiteKnowledgeGraphStore) IngestExtraction(ctx context.Context, agentID, userID string, entities []store.Entity, relations []store.Relation) ([]string, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := time.Now().UTC()
+	tid := tenantIDForInsert(ctx)
+
+	// Upsert entities and build external_id → DB ID lookup for relations
+	extIDToID := make(map[string]string, len(entities))
+	for i := range entities {
+		e := &entities[i]
+		e.AgentID = agentID
+		e.UserID = userID
+		props, _ := json.Marshal(e.Properties)
+		// RETURNING yields the existing row's ID on conflict.
+		var actualID string
+		if err := tx.QueryRowContext(ctx, entityUpsertSQL,
+			uuid.Must(uuid.NewV7()), agentID, userID, e.ExternalID, e.Name, e.EntityType,
+			e.Description, string(props), e.SourceID, e.Confidence, tid, now,
+		).Scan(&actualID); err != nil {
+			return nil, err
+		}
+		extIDToID[e.ExternalID] = actualID
+	}
+
+	// Batch-generate embeddings for all upserted entities (best-effort).
+	if s.embProvider != nil && len(extIDToID) > 0 {
+		texts := make([]string, 0, len(entities))
+		ids := make([]string, 0, len(entities))
+		for _, e := range entities {
+			texts = append(texts, e.Name+" "+e.Description)
+			ids = append(ids, extIDToID[e.ExternalID])
+		}
+		embeddings, embErr := s.embProvider.Embed(ctx, texts)
+		if embErr != nil {
+			slog.Warn("kg entity embedding batch failed", "error", embErr)
+		} else {
+			for i, emb := range embeddings {
+				if i >= len(ids) || len(emb) == 0 {
+					continue
+				}
+				if _, err := tx.ExecContext(ctx,
+					`UPDATE kg_entities SET embedding = ? WHERE id = ?`, encodeVector(emb), ids[i],
+				); err != nil {
+					slog.Warn("kg entity embedding update failed", "entity_id", ids[i], "error", err)
+				}
+			}
+		}
+	}
+
+	for i := range relations {
+		r := &relations[i]
+		r.AgentID = agentID
+		r.UserID = userID
+		// Resolve external_id references to actual DB IDs
+		src, ok1 := extIDToID[r.SourceEntityID]
+		tgt, ok2 := extIDToID[r.TargetEntityID]
+		if !ok1 || !ok2 {
+			continue // skip relations referencing unknown entities
+		}
+		props, _ := json.Marshal(r.Properties)
+		if _, err := tx.ExecContext(ctx, relationUpsertSQL,
+			uuid.Must(uuid.NewV7()), agentID, userID, src, r.RelationType, tgt, r.Confidence, string(props), tid, now,
+		); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Collect upserted entity IDs for downstream processing (e.g. dedup)
+	entityIDs := make([]string, 0, len(extIDToID))
+	for _, id := range extIDToID {
+		entityIDs = append(entityIDs, id)
+	}
+	return entityIDs, nil
+}
+
+func (s *SQLiteKnowledgeGraphStore) PruneByConfidence(ctx context.Context, agentID, userID string, minConfidence float64) (int, error) {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return 0, err
+	}
+	where := "agent_id = ?"
+	args := []any{agentID}
+	if !store.IsSharedKG(ctx) {
+		where += " AND user_id = ?"
+		args = append(args, userID)
+	}
+	args = append(args, minConfidence)
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM kg_entities WHERE `+where+` AND confidence < ?`+tc, append(args, tcArgs...)...)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+func (s *SQLiteKnowledgeGraphStore) Stats(ctx context.Context, agentID, userID string) (*store.GraphStats, error) {
+	stats := &store.GraphStats{EntityTypes: make(map[string]int)}
+
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	where := "agent_id = ?"
+	args := []any{agentID}
+	if userID != "" {
+		where += " AND user_id = ?"
+		args = append(args, userID)
+	}
+	where += tc
+	args = append(args, tcArgs...)
+
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM kg_entities WHERE `+where, args...,
+	).Scan(&stats.EntityCount); err != nil {
+		return nil, err
+	}
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM kg_relations WHERE `+where, args...,
+	).Scan(&stats.RelationCount); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT entity_type, COUNT(*) FROM kg_entities WHERE `+where+` GROUP BY entity_type`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t string
+		var c int
+		if err := rows.Scan(&t, &c); err != nil {
+			continue
+		}
+		stats.EntityTypes[t] = c
+	}
+
+	// Fetch distinct user IDs (only when not filtering by specific user)
+	if userID == "" {
+		uidRows, uidErr := s.db.QueryContext(ctx,
+			`SELECT DISTINCT user_id FROM kg_entities WHERE agent_id = ?`+tc+` AND user_id != '' ORDER BY user_id`,
+			append([]any{agentID}, tcArgs...)...,
+		)
+		if uidErr == nil {
+			defer uidRows.Close()
+			for uidRows.Next() {
+				var uid string
+				if uidRows.Scan(&uid) == nil && uid != "" {
+					stats.UserIDs = append(stats.UserIDs, uid)
+				}
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+func scanRelations(rows *sql.Rows) ([]store.Relation, error) {
+	var result []store.Relation
+	for rows.Next() {
+		var r store.Relation
+		var props []byte
+		createdAt := &sqliteTime{}
+		if err := rows.Scan(
+			&r.ID, &r.AgentID, &r.UserID, &r.SourceEntityID, &r.RelationType,
+			&r.TargetEntityID, &r.Confidence, &props, createdAt,
+		); err != nil {
+			continue
+		}
+		json.Unmarshal(props, &r.Properties) //nolint:errcheck
+		r.CreatedAt = createdAt.Time.UnixMilli()
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,272 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func createTestKGAgent(t *testing.T, agentStore *SQLiteAgentStore, ctx context.Context, key string) *store.AgentData {
+	t.Helper()
+	agent := &store.AgentData{
+		TenantID:          store.MasterTenantID,
+		AgentKey:          key,
+		DisplayName:       key,
+		OwnerID:           "user-1",
+		Provider:          "openai",
+		Model:             "gpt-4.1-mini",
+		ContextWindow:     32000,
+		MaxToolIterations: 8,
+		Workspace:         ".",
+		AgentType:         store.AgentTypeOpen,
+		Status:            store.AgentStatusActive,
+	}
+	if err := agentStore.Create(ctx, agent); err != nil {
+		t.Fatalf("Create agent %s: %v", key, err)
+	}
+	return agent
+}
+
+func TestSQLiteKnowledgeGraphStore_IngestTraverseMerge(t *testing.T) {
+	agentStore, ctx, db := newTestSQLiteAgentStore(t)
+	agent := createTestKGAgent(t, agentStore, ctx, "kg-agent")
+	agentID := agent.ID.String()
+
+	kgStore := NewSQLiteKnowledgeGraphStore(db)
+	ids, err := kgStore.IngestExtraction(ctx, agentID, "user-1",
+		[]store.Entity{
+			{ExternalID: "alice", Name: "Alice", EntityType: "person", Confidence: 0.9},
+			{ExternalID: "acme", Name: "Acme Corp", EntityType: "organization", Confidence: 0.9},
+			{ExternalID: "acme-dup", Name: "ACME", EntityType: "organization", Confidence: 0.5},
+			{ExternalID: "paris", Name: "Paris", EntityType: "location", Confidence: 0.8},
+		},
+		[]store.Relation{
+			{SourceEntityID: "alice", RelationType: "works_at", TargetEntityID: "acme", Confidence: 0.9},
+			{SourceEntityID: "alice", RelationType: "works_at", TargetEntityID: "acme-dup", Confidence: 0.6},
+			{SourceEntityID: "acme-dup", RelationType: "located_in", TargetEntityID: "paris", Confidence: 0.7},
+			{SourceEntityID: "alice", RelationType: "knows", TargetEntityID: "missing", Confidence: 0.7},
+		})
+	if err != nil {
+		t.Fatalf("IngestExtraction: %v", err)
+	}
+	if len(ids) != 4 {
+		t.Fatalf("IngestExtraction returned %d ids, want 4", len(ids))
+	}
+
+	byName := map[string]string{}
+	entities, err := kgStore.ListEntities(ctx, agentID, "user-1", store.EntityListOptions{})
+	if err != nil {
+		t.Fatalf("ListEntities: %v", err)
+	}
+	for _, e := range entities {
+		byName[e.Name] = e.ID
+	}
+
+	// Alice -> ACME -> Paris is reachable within three levels; the reverse edge is marked with "~".
+	results, err := kgStore.Traverse(ctx, agentID, "user-1", byName["Alice"], 3)
+	if err != nil {
+		t.Fatalf("Traverse: %v", err)
+	}
+	var paris *store.TraversalResult
+	for i := range results {
+		if results[i].Entity.Name == "Paris" {
+			paris = &results[i]
+		}
+	}
+	if paris == nil {
+		t.Fatalf("Traverse did not reach Paris: %+v", results)
+	}
+	if paris.Depth != 3 || len(paris.Path) != 3 || paris.Path[0] != byName["Alice"] || paris.Via != "located_in" {
+		t.Fatalf("Paris traversal = depth %d path %v via %q", paris.Depth, paris.Path, paris.Via)
+	}
+	back, err := kgStore.Traverse(ctx, agentID, "user-1", byName["Paris"], 1)
+	if err != nil {
+		t.Fatalf("Traverse reverse: %v", err)
+	}
+	if len(back) != 0 {
+		t.Fatalf("maxDepth 1 should return no neighbours, got %d", len(back))
+	}
+	back, _ = kgStore.Traverse(ctx, agentID, "user-1", byName["Paris"], 2)
+	if len(back) != 1 || back[0].Via != "~located_in" {
+		t.Fatalf("reverse traversal = %+v, want one ~located_in hop", back)
+	}
+
+	// Merging ACME into Acme Corp drops the duplicate works_at edge and re-points located_in.
+	if err := kgStore.MergeEntities(ctx, agentID, "user-1", byName["Acme Corp"], byName["ACME"]); err != nil {
+		t.Fatalf("MergeEntities: %v", err)
+	}
+	if _, err := kgStore.GetEntity(ctx, agentID, "user-1", byName["ACME"]); err == nil {
+		t.Fatal("source entity should be deleted after merge")
+	}
+	rels, err := kgStore.ListRelations(ctx, agentID, "user-1", byName["Acme Corp"])
+	if err != nil {
+		t.Fatalf("ListRelations: %v", err)
+	}
+	if len(rels) != 2 {
+		t.Fatalf("merged entity has %d relations, want 2: %+v", len(rels), rels)
+	}
+
+	stats, err := kgStore.Stats(ctx, agentID, "user-1")
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.EntityCount != 3 || stats.RelationCount != 2 || stats.EntityTypes["organization"] != 1 {
+		t.Fatalf("Stats = %+v", stats)
+	}
+}
+
+func TestSQLiteKnowledgeGraphStore_DedupAndSearch(t *testing.T) {
+	agentStore, ctx, db := newTestSQLiteAgentStore(t)
+	agent := createTestKGAgent(t, agentStore, ctx, "kg-dedup-agent")
+	agentID := agent.ID.String()
+
+	kgStore := NewSQLiteKnowledgeGraphStore(db)
+	kgStore.SetEmbeddingProvider(&keywordEmbedder{})
+	ids, err := kgStore.IngestExtraction(ctx, agentID, "user-1", []store.Entity{
+		{ExternalID: "cat-1", Name: "Kitten", EntityType: "animal", Confidence: 0.9},
+		{ExternalID: "cat-2", Name: "Kitten.", EntityType: "animal", Confidence: 0.4},
+		{ExternalID: "feline", Name: "Feline", EntityType: "animal", Confidence: 0.8},
+		{ExternalID: "car", Name: "Sedan", EntityType: "animal", Confidence: 0.8},
+	}, nil)
+	if err != nil {
+		t.Fatalf("IngestExtraction: %v", err)
+	}
+
+	// Identical vectors with near-identical names auto-merge; Feline shares the
+	// vector but not the name, so it is only flagged for review.
+	merged, flagged, err := kgStore.DedupAfterExtraction(ctx, agentID, "user-1", ids)
+	if err != nil {
+		t.Fatalf("DedupAfterExtraction: %v", err)
+	}
+	if merged != 1 || flagged == 0 {
+		t.Fatalf("DedupAfterExtraction merged=%d flagged=%d, want 1 merged and some flagged", merged, flagged)
+	}
+
+	candidates, err := kgStore.ListDedupCandidates(ctx, agentID, "user-1", 10)
+	if err != nil {
+		t.Fatalf("ListDedupCandidates: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].Similarity < dedupCandidateThreshold {
+		t.Fatalf("ListDedupCandidates = %+v", candidates)
+	}
+	if err := kgStore.DismissCandidate(ctx, agentID, candidates[0].ID); err != nil {
+		t.Fatalf("DismissCandidate: %v", err)
+	}
+	if err := kgStore.DismissCandidate(ctx, agentID, candidates[0].ID); err != sql.ErrNoRows {
+		t.Fatalf("second DismissCandidate = %v, want sql.ErrNoRows", err)
+	}
+
+	found, err := kgStore.SearchEntities(ctx, agentID, "user-1", "kitten", 5)
+	if err != nil {
+		t.Fatalf("SearchEntities: %v", err)
+	}
+	if len(found) < 2 || found[0].Name != "Kitten" {
+		t.Fatalf("SearchEntities = %+v, want Kitten first", found)
+	}
+	for _, e := range found {
+		if e.Name == "Sedan" {
+			t.Fatalf("SearchEntities should not return unrelated Sedan: %+v", found)
+		}
+	}
+}
+
+func TestSQLiteAgentLinkStore_DelegateTargets(t *testing.T) {
+	agentStore, ctx, db := newTestSQLiteAgentStore(t)
+	lead := createTestKGAgent(t, agentStore, ctx, "lead-agent")
+	writer := createTestKGAgent(t, agentStore, ctx, "writer-agent")
+	coder := createTestKGAgent(t, agentStore, ctx, "coder-agent")
+
+	links := NewSQLiteAgentLinkStore(db)
+	for _, l := range []*store.AgentLinkData{
+		{SourceAgentID: lead.ID, TargetAgentID: writer.ID, Direction: store.LinkDirectionOutbound, Status: store.LinkStatusActive, CreatedBy: "user-1"},
+		{SourceAgentID: coder.ID, TargetAgentID: lead.ID, Direction: store.LinkDirectionBidirectional, Status: store.LinkStatusActive, CreatedBy: "user-1"},
+	} {
+		if err := links.CreateLink(ctx, l); err != nil {
+			t.Fatalf("CreateLink: %v", err)
+		}
+	}
+
+	targets, err := links.DelegateTargets(ctx, lead.ID)
+	if err != nil {
+		t.Fatalf("DelegateTargets: %v", err)
+	}
+	got := map[string]bool{}
+	for _, tgt := range targets {
+		got[tgt.TargetAgentKey] = true
+	}
+	if len(targets) != 2 || !got["writer-agent"] || !got["coder-agent"] {
+		t.Fatalf("DelegateTargets = %+v, want writer-agent and coder-agent", targets)
+	}
+
+	if ok, _ := links.CanDelegate(ctx, writer.ID, lead.ID); ok {
+		t.Fatal("outbound link must not allow reverse delegation")
+	}
+	if ok, _ := links.CanDelegate(ctx, lead.ID, coder.ID); !ok {
+		t.Fatal("bidirectional link should allow delegation from either side")
+	}
+
+	found, err := links.SearchDelegateTargets(ctx, lead.ID, "coder", 5)
+	if err != nil {
+		t.Fatalf("SearchDelegateTargets: %v", err)
+	}
+	if len(found) != 1 || found[0].TargetAgentKey != "coder-agent" {
+		t.Fatalf("SearchDelegateTargets = %+v", found)
+	}
+}
+
+func TestSQLiteSecureCLIStore_LookupByBinary(t *testing.T) {
+	agentStore, ctx, db := newTestSQLiteAgentStore(t)
+	agent := createTestKGAgent(t, agentStore, ctx, "cli-agent")
+
+	cli := NewSQLiteSecureCLIStore(db, "")
+	grants := NewSQLiteSecureCLIAgentGrantStore(db)
+	bin := &store.SecureCLIBinary{
+		BinaryName:     "gh",
+		Description:    "GitHub CLI",
+		EncryptedEnv:   []byte(`{"GH_TOKEN":"shared"}`),
+		DenyArgs:       json.RawMessage(`["auth"]`),
+		TimeoutSeconds: 30,
+		Enabled:        true,
+		CreatedBy:      "user-1",
+	}
+	if err := cli.Create(ctx, bin); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Non-global binaries require an enabled grant.
+	if got, err := cli.LookupByBinary(ctx, "gh", &agent.ID, ""); err != nil || got != nil {
+		t.Fatalf("LookupByBinary without grant = %+v, %v; want nil", got, err)
+	}
+	timeout := 90
+	if err := grants.Create(ctx, &store.SecureCLIAgentGrant{
+		BinaryID: bin.ID, AgentID: agent.ID, TimeoutSeconds: &timeout, Enabled: true,
+	}); err != nil {
+		t.Fatalf("grant Create: %v", err)
+	}
+	if err := cli.SetUserCredentials(ctx, bin.ID, "user-1", []byte(`{"GH_TOKEN":"mine"}`)); err != nil {
+		t.Fatalf("SetUserCredentials: %v", err)
+	}
+
+	got, err := cli.LookupByBinary(ctx, "gh", &agent.ID, "user-1")
+	if err != nil || got == nil {
+		t.Fatalf("LookupByBinary with grant = %+v, %v", got, err)
+	}
+	if got.TimeoutSeconds != 90 {
+		t.Fatalf("grant override not merged: timeout = %d", got.TimeoutSeconds)
+	}
+	if string(got.UserEnv) != `{"GH_TOKEN":"mine"}` || string(got.EncryptedEnv) != `{"GH_TOKEN":"shared"}` {
+		t.Fatalf("env = %q user env = %q", got.EncryptedEnv, got.UserEnv)
+	}
+
+	other := uuid.New()
+	if got, _ := cli.LookupByBinary(ctx, "gh", &other, ""); got != nil {
+		t.Fatal("agent without grant must not see non-global binary")
+	}
+}
@@ -0,0 +1,112 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// traverseTimeout bounds recursive CTE walks; SQLite has no statement_timeout.
+const traverseTimeout = 5 * time.Second
+
+// Traverse walks the knowledge graph from startEntityID up to maxDepth hops
+// using a recursive CTE. Returns all reachable entities (excluding the start node).
+// The path is tracked as a comma-delimited string (",id1,id2,") for cycle checks.
+func (s *SQLiteKnowledgeGraphStore) Traverse(ctx context.Context, agentID, userID, startEntityID string, maxDepth int) ([]store.TraversalResult, error) {
+	if maxDepth <= 0 {
+		maxDepth = 3
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, traverseTimeout)
+	defer cancel()
+
+	tc, tcArgs, err := scopeClauseAlias(ctx, "e")
+	if err != nil {
+		return nil, err
+	}
+
+	// Shared KG walks every user's edges for the agent; otherwise edges are per-user.
+	anchorWhere := "e.id = ? AND e.agent_id = ?"
+	edgeScope := "r.agent_id = ?"
+	nodeScope := "e.agent_id = ?"
+	anchorArgs := []any{startEntityID, agentID}
+	stepArgs := []any{agentID, agentID}
+	if !store.IsSharedKG(ctx) {
+		anchorWhere += " AND e.user_id = ?"
+		edgeScope += " AND r.user_id = ?"
+		nodeScope += " AND e.user_id = ?"
+		anchorArgs = append(anchorArgs, userID)
+		stepArgs = []any{agentID, userID, agentID, userID}
+	}
+
+	q := `
+	WITH RECURSIVE paths AS (
+		SELECT
+			e.id, e.agent_id, e.user_id, e.external_id,
+			e.name, e.entity_type, COALESCE(e.description, '') AS description,
+			COALESCE(e.properties, '{}') AS properties, COALESCE(e.source_id, '') AS source_id, e.confidence,
+			e.created_at, e.updated_at,
+			1 AS depth,
+			',' || e.id || ',' AS path,
+			'' AS via
+		FROM kg_entities e
+		WHERE ` + anchorWhere + tc + `
+
+		UNION ALL
+
+		SELECT
+			e.id, e.agent_id, e.user_id, e.external_id,
+			e.name, e.entity_type, COALESCE(e.description, ''),
+			COALESCE(e.properties, '{}'), COALESCE(e.source_id, ''), e.confidence,
+			e.created_at, e.updated_at,
+			p.depth + 1,
+			p.path || e.id || ',',
+			CASE WHEN r.source_entity_id = p.id
+				THEN r.relation_type
+				ELSE '~' || r.relation_type
+			END
+		FROM paths p
+		JOIN kg_relations r ON (r.source_entity_id = p.id OR r.target_entity_id = p.id) AND ` + edgeScope + `
+		JOIN kg_entities  e ON e.id = (CASE WHEN r.source_entity_id = p.id THEN r.target_entity_id ELSE r.source_entity_id END) AND ` + nodeScope + `
+		WHERE p.depth < ?
+		  AND instr(p.path, ',' || e.id || ',') = 0
+	)
+	SELECT
+		id, agent_id, user_id, external_id,
+		name, entity_type, description,
+		properties, source_id, confidence,
+		created_at, updated_at,
+		depth, path, via
+	FROM paths WHERE depth > 1`
+
+	args := append(anchorArgs, tcArgs...)
+	args = append(args, stepArgs...)
+	args = append(args, maxDepth)
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []store.TraversalResult
+	for rows.Next() {
+		var depth int
+		var path, via string
+		e, err := scanEntityWith(rows, &depth, &path, &via)
+		if err != nil {
+			continue
+		}
+		results = append(results, store.TraversalResult{
+			Entity: *e,
+			Depth:  depth,
+			Path:   strings.Split(strings.Trim(path, ","), ","),
+			Via:    via,
+		})
+	}
+	return results, rows.Err()
+}
@@ -11,8 +11,8 @@ import (
 )
 
 // SQLiteMemoryStore implements store.MemoryStore backed by SQLite.
-// Vector search is not available in the Lite edition (VectorSearch: false).
-// FTS uses simple LIKE queries instead of tsvector.
+// Embeddings are persisted as float32 BLOBs and searched by brute-force cosine
+// similarity; text matching uses simple LIKE queries instead of tsvector.
 type SQLiteMemoryStore struct {
 	db       *sql.DB
 	provider store.EmbeddingProvider
@@ -35,8 +35,8 @@ func DefaultSQLiteMemoryConfig() SQLiteMemoryConfig {
 		MaxChunkLen:  1000,
 		ChunkOverlap: 200,
 		MaxResults:   6,
-		TextWeight:   1.0,
-		VectorWeight: 0.0, // no vector search in SQLite edition
+		TextWeight:   0.3,
+		VectorWeight: 0.7,
 	}
 }
 
@@ -45,8 +45,8 @@ func NewSQLiteMemoryStore(db *sql.DB) *SQLiteMemoryStore {
 	return &SQLiteMemoryStore{db: db, cfg: DefaultSQLiteMemoryConfig()}
 }
 
-// SetEmbeddingProvider stores the provider reference but embeddings are not
-// persisted in SQLite (no vector column). Kept for interface compatibility.
+// SetEmbeddingProvider sets the provider used to embed chunks on index and
+// queries on search. Without a provider, Search degrades to LIKE-only.
 func (s *SQLiteMemoryStore) SetEmbeddingProvider(provider store.EmbeddingProvider) {
 	s.provider = provider
 }
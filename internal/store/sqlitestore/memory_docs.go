@@ -138,7 +138,7 @@ func (s *SQLiteMemoryStore) ListDocuments(ctx context.Context, agentID, userID s
 	return result, nil
 }
 
-// IndexDocument chunks a document and stores chunks (without embeddings in SQLite).
+// IndexDocument chunks a document and stores chunks with embeddings (when a provider is set).
 func (s *SQLiteMemoryStore) IndexDocument(ctx context.Context, agentID, userID, path string) error {
 	content, err := s.GetDocument(ctx, agentID, userID, path)
 	if err != nil {
@@ -195,16 +195,27 @@ func (s *SQLiteMemoryStore) IndexDocument(ctx context.Context, agentID, userID,
 		uid = &userID
 	}
 
-	for _, tc := range chunks {
+	texts := make([]string, len(chunks))
+	for i, c := range chunks {
+		texts[i] = c.Text
+	}
+	embeddings := s.embedTexts(ctx, texts)
+
+	for i, tc := range chunks {
 		hash := memory.ContentHash(tc.Text)
 		chunkID := uuid.Must(uuid.NewV7()).String()
 		now := time.Now().UTC()
 
+		var embedding []byte
+		if i < len(embeddings) {
+			embedding = encodeVector(embeddings[i])
+		}
+
 		if _, err := s.db.ExecContext(ctx,
-			`INSERT INTO memory_chunks (id, agent_id, document_id, user_id, path, start_line, end_line, hash, text, tenant_id, updated_at)
-			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			`INSERT INTO memory_chunks (id, agent_id, document_id, user_id, path, start_line, end_line, hash, text, embedding, tenant_id, updated_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 			 ON CONFLICT DO NOTHING`,
-			chunkID, agentID, docID, uid, path, tc.StartLine, tc.EndLine, hash, tc.Text, tid, now,
+			chunkID, agentID, docID, uid, path, tc.StartLine, tc.EndLine, hash, tc.Text, embedding, tid, now,
 		); err != nil {
 			slog.Warn("memory: insert chunk failed", "path", path, "error", err)
 		}
@@ -299,8 +310,7 @@ func (s *SQLiteMemoryStore) ListAllDocuments(ctx context.Context, agentID string
 	return result, nil
 }
 
-// GetDocumentDetail returns full document info with chunk count.
-// EmbeddedCount is always 0 (no embedding column in SQLite).
+// GetDocumentDetail returns full document info with chunk and embedded-chunk counts.
 func (s *SQLiteMemoryStore) GetDocumentDetail(ctx context.Context, agentID, userID, path string) (*store.DocumentDetail, error) {
 	var q string
 	var args []any
@@ -311,7 +321,7 @@ func (s *SQLiteMemoryStore) GetDocumentDetail(ctx context.Context, agentID, user
 			return nil, err
 		}
 		q = `SELECT d.path, d.content, d.hash, d.user_id, d.created_at, d.updated_at,
-				COUNT(c.id) AS chunk_count
+				COUNT(c.id) AS chunk_count, COUNT(c.embedding) AS embedded_count
 			 FROM memory_documents d
 			 LEFT JOIN memory_chunks c ON c.document_id = d.id
 			 WHERE d.agent_id = ? AND d.path = ? AND d.user_id IS NULL` + tc + `
@@ -323,7 +333,7 @@ func (s *SQLiteMemoryStore) GetDocumentDetail(ctx context.Context, agentID, user
 			return nil, err
 		}
 		q = `SELECT d.path, d.content, d.hash, d.user_id, d.created_at, d.updated_at,
-				COUNT(c.id) AS chunk_count
+				COUNT(c.id) AS chunk_count, COUNT(c.embedding) AS embedded_count
 			 FROM memory_documents d
 			 LEFT JOIN memory_chunks c ON c.document_id = d.id
 			 WHERE d.agent_id = ? AND d.path = ? AND d.user_id = ?` + tc + `
@@ -336,7 +346,7 @@ func (s *SQLiteMemoryStore) GetDocumentDetail(ctx context.Context, agentID, user
 	var createdAt, updatedAt time.Time
 	err := s.db.QueryRowContext(ctx, q, args...).Scan(
 		&detail.Path, &detail.Content, &detail.Hash, &uid,
-		&createdAt, &updatedAt, &detail.ChunkCount,
+		&createdAt, &updatedAt, &detail.ChunkCount, &detail.EmbeddedCount,
 	)
 	if err != nil {
 		return nil, err
@@ -346,7 +356,6 @@ func (s *SQLiteMemoryStore) GetDocumentDetail(ctx context.Context, agentID, user
 	}
 	detail.CreatedAt = createdAt.UnixMilli()
 	detail.UpdatedAt = updatedAt.UnixMilli()
-	// EmbeddedCount always 0 — no embedding column in SQLite
 	return &detail, nil
 }
 
@@ -360,7 +369,7 @@ func (s *SQLiteMemoryStore) ListChunks(ctx context.Context, agentID, userID, pat
 		if err != nil {
 			return nil, err
 		}
-		q = `SELECT c.id, c.start_line, c.end_line, c.text
+		q = `SELECT c.id, c.start_line, c.end_line, c.text, c.embedding IS NOT NULL
 			 FROM memory_chunks c
 			 JOIN memory_documents d ON c.document_id = d.id
 			 WHERE d.agent_id = ? AND d.path = ? AND d.user_id IS NULL` + tc + `
@@ -371,7 +380,7 @@ func (s *SQLiteMemoryStore) ListChunks(ctx context.Context, agentID, userID, pat
 		if err != nil {
 			return nil, err
 		}
-		q = `SELECT c.id, c.start_line, c.end_line, c.text
+		q = `SELECT c.id, c.start_line, c.end_line, c.text, c.embedding IS NOT NULL
 			 FROM memory_chunks c
 			 JOIN memory_documents d ON c.document_id = d.id
 			 WHERE d.agent_id = ? AND d.path = ? AND d.user_id = ?` + tc + `
@@ -388,10 +397,9 @@ func (s *SQLiteMemoryStore) ListChunks(ctx context.Context, agentID, userID, pat
 	var result []store.ChunkInfo
 	for rows.Next() {
 		var ci store.ChunkInfo
-		if err := rows.Scan(&ci.ID, &ci.StartLine, &ci.EndLine, &ci.TextPreview); err != nil {
+		if err := rows.Scan(&ci.ID, &ci.StartLine, &ci.EndLine, &ci.TextPreview, &ci.HasEmbedding); err != nil {
 			continue
 		}
-		// HasEmbedding always false — no embedding column in SQLite
 		result = append(result, ci)
 	}
 	if err := rows.Err(); err != nil {
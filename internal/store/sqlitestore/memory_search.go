@@ -9,23 +9,52 @@ import (
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
-// Search performs LIKE-based text search over memory_chunks.
-// Vector search is not available in the SQLite edition — VectorSearch is always false.
-// Merges global (user_id IS NULL) + per-user chunks, with user boost.
+// Search performs hybrid search over memory_chunks: LIKE-based text matching
+// plus brute-force cosine similarity over stored embeddings when an embedding
+// provider is configured. Scores are merged with the same text/vector weighting
+// as the Postgres store. Merges global (user_id IS NULL) + per-user chunks, with user boost.
 func (s *SQLiteMemoryStore) Search(ctx context.Context, query string, agentID, userID string, opts store.MemorySearchOptions) ([]store.MemorySearchResult, error) {
 	maxResults := opts.MaxResults
 	if maxResults <= 0 {
 		maxResults = s.cfg.MaxResults
 	}
 
-	results, err := s.likeSearch(ctx, query, agentID, userID, maxResults*2)
+	textResults, err := s.likeSearch(ctx, query, agentID, userID, maxResults*2)
 	if err != nil {
 		return nil, err
 	}
 
+	var vecResults []scoredChunk
+	if s.provider != nil {
+		embeddings, embErr := s.provider.Embed(ctx, []string{query})
+		if embErr == nil && len(embeddings) > 0 {
+			vecResults, err = s.vectorSearch(ctx, embeddings[0], agentID, userID, maxResults*2)
+			if err != nil {
+				vecResults = nil
+			}
+		}
+	}
+
+	// Merge results — use per-query overrides if set, else store defaults
+	s.mu.RLock()
+	textW, vecW := s.cfg.TextWeight, s.cfg.VectorWeight
+	s.mu.RUnlock()
+	if opts.TextWeight > 0 {
+		textW = opts.TextWeight
+	}
+	if opts.VectorWeight > 0 {
+		vecW = opts.VectorWeight
+	}
+	if len(textResults) == 0 && len(vecResults) > 0 {
+		textW, vecW = 0, 1.0
+	} else if len(vecResults) == 0 && len(textResults) > 0 {
+		textW, vecW = 1.0, 0
+	}
+	merged := hybridMerge(textResults, vecResults, textW, vecW)
+
 	// Apply filters and cap results
 	var filtered []store.MemorySearchResult
-	for _, r := range results {
+	for _, r := range merged {
 		if opts.MinScore > 0 && r.Score < opts.MinScore {
 			continue
 		}
@@ -41,8 +70,8 @@ func (s *SQLiteMemoryStore) Search(ctx context.Context, query string, agentID, u
 }
 
 // likeSearch performs a case-insensitive LIKE search across chunk text.
-// Returns results scored 1.0 (global) or 1.2 (personal, boosted).
-func (s *SQLiteMemoryStore) likeSearch(ctx context.Context, query, agentID, userID string, limit int) ([]store.MemorySearchResult, error) {
+// Every match scores 1.0; the personal boost is applied by hybridMerge.
+func (s *SQLiteMemoryStore) likeSearch(ctx context.Context, query, agentID, userID string, limit int) ([]scoredChunk, error) {
 	pattern := "%" + escapeLike(query) + "%"
 
 	var q string
@@ -81,29 +110,13 @@ func (s *SQLiteMemoryStore) likeSearch(ctx context.Context, query, agentID, user
 	}
 	defer rows.Close()
 
-	var results []store.MemorySearchResult
+	var results []scoredChunk
 	for rows.Next() {
-		var path, text string
-		var startLine, endLine int
-		var uid *string
-		if err := rows.Scan(&path, &startLine, &endLine, &text, &uid); err != nil {
+		r := scoredChunk{Score: 1.0}
+		if err := rows.Scan(&r.Path, &r.StartLine, &r.EndLine, &r.Text, &r.UserID); err != nil {
 			continue
 		}
-		scope := "global"
-		score := 1.0
-		if uid != nil && *uid != "" {
-			scope = "personal"
-			score = 1.2 // personal boost, mirrors PG hybrid merge
-		}
-		results = append(results, store.MemorySearchResult{
-			Path:      path,
-			StartLine: startLine,
-			EndLine:   endLine,
-			Score:     score,
-			Snippet:   text,
-			Source:    "memory",
-			Scope:     scope,
-		})
+		results = append(results, r)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
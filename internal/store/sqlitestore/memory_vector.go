@@ -0,0 +1,336 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/nextlevelbuilder/goclaw/internal/memory"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// Embeddings are stored as little-endian float32 BLOBs (4 bytes per dimension).
+// Search is brute-force cosine similarity in Go: memory corpora in the SQLite
+// edition are per-agent and small enough that a full scan beats maintaining an index.
+
+// encodeVector serializes an embedding into a BLOB.
+func encodeVector(v []float32) []byte {
+	if len(v) == 0 {
+		return nil
+	}
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(f))
+	}
+	return buf
+}
+
+// decodeVector deserializes a BLOB produced by encodeVector.
+// Returns nil for empty or malformed input.
+func decodeVector(b []byte) []float32 {
+	if len(b) == 0 || len(b)%4 != 0 {
+		return nil
+	}
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[i*4:]))
+	}
+	return v
+}
+
+// cosineSimilarity returns the cosine similarity of a and b, or 0 when the
+// dimensions differ (e.g. chunks embedded by a previous provider/model).
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		x, y := float64(a[i]), float64(b[i])
+		dot += x * y
+		na += x * x
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// scoredChunk is a search hit before hybrid merging.
+type scoredChunk struct {
+	Path      string
+	StartLine int
+	EndLine   int
+	Text      string
+	Score     float64
+	UserID    *string
+}
+
+// vectorSearch scans embedded chunks visible to (agentID, userID) and returns the
+// top `limit` by cosine similarity. Embeddings are scored first and only the
+// winning rows are re-read with their text to keep the scan cheap.
+func (s *SQLiteMemoryStore) vectorSearch(ctx context.Context, embedding []float32, agentID, userID string, limit int) ([]scoredChunk, error) {
+	tc, tcArgs, err := scopeClause(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var q string
+	var args []any
+	if userID != "" {
+		q = `SELECT id, embedding FROM memory_chunks
+			 WHERE agent_id = ? AND (user_id IS NULL OR user_id = ?) AND embedding IS NOT NULL` + tc
+		args = append([]any{agentID, userID}, tcArgs...)
+	} else {
+		q = `SELECT id, embedding FROM memory_chunks
+			 WHERE agent_id = ? AND user_id IS NULL AND embedding IS NOT NULL` + tc
+		args = append([]any{agentID}, tcArgs...)
+	}
+
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	type hit struct {
+		id    string
+		score float64
+	}
+	var hits []hit
+	for rows.Next() {
+		var id string
+		var blob []byte
+		if err := rows.Scan(&id, &blob); err != nil {
+			continue
+		}
+		score := cosineSimilarity(embedding, decodeVector(blob))
+		if score <= 0 {
+			continue
+		}
+		hits = append(hits, hit{id: id, score: score})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(hits) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	scores := make(map[string]float64, len(hits))
+	placeholders := make([]string, len(hits))
+	idArgs := make([]any, len(hits))
+	for i, h := range hits {
+		scores[h.id] = h.score
+		placeholders[i] = "?"
+		idArgs[i] = h.id
+	}
+	detailRows, err := s.db.QueryContext(ctx,
+		`SELECT id, path, start_line, end_line, text, user_id FROM memory_chunks
+		 WHERE id IN (`+strings.Join(placeholders, ",")+`)`, idArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer detailRows.Close()
+
+	results := make([]scoredChunk, 0, len(hits))
+	for detailRows.Next() {
+		var id string
+		var r scoredChunk
+		if err := detailRows.Scan(&id, &r.Path, &r.StartLine, &r.EndLine, &r.Text, &r.UserID); err != nil {
+			continue
+		}
+		r.Score = scores[id]
+		results = append(results, r)
+	}
+	if err := detailRows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results, nil
+}
+
+// hybridMerge combines text and vector results with weighted scoring.
+// Per-user results get a 1.2x boost. Deduplication: user copy wins over global.
+// Mirrors pg.hybridMerge so both editions rank identically.
+func hybridMerge(text, vec []scoredChunk, textWeight, vectorWeight float64) []store.MemorySearchResult {
+	type key struct {
+		Path      string
+		StartLine int
+	}
+	seen := make(map[key]*store.MemorySearchResult)
+
+	addResult := func(r scoredChunk, weight float64) {
+		k := key{r.Path, r.StartLine}
+		scope := "global"
+		boost := 1.0
+		if r.UserID != nil && *r.UserID != "" {
+			scope = "personal"
+			boost = 1.2
+		}
+		score := r.Score * weight * boost
+
+		if existing, ok := seen[k]; ok {
+			existing.Score += score
+			if scope == "personal" {
+				existing.Scope = "personal"
+				existing.Snippet = r.Text
+			}
+			return
+		}
+		seen[k] = &store.MemorySearchResult{
+			Path:      r.Path,
+			StartLine: r.StartLine,
+			EndLine:   r.EndLine,
+			Score:     score,
+			Snippet:   r.Text,
+			Source:    "memory",
+			Scope:     scope,
+		}
+	}
+
+	for _, r := range text {
+		addResult(r, textWeight)
+	}
+	for _, r := range vec {
+		addResult(r, vectorWeight)
+	}
+
+	results := make([]store.MemorySearchResult, 0, len(seen))
+	for _, r := range seen {
+		results = append(results, *r)
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results
+}
+
+// embedTexts returns one embedding per text (nil entries on failure), serving
+// repeated content from embedding_cache and writing fresh vectors back.
+func (s *SQLiteMemoryStore) embedTexts(ctx context.Context, texts []string) [][]float32 {
+	if s.provider == nil || len(texts) == 0 {
+		return nil
+	}
+	// The shared cached provider already serves repeats from the same table.
+	if _, shared := s.provider.(*memory.CachedEmbeddingProvider); shared {
+		embeddings, err := s.provider.Embed(ctx, texts)
+		if err != nil {
+			slog.Warn("memory embedding failed, storing chunks without vectors", "error", err)
+			return make([][]float32, len(texts))
+		}
+		return embeddings
+	}
+	providerName, providerModel := s.provider.Name(), s.provider.Model()
+	cache := NewSQLiteEmbeddingCacheStore(s.db)
+
+	hashes := make([]string, len(texts))
+	for i, t := range texts {
+		hashes[i] = memory.ContentHash(t)
+	}
+	cached, err := cache.LookupEmbeddings(ctx, hashes, providerName, providerModel)
+	if err != nil {
+		slog.Warn("embedding cache lookup failed, falling back to full API call", "error", err)
+		cached = nil
+	}
+
+	embeddings := make([][]float32, len(texts))
+	var missIdxs []int
+	var missTexts []string
+	for i, h := range hashes {
+		if emb, ok := cached[h]; ok {
+			embeddings[i] = emb
+			continue
+		}
+		missIdxs = append(missIdxs, i)
+		missTexts = append(missTexts, texts[i])
+	}
+	if len(missTexts) == 0 {
+		return embeddings
+	}
+
+	fresh, err := s.provider.Embed(ctx, missTexts)
+	if err != nil {
+		slog.Warn("memory embedding failed, storing chunks without vectors", "error", err)
+		return embeddings
+	}
+	if len(fresh) != len(missTexts) {
+		slog.Warn("embedding API returned mismatched count", "expected", len(missTexts), "got", len(fresh))
+	}
+	entries := make(map[string][]float32, len(fresh))
+	for j, emb := range fresh {
+		if j >= len(missIdxs) || len(emb) == 0 {
+			continue
+		}
+		idx := missIdxs[j]
+		embeddings[idx] = emb
+		entries[hashes[idx]] = emb
+	}
+	if err := cache.WriteEmbeddings(ctx, entries, providerName, providerModel); err != nil {
+		slog.Warn("embedding cache write failed", "error", err)
+	}
+	return embeddings
+}
+
+// BackfillEmbeddings generates embeddings for chunks stored without vectors
+// (written before a provider was configured or while it was failing).
+func (s *SQLiteMemoryStore) BackfillEmbeddings(ctx context.Context) (int, error) {
+	if s.provider == nil {
+		return 0, fmt.Errorf("no embedding provider configured")
+	}
+
+	const batchSize = 50
+	total := 0
+	for {
+		rows, err := s.db.QueryContext(ctx,
+			"SELECT id, text FROM memory_chunks WHERE embedding IS NULL ORDER BY id ASC LIMIT ?", batchSize)
+		if err != nil {
+			return total, fmt.Errorf("query chunks without embeddings: %w", err)
+		}
+		var ids, texts []string
+		for rows.Next() {
+			var id, text string
+			if err := rows.Scan(&id, &text); err != nil {
+				continue
+			}
+			ids = append(ids, id)
+			texts = append(texts, text)
+		}
+		rows.Close()
+		if len(ids) == 0 {
+			break
+		}
+
+		embeddings, err := s.provider.Embed(ctx, texts)
+		if err != nil {
+			return total, fmt.Errorf("generate embeddings: %w", err)
+		}
+		updated := 0
+		for i, id := range ids {
+			if i >= len(embeddings) || len(embeddings[i]) == 0 {
+				continue
+			}
+			if _, err := s.db.ExecContext(ctx,
+				"UPDATE memory_chunks SET embedding = ? WHERE id = ?", encodeVector(embeddings[i]), id,
+			); err != nil {
+				return total, fmt.Errorf("update chunk embedding id=%s: %w", id, err)
+			}
+			updated++
+		}
+		total += updated
+
+		// Stop when the provider returned nothing usable to avoid re-selecting the same rows forever.
+		if len(ids) < batchSize || updated == 0 {
+			break
+		}
+	}
+	return total, nil
+}
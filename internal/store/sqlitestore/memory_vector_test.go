@@ -0,0 +1,119 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// keywordEmbedder maps synonyms onto shared dimensions so that queries with no
+// literal overlap still land near the right chunks.
+type keywordEmbedder struct {
+	calls int
+}
+
+var keywordDims = map[string]int{
+	"cat": 0, "kitten": 0, "feline": 0,
+	"car": 1, "engine": 1, "sedan": 1,
+}
+
+func (e *keywordEmbedder) Name() string  { return "test" }
+func (e *keywordEmbedder) Model() string { return "keywords-v1" }
+
+func (e *keywordEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		vec := make([]float32, 2)
+		for _, word := range strings.Fields(strings.ToLower(text)) {
+			if dim, ok := keywordDims[strings.Trim(word, ".,")]; ok {
+				vec[dim]++
+			}
+		}
+		out[i] = vec
+	}
+	return out, nil
+}
+
+func TestVectorEncodingRoundTrip(t *testing.T) {
+	in := []float32{0.25, -1.5, 3, float32(math.Pi)}
+	out := decodeVector(encodeVector(in))
+	if len(out) != len(in) {
+		t.Fatalf("decoded %d dims, want %d", len(out), len(in))
+	}
+	for i := range in {
+		if out[i] != in[i] {
+			t.Fatalf("dim %d = %v, want %v", i, out[i], in[i])
+		}
+	}
+	if decodeVector([]byte{1, 2, 3}) != nil {
+		t.Fatal("malformed blob should decode to nil")
+	}
+	if got := cosineSimilarity([]float32{1, 0}, []float32{1, 0, 0}); got != 0 {
+		t.Fatalf("dimension mismatch similarity = %v, want 0", got)
+	}
+	if got := cosineSimilarity([]float32{1, 2}, []float32{2, 4}); math.Abs(got-1) > 1e-9 {
+		t.Fatalf("parallel similarity = %v, want 1", got)
+	}
+}
+
+func TestSQLiteMemoryStore_SearchFindsSemanticMatches(t *testing.T) {
+	agentStore, ctx, db := newTestSQLiteAgentStore(t)
+	agent := &store.AgentData{
+		TenantID:          store.MasterTenantID,
+		AgentKey:          "memory-vector-agent",
+		DisplayName:       "Memory Vector",
+		OwnerID:           "user-1",
+		Provider:          "openai",
+		Model:             "gpt-4.1-mini",
+		ContextWindow:     32000,
+		MaxToolIterations: 8,
+		Workspace:         ".",
+		AgentType:         store.AgentTypeOpen,
+		Status:            store.AgentStatusActive,
+	}
+	if err := agentStore.Create(ctx, agent); err != nil {
+		t.Fatalf("Create agent error: %v", err)
+	}
+	agentID := agent.ID.String()
+
+	memStore := NewSQLiteMemoryStore(db)
+	embedder := &keywordEmbedder{}
+	memStore.SetEmbeddingProvider(embedder)
+
+	docs := map[string]string{
+		"pets.md": "The feline sleeps on the sofa all afternoon.",
+		"cars.md": "The sedan needs a new engine before winter.",
+	}
+	for path, content := range docs {
+		if err := memStore.PutDocument(ctx, agentID, "", path, content); err != nil {
+			t.Fatalf("PutDocument(%s) error: %v", path, err)
+		}
+		if err := memStore.IndexDocument(ctx, agentID, "", path); err != nil {
+			t.Fatalf("IndexDocument(%s) error: %v", path, err)
+		}
+	}
+
+	// "kitten" appears in no chunk, so only the vector path can find pets.md.
+	results, err := memStore.Search(ctx, "kitten", agentID, "", store.MemorySearchOptions{MaxResults: 5})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 1 || results[0].Path != "pets.md" {
+		t.Fatalf("results = %+v, want only pets.md", results)
+	}
+
+	// Re-indexing unchanged content is served from embedding_cache.
+	before := embedder.calls
+	if err := memStore.IndexDocument(ctx, agentID, "", "cars.md"); err != nil {
+		t.Fatalf("re-IndexDocument error: %v", err)
+	}
+	if embedder.calls != before {
+		t.Fatalf("embedder called %d more times on re-index, want cache hit", embedder.calls-before)
+	}
+}
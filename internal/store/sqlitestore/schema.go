@@ -18,7 +18,7 @@ var schemaSQL string
 
 // SchemaVersion is the current SQLite schema version.
 // Bump this when adding new migration steps below.
-const SchemaVersion = 9
+const SchemaVersion = 18
 
 // migrations maps version → SQL to apply when upgrading FROM that version.
 // schema.sql always represents the LATEST full schema (for fresh DBs).
@@ -120,6 +120,175 @@ ALTER TABLE agents ADD COLUMN worker_endpoint_id TEXT;
 CREATE INDEX IF NOT EXISTS idx_agents_tenant_worker_endpoint ON agents(tenant_id, worker_endpoint_id) WHERE worker_endpoint_id IS NOT NULL;`,
 	8: `ALTER TABLE agents ADD COLUMN workspace_key TEXT;
 CREATE INDEX IF NOT EXISTS idx_agents_tenant_workspace_key ON agents(tenant_id, workspace_key) WHERE workspace_key IS NOT NULL;`,
+	// Version 9 → 10: float32 BLOB embeddings for memory vector search.
+	9: `ALTER TABLE memory_chunks ADD COLUMN embedding BLOB;
+ALTER TABLE embedding_cache ADD COLUMN embedding BLOB;`,
+	// Version 10 → 11: knowledge graph embeddings + dedup review, per-user secure CLI credentials.
+	10: `ALTER TABLE kg_entities ADD COLUMN embedding BLOB;
+CREATE TABLE IF NOT EXISTS kg_dedup_candidates (
+    id          TEXT NOT NULL PRIMARY KEY,
+    tenant_id   TEXT REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id    TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    user_id     VARCHAR(255) NOT NULL DEFAULT '',
+    entity_a_id TEXT NOT NULL REFERENCES kg_entities(id) ON DELETE CASCADE,
+    entity_b_id TEXT NOT NULL REFERENCES kg_entities(id) ON DELETE CASCADE,
+    similarity  REAL NOT NULL,
+    status      VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(entity_a_id, entity_b_id)
+);
+CREATE INDEX IF NOT EXISTS idx_kg_dedup_agent ON kg_dedup_candidates(agent_id, status);
+CREATE TABLE IF NOT EXISTS secure_cli_user_credentials (
+    id            TEXT NOT NULL PRIMARY KEY,
+    binary_id     TEXT NOT NULL REFERENCES secure_cli_binaries(id) ON DELETE CASCADE,
+    user_id       VARCHAR(255) NOT NULL,
+    encrypted_env BLOB NOT NULL,
+    metadata      TEXT NOT NULL DEFAULT '{}',
+    tenant_id     TEXT NOT NULL REFERENCES tenants(id),
+    created_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(binary_id, user_id, tenant_id)
+);
+CREATE INDEX IF NOT EXISTS idx_scuc_tenant ON secure_cli_user_credentials(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_scuc_binary ON secure_cli_user_credentials(binary_id);`,
+	// Version 11 → 12: team task due dates, SLA targets and overdue escalation.
+	11: `ALTER TABLE team_tasks ADD COLUMN due_at TEXT;
+ALTER TABLE team_tasks ADD COLUMN sla_minutes INT NOT NULL DEFAULT 0;
+ALTER TABLE team_tasks ADD COLUMN at_risk_at TEXT;
+ALTER TABLE team_tasks ADD COLUMN escalation_count INT NOT NULL DEFAULT 0;
+ALTER TABLE team_tasks ADD COLUMN next_escalation_at TEXT;
+CREATE INDEX IF NOT EXISTS idx_tt_due ON team_tasks(due_at) WHERE due_at IS NOT NULL AND status NOT IN ('completed','cancelled');`,
+	// Version 12 → 13: team task templates with cron recurrence.
+	12: `CREATE TABLE IF NOT EXISTS team_task_templates (
+    id             TEXT NOT NULL PRIMARY KEY,
+    team_id        TEXT NOT NULL REFERENCES agent_teams(id) ON DELETE CASCADE,
+    name           VARCHAR(255) NOT NULL,
+    subject        VARCHAR(500) NOT NULL,
+    description    TEXT NOT NULL DEFAULT '',
+    owner_agent_id TEXT REFERENCES agents(id) ON DELETE SET NULL,
+    priority       INT NOT NULL DEFAULT 0,
+    subtasks       TEXT NOT NULL DEFAULT '[]',
+    schedule       VARCHAR(100) NOT NULL DEFAULT '',
+    timezone       VARCHAR(64) NOT NULL DEFAULT '',
+    enabled        BOOLEAN NOT NULL DEFAULT 1,
+    next_run_at    TEXT,
+    last_run_at    TEXT,
+    run_count      INT NOT NULL DEFAULT 0,
+    created_by     VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_id      TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(team_id, name)
+);
+CREATE INDEX IF NOT EXISTS idx_ttt_team ON team_task_templates(team_id);
+CREATE INDEX IF NOT EXISTS idx_ttt_tenant ON team_task_templates(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_ttt_next_run ON team_task_templates(next_run_at) WHERE enabled = 1 AND next_run_at IS NOT NULL;`,
+	// Version 13 → 14: declarative team workflows and their runs.
+	13: `CREATE TABLE IF NOT EXISTS team_workflows (
+    id          TEXT NOT NULL PRIMARY KEY,
+    team_id     TEXT NOT NULL REFERENCES agent_teams(id) ON DELETE CASCADE,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    definition  TEXT NOT NULL DEFAULT '{}',
+    enabled     BOOLEAN NOT NULL DEFAULT 1,
+    created_by  VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_id   TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(team_id, name)
+);
+CREATE INDEX IF NOT EXISTS idx_twf_team ON team_workflows(team_id);
+CREATE INDEX IF NOT EXISTS idx_twf_tenant ON team_workflows(tenant_id);
+CREATE TABLE IF NOT EXISTS team_workflow_runs (
+    id           TEXT NOT NULL PRIMARY KEY,
+    workflow_id  TEXT NOT NULL REFERENCES team_workflows(id) ON DELETE CASCADE,
+    team_id      TEXT NOT NULL REFERENCES agent_teams(id) ON DELETE CASCADE,
+    status       VARCHAR(20) NOT NULL DEFAULT 'running',
+    trigger_kind VARCHAR(20) NOT NULL DEFAULT 'manual',
+    triggered_by VARCHAR(255) NOT NULL DEFAULT '',
+    input        TEXT NOT NULL DEFAULT '{}',
+    definition   TEXT NOT NULL DEFAULT '{}',
+    steps        TEXT NOT NULL DEFAULT '{}',
+    output       TEXT NOT NULL DEFAULT '',
+    error        TEXT NOT NULL DEFAULT '',
+    trace_id     TEXT,
+    started_at   TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    finished_at  TEXT,
+    tenant_id    TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at   TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at   TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+CREATE INDEX IF NOT EXISTS idx_twfr_workflow ON team_workflow_runs(workflow_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_twfr_team ON team_workflow_runs(team_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_twfr_tenant ON team_workflow_runs(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_twfr_running ON team_workflow_runs(status) WHERE status = 'running';`,
+	// Version 14 → 15: knowledge base collections, documents and chunks.
+	14: `CREATE TABLE IF NOT EXISTS kb_collections (
+    id          TEXT NOT NULL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by  VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_id   TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(tenant_id, name)
+);
+CREATE TABLE IF NOT EXISTS kb_collection_agents (
+    collection_id TEXT NOT NULL REFERENCES kb_collections(id) ON DELETE CASCADE,
+    agent_id      TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    tenant_id     TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    PRIMARY KEY (collection_id, agent_id)
+);
+CREATE INDEX IF NOT EXISTS idx_kbca_agent ON kb_collection_agents(agent_id);
+CREATE TABLE IF NOT EXISTS kb_documents (
+    id            TEXT NOT NULL PRIMARY KEY,
+    collection_id TEXT NOT NULL REFERENCES kb_collections(id) ON DELETE CASCADE,
+    file_name     VARCHAR(500) NOT NULL,
+    mime_type     VARCHAR(255) NOT NULL DEFAULT '',
+    hash          VARCHAR(64) NOT NULL DEFAULT '',
+    size_bytes    INTEGER NOT NULL DEFAULT 0,
+    pages         INT NOT NULL DEFAULT 0,
+    content       TEXT NOT NULL DEFAULT '',
+    status        VARCHAR(20) NOT NULL DEFAULT 'pending',
+    error         TEXT NOT NULL DEFAULT '',
+    chunk_count   INT NOT NULL DEFAULT 0,
+    created_by    VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_id     TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(collection_id, file_name)
+);
+CREATE INDEX IF NOT EXISTS idx_kbdoc_tenant ON kb_documents(tenant_id);
+CREATE TABLE IF NOT EXISTS kb_chunks (
+    id            TEXT NOT NULL PRIMARY KEY,
+    collection_id TEXT NOT NULL REFERENCES kb_collections(id) ON DELETE CASCADE,
+    document_id   TEXT NOT NULL REFERENCES kb_documents(id) ON DELETE CASCADE,
+    page          INT NOT NULL DEFAULT 0,
+    start_line    INT NOT NULL DEFAULT 0,
+    end_line      INT NOT NULL DEFAULT 0,
+    hash          VARCHAR(64) NOT NULL,
+    text          TEXT NOT NULL,
+    embedding     BLOB,
+    tenant_id     TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+CREATE INDEX IF NOT EXISTS idx_kbchunk_collection ON kb_chunks(collection_id);
+CREATE INDEX IF NOT EXISTS idx_kbchunk_document ON kb_chunks(document_id);`,
+	// Version 15 → 16: cron missed-run catch-up policy.
+	15: `ALTER TABLE cron_jobs ADD COLUMN catch_up VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE cron_jobs ADD COLUMN catch_up_pending INT NOT NULL DEFAULT 0;
+ALTER TABLE cron_run_logs ADD COLUMN catch_up VARCHAR(16);
+ALTER TABLE cron_run_logs ADD COLUMN missed INT;`,
+	// Version 16 → 17: cron chaining and delivery conditions.
+	16: `ALTER TABLE cron_jobs ADD COLUMN after_job_id TEXT REFERENCES cron_jobs(id) ON DELETE CASCADE;
+ALTER TABLE cron_jobs ADD COLUMN chain_input TEXT;
+ALTER TABLE cron_jobs ADD COLUMN deliver_if TEXT NOT NULL DEFAULT '';
+ALTER TABLE cron_jobs ADD COLUMN last_output TEXT;
+CREATE INDEX IF NOT EXISTS idx_cron_jobs_after ON cron_jobs(after_job_id) WHERE after_job_id IS NOT NULL;`,
+	// Version 17 → 18: session token estimate persisted on save.
+	17: `ALTER TABLE sessions ADD COLUMN estimated_tokens INT NOT NULL DEFAULT 0;
+UPDATE sessions SET estimated_tokens = length(messages) / 4 + 12000 WHERE json_array_length(messages) > 0;`,
 }
 
 // EnsureSchema creates tables if they don't exist and applies incremental migrations.
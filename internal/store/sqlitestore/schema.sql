@@ -269,6 +269,7 @@ CREATE TABLE IF NOT EXISTS sessions (
     output_tokens                 BIGINT NOT NULL DEFAULT 0,
     compaction_count              INT NOT NULL DEFAULT 0,
     memory_flush_compaction_count INT NOT NULL DEFAULT 0,
+    estimated_tokens              INT NOT NULL DEFAULT 0,
     memory_flush_at               BIGINT DEFAULT 0,
     label                         VARCHAR(500),
     spawned_by                    VARCHAR(200),
@@ -313,7 +314,7 @@ CREATE INDEX IF NOT EXISTS idx_memory_documents_tenant ON memory_documents(tenan
 
 -- ============================================================
 -- Table: memory_chunks
--- Note: embedding (vector) and tsv (tsvector) columns omitted
+-- Note: tsv (tsvector) column omitted; embedding stored as little-endian float32 BLOB
 -- ============================================================
 
 CREATE TABLE IF NOT EXISTS memory_chunks (
@@ -326,6 +327,7 @@ CREATE TABLE IF NOT EXISTS memory_chunks (
     end_line    INT NOT NULL DEFAULT 0,
     hash        VARCHAR(64) NOT NULL,
     text        TEXT NOT NULL,
+    embedding   BLOB,
     team_id     TEXT REFERENCES agent_teams(id) ON DELETE SET NULL,
     tenant_id   TEXT NOT NULL REFERENCES tenants(id),
     created_at  TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
@@ -340,13 +342,14 @@ CREATE INDEX IF NOT EXISTS idx_memory_chunks_tenant ON memory_chunks(tenant_id);
 
 -- ============================================================
 -- Table: embedding_cache
--- Note: embedding (vector) column omitted
+-- Note: embedding stored as little-endian float32 BLOB
 -- ============================================================
 
 CREATE TABLE IF NOT EXISTS embedding_cache (
     hash       VARCHAR(64) NOT NULL,
     provider   VARCHAR(50) NOT NULL,
     model      VARCHAR(200) NOT NULL,
+    embedding  BLOB,
     dims       INT NOT NULL DEFAULT 0,
     tenant_id  TEXT NOT NULL REFERENCES tenants(id),
     created_at TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
@@ -469,13 +472,20 @@ CREATE TABLE IF NOT EXISTS cron_jobs (
     team_id          TEXT REFERENCES agent_teams(id) ON DELETE SET NULL,
     tenant_id        TEXT NOT NULL REFERENCES tenants(id),
     created_at       TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
-    updated_at       TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+    updated_at       TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    catch_up         VARCHAR(16) NOT NULL DEFAULT '',
+    catch_up_pending INT NOT NULL DEFAULT 0,
+    after_job_id     TEXT REFERENCES cron_jobs(id) ON DELETE CASCADE,
+    chain_input      TEXT,
+    deliver_if       TEXT NOT NULL DEFAULT '',
+    last_output      TEXT
 );
 
 CREATE INDEX IF NOT EXISTS idx_cron_jobs_user_id ON cron_jobs(user_id);
 CREATE INDEX IF NOT EXISTS idx_cron_jobs_agent_user ON cron_jobs(agent_id, user_id);
 CREATE INDEX IF NOT EXISTS idx_cron_jobs_team ON cron_jobs(team_id) WHERE team_id IS NOT NULL;
 CREATE INDEX IF NOT EXISTS idx_cron_jobs_tenant ON cron_jobs(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_cron_jobs_after ON cron_jobs(after_job_id) WHERE after_job_id IS NOT NULL;
 
 -- ============================================================
 -- Table: cron_run_logs
@@ -493,7 +503,9 @@ CREATE TABLE IF NOT EXISTS cron_run_logs (
     output_tokens INT DEFAULT 0,
     team_id       TEXT REFERENCES agent_teams(id) ON DELETE SET NULL,
     ran_at        TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
-    created_at    TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+    created_at    TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    catch_up      VARCHAR(16),
+    missed        INT
 );
 
 CREATE INDEX IF NOT EXISTS idx_cron_run_logs_job ON cron_run_logs(job_id, ran_at DESC);
@@ -859,6 +871,11 @@ CREATE TABLE IF NOT EXISTS team_tasks (
     followup_message     TEXT,
     followup_channel     VARCHAR(60),
     followup_chat_id     VARCHAR(255),
+    due_at               TEXT,
+    sla_minutes          INT NOT NULL DEFAULT 0,
+    at_risk_at           TEXT,
+    escalation_count     INT NOT NULL DEFAULT 0,
+    next_escalation_at   TEXT,
     confidence_score     REAL,
     comment_count        INT NOT NULL DEFAULT 0,
     attachment_count     INT NOT NULL DEFAULT 0,
@@ -876,6 +893,7 @@ CREATE INDEX IF NOT EXISTS idx_tt_type ON team_tasks(team_id, task_type);
 CREATE INDEX IF NOT EXISTS idx_tt_lock ON team_tasks(lock_expires_at) WHERE lock_expires_at IS NOT NULL AND status = 'in_progress';
 CREATE UNIQUE INDEX IF NOT EXISTS idx_tt_identifier ON team_tasks(team_id, identifier) WHERE identifier IS NOT NULL;
 CREATE INDEX IF NOT EXISTS idx_tt_followup ON team_tasks(followup_at) WHERE followup_at IS NOT NULL AND status = 'in_progress';
+CREATE INDEX IF NOT EXISTS idx_tt_due ON team_tasks(due_at) WHERE due_at IS NOT NULL AND status NOT IN ('completed','cancelled');
 -- idx_tt_blocked_by (GIN on array) omitted: Go code handles JSON array filtering
 CREATE INDEX IF NOT EXISTS idx_tt_owner_status ON team_tasks(team_id, owner_agent_id, status);
 CREATE INDEX IF NOT EXISTS idx_team_tasks_tenant ON team_tasks(tenant_id);
@@ -943,6 +961,89 @@ CREATE INDEX IF NOT EXISTS idx_tta_task ON team_task_attachments(task_id);
 CREATE INDEX IF NOT EXISTS idx_tta_team ON team_task_attachments(team_id);
 CREATE INDEX IF NOT EXISTS idx_team_task_attachments_tenant ON team_task_attachments(tenant_id);
 
+-- ============================================================
+-- Table: team_task_templates
+-- Note: subtasks stored as TEXT (JSON array)
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS team_task_templates (
+    id             TEXT NOT NULL PRIMARY KEY,
+    team_id        TEXT NOT NULL REFERENCES agent_teams(id) ON DELETE CASCADE,
+    name           VARCHAR(255) NOT NULL,
+    subject        VARCHAR(500) NOT NULL,
+    description    TEXT NOT NULL DEFAULT '',
+    owner_agent_id TEXT REFERENCES agents(id) ON DELETE SET NULL,
+    priority       INT NOT NULL DEFAULT 0,
+    subtasks       TEXT NOT NULL DEFAULT '[]',
+    schedule       VARCHAR(100) NOT NULL DEFAULT '',
+    timezone       VARCHAR(64) NOT NULL DEFAULT '',
+    enabled        BOOLEAN NOT NULL DEFAULT 1,
+    next_run_at    TEXT,
+    last_run_at    TEXT,
+    run_count      INT NOT NULL DEFAULT 0,
+    created_by     VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_id      TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at     TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(team_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ttt_team ON team_task_templates(team_id);
+CREATE INDEX IF NOT EXISTS idx_ttt_tenant ON team_task_templates(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_ttt_next_run ON team_task_templates(next_run_at) WHERE enabled = 1 AND next_run_at IS NOT NULL;
+
+-- ============================================================
+-- Table: team_workflows
+-- Note: definition stored as TEXT (JSON)
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS team_workflows (
+    id          TEXT NOT NULL PRIMARY KEY,
+    team_id     TEXT NOT NULL REFERENCES agent_teams(id) ON DELETE CASCADE,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    definition  TEXT NOT NULL DEFAULT '{}',
+    enabled     BOOLEAN NOT NULL DEFAULT 1,
+    created_by  VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_id   TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(team_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_twf_team ON team_workflows(team_id);
+CREATE INDEX IF NOT EXISTS idx_twf_tenant ON team_workflows(tenant_id);
+
+-- ============================================================
+-- Table: team_workflow_runs
+-- Note: input, definition and steps stored as TEXT (JSON)
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS team_workflow_runs (
+    id           TEXT NOT NULL PRIMARY KEY,
+    workflow_id  TEXT NOT NULL REFERENCES team_workflows(id) ON DELETE CASCADE,
+    team_id      TEXT NOT NULL REFERENCES agent_teams(id) ON DELETE CASCADE,
+    status       VARCHAR(20) NOT NULL DEFAULT 'running',
+    trigger_kind VARCHAR(20) NOT NULL DEFAULT 'manual',
+    triggered_by VARCHAR(255) NOT NULL DEFAULT '',
+    input        TEXT NOT NULL DEFAULT '{}',
+    definition   TEXT NOT NULL DEFAULT '{}',
+    steps        TEXT NOT NULL DEFAULT '{}',
+    output       TEXT NOT NULL DEFAULT '',
+    error        TEXT NOT NULL DEFAULT '',
+    trace_id     TEXT,
+    started_at   TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    finished_at  TEXT,
+    tenant_id    TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at   TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at   TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_twfr_workflow ON team_workflow_runs(workflow_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_twfr_team ON team_workflow_runs(team_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_twfr_tenant ON team_workflow_runs(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_twfr_running ON team_workflow_runs(status) WHERE status = 'running';
+
 -- ============================================================
 -- Table: team_user_grants
 -- ============================================================
@@ -964,7 +1065,7 @@ CREATE INDEX IF NOT EXISTS idx_team_user_grants_tenant ON team_user_grants(tenan
 
 -- ============================================================
 -- Table: kg_entities
--- Note: embedding (vector) column omitted
+-- Note: embedding stored as little-endian float32 BLOB; tsv omitted (LIKE search)
 -- ============================================================
 
 CREATE TABLE IF NOT EXISTS kg_entities (
@@ -978,6 +1079,7 @@ CREATE TABLE IF NOT EXISTS kg_entities (
     properties  TEXT DEFAULT '{}',
     source_id   VARCHAR(255) DEFAULT '',
     confidence  REAL NOT NULL DEFAULT 1.0,
+    embedding   BLOB,
     team_id     TEXT REFERENCES agent_teams(id) ON DELETE SET NULL,
     tenant_id   TEXT NOT NULL REFERENCES tenants(id),
     created_at  TEXT DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
@@ -1014,6 +1116,25 @@ CREATE INDEX IF NOT EXISTS idx_kg_relations_target ON kg_relations(target_entity
 CREATE INDEX IF NOT EXISTS idx_kg_relations_team ON kg_relations(team_id) WHERE team_id IS NOT NULL;
 CREATE INDEX IF NOT EXISTS idx_kg_relations_tenant ON kg_relations(tenant_id);
 
+-- ============================================================
+-- Table: kg_dedup_candidates
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS kg_dedup_candidates (
+    id          TEXT NOT NULL PRIMARY KEY,
+    tenant_id   TEXT REFERENCES tenants(id) ON DELETE CASCADE,
+    agent_id    TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    user_id     VARCHAR(255) NOT NULL DEFAULT '',
+    entity_a_id TEXT NOT NULL REFERENCES kg_entities(id) ON DELETE CASCADE,
+    entity_b_id TEXT NOT NULL REFERENCES kg_entities(id) ON DELETE CASCADE,
+    similarity  REAL NOT NULL,
+    status      VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(entity_a_id, entity_b_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kg_dedup_agent ON kg_dedup_candidates(agent_id, status);
+
 -- ============================================================
 -- Table: channel_pending_messages
 -- ============================================================
@@ -1218,6 +1339,25 @@ CREATE INDEX IF NOT EXISTS idx_scag_binary ON secure_cli_agent_grants(binary_id)
 CREATE INDEX IF NOT EXISTS idx_scag_agent ON secure_cli_agent_grants(agent_id);
 CREATE INDEX IF NOT EXISTS idx_scag_tenant ON secure_cli_agent_grants(tenant_id);
 
+-- ============================================================
+-- Table: secure_cli_user_credentials
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS secure_cli_user_credentials (
+    id            TEXT NOT NULL PRIMARY KEY,
+    binary_id     TEXT NOT NULL REFERENCES secure_cli_binaries(id) ON DELETE CASCADE,
+    user_id       VARCHAR(255) NOT NULL,
+    encrypted_env BLOB NOT NULL,
+    metadata      TEXT NOT NULL DEFAULT '{}',
+    tenant_id     TEXT NOT NULL REFERENCES tenants(id),
+    created_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(binary_id, user_id, tenant_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_scuc_tenant ON secure_cli_user_credentials(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_scuc_binary ON secure_cli_user_credentials(binary_id);
+
 -- ============================================================
 -- Table: api_keys
 -- ============================================================
@@ -1437,3 +1577,80 @@ CREATE TABLE IF NOT EXISTS subagent_tasks (
 CREATE INDEX IF NOT EXISTS idx_subagent_tasks_parent_status ON subagent_tasks(tenant_id, parent_agent_key, status);
 CREATE INDEX IF NOT EXISTS idx_subagent_tasks_session ON subagent_tasks(session_key);
 CREATE INDEX IF NOT EXISTS idx_subagent_tasks_created ON subagent_tasks(tenant_id, created_at);
+
+-- ============================================================
+-- Table: kb_collections
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS kb_collections (
+    id          TEXT NOT NULL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by  VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_id   TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at  TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(tenant_id, name)
+);
+
+-- ============================================================
+-- Table: kb_collection_agents
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS kb_collection_agents (
+    collection_id TEXT NOT NULL REFERENCES kb_collections(id) ON DELETE CASCADE,
+    agent_id      TEXT NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    tenant_id     TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    PRIMARY KEY (collection_id, agent_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kbca_agent ON kb_collection_agents(agent_id);
+
+-- ============================================================
+-- Table: kb_documents
+-- Note: content holds the extracted text for re-indexing
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS kb_documents (
+    id            TEXT NOT NULL PRIMARY KEY,
+    collection_id TEXT NOT NULL REFERENCES kb_collections(id) ON DELETE CASCADE,
+    file_name     VARCHAR(500) NOT NULL,
+    mime_type     VARCHAR(255) NOT NULL DEFAULT '',
+    hash          VARCHAR(64) NOT NULL DEFAULT '',
+    size_bytes    INTEGER NOT NULL DEFAULT 0,
+    pages         INT NOT NULL DEFAULT 0,
+    content       TEXT NOT NULL DEFAULT '',
+    status        VARCHAR(20) NOT NULL DEFAULT 'pending',
+    error         TEXT NOT NULL DEFAULT '',
+    chunk_count   INT NOT NULL DEFAULT 0,
+    created_by    VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_id     TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    updated_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+    UNIQUE(collection_id, file_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kbdoc_tenant ON kb_documents(tenant_id);
+
+-- ============================================================
+-- Table: kb_chunks
+-- Note: tsv (tsvector) column omitted; embedding stored as little-endian float32 BLOB
+-- ============================================================
+
+CREATE TABLE IF NOT EXISTS kb_chunks (
+    id            TEXT NOT NULL PRIMARY KEY,
+    collection_id TEXT NOT NULL REFERENCES kb_collections(id) ON DELETE CASCADE,
+    document_id   TEXT NOT NULL REFERENCES kb_documents(id) ON DELETE CASCADE,
+    page          INT NOT NULL DEFAULT 0,
+    start_line    INT NOT NULL DEFAULT 0,
+    end_line      INT NOT NULL DEFAULT 0,
+    hash          VARCHAR(64) NOT NULL,
+    text          TEXT NOT NULL,
+    embedding     BLOB,
+    tenant_id     TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at    TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_kbchunk_collection ON kb_chunks(collection_id);
+CREATE INDEX IF NOT EXISTS idx_kbchunk_document ON kb_chunks(document_id);
@@ -0,0 +1,376 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/crypto"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SQLiteSecureCLIStore implements store.SecureCLIStore backed by SQLite.
+type SQLiteSecureCLIStore struct {
+	db     *sql.DB
+	encKey string
+}
+
+func NewSQLiteSecureCLIStore(db *sql.DB, encryptionKey string) *SQLiteSecureCLIStore {
+	return &SQLiteSecureCLIStore{db: db, encKey: encryptionKey}
+}
+
+const secureCLISelectCols = `id, binary_name, binary_path, description, encrypted_env,
+ deny_args, deny_verbose, timeout_seconds, tips, is_global, enabled, created_by, created_at, updated_at`
+
+// secureCLISelectColsAliased is prefixed with table alias "b." for queries that JOIN grants/credentials.
+const secureCLISelectColsAliased = `b.id, b.binary_name, b.binary_path, b.description, b.encrypted_env,
+ b.deny_args, b.deny_verbose, b.timeout_seconds, b.tips, b.is_global, b.enabled, b.created_by, b.created_at, b.updated_at`
+
+func (s *SQLiteSecureCLIStore) Create(ctx context.Context, b *store.SecureCLIBinary) error {
+	if err := store.ValidateUserID(b.CreatedBy); err != nil {
+		return err
+	}
+	if b.ID == uuid.Nil {
+		b.ID = store.GenNewID()
+	}
+
+	envBytes, err := s.encryptEnv(b.EncryptedEnv)
+	if err != nil {
+		return err
+	}
+	if envBytes == nil {
+		envBytes = []byte{} // encrypted_env is NOT NULL
+	}
+
+	now := time.Now().UTC()
+	b.CreatedAt = now
+	b.UpdatedAt = now
+
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO secure_cli_binaries (id, binary_name, binary_path, description, encrypted_env,
+		 deny_args, deny_verbose, timeout_seconds, tips, is_global, enabled, created_by, created_at, updated_at, tenant_id)
+		 VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+		b.ID, b.BinaryName, nilStr(derefStr(b.BinaryPath)), b.Description,
+		envBytes,
+		string(jsonOrEmptyArray(b.DenyArgs)), string(jsonOrEmptyArray(b.DenyVerbose)),
+		b.TimeoutSeconds, b.Tips,
+		b.IsGlobal, b.Enabled,
+		b.CreatedBy, now, now, tenantIDForInsert(ctx),
+	)
+	return err
+}
+
+func (s *SQLiteSecureCLIStore) Get(ctx context.Context, id uuid.UUID) (*store.SecureCLIBinary, error) {
+	if store.IsCrossTenant(ctx) {
+		return s.scanBinary(s.db.QueryRowContext(ctx,
+			`SELECT `+secureCLISelectCols+` FROM secure_cli_binaries WHERE id = ?`, id))
+	}
+	tenantID := store.TenantIDFromContext(ctx)
+	if tenantID == uuid.Nil {
+		return nil, sql.ErrNoRows
+	}
+	return s.scanBinary(s.db.QueryRowContext(ctx,
+		`SELECT `+secureCLISelectCols+` FROM secure_cli_binaries WHERE id = ? AND tenant_id = ?`, id, tenantID))
+}
+
+// secureCLIAllowedFields is the allowlist of columns that can be updated via execMapUpdate.
+var secureCLIAllowedFields = map[string]bool{
+	"binary_name": true, "binary_path": true, "description": true,
+	"encrypted_env": true, "deny_args": true, "deny_verbose": true,
+	"timeout_seconds": true, "tips": true, "is_global": true, "enabled": true,
+	"updated_at": true,
+}
+
+func (s *SQLiteSecureCLIStore) Update(ctx context.Context, id uuid.UUID, updates map[string]any) error {
+	for k := range updates {
+		if !secureCLIAllowedFields[k] {
+			delete(updates, k)
+		}
+	}
+
+	// Encrypt env if present in updates
+	if envVal, ok := updates["encrypted_env"]; ok {
+		if envStr, isStr := envVal.(string); isStr && envStr != "" && s.encKey != "" {
+			encrypted, err := crypto.Encrypt(envStr, s.encKey)
+			if err != nil {
+				return fmt.Errorf("encrypt env: %w", err)
+			}
+			updates["encrypted_env"] = []byte(encrypted)
+		}
+	}
+	updates["updated_at"] = time.Now().UTC()
+	if store.IsCrossTenant(ctx) {
+		return execMapUpdate(ctx, s.db, "secure_cli_binaries", id, updates)
+	}
+	tid := store.TenantIDFromContext(ctx)
+	if tid == uuid.Nil {
+		return fmt.Errorf("tenant_id required for update")
+	}
+	return execMapUpdateWhere(ctx, s.db, "secure_cli_binaries", updates, "id = ? AND tenant_id = ?", id, tid)
+}
+
+func (s *SQLiteSecureCLIStore) Delete(ctx context.Context, id uuid.UUID) error {
+	if store.IsCrossTenant(ctx) {
+		_, err := s.db.ExecContext(ctx, "DELETE FROM secure_cli_binaries WHERE id = ?", id)
+		return err
+	}
+	tid := store.TenantIDFromContext(ctx)
+	if tid == uuid.Nil {
+		return fmt.Errorf("tenant_id required")
+	}
+	_, err := s.db.ExecContext(ctx, "DELETE FROM secure_cli_binaries WHERE id = ? AND tenant_id = ?", id, tid)
+	return err
+}
+
+func (s *SQLiteSecureCLIStore) List(ctx context.Context) ([]store.SecureCLIBinary, error) {
+	return s.listWhere(ctx, "")
+}
+
+func (s *SQLiteSecureCLIStore) ListEnabled(ctx context.Context) ([]store.SecureCLIBinary, error) {
+	return s.listWhere(ctx, "enabled = 1")
+}
+
+// listWhere lists tenant-scoped binaries matching an optional static condition.
+func (s *SQLiteSecureCLIStore) listWhere(ctx context.Context, cond string) ([]store.SecureCLIBinary, error) {
+	query := `SELECT ` + secureCLISelectCols + ` FROM secure_cli_binaries WHERE 1=1`
+	if cond != "" {
+		query += ` AND ` + cond
+	}
+	var qArgs []any
+	if !store.IsCrossTenant(ctx) {
+		tenantID := store.TenantIDFromContext(ctx)
+		if tenantID == uuid.Nil {
+			return nil, nil
+		}
+		query += ` AND tenant_id = ?`
+		qArgs = append(qArgs, tenantID)
+	}
+	query += ` ORDER BY binary_name`
+	rows, err := s.db.QueryContext(ctx, query, qArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.SecureCLIBinary
+	for rows.Next() {
+		b, err := s.scanBinary(rows)
+		if err != nil {
+			continue
+		}
+		result = append(result, *b)
+	}
+	return result, rows.Err()
+}
+
+// LookupByBinary finds the credential config for a binary name.
+// Checks agent grant authorization and merges overrides if agentID is provided.
+// Also fetches per-user env overrides via LEFT JOIN when userID is non-empty.
+func (s *SQLiteSecureCLIStore) LookupByBinary(ctx context.Context, binaryName string, agentID *uuid.UUID, userID string) (*store.SecureCLIBinary, error) {
+	tid := store.TenantIDFromContext(ctx)
+	isCross := store.IsCrossTenant(ctx)
+	if !isCross && tid == uuid.Nil {
+		return nil, nil
+	}
+
+	query := `SELECT ` + secureCLISelectColsAliased + `,
+		 g.deny_args, g.deny_verbose, g.timeout_seconds, g.tips, g.id`
+	if userID != "" {
+		query += `, uc.encrypted_env`
+	} else {
+		query += `, NULL`
+	}
+	query += ` FROM secure_cli_binaries b`
+
+	var args []any
+	if agentID != nil {
+		query += ` LEFT JOIN secure_cli_agent_grants g ON g.binary_id = b.id AND g.agent_id = ?`
+		args = append(args, *agentID)
+	} else {
+		query += ` LEFT JOIN secure_cli_agent_grants g ON 0` // never match
+	}
+	if userID != "" {
+		query += ` LEFT JOIN secure_cli_user_credentials uc ON uc.binary_id = b.id AND uc.user_id = ?`
+		args = append(args, userID)
+		if !isCross {
+			query += ` AND uc.tenant_id = ?`
+			args = append(args, tid)
+		}
+	}
+
+	query += ` WHERE b.binary_name = ? AND b.enabled = 1`
+	args = append(args, binaryName)
+	if !isCross {
+		query += ` AND b.tenant_id = ?`
+		args = append(args, tid)
+	}
+
+	// Authorization: global (no grant needed OR has enabled grant) OR non-global (must have enabled grant)
+	if agentID != nil {
+		query += ` AND (
+			(b.is_global = 1 AND (g.id IS NULL OR g.enabled = 1))
+			OR
+			(b.is_global = 0 AND g.id IS NOT NULL AND g.enabled = 1)
+		)`
+	} else {
+		// No agent context — only return global binaries
+		query += ` AND b.is_global = 1`
+	}
+	query += ` LIMIT 1`
+
+	b, err := s.scanBinaryWithGrant(s.db.QueryRowContext(ctx, query, args...), true)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return b, err
+}
+
+// ListForAgent returns all CLIs accessible by an agent (global + granted),
+// with grant overrides merged into the returned configs.
+func (s *SQLiteSecureCLIStore) ListForAgent(ctx context.Context, agentID uuid.UUID) ([]store.SecureCLIBinary, error) {
+	tid := store.TenantIDFromContext(ctx)
+	isCross := store.IsCrossTenant(ctx)
+	if !isCross && tid == uuid.Nil {
+		return nil, nil
+	}
+
+	query := `SELECT ` + secureCLISelectColsAliased + `,
+		 g.deny_args, g.deny_verbose, g.timeout_seconds, g.tips, g.id
+		 FROM secure_cli_binaries b
+		 LEFT JOIN secure_cli_agent_grants g ON g.binary_id = b.id AND g.agent_id = ?
+		 WHERE b.enabled = 1
+		   AND (
+		     (b.is_global = 1 AND (g.id IS NULL OR g.enabled = 1))
+		     OR
+		     (b.is_global = 0 AND g.id IS NOT NULL AND g.enabled = 1)
+		   )`
+	args := []any{agentID}
+	if !isCross {
+		query += ` AND b.tenant_id = ?`
+		args = append(args, tid)
+	}
+	query += ` ORDER BY b.binary_name`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.SecureCLIBinary
+	for rows.Next() {
+		b, err := s.scanBinaryWithGrant(rows, false)
+		if err != nil {
+			continue
+		}
+		result = append(result, *b)
+	}
+	return result, rows.Err()
+}
+
+// --- scan helpers ---
+
+func (s *SQLiteSecureCLIStore) scanBinary(row rowScanner) (*store.SecureCLIBinary, error) {
+	var b store.SecureCLIBinary
+	var binaryPath *string
+	var denyArgs, denyVerbose, env []byte
+	createdAt, updatedAt := scanTimePair()
+	if err := row.Scan(
+		&b.ID, &b.BinaryName, &binaryPath, &b.Description, &env,
+		&denyArgs, &denyVerbose,
+		&b.TimeoutSeconds, &b.Tips, &b.IsGlobal,
+		&b.Enabled, &b.CreatedBy, createdAt, updatedAt,
+	); err != nil {
+		return nil, err
+	}
+	s.fillBinary(&b, binaryPath, denyArgs, denyVerbose, env)
+	b.CreatedAt = createdAt.Time
+	b.UpdatedAt = updatedAt.Time
+	return &b, nil
+}
+
+// scanBinaryWithGrant scans binary columns followed by grant override columns
+// and, when withUserEnv is set, the per-user encrypted env column.
+func (s *SQLiteSecureCLIStore) scanBinaryWithGrant(row rowScanner, withUserEnv bool) (*store.SecureCLIBinary, error) {
+	var b store.SecureCLIBinary
+	var binaryPath, grantTips *string
+	var denyArgs, denyVerbose, env, grantDenyArgs, grantDenyVerbose, userEnv []byte
+	var grantTimeout *int
+	var grantID *uuid.UUID
+	createdAt, updatedAt := scanTimePair()
+
+	dest := []any{
+		&b.ID, &b.BinaryName, &binaryPath, &b.Description, &env,
+		&denyArgs, &denyVerbose,
+		&b.TimeoutSeconds, &b.Tips, &b.IsGlobal,
+		&b.Enabled, &b.CreatedBy, createdAt, updatedAt,
+		&grantDenyArgs, &grantDenyVerbose, &grantTimeout, &grantTips, &grantID,
+	}
+	if withUserEnv {
+		dest = append(dest, &userEnv)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	s.fillBinary(&b, binaryPath, denyArgs, denyVerbose, env)
+	b.CreatedAt = createdAt.Time
+	b.UpdatedAt = updatedAt.Time
+
+	if grantID != nil {
+		grant := &store.SecureCLIAgentGrant{TimeoutSeconds: grantTimeout, Tips: grantTips}
+		if grantDenyArgs != nil {
+			raw := json.RawMessage(grantDenyArgs)
+			grant.DenyArgs = &raw
+		}
+		if grantDenyVerbose != nil {
+			raw := json.RawMessage(grantDenyVerbose)
+			grant.DenyVerbose = &raw
+		}
+		b.MergeGrantOverrides(grant)
+	}
+
+	if len(userEnv) > 0 {
+		b.UserEnv = s.decryptEnv(userEnv, b.BinaryName)
+	}
+	return &b, nil
+}
+
+func (s *SQLiteSecureCLIStore) fillBinary(b *store.SecureCLIBinary, binaryPath *string, denyArgs, denyVerbose, env []byte) {
+	b.BinaryPath = binaryPath
+	b.DenyArgs = denyArgs
+	b.DenyVerbose = denyVerbose
+	b.EncryptedEnv = s.decryptEnv(env, b.BinaryName)
+}
+
+// encryptEnv encrypts a plaintext env JSON blob when an encryption key is configured.
+func (s *SQLiteSecureCLIStore) encryptEnv(env []byte) ([]byte, error) {
+	if len(env) == 0 || s.encKey == "" {
+		return env, nil
+	}
+	encrypted, err := crypto.Encrypt(string(env), s.encKey)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt env: %w", err)
+	}
+	return []byte(encrypted), nil
+}
+
+// decryptEnv reverses encryptEnv. Returns nil when decryption fails.
+func (s *SQLiteSecureCLIStore) decryptEnv(env []byte, binaryName string) []byte {
+	if len(env) == 0 || s.encKey == "" {
+		return env
+	}
+	decrypted, err := crypto.Decrypt(string(env), s.encKey)
+	if err != nil {
+		slog.Warn("secure_cli: failed to decrypt env", "binary", binaryName, "error", err)
+		return nil
+	}
+	return []byte(decrypted)
+}
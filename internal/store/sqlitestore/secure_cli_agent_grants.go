@@ -0,0 +1,166 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SQLiteSecureCLIAgentGrantStore implements store.SecureCLIAgentGrantStore backed by SQLite.
+type SQLiteSecureCLIAgentGrantStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteSecureCLIAgentGrantStore(db *sql.DB) *SQLiteSecureCLIAgentGrantStore {
+	return &SQLiteSecureCLIAgentGrantStore{db: db}
+}
+
+const grantSelectCols = `id, binary_id, agent_id, deny_args, deny_verbose, timeout_seconds, tips, enabled, created_at, updated_at`
+
+func (s *SQLiteSecureCLIAgentGrantStore) Create(ctx context.Context, g *store.SecureCLIAgentGrant) error {
+	if g.ID == uuid.Nil {
+		g.ID = store.GenNewID()
+	}
+	now := time.Now().UTC()
+	g.CreatedAt = now
+	g.UpdatedAt = now
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO secure_cli_agent_grants
+		 (id, binary_id, agent_id, deny_args, deny_verbose, timeout_seconds, tips, enabled, tenant_id, created_at, updated_at)
+		 VALUES (?,?,?,?,?,?,?,?,?,?,?)`,
+		g.ID, g.BinaryID, g.AgentID,
+		nullableJSON(g.DenyArgs), nullableJSON(g.DenyVerbose),
+		g.TimeoutSeconds, g.Tips,
+		g.Enabled, tenantIDForInsert(ctx), now, now,
+	)
+	return err
+}
+
+func (s *SQLiteSecureCLIAgentGrantStore) Get(ctx context.Context, id uuid.UUID) (*store.SecureCLIAgentGrant, error) {
+	query := `SELECT ` + grantSelectCols + ` FROM secure_cli_agent_grants WHERE id = ?`
+	args := []any{id}
+	if !store.IsCrossTenant(ctx) {
+		tid := store.TenantIDFromContext(ctx)
+		if tid == uuid.Nil {
+			return nil, sql.ErrNoRows
+		}
+		query += ` AND tenant_id = ?`
+		args = append(args, tid)
+	}
+	return scanGrant(s.db.QueryRowContext(ctx, query, args...))
+}
+
+var grantAllowedFields = map[string]bool{
+	"deny_args": true, "deny_verbose": true, "timeout_seconds": true,
+	"tips": true, "enabled": true, "updated_at": true,
+}
+
+func (s *SQLiteSecureCLIAgentGrantStore) Update(ctx context.Context, id uuid.UUID, updates map[string]any) error {
+	for k := range updates {
+		if !grantAllowedFields[k] {
+			delete(updates, k)
+		}
+	}
+	updates["updated_at"] = time.Now().UTC()
+
+	if store.IsCrossTenant(ctx) {
+		return execMapUpdate(ctx, s.db, "secure_cli_agent_grants", id, updates)
+	}
+	tid := store.TenantIDFromContext(ctx)
+	if tid == uuid.Nil {
+		return fmt.Errorf("tenant_id required")
+	}
+	return execMapUpdateWhere(ctx, s.db, "secure_cli_agent_grants", updates, "id = ? AND tenant_id = ?", id, tid)
+}
+
+func (s *SQLiteSecureCLIAgentGrantStore) Delete(ctx context.Context, id uuid.UUID) error {
+	if store.IsCrossTenant(ctx) {
+		_, err := s.db.ExecContext(ctx, "DELETE FROM secure_cli_agent_grants WHERE id = ?", id)
+		return err
+	}
+	tid := store.TenantIDFromContext(ctx)
+	if tid == uuid.Nil {
+		return fmt.Errorf("tenant_id required")
+	}
+	_, err := s.db.ExecContext(ctx, "DELETE FROM secure_cli_agent_grants WHERE id = ? AND tenant_id = ?", id, tid)
+	return err
+}
+
+func (s *SQLiteSecureCLIAgentGrantStore) ListByBinary(ctx context.Context, binaryID uuid.UUID) ([]store.SecureCLIAgentGrant, error) {
+	return s.listBy(ctx, "binary_id", binaryID)
+}
+
+func (s *SQLiteSecureCLIAgentGrantStore) ListByAgent(ctx context.Context, agentID uuid.UUID) ([]store.SecureCLIAgentGrant, error) {
+	return s.listBy(ctx, "agent_id", agentID)
+}
+
+// listBy lists tenant-scoped grants by a hardcoded foreign-key column.
+func (s *SQLiteSecureCLIAgentGrantStore) listBy(ctx context.Context, col string, id uuid.UUID) ([]store.SecureCLIAgentGrant, error) {
+	query := `SELECT ` + grantSelectCols + ` FROM secure_cli_agent_grants WHERE ` + col + ` = ?`
+	args := []any{id}
+	if !store.IsCrossTenant(ctx) {
+		tid := store.TenantIDFromContext(ctx)
+		if tid == uuid.Nil {
+			return nil, nil
+		}
+		query += ` AND tenant_id = ?`
+		args = append(args, tid)
+	}
+	query += ` ORDER BY created_at`
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.SecureCLIAgentGrant
+	for rows.Next() {
+		g, err := scanGrant(rows)
+		if err != nil {
+			continue
+		}
+		result = append(result, *g)
+	}
+	return result, rows.Err()
+}
+
+func scanGrant(row rowScanner) (*store.SecureCLIAgentGrant, error) {
+	var g store.SecureCLIAgentGrant
+	var denyArgs, denyVerbose []byte
+	createdAt, updatedAt := scanTimePair()
+	if err := row.Scan(
+		&g.ID, &g.BinaryID, &g.AgentID,
+		&denyArgs, &denyVerbose, &g.TimeoutSeconds, &g.Tips,
+		&g.Enabled, createdAt, updatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if denyArgs != nil {
+		raw := json.RawMessage(denyArgs)
+		g.DenyArgs = &raw
+	}
+	if denyVerbose != nil {
+		raw := json.RawMessage(denyVerbose)
+		g.DenyVerbose = &raw
+	}
+	g.CreatedAt = createdAt.Time
+	g.UpdatedAt = updatedAt.Time
+	return &g, nil
+}
+
+// nullableJSON returns nil if the pointer is nil, otherwise the JSON text for storage.
+func nullableJSON(v *json.RawMessage) any {
+	if v == nil {
+		return nil
+	}
+	return string(*v)
+}
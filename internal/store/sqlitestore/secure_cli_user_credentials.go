@@ -0,0 +1,95 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// GetUserCredentials returns per-user env overrides for a binary.
+// Returns (nil, nil) if no per-user credentials exist.
+func (s *SQLiteSecureCLIStore) GetUserCredentials(ctx context.Context, binaryID uuid.UUID, userID string) (*store.SecureCLIUserCredential, error) {
+	uc, err := s.scanUserCredential(s.db.QueryRowContext(ctx,
+		`SELECT id, binary_id, user_id, encrypted_env, metadata, created_at, updated_at
+		 FROM secure_cli_user_credentials
+		 WHERE binary_id = ? AND user_id = ? AND tenant_id = ?`,
+		binaryID, userID, tenantIDForInsert(ctx),
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return uc, err
+}
+
+// SetUserCredentials creates or replaces per-user env overrides for a binary.
+func (s *SQLiteSecureCLIStore) SetUserCredentials(ctx context.Context, binaryID uuid.UUID, userID string, encryptedEnv []byte) error {
+	envBytes, err := s.encryptEnv(encryptedEnv)
+	if err != nil {
+		return err
+	}
+	if envBytes == nil {
+		envBytes = []byte{}
+	}
+
+	now := time.Now().UTC()
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO secure_cli_user_credentials (id, binary_id, user_id, encrypted_env, metadata, tenant_id, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, '{}', ?, ?, ?)
+		 ON CONFLICT (binary_id, user_id, tenant_id) DO UPDATE SET
+		   encrypted_env = excluded.encrypted_env,
+		   updated_at = excluded.updated_at`,
+		store.GenNewID(), binaryID, userID, envBytes, tenantIDForInsert(ctx), now, now,
+	)
+	return err
+}
+
+func (s *SQLiteSecureCLIStore) DeleteUserCredentials(ctx context.Context, binaryID uuid.UUID, userID string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM secure_cli_user_credentials WHERE binary_id = ? AND user_id = ? AND tenant_id = ?`,
+		binaryID, userID, tenantIDForInsert(ctx),
+	)
+	return err
+}
+
+func (s *SQLiteSecureCLIStore) ListUserCredentials(ctx context.Context, binaryID uuid.UUID) ([]store.SecureCLIUserCredential, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, binary_id, user_id, encrypted_env, metadata, created_at, updated_at
+		 FROM secure_cli_user_credentials
+		 WHERE binary_id = ? AND tenant_id = ?
+		 ORDER BY created_at`, binaryID, tenantIDForInsert(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []store.SecureCLIUserCredential
+	for rows.Next() {
+		uc, err := s.scanUserCredential(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *uc)
+	}
+	return result, rows.Err()
+}
+
+func (s *SQLiteSecureCLIStore) scanUserCredential(row rowScanner) (*store.SecureCLIUserCredential, error) {
+	var uc store.SecureCLIUserCredential
+	var env, metadata []byte
+	createdAt, updatedAt := scanTimePair()
+	if err := row.Scan(&uc.ID, &uc.BinaryID, &uc.UserID, &env, &metadata, createdAt, updatedAt); err != nil {
+		return nil, err
+	}
+	uc.Metadata = metadata
+	uc.CreatedAt = createdAt.Time.Format(time.RFC3339)
+	uc.UpdatedAt = updatedAt.Time.Format(time.RFC3339)
+	uc.EncryptedEnv = s.decryptEnv(env, uc.BinaryID.String())
+	return &uc, nil
+}
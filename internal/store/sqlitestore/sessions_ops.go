@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
 func (s *SQLiteSessionStore) Save(ctx context.Context, key string) error {
@@ -27,6 +28,7 @@ func (s *SQLiteSessionStore) Save(ctx context.Context, key string) error {
 	s.mu.RUnlock()
 
 	msgsJSON, _ := json.Marshal(snapshot.Messages)
+	estimatedTokens := store.EstimateSessionTokens(&snapshot)
 	metaJSON := []byte("{}")
 	if len(snapshot.Metadata) > 0 {
 		metaJSON, _ = json.Marshal(snapshot.Metadata)
@@ -39,14 +41,14 @@ func (s *SQLiteSessionStore) Save(ctx context.Context, key string) error {
 			memory_flush_compaction_count = ?, memory_flush_at = ?,
 			label = ?, spawned_by = ?, spawn_depth = ?,
 			agent_id = ?, user_id = ?, metadata = ?, updated_at = ?,
-			team_id = ?
+			team_id = ?, estimated_tokens = ?
 		 WHERE session_key = ? AND tenant_id = ?`,
 		msgsJSON, nilStr(snapshot.Summary), nilStr(snapshot.Model), nilStr(snapshot.Provider), nilStr(snapshot.Channel),
 		snapshot.InputTokens, snapshot.OutputTokens, snapshot.CompactionCount,
 		snapshot.MemoryFlushCompactionCount, snapshot.MemoryFlushAt,
 		nilStr(snapshot.Label), nilStr(snapshot.SpawnedBy), snapshot.SpawnDepth,
 		nilSessionUUID(snapshot.AgentUUID), nilStr(snapshot.UserID), metaJSON, snapshot.Updated,
-		snapshot.TeamID,
+		snapshot.TeamID, estimatedTokens,
 		key, tenantIDForInsert(ctx),
 	)
 	if err != nil {
@@ -58,8 +60,9 @@ func (s *SQLiteSessionStore) Save(ctx context.Context, key string) error {
 			`INSERT INTO sessions (id, session_key, messages, summary, model, provider, channel,
 				input_tokens, output_tokens, compaction_count,
 				memory_flush_compaction_count, memory_flush_at,
-				label, spawned_by, spawn_depth, agent_id, user_id, metadata, updated_at, team_id, tenant_id, created_at)
-			 VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)
+				label, spawned_by, spawn_depth, agent_id, user_id, metadata, updated_at, team_id, tenant_id, created_at,
+				estimated_tokens)
+			 VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)
 			 ON CONFLICT(session_key, tenant_id) DO UPDATE SET
 				messages = excluded.messages, summary = excluded.summary, model = excluded.model,
 				provider = excluded.provider, channel = excluded.channel,
@@ -69,7 +72,8 @@ func (s *SQLiteSessionStore) Save(ctx context.Context, key string) error {
 				memory_flush_at = excluded.memory_flush_at,
 				label = excluded.label, spawned_by = excluded.spawned_by, spawn_depth = excluded.spawn_depth,
 				agent_id = excluded.agent_id, user_id = excluded.user_id, metadata = excluded.metadata,
-				updated_at = excluded.updated_at, team_id = excluded.team_id`,
+				updated_at = excluded.updated_at, team_id = excluded.team_id,
+				estimated_tokens = excluded.estimated_tokens`,
 			uuid.Must(uuid.NewV7()), key, msgsJSON,
 			nilStr(snapshot.Summary), nilStr(snapshot.Model), nilStr(snapshot.Provider), nilStr(snapshot.Channel),
 			snapshot.InputTokens, snapshot.OutputTokens, snapshot.CompactionCount,
@@ -77,6 +81,7 @@ func (s *SQLiteSessionStore) Save(ctx context.Context, key string) error {
 			nilStr(snapshot.Label), nilStr(snapshot.SpawnedBy), snapshot.SpawnDepth,
 			nilSessionUUID(snapshot.AgentUUID), nilStr(snapshot.UserID), metaJSON, snapshot.Updated,
 			snapshot.TeamID, tenantIDForInsert(ctx), snapshot.Updated,
+			estimatedTokens,
 		)
 		return err
 	}
@@ -119,7 +124,7 @@ func (s *SQLiteSessionStore) Reset(ctx context.Context, key string) {
 	// Session not in cache — clear directly in DB.
 	tid := tenantIDForInsert(ctx)
 	if _, err := s.db.ExecContext(ctx,
-		`UPDATE sessions SET messages = '[]', summary = '', updated_at = ?
+		`UPDATE sessions SET messages = '[]', summary = '', estimated_tokens = 0, updated_at = ?
 		 WHERE session_key = ? AND tenant_id = ?`,
 		time.Now(), key, tid,
 	); err != nil {
@@ -27,6 +27,7 @@ const taskSelectCols = `t.id, t.team_id, t.subject, t.description, t.status, t.o
 		 t.task_type, t.task_number, COALESCE(t.identifier,''), t.created_by_agent_id, COALESCE(t.assignee_user_id,''), t.parent_id,
 		 COALESCE(t.chat_id,''), t.metadata, t.locked_at, t.lock_expires_at, COALESCE(t.progress_percent,0), COALESCE(t.progress_step,''),
 		 t.followup_at, COALESCE(t.followup_count,0), COALESCE(t.followup_max,0), COALESCE(t.followup_message,''), COALESCE(t.followup_channel,''), COALESCE(t.followup_chat_id,''),
+		 t.due_at, COALESCE(t.sla_minutes,0), t.at_risk_at, COALESCE(t.escalation_count,0), t.next_escalation_at,
 		 COALESCE(t.comment_count,0), COALESCE(t.attachment_count,0),
 		 t.created_at, t.updated_at,
 		 COALESCE(a.agent_key, '') AS owner_agent_key,
@@ -117,8 +118,9 @@ func (s *SQLiteTeamStore) CreateTask(ctx context.Context, task *store.TeamTaskDa
 
 	_, err = tx.ExecContext(ctx,
 		`INSERT INTO team_tasks (id, team_id, subject, description, status, owner_agent_id, blocked_by, priority, result, user_id, channel,
-		 task_type, task_number, identifier, created_by_agent_id, parent_id, chat_id, metadata, locked_at, lock_expires_at, created_at, updated_at, tenant_id)
-		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		 task_type, task_number, identifier, created_by_agent_id, parent_id, chat_id, metadata, locked_at, lock_expires_at,
+		 due_at, sla_minutes, at_risk_at, created_at, updated_at, tenant_id)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		task.ID, task.TeamID, task.Subject, task.Description,
 		task.Status, task.OwnerAgentID, blockedByJSON,
 		task.Priority, task.Result,
@@ -128,6 +130,7 @@ func (s *SQLiteTeamStore) CreateTask(ctx context.Context, task *store.TeamTaskDa
 		nilStr(task.ChatID),
 		metaJSON,
 		task.LockedAt, task.LockExpiresAt,
+		utcTimePtr(task.DueAt), task.SLAMinutes, utcTimePtr(task.AtRiskAt),
 		now, now, tenantIDForInsert(ctx),
 	)
 	if err != nil {
@@ -146,6 +149,12 @@ var allowedTaskUpdateCols = map[string]bool{
 	"metadata":         true,
 	"blocked_by":       true,
 	"updated_at":       true,
+	// Due date / SLA (set together by the tool and RPC layers).
+	"due_at":             true,
+	"sla_minutes":        true,
+	"at_risk_at":         true,
+	"escalation_count":   true,
+	"next_escalation_at": true,
 }
 
 func (s *SQLiteTeamStore) UpdateTask(ctx context.Context, taskID uuid.UUID, updates map[string]any) error {
@@ -166,6 +175,15 @@ func (s *SQLiteTeamStore) UpdateTask(ctx context.Context, taskID uuid.UUID, upda
 			updates["blocked_by"] = jsonStringArray(tv)
 		}
 	}
+	// SLA timestamps are compared as text against UTC "now"; store them in UTC.
+	for _, col := range []string{"due_at", "at_risk_at", "next_escalation_at"} {
+		switch v := updates[col].(type) {
+		case time.Time:
+			updates[col] = v.UTC()
+		case *time.Time:
+			updates[col] = utcTimePtr(v)
+		}
+	}
 	updates["updated_at"] = time.Now()
 	if store.IsCrossTenant(ctx) {
 		return execMapUpdate(ctx, s.db, "team_tasks", taskID, updates)
@@ -184,6 +202,7 @@ func (s *SQLiteTeamStore) ListTasks(ctx context.Context, teamID uuid.UUID, order
 	}
 
 	statusWhere := ""
+	var statusArgs []any
 	switch statusFilter {
 	case store.TeamTaskFilterActive:
 		statusWhere = "AND t.status NOT IN ('completed','cancelled')"
@@ -191,6 +210,13 @@ func (s *SQLiteTeamStore) ListTasks(ctx context.Context, teamID uuid.UUID, order
 		statusWhere = "AND t.status = 'in_review'"
 	case store.TeamTaskFilterCompleted:
 		statusWhere = "AND t.status IN ('completed','cancelled')"
+	case store.TeamTaskFilterOverdue:
+		statusWhere = "AND t.status NOT IN ('completed','cancelled') AND t.due_at IS NOT NULL AND t.due_at <= ?"
+		statusArgs = []any{time.Now().UTC()}
+	case store.TeamTaskFilterAtRisk:
+		now := time.Now().UTC() // due_at/at_risk_at are stored in UTC and compared as text
+		statusWhere = "AND t.status NOT IN ('completed','cancelled') AND t.at_risk_at IS NOT NULL AND t.at_risk_at <= ? AND t.due_at > ?"
+		statusArgs = []any{now, now}
 	}
 
 	if limit <= 0 {
@@ -200,7 +226,8 @@ func (s *SQLiteTeamStore) ListTasks(ctx context.Context, teamID uuid.UUID, order
 	// Scope filter using COALESCE for optional channel/chatID.
 	scopeWhere := "AND (? = '' OR COALESCE(t.channel,'') = ?) AND (? = '' OR COALESCE(t.chat_id,'') = ?)"
 
-	args := []any{teamID, userID, userID, channel, channel, chatID, chatID}
+	args := append([]any{teamID, userID, userID}, statusArgs...)
+	args = append(args, channel, channel, chatID, chatID)
 
 	tenantWhere := ""
 	if !store.IsCrossTenant(ctx) {
@@ -467,6 +494,7 @@ func scanTaskRowsJoined(rows *sql.Rows) ([]store.TeamTaskData, error) {
 		var lockedAt, lockExpiresAt, followupAt nullSqliteTime
 		var followupCount, followupMax int
 		var followupMessage, followupChannel, followupChatID string
+		var dueAt, atRiskAt, nextEscalationAt nullSqliteTime
 		createdAt, updatedAt := scanTimePair()
 		if err := rows.Scan(
 			&d.ID, &d.TeamID, &d.Subject, &desc, &d.Status,
@@ -475,6 +503,7 @@ func scanTaskRowsJoined(rows *sql.Rows) ([]store.TeamTaskData, error) {
 			&d.TaskType, &d.TaskNumber, &identifier, &createdByAgentID, &assigneeUserID, &parentID,
 			&chatID, &metadataJSON, &lockedAt, &lockExpiresAt, &d.ProgressPercent, &progressStep,
 			&followupAt, &followupCount, &followupMax, &followupMessage, &followupChannel, &followupChatID,
+			&dueAt, &d.SLAMinutes, &atRiskAt, &d.EscalationCount, &nextEscalationAt,
 			&d.CommentCount, &d.AttachmentCount,
 			createdAt, updatedAt,
 			&d.OwnerAgentKey,
@@ -526,6 +555,15 @@ func scanTaskRowsJoined(rows *sql.Rows) ([]store.TeamTaskData, error) {
 		d.FollowupMessage = followupMessage
 		d.FollowupChannel = followupChannel
 		d.FollowupChatID = followupChatID
+		if dueAt.Valid {
+			d.DueAt = &dueAt.Time
+		}
+		if atRiskAt.Valid {
+			d.AtRiskAt = &atRiskAt.Time
+		}
+		if nextEscalationAt.Valid {
+			d.NextEscalationAt = &nextEscalationAt.Time
+		}
 		tasks = append(tasks, d)
 	}
 	return tasks, rows.Err()
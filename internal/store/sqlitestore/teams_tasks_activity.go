@@ -607,6 +607,40 @@ func (s *SQLiteTeamStore) IncrementFollowupCount(ctx context.Context, taskID uui
 	return err
 }
 
+func (s *SQLiteTeamStore) ListAllOverdueTasks(ctx context.Context) ([]store.RecoveredTaskInfo, error) {
+	// due_at and next_escalation_at are stored in UTC and compared as text.
+	now := time.Now().UTC()
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT t.id, t.team_id, t.tenant_id, t.task_number, t.subject,
+		        COALESCE(t.channel, ''), COALESCE(t.chat_id, '')
+		 FROM team_tasks t
+		 `+v2ActiveTeamJoin+`
+		 WHERE t.due_at IS NOT NULL
+		   AND t.due_at <= ?
+		   AND t.status NOT IN (?, ?)
+		   AND ((t.escalation_count = 0 AND t.next_escalation_at IS NULL)
+		        OR t.next_escalation_at <= ?)
+		 ORDER BY t.due_at
+		 LIMIT 100`,
+		now, store.TeamTaskStatusCompleted, store.TeamTaskStatusCancelled, now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRecoveredTaskInfoRows(rows)
+}
+
+func (s *SQLiteTeamStore) MarkTaskEscalated(ctx context.Context, taskID uuid.UUID, nextAt *time.Time) error {
+	tid := tenantIDForInsert(ctx)
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE team_tasks SET escalation_count = escalation_count + 1, next_escalation_at = ?, updated_at = ?
+		 WHERE id = ? AND tenant_id = ?`,
+		utcTimePtr(nextAt), time.Now(), taskID, tid,
+	)
+	return err
+}
+
 func (s *SQLiteTeamStore) ClearFollowupByScope(ctx context.Context, channel, chatID string) (int, error) {
 	tid := tenantIDForInsert(ctx)
 	res, err := s.db.ExecContext(ctx,
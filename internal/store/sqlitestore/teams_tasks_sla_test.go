@@ -0,0 +1,140 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestSQLiteTeamStore_OverdueEscalation(t *testing.T) {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "teams.db"))
+	if err != nil {
+		t.Fatalf("OpenDB error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if err := EnsureSchema(db); err != nil {
+		t.Fatalf("EnsureSchema error: %v", err)
+	}
+
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+	leadID := uuid.New()
+	if _, err := db.ExecContext(ctx, `INSERT INTO agents (id, agent_key, owner_id, provider, model, tenant_id) VALUES (?, ?, ?, ?, ?, ?)`,
+		leadID, "lead", "user-1", "openai", "gpt-4.1-mini", store.MasterTenantID); err != nil {
+		t.Fatalf("insert agent: %v", err)
+	}
+	teams := NewSQLiteTeamStore(db)
+	team := &store.TeamData{Name: "t", LeadAgentID: leadID, Status: store.TeamStatusActive, Settings: []byte(`{"version":2}`), CreatedBy: "user-1"}
+	if err := teams.CreateTeam(ctx, team); err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+
+	now := time.Now()
+	past, riskStart, future := now.Add(-time.Hour), now.Add(-time.Minute), now.Add(time.Hour)
+	overdue := &store.TeamTaskData{TeamID: team.ID, Subject: "late", Status: store.TeamTaskStatusPending, DueAt: &past, AtRiskAt: &past}
+	atRisk := &store.TeamTaskData{TeamID: team.ID, Subject: "close", Status: store.TeamTaskStatusPending, DueAt: &future, AtRiskAt: &riskStart}
+	done := &store.TeamTaskData{TeamID: team.ID, Subject: "done", Status: store.TeamTaskStatusCompleted, DueAt: &past}
+	for _, task := range []*store.TeamTaskData{overdue, atRisk, done} {
+		task.Metadata = map[string]any{"origin": "test"}
+		if err := teams.CreateTask(ctx, task); err != nil {
+			t.Fatalf("CreateTask: %v", err)
+		}
+	}
+
+	list := func(filter string) []store.TeamTaskData {
+		t.Helper()
+		tasks, err := teams.ListTasks(ctx, team.ID, "newest", filter, "", "", "", 0, 0)
+		if err != nil {
+			t.Fatalf("ListTasks(%s): %v", filter, err)
+		}
+		return tasks
+	}
+	if got := list(store.TeamTaskFilterOverdue); len(got) != 1 || got[0].ID != overdue.ID || got[0].DueAt == nil {
+		t.Fatalf("overdue filter = %+v", got)
+	}
+	if got := list(store.TeamTaskFilterAtRisk); len(got) != 1 || got[0].ID != atRisk.ID {
+		t.Fatalf("at_risk filter = %+v", got)
+	}
+
+	due, err := teams.ListAllOverdueTasks(ctx)
+	if err != nil || len(due) != 1 || due[0].ID != overdue.ID || due[0].TenantID != store.MasterTenantID {
+		t.Fatalf("ListAllOverdueTasks = %+v, %v", due, err)
+	}
+
+	// Scheduled re-escalation in the future → not due again yet.
+	next := now.Add(time.Hour)
+	if err := teams.MarkTaskEscalated(ctx, overdue.ID, &next); err != nil {
+		t.Fatalf("MarkTaskEscalated: %v", err)
+	}
+	if due, _ := teams.ListAllOverdueTasks(ctx); len(due) != 0 {
+		t.Fatalf("expected no escalation due, got %+v", due)
+	}
+	got, err := teams.GetTask(ctx, overdue.ID)
+	if err != nil || got.EscalationCount != 1 || got.NextEscalationAt == nil {
+		t.Fatalf("after escalation: %+v, %v", got, err)
+	}
+
+	// Re-escalation due again; the final one (nil next) stops the schedule.
+	if _, err := db.ExecContext(ctx, `UPDATE team_tasks SET next_escalation_at = ? WHERE id = ?`, past, overdue.ID); err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+	if due, _ := teams.ListAllOverdueTasks(ctx); len(due) != 1 {
+		t.Fatalf("expected re-escalation due, got %+v", due)
+	}
+	if err := teams.MarkTaskEscalated(ctx, overdue.ID, nil); err != nil {
+		t.Fatalf("MarkTaskEscalated: %v", err)
+	}
+	if due, _ := teams.ListAllOverdueTasks(ctx); len(due) != 0 {
+		t.Fatalf("expected escalation schedule to stop, got %+v", due)
+	}
+}
+
+func TestSQLiteTeamStore_OverdueOnNonUTCHost(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	prevLocal := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = prevLocal })
+
+	db, err := OpenDB(filepath.Join(t.TempDir(), "teams.db"))
+	if err != nil {
+		t.Fatalf("OpenDB error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if err := EnsureSchema(db); err != nil {
+		t.Fatalf("EnsureSchema error: %v", err)
+	}
+
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+	leadID := uuid.New()
+	if _, err := db.ExecContext(ctx, `INSERT INTO agents (id, agent_key, owner_id, provider, model, tenant_id) VALUES (?, ?, ?, ?, ?, ?)`,
+		leadID, "lead", "user-1", "openai", "gpt-4.1-mini", store.MasterTenantID); err != nil {
+		t.Fatalf("insert agent: %v", err)
+	}
+	teams := NewSQLiteTeamStore(db)
+	team := &store.TeamData{Name: "t", LeadAgentID: leadID, Status: store.TeamStatusActive, Settings: []byte(`{"version":2}`), CreatedBy: "user-1"}
+	if err := teams.CreateTeam(ctx, team); err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+
+	// Due in two hours: 7 hours east of UTC, a local "now" sorts after it as text.
+	due := time.Now().UTC().Add(2 * time.Hour)
+	task := &store.TeamTaskData{TeamID: team.ID, Subject: "soon", Status: store.TeamTaskStatusPending, DueAt: &due, Metadata: map[string]any{"origin": "test"}}
+	if err := teams.CreateTask(ctx, task); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+	if got, _ := teams.ListTasks(ctx, team.ID, "newest", store.TeamTaskFilterOverdue, "", "", "", 0, 0); len(got) != 0 {
+		t.Fatalf("task due in 2h listed as overdue: %+v", got)
+	}
+	if got, _ := teams.ListAllOverdueTasks(ctx); len(got) != 0 {
+		t.Fatalf("task due in 2h escalated as overdue: %+v", got)
+	}
+}
@@ -0,0 +1,218 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// ============================================================
+// Task templates
+// ============================================================
+
+const templateSelectCols = `tt.id, tt.team_id, tt.tenant_id, tt.name, tt.subject, tt.description, tt.owner_agent_id,
+		 tt.priority, tt.subtasks, tt.schedule, tt.timezone, tt.enabled, tt.next_run_at, tt.last_run_at, tt.run_count,
+		 tt.created_by, tt.created_at, tt.updated_at, COALESCE(a.agent_key, '')`
+
+const templateJoinClause = `FROM team_task_templates tt
+		 LEFT JOIN agents a ON a.id = tt.owner_agent_id`
+
+func (s *SQLiteTeamStore) CreateTaskTemplate(ctx context.Context, tpl *store.TeamTaskTemplateData) error {
+	if tpl.ID == uuid.Nil {
+		tpl.ID = store.GenNewID()
+	}
+	now := time.Now()
+	tpl.CreatedAt = now
+	tpl.UpdatedAt = now
+	tpl.TenantID = tenantIDForInsert(ctx)
+
+	subtasks, err := marshalTemplateSubtasks(tpl.Subtasks)
+	if err != nil {
+		return fmt.Errorf("marshal subtasks: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO team_task_templates (id, team_id, name, subject, description, owner_agent_id, priority, subtasks,
+		 schedule, timezone, enabled, next_run_at, created_by, created_at, updated_at, tenant_id)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		tpl.ID, tpl.TeamID, tpl.Name, tpl.Subject, tpl.Description, tpl.OwnerAgentID, tpl.Priority,
+		string(subtasks), tpl.Schedule, tpl.Timezone, tpl.Enabled, utcTimePtr(tpl.NextRunAt),
+		tpl.CreatedBy, now, now, tpl.TenantID,
+	)
+	return err
+}
+
+func (s *SQLiteTeamStore) GetTaskTemplate(ctx context.Context, templateID uuid.UUID) (*store.TeamTaskTemplateData, error) {
+	args := []any{templateID}
+	tenantWhere := ""
+	if !store.IsCrossTenant(ctx) {
+		tid := store.TenantIDFromContext(ctx)
+		if tid == uuid.Nil {
+			return nil, fmt.Errorf("tenant_id required")
+		}
+		tenantWhere = " AND tt.tenant_id = ?"
+		args = append(args, tid)
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+templateSelectCols+`
+		 `+templateJoinClause+`
+		 WHERE tt.id = ?`+tenantWhere, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tpls, err := scanTemplateRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(tpls) == 0 {
+		return nil, store.ErrTaskTemplateNotFound
+	}
+	return &tpls[0], nil
+}
+
+func (s *SQLiteTeamStore) ListTaskTemplates(ctx context.Context, teamID uuid.UUID) ([]store.TeamTaskTemplateData, error) {
+	args := []any{teamID}
+	tenantWhere := ""
+	if !store.IsCrossTenant(ctx) {
+		tid := store.TenantIDFromContext(ctx)
+		if tid == uuid.Nil {
+			return nil, fmt.Errorf("tenant_id required")
+		}
+		tenantWhere = " AND tt.tenant_id = ?"
+		args = append(args, tid)
+	}
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+templateSelectCols+`
+		 `+templateJoinClause+`
+		 WHERE tt.team_id = ?`+tenantWhere+`
+		 ORDER BY tt.name`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTemplateRows(rows)
+}
+
+func (s *SQLiteTeamStore) UpdateTaskTemplate(ctx context.Context, tpl *store.TeamTaskTemplateData) error {
+	subtasks, err := marshalTemplateSubtasks(tpl.Subtasks)
+	if err != nil {
+		return fmt.Errorf("marshal subtasks: %w", err)
+	}
+	tpl.UpdatedAt = time.Now()
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE team_task_templates
+		 SET name = ?, subject = ?, description = ?, owner_agent_id = ?, priority = ?, subtasks = ?,
+		     schedule = ?, timezone = ?, enabled = ?, next_run_at = ?, updated_at = ?
+		 WHERE id = ? AND team_id = ? AND tenant_id = ?`,
+		tpl.Name, tpl.Subject, tpl.Description, tpl.OwnerAgentID, tpl.Priority, string(subtasks),
+		tpl.Schedule, tpl.Timezone, tpl.Enabled, utcTimePtr(tpl.NextRunAt), tpl.UpdatedAt,
+		tpl.ID, tpl.TeamID, tenantIDForInsert(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrTaskTemplateNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteTeamStore) DeleteTaskTemplate(ctx context.Context, templateID, teamID uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM team_task_templates WHERE id = ? AND team_id = ? AND tenant_id = ?`,
+		templateID, teamID, tenantIDForInsert(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrTaskTemplateNotFound
+	}
+	return nil
+}
+
+// ListDueTaskTemplates returns enabled templates with a due next_run_at across all v2 active teams.
+// next_run_at is always written in UTC so the text comparison orders correctly.
+func (s *SQLiteTeamStore) ListDueTaskTemplates(ctx context.Context, now time.Time) ([]store.TeamTaskTemplateData, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+templateSelectCols+`
+		 `+templateJoinClause+`
+		 JOIN agent_teams tm ON tm.id = tt.team_id
+		   AND tm.status = 'active'
+		   AND COALESCE(CAST(json_extract(tm.settings, '$.version') AS INTEGER), 0) >= 2
+		 WHERE tt.enabled = 1 AND tt.next_run_at IS NOT NULL AND tt.next_run_at <= ?
+		 ORDER BY tt.next_run_at
+		 LIMIT 100`,
+		now.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanTemplateRows(rows)
+}
+
+func (s *SQLiteTeamStore) MarkTaskTemplateRun(ctx context.Context, templateID uuid.UUID, ranAt time.Time, nextRunAt *time.Time) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE team_task_templates SET last_run_at = ?, next_run_at = ?, run_count = run_count + 1, updated_at = ?
+		 WHERE id = ? AND tenant_id = ?`,
+		ranAt.UTC(), utcTimePtr(nextRunAt), time.Now(), templateID, tenantIDForInsert(ctx),
+	)
+	return err
+}
+
+func marshalTemplateSubtasks(subtasks []store.TeamTaskTemplateSubtask) ([]byte, error) {
+	if len(subtasks) == 0 {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(subtasks)
+}
+
+// utcTimePtr normalizes a nullable time to UTC for text-comparable storage.
+func utcTimePtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+func scanTemplateRows(rows *sql.Rows) ([]store.TeamTaskTemplateData, error) {
+	var tpls []store.TeamTaskTemplateData
+	for rows.Next() {
+		var d store.TeamTaskTemplateData
+		var ownerID *uuid.UUID
+		var subtasksJSON []byte
+		var nextRunAt, lastRunAt nullSqliteTime
+		createdAt, updatedAt := scanTimePair()
+		if err := rows.Scan(
+			&d.ID, &d.TeamID, &d.TenantID, &d.Name, &d.Subject, &d.Description, &ownerID,
+			&d.Priority, &subtasksJSON, &d.Schedule, &d.Timezone, &d.Enabled, &nextRunAt, &lastRunAt, &d.RunCount,
+			&d.CreatedBy, createdAt, updatedAt, &d.OwnerAgentKey,
+		); err != nil {
+			return nil, err
+		}
+		d.OwnerAgentID = ownerID
+		d.CreatedAt = createdAt.Time
+		d.UpdatedAt = updatedAt.Time
+		if len(subtasksJSON) > 0 {
+			_ = json.Unmarshal(subtasksJSON, &d.Subtasks)
+		}
+		if nextRunAt.Valid {
+			d.NextRunAt = &nextRunAt.Time
+		}
+		if lastRunAt.Valid {
+			d.LastRunAt = &lastRunAt.Time
+		}
+		tpls = append(tpls, d)
+	}
+	return tpls, rows.Err()
+}
@@ -0,0 +1,100 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestSQLiteTeamStore_TaskTemplates(t *testing.T) {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "teams.db"))
+	if err != nil {
+		t.Fatalf("OpenDB error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if err := EnsureSchema(db); err != nil {
+		t.Fatalf("EnsureSchema error: %v", err)
+	}
+
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+	leadID, memberID := uuid.New(), uuid.New()
+	for key, id := range map[string]uuid.UUID{"lead": leadID, "writer": memberID} {
+		if _, err := db.ExecContext(ctx, `INSERT INTO agents (id, agent_key, owner_id, provider, model, tenant_id) VALUES (?, ?, ?, ?, ?, ?)`,
+			id, key, "user-1", "openai", "gpt-4.1-mini", store.MasterTenantID); err != nil {
+			t.Fatalf("insert agent: %v", err)
+		}
+	}
+	teams := NewSQLiteTeamStore(db)
+	team := &store.TeamData{Name: "t", LeadAgentID: leadID, Status: store.TeamStatusActive, Settings: []byte(`{"version":2}`), CreatedBy: "user-1"}
+	if err := teams.CreateTeam(ctx, team); err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	tpl := &store.TeamTaskTemplateData{
+		TeamID:       team.ID,
+		Name:         "weekly",
+		Subject:      "Weekly report",
+		OwnerAgentID: &memberID,
+		Subtasks:     []store.TeamTaskTemplateSubtask{{Key: "draft", Subject: "Draft"}},
+		Schedule:     "0 9 * * 1",
+		Enabled:      true,
+		NextRunAt:    &past,
+		CreatedBy:    "user-1",
+	}
+	if err := teams.CreateTaskTemplate(ctx, tpl); err != nil {
+		t.Fatalf("CreateTaskTemplate: %v", err)
+	}
+
+	got, err := teams.GetTaskTemplate(ctx, tpl.ID)
+	if err != nil {
+		t.Fatalf("GetTaskTemplate: %v", err)
+	}
+	if got.OwnerAgentKey != "writer" || len(got.Subtasks) != 1 || got.Subtasks[0].Key != "draft" || got.NextRunAt == nil {
+		t.Fatalf("unexpected template: %+v", got)
+	}
+
+	due, err := teams.ListDueTaskTemplates(ctx, time.Now())
+	if err != nil || len(due) != 1 || due[0].ID != tpl.ID || due[0].TenantID != store.MasterTenantID {
+		t.Fatalf("ListDueTaskTemplates = %+v, %v", due, err)
+	}
+
+	// Marking a run with a future next_run_at takes it off the due list.
+	next := time.Now().Add(time.Hour)
+	if err := teams.MarkTaskTemplateRun(ctx, tpl.ID, time.Now(), &next); err != nil {
+		t.Fatalf("MarkTaskTemplateRun: %v", err)
+	}
+	if due, _ := teams.ListDueTaskTemplates(ctx, time.Now()); len(due) != 0 {
+		t.Fatalf("expected no template due, got %+v", due)
+	}
+	got, _ = teams.GetTaskTemplate(ctx, tpl.ID)
+	if got.RunCount != 1 || got.LastRunAt == nil {
+		t.Fatalf("after run: %+v", got)
+	}
+
+	got.Subject = "Weekly summary"
+	got.Enabled = false
+	got.NextRunAt = nil
+	if err := teams.UpdateTaskTemplate(ctx, got); err != nil {
+		t.Fatalf("UpdateTaskTemplate: %v", err)
+	}
+	list, err := teams.ListTaskTemplates(ctx, team.ID)
+	if err != nil || len(list) != 1 || list[0].Subject != "Weekly summary" || list[0].Enabled {
+		t.Fatalf("ListTaskTemplates = %+v, %v", list, err)
+	}
+
+	if err := teams.DeleteTaskTemplate(ctx, tpl.ID, team.ID); err != nil {
+		t.Fatalf("DeleteTaskTemplate: %v", err)
+	}
+	if _, err := teams.GetTaskTemplate(ctx, tpl.ID); !errors.Is(err, store.ErrTaskTemplateNotFound) {
+		t.Fatalf("after delete: %v", err)
+	}
+}
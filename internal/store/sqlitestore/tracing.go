@@ -235,6 +235,43 @@ func (s *SQLiteTracingStore) GetCostSummary(ctx context.Context, opts store.Cost
 	return result, rows.Err()
 }
 
+// GetSpend sums LLM span costs matching q. The user filter joins the owning
+// trace since spans do not carry user_id.
+func (s *SQLiteTracingStore) GetSpend(ctx context.Context, q store.SpendQuery) (float64, error) {
+	conditions := []string{"s.total_cost IS NOT NULL", "s.created_at >= ?"}
+	args := []any{q.From}
+
+	tenantID := q.TenantID
+	if tenantID == uuid.Nil && !store.IsCrossTenant(ctx) {
+		tenantID = store.TenantIDFromContext(ctx)
+	}
+	if tenantID != uuid.Nil {
+		conditions = append(conditions, "s.tenant_id = ?")
+		args = append(args, tenantID)
+	}
+	if !q.To.IsZero() {
+		conditions = append(conditions, "s.created_at < ?")
+		args = append(args, q.To)
+	}
+	if q.AgentID != nil {
+		conditions = append(conditions, "s.agent_id = ?")
+		args = append(args, *q.AgentID)
+	}
+	from := "spans s"
+	if q.UserID != "" {
+		from = "spans s JOIN traces t ON t.id = s.trace_id"
+		conditions = append(conditions, "t.user_id = ?")
+		args = append(args, q.UserID)
+	}
+
+	var cost float64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(s.total_cost), 0) FROM `+from+` WHERE `+strings.Join(conditions, " AND "),
+		args...,
+	).Scan(&cost)
+	return cost, err
+}
+
 // DeleteTracesOlderThan deletes traces and their spans older than cutoff.
 func (s *SQLiteTracingStore) DeleteTracesOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
 	// Delete spans belonging to old traces.
@@ -0,0 +1,338 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SQLiteWorkflowStore implements store.WorkflowStore backed by SQLite.
+// SQLite deployments run a single gateway process, so run locks are
+// in-process mutexes rather than row locks.
+type SQLiteWorkflowStore struct {
+	db *sql.DB
+
+	runLocksMu sync.Mutex
+	runLocks   map[uuid.UUID]*sync.Mutex
+}
+
+func NewSQLiteWorkflowStore(db *sql.DB) *SQLiteWorkflowStore {
+	return &SQLiteWorkflowStore{db: db, runLocks: make(map[uuid.UUID]*sync.Mutex)}
+}
+
+// ============================================================
+// Workflows
+// ============================================================
+
+const workflowSelectCols = `id, team_id, tenant_id, name, description, definition, enabled, created_by, created_at, updated_at`
+
+func (s *SQLiteWorkflowStore) CreateWorkflow(ctx context.Context, wf *store.WorkflowData) error {
+	if wf.ID == uuid.Nil {
+		wf.ID = store.GenNewID()
+	}
+	now := time.Now()
+	wf.CreatedAt = now
+	wf.UpdatedAt = now
+	wf.TenantID = tenantIDForInsert(ctx)
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO team_workflows (id, team_id, name, description, definition, enabled, created_by, created_at, updated_at, tenant_id)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		wf.ID, wf.TeamID, wf.Name, wf.Description, string(jsonOrEmpty(wf.Definition)), wf.Enabled, wf.CreatedBy, now, now, wf.TenantID,
+	)
+	return err
+}
+
+func (s *SQLiteWorkflowStore) GetWorkflow(ctx context.Context, id uuid.UUID) (*store.WorkflowData, error) {
+	where, args, err := workflowTenantWhere(ctx, "tenant_id", "id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	wfs, err := s.queryWorkflows(ctx, `SELECT `+workflowSelectCols+` FROM team_workflows WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(wfs) == 0 {
+		return nil, store.ErrWorkflowNotFound
+	}
+	return &wfs[0], nil
+}
+
+func (s *SQLiteWorkflowStore) GetWorkflowByName(ctx context.Context, teamID uuid.UUID, name string) (*store.WorkflowData, error) {
+	where, args, err := workflowTenantWhere(ctx, "tenant_id", "team_id = ? AND LOWER(name) = LOWER(?)", teamID, name)
+	if err != nil {
+		return nil, err
+	}
+	wfs, err := s.queryWorkflows(ctx, `SELECT `+workflowSelectCols+` FROM team_workflows WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(wfs) == 0 {
+		return nil, store.ErrWorkflowNotFound
+	}
+	return &wfs[0], nil
+}
+
+func (s *SQLiteWorkflowStore) ListWorkflows(ctx context.Context, teamID uuid.UUID) ([]store.WorkflowData, error) {
+	where, args, err := workflowTenantWhere(ctx, "tenant_id", "team_id = ?", teamID)
+	if err != nil {
+		return nil, err
+	}
+	return s.queryWorkflows(ctx, `SELECT `+workflowSelectCols+` FROM team_workflows WHERE `+where+` ORDER BY name`, args...)
+}
+
+func (s *SQLiteWorkflowStore) UpdateWorkflow(ctx context.Context, wf *store.WorkflowData) error {
+	wf.UpdatedAt = time.Now()
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE team_workflows SET name = ?, description = ?, definition = ?, enabled = ?, updated_at = ?
+		 WHERE id = ? AND team_id = ? AND tenant_id = ?`,
+		wf.Name, wf.Description, string(jsonOrEmpty(wf.Definition)), wf.Enabled, wf.UpdatedAt,
+		wf.ID, wf.TeamID, tenantIDForInsert(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrWorkflowNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteWorkflowStore) DeleteWorkflow(ctx context.Context, id, teamID uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM team_workflows WHERE id = ? AND team_id = ? AND tenant_id = ?`,
+		id, teamID, tenantIDForInsert(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrWorkflowNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteWorkflowStore) queryWorkflows(ctx context.Context, query string, args ...any) ([]store.WorkflowData, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var wfs []store.WorkflowData
+	for rows.Next() {
+		var d store.WorkflowData
+		var def []byte
+		createdAt, updatedAt := scanTimePair()
+		if err := rows.Scan(&d.ID, &d.TeamID, &d.TenantID, &d.Name, &d.Description, &def, &d.Enabled,
+			&d.CreatedBy, createdAt, updatedAt); err != nil {
+			return nil, err
+		}
+		d.CreatedAt = createdAt.Time
+		d.UpdatedAt = updatedAt.Time
+		d.Definition = json.RawMessage(def)
+		wfs = append(wfs, d)
+	}
+	return wfs, rows.Err()
+}
+
+// ============================================================
+// Workflow runs
+// ============================================================
+
+const workflowRunSelectCols = `r.id, r.workflow_id, r.team_id, r.tenant_id, r.status, r.trigger_kind, r.triggered_by,
+		 r.input, r.definition, r.steps, r.output, r.error, r.trace_id, r.started_at, r.finished_at,
+		 r.created_at, r.updated_at, COALESCE(w.name, '')`
+
+const workflowRunJoinClause = `FROM team_workflow_runs r
+		 LEFT JOIN team_workflows w ON w.id = r.workflow_id`
+
+func (s *SQLiteWorkflowStore) CreateWorkflowRun(ctx context.Context, run *store.WorkflowRunData) error {
+	if run.ID == uuid.Nil {
+		run.ID = store.GenNewID()
+	}
+	now := time.Now()
+	run.CreatedAt = now
+	run.UpdatedAt = now
+	if run.StartedAt.IsZero() {
+		run.StartedAt = now
+	}
+	run.TenantID = tenantIDForInsert(ctx)
+	input, steps, err := marshalWorkflowRunJSON(run)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO team_workflow_runs (id, workflow_id, team_id, status, trigger_kind, triggered_by, input, definition, steps,
+		 output, error, trace_id, started_at, finished_at, created_at, updated_at, tenant_id)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.ID, run.WorkflowID, run.TeamID, run.Status, run.Trigger, run.TriggeredBy, string(input), string(jsonOrEmpty(run.Definition)), string(steps),
+		run.Output, run.Error, nilUUID(run.TraceID), run.StartedAt, nilTime(run.FinishedAt), now, now, run.TenantID,
+	)
+	return err
+}
+
+func (s *SQLiteWorkflowStore) GetWorkflowRun(ctx context.Context, id uuid.UUID) (*store.WorkflowRunData, error) {
+	where, args, err := workflowTenantWhere(ctx, "r.tenant_id", "r.id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	runs, err := s.queryRuns(ctx, `SELECT `+workflowRunSelectCols+` `+workflowRunJoinClause+` WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, store.ErrWorkflowRunNotFound
+	}
+	return &runs[0], nil
+}
+
+func (s *SQLiteWorkflowStore) ListWorkflowRuns(ctx context.Context, teamID, workflowID uuid.UUID, limit int) ([]store.WorkflowRunData, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	cond, args := "r.team_id = ?", []any{teamID}
+	if workflowID != uuid.Nil {
+		cond, args = "r.team_id = ? AND r.workflow_id = ?", []any{teamID, workflowID}
+	}
+	where, args, err := workflowTenantWhere(ctx, "r.tenant_id", cond, args...)
+	if err != nil {
+		return nil, err
+	}
+	return s.queryRuns(ctx,
+		`SELECT `+workflowRunSelectCols+` `+workflowRunJoinClause+` WHERE `+where+
+			fmt.Sprintf(` ORDER BY r.created_at DESC LIMIT %d`, limit), args...)
+}
+
+func (s *SQLiteWorkflowStore) UpdateWorkflowRun(ctx context.Context, run *store.WorkflowRunData) error {
+	_, steps, err := marshalWorkflowRunJSON(run)
+	if err != nil {
+		return err
+	}
+	run.UpdatedAt = time.Now()
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE team_workflow_runs SET status = ?, steps = ?, output = ?, error = ?, trace_id = ?, finished_at = ?, updated_at = ?
+		 WHERE id = ? AND tenant_id = ?`,
+		run.Status, string(steps), run.Output, run.Error, nilUUID(run.TraceID), nilTime(run.FinishedAt), run.UpdatedAt,
+		run.ID, tenantIDForInsert(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return store.ErrWorkflowRunNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteWorkflowStore) LockWorkflowRun(ctx context.Context, id uuid.UUID, fn func(run *store.WorkflowRunData) error) (*store.WorkflowRunData, error) {
+	mu := s.runLock(id)
+	mu.Lock()
+	defer mu.Unlock()
+
+	run, err := s.GetWorkflowRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(run); err != nil {
+		return run, err
+	}
+	if run.Status != store.WorkflowRunStatusRunning {
+		s.runLocksMu.Lock()
+		delete(s.runLocks, id)
+		s.runLocksMu.Unlock()
+	}
+	return run, s.UpdateWorkflowRun(ctx, run)
+}
+
+func (s *SQLiteWorkflowStore) runLock(id uuid.UUID) *sync.Mutex {
+	s.runLocksMu.Lock()
+	defer s.runLocksMu.Unlock()
+	mu, ok := s.runLocks[id]
+	if !ok {
+		mu = &sync.Mutex{}
+		s.runLocks[id] = mu
+	}
+	return mu
+}
+
+func (s *SQLiteWorkflowStore) ListActiveWorkflowRuns(ctx context.Context) ([]store.WorkflowRunData, error) {
+	return s.queryRuns(ctx,
+		`SELECT `+workflowRunSelectCols+` `+workflowRunJoinClause+`
+		 WHERE r.status = ?
+		 ORDER BY r.started_at
+		 LIMIT 200`, store.WorkflowRunStatusRunning)
+}
+
+func (s *SQLiteWorkflowStore) queryRuns(ctx context.Context, query string, args ...any) ([]store.WorkflowRunData, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var runs []store.WorkflowRunData
+	for rows.Next() {
+		var d store.WorkflowRunData
+		var input, def, steps []byte
+		var traceID *uuid.UUID
+		startedAt := &sqliteTime{}
+		var finishedAt nullSqliteTime
+		createdAt, updatedAt := scanTimePair()
+		if err := rows.Scan(&d.ID, &d.WorkflowID, &d.TeamID, &d.TenantID, &d.Status, &d.Trigger, &d.TriggeredBy,
+			&input, &def, &steps, &d.Output, &d.Error, &traceID, startedAt, &finishedAt,
+			createdAt, updatedAt, &d.WorkflowName); err != nil {
+			return nil, err
+		}
+		d.TraceID = traceID
+		d.StartedAt = startedAt.Time
+		d.CreatedAt = createdAt.Time
+		d.UpdatedAt = updatedAt.Time
+		if finishedAt.Valid {
+			d.FinishedAt = &finishedAt.Time
+		}
+		d.Definition = json.RawMessage(def)
+		_ = json.Unmarshal(input, &d.Input)
+		_ = json.Unmarshal(steps, &d.Steps)
+		runs = append(runs, d)
+	}
+	return runs, rows.Err()
+}
+
+// workflowTenantWhere appends "AND <col> = ?" tenant scoping to cond.
+// Cross-tenant contexts are left unscoped.
+func workflowTenantWhere(ctx context.Context, col, cond string, args ...any) (string, []any, error) {
+	if store.IsCrossTenant(ctx) {
+		return cond, args, nil
+	}
+	tid := store.TenantIDFromContext(ctx)
+	if tid == uuid.Nil {
+		return "", nil, fmt.Errorf("tenant_id required")
+	}
+	args = append(args, tid)
+	return cond + " AND " + col + " = ?", args, nil
+}
+
+func marshalWorkflowRunJSON(run *store.WorkflowRunData) (input, steps []byte, err error) {
+	in := run.Input
+	if in == nil {
+		in = map[string]string{}
+	}
+	if input, err = json.Marshal(in); err != nil {
+		return nil, nil, fmt.Errorf("marshal input: %w", err)
+	}
+	st := run.Steps
+	if st == nil {
+		st = map[string]*store.WorkflowStepState{}
+	}
+	if steps, err = json.Marshal(st); err != nil {
+		return nil, nil, fmt.Errorf("marshal steps: %w", err)
+	}
+	return input, steps, nil
+}
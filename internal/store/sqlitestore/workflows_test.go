@@ -0,0 +1,119 @@
+//go:build sqlite || sqliteonly
+
+package sqlitestore
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestSQLiteWorkflowStore_WorkflowsAndRuns(t *testing.T) {
+	db, err := OpenDB(filepath.Join(t.TempDir(), "workflows.db"))
+	if err != nil {
+		t.Fatalf("OpenDB error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if err := EnsureSchema(db); err != nil {
+		t.Fatalf("EnsureSchema error: %v", err)
+	}
+
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+	leadID := uuid.New()
+	if _, err := db.ExecContext(ctx, `INSERT INTO agents (id, agent_key, owner_id, provider, model, tenant_id) VALUES (?, ?, ?, ?, ?, ?)`,
+		leadID, "lead", "user-1", "openai", "gpt-4.1-mini", store.MasterTenantID); err != nil {
+		t.Fatalf("insert agent: %v", err)
+	}
+	team := &store.TeamData{Name: "t", LeadAgentID: leadID, Status: store.TeamStatusActive, Settings: []byte(`{"version":2}`), CreatedBy: "user-1"}
+	if err := NewSQLiteTeamStore(db).CreateTeam(ctx, team); err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+
+	ws := NewSQLiteWorkflowStore(db)
+	wf := &store.WorkflowData{
+		TeamID:     team.ID,
+		Name:       "Digest",
+		Definition: []byte(`{"steps":[{"id":"a","agent":"writer","task":"A"}]}`),
+		Enabled:    true,
+		CreatedBy:  "user-1",
+	}
+	if err := ws.CreateWorkflow(ctx, wf); err != nil {
+		t.Fatalf("CreateWorkflow: %v", err)
+	}
+	got, err := ws.GetWorkflowByName(ctx, team.ID, "digest")
+	if err != nil || got.ID != wf.ID || string(got.Definition) != string(wf.Definition) {
+		t.Fatalf("GetWorkflowByName = %+v, %v", got, err)
+	}
+
+	got.Description = "weekly"
+	got.Enabled = false
+	if err := ws.UpdateWorkflow(ctx, got); err != nil {
+		t.Fatalf("UpdateWorkflow: %v", err)
+	}
+	list, err := ws.ListWorkflows(ctx, team.ID)
+	if err != nil || len(list) != 1 || list[0].Description != "weekly" || list[0].Enabled {
+		t.Fatalf("ListWorkflows = %+v, %v", list, err)
+	}
+
+	taskID := uuid.New()
+	run := &store.WorkflowRunData{
+		WorkflowID: wf.ID,
+		TeamID:     team.ID,
+		Status:     store.WorkflowRunStatusRunning,
+		Trigger:    store.WorkflowTriggerManual,
+		Input:      map[string]string{"topic": "go"},
+		Definition: wf.Definition,
+		Steps: map[string]*store.WorkflowStepState{
+			"a": {Status: store.WorkflowStepStatusActive, TaskID: &taskID},
+		},
+	}
+	if err := ws.CreateWorkflowRun(ctx, run); err != nil {
+		t.Fatalf("CreateWorkflowRun: %v", err)
+	}
+	active, err := ws.ListActiveWorkflowRuns(context.Background())
+	if err != nil || len(active) != 1 || active[0].TenantID != store.MasterTenantID || active[0].WorkflowName != "Digest" {
+		t.Fatalf("ListActiveWorkflowRuns = %+v, %v", active, err)
+	}
+
+	now := time.Now()
+	run.Status = store.WorkflowRunStatusCompleted
+	run.Steps["a"].Status = store.WorkflowStepStatusCompleted
+	run.Steps["a"].Output = "done"
+	run.Output = "done"
+	run.FinishedAt = &now
+	if err := ws.UpdateWorkflowRun(ctx, run); err != nil {
+		t.Fatalf("UpdateWorkflowRun: %v", err)
+	}
+	gotRun, err := ws.GetWorkflowRun(ctx, run.ID)
+	if err != nil {
+		t.Fatalf("GetWorkflowRun: %v", err)
+	}
+	if gotRun.Status != store.WorkflowRunStatusCompleted || gotRun.FinishedAt == nil || gotRun.Input["topic"] != "go" ||
+		gotRun.Steps["a"].Output != "done" || *gotRun.Steps["a"].TaskID != taskID {
+		t.Fatalf("unexpected run: %+v", gotRun)
+	}
+	if active, _ := ws.ListActiveWorkflowRuns(context.Background()); len(active) != 0 {
+		t.Fatalf("expected no active runs, got %d", len(active))
+	}
+	runs, err := ws.ListWorkflowRuns(ctx, team.ID, wf.ID, 10)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("ListWorkflowRuns = %+v, %v", runs, err)
+	}
+
+	// Deleting the workflow removes its runs.
+	if err := ws.DeleteWorkflow(ctx, wf.ID, team.ID); err != nil {
+		t.Fatalf("DeleteWorkflow: %v", err)
+	}
+	if _, err := ws.GetWorkflow(ctx, wf.ID); !errors.Is(err, store.ErrWorkflowNotFound) {
+		t.Fatalf("after delete: %v", err)
+	}
+	if _, err := ws.GetWorkflowRun(ctx, run.ID); !errors.Is(err, store.ErrWorkflowRunNotFound) {
+		t.Fatalf("run after delete: %v", err)
+	}
+}
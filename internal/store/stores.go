@@ -18,9 +18,12 @@ type Stores struct {
 	ConfigSecrets         ConfigSecretsStore
 	AgentLinks            AgentLinkStore
 	Teams                 TeamStore
+	Workflows             WorkflowStore
 	BuiltinTools          BuiltinToolStore
 	PendingMessages       PendingMessageStore
 	KnowledgeGraph        KnowledgeGraphStore
+	KnowledgeBases        KnowledgeBaseStore
+	EmbeddingCache        EmbeddingCacheStore
 	Contacts              ContactStore
 	Activity              ActivityStore
 	Snapshots             SnapshotStore
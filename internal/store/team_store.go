@@ -23,6 +23,9 @@ type RecoveredTaskInfo struct {
 // ErrTaskNotFound is returned when a task does not exist.
 var ErrTaskNotFound = errors.New("task not found")
 
+// ErrTaskTemplateNotFound is returned when a task template does not exist.
+var ErrTaskTemplateNotFound = errors.New("task template not found")
+
 // Team status constants.
 const (
 	TeamStatusActive   = "active"
@@ -54,6 +57,8 @@ const (
 	TeamTaskFilterInReview  = "in_review" // only in_review tasks
 	TeamTaskFilterCompleted = "completed" // only completed tasks
 	TeamTaskFilterAll       = "all"       // all statuses (default when "" passed)
+	TeamTaskFilterOverdue   = "overdue"   // active tasks past their due date
+	TeamTaskFilterAtRisk    = "at_risk"   // active tasks inside the at-risk window, not yet overdue
 )
 
 
@@ -126,6 +131,13 @@ type TeamTaskData struct {
 	FollowupChannel string     `json:"followup_channel,omitempty"`
 	FollowupChatID  string     `json:"followup_chat_id,omitempty"`
 
+	// Due date / SLA fields
+	DueAt            *time.Time `json:"due_at,omitempty"`
+	SLAMinutes       int        `json:"sla_minutes,omitempty"`        // SLA target the due date was derived from (0 = explicit or none)
+	AtRiskAt         *time.Time `json:"at_risk_at,omitempty"`         // task is "at risk" from this time until due_at
+	EscalationCount  int        `json:"escalation_count,omitempty"`   // overdue escalations sent so far
+	NextEscalationAt *time.Time `json:"next_escalation_at,omitempty"` // nil = no further escalation scheduled
+
 	// Denormalized counts for dashboard performance
 	CommentCount    int `json:"comment_count"`
 	AttachmentCount int `json:"attachment_count"`
@@ -176,6 +188,46 @@ type TeamTaskAttachmentData struct {
 	DownloadURL       string          `json:"download_url,omitempty"` // signed URL, populated at delivery time
 }
 
+// TeamTaskTemplateData is a reusable task blueprint. Instantiating it creates
+// a parent task plus one child task (ParentID = parent) per subtask. A
+// non-empty Schedule (cron expression) makes the task ticker instantiate it
+// automatically.
+type TeamTaskTemplateData struct {
+	BaseModel
+	TeamID       uuid.UUID                 `json:"team_id"`
+	TenantID     uuid.UUID                 `json:"tenant_id"`
+	Name         string                    `json:"name"`
+	Subject      string                    `json:"subject"`
+	Description  string                    `json:"description,omitempty"`
+	OwnerAgentID *uuid.UUID                `json:"owner_agent_id,omitempty"` // default owner for the parent and subtasks
+	Priority     int                       `json:"priority"`
+	Subtasks     []TeamTaskTemplateSubtask `json:"subtasks,omitempty"`
+
+	// Recurrence (cron expression evaluated in Timezone; empty = manual only)
+	Schedule  string     `json:"schedule,omitempty"`
+	Timezone  string     `json:"timezone,omitempty"`
+	Enabled   bool       `json:"enabled"`
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	RunCount  int        `json:"run_count"`
+
+	CreatedBy string `json:"created_by,omitempty"`
+
+	// Joined fields
+	OwnerAgentKey string `json:"owner_agent_key,omitempty"`
+}
+
+// TeamTaskTemplateSubtask is one child task of a template. BlockedBy lists the
+// keys of sibling subtasks that must complete first.
+type TeamTaskTemplateSubtask struct {
+	Key          string     `json:"key"`
+	Subject      string     `json:"subject"`
+	Description  string     `json:"description,omitempty"`
+	OwnerAgentID *uuid.UUID `json:"owner_agent_id,omitempty"` // nil = template owner
+	Priority     int        `json:"priority,omitempty"`
+	BlockedBy    []string   `json:"blocked_by,omitempty"`
+}
+
 // TeamUserGrant represents a user's access grant to a team.
 type TeamUserGrant struct {
 	ID        uuid.UUID `json:"id"`
@@ -256,6 +308,12 @@ type TaskRecoveryStore interface {
 	MarkAllStaleTasks(ctx context.Context, olderThan time.Time) ([]RecoveredTaskInfo, error)
 	MarkInReviewStaleTasks(ctx context.Context, olderThan time.Time) ([]RecoveredTaskInfo, error)
 	FixOrphanedBlockedTasks(ctx context.Context) ([]RecoveredTaskInfo, error)
+	// ListAllOverdueTasks returns active tasks (across v2 active teams) that are
+	// past due and have an escalation due: never escalated, or next_escalation_at reached.
+	ListAllOverdueTasks(ctx context.Context) ([]RecoveredTaskInfo, error)
+	// MarkTaskEscalated increments escalation_count and schedules the next
+	// escalation (nil = stop escalating).
+	MarkTaskEscalated(ctx context.Context, taskID uuid.UUID, nextAt *time.Time) error
 }
 
 // TaskFollowupStore manages follow-up reminder scheduling.
@@ -278,6 +336,20 @@ type TeamAccessStore interface {
 	HasTeamAccess(ctx context.Context, teamID uuid.UUID, userID string) (bool, error)
 }
 
+// TaskTemplateStore manages reusable task templates and their recurrence state.
+type TaskTemplateStore interface {
+	CreateTaskTemplate(ctx context.Context, tpl *TeamTaskTemplateData) error
+	GetTaskTemplate(ctx context.Context, templateID uuid.UUID) (*TeamTaskTemplateData, error)
+	ListTaskTemplates(ctx context.Context, teamID uuid.UUID) ([]TeamTaskTemplateData, error)
+	UpdateTaskTemplate(ctx context.Context, tpl *TeamTaskTemplateData) error
+	DeleteTaskTemplate(ctx context.Context, templateID, teamID uuid.UUID) error
+	// ListDueTaskTemplates returns enabled templates whose next_run_at has passed,
+	// across all v2 active teams (cross-tenant, for the task ticker).
+	ListDueTaskTemplates(ctx context.Context, now time.Time) ([]TeamTaskTemplateData, error)
+	// MarkTaskTemplateRun records a run and stores the next scheduled run (nil = none).
+	MarkTaskTemplateRun(ctx context.Context, templateID uuid.UUID, ranAt time.Time, nextRunAt *time.Time) error
+}
+
 // TeamStore composes all team sub-interfaces for backward compatibility.
 // New code should depend on the specific sub-interface it needs.
 type TeamStore interface {
@@ -287,4 +359,5 @@ type TeamStore interface {
 	TaskRecoveryStore
 	TaskFollowupStore
 	TeamAccessStore
+	TaskTemplateStore
 }
@@ -115,6 +115,17 @@ type CostSummaryOpts struct {
 	To      *time.Time
 }
 
+// SpendQuery selects LLM spend summed from span costs. Unlike trace totals,
+// span costs include runs that are still in progress. Zero-valued filters
+// are ignored; the tenant filter falls back to the context tenant.
+type SpendQuery struct {
+	TenantID uuid.UUID
+	AgentID  *uuid.UUID
+	UserID   string
+	From     time.Time
+	To       time.Time
+}
+
 // CostSummaryRow is a single row of aggregated cost data.
 type CostSummaryRow struct {
 	AgentID           *uuid.UUID `json:"agent_id,omitempty"`
@@ -156,6 +167,7 @@ type TracingStore interface {
 	// Cost aggregation
 	GetMonthlyAgentCost(ctx context.Context, agentID uuid.UUID, year int, month time.Month) (float64, error)
 	GetCostSummary(ctx context.Context, opts CostSummaryOpts) ([]CostSummaryRow, error)
+	GetSpend(ctx context.Context, q SpendQuery) (float64, error)
 
 	// Maintenance
 	DeleteTracesOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
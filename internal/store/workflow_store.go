@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWorkflowNotFound    = errors.New("workflow not found")
+	ErrWorkflowRunNotFound = errors.New("workflow run not found")
+)
+
+// Workflow run status constants.
+const (
+	WorkflowRunStatusRunning   = "running"
+	WorkflowRunStatusCompleted = "completed"
+	WorkflowRunStatusFailed    = "failed"
+	WorkflowRunStatusCancelled = "cancelled"
+)
+
+// Workflow step status constants.
+const (
+	WorkflowStepStatusWaiting   = "waiting"   // dependencies not resolved yet, no task
+	WorkflowStepStatusActive    = "active"    // task created, not finished
+	WorkflowStepStatusCompleted = "completed" // task completed
+	WorkflowStepStatusFailed    = "failed"    // task failed
+	WorkflowStepStatusCancelled = "cancelled" // task or run cancelled
+	WorkflowStepStatusSkipped   = "skipped"   // condition false or all dependencies skipped
+)
+
+// Workflow trigger constants.
+const (
+	WorkflowTriggerManual = "manual" // RPC / dashboard
+	WorkflowTriggerCron   = "cron"
+	WorkflowTriggerWake   = "wake" // POST /v1/agents/{id}/wake
+)
+
+// WorkflowData is a declarative multi-agent workflow bound to a team.
+// Definition holds the normalized JSON form of the workflow (see internal/workflow).
+type WorkflowData struct {
+	BaseModel
+	TeamID      uuid.UUID       `json:"team_id"`
+	TenantID    uuid.UUID       `json:"tenant_id,omitempty"`
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Definition  json.RawMessage `json:"definition"`
+	Enabled     bool            `json:"enabled"`
+	CreatedBy   string          `json:"created_by"`
+}
+
+// WorkflowStepState tracks one step of a workflow run.
+type WorkflowStepState struct {
+	Status     string     `json:"status"`
+	TaskID     *uuid.UUID `json:"task_id,omitempty"`
+	SpanID     *uuid.UUID `json:"span_id,omitempty"`
+	Output     string     `json:"output,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// WorkflowRunData is one execution of a workflow. Definition is a snapshot taken
+// at start so editing the workflow does not affect in-flight runs.
+type WorkflowRunData struct {
+	BaseModel
+	WorkflowID  uuid.UUID                     `json:"workflow_id"`
+	TeamID      uuid.UUID                     `json:"team_id"`
+	TenantID    uuid.UUID                     `json:"tenant_id,omitempty"`
+	Status      string                        `json:"status"`
+	Trigger     string                        `json:"trigger"`
+	TriggeredBy string                        `json:"triggered_by,omitempty"`
+	Input       map[string]string             `json:"input,omitempty"`
+	Definition  json.RawMessage               `json:"definition"`
+	Steps       map[string]*WorkflowStepState `json:"steps"`
+	Output      string                        `json:"output,omitempty"`
+	Error       string                        `json:"error,omitempty"`
+	TraceID     *uuid.UUID                    `json:"trace_id,omitempty"`
+	StartedAt   time.Time                     `json:"started_at"`
+	FinishedAt  *time.Time                    `json:"finished_at,omitempty"`
+
+	// Joined
+	WorkflowName string `json:"workflow_name,omitempty"`
+}
+
+// WorkflowStore manages workflow definitions and their runs.
+type WorkflowStore interface {
+	CreateWorkflow(ctx context.Context, wf *WorkflowData) error
+	GetWorkflow(ctx context.Context, id uuid.UUID) (*WorkflowData, error)
+	GetWorkflowByName(ctx context.Context, teamID uuid.UUID, name string) (*WorkflowData, error)
+	ListWorkflows(ctx context.Context, teamID uuid.UUID) ([]WorkflowData, error)
+	// UpdateWorkflow writes name, description, definition and enabled.
+	UpdateWorkflow(ctx context.Context, wf *WorkflowData) error
+	DeleteWorkflow(ctx context.Context, id, teamID uuid.UUID) error
+
+	CreateWorkflowRun(ctx context.Context, run *WorkflowRunData) error
+	GetWorkflowRun(ctx context.Context, id uuid.UUID) (*WorkflowRunData, error)
+	// ListWorkflowRuns returns runs newest first. workflowID = uuid.Nil lists all runs of the team.
+	ListWorkflowRuns(ctx context.Context, teamID, workflowID uuid.UUID, limit int) ([]WorkflowRunData, error)
+	// UpdateWorkflowRun writes status, steps, output, error, trace_id and finished_at.
+	UpdateWorkflowRun(ctx context.Context, run *WorkflowRunData) error
+	// LockWorkflowRun loads a run under an exclusive lock held across all
+	// replicas (a row lock on Postgres), calls fn with it and, if fn returns
+	// nil, writes it back like UpdateWorkflowRun before releasing the lock.
+	// Concurrent reconciles of one run are serialized, so the side effects fn
+	// performs (creating step tasks) happen once per state transition.
+	LockWorkflowRun(ctx context.Context, id uuid.UUID, fn func(run *WorkflowRunData) error) (*WorkflowRunData, error)
+	// ListActiveWorkflowRuns returns running runs across all tenants (for reconciliation).
+	ListActiveWorkflowRuns(ctx context.Context) ([]WorkflowRunData, error)
+}
@@ -33,6 +33,12 @@ type TaskTicker struct {
 	msgBus   *bus.MessageBus
 	interval time.Duration
 
+	// dispatcher starts pending tasks created by recurring templates (optional).
+	dispatcher tools.PostTurnProcessor
+
+	// workflows advances workflow runs whose step events were missed (optional).
+	workflows WorkflowReconciler
+
 	stopCh chan struct{}
 	wg     sync.WaitGroup
 
@@ -55,8 +61,26 @@ func NewTaskTicker(teams store.TeamStore, agents store.AgentStore, msgBus *bus.M
 	}
 }
 
+// WorkflowReconciler advances running workflow runs from their tasks' state.
+type WorkflowReconciler interface {
+	ReconcileActiveRuns(ctx context.Context)
+}
+
+// SetWorkflowReconciler wires the workflow engine so runs keep moving even
+// when a task completion event was lost (restart, cluster relay).
+func (t *TaskTicker) SetWorkflowReconciler(w WorkflowReconciler) {
+	t.workflows = w
+}
+
+// SetDispatcher wires the dispatcher used to start tasks created from
+// recurring templates. Without it those tasks stay pending until the lead acts.
+func (t *TaskTicker) SetDispatcher(d tools.PostTurnProcessor) {
+	t.dispatcher = d
+}
+
 // Start launches the background recovery loop.
 func (t *TaskTicker) Start() {
+	t.stopCh = make(chan struct{}) // fresh per start so a stopped ticker can restart
 	t.wg.Add(1)
 	go t.loop()
 	slog.Info("task ticker started", "interval", t.interval)
@@ -98,6 +122,23 @@ func (t *TaskTicker) recoverAll(forceRecover bool) {
 	t.processFollowups(followupCtx)
 	followupCancel()
 
+	// Step 1b: Escalate tasks past their due date (lead + human side).
+	overdueCtx, overdueCancel := context.WithTimeout(context.Background(), 15*time.Second)
+	t.processOverdue(overdueCtx)
+	overdueCancel()
+
+	// Step 1c: Instantiate recurring task templates whose schedule is due.
+	templateCtx, templateCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.processTemplates(templateCtx)
+	templateCancel()
+
+	// Step 1d: Advance workflow runs whose step tasks finished without an event.
+	if t.workflows != nil {
+		workflowCtx, workflowCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		t.workflows.ReconcileActiveRuns(workflowCtx)
+		workflowCancel()
+	}
+
 	// Step 2: Batch recovery — single query across all v2 active teams.
 	// Separate timeout so followup duration doesn't eat into recovery budget.
 	recoverCtx, recoverCancel := context.WithTimeout(context.Background(), 30*time.Second)
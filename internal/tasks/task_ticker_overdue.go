@@ -0,0 +1,148 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// ============================================================
+// Overdue escalation (batch)
+// ============================================================
+
+// processOverdue escalates tasks past their due date: the lead agent gets a
+// batched system message, the human side gets a reminder on the task's
+// follow-up channel (or origin chat), and a team.task.overdue event is
+// broadcast. Each task is re-escalated every escalation_interval_minutes
+// until max_escalations is reached.
+func (t *TaskTicker) processOverdue(ctx context.Context) {
+	overdue, err := t.teams.ListAllOverdueTasks(ctx)
+	if err != nil {
+		slog.Warn("task_ticker: list overdue tasks", "error", err)
+		return
+	}
+	if len(overdue) == 0 {
+		return
+	}
+
+	// Group by team: SLA settings and lead notifications are per team.
+	byTeam := map[uuid.UUID][]store.RecoveredTaskInfo{}
+	for _, info := range overdue {
+		byTeam[info.TeamID] = append(byTeam[info.TeamID], info)
+	}
+	for teamID, infos := range byTeam {
+		tctx := store.WithTenantID(ctx, infos[0].TenantID)
+		team, err := t.teams.GetTeam(tctx, teamID)
+		if err != nil || team == nil {
+			continue
+		}
+		t.escalateTeamOverdue(tctx, team, infos)
+	}
+}
+
+// escalateTeamOverdue handles one team's overdue tasks. ctx carries the team's tenant.
+func (t *TaskTicker) escalateTeamOverdue(ctx context.Context, team *store.TeamData, infos []store.RecoveredTaskInfo) {
+	cfg := tools.ParseSLAConfig(team.Settings)
+	now := time.Now()
+
+	var escalated []store.RecoveredTaskInfo
+	for _, info := range infos {
+		task, err := t.teams.GetTask(ctx, info.ID)
+		if err != nil || task == nil || task.DueAt == nil {
+			continue
+		}
+		level := task.EscalationCount + 1
+		if err := t.teams.MarkTaskEscalated(ctx, task.ID, cfg.NextEscalation(level, now)); err != nil {
+			slog.Warn("task_ticker: mark task escalated", "task_id", task.ID, "error", err)
+			continue
+		}
+
+		late := now.Sub(*task.DueAt).Round(time.Minute)
+		info.Subject = fmt.Sprintf("%s (due %s, %s late, escalation %s)",
+			task.Subject, task.DueAt.UTC().Format(time.RFC3339), late, escalationLabel(level, cfg.MaxEscalations))
+		escalated = append(escalated, info)
+
+		if cfg.NotifyAssignee {
+			t.notifyOverdueAssignee(task, level, cfg.MaxEscalations, late)
+		}
+
+		if t.msgBus != nil {
+			bus.BroadcastForTenant(t.msgBus, protocol.EventTeamTaskOverdue, info.TenantID, tools.BuildTaskEventPayload(
+				team.ID.String(), task.ID.String(),
+				task.Status,
+				"system", "task_ticker",
+				tools.WithTaskInfo(task.TaskNumber, task.Subject),
+				tools.WithOwnerAgentKey(task.OwnerAgentKey),
+				tools.WithUserID(task.UserID),
+				tools.WithChannel(task.Channel),
+				tools.WithChatID(task.ChatID),
+				tools.WithOverdue(*task.DueAt, level),
+			))
+		}
+
+		slog.Info("task_ticker: escalated overdue task",
+			"task_id", task.ID,
+			"task_number", task.TaskNumber,
+			"level", level,
+			"team_id", team.ID,
+		)
+	}
+
+	if len(escalated) > 0 {
+		t.notifyLeaders(ctx, escalated, "past their due date",
+			"Check with the assignee and either unblock, re-assign, or re-plan.\n"+
+				"To see details: use team_tasks(action=\"get\", task_id=\"<task_id>\").\n"+
+				"To move the deadline: use team_tasks(action=\"update\", task_id=\"<task_id>\", due_at=\"4h\").\n"+
+				"To re-dispatch: use team_tasks(action=\"retry\", task_id=\"<task_id>\").\n"+
+				"To cancel: use team_tasks(action=\"update\", task_id=\"<task_id>\", status=\"cancelled\").")
+	}
+}
+
+// notifyOverdueAssignee sends the overdue reminder to the human side of the task:
+// the follow-up channel set by ask_user, falling back to the chat the task came from.
+func (t *TaskTicker) notifyOverdueAssignee(task *store.TeamTaskData, level, maxLevel int, late time.Duration) {
+	if t.msgBus == nil {
+		return
+	}
+	channel, chatID := task.FollowupChannel, task.FollowupChatID
+	if channel == "" || chatID == "" {
+		channel, chatID = task.Channel, task.ChatID
+	}
+	switch channel {
+	case "", "system", "teammate", "dashboard":
+		return // no human-facing chat to notify
+	}
+	if chatID == "" {
+		return
+	}
+
+	content := fmt.Sprintf("Overdue (%s): task #%d \"%s\" was due %s (%s ago).",
+		escalationLabel(level, maxLevel), task.TaskNumber, task.Subject,
+		task.DueAt.UTC().Format("2006-01-02 15:04 UTC"), late)
+	if task.AssigneeUserID != "" {
+		content += " Assignee: " + task.AssigneeUserID + "."
+	}
+
+	if !t.msgBus.TryPublishOutbound(bus.OutboundMessage{
+		Channel: channel,
+		ChatID:  chatID,
+		Content: content,
+	}) {
+		slog.Warn("task_ticker: outbound buffer full, skipping overdue reminder", "task_id", task.ID)
+	}
+}
+
+func escalationLabel(level, maxLevel int) string {
+	if maxLevel > 0 {
+		return fmt.Sprintf("%d/%d", level, maxLevel)
+	}
+	return fmt.Sprintf("%d", level)
+}
@@ -0,0 +1,105 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// ============================================================
+// Recurring task templates (batch)
+// ============================================================
+
+// processTemplates instantiates task templates whose cron schedule is due.
+// The schedule is advanced before instantiation so a failing template does
+// not re-fire on every tick; runs missed while the gateway was down are
+// skipped (next run is computed from now, not from the missed tick).
+func (t *TaskTicker) processTemplates(ctx context.Context) {
+	now := time.Now()
+	due, err := t.teams.ListDueTaskTemplates(ctx, now)
+	if err != nil {
+		slog.Warn("task_ticker: list due task templates", "error", err)
+		return
+	}
+	for i := range due {
+		tpl := &due[i]
+		tctx := store.WithTenantID(ctx, tpl.TenantID)
+
+		next := *tpl
+		if err := tools.ScheduleTaskTemplate(&next, now); err != nil {
+			slog.Warn("task_ticker: template schedule invalid, disabling recurrence", "template_id", tpl.ID, "error", err)
+			next.NextRunAt = nil
+		}
+		if err := t.teams.MarkTaskTemplateRun(tctx, tpl.ID, now, next.NextRunAt); err != nil {
+			slog.Warn("task_ticker: mark template run", "template_id", tpl.ID, "error", err)
+			continue
+		}
+
+		team, err := t.teams.GetTeam(tctx, tpl.TeamID)
+		if err != nil || team == nil {
+			continue
+		}
+		t.runTemplate(tctx, team, tpl, now)
+	}
+}
+
+// runTemplate creates one scheduled run of a template, dispatches its
+// unblocked tasks and tells the lead. ctx carries the team's tenant.
+func (t *TaskTicker) runTemplate(ctx context.Context, team *store.TeamData, tpl *store.TeamTaskTemplateData, now time.Time) {
+	created, err := tools.InstantiateTaskTemplate(ctx, t.teams, team, tpl, tools.TemplateRunOptions{
+		UserID:  team.CreatedBy,
+		Channel: "dashboard",
+		ChatID:  team.ID.String(),
+		Now:     now,
+	})
+	if err != nil {
+		slog.Warn("task_ticker: instantiate template", "template_id", tpl.ID, "created", len(created), "error", err)
+	}
+	if len(created) == 0 {
+		return
+	}
+	slog.Info("task_ticker: instantiated recurring template",
+		"template_id", tpl.ID,
+		"name", tpl.Name,
+		"tasks", len(created),
+		"team_id", team.ID,
+	)
+
+	infos := make([]store.RecoveredTaskInfo, 0, len(created))
+	for _, task := range created {
+		infos = append(infos, store.RecoveredTaskInfo{
+			ID:         task.ID,
+			TeamID:     team.ID,
+			TenantID:   tpl.TenantID,
+			TaskNumber: task.TaskNumber,
+			Subject:    task.Subject,
+			Channel:    task.Channel,
+			ChatID:     task.ChatID,
+		})
+		if t.msgBus != nil {
+			bus.BroadcastForTenant(t.msgBus, protocol.EventTeamTaskCreated, tpl.TenantID, tools.BuildTaskEventPayload(
+				team.ID.String(), task.ID.String(),
+				task.Status,
+				"system", "task_template",
+				tools.WithTaskInfo(task.TaskNumber, task.Subject),
+				tools.WithChannel(task.Channel),
+				tools.WithChatID(task.ChatID),
+			))
+		}
+	}
+
+	if t.dispatcher != nil {
+		t.dispatcher.DispatchUnblockedTasks(ctx, team.ID)
+	}
+
+	t.notifyLeaders(ctx, infos, fmt.Sprintf("created from recurring template %q", tpl.Name),
+		"Unblocked tasks were dispatched to their assignees; blocked ones start when their blockers complete.\n"+
+			"To view all tasks: use team_tasks(action=\"list\").\n"+
+			"To change the recurrence: use team_tasks(action=\"template_update\", template_id=\""+tpl.ID.String()+"\", schedule=\"...\").")
+}
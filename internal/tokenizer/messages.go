@@ -0,0 +1,117 @@
+package tokenizer
+
+import (
+	"encoding/json"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+// Framing overhead, from OpenAI's chat format (role markers and separators
+// around each message). Other providers frame messages differently but within
+// a few tokens, so the same constants are used everywhere.
+const (
+	messageOverhead  = 3
+	replyPriming     = 3 // every reply is primed with <|start|>assistant<|message|>
+	toolCallOverhead = 3
+	toolDefOverhead  = 8
+	// imageTokens is charged per attached image. Providers bill images by
+	// resolution; this is Anthropic's ceiling for a ~1.15 MP image, and
+	// images are downscaled to about that size before sending.
+	imageTokens = 1600
+)
+
+// orDefault lets callers pass a nil Tokenizer.
+func orDefault(t Tokenizer) Tokenizer {
+	if t == nil {
+		return Default()
+	}
+	return t
+}
+
+// CountMessage returns the tokens a message contributes to a prompt:
+// content, tool calls (name + JSON arguments), attached images and framing.
+// A nil tokenizer uses Default.
+func CountMessage(t Tokenizer, m providers.Message) int {
+	t = orDefault(t)
+	n := messageOverhead + t.Count(m.Content)
+	for _, tc := range m.ToolCalls {
+		n += toolCallOverhead + t.Count(tc.ID) + t.Count(tc.Name)
+		if len(tc.Arguments) > 0 {
+			args, _ := json.Marshal(tc.Arguments)
+			n += t.Count(string(args))
+		}
+	}
+	if m.ToolCallID != "" {
+		n += t.Count(m.ToolCallID)
+	}
+	n += len(m.Images) * imageTokens
+	return n
+}
+
+// CountMessages sums CountMessage over msgs (no reply priming).
+func CountMessages(t Tokenizer, msgs []providers.Message) int {
+	n := 0
+	for _, m := range msgs {
+		n += CountMessage(t, m)
+	}
+	return n
+}
+
+// CountCalibrated estimates a conversation's prompt tokens from the actual
+// prompt-token count of the last LLM call (taken when the conversation had
+// lastMsgCount messages), counting only the messages added since. Without
+// calibration data it falls back to CountMessages.
+func CountCalibrated(t Tokenizer, msgs []providers.Message, lastPromptTokens, lastMsgCount int) int {
+	if lastPromptTokens <= 0 || lastMsgCount <= 0 {
+		return CountMessages(t, msgs)
+	}
+	if len(msgs) <= lastMsgCount {
+		// No new messages since calibration (or history was truncated).
+		return lastPromptTokens
+	}
+	return lastPromptTokens + CountMessages(t, msgs[lastMsgCount:])
+}
+
+// CountTools returns the tokens used by tool definitions (name, description
+// and JSON parameter schema of each).
+func CountTools(t Tokenizer, defs []providers.ToolDefinition) int {
+	t = orDefault(t)
+	n := 0
+	for _, d := range defs {
+		n += toolDefOverhead + t.Count(d.Function.Name) + t.Count(d.Function.Description)
+		if len(d.Function.Parameters) > 0 {
+			params, _ := json.Marshal(d.Function.Parameters)
+			n += t.Count(string(params))
+		}
+	}
+	return n
+}
+
+// PromptCount is a token breakdown of a complete prompt.
+type PromptCount struct {
+	Tokenizer string `json:"tokenizer"`
+	System    int    `json:"system"`   // system messages
+	History   int    `json:"history"`  // all other messages, including the new one
+	Tools     int    `json:"tools"`    // tool definitions
+	Total     int    `json:"total"`    // System + History + Tools + reply priming
+	Messages  int    `json:"messages"` // message count
+}
+
+// CountPrompt counts a full request: messages plus tool definitions.
+func CountPrompt(t Tokenizer, msgs []providers.Message, tools []providers.ToolDefinition) PromptCount {
+	t = orDefault(t)
+	pc := PromptCount{Tokenizer: t.Name(), Messages: len(msgs)}
+	for _, m := range msgs {
+		if m.Role == "system" {
+			pc.System += CountMessage(t, m)
+		} else {
+			pc.History += CountMessage(t, m)
+		}
+	}
+	pc.Tools = CountTools(t, tools)
+	pc.Total = pc.System + pc.History + pc.Tools
+	if len(msgs) > 0 {
+		pc.Total += replyPriming
+	}
+	return pc
+}
@@ -0,0 +1,206 @@
+// Package tokenizer counts tokens with the BPE vocabulary of the target model,
+// so context budgeting (pruning, compaction, adaptive throttle) works from
+// real token counts instead of bytes/4 heuristics.
+//
+// OpenAI models use their published encodings (o200k_base, cl100k_base).
+// Anthropic does not publish the Claude tokenizer; Claude models get a
+// cl100k-based approximation scaled to Claude's typically higher counts.
+// Other families (Qwen, DeepSeek, Gemini, Llama, ...) use cl100k, which is
+// close for mixed English/code text.
+package tokenizer
+
+import (
+	"hash/maphash"
+	"log/slog"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// Tokenizer names.
+const (
+	NameO200k     = "o200k_base"
+	NameCL100k    = "cl100k_base"
+	NameClaude    = "claude"
+	NameHeuristic = "heuristic"
+)
+
+// claudeScale converts cl100k counts to Claude 3+ counts. Measured Claude
+// counts run ~10–20% above cl100k for English prose and code; the upper-middle
+// value keeps budget checks on the safe side.
+const claudeScale = 1.15
+
+// Tokenizer counts the tokens of a string.
+type Tokenizer interface {
+	Name() string
+	Count(text string) int
+}
+
+var (
+	o200k     = &bpe{name: NameO200k}
+	cl100k    = &bpe{name: NameCL100k}
+	claude    = scaled{name: NameClaude, base: cl100k, factor: claudeScale}
+	heuristic = heuristicTokenizer{}
+)
+
+// Default is the tokenizer used when the model is unknown.
+func Default() Tokenizer { return cl100k }
+
+// Get returns the tokenizer with the given name, or Default for unknown names.
+func Get(name string) Tokenizer {
+	switch name {
+	case NameO200k:
+		return o200k
+	case NameClaude:
+		return claude
+	case NameHeuristic:
+		return heuristic
+	}
+	return cl100k
+}
+
+// ForModel picks the tokenizer for a provider/model pair. The model name
+// decides; the provider only matters when the model is empty (Anthropic and
+// Claude CLI providers default to Claude models).
+func ForModel(provider, model string) Tokenizer {
+	m := strings.ToLower(model)
+	if i := strings.LastIndex(m, "/"); i >= 0 {
+		m = m[i+1:] // "anthropic/claude-sonnet-4", "openai/gpt-4o"
+	}
+	p := strings.ToLower(provider)
+	switch {
+	case strings.Contains(m, "claude"), m == "sonnet", m == "opus", m == "haiku":
+		return claude
+	case m == "" && (strings.Contains(p, "anthropic") || strings.Contains(p, "claude")):
+		return claude
+	case hasAnyPrefix(m, "gpt-4o", "gpt-4.1", "gpt-4.5", "gpt-5", "chatgpt-4o", "gpt-oss", "o1", "o3", "o4", "codex"):
+		return o200k
+	case m == "" && strings.Contains(p, "codex"):
+		return o200k
+	}
+	return cl100k
+}
+
+func hasAnyPrefix(s string, prefixes ...string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// bpe is a tiktoken encoding, loaded on first use (a vocabulary costs tens of
+// MB of memory, so unused ones are never loaded).
+type bpe struct {
+	name string
+	once sync.Once
+	enc  *tiktoken.Tiktoken
+}
+
+func (b *bpe) Name() string { return b.name }
+
+func (b *bpe) Count(text string) int {
+	if text == "" {
+		return 0
+	}
+	enc := b.encoding()
+	if enc == nil {
+		return heuristic.Count(text)
+	}
+	return counts.get(b.name, text, func() int {
+		return len(enc.EncodeOrdinary(text))
+	})
+}
+
+func (b *bpe) encoding() *tiktoken.Tiktoken {
+	b.once.Do(func() {
+		enc, err := tiktoken.GetEncoding(b.name)
+		if err != nil {
+			slog.Warn("tokenizer: failed to load encoding, using heuristic counts", "encoding", b.name, "error", err)
+			return
+		}
+		b.enc = enc
+	})
+	return b.enc
+}
+
+// scaled approximates a tokenizer that isn't available by scaling another.
+type scaled struct {
+	name   string
+	base   Tokenizer
+	factor float64
+}
+
+func (s scaled) Name() string { return s.name }
+
+func (s scaled) Count(text string) int {
+	n := s.base.Count(text)
+	if n == 0 {
+		return 0
+	}
+	return int(float64(n)*s.factor + 0.5)
+}
+
+// heuristicTokenizer is the fallback when a vocabulary can't be loaded:
+// ~3 runes per token, which over-counts English slightly and under-counts CJK.
+type heuristicTokenizer struct{}
+
+func (heuristicTokenizer) Name() string { return NameHeuristic }
+
+func (heuristicTokenizer) Count(text string) int {
+	return (utf8.RuneCountInString(text) + 2) / 3
+}
+
+// Count caches. Pruning and compaction re-count the same history every
+// iteration; BPE-encoding it each time would dominate the loop. Strings
+// shorter than minCachedLen are cheaper to encode than to hash and look up.
+const (
+	minCachedLen    = 256
+	maxCacheEntries = 8192
+)
+
+var counts = &countCache{seed: maphash.MakeSeed()}
+
+type countKey struct {
+	encoding string
+	hash     uint64
+	length   int
+}
+
+// countCache is a two-generation cache: when the current generation fills up
+// it becomes the previous one, so entries used in the last cycle survive.
+type countCache struct {
+	seed maphash.Seed
+	mu   sync.Mutex
+	cur  map[countKey]int
+	prev map[countKey]int
+}
+
+func (c *countCache) get(encoding, text string, compute func() int) int {
+	if len(text) < minCachedLen {
+		return compute()
+	}
+	key := countKey{encoding: encoding, hash: maphash.String(c.seed, text), length: len(text)}
+
+	c.mu.Lock()
+	if n, ok := c.cur[key]; ok {
+		c.mu.Unlock()
+		return n
+	}
+	n, ok := c.prev[key]
+	c.mu.Unlock()
+	if !ok {
+		n = compute()
+	}
+
+	c.mu.Lock()
+	if c.cur == nil || len(c.cur) >= maxCacheEntries {
+		c.prev, c.cur = c.cur, make(map[countKey]int, maxCacheEntries/4)
+	}
+	c.cur[key] = n
+	c.mu.Unlock()
+	return n
+}
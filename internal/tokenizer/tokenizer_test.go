@@ -0,0 +1,135 @@
+package tokenizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/providers"
+)
+
+func TestBPECounts(t *testing.T) {
+	// Reference counts from OpenAI's tiktoken.
+	tests := []struct {
+		tok  Tokenizer
+		text string
+		want int
+	}{
+		{cl100k, "hello world", 2},
+		{cl100k, "tiktoken is great!", 6},
+		{o200k, "hello world", 2},
+	}
+	for _, tt := range tests {
+		if got := tt.tok.Count(tt.text); got != tt.want {
+			t.Errorf("%s.Count(%q) = %d, want %d", tt.tok.Name(), tt.text, got, tt.want)
+		}
+	}
+	if got := o200k.encoding().EncodeOrdinary("hello world"); len(got) != 2 || got[0] != 24912 || got[1] != 2375 {
+		t.Errorf("o200k ids = %v, want [24912 2375]", got)
+	}
+	if got := cl100k.Count(""); got != 0 {
+		t.Errorf("empty string = %d tokens, want 0", got)
+	}
+}
+
+func TestForModel(t *testing.T) {
+	tests := []struct {
+		provider, model, want string
+	}{
+		{"anthropic", "claude-sonnet-4-5", NameClaude},
+		{"openrouter", "anthropic/claude-3.5-haiku", NameClaude},
+		{"claude-cli", "sonnet", NameClaude},
+		{"anthropic", "", NameClaude},
+		{"openai", "gpt-4o-mini", NameO200k},
+		{"openai", "gpt-5", NameO200k},
+		{"openai", "o3-mini", NameO200k},
+		{"openai-codex", "gpt-5.3-codex", NameO200k},
+		{"openai", "gpt-4-turbo", NameCL100k},
+		{"dashscope", "qwen3-max", NameCL100k},
+		{"", "", NameCL100k},
+	}
+	for _, tt := range tests {
+		if got := ForModel(tt.provider, tt.model).Name(); got != tt.want {
+			t.Errorf("ForModel(%q, %q) = %s, want %s", tt.provider, tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestClaudeApproximationScalesCL100k(t *testing.T) {
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 40)
+	base := cl100k.Count(text)
+	got := claude.Count(text)
+	if got <= base {
+		t.Errorf("claude count %d should exceed cl100k count %d", got, base)
+	}
+}
+
+func TestCountCacheConsistent(t *testing.T) {
+	text := strings.Repeat("func main() { fmt.Println(\"hi\") }\n", 50)
+	first := o200k.Count(text)
+	if second := o200k.Count(text); second != first {
+		t.Errorf("cached count %d != first count %d", second, first)
+	}
+	if cl := cl100k.Count(text); cl == 0 {
+		t.Error("cache must be keyed per encoding")
+	}
+}
+
+func TestCountPrompt(t *testing.T) {
+	msgs := []providers.Message{
+		{Role: "system", Content: "You are helpful."},
+		{Role: "user", Content: "Hello"},
+		{Role: "assistant", Content: "", ToolCalls: []providers.ToolCall{
+			{ID: "call_1", Name: "web_search", Arguments: map[string]any{"query": "weather"}},
+		}},
+		{Role: "tool", Content: "Sunny", ToolCallID: "call_1"},
+	}
+	tools := []providers.ToolDefinition{{Type: "function", Function: providers.ToolFunctionSchema{
+		Name: "web_search", Description: "Search the web",
+		Parameters: map[string]any{"type": "object", "properties": map[string]any{"query": map[string]any{"type": "string"}}},
+	}}}
+
+	pc := CountPrompt(nil, msgs, tools)
+	if pc.Tokenizer != NameCL100k {
+		t.Errorf("tokenizer = %s, want default", pc.Tokenizer)
+	}
+	if pc.System != CountMessage(nil, msgs[0]) {
+		t.Errorf("system = %d, want %d", pc.System, CountMessage(nil, msgs[0]))
+	}
+	if pc.History != CountMessages(nil, msgs[1:]) {
+		t.Errorf("history = %d, want %d", pc.History, CountMessages(nil, msgs[1:]))
+	}
+	if pc.Tools == 0 || pc.Total != pc.System+pc.History+pc.Tools+replyPriming {
+		t.Errorf("breakdown = %+v", pc)
+	}
+
+	withImage := providers.Message{Role: "user", Content: "Hello", Images: []providers.ImageContent{{MimeType: "image/png"}}}
+	if got := CountMessage(nil, withImage) - CountMessage(nil, msgs[1]); got != imageTokens {
+		t.Errorf("image adds %d tokens, want %d", got, imageTokens)
+	}
+}
+
+func TestCountCalibrated(t *testing.T) {
+	msgs := []providers.Message{
+		{Role: "user", Content: "Hello"},
+		{Role: "assistant", Content: "Hi there"},
+		{Role: "user", Content: "What's the weather?"},
+	}
+	if got, want := CountCalibrated(nil, msgs, 0, 0), CountMessages(nil, msgs); got != want {
+		t.Errorf("uncalibrated = %d, want %d", got, want)
+	}
+	if got, want := CountCalibrated(nil, msgs, 5000, 2), 5000+CountMessage(nil, msgs[2]); got != want {
+		t.Errorf("calibrated = %d, want %d", got, want)
+	}
+	if got := CountCalibrated(nil, msgs[:1], 5000, 2); got != 5000 {
+		t.Errorf("truncated history = %d, want calibration value", got)
+	}
+}
+
+func BenchmarkCountUncached(b *testing.B) {
+	text := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit. ", 2000)
+	o200k.Count("warm up")
+	b.SetBytes(int64(len(text)))
+	for b.Loop() {
+		_ = o200k.encoding().EncodeOrdinary(text)
+	}
+}
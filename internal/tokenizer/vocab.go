@@ -0,0 +1,60 @@
+package tokenizer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"embed"
+	"encoding/base64"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// Only the two encodings current models use are embedded (gzipped, ~2.4 MB)
+// so tokenization never depends on downloading vocabularies at runtime.
+//
+//go:embed vocab/*.tiktoken.gz
+var vocabFS embed.FS
+
+func init() {
+	tiktoken.SetBpeLoader(embeddedLoader{})
+}
+
+// embeddedLoader serves tiktoken's BPE rank files from vocabFS. tiktoken asks
+// for the upstream URL; only the base name is used.
+type embeddedLoader struct{}
+
+func (embeddedLoader) LoadTiktokenBpe(file string) (map[string]int, error) {
+	f, err := vocabFS.Open("vocab/" + path.Base(file) + ".gz")
+	if err != nil {
+		return nil, fmt.Errorf("tokenizer: vocabulary %s not embedded: %w", path.Base(file), err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	ranks := make(map[string]int, 200_000)
+	sc := bufio.NewScanner(zr)
+	for sc.Scan() {
+		tok, rank, ok := strings.Cut(sc.Text(), " ")
+		if !ok {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(tok)
+		if err != nil {
+			return nil, fmt.Errorf("tokenizer: %s: %w", path.Base(file), err)
+		}
+		r, err := strconv.Atoi(rank)
+		if err != nil {
+			return nil, fmt.Errorf("tokenizer: %s: %w", path.Base(file), err)
+		}
+		ranks[string(b)] = r
+	}
+	return ranks, sc.Err()
+}
@@ -0,0 +1,739 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nextlevelbuilder/goclaw/internal/sandbox"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+const (
+	patchMaxConflictsShown = 20
+	patchMaxExpectedShown  = 8       // expected lines quoted per conflicting hunk
+	patchMaxClosestWork    = 4000000 // line comparisons allowed for closest-match search
+)
+
+// ApplyPatchTool applies multi-hunk, multi-file patches. It accepts unified
+// diffs and the "*** Begin Patch" envelope, validates every hunk before
+// writing anything, and rolls back on write failure so a patch lands whole
+// or not at all. Supports the same interceptors and sandbox routing as edit.
+type ApplyPatchTool struct {
+	workspace       string
+	restrict        bool
+	deniedPrefixes  []string // path prefixes to deny access to (e.g. .goclaw)
+	sandboxMgr      sandbox.Manager
+	contextFileIntc *ContextFileInterceptor     // nil = no virtual FS routing
+	memIntc         *MemoryInterceptor          // nil = no memory routing
+	permStore       store.ConfigPermissionStore // nil = no group write restriction
+	workspaceIntc   *WorkspaceInterceptor       // nil = no team workspace validation
+}
+
+// DenyPaths adds path prefixes that apply_patch must reject.
+func (t *ApplyPatchTool) DenyPaths(prefixes ...string) {
+	t.deniedPrefixes = append(t.deniedPrefixes, prefixes...)
+}
+
+// SetContextFileInterceptor enables virtual FS routing for context files.
+func (t *ApplyPatchTool) SetContextFileInterceptor(intc *ContextFileInterceptor) {
+	t.contextFileIntc = intc
+}
+
+// SetMemoryInterceptor enables virtual FS routing for memory files.
+func (t *ApplyPatchTool) SetMemoryInterceptor(intc *MemoryInterceptor) {
+	t.memIntc = intc
+}
+
+// SetConfigPermStore enables group write permission checks.
+func (t *ApplyPatchTool) SetConfigPermStore(s store.ConfigPermissionStore) {
+	t.permStore = s
+}
+
+// SetWorkspaceInterceptor enables team workspace validation and event broadcasting.
+func (t *ApplyPatchTool) SetWorkspaceInterceptor(intc *WorkspaceInterceptor) {
+	t.workspaceIntc = intc
+}
+
+func NewApplyPatchTool(workspace string, restrict bool) *ApplyPatchTool {
+	return &ApplyPatchTool{workspace: workspace, restrict: restrict}
+}
+
+func NewSandboxedApplyPatchTool(workspace string, restrict bool, mgr sandbox.Manager) *ApplyPatchTool {
+	return &ApplyPatchTool{workspace: workspace, restrict: restrict, sandboxMgr: mgr}
+}
+
+// SetSandboxKey is a no-op; sandbox key is now read from ctx (thread-safe).
+func (t *ApplyPatchTool) SetSandboxKey(key string) {}
+
+func (t *ApplyPatchTool) Name() string { return "apply_patch" }
+func (t *ApplyPatchTool) Description() string {
+	return "Apply a patch that edits, creates, deletes or renames several files in one call. " +
+		"Accepts a unified diff (---/+++/@@) or a \"*** Begin Patch\" block with *** Add File / *** Update File / *** Delete File / *** Move to sections " +
+		"and @@ hunks of ' ' context, '-' removed and '+' added lines. " +
+		"Every hunk must match or no file is changed; conflicts are reported per hunk. Use dry_run=true to check a patch without writing."
+}
+
+func (t *ApplyPatchTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"patch": map[string]any{
+				"type":        "string",
+				"description": "Patch text: a unified diff, or \"*** Begin Patch\" ... \"*** End Patch\". Include ~3 unchanged context lines around each change.",
+			},
+			"dry_run": map[string]any{
+				"type":        "boolean",
+				"description": "Validate the patch and report what would change without writing any file (default: false)",
+			},
+		},
+		"required": []string{"patch"},
+	}
+}
+
+func (t *ApplyPatchTool) Execute(ctx context.Context, args map[string]any) *Result {
+	patchText, _ := args["patch"].(string)
+	dryRun, _ := args["dry_run"].(bool)
+
+	if strings.TrimSpace(patchText) == "" {
+		return ErrorResult("patch is required")
+	}
+
+	// Group write permission check
+	if !dryRun && t.permStore != nil {
+		if err := store.CheckFileWriterPermission(ctx, t.permStore); err != nil {
+			return ErrorResult(err.Error())
+		}
+	}
+
+	patches, err := parsePatch(patchText)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("invalid patch: %v", err))
+	}
+
+	fsys, err := t.patchFS(ctx)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	changes, conflicts := planPatch(ctx, fsys, patches)
+	if len(conflicts) > 0 {
+		return ErrorResult(formatPatchConflicts(conflicts, dryRun))
+	}
+	if dryRun {
+		return SilentResult(fmt.Sprintf("Dry run: patch applies cleanly to %d file(s); nothing was written.\n%s",
+			len(changes), summarizePatchChanges(changes)))
+	}
+
+	if err := commitPatch(ctx, fsys, changes); err != nil {
+		return ErrorResult(fmt.Sprintf("failed to apply patch: %v", err) + MaybeFsBridgeHint(err))
+	}
+	return SilentResult(fmt.Sprintf("Patch applied: %d file(s) changed\n%s", len(changes), summarizePatchChanges(changes)))
+}
+
+// patchFS builds the file access layer for this call: sandbox container or
+// host workspace, with context/memory files routed to the DB.
+func (t *ApplyPatchTool) patchFS(ctx context.Context) (patchFS, error) {
+	var base patchFS
+	if sandboxKey := ToolSandboxKeyFromCtx(ctx); t.sandboxMgr != nil && sandboxKey != "" {
+		sb, err := t.sandboxMgr.Get(ctx, sandboxKey, t.workspace, SandboxConfigFromCtx(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("sandbox error: %v", err)
+		}
+		containerCwd, err := SandboxCwd(ctx, t.workspace, sandbox.DefaultContainerWorkdir)
+		if err != nil {
+			return nil, fmt.Errorf("sandbox path mapping: %v", err)
+		}
+		base = &sandboxPatchFS{
+			bridge: sandbox.NewFsBridge(sb.ID(), sandbox.DefaultContainerWorkdir),
+			cwd:    containerCwd,
+		}
+	} else {
+		workspace := ToolWorkspaceFromCtx(ctx)
+		if workspace == "" {
+			workspace = t.workspace
+		}
+		base = &hostPatchFS{
+			workspace:     workspace,
+			baseWorkspace: t.workspace,
+			restrict:      effectiveRestrict(ctx, t.restrict),
+			allowed:       allowedWithTeamWorkspace(ctx, nil),
+			denied:        t.deniedPrefixes,
+			workspaceIntc: t.workspaceIntc,
+		}
+	}
+	if t.contextFileIntc == nil && t.memIntc == nil {
+		return base, nil
+	}
+	return &virtualPatchFS{base: base, contextFileIntc: t.contextFileIntc, memIntc: t.memIntc}, nil
+}
+
+// patchFS is the file access apply_patch needs. read reports a missing file
+// as exists=false rather than an error.
+type patchFS interface {
+	read(ctx context.Context, path string) (content string, exists bool, err error)
+	write(ctx context.Context, path, content string) error
+	remove(ctx context.Context, path string) error
+}
+
+// hostPatchFS reads and writes the host workspace with the usual path rules.
+type hostPatchFS struct {
+	workspace     string // per-user workspace paths resolve against
+	baseWorkspace string // tool workspace deny prefixes are relative to
+	restrict      bool
+	allowed       []string
+	denied        []string
+	workspaceIntc *WorkspaceInterceptor
+}
+
+func (h *hostPatchFS) resolve(path string) (string, error) {
+	resolved, err := resolvePathWithAllowed(path, h.workspace, h.restrict, h.allowed)
+	if err != nil {
+		return "", err
+	}
+	if err := checkDeniedPath(resolved, h.baseWorkspace, h.denied); err != nil {
+		return "", err
+	}
+	return resolved, nil
+}
+
+func (h *hostPatchFS) read(_ context.Context, path string) (string, bool, error) {
+	resolved, err := h.resolve(path)
+	if err != nil {
+		return "", false, err
+	}
+	data, err := os.ReadFile(resolved)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read file: %v", err)
+	}
+	return string(data), true, nil
+}
+
+func (h *hostPatchFS) write(ctx context.Context, path, content string) error {
+	resolved, err := h.resolve(path)
+	if err != nil {
+		return err
+	}
+	if h.workspaceIntc != nil {
+		isDelete, err := h.workspaceIntc.HandleWrite(ctx, resolved, content)
+		if err != nil {
+			return err
+		}
+		if isDelete {
+			return h.removeResolved(ctx, resolved)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(resolved), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %v", err)
+	}
+	perm := os.FileMode(0644)
+	if fi, err := os.Stat(resolved); err == nil {
+		perm = fi.Mode().Perm()
+	}
+	if err := os.WriteFile(resolved, []byte(content), perm); err != nil {
+		return fmt.Errorf("failed to write file: %v", err)
+	}
+	if h.workspaceIntc != nil {
+		h.workspaceIntc.AfterWrite(ctx, resolved, "write")
+	}
+	return nil
+}
+
+func (h *hostPatchFS) remove(ctx context.Context, path string) error {
+	resolved, err := h.resolve(path)
+	if err != nil {
+		return err
+	}
+	if h.workspaceIntc != nil {
+		// Empty content asks the interceptor to authorize a delete.
+		if _, err := h.workspaceIntc.HandleWrite(ctx, resolved, ""); err != nil {
+			return err
+		}
+	}
+	return h.removeResolved(ctx, resolved)
+}
+
+func (h *hostPatchFS) removeResolved(ctx context.Context, resolved string) error {
+	if err := os.Remove(resolved); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %v", err)
+	}
+	if h.workspaceIntc != nil {
+		h.workspaceIntc.AfterWrite(ctx, resolved, "delete")
+	}
+	return nil
+}
+
+// sandboxPatchFS routes file access into the agent's sandbox container.
+type sandboxPatchFS struct {
+	bridge *sandbox.FsBridge
+	cwd    string
+}
+
+func (s *sandboxPatchFS) read(ctx context.Context, path string) (string, bool, error) {
+	content, err := s.bridge.ReadFile(ctx, ResolveSandboxPath(path, s.cwd))
+	if err != nil {
+		if strings.Contains(err.Error(), "No such file") {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to read file: %w", err)
+	}
+	return content, true, nil
+}
+
+func (s *sandboxPatchFS) write(ctx context.Context, path, content string) error {
+	return s.bridge.WriteFile(ctx, ResolveSandboxPath(path, s.cwd), content, false)
+}
+
+func (s *sandboxPatchFS) remove(ctx context.Context, path string) error {
+	return s.bridge.Remove(ctx, ResolveSandboxPath(path, s.cwd))
+}
+
+// virtualPatchFS sends context and memory files to their DB interceptors
+// and everything else to base.
+type virtualPatchFS struct {
+	base            patchFS
+	contextFileIntc *ContextFileInterceptor
+	memIntc         *MemoryInterceptor
+}
+
+func (v *virtualPatchFS) read(ctx context.Context, path string) (string, bool, error) {
+	if v.contextFileIntc != nil {
+		if content, handled, err := v.contextFileIntc.ReadFile(ctx, path); handled {
+			return content, content != "", err
+		}
+	}
+	if v.memIntc != nil {
+		if content, handled, err := v.memIntc.ReadFile(ctx, path); handled {
+			return content, content != "", err
+		}
+	}
+	return v.base.read(ctx, path)
+}
+
+func (v *virtualPatchFS) write(ctx context.Context, path, content string) error {
+	if v.contextFileIntc != nil {
+		if handled, err := v.contextFileIntc.WriteFile(ctx, path, content); handled {
+			return err
+		}
+	}
+	if v.memIntc != nil {
+		if mwr, err := v.memIntc.WriteFile(ctx, path, content, false); mwr.Handled {
+			return err
+		}
+	}
+	return v.base.write(ctx, path, content)
+}
+
+func (v *virtualPatchFS) remove(ctx context.Context, path string) error {
+	if v.contextFileIntc != nil {
+		if _, handled, _ := v.contextFileIntc.ReadFile(ctx, path); handled {
+			return fmt.Errorf("%s is a context file and cannot be deleted", path)
+		}
+	}
+	if v.memIntc != nil {
+		if _, handled, _ := v.memIntc.ReadFile(ctx, path); handled {
+			return fmt.Errorf("%s is a memory file and cannot be deleted", path)
+		}
+	}
+	return v.base.remove(ctx, path)
+}
+
+// patchChange is one validated file change, with enough state to undo it.
+type patchChange struct {
+	op      patchOp
+	path    string // file written (rename target for moves)
+	from    string // moves only: original path
+	before  string
+	after   string
+	added   int
+	removed int
+}
+
+type patchConflict struct {
+	path   string
+	detail string
+}
+
+// planPatch validates every file operation and hunk against current file
+// contents without writing. Later operations on the same path see the
+// result of earlier ones.
+func planPatch(ctx context.Context, fsys patchFS, patches []filePatch) ([]patchChange, []patchConflict) {
+	type pendingFile struct {
+		content string
+		exists  bool
+	}
+	pending := map[string]pendingFile{}
+	state := func(path string) (string, bool, error) {
+		if p, ok := pending[path]; ok {
+			return p.content, p.exists, nil
+		}
+		return fsys.read(ctx, path)
+	}
+
+	var (
+		changes   []patchChange
+		conflicts []patchConflict
+	)
+	for _, p := range patches {
+		if p.Path == "" {
+			conflicts = append(conflicts, patchConflict{path: "(patch)", detail: "file operation without a path"})
+			continue
+		}
+		content, exists, err := state(p.Path)
+		if err != nil {
+			conflicts = append(conflicts, patchConflict{path: p.Path, detail: err.Error()})
+			continue
+		}
+
+		switch p.Op {
+		case patchAdd:
+			if exists {
+				conflicts = append(conflicts, patchConflict{path: p.Path, detail: "file already exists (use an update instead of add)"})
+				continue
+			}
+			changes = append(changes, patchChange{op: patchAdd, path: p.Path, after: p.Content, added: countPatchLines(p.Content)})
+			pending[p.Path] = pendingFile{content: p.Content, exists: true}
+
+		case patchDelete:
+			if !exists {
+				conflicts = append(conflicts, patchConflict{path: p.Path, detail: "file to delete does not exist"})
+				continue
+			}
+			changes = append(changes, patchChange{op: patchDelete, path: p.Path, before: content, removed: countPatchLines(content)})
+			pending[p.Path] = pendingFile{}
+
+		case patchUpdate:
+			if !exists {
+				conflicts = append(conflicts, patchConflict{path: p.Path, detail: "file to update does not exist"})
+				continue
+			}
+			after, hunkConflicts := applyHunks(content, p.Hunks)
+			for _, c := range hunkConflicts {
+				conflicts = append(conflicts, patchConflict{path: p.Path, detail: c})
+			}
+			if len(hunkConflicts) > 0 {
+				continue
+			}
+			change := patchChange{op: patchUpdate, path: p.Path, before: content, after: after}
+			for i := range p.Hunks {
+				a, r := p.Hunks[i].counts()
+				change.added += a
+				change.removed += r
+			}
+			if p.MoveTo != "" && p.MoveTo != p.Path {
+				if _, dstExists, err := state(p.MoveTo); err != nil {
+					conflicts = append(conflicts, patchConflict{path: p.MoveTo, detail: err.Error()})
+					continue
+				} else if dstExists {
+					conflicts = append(conflicts, patchConflict{path: p.MoveTo, detail: "move target already exists"})
+					continue
+				}
+				change.from, change.path = p.Path, p.MoveTo
+				pending[p.Path] = pendingFile{}
+			}
+			changes = append(changes, change)
+			pending[change.path] = pendingFile{content: after, exists: true}
+		}
+	}
+	return changes, conflicts
+}
+
+// commitPatch writes the planned changes in order. If one fails, every
+// change up to and including it is reverted (best effort).
+func commitPatch(ctx context.Context, fsys patchFS, changes []patchChange) error {
+	for i, c := range changes {
+		err := applyPatchChange(ctx, fsys, c)
+		if err == nil {
+			continue
+		}
+		failed := 0
+		for j := i; j >= 0; j-- {
+			if revertPatchChange(ctx, fsys, changes[j]) != nil {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%s: %w (rollback incomplete: %d file(s) could not be restored)", c.path, err, failed)
+		}
+		return fmt.Errorf("%s: %w (earlier changes were rolled back)", c.path, err)
+	}
+	return nil
+}
+
+func applyPatchChange(ctx context.Context, fsys patchFS, c patchChange) error {
+	switch {
+	case c.op == patchDelete:
+		return fsys.remove(ctx, c.path)
+	case c.from != "":
+		if err := fsys.write(ctx, c.path, c.after); err != nil {
+			return err
+		}
+		return fsys.remove(ctx, c.from)
+	default:
+		return fsys.write(ctx, c.path, c.after)
+	}
+}
+
+func revertPatchChange(ctx context.Context, fsys patchFS, c patchChange) error {
+	switch {
+	case c.op == patchAdd:
+		return fsys.remove(ctx, c.path)
+	case c.from != "":
+		if err := fsys.write(ctx, c.from, c.before); err != nil {
+			return err
+		}
+		return fsys.remove(ctx, c.path)
+	default:
+		return fsys.write(ctx, c.path, c.before)
+	}
+}
+
+// applyHunks applies hunks to content in order. Context is matched exactly
+// first, then ignoring trailing whitespace, then ignoring surrounding
+// whitespace. Returns one message per hunk that could not be placed.
+func applyHunks(content string, hunks []patchHunk) (string, []string) {
+	crlf := strings.Contains(content, "\r\n")
+	if crlf {
+		content = strings.ReplaceAll(content, "\r\n", "\n")
+	}
+	addFinalNewline := content == "" || strings.HasSuffix(content, "\n")
+	var lines []string
+	if trimmed := strings.TrimSuffix(content, "\n"); content != "" {
+		lines = strings.Split(trimmed, "\n")
+	}
+
+	type replacement struct {
+		start, n int
+		with     []string
+	}
+	var (
+		repls     []replacement
+		conflicts []string
+		cursor    int
+	)
+	for i := range hunks {
+		h := &hunks[i]
+		label := fmt.Sprintf("hunk %d/%d", i+1, len(hunks))
+		if h.Header != "" && h.Header != "@@" {
+			label += " (" + h.Header + ")"
+		}
+
+		start := cursor
+		if h.Anchor != "" {
+			idx := seekPatchLines(lines, []string{h.Anchor}, cursor, -1, false)
+			if idx < 0 {
+				conflicts = append(conflicts, fmt.Sprintf("%s: anchor line %q not found", label, h.Anchor))
+				continue
+			}
+			start = idx + 1
+		}
+
+		old := h.oldLines()
+		var idx int
+		switch {
+		case len(old) > 0:
+			idx = seekPatchLines(lines, old, start, h.OldStart-1, h.EOF)
+			if idx < 0 {
+				conflicts = append(conflicts, label+": "+describePatchMismatch(lines, old))
+				continue
+			}
+		case h.OldStart > 0 || strings.HasPrefix(h.Header, "@@ -0"):
+			// Pure insertion in a unified diff: "-N,0" inserts after line N.
+			idx = min(h.OldStart, len(lines))
+		case h.Anchor != "":
+			idx = start
+		default:
+			idx = len(lines) // envelope insertion without context appends
+		}
+		if idx < cursor {
+			conflicts = append(conflicts, label+": overlaps the previous hunk")
+			continue
+		}
+
+		// Context lines keep the file's text so fuzzy matches don't rewrite
+		// whitespace outside the change.
+		with := make([]string, 0, len(h.Lines))
+		oi := idx
+		for _, l := range h.Lines {
+			switch l.Kind {
+			case ' ':
+				with = append(with, lines[oi])
+				oi++
+			case '-':
+				oi++
+			case '+':
+				with = append(with, l.Text)
+			}
+		}
+		repls = append(repls, replacement{start: idx, n: len(old), with: with})
+		cursor = idx + len(old)
+		if h.NoEOL {
+			addFinalNewline = false
+		}
+	}
+	if len(conflicts) > 0 {
+		return "", conflicts
+	}
+
+	for i := len(repls) - 1; i >= 0; i-- {
+		r := repls[i]
+		next := make([]string, 0, len(lines)-r.n+len(r.with))
+		next = append(next, lines[:r.start]...)
+		next = append(next, r.with...)
+		next = append(next, lines[r.start+r.n:]...)
+		lines = next
+	}
+
+	out := strings.Join(lines, "\n")
+	if len(lines) > 0 && addFinalNewline {
+		out += "\n"
+	}
+	if crlf {
+		out = strings.ReplaceAll(out, "\n", "\r\n")
+	}
+	return out, nil
+}
+
+// seekPatchLines finds pattern in lines at or after start. With hint >= 0
+// the match closest to hint wins; with eof the end of file is tried first.
+func seekPatchLines(lines, pattern []string, start, hint int, eof bool) int {
+	if len(pattern) > len(lines) {
+		return -1
+	}
+	normalizers := []func(string) string{
+		func(s string) string { return s },
+		func(s string) string { return strings.TrimRight(s, " \t\r") },
+		strings.TrimSpace,
+	}
+	for _, norm := range normalizers {
+		matchAt := func(i int) bool {
+			for j, p := range pattern {
+				if norm(lines[i+j]) != norm(p) {
+					return false
+				}
+			}
+			return true
+		}
+		if eof {
+			if i := len(lines) - len(pattern); i >= start && matchAt(i) {
+				return i
+			}
+		}
+		best := -1
+		for i := start; i+len(pattern) <= len(lines); i++ {
+			if !matchAt(i) {
+				continue
+			}
+			if hint < 0 {
+				return i
+			}
+			if best < 0 || absInt(i-hint) < absInt(best-hint) {
+				best = i
+			} else if i > hint {
+				break // matches only get further from hint
+			}
+		}
+		if best >= 0 {
+			return best
+		}
+	}
+	return -1
+}
+
+// describePatchMismatch explains a hunk whose context was not found: the
+// expected lines plus the closest region of the file, if any.
+func describePatchMismatch(lines, expected []string) string {
+	var sb strings.Builder
+	sb.WriteString("context not found; expected:")
+	for i, l := range expected {
+		if i == patchMaxExpectedShown {
+			fmt.Fprintf(&sb, "\n    | ... (%d more lines)", len(expected)-i)
+			break
+		}
+		sb.WriteString("\n    | " + l)
+	}
+
+	if len(expected) > len(lines) || len(lines)*len(expected) > patchMaxClosestWork {
+		return sb.String()
+	}
+	bestAt, bestScore := -1, 0
+	for i := 0; i+len(expected) <= len(lines); i++ {
+		score := 0
+		for j, e := range expected {
+			if strings.TrimSpace(lines[i+j]) == strings.TrimSpace(e) {
+				score++
+			}
+		}
+		if score > bestScore {
+			bestAt, bestScore = i, score
+		}
+	}
+	if bestAt >= 0 {
+		fmt.Fprintf(&sb, "\n  closest match at line %d (%d/%d lines match)", bestAt+1, bestScore, len(expected))
+		for j := range expected {
+			if strings.TrimSpace(lines[bestAt+j]) != strings.TrimSpace(expected[j]) {
+				fmt.Fprintf(&sb, "; first difference at line %d: %q", bestAt+j+1, lines[bestAt+j])
+				break
+			}
+		}
+	}
+	return sb.String()
+}
+
+func formatPatchConflicts(conflicts []patchConflict, dryRun bool) string {
+	var sb strings.Builder
+	if dryRun {
+		fmt.Fprintf(&sb, "Dry run: patch does not apply (%d conflict(s)).\n", len(conflicts))
+	} else {
+		fmt.Fprintf(&sb, "Patch not applied: %d conflict(s); no files were changed.\n", len(conflicts))
+	}
+	for i, c := range conflicts {
+		if i == patchMaxConflictsShown {
+			fmt.Fprintf(&sb, "\n... %d more conflict(s) not shown", len(conflicts)-i)
+			break
+		}
+		fmt.Fprintf(&sb, "\n%s: %s", c.path, c.detail)
+	}
+	sb.WriteString("\n\nRe-read the affected files and regenerate the failing hunks.")
+	return sb.String()
+}
+
+func summarizePatchChanges(changes []patchChange) string {
+	var sb strings.Builder
+	for _, c := range changes {
+		switch {
+		case c.op == patchAdd:
+			fmt.Fprintf(&sb, "  A %s (+%d)\n", c.path, c.added)
+		case c.op == patchDelete:
+			fmt.Fprintf(&sb, "  D %s (-%d)\n", c.path, c.removed)
+		case c.from != "":
+			fmt.Fprintf(&sb, "  R %s -> %s (+%d -%d)\n", c.from, c.path, c.added, c.removed)
+		default:
+			fmt.Fprintf(&sb, "  M %s (+%d -%d)\n", c.path, c.added, c.removed)
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func countPatchLines(content string) int {
+	if content == "" {
+		return 0
+	}
+	n := strings.Count(content, "\n")
+	if !strings.HasSuffix(content, "\n") {
+		n++
+	}
+	return n
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
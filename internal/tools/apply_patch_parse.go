@@ -0,0 +1,375 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// patchOp is the kind of change a filePatch makes.
+type patchOp int
+
+const (
+	patchUpdate patchOp = iota
+	patchAdd
+	patchDelete
+)
+
+// filePatch is one file's worth of changes parsed from a patch.
+type filePatch struct {
+	Op      patchOp
+	Path    string
+	MoveTo  string      // update only: rename target ("" = keep path)
+	Hunks   []patchHunk // update only
+	Content string      // add only: full file content
+}
+
+// patchHunk is a contiguous block of context/removed/added lines.
+type patchHunk struct {
+	Header   string // "@@ ... @@" line as written, for conflict reports
+	Anchor   string // envelope "@@ <line>" hint: a line to seek before matching
+	OldStart int    // unified diff: 1-based start line in the original file (0 = unknown)
+	Lines    []patchLine
+	EOF      bool // hunk must match at end of file ("*** End of File")
+	NoEOL    bool // result must not end with a newline ("\ No newline at end of file")
+}
+
+type patchLine struct {
+	Kind byte // ' ', '-' or '+'
+	Text string
+}
+
+// oldLines returns the lines the hunk expects to find (context + removed).
+func (h *patchHunk) oldLines() []string {
+	var out []string
+	for _, l := range h.Lines {
+		if l.Kind != '+' {
+			out = append(out, l.Text)
+		}
+	}
+	return out
+}
+
+// counts returns the number of added and removed lines.
+func (h *patchHunk) counts() (added, removed int) {
+	for _, l := range h.Lines {
+		switch l.Kind {
+		case '+':
+			added++
+		case '-':
+			removed++
+		}
+	}
+	return
+}
+
+// parsePatch accepts either the "*** Begin Patch" envelope format or a
+// unified diff (git diff / diff -u output).
+func parsePatch(text string) ([]filePatch, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		if strings.TrimSpace(l) == "*** Begin Patch" {
+			return parseEnvelopePatch(lines)
+		}
+		break
+	}
+	return parseUnifiedDiff(lines)
+}
+
+// parseEnvelopePatch parses the format:
+//
+//	*** Begin Patch
+//	*** Add File: path      (followed by "+" lines)
+//	*** Delete File: path
+//	*** Update File: path
+//	*** Move to: new/path   (optional)
+//	@@ optional anchor line
+//	 context / -removed / +added
+//	*** End of File         (optional: hunk is anchored at EOF)
+//	*** End Patch
+func parseEnvelopePatch(lines []string) ([]filePatch, error) {
+	var (
+		patches []filePatch
+		cur     *filePatch
+		hunk    *patchHunk
+		started bool
+	)
+	flushHunk := func() {
+		if cur != nil && hunk != nil && len(hunk.Lines) > 0 {
+			cur.Hunks = append(cur.Hunks, *hunk)
+		}
+		hunk = nil
+	}
+	flushFile := func() error {
+		flushHunk()
+		if cur == nil {
+			return nil
+		}
+		if cur.Op == patchUpdate && len(cur.Hunks) == 0 && cur.MoveTo == "" {
+			return fmt.Errorf("update of %s has no hunks", cur.Path)
+		}
+		patches = append(patches, *cur)
+		cur = nil
+		return nil
+	}
+
+	for i, raw := range lines {
+		lineNo := i + 1
+		trimmed := strings.TrimSpace(raw)
+		if !started {
+			if trimmed == "*** Begin Patch" {
+				started = true
+			}
+			continue
+		}
+
+		switch {
+		case trimmed == "*** End Patch":
+			if err := flushFile(); err != nil {
+				return nil, err
+			}
+			return requirePatches(patches)
+		case strings.HasPrefix(trimmed, "*** Add File:"):
+			if err := flushFile(); err != nil {
+				return nil, err
+			}
+			cur = &filePatch{Op: patchAdd, Path: strings.TrimSpace(strings.TrimPrefix(trimmed, "*** Add File:"))}
+		case strings.HasPrefix(trimmed, "*** Delete File:"):
+			if err := flushFile(); err != nil {
+				return nil, err
+			}
+			cur = &filePatch{Op: patchDelete, Path: strings.TrimSpace(strings.TrimPrefix(trimmed, "*** Delete File:"))}
+		case strings.HasPrefix(trimmed, "*** Update File:"):
+			if err := flushFile(); err != nil {
+				return nil, err
+			}
+			cur = &filePatch{Op: patchUpdate, Path: strings.TrimSpace(strings.TrimPrefix(trimmed, "*** Update File:"))}
+		case strings.HasPrefix(trimmed, "*** Move to:"):
+			if cur == nil || cur.Op != patchUpdate {
+				return nil, fmt.Errorf("line %d: \"*** Move to\" must follow \"*** Update File\"", lineNo)
+			}
+			cur.MoveTo = strings.TrimSpace(strings.TrimPrefix(trimmed, "*** Move to:"))
+		case trimmed == "*** End of File":
+			if hunk != nil {
+				hunk.EOF = true
+			}
+			flushHunk()
+		default:
+			if cur == nil {
+				if trimmed == "" {
+					continue
+				}
+				return nil, fmt.Errorf("line %d: expected a file header, got %q", lineNo, raw)
+			}
+			switch cur.Op {
+			case patchAdd:
+				if !strings.HasPrefix(raw, "+") {
+					return nil, fmt.Errorf("line %d: lines of an added file must start with '+'", lineNo)
+				}
+				cur.Content += raw[1:] + "\n"
+			case patchDelete:
+				if trimmed != "" {
+					return nil, fmt.Errorf("line %d: unexpected content after \"*** Delete File\"", lineNo)
+				}
+			case patchUpdate:
+				if strings.HasPrefix(raw, "@@") {
+					flushHunk()
+					anchor := strings.TrimSpace(strings.TrimPrefix(raw, "@@"))
+					hunk = &patchHunk{Header: raw, Anchor: anchor}
+					continue
+				}
+				pl, ok := parseHunkLine(raw)
+				if !ok {
+					return nil, fmt.Errorf("line %d: hunk lines must start with ' ', '-' or '+', got %q", lineNo, raw)
+				}
+				if hunk == nil {
+					hunk = &patchHunk{Header: "@@"}
+				}
+				hunk.Lines = append(hunk.Lines, pl)
+			}
+		}
+	}
+
+	if !started {
+		return nil, fmt.Errorf("missing \"*** Begin Patch\"")
+	}
+	// Tolerate a missing "*** End Patch" — models often drop the last line.
+	if err := flushFile(); err != nil {
+		return nil, err
+	}
+	return requirePatches(patches)
+}
+
+// parseUnifiedDiff parses "--- a/x" / "+++ b/x" / "@@ -l,n +l,n @@" diffs.
+// Hunk line counts are not enforced: model-written diffs often get them
+// wrong, so a hunk simply runs until the next header.
+func parseUnifiedDiff(lines []string) ([]filePatch, error) {
+	var (
+		patches []filePatch
+		cur     *filePatch
+		hunk    *patchHunk
+	)
+	flushHunk := func() {
+		if cur != nil && hunk != nil {
+			// Blank lines trailing a hunk are usually separators, not context.
+			for len(hunk.Lines) > 0 {
+				last := hunk.Lines[len(hunk.Lines)-1]
+				if last.Kind != ' ' || last.Text != "" {
+					break
+				}
+				hunk.Lines = hunk.Lines[:len(hunk.Lines)-1]
+			}
+			if len(hunk.Lines) > 0 {
+				cur.Hunks = append(cur.Hunks, *hunk)
+			}
+		}
+		hunk = nil
+	}
+	flushFile := func() {
+		flushHunk()
+		if cur == nil {
+			return
+		}
+		if cur.Op == patchAdd {
+			for _, h := range cur.Hunks {
+				for _, l := range h.Lines {
+					if l.Kind == '+' {
+						cur.Content += l.Text + "\n"
+					}
+				}
+				if h.NoEOL {
+					cur.Content = strings.TrimSuffix(cur.Content, "\n")
+				}
+			}
+			cur.Hunks = nil
+		}
+		patches = append(patches, *cur)
+		cur = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		raw := lines[i]
+		if strings.HasPrefix(raw, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ ") {
+			flushFile()
+			oldPath := diffHeaderPath(raw[4:])
+			newPath := diffHeaderPath(lines[i+1][4:])
+			i++
+			switch {
+			case oldPath == "" && newPath == "":
+				return nil, fmt.Errorf("line %d: both sides of the diff are /dev/null", i)
+			case oldPath == "":
+				cur = &filePatch{Op: patchAdd, Path: newPath}
+			case newPath == "":
+				cur = &filePatch{Op: patchDelete, Path: oldPath}
+			default:
+				cur = &filePatch{Op: patchUpdate, Path: oldPath}
+				if newPath != oldPath {
+					cur.MoveTo = newPath
+				}
+			}
+			continue
+		}
+		if cur == nil {
+			continue // preamble: "diff --git", "index ...", commit message, etc.
+		}
+		if strings.HasPrefix(raw, "@@") {
+			flushHunk()
+			oldStart, err := parseHunkHeader(raw)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			hunk = &patchHunk{Header: raw, OldStart: oldStart}
+			continue
+		}
+		if strings.HasPrefix(raw, "diff ") {
+			flushFile()
+			continue
+		}
+		if hunk == nil {
+			continue // extended headers: "new file mode", "similarity index", ...
+		}
+		if strings.HasPrefix(raw, `\`) {
+			// "\ No newline at end of file" applies to the preceding line.
+			if n := len(hunk.Lines); n > 0 && hunk.Lines[n-1].Kind != '-' {
+				hunk.NoEOL = true
+			}
+			continue
+		}
+		pl, ok := parseHunkLine(raw)
+		if !ok {
+			flushHunk()
+			continue
+		}
+		hunk.Lines = append(hunk.Lines, pl)
+	}
+	flushFile()
+
+	if len(patches) == 0 {
+		return nil, fmt.Errorf("no file changes found: expected a unified diff (---/+++/@@) or a \"*** Begin Patch\" block")
+	}
+	for _, p := range patches {
+		if p.Op == patchUpdate && len(p.Hunks) == 0 && p.MoveTo == "" {
+			return nil, fmt.Errorf("diff for %s has no hunks", p.Path)
+		}
+	}
+	return patches, nil
+}
+
+// parseHunkLine splits a hunk body line into its kind and text. An empty
+// line is treated as blank context (editors and models strip the space).
+func parseHunkLine(raw string) (patchLine, bool) {
+	if raw == "" {
+		return patchLine{Kind: ' '}, true
+	}
+	switch raw[0] {
+	case ' ', '-', '+':
+		return patchLine{Kind: raw[0], Text: raw[1:]}, true
+	}
+	return patchLine{}, false
+}
+
+// parseHunkHeader extracts the old start line from "@@ -l[,n] +l[,n] @@".
+// A bare "@@" is accepted (start unknown).
+func parseHunkHeader(raw string) (int, error) {
+	rest := strings.TrimSpace(strings.TrimPrefix(raw, "@@"))
+	if rest == "" || !strings.HasPrefix(rest, "-") {
+		return 0, nil
+	}
+	field := strings.Fields(rest)[0][1:]
+	if comma := strings.IndexByte(field, ','); comma >= 0 {
+		field = field[:comma]
+	}
+	n, err := strconv.Atoi(field)
+	if err != nil {
+		return 0, fmt.Errorf("malformed hunk header %q", raw)
+	}
+	return n, nil
+}
+
+// diffHeaderPath normalizes the path in a ---/+++ header: strips a
+// trailing timestamp, the a/ or b/ prefix, and maps /dev/null to "".
+func diffHeaderPath(s string) string {
+	if tab := strings.IndexByte(s, '\t'); tab >= 0 {
+		s = s[:tab]
+	}
+	s = strings.TrimSpace(s)
+	if s == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		s = s[2:]
+	}
+	return s
+}
+
+func requirePatches(patches []filePatch) ([]filePatch, error) {
+	if len(patches) == 0 {
+		return nil, fmt.Errorf("patch contains no file operations")
+	}
+	return patches, nil
+}
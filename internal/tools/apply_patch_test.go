@@ -0,0 +1,243 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePatchFixture(t *testing.T, ws string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		p := filepath.Join(ws, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readPatchFixture(t *testing.T, ws, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(ws, rel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestApplyPatchEnvelopeMultiFile(t *testing.T) {
+	ws := t.TempDir()
+	writePatchFixture(t, ws, map[string]string{
+		"a.go":     "package a\n\nfunc A() int {\n\treturn 1\n}\n\nfunc B() int {\n\treturn 2\n}\n",
+		"old.txt":  "rename me\n",
+		"gone.txt": "bye\n",
+	})
+	tool := NewApplyPatchTool(ws, true)
+
+	patch := `*** Begin Patch
+*** Update File: a.go
+@@ func A() int {
+-	return 1
++	return 10
+@@ func B() int {
+-	return 2
++	return 20
+*** Add File: docs/new.md
++# New
++body
+*** Delete File: gone.txt
+*** Update File: old.txt
+*** Move to: renamed.txt
+@@
+-rename me
++renamed
+*** End Patch`
+
+	res := tool.Execute(context.Background(), map[string]any{"patch": patch})
+	if res.IsError {
+		t.Fatalf("apply_patch error: %s", res.ForLLM)
+	}
+	for _, want := range []string{"4 file(s) changed", "M a.go (+2 -2)", "A docs/new.md (+2)", "D gone.txt", "R old.txt -> renamed.txt"} {
+		if !strings.Contains(res.ForLLM, want) {
+			t.Fatalf("summary missing %q:\n%s", want, res.ForLLM)
+		}
+	}
+
+	if got := readPatchFixture(t, ws, "a.go"); !strings.Contains(got, "return 10") || !strings.Contains(got, "return 20") {
+		t.Fatalf("a.go = %q", got)
+	}
+	if got := readPatchFixture(t, ws, "docs/new.md"); got != "# New\nbody\n" {
+		t.Fatalf("new.md = %q", got)
+	}
+	if got := readPatchFixture(t, ws, "renamed.txt"); got != "renamed\n" {
+		t.Fatalf("renamed.txt = %q", got)
+	}
+	for _, rel := range []string{"gone.txt", "old.txt"} {
+		if _, err := os.Stat(filepath.Join(ws, rel)); !os.IsNotExist(err) {
+			t.Fatalf("%s should be removed (err=%v)", rel, err)
+		}
+	}
+}
+
+func TestApplyPatchUnifiedDiff(t *testing.T) {
+	ws := t.TempDir()
+	var sb strings.Builder
+	for i := 1; i <= 20; i++ {
+		sb.WriteString("line " + string(rune('a'+i-1)) + "\n")
+	}
+	writePatchFixture(t, ws, map[string]string{"list.txt": sb.String()})
+	tool := NewApplyPatchTool(ws, true)
+
+	// Line numbers are off by two: the hint only picks among matches.
+	patch := `diff --git a/list.txt b/list.txt
+index 1111111..2222222 100644
+--- a/list.txt
++++ b/list.txt
+@@ -4,3 +4,4 @@
+ line c
+-line d
++line D
++line d2
+ line e
+@@ -17,2 +18,2 @@ trailing
+ line s
+-line t
++line T
+--- /dev/null
++++ b/added.txt
+@@ -0,0 +1,2 @@
++one
++two
+`
+	res := tool.Execute(context.Background(), map[string]any{"patch": patch})
+	if res.IsError {
+		t.Fatalf("apply_patch error: %s", res.ForLLM)
+	}
+	got := readPatchFixture(t, ws, "list.txt")
+	for _, want := range []string{"line c\nline D\nline d2\nline e\n", "line s\nline T\n"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("list.txt missing %q:\n%s", want, got)
+		}
+	}
+	if got := readPatchFixture(t, ws, "added.txt"); got != "one\ntwo\n" {
+		t.Fatalf("added.txt = %q", got)
+	}
+}
+
+func TestApplyPatchConflictIsAtomic(t *testing.T) {
+	ws := t.TempDir()
+	writePatchFixture(t, ws, map[string]string{
+		"one.txt": "alpha\nbeta\ngamma\n",
+		"two.txt": "red\ngreen\nblue\n",
+	})
+	tool := NewApplyPatchTool(ws, true)
+
+	patch := `*** Begin Patch
+*** Update File: one.txt
+@@
+ alpha
+-beta
++BETA
+*** Update File: two.txt
+@@
+ red
+-yellow
++YELLOW
+ blue
+*** End Patch`
+
+	res := tool.Execute(context.Background(), map[string]any{"patch": patch})
+	if !res.IsError {
+		t.Fatalf("expected conflict, got %q", res.ForLLM)
+	}
+	for _, want := range []string{"1 conflict(s)", "two.txt: hunk 1/1", "| yellow", "closest match at line 1 (2/3 lines match)", `first difference at line 2: "green"`} {
+		if !strings.Contains(res.ForLLM, want) {
+			t.Fatalf("conflict report missing %q:\n%s", want, res.ForLLM)
+		}
+	}
+	if got := readPatchFixture(t, ws, "one.txt"); got != "alpha\nbeta\ngamma\n" {
+		t.Fatalf("one.txt was modified despite conflict: %q", got)
+	}
+}
+
+func TestApplyPatchDryRun(t *testing.T) {
+	ws := t.TempDir()
+	writePatchFixture(t, ws, map[string]string{"f.txt": "x\n"})
+	tool := NewApplyPatchTool(ws, true)
+
+	patch := "--- a/f.txt\n+++ b/f.txt\n@@ -1 +1 @@\n-x\n+y\n"
+	res := tool.Execute(context.Background(), map[string]any{"patch": patch, "dry_run": true})
+	if res.IsError || !strings.Contains(res.ForLLM, "Dry run: patch applies cleanly") || !strings.Contains(res.ForLLM, "M f.txt (+1 -1)") {
+		t.Fatalf("dry run result: %+v", res)
+	}
+	if got := readPatchFixture(t, ws, "f.txt"); got != "x\n" {
+		t.Fatalf("dry run wrote file: %q", got)
+	}
+
+	res = tool.Execute(context.Background(), map[string]any{"patch": "*** Begin Patch\n*** Add File: f.txt\n+z\n*** End Patch", "dry_run": true})
+	if !res.IsError || !strings.Contains(res.ForLLM, "already exists") {
+		t.Fatalf("add over existing file should conflict: %+v", res)
+	}
+}
+
+func TestApplyPatchPathRules(t *testing.T) {
+	ws := t.TempDir()
+	writePatchFixture(t, ws, map[string]string{".goclaw/state.json": "{}\n"})
+	tool := NewApplyPatchTool(ws, true)
+	tool.DenyPaths(".goclaw")
+
+	res := tool.Execute(context.Background(), map[string]any{"patch": "*** Begin Patch\n*** Delete File: .goclaw/state.json\n*** End Patch"})
+	if !res.IsError {
+		t.Fatalf("denied path should be rejected: %q", res.ForLLM)
+	}
+	res = tool.Execute(context.Background(), map[string]any{"patch": "*** Begin Patch\n*** Add File: ../escape.txt\n+x\n*** End Patch"})
+	if !res.IsError {
+		t.Fatalf("path outside workspace should be rejected: %q", res.ForLLM)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(ws), "escape.txt")); !os.IsNotExist(err) {
+		t.Fatal("escape.txt was written outside the workspace")
+	}
+}
+
+func TestApplyHunksWhitespaceAndLineEndings(t *testing.T) {
+	hunks := []patchHunk{{Lines: []patchLine{{' ', "if x {"}, {'-', "  call()"}, {'+', "  call2()"}, {' ', "}"}}}}
+
+	// Trailing whitespace in the file still matches.
+	got, conflicts := applyHunks("if x {   \n  call()\n}\n", hunks)
+	if len(conflicts) > 0 || got != "if x {   \n  call2()\n}\n" {
+		t.Fatalf("got %q, conflicts %v", got, conflicts)
+	}
+
+	// CRLF files keep CRLF.
+	got, conflicts = applyHunks("if x {\r\n  call()\r\n}\r\n", hunks)
+	if len(conflicts) > 0 || got != "if x {\r\n  call2()\r\n}\r\n" {
+		t.Fatalf("crlf: got %q, conflicts %v", got, conflicts)
+	}
+
+	// A missing trailing newline is preserved.
+	got, _ = applyHunks("if x {\n  call()\n}", hunks)
+	if got != "if x {\n  call2()\n}" {
+		t.Fatalf("no-eol: got %q", got)
+	}
+}
+
+func TestParsePatchErrors(t *testing.T) {
+	tests := []struct {
+		name, patch string
+	}{
+		{"not a patch", "hello world"},
+		{"update without hunks", "*** Begin Patch\n*** Update File: a.txt\n*** End Patch"},
+		{"bad add line", "*** Begin Patch\n*** Add File: a.txt\nmissing plus\n*** End Patch"},
+		{"move outside update", "*** Begin Patch\n*** Move to: b.txt\n*** End Patch"},
+	}
+	for _, tt := range tests {
+		if _, err := parsePatch(tt.patch); err == nil {
+			t.Errorf("%s: expected parse error", tt.name)
+		}
+	}
+}
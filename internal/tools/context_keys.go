@@ -618,3 +618,18 @@ func SandboxConfigFromCtx(ctx context.Context) *sandbox.Config {
 	}
 	return nil
 }
+
+// --- Per-agent tool guardrails ---
+
+const ctxToolGuardrails toolContextKey = "tool_guardrails"
+
+// WithToolGuardrails injects the agent's guardrail rules (from tools_config),
+// evaluated by the registry after the global rules.
+func WithToolGuardrails(ctx context.Context, rules []config.ToolGuardrailRule) context.Context {
+	return context.WithValue(ctx, ctxToolGuardrails, rules)
+}
+
+func ToolGuardrailsFromCtx(ctx context.Context) []config.ToolGuardrailRule {
+	v, _ := ctx.Value(ctxToolGuardrails).([]config.ToolGuardrailRule)
+	return v
+}
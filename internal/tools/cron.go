@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/nextlevelbuilder/goclaw/internal/cron"
 	"github.com/nextlevelbuilder/goclaw/internal/store"
 )
 
@@ -44,12 +45,15 @@ VALID ACTIONS AND EXACT PAYLOAD SHAPES:
   "job": {
     "name": "string",             // required, lowercase slug: [a-z0-9-]+
     "schedule": { ... },          // required
-    "message": "string",          // required
+    "message": "string",          // required (optional for webhook jobs)
     "deliver": true|false,        // optional, default false
     "channel": "string",          // optional, auto-filled from current channel context
     "to": "string",               // optional
     "agentId": "string",          // optional, defaults to current agent
-    "deleteAfterRun": true|false  // optional, default true for schedule.kind="at"
+    "deleteAfterRun": true|false, // optional, default true for schedule.kind="at"
+    "catchUp": "string",          // optional: "run-once" (default), "skip" or "run-all" for runs missed while the gateway was down
+    "webhookUrl": "string",       // optional: POST the job to this public http(s) URL instead of running an agent turn
+    "deliverIf": "string"         // optional: deliver only when the output matches (see CONDITIONS)
   }
 }
 
@@ -66,7 +70,10 @@ VALID ACTIONS AND EXACT PAYLOAD SHAPES:
     "to": "string",
     "agentId": "string",
     "deleteAfterRun": true|false,
-    "disabled": true|false
+    "disabled": true|false,
+    "catchUp": "run-once"|"skip"|"run-all",
+    "webhookUrl": "string",       // "" turns a webhook job back into an agent turn
+    "deliverIf": "string"
   }
 }
 
@@ -80,19 +87,23 @@ VALID ACTIONS AND EXACT PAYLOAD SHAPES:
 { "action": "runs", "jobId": "string" }
 
 SCHEDULE SCHEMA:
-- at: { "kind": "at", "atMs": <unix-milliseconds> }
+- at: { "kind": "at", "atMs": <unix-milliseconds> }  (an absolute instant; "tz" does not apply)
 - every: { "kind": "every", "everyMs": <interval-ms> }
 - cron: { "kind": "cron", "expr": "<5-field cron>", "tz": "<IANA timezone, e.g. Asia/Ho_Chi_Minh; omit for gateway default>" }
+- after: { "kind": "after", "after": "<jobId>" } — runs each time that job succeeds, with its output appended to "message"
+
+CONDITIONS (deliverIf; prefix with "!" to negate):
+- "nonempty", "changed" (differs from the previous run), "contains:<text>", "matches:<regex>"
 
 RULES:
 - For action="add", send the job inside "job". Do not place job fields at the root level.
 - For action="update", send changes inside "patch". Do not place patch fields at the root level.
 - Always use "jobId". Do not use "id".
-- "name", "schedule", and "message" are required for add.
+- "name", "schedule", and "message" are required for add ("message" is optional with "webhookUrl").
 - "name" must match: lowercase letters, numbers, hyphens only.
 - Before creating or updating a scheduled job, call the datetime tool first to get the precise current time and unix_ms timestamp. Never guess timestamps.
 - Omit optional fields when unknown; do not invent placeholder values like "", 0, or null unless required.
-- Jobs run as isolated agent turns using the provided "message".`
+- Jobs run as isolated agent turns using the provided "message", or POST {"jobId","jobName","message","input","firedAtMs"} to "webhookUrl".`
 }
 
 func (t *CronTool) Parameters() map[string]any {
@@ -110,7 +121,7 @@ func (t *CronTool) Parameters() map[string]any {
 			},
 			"job": map[string]any{
 				"type":                 "object",
-				"description":          "Job definition for add action (name, schedule, message, deliver, channel, to, agentId, deleteAfterRun)",
+				"description":          "Job definition for add action (name, schedule, message, deliver, channel, to, agentId, deleteAfterRun, catchUp, webhookUrl, deliverIf)",
 				"additionalProperties": true,
 			},
 			"jobId": map[string]any{
@@ -207,16 +218,29 @@ func (t *CronTool) handleAdd(ctx context.Context, args map[string]any, agentID,
 	}
 
 	message, _ := jobObj["message"].(string)
-	if message == "" {
+	webhookURL, _ := jobObj["webhookUrl"].(string)
+	if message == "" && webhookURL == "" {
 		return ErrorResult("job.message is required")
 	}
+	if webhookURL != "" {
+		if err := cron.ValidateWebhookURL(webhookURL); err != nil {
+			return ErrorResult(err.Error())
+		}
+		if err := CheckSSRF(webhookURL); err != nil {
+			return ErrorResult(fmt.Sprintf("job.webhookUrl rejected: %v", err))
+		}
+	}
+	deliverIf, _ := jobObj["deliverIf"].(string)
+	if err := cron.ValidateCondition(deliverIf); err != nil {
+		return ErrorResult(err.Error())
+	}
 
 	// Parse schedule
 	schedule := store.CronSchedule{
 		Kind: stringFromMap(scheduleObj, "kind"),
 	}
 	if schedule.Kind == "" {
-		return ErrorResult("job.schedule.kind is required (at, every, or cron)")
+		return ErrorResult("job.schedule.kind is required (at, every, cron, or after)")
 	}
 
 	switch schedule.Kind {
@@ -243,13 +267,26 @@ func (t *CronTool) handleAdd(ctx context.Context, args map[string]any, agentID,
 			return ErrorResult("job.schedule.expr is required for 'cron' schedule")
 		}
 		schedule.TZ = stringFromMap(scheduleObj, "tz")
-		if schedule.TZ != "" {
-			if _, err := time.LoadLocation(schedule.TZ); err != nil {
-				return ErrorResult(fmt.Sprintf("invalid timezone '%s': use IANA names like 'Asia/Ho_Chi_Minh', 'America/New_York'", schedule.TZ))
-			}
+	case "after":
+		schedule.After = stringFromMap(scheduleObj, "after")
+		if schedule.After == "" {
+			return ErrorResult("job.schedule.after is required for 'after' schedule")
+		}
+		if _, errResult := t.checkJobOwnership(ctx, schedule.After, agentID, userID); errResult != nil {
+			return errResult
 		}
 	default:
-		return ErrorResult(fmt.Sprintf("invalid schedule kind: %s (must be at, every, or cron)", schedule.Kind))
+		return ErrorResult(fmt.Sprintf("invalid schedule kind: %s (must be at, every, cron, or after)", schedule.Kind))
+	}
+	if schedule.TZ != "" {
+		if _, err := time.LoadLocation(schedule.TZ); err != nil {
+			return ErrorResult(fmt.Sprintf("invalid timezone '%s': use IANA names like 'Asia/Ho_Chi_Minh', 'America/New_York'", schedule.TZ))
+		}
+	}
+
+	catchUp, _ := jobObj["catchUp"].(string)
+	if err := store.ValidateCronCatchUp(catchUp); err != nil {
+		return ErrorResult(err.Error())
 	}
 
 	// Optional fields
@@ -293,10 +330,24 @@ func (t *CronTool) handleAdd(ctx context.Context, args map[string]any, agentID,
 		return ErrorResult(fmt.Sprintf("failed to create cron job: %v", err))
 	}
 
-	// Set wake_heartbeat if requested (triggers heartbeat after cron job completes)
+	// Apply fields not in the AddJob signature: wake_heartbeat (triggers
+	// heartbeat after cron job completes), the catch-up policy, the webhook
+	// target and the delivery condition.
+	var patch store.CronJobPatch
 	if wh, _ := jobObj["wake_heartbeat"].(bool); wh {
-		wakeTrue := true
-		if updated, uErr := t.cronStore.UpdateJob(ctx, job.ID, store.CronJobPatch{WakeHeartbeat: &wakeTrue}); uErr == nil {
+		patch.WakeHeartbeat = &wh
+	}
+	if catchUp != "" {
+		patch.CatchUp = &catchUp
+	}
+	if webhookURL != "" {
+		patch.WebhookURL = &webhookURL
+	}
+	if deliverIf != "" {
+		patch.DeliverIf = &deliverIf
+	}
+	if patch.WakeHeartbeat != nil || patch.CatchUp != nil || patch.WebhookURL != nil || patch.DeliverIf != nil {
+		if updated, uErr := t.cronStore.UpdateJob(ctx, job.ID, patch); uErr == nil {
 			job = updated
 		}
 	}
@@ -351,6 +402,17 @@ func (t *CronTool) handleUpdate(ctx context.Context, args map[string]any, agentI
 		}
 	}
 
+	if patch.Schedule != nil && patch.Schedule.After != "" {
+		if _, errResult := t.checkJobOwnership(ctx, patch.Schedule.After, agentID, userID); errResult != nil {
+			return errResult
+		}
+	}
+	if patch.WebhookURL != nil && *patch.WebhookURL != "" {
+		if err := CheckSSRF(*patch.WebhookURL); err != nil {
+			return ErrorResult(fmt.Sprintf("patch.webhookUrl rejected: %v", err))
+		}
+	}
+
 	job, err := t.cronStore.UpdateJob(ctx, jobID, patch)
 	if err != nil {
 		return ErrorResult(fmt.Sprintf("failed to update cron job: %v", err))
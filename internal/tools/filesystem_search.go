@@ -0,0 +1,516 @@
+package tools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nextlevelbuilder/goclaw/internal/sandbox"
+)
+
+const (
+	searchDefaultMaxResults = 100
+	searchMaxResultsLimit   = 500
+	searchMaxContextLines   = 10
+	searchMaxFileSize       = 2 << 20 // skip larger files (logs, dumps)
+	searchMaxFilesScanned   = 20000   // bounds the walk on huge workspaces
+	searchMaxLineChars      = 300     // long lines (minified code) are truncated
+)
+
+// searchSkipDirs are directories never descended into.
+var searchSkipDirs = map[string]bool{".git": true, "node_modules": true}
+
+// SearchFilesTool searches file contents (regex or literal) and matches file
+// names by glob across the workspace. It applies the same path rules as
+// read_file/list_files: restrict_to_workspace, allowed and denied prefixes,
+// and the team workspace from context.
+type SearchFilesTool struct {
+	workspace       string
+	restrict        bool
+	allowedPrefixes []string
+	deniedPrefixes  []string
+	sandboxMgr      sandbox.Manager
+}
+
+func NewSearchFilesTool(workspace string, restrict bool) *SearchFilesTool {
+	return &SearchFilesTool{workspace: workspace, restrict: restrict}
+}
+
+func NewSandboxedSearchFilesTool(workspace string, restrict bool, mgr sandbox.Manager) *SearchFilesTool {
+	return &SearchFilesTool{workspace: workspace, restrict: restrict, sandboxMgr: mgr}
+}
+
+// AllowPaths adds extra path prefixes that search_files may search even when
+// restrict_to_workspace is true (e.g. skills directories).
+func (t *SearchFilesTool) AllowPaths(prefixes ...string) {
+	t.allowedPrefixes = append(t.allowedPrefixes, prefixes...)
+}
+
+// DenyPaths adds path prefixes that search_files must reject/skip.
+func (t *SearchFilesTool) DenyPaths(prefixes ...string) {
+	t.deniedPrefixes = append(t.deniedPrefixes, prefixes...)
+}
+
+// SetSandboxKey is a no-op; sandbox key is now read from ctx (thread-safe).
+func (t *SearchFilesTool) SetSandboxKey(key string) {}
+
+func (t *SearchFilesTool) Name() string { return "search_files" }
+func (t *SearchFilesTool) Description() string {
+	return "Search file contents by regex or literal text, or find files by glob, across the workspace. " +
+		"Returns path:line: text matches. Prefer this over exec grep/find."
+}
+func (t *SearchFilesTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"pattern": map[string]any{
+				"type":        "string",
+				"description": "Text to search for in file contents (RE2 regex unless literal=true). Omit to only list files matching glob.",
+			},
+			"path": map[string]any{
+				"type":        "string",
+				"description": "Directory or file to search (relative to workspace; omit for workspace root)",
+			},
+			"glob": map[string]any{
+				"type":        "string",
+				"description": "File filter, e.g. \"*.go\" (matches file names) or \"docs/**/*.md\" (matches paths relative to path)",
+			},
+			"literal": map[string]any{
+				"type":        "boolean",
+				"description": "Treat pattern as plain text instead of a regex. Defaults to false.",
+			},
+			"ignore_case": map[string]any{
+				"type":        "boolean",
+				"description": "Case-insensitive matching. Defaults to false.",
+			},
+			"context_lines": map[string]any{
+				"type":        "integer",
+				"description": fmt.Sprintf("Lines of context before and after each match (0-%d). Defaults to 0.", searchMaxContextLines),
+			},
+			"max_results": map[string]any{
+				"type":        "integer",
+				"description": fmt.Sprintf("Maximum matches (or files, without pattern) to return. Defaults to %d, max %d.", searchDefaultMaxResults, searchMaxResultsLimit),
+			},
+		},
+	}
+}
+
+// searchRequest is the parsed form of the tool arguments.
+type searchRequest struct {
+	path       string
+	pattern    string
+	glob       string
+	literal    bool
+	ignoreCase bool
+	context    int
+	maxResults int
+	re         *regexp.Regexp // nil = list files only
+}
+
+func parseSearchArgs(args map[string]any) (*searchRequest, error) {
+	req := &searchRequest{
+		path:       ".",
+		maxResults: intArg(args, "max_results", searchDefaultMaxResults),
+		context:    intArg(args, "context_lines", 0),
+	}
+	if p, _ := args["path"].(string); p != "" {
+		req.path = p
+	}
+	req.pattern, _ = args["pattern"].(string)
+	req.glob, _ = args["glob"].(string)
+	req.literal, _ = args["literal"].(bool)
+	req.ignoreCase, _ = args["ignore_case"].(bool)
+
+	if req.pattern == "" && req.glob == "" {
+		return nil, fmt.Errorf("pattern or glob is required")
+	}
+	if req.glob != "" {
+		if _, err := path.Match(strings.ReplaceAll(req.glob, "**", "*"), ""); err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %v", req.glob, err)
+		}
+	}
+	req.maxResults = max(1, min(req.maxResults, searchMaxResultsLimit))
+	req.context = max(0, min(req.context, searchMaxContextLines))
+
+	if req.pattern != "" {
+		expr := req.pattern
+		if req.literal {
+			expr = regexp.QuoteMeta(expr)
+		}
+		if req.ignoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %v (set literal=true to search plain text)", err)
+		}
+		req.re = re
+	}
+	return req, nil
+}
+
+func (t *SearchFilesTool) Execute(ctx context.Context, args map[string]any) *Result {
+	req, err := parseSearchArgs(args)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	// Sandbox routing (sandboxKey from ctx — thread-safe)
+	sandboxKey := ToolSandboxKeyFromCtx(ctx)
+	if t.sandboxMgr != nil && sandboxKey != "" {
+		return t.executeInSandbox(ctx, req, sandboxKey)
+	}
+
+	// Host execution — use per-user workspace from context if available
+	workspace := ToolWorkspaceFromCtx(ctx)
+	if workspace == "" {
+		workspace = t.workspace
+	}
+	restrict := effectiveRestrict(ctx, t.restrict)
+	allowed := allowedWithTeamWorkspace(ctx, t.allowedPrefixes)
+	root, err := resolvePathWithAllowed(req.path, workspace, restrict, allowed)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if err := checkDeniedPath(root, t.workspace, t.deniedPrefixes); err != nil {
+		return ErrorResult(err.Error())
+	}
+	if _, err := os.Stat(root); err != nil {
+		if os.IsNotExist(err) {
+			return SilentResult(fmt.Sprintf("Path does not exist: %s", req.path))
+		}
+		return ErrorResult(fmt.Sprintf("failed to search: %v", err))
+	}
+
+	s := &fileSearcher{
+		req:       req,
+		root:      root,
+		display:   displayBase(root, workspace),
+		restrict:  restrict,
+		workspace: t.workspace,
+		denied:    t.deniedPrefixes,
+	}
+	if err := s.run(ctx); err != nil {
+		return ErrorResult(fmt.Sprintf("failed to search: %v", err))
+	}
+	return SilentResult(s.render())
+}
+
+func (t *SearchFilesTool) executeInSandbox(ctx context.Context, req *searchRequest, sandboxKey string) *Result {
+	sb, err := t.sandboxMgr.Get(ctx, sandboxKey, t.workspace, SandboxConfigFromCtx(ctx))
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("sandbox error: %v", err))
+	}
+	bridge := sandbox.NewFsBridge(sb.ID(), sandbox.DefaultContainerWorkdir)
+
+	containerCwd, cwdErr := SandboxCwd(ctx, t.workspace, sandbox.DefaultContainerWorkdir)
+	if cwdErr != nil {
+		return ErrorResult(fmt.Sprintf("sandbox path mapping: %v", cwdErr))
+	}
+	containerPath := ResolveSandboxPath(req.path, containerCwd)
+
+	files, err := bridge.Search(ctx, containerPath, sandbox.SearchOptions{
+		Pattern:    req.pattern,
+		Literal:    req.literal,
+		IgnoreCase: req.ignoreCase,
+		Match: func(rel string) bool {
+			return req.glob == "" || matchSearchGlob(req.glob, rel)
+		},
+		MaxScan:     searchMaxFilesScanned,
+		MaxFiles:    req.maxResults + 1, // one extra so the cap is reported
+		MaxFileSize: searchMaxFileSize,
+	})
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to search: %v", err) + MaybeFsBridgeHint(err))
+	}
+
+	// The container only narrows the candidates; matching and output are done
+	// here so both paths share RE2 semantics and formatting.
+	s := &fileSearcher{req: req}
+	for _, p := range files {
+		if s.capped || ctx.Err() != nil {
+			break
+		}
+		name := strings.TrimPrefix(p, containerCwd+"/")
+		if req.re == nil {
+			s.listFile(name)
+			continue
+		}
+		if isBinaryFileExt(p) {
+			continue
+		}
+		content, err := bridge.ReadFile(ctx, p)
+		if err != nil {
+			continue
+		}
+		s.scanned++
+		s.scanData(name, []byte(content))
+	}
+	return SilentResult(s.render())
+}
+
+// fileSearcher walks a directory tree on the host and collects matches.
+type fileSearcher struct {
+	req       *searchRequest
+	root      string
+	display   string // prefix stripped from reported paths
+	restrict  bool
+	workspace string
+	denied    []string
+
+	out     strings.Builder
+	matches int
+	files   int
+	scanned int
+	capped  bool
+}
+
+func (s *fileSearcher) run(ctx context.Context) error {
+	info, err := os.Stat(s.root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		s.visit(s.root, filepath.Base(s.root))
+		return nil
+	}
+
+	return filepath.WalkDir(s.root, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if d != nil && d.IsDir() && p != s.root {
+				return fs.SkipDir // unreadable directory
+			}
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if s.capped || s.scanned >= searchMaxFilesScanned {
+			s.capped = true
+			return fs.SkipAll
+		}
+		if len(s.denied) > 0 && checkDeniedPath(p, s.workspace, s.denied) != nil {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if p != s.root && searchSkipDirs[d.Name()] {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		// Symlinks are skipped: a link could point outside the workspace.
+		// Hardlinks are rejected for the same reason when restricted.
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if s.restrict && checkHardlink(p) != nil {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(s.root, p)
+		if s.req.glob != "" && !matchSearchGlob(s.req.glob, filepath.ToSlash(rel)) {
+			return nil
+		}
+		s.visit(p, rel)
+		return nil
+	})
+}
+
+// visit records a matching file (glob-only mode) or scans it for matches.
+func (s *fileSearcher) visit(p, rel string) {
+	s.scanned++
+	name := s.displayPath(p)
+
+	if s.req.re == nil {
+		s.listFile(name)
+		return
+	}
+
+	if isBinaryFileExt(p) {
+		return
+	}
+	info, err := os.Stat(p)
+	if err != nil || info.Size() > searchMaxFileSize {
+		return
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return
+	}
+	s.scanData(name, data)
+}
+
+// listFile records a file name in glob-only mode.
+func (s *fileSearcher) listFile(name string) {
+	if s.files >= s.req.maxResults {
+		s.capped = true
+		return
+	}
+	s.files++
+	fmt.Fprintf(&s.out, "%s\n", name)
+}
+
+// scanData scans one file's content, skipping binary data.
+func (s *fileSearcher) scanData(name string, data []byte) {
+	if bytes.IndexByte(data[:min(len(data), 8192)], 0) >= 0 {
+		return
+	}
+	s.scanFile(name, data)
+}
+
+// scanFile writes grep-style output for one file: "path:N: text" for matches,
+// "path-N- text" for context lines and "--" between separate groups.
+func (s *fileSearcher) scanFile(name string, data []byte) {
+	var lines []string
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), searchMaxFileSize)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+
+	ctxN := s.req.context
+	lastPrinted := -1
+	fileHit := false
+	for i, line := range lines {
+		if !s.req.re.MatchString(line) {
+			continue
+		}
+		if s.matches >= s.req.maxResults {
+			s.capped = true
+			return
+		}
+		s.matches++
+		if !fileHit {
+			fileHit = true
+			s.files++
+		}
+
+		start := max(i-ctxN, lastPrinted+1)
+		if lastPrinted >= 0 && start > lastPrinted+1 && ctxN > 0 {
+			s.out.WriteString("--\n")
+		}
+		for j := start; j < i; j++ {
+			fmt.Fprintf(&s.out, "%s-%d- %s\n", name, j+1, truncateSearchLine(lines[j]))
+		}
+		fmt.Fprintf(&s.out, "%s:%d: %s\n", name, i+1, truncateSearchLine(line))
+		lastPrinted = i
+
+		// Trailing context stops before the next match so it is not printed twice.
+		for j := i + 1; j <= i+ctxN && j < len(lines); j++ {
+			if s.req.re.MatchString(lines[j]) {
+				break
+			}
+			fmt.Fprintf(&s.out, "%s-%d- %s\n", name, j+1, truncateSearchLine(lines[j]))
+			lastPrinted = j
+		}
+	}
+}
+
+func (s *fileSearcher) displayPath(p string) string {
+	if s.display != "" {
+		if rel, err := filepath.Rel(s.display, p); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return p
+}
+
+func (s *fileSearcher) render() string {
+	body := s.out.String()
+	var summary string
+	switch {
+	case s.req.re == nil && s.files == 0:
+		return "No files matched."
+	case s.req.re == nil:
+		summary = fmt.Sprintf("Found %d files", s.files)
+	case s.matches == 0:
+		return "No matches found."
+	default:
+		summary = fmt.Sprintf("Found %d matches in %d files", s.matches, s.files)
+	}
+	if s.capped {
+		summary += fmt.Sprintf(" (results capped at %d; narrow the search with path or glob)", s.req.maxResults)
+		if s.scanned >= searchMaxFilesScanned {
+			summary += fmt.Sprintf(" [stopped after scanning %d files]", searchMaxFilesScanned)
+		}
+	}
+	return capSearchOutput(summary + "\n\n" + body)
+}
+
+// displayBase returns the directory reported paths are relative to: the
+// workspace when root is inside it, otherwise root itself.
+func displayBase(root, workspace string) string {
+	if wsReal, err := filepath.EvalSymlinks(workspace); err == nil && isPathInside(root, wsReal) {
+		return wsReal
+	}
+	if abs, err := filepath.Abs(workspace); err == nil && isPathInside(root, abs) {
+		return abs
+	}
+	if info, err := os.Stat(root); err == nil && !info.IsDir() {
+		return filepath.Dir(root)
+	}
+	return root
+}
+
+// matchSearchGlob matches a glob against a slash-separated path relative to
+// the search root. Globs without "/" match the file name only; "**" matches
+// any number of directories.
+func matchSearchGlob(glob, rel string) bool {
+	if !strings.Contains(glob, "/") {
+		ok, _ := path.Match(glob, path.Base(rel))
+		return ok
+	}
+	return matchGlobSegments(strings.Split(glob, "/"), strings.Split(rel, "/"))
+}
+
+func matchGlobSegments(glob, parts []string) bool {
+	for len(glob) > 0 {
+		if glob[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchGlobSegments(glob[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(glob[0], parts[0]); !ok {
+			return false
+		}
+		glob, parts = glob[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+func truncateSearchLine(line string) string {
+	line = strings.TrimRight(line, "\r")
+	if r := []rune(line); len(r) > searchMaxLineChars {
+		return string(r[:searchMaxLineChars]) + "…"
+	}
+	return line
+}
+
+// capSearchOutput keeps results within the same budget as read_file.
+func capSearchOutput(out string) string {
+	r := []rune(out)
+	if len(r) <= readFileMaxChars {
+		return out
+	}
+	head := string(r[:readFileMaxChars])
+	if cut := strings.LastIndexByte(head, '\n'); cut > 0 {
+		head = head[:cut]
+	}
+	return head + "\n\n[Output capped. Narrow the search with path, glob or max_results.]"
+}
@@ -0,0 +1,166 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func setupSearchWorkspace(t *testing.T) string {
+	t.Helper()
+	ws := t.TempDir()
+	files := map[string]string{
+		"main.go":              "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n",
+		"docs/guide.md":        "# Guide\nSetup steps\nTODO: write intro\n",
+		"docs/api/ref.md":      "Reference\ntodo later\n",
+		"notes.txt":            "a+b literal\nplain\n",
+		".goclaw/secret.txt":   "TODO secret\n",
+		".git/HEAD":            "TODO ref\n",
+		"node_modules/x/a.txt": "TODO vendored\n",
+	}
+	for rel, content := range files {
+		p := filepath.Join(ws, rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ws
+}
+
+func runSearch(t *testing.T, tool *SearchFilesTool, args map[string]any) string {
+	t.Helper()
+	res := tool.Execute(context.Background(), args)
+	if res.IsError {
+		t.Fatalf("search_files(%v) error: %s", args, res.ForLLM)
+	}
+	return res.ForLLM
+}
+
+func TestSearchFilesRegexSkipsDeniedAndVendoredDirs(t *testing.T) {
+	ws := setupSearchWorkspace(t)
+	tool := NewSearchFilesTool(ws, true)
+	tool.DenyPaths(".goclaw")
+
+	out := runSearch(t, tool, map[string]any{"pattern": "TODO"})
+	if !strings.Contains(out, "docs/guide.md:3: TODO: write intro") {
+		t.Fatalf("missing match:\n%s", out)
+	}
+	for _, hidden := range []string{"secret", ".git", "vendored"} {
+		if strings.Contains(out, hidden) {
+			t.Fatalf("output leaked %q:\n%s", hidden, out)
+		}
+	}
+	if !strings.HasPrefix(out, "Found 1 matches in 1 files") {
+		t.Fatalf("summary = %q", strings.SplitN(out, "\n", 2)[0])
+	}
+}
+
+func TestSearchFilesIgnoreCaseAndGlob(t *testing.T) {
+	ws := setupSearchWorkspace(t)
+	tool := NewSearchFilesTool(ws, true)
+
+	out := runSearch(t, tool, map[string]any{"pattern": "todo", "ignore_case": true, "glob": "docs/**/*.md"})
+	if !strings.Contains(out, "docs/guide.md:3:") || !strings.Contains(out, "docs/api/ref.md:2:") {
+		t.Fatalf("expected matches in both docs:\n%s", out)
+	}
+
+	out = runSearch(t, tool, map[string]any{"pattern": "todo", "ignore_case": true, "glob": "ref.md"})
+	if strings.Contains(out, "guide.md") || !strings.Contains(out, "ref.md") {
+		t.Fatalf("name glob should only match ref.md:\n%s", out)
+	}
+}
+
+func TestSearchFilesLiteralAndContext(t *testing.T) {
+	ws := setupSearchWorkspace(t)
+	tool := NewSearchFilesTool(ws, true)
+
+	if res := tool.Execute(context.Background(), map[string]any{"pattern": "a+(b"}); !res.IsError {
+		t.Fatalf("invalid regex should error, got %q", res.ForLLM)
+	}
+	out := runSearch(t, tool, map[string]any{"pattern": "a+b", "literal": true})
+	if !strings.Contains(out, "notes.txt:1: a+b literal") {
+		t.Fatalf("literal match missing:\n%s", out)
+	}
+
+	out = runSearch(t, tool, map[string]any{"pattern": "println", "context_lines": 1, "path": "main.go"})
+	for _, want := range []string{"main.go-3- func main() {", "main.go:4: \tprintln", "main.go-5- }"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSearchFilesGlobOnlyAndCap(t *testing.T) {
+	ws := setupSearchWorkspace(t)
+	tool := NewSearchFilesTool(ws, true)
+
+	out := runSearch(t, tool, map[string]any{"glob": "*.md"})
+	if !strings.Contains(out, "docs/guide.md") || !strings.Contains(out, "docs/api/ref.md") || strings.Contains(out, "main.go") {
+		t.Fatalf("glob listing:\n%s", out)
+	}
+
+	out = runSearch(t, tool, map[string]any{"pattern": "e", "max_results": 1})
+	if !strings.Contains(out, "results capped at 1") {
+		t.Fatalf("expected cap notice:\n%s", out)
+	}
+
+	if res := tool.Execute(context.Background(), map[string]any{}); !res.IsError {
+		t.Fatal("missing pattern and glob should error")
+	}
+}
+
+func TestSearchFilesRestrictedWorkspace(t *testing.T) {
+	ws := setupSearchWorkspace(t)
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "leak.txt"), []byte("TODO outside\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tool := NewSearchFilesTool(ws, true)
+
+	if res := tool.Execute(context.Background(), map[string]any{"pattern": "TODO", "path": outside}); !res.IsError {
+		t.Fatalf("search outside workspace should be denied, got %q", res.ForLLM)
+	}
+
+	tool.AllowPaths(outside)
+	out := runSearch(t, tool, map[string]any{"pattern": "TODO", "path": outside})
+	if !strings.Contains(out, "leak.txt:1:") {
+		t.Fatalf("allowed prefix not searched:\n%s", out)
+	}
+
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require privileges on windows")
+	}
+	if err := os.Symlink(filepath.Join(outside, "leak.txt"), filepath.Join(ws, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+	out = runSearch(t, NewSearchFilesTool(ws, true), map[string]any{"pattern": "outside"})
+	if strings.Contains(out, "link.txt") {
+		t.Fatalf("symlinked file outside workspace was searched:\n%s", out)
+	}
+}
+
+func TestMatchSearchGlob(t *testing.T) {
+	tests := []struct {
+		glob, rel string
+		want      bool
+	}{
+		{"*.go", "cmd/main.go", true},
+		{"*.go", "main.md", false},
+		{"docs/*.md", "docs/a.md", true},
+		{"docs/*.md", "docs/x/a.md", false},
+		{"docs/**/*.md", "docs/a.md", true},
+		{"docs/**/*.md", "docs/x/y/a.md", true},
+		{"**/test_*.py", "a/b/test_x.py", true},
+	}
+	for _, tt := range tests {
+		if got := matchSearchGlob(tt.glob, tt.rel); got != tt.want {
+			t.Errorf("matchSearchGlob(%q, %q) = %v, want %v", tt.glob, tt.rel, got, tt.want)
+		}
+	}
+}
@@ -382,8 +382,11 @@ func splitOnce(s, sep string) []string {
 }
 
 func intArg(args map[string]any, key string, fallback int) int {
-	if v, ok := args[key].(float64); ok {
+	switch v := args[key].(type) {
+	case float64:
 		return int(v)
+	case int:
+		return v
 	}
 	return fallback
 }
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// KBSearchTool implements the kb_search tool: retrieval over the document
+// knowledge bases attached to the current agent, with citations.
+type KBSearchTool struct {
+	kbStore store.KnowledgeBaseStore
+}
+
+func NewKBSearchTool() *KBSearchTool {
+	return &KBSearchTool{}
+}
+
+// SetKnowledgeBaseStore enables knowledge base queries.
+func (t *KBSearchTool) SetKnowledgeBaseStore(s store.KnowledgeBaseStore) {
+	t.kbStore = s
+}
+
+func (t *KBSearchTool) Name() string { return "kb_search" }
+
+func (t *KBSearchTool) Description() string {
+	return "Search the document knowledge bases (uploaded manuals, policies, reference documents) attached to this agent. " +
+		"Returns numbered passages with their source file and page. Base answers on these passages and cite them " +
+		"as [file, page N]. If nothing relevant is found, say so instead of guessing. " +
+		"Query in the language the documents are written in."
+}
+
+func (t *KBSearchTool) Parameters() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"query": map[string]any{
+				"type":        "string",
+				"description": "Natural language search query.",
+			},
+			"collection": map[string]any{
+				"type":        "string",
+				"description": "Optional: name of one knowledge base to search. Omit to search all attached knowledge bases.",
+			},
+			"maxResults": map[string]any{
+				"type":        "number",
+				"description": "Maximum number of passages to return (default: 6)",
+			},
+		},
+		"required": []string{"query"},
+	}
+}
+
+func (t *KBSearchTool) Execute(ctx context.Context, args map[string]any) *Result {
+	query, _ := args["query"].(string)
+	if strings.TrimSpace(query) == "" {
+		return ErrorResult("query parameter is required")
+	}
+	var maxResults int
+	if mr, ok := args["maxResults"].(float64); ok {
+		maxResults = int(mr)
+	}
+
+	agentID := store.AgentIDFromContext(ctx)
+	if t.kbStore == nil || agentID == uuid.Nil {
+		return ErrorResult("knowledge base not available")
+	}
+
+	opts := store.KBSearchOptions{MaxResults: maxResults}
+	if name, _ := args["collection"].(string); strings.TrimSpace(name) != "" {
+		col, err := t.kbStore.GetCollectionByName(ctx, strings.TrimSpace(name))
+		if errors.Is(err, store.ErrKBCollectionNotFound) {
+			return ErrorResult(fmt.Sprintf("knowledge base %q not found", name))
+		}
+		if err != nil {
+			return ErrorResult(fmt.Sprintf("knowledge base search failed: %v", err))
+		}
+		opts.CollectionIDs = []uuid.UUID{col.ID}
+	}
+
+	results, err := t.kbStore.Search(ctx, query, agentID, opts)
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("knowledge base search failed: %v", err))
+	}
+	if len(results) == 0 {
+		cols, _ := t.kbStore.ListAgentCollections(ctx, agentID)
+		if len(cols) == 0 {
+			return NewResult("No knowledge bases are attached to this agent.")
+		}
+		return NewResult("No knowledge base passages found for query: " + query)
+	}
+	return NewResult(formatKBResults(results))
+}
+
+// formatKBResults renders passages as a numbered list with citations.
+func formatKBResults(results []store.KBSearchResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d passages. Cite sources as [file, page N].\n", len(results))
+	for i, r := range results {
+		fmt.Fprintf(&b, "\n[%d] %s", i+1, kbCitation(r))
+		fmt.Fprintf(&b, " (knowledge base: %s, score %.2f)\n", r.CollectionName, r.Score)
+		b.WriteString(strings.TrimSpace(r.Snippet))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// kbCitation returns "file, page N" or "file, lines A-B" for unpaged documents.
+func kbCitation(r store.KBSearchResult) string {
+	if r.Page > 0 {
+		return fmt.Sprintf("%s, page %d", r.FileName, r.Page)
+	}
+	return fmt.Sprintf("%s, lines %d-%d", r.FileName, r.StartLine, r.EndLine)
+}
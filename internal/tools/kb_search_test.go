@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// fakeKBStore implements the knowledge base methods kb_search calls.
+type fakeKBStore struct {
+	store.KnowledgeBaseStore
+	collections []store.KBCollectionData
+	results     []store.KBSearchResult
+	gotOpts     store.KBSearchOptions
+}
+
+func (f *fakeKBStore) GetCollectionByName(_ context.Context, name string) (*store.KBCollectionData, error) {
+	for i := range f.collections {
+		if strings.EqualFold(f.collections[i].Name, name) {
+			return &f.collections[i], nil
+		}
+	}
+	return nil, store.ErrKBCollectionNotFound
+}
+
+func (f *fakeKBStore) ListAgentCollections(context.Context, uuid.UUID) ([]store.KBCollectionData, error) {
+	return f.collections, nil
+}
+
+func (f *fakeKBStore) Search(_ context.Context, _ string, _ uuid.UUID, opts store.KBSearchOptions) ([]store.KBSearchResult, error) {
+	f.gotOpts = opts
+	return f.results, nil
+}
+
+func TestKBSearchTool_CitesSources(t *testing.T) {
+	colID := uuid.New()
+	fake := &fakeKBStore{
+		collections: []store.KBCollectionData{{BaseModel: store.BaseModel{ID: colID}, Name: "Manuals"}},
+		results: []store.KBSearchResult{
+			{CollectionName: "Manuals", FileName: "router.pdf", Page: 12, Score: 0.82, Snippet: "Hold reset for 10 seconds."},
+			{CollectionName: "Manuals", FileName: "faq.md", StartLine: 3, EndLine: 8, Score: 0.4, Snippet: "Factory reset erases settings."},
+		},
+	}
+	tool := NewKBSearchTool()
+	tool.SetKnowledgeBaseStore(fake)
+	ctx := store.WithAgentID(context.Background(), uuid.New())
+
+	res := tool.Execute(ctx, map[string]any{"query": "factory reset", "collection": "manuals"})
+	if res.IsError {
+		t.Fatalf("Execute error: %s", res.ForLLM)
+	}
+	for _, want := range []string{"[1] router.pdf, page 12", "[2] faq.md, lines 3-8", "Hold reset for 10 seconds."} {
+		if !strings.Contains(res.ForLLM, want) {
+			t.Errorf("output missing %q:\n%s", want, res.ForLLM)
+		}
+	}
+	if len(fake.gotOpts.CollectionIDs) != 1 || fake.gotOpts.CollectionIDs[0] != colID {
+		t.Errorf("collection filter = %v, want [%s]", fake.gotOpts.CollectionIDs, colID)
+	}
+
+	if res := tool.Execute(ctx, map[string]any{"query": "x", "collection": "nope"}); !res.IsError {
+		t.Error("unknown collection should be an error")
+	}
+
+	fake.results, fake.collections = nil, nil
+	if res := tool.Execute(ctx, map[string]any{"query": "x"}); !strings.Contains(res.ForLLM, "No knowledge bases are attached") {
+		t.Errorf("no collections = %q", res.ForLLM)
+	}
+}
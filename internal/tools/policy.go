@@ -15,9 +15,9 @@ var toolGroupsMu sync.RWMutex
 
 // Tool groups map group names to tool names.
 var toolGroups = map[string][]string{
-	"memory":     {"memory_search", "memory_get"},
+	"memory":     {"memory_search", "memory_get", "kb_search"},
 	"web":        {"web_search", "web_fetch"},
-	"fs":         {"read_file", "write_file", "list_files", "edit"},
+	"fs":         {"read_file", "write_file", "list_files", "search_files", "edit", "apply_patch"},
 	"runtime":    {"exec"},
 	"sessions":   {"sessions_list", "sessions_history", "sessions_send", "spawn", "session_status"},
 	"ui":         {"browser"},
@@ -26,9 +26,9 @@ var toolGroups = map[string][]string{
 	"team":       {"team_tasks"},
 	// Composite group: all goclaw native tools (excludes MCP/custom plugins).
 	"goclaw": {
-		"read_file", "write_file", "list_files", "edit", "exec",
+		"read_file", "write_file", "list_files", "search_files", "edit", "apply_patch", "exec",
 		"web_search", "web_fetch", "browser",
-		"memory_search", "memory_get",
+		"memory_search", "memory_get", "kb_search",
 		"sessions_list", "sessions_history", "sessions_send", "spawn", "session_status",
 		"cron", "message", "create_forum_topic", "list_group_members",
 		"read_image", "read_document", "read_audio", "read_video",
@@ -2,11 +2,13 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"strings"
 
+	"github.com/nextlevelbuilder/goclaw/internal/docextract"
 	"github.com/nextlevelbuilder/goclaw/internal/providers"
 )
 
@@ -74,9 +76,10 @@ func NewReadDocumentTool(registry *providers.Registry, mediaLoader MediaPathLoad
 func (t *ReadDocumentTool) Name() string { return "read_document" }
 
 func (t *ReadDocumentTool) Description() string {
-	return "Analyze documents (PDF, DOCX, images of documents, etc.) attached to the conversation. " +
+	return "Analyze documents (PDF, DOCX, XLSX, PPTX, images of documents, etc.) attached to the conversation. " +
 		"Use when you see <media:document> tags and need to extract or analyze document content. " +
-		"Specify what you want to extract or analyze."
+		"Text of PDF and Office files is extracted locally and returned for you to analyze; " +
+		"scanned PDFs and images are analyzed by a vision model. Specify what you want to extract or analyze."
 }
 
 func (t *ReadDocumentTool) Parameters() map[string]any {
@@ -91,6 +94,10 @@ func (t *ReadDocumentTool) Parameters() map[string]any {
 				"type":        "string",
 				"description": "Optional: specific media_id from <media:document> tag. If omitted, uses most recent document.",
 			},
+			"visual": map[string]any{
+				"type":        "boolean",
+				"description": "Optional: skip local text extraction and have a vision model read the document. Use when charts, images or layout matter.",
+			},
 		},
 		"required": []string{"prompt"},
 	}
@@ -102,6 +109,7 @@ func (t *ReadDocumentTool) Execute(ctx context.Context, args map[string]any) *Re
 		prompt = "Analyze this document and describe its contents."
 	}
 	mediaID, _ := args["media_id"].(string)
+	visual, _ := args["visual"].(bool)
 
 	// Resolve document file path from MediaRefs in context.
 	docPath, docMime, err := t.resolveDocumentFile(ctx, mediaID)
@@ -131,6 +139,13 @@ func (t *ReadDocumentTool) Execute(ctx context.Context, args map[string]any) *Re
 		return NewResult(content)
 	}
 
+	// Local extraction: PDF text layer and Office documents need no provider call.
+	if !visual {
+		if r := extractDocumentLocally(docPath, docMime, data); r != nil {
+			return r
+		}
+	}
+
 	chain := ResolveMediaProviderChain(ctx, "read_document", "", "",
 		documentProviderPriority, documentModelDefaults, t.registry)
 
@@ -155,3 +170,40 @@ func (t *ReadDocumentTool) Execute(ctx context.Context, args map[string]any) *Re
 	result.Model = chainResult.Model
 	return result
 }
+
+// extractDocumentLocally returns the document's text extracted in-process, or
+// nil when the format isn't supported or has no text layer (scanned PDFs), in
+// which case the caller falls back to the provider chain.
+func extractDocumentLocally(docPath, docMime string, data []byte) *Result {
+	format := docextract.FormatOf(docPath, docMime)
+	if format == "" {
+		return nil
+	}
+	doc, err := docextract.Extract(data, format, documentMaxTextBytes)
+	if err != nil {
+		if errors.Is(err, docextract.ErrNoText) {
+			slog.Info("read_document: no text layer, using provider", "format", format)
+		} else {
+			slog.Warn("read_document: local extraction failed, using provider", "format", format, "error", err)
+		}
+		return nil
+	}
+	slog.Info("read_document: extracted text locally", "format", format, "parts", doc.Parts, "chars", len(doc.Text))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Text extracted from %s document", strings.ToUpper(string(format)))
+	switch format {
+	case docextract.FormatPDF:
+		fmt.Fprintf(&b, ", %d pages", doc.Parts)
+	case docextract.FormatPPTX:
+		fmt.Fprintf(&b, ", %d slides", doc.Parts)
+	case docextract.FormatXLSX:
+		fmt.Fprintf(&b, ", %d sheets", doc.Parts)
+	}
+	b.WriteString(". Images and charts are not included; call read_document with visual=true if they matter.]\n\n")
+	b.WriteString(doc.Text)
+	if doc.Truncated {
+		b.WriteString("\n\n[... truncated at 500KB ...]")
+	}
+	return NewResult(b.String())
+}
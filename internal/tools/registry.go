@@ -20,8 +20,10 @@ type Registry struct {
 	aliases     map[string]string // alias name → canonical tool name
 	disabled    map[string]bool   // tools disabled via admin UI (kept in registry, excluded from List)
 	mu          sync.RWMutex
-	rateLimiter *ToolRateLimiter // nil = no rate limiting
-	scrubbing   bool             // scrub credentials from output (default true)
+	rateLimiter *ToolRateLimiter     // nil = no rate limiting
+	approvals   *ToolApprovalManager // nil = no human approval gate
+	guardrails  *ToolGuardrails      // nil = no content-aware argument/result rules
+	scrubbing   bool                 // scrub credentials from output (default true)
 
 	// deferredActivator is called when a tool is not in the registry but may be
 	// a deferred MCP tool. Returns true if the tool was successfully activated.
@@ -62,6 +64,17 @@ func (r *Registry) SetRateLimiter(rl *ToolRateLimiter) {
 	r.rateLimiter = rl
 }
 
+// SetApprovalManager gates tool calls matching the approval rules behind a human decision.
+func (r *Registry) SetApprovalManager(m *ToolApprovalManager) {
+	r.approvals = m
+}
+
+// SetGuardrails enables content-aware argument and result rules.
+// Per-agent rules (WithToolGuardrails) are only evaluated when guardrails are set.
+func (r *Registry) SetGuardrails(g *ToolGuardrails) {
+	r.guardrails = g
+}
+
 // SetScrubbing enables or disables credential scrubbing on tool output.
 func (r *Registry) SetScrubbing(enabled bool) {
 	r.scrubbing = enabled
@@ -188,6 +201,21 @@ func (r *Registry) ExecuteWithContext(ctx context.Context, name string, args map
 		}
 	}
 
+	// Content-aware argument rules (deny or rewrite before anyone sees the call)
+	if r.guardrails != nil {
+		var denied *Result
+		if args, denied = r.guardrails.CheckCall(ctx, tool, args); denied != nil {
+			return denied
+		}
+	}
+
+	// Human approval gate (blocks until approved, denied or timed out)
+	if r.approvals.Enabled() {
+		if denied := r.approvals.Gate(ctx, tool, args); denied != nil {
+			return denied
+		}
+	}
+
 	start := time.Now()
 	result := safeExecute(tool, ctx, args)
 	duration := time.Since(start)
@@ -202,6 +230,11 @@ func (r *Registry) ExecuteWithContext(ctx context.Context, name string, args map
 		}
 	}
 
+	// Content-aware result rules (redact before the output reaches the LLM)
+	if r.guardrails != nil {
+		r.guardrails.FilterResult(ctx, tool, args, result)
+	}
+
 	slog.Debug("tool executed",
 		"tool", name,
 		"duration_ms", duration.Milliseconds(),
@@ -309,7 +342,7 @@ func (r *Registry) Count() int {
 }
 
 // Clone creates a shallow copy of the registry with all registered tools and aliases.
-// The clone shares the rate limiter (thread-safe) and scrubbing setting.
+// The clone shares the rate limiter (thread-safe), approval gate, guardrails and scrubbing setting.
 // Used by subagent toolsFactory so subagents inherit parent tools (web_fetch, web_search, etc.).
 func (r *Registry) Clone() *Registry {
 	r.mu.RLock()
@@ -319,6 +352,8 @@ func (r *Registry) Clone() *Registry {
 		aliases:     make(map[string]string, len(r.aliases)),
 		disabled:    make(map[string]bool, len(r.disabled)),
 		rateLimiter: r.rateLimiter,
+		approvals:   r.approvals,
+		guardrails:  r.guardrails,
 		scrubbing:   r.scrubbing,
 	}
 	maps.Copy(clone.tools, r.tools)
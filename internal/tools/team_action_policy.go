@@ -18,6 +18,7 @@ var fullActions = []string{
 	"list", "get", "create", "claim", "complete", "cancel",
 	"approve", "reject", "search", "review", "comment",
 	"progress", "attach", "update", "ask_user", "clear_ask_user", "retry",
+	"template_list", "template_create", "template_update", "template_delete", "template_run",
 }
 
 func (FullTeamPolicy) IsAllowed(string) bool       { return true }
@@ -29,7 +30,7 @@ func (FullTeamPolicy) MemberGuidance() string {
 }
 
 // LiteTeamPolicy allows core lifecycle actions only (desktop/lite edition).
-// Blocked: comment, review, approve, reject, attach, ask_user, clear_ask_user.
+// Blocked: comment, review, approve, reject, attach, ask_user, clear_ask_user, template_*.
 type LiteTeamPolicy struct{}
 
 var liteActions = []string{
@@ -40,6 +41,8 @@ var liteActions = []string{
 var liteBlocked = map[string]bool{
 	"comment": true, "review": true, "approve": true, "reject": true,
 	"attach": true, "ask_user": true, "clear_ask_user": true,
+	"template_list": true, "template_create": true, "template_update": true,
+	"template_delete": true, "template_run": true,
 }
 
 func (LiteTeamPolicy) IsAllowed(action string) bool { return !liteBlocked[action] }
@@ -108,6 +108,14 @@ func WithProgress(percent int, step string) TaskEventOption {
 	}
 }
 
+// WithOverdue sets DueAt and EscalationLevel on the payload.
+func WithOverdue(dueAt time.Time, level int) TaskEventOption {
+	return func(p *protocol.TeamTaskEventPayload) {
+		p.DueAt = dueAt.UTC().Format("2006-01-02T15:04:05Z")
+		p.EscalationLevel = level
+	}
+}
+
 // WithContextInfo extracts UserID, Channel, ChatID, and PeerKind from the context
 // using standard tool context accessors.
 func WithContextInfo(ctx context.Context) TaskEventOption {
@@ -42,4 +42,5 @@ const (
 	TaskMetaOriginTrace    = "origin_trace_id"
 	TaskMetaOriginRootSpan = "origin_root_span_id"
 	TaskMetaTeamWorkspace  = "team_workspace"
+	TaskMetaTemplateID     = "template_id" // set on tasks instantiated from a task template
 )
@@ -176,6 +176,12 @@ func (b *baseNoopTeamStore) MarkInReviewStaleTasks(_ context.Context, _ time.Tim
 func (b *baseNoopTeamStore) FixOrphanedBlockedTasks(_ context.Context) ([]store.RecoveredTaskInfo, error) {
 	return nil, fmt.Errorf("not implemented: FixOrphanedBlockedTasks")
 }
+func (b *baseNoopTeamStore) ListAllOverdueTasks(_ context.Context) ([]store.RecoveredTaskInfo, error) {
+	return nil, fmt.Errorf("not implemented: ListAllOverdueTasks")
+}
+func (b *baseNoopTeamStore) MarkTaskEscalated(_ context.Context, _ uuid.UUID, _ *time.Time) error {
+	return fmt.Errorf("not implemented: MarkTaskEscalated")
+}
 
 // TaskFollowupStore
 func (b *baseNoopTeamStore) SetTaskFollowup(_ context.Context, _, _ uuid.UUID, _ time.Time, _ int, _, _, _ string) error {
@@ -217,6 +223,29 @@ func (b *baseNoopTeamStore) HasTeamAccess(_ context.Context, _ uuid.UUID, _ stri
 	return false, fmt.Errorf("not implemented: HasTeamAccess")
 }
 
+// TaskTemplateStore
+func (b *baseNoopTeamStore) CreateTaskTemplate(_ context.Context, _ *store.TeamTaskTemplateData) error {
+	return fmt.Errorf("not implemented: CreateTaskTemplate")
+}
+func (b *baseNoopTeamStore) GetTaskTemplate(_ context.Context, _ uuid.UUID) (*store.TeamTaskTemplateData, error) {
+	return nil, fmt.Errorf("not implemented: GetTaskTemplate")
+}
+func (b *baseNoopTeamStore) ListTaskTemplates(_ context.Context, _ uuid.UUID) ([]store.TeamTaskTemplateData, error) {
+	return nil, fmt.Errorf("not implemented: ListTaskTemplates")
+}
+func (b *baseNoopTeamStore) UpdateTaskTemplate(_ context.Context, _ *store.TeamTaskTemplateData) error {
+	return fmt.Errorf("not implemented: UpdateTaskTemplate")
+}
+func (b *baseNoopTeamStore) DeleteTaskTemplate(_ context.Context, _, _ uuid.UUID) error {
+	return fmt.Errorf("not implemented: DeleteTaskTemplate")
+}
+func (b *baseNoopTeamStore) ListDueTaskTemplates(_ context.Context, _ time.Time) ([]store.TeamTaskTemplateData, error) {
+	return nil, fmt.Errorf("not implemented: ListDueTaskTemplates")
+}
+func (b *baseNoopTeamStore) MarkTaskTemplateRun(_ context.Context, _ uuid.UUID, _ time.Time, _ *time.Time) error {
+	return fmt.Errorf("not implemented: MarkTaskTemplateRun")
+}
+
 // ============================================================
 // mockTaskStore — in-memory TeamStore for unit tests
 // ============================================================
@@ -292,7 +321,9 @@ func (s *mockTaskStore) CreateTask(_ context.Context, task *store.TeamTaskData)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	now := time.Now()
-	task.ID = uuid.New()
+	if task.ID == uuid.Nil {
+		task.ID = uuid.New()
+	}
 	s.taskSeq++
 	task.TaskNumber = s.taskSeq
 	task.CreatedAt = now
@@ -0,0 +1,191 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// SLAConfig controls task due dates derived from priority and the overdue
+// escalation schedule run by the task ticker.
+//
+// Team settings example:
+//
+//	"sla": {
+//	  "targets_minutes": {"2": 120, "1": 480, "default": 1440},
+//	  "at_risk_percent": 20,
+//	  "escalation_interval_minutes": 60,
+//	  "max_escalations": 3,
+//	  "notify_assignee": true
+//	}
+type SLAConfig struct {
+	Targets                   map[int]int `json:"targets_minutes"`             // priority → minutes to due
+	DefaultMinutes            int         `json:"default_minutes"`             // fallback target (0 = no SLA)
+	AtRiskPercent             int         `json:"at_risk_percent"`             // default: 20
+	EscalationIntervalMinutes int         `json:"escalation_interval_minutes"` // default: 60
+	MaxEscalations            int         `json:"max_escalations"`             // default: 3 (0 = unlimited)
+	NotifyAssignee            bool        `json:"notify_assignee"`             // default: true
+}
+
+// DefaultSLAConfig returns the default config: no SLA targets (only explicit
+// due dates are tracked), hourly escalation, at most 3 escalations.
+func DefaultSLAConfig() SLAConfig {
+	return SLAConfig{
+		AtRiskPercent:             20,
+		EscalationIntervalMinutes: 60,
+		MaxEscalations:            3,
+		NotifyAssignee:            true,
+	}
+}
+
+// ParseSLAConfig extracts SLA config from team settings.
+func ParseSLAConfig(settings json.RawMessage) SLAConfig {
+	cfg := DefaultSLAConfig()
+	if len(settings) == 0 {
+		return cfg
+	}
+	var s struct {
+		SLA *struct {
+			Targets                   map[string]int `json:"targets_minutes"`
+			AtRiskPercent             *int           `json:"at_risk_percent"`
+			EscalationIntervalMinutes *int           `json:"escalation_interval_minutes"`
+			MaxEscalations            *int           `json:"max_escalations"`
+			NotifyAssignee            *bool          `json:"notify_assignee"`
+		} `json:"sla"`
+	}
+	if json.Unmarshal(settings, &s) != nil || s.SLA == nil {
+		return cfg
+	}
+	for k, v := range s.SLA.Targets {
+		if v <= 0 {
+			continue
+		}
+		if k == "default" {
+			cfg.DefaultMinutes = v
+			continue
+		}
+		if p, err := strconv.Atoi(k); err == nil {
+			if cfg.Targets == nil {
+				cfg.Targets = make(map[int]int)
+			}
+			cfg.Targets[p] = v
+		}
+	}
+	if v := s.SLA.AtRiskPercent; v != nil && *v >= 0 && *v < 100 {
+		cfg.AtRiskPercent = *v
+	}
+	if v := s.SLA.EscalationIntervalMinutes; v != nil && *v > 0 {
+		cfg.EscalationIntervalMinutes = *v
+	}
+	if v := s.SLA.MaxEscalations; v != nil && *v >= 0 {
+		cfg.MaxEscalations = *v
+	}
+	if s.SLA.NotifyAssignee != nil {
+		cfg.NotifyAssignee = *s.SLA.NotifyAssignee
+	}
+	return cfg
+}
+
+// TargetMinutes returns the SLA target for a priority (0 = no SLA).
+func (c SLAConfig) TargetMinutes(priority int) int {
+	if m, ok := c.Targets[priority]; ok {
+		return m
+	}
+	return c.DefaultMinutes
+}
+
+// ApplyDue sets DueAt, SLAMinutes and AtRiskAt on a task. An explicit due time
+// wins; otherwise the priority's SLA target (if any) is counted from now.
+// Escalation state is reset so a new due date starts a fresh schedule.
+func (c SLAConfig) ApplyDue(task *store.TeamTaskData, due *time.Time, now time.Time) {
+	task.EscalationCount = 0
+	task.NextEscalationAt = nil
+	task.SLAMinutes = 0
+	if due == nil {
+		mins := c.TargetMinutes(task.Priority)
+		if mins <= 0 {
+			task.DueAt = nil
+			task.AtRiskAt = nil
+			return
+		}
+		d := now.Add(time.Duration(mins) * time.Minute)
+		due = &d
+		task.SLAMinutes = mins
+	}
+	dueAt := due.UTC()
+	task.DueAt = &dueAt
+
+	// At-risk window is a share of the time remaining until due.
+	window := dueAt.Sub(now) * time.Duration(c.AtRiskPercent) / 100
+	if window < 0 {
+		window = 0
+	}
+	atRisk := dueAt.Add(-window)
+	task.AtRiskAt = &atRisk
+}
+
+// DueUpdates returns the UpdateTask column map for the task's due/SLA fields.
+func DueUpdates(task *store.TeamTaskData) map[string]any {
+	return map[string]any{
+		"due_at":             task.DueAt,
+		"sla_minutes":        task.SLAMinutes,
+		"at_risk_at":         task.AtRiskAt,
+		"escalation_count":   task.EscalationCount,
+		"next_escalation_at": task.NextEscalationAt,
+	}
+}
+
+// NextEscalation returns when to escalate again after sending escalation
+// number `sent` (1-based), or nil when max_escalations has been reached.
+func (c SLAConfig) NextEscalation(sent int, now time.Time) *time.Time {
+	if c.MaxEscalations > 0 && sent >= c.MaxEscalations {
+		return nil
+	}
+	next := now.Add(time.Duration(c.EscalationIntervalMinutes) * time.Minute)
+	return &next
+}
+
+// ParseDueAt parses a due date given as RFC 3339 ("2026-05-01T17:00:00Z"),
+// a plain date ("2026-05-01", end of that day UTC) or a duration relative to
+// now ("90m", "4h", "2d", "1w"). Dates that are not after now are rejected,
+// so the tools and the RPC layer apply the same rule.
+func ParseDueAt(s string, now time.Time) (time.Time, error) {
+	t, err := parseDueAt(s, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("due date %s is not in the future", t.Format(time.RFC3339))
+	}
+	return t, nil
+}
+
+func parseDueAt(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty due date")
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	unit := s[len(s)-1]
+	if n, err := strconv.Atoi(s[:len(s)-1]); err == nil && n > 0 {
+		switch unit {
+		case 'd':
+			return now.Add(time.Duration(n) * 24 * time.Hour).UTC(), nil
+		case 'w':
+			return now.Add(time.Duration(n) * 7 * 24 * time.Hour).UTC(), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(d).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid due date %q: use RFC 3339 (2026-05-01T17:00:00Z), a date (2026-05-01) or a relative duration (4h, 2d, 1w)", s)
+}
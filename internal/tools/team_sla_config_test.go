@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestParseSLAConfig(t *testing.T) {
+	cfg := ParseSLAConfig(nil)
+	if cfg.TargetMinutes(5) != 0 || cfg.MaxEscalations != 3 || cfg.EscalationIntervalMinutes != 60 || !cfg.NotifyAssignee {
+		t.Fatalf("unexpected defaults: %+v", cfg)
+	}
+
+	cfg = ParseSLAConfig(json.RawMessage(`{"sla":{
+		"targets_minutes":{"2":120,"1":480,"default":1440,"bogus":5},
+		"at_risk_percent":50,"escalation_interval_minutes":30,"max_escalations":0,"notify_assignee":false}}`))
+	if got := cfg.TargetMinutes(2); got != 120 {
+		t.Errorf("priority 2 target = %d", got)
+	}
+	if got := cfg.TargetMinutes(7); got != 1440 {
+		t.Errorf("default target = %d", got)
+	}
+	if cfg.AtRiskPercent != 50 || cfg.EscalationIntervalMinutes != 30 || cfg.MaxEscalations != 0 || cfg.NotifyAssignee {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestSLAConfigApplyDue(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	cfg := ParseSLAConfig(json.RawMessage(`{"sla":{"targets_minutes":{"1":100}}}`))
+
+	task := &store.TeamTaskData{Priority: 1, EscalationCount: 2}
+	cfg.ApplyDue(task, nil, now)
+	if task.SLAMinutes != 100 || !task.DueAt.Equal(now.Add(100*time.Minute)) {
+		t.Fatalf("due = %v, sla = %d", task.DueAt, task.SLAMinutes)
+	}
+	// Default 20% at-risk window: 80 minutes in.
+	if !task.AtRiskAt.Equal(now.Add(80 * time.Minute)) {
+		t.Errorf("at_risk_at = %v", task.AtRiskAt)
+	}
+	if task.EscalationCount != 0 {
+		t.Errorf("escalation count not reset: %d", task.EscalationCount)
+	}
+
+	// No target for this priority → no due date.
+	task = &store.TeamTaskData{Priority: 0}
+	cfg.ApplyDue(task, nil, now)
+	if task.DueAt != nil || task.AtRiskAt != nil {
+		t.Errorf("expected no due date, got %v", task.DueAt)
+	}
+
+	// Explicit due date wins over the SLA target.
+	due := now.Add(10 * time.Hour)
+	task = &store.TeamTaskData{Priority: 1}
+	cfg.ApplyDue(task, &due, now)
+	if !task.DueAt.Equal(due) || task.SLAMinutes != 0 || !task.AtRiskAt.Equal(now.Add(8*time.Hour)) {
+		t.Errorf("explicit: due=%v sla=%d at_risk=%v", task.DueAt, task.SLAMinutes, task.AtRiskAt)
+	}
+}
+
+func TestSLAConfigNextEscalation(t *testing.T) {
+	now := time.Now()
+	cfg := DefaultSLAConfig()
+	if next := cfg.NextEscalation(1, now); next == nil || !next.Equal(now.Add(time.Hour)) {
+		t.Errorf("next after 1st = %v", next)
+	}
+	if next := cfg.NextEscalation(3, now); next != nil {
+		t.Errorf("expected nil after max escalations, got %v", next)
+	}
+	cfg.MaxEscalations = 0
+	if next := cfg.NextEscalation(10, now); next == nil {
+		t.Error("max_escalations=0 should escalate indefinitely")
+	}
+}
+
+func TestParseDueAt(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-05-03T09:30:00Z", time.Date(2026, 5, 3, 9, 30, 0, 0, time.UTC)},
+		{"2026-05-03", time.Date(2026, 5, 3, 23, 59, 59, 0, time.UTC)},
+		{"90m", now.Add(90 * time.Minute)},
+		{"4h", now.Add(4 * time.Hour)},
+		{"2d", now.Add(48 * time.Hour)},
+		{"1w", now.Add(7 * 24 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := ParseDueAt(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseDueAt(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "tomorrow", "-2h", "0d", "2026-04-30", "2026-05-01T11:00:00Z"} {
+		if _, err := ParseDueAt(bad, now); err == nil {
+			t.Errorf("ParseDueAt(%q) should fail", bad)
+		}
+	}
+}
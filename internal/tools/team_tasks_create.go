@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -62,6 +63,16 @@ func (t *TeamTasksTool) executeCreate(ctx context.Context, args map[string]any)
 		priority = int(p)
 	}
 
+	now := time.Now()
+	var dueAt *time.Time
+	if raw, _ := args["due_at"].(string); raw != "" {
+		d, err := ParseDueAt(raw, now)
+		if err != nil {
+			return ErrorResult(err.Error())
+		}
+		dueAt = &d
+	}
+
 	var blockedBy []uuid.UUID
 	if raw, ok := args["blocked_by"].([]any); ok {
 		for _, v := range raw {
@@ -220,6 +231,8 @@ func (t *TeamTasksTool) executeCreate(ctx context.Context, args map[string]any)
 		Metadata:         taskMeta,
 	}
 	task.OwnerAgentID = &assigneeID
+	// Explicit due_at wins; otherwise the team's per-priority SLA target applies.
+	ParseSLAConfig(team.Settings).ApplyDue(task, dueAt, now)
 
 	// Auto-link member request to the member's current task as parent.
 	if !isLead && taskType == "request" {
@@ -312,6 +325,9 @@ func (t *TeamTasksTool) executeCreate(ctx context.Context, args map[string]any)
 		assigneeName = t.manager.AgentKeyFromID(ctx, assigneeID)
 	}
 	msg := fmt.Sprintf("Task created: %s (id=%s, task_number=%d, status=%s, assignee=%s)", subject, task.ID, task.TaskNumber, status, assigneeName)
+	if task.DueAt != nil {
+		msg += fmt.Sprintf("\nDue: %s", task.DueAt.Format(time.RFC3339))
+	}
 
 	// Soft guardrail: warn if subject suggests multiple deliverables.
 	// Only checks subject (not description) — detailed descriptions are fine.
@@ -425,3 +425,80 @@ func TestCreate(t *testing.T) {
 		}
 	})
 }
+
+func TestCreateDueAt(t *testing.T) {
+	createdTask := func(mb *mockBackend) *store.TeamTaskData {
+		mb.taskStore.mu.Lock()
+		defer mb.taskStore.mu.Unlock()
+		for _, v := range mb.taskStore.tasks {
+			return v
+		}
+		return nil
+	}
+
+	t.Run("Explicit", func(t *testing.T) {
+		mb, tool, _, _, ctx := newTestTeamSetup()
+		ptd := NewPendingTeamDispatch()
+		ptd.MarkListed()
+		ctx = WithPendingTeamDispatch(ctx, ptd)
+
+		result := tool.Execute(ctx, map[string]any{
+			"action":   "create",
+			"subject":  "Ship report",
+			"assignee": "member-agent",
+			"due_at":   "2h",
+		})
+		if result.IsError {
+			t.Fatalf("unexpected error: %s", result.ForLLM)
+		}
+		task := createdTask(mb)
+		if task.DueAt == nil || time.Until(*task.DueAt) < 110*time.Minute {
+			t.Fatalf("expected due_at ~2h from now, got %v", task.DueAt)
+		}
+		if task.SLAMinutes != 0 {
+			t.Errorf("explicit due_at should not record an SLA target, got %d", task.SLAMinutes)
+		}
+		if !strings.Contains(result.ForLLM, "Due: ") {
+			t.Errorf("expected due date in response, got: %s", result.ForLLM)
+		}
+	})
+
+	t.Run("PrioritySLA", func(t *testing.T) {
+		mb, tool, _, _, ctx := newTestTeamSetup()
+		mb.team.Settings = json.RawMessage(`{"sla":{"targets_minutes":{"2":60,"default":1440}}}`)
+		ptd := NewPendingTeamDispatch()
+		ptd.MarkListed()
+		ctx = WithPendingTeamDispatch(ctx, ptd)
+
+		result := tool.Execute(ctx, map[string]any{
+			"action":   "create",
+			"subject":  "Hotfix",
+			"assignee": "member-agent",
+			"priority": float64(2),
+		})
+		if result.IsError {
+			t.Fatalf("unexpected error: %s", result.ForLLM)
+		}
+		task := createdTask(mb)
+		if task.SLAMinutes != 60 || task.DueAt == nil || task.AtRiskAt == nil {
+			t.Fatalf("expected 60m SLA with due/at-risk set, got sla=%d due=%v at_risk=%v", task.SLAMinutes, task.DueAt, task.AtRiskAt)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, tool, _, _, ctx := newTestTeamSetup()
+		ptd := NewPendingTeamDispatch()
+		ptd.MarkListed()
+		ctx = WithPendingTeamDispatch(ctx, ptd)
+
+		result := tool.Execute(ctx, map[string]any{
+			"action":   "create",
+			"subject":  "Whenever",
+			"assignee": "member-agent",
+			"due_at":   "soonish",
+		})
+		if !result.IsError || !strings.Contains(result.ForLLM, "invalid due date") {
+			t.Fatalf("expected invalid due date error, got: %s", result.ForLLM)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"path/filepath"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -298,8 +299,30 @@ func (t *TeamTasksTool) executeUpdate(ctx context.Context, args map[string]any)
 		}
 		updates["blocked_by"] = blockedBy
 	}
+	if raw, ok := args["due_at"].(string); ok && raw != "" {
+		// A new due date restarts the escalation schedule; "none" clears it.
+		now := time.Now()
+		var dueAt *time.Time
+		if raw != "none" {
+			d, err := ParseDueAt(raw, now)
+			if err != nil {
+				return ErrorResult(err.Error())
+			}
+			dueAt = &d
+		}
+		due := *task
+		if dueAt != nil {
+			ParseSLAConfig(team.Settings).ApplyDue(&due, dueAt, now)
+		} else {
+			due.DueAt, due.AtRiskAt, due.NextEscalationAt = nil, nil, nil
+			due.SLAMinutes, due.EscalationCount = 0, 0
+		}
+		for col, v := range DueUpdates(&due) {
+			updates[col] = v
+		}
+	}
 	if len(updates) == 0 {
-		return ErrorResult("no updates provided (set description, subject, or blocked_by)")
+		return ErrorResult("no updates provided (set description, subject, blocked_by, or due_at)")
 	}
 
 	if err := t.manager.Store().UpdateTask(ctx, taskID, updates); err != nil {
@@ -39,6 +39,7 @@ type taskListItem struct {
 	ProgressPercent      int              `json:"progress_percent,omitempty"`
 	ProgressStep         string           `json:"progress_step,omitempty"`
 	BlockedBy            []blockerSummary `json:"blocked_by,omitempty"`
+	DueAt                *time.Time       `json:"due_at,omitempty"`
 	CreatedAt            time.Time        `json:"created_at"`
 }
 
@@ -58,6 +59,8 @@ type taskDetailItem struct {
 	ProgressStep         string           `json:"progress_step,omitempty"`
 	BlockedBy            []blockerSummary `json:"blocked_by,omitempty"`
 	Priority             int              `json:"priority"`
+	DueAt                *time.Time       `json:"due_at,omitempty"`
+	EscalationCount      int              `json:"escalation_count,omitempty"`
 	CreatedAt            time.Time        `json:"created_at"`
 	UpdatedAt            time.Time        `json:"updated_at"`
 }
@@ -119,6 +122,7 @@ func (t *TeamTasksTool) toListItem(ctx context.Context, task store.TeamTaskData)
 		ProgressPercent:      task.ProgressPercent,
 		ProgressStep:         task.ProgressStep,
 		BlockedBy:            t.resolveBlockers(ctx, task.BlockedBy),
+		DueAt:                task.DueAt,
 		CreatedAt:            task.CreatedAt,
 	}
 }
@@ -139,6 +143,8 @@ func (t *TeamTasksTool) toDetailItem(ctx context.Context, task *store.TeamTaskDa
 		ProgressStep:         task.ProgressStep,
 		BlockedBy:            t.resolveBlockers(ctx, task.BlockedBy),
 		Priority:             task.Priority,
+		DueAt:                task.DueAt,
+		EscalationCount:      task.EscalationCount,
 		CreatedAt:            task.CreatedAt,
 		UpdatedAt:            task.UpdatedAt,
 	}
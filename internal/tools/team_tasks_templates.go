@@ -0,0 +1,385 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// templateListItem is the compact template shape returned to agents.
+type templateListItem struct {
+	ID        string                `json:"id"`
+	Name      string                `json:"name"`
+	Subject   string                `json:"subject"`
+	Owner     string                `json:"owner,omitempty"`
+	Priority  int                   `json:"priority,omitempty"`
+	Subtasks  []templateSubtaskItem `json:"subtasks,omitempty"`
+	Schedule  string                `json:"schedule,omitempty"`
+	Timezone  string                `json:"timezone,omitempty"`
+	Enabled   bool                  `json:"enabled"`
+	NextRunAt string                `json:"next_run_at,omitempty"`
+	LastRunAt string                `json:"last_run_at,omitempty"`
+	RunCount  int                   `json:"run_count,omitempty"`
+}
+
+type templateSubtaskItem struct {
+	Key       string   `json:"key"`
+	Subject   string   `json:"subject"`
+	Owner     string   `json:"owner,omitempty"`
+	BlockedBy []string `json:"blocked_by,omitempty"`
+}
+
+func (t *TeamTasksTool) executeTemplateList(ctx context.Context, _ map[string]any) *Result {
+	team, _, err := t.manager.ResolveTeam(ctx)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	tpls, err := t.manager.Store().ListTaskTemplates(ctx, team.ID)
+	if err != nil {
+		return ErrorResult("failed to list templates: " + err.Error())
+	}
+
+	items := make([]templateListItem, 0, len(tpls))
+	for _, tpl := range tpls {
+		item := templateListItem{
+			ID:       tpl.ID.String(),
+			Name:     tpl.Name,
+			Subject:  tpl.Subject,
+			Owner:    tpl.OwnerAgentKey,
+			Priority: tpl.Priority,
+			Schedule: tpl.Schedule,
+			Timezone: tpl.Timezone,
+			Enabled:  tpl.Enabled,
+			RunCount: tpl.RunCount,
+		}
+		if tpl.NextRunAt != nil {
+			item.NextRunAt = tpl.NextRunAt.UTC().Format(time.RFC3339)
+		}
+		if tpl.LastRunAt != nil {
+			item.LastRunAt = tpl.LastRunAt.UTC().Format(time.RFC3339)
+		}
+		for _, sub := range tpl.Subtasks {
+			si := templateSubtaskItem{Key: sub.Key, Subject: sub.Subject, BlockedBy: sub.BlockedBy}
+			if sub.OwnerAgentID != nil {
+				si.Owner = t.manager.AgentKeyFromID(ctx, *sub.OwnerAgentID)
+			}
+			item.Subtasks = append(item.Subtasks, si)
+		}
+		items = append(items, item)
+	}
+
+	out, _ := json.Marshal(map[string]any{"templates": items, "count": len(items)})
+	return SilentResult(string(out))
+}
+
+func (t *TeamTasksTool) executeTemplateCreate(ctx context.Context, args map[string]any) *Result {
+	team, agentID, err := t.manager.ResolveTeam(ctx)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if err := t.manager.RequireLead(ctx, team, agentID); err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	tpl := &store.TeamTaskTemplateData{
+		TeamID:    team.ID,
+		Enabled:   true,
+		CreatedBy: store.UserIDFromContext(ctx),
+	}
+	if err := t.applyTemplateArgs(ctx, tpl, args); err != nil {
+		return ErrorResult(err.Error())
+	}
+	if tpl.OwnerAgentID == nil {
+		return ErrorResult("assignee is required — the template's default owner for its tasks")
+	}
+	if err := t.checkTemplate(ctx, team, agentID, tpl); err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	if err := t.manager.Store().CreateTaskTemplate(ctx, tpl); err != nil {
+		return ErrorResult("failed to create template: " + err.Error())
+	}
+
+	msg := fmt.Sprintf("Template created: %s (id=%s, %d subtask(s))", tpl.Name, tpl.ID, len(tpl.Subtasks))
+	if tpl.NextRunAt != nil {
+		msg += fmt.Sprintf("\nSchedule: %s — next run %s", tpl.Schedule, tpl.NextRunAt.UTC().Format(time.RFC3339))
+	} else {
+		msg += "\nNo schedule — run it with team_tasks(action=\"template_run\", template_id=\"" + tpl.ID.String() + "\")."
+	}
+	return NewResult(msg)
+}
+
+func (t *TeamTasksTool) executeTemplateUpdate(ctx context.Context, args map[string]any) *Result {
+	team, agentID, err := t.manager.ResolveTeam(ctx)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if err := t.manager.RequireLead(ctx, team, agentID); err != nil {
+		return ErrorResult(err.Error())
+	}
+	tpl, err := t.resolveTemplate(ctx, team, args)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if err := t.applyTemplateArgs(ctx, tpl, args); err != nil {
+		return ErrorResult(err.Error())
+	}
+	if err := t.checkTemplate(ctx, team, agentID, tpl); err != nil {
+		return ErrorResult(err.Error())
+	}
+	if err := t.manager.Store().UpdateTaskTemplate(ctx, tpl); err != nil {
+		return ErrorResult("failed to update template: " + err.Error())
+	}
+
+	msg := fmt.Sprintf("Template %s updated.", tpl.Name)
+	if tpl.NextRunAt != nil {
+		msg += fmt.Sprintf(" Next run: %s", tpl.NextRunAt.UTC().Format(time.RFC3339))
+	}
+	return NewResult(msg)
+}
+
+func (t *TeamTasksTool) executeTemplateDelete(ctx context.Context, args map[string]any) *Result {
+	team, agentID, err := t.manager.ResolveTeam(ctx)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if err := t.manager.RequireLead(ctx, team, agentID); err != nil {
+		return ErrorResult(err.Error())
+	}
+	tpl, err := t.resolveTemplate(ctx, team, args)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if err := t.manager.Store().DeleteTaskTemplate(ctx, tpl.ID, team.ID); err != nil {
+		return ErrorResult("failed to delete template: " + err.Error())
+	}
+	return NewResult(fmt.Sprintf("Template %s deleted. Tasks already created from it are kept.", tpl.Name))
+}
+
+// executeTemplateRun instantiates a template now, independent of its schedule.
+func (t *TeamTasksTool) executeTemplateRun(ctx context.Context, args map[string]any) *Result {
+	team, agentID, err := t.manager.ResolveTeam(ctx)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+	if err := t.manager.RequireLead(ctx, team, agentID); err != nil {
+		return ErrorResult(err.Error())
+	}
+	tpl, err := t.resolveTemplate(ctx, team, args)
+	if err != nil {
+		return ErrorResult(err.Error())
+	}
+
+	chatID := ToolChatIDFromCtx(ctx)
+	meta := map[string]any{
+		TaskMetaTeamWorkspace: ResolveWorkspace(t.manager.DataDir(),
+			TenantLayer(store.TenantIDFromContext(ctx), store.TenantSlugFromContext(ctx)),
+			TeamLayer(team.ID),
+			UserChatLayer(chatID, IsSharedWorkspace(team.Settings)),
+		),
+	}
+	if pk := ToolPeerKindFromCtx(ctx); pk != "" {
+		meta[TaskMetaPeerKind] = pk
+	}
+	if lk := ToolLocalKeyFromCtx(ctx); lk != "" {
+		meta[TaskMetaLocalKey] = lk
+	}
+	if sk := ToolSessionKeyFromCtx(ctx); sk != "" {
+		meta[TaskMetaOriginSession] = sk
+	}
+
+	now := time.Now()
+	created, err := InstantiateTaskTemplate(ctx, t.manager.Store(), team, tpl, TemplateRunOptions{
+		UserID:           store.UserIDFromContext(ctx),
+		Channel:          ToolChannelFromCtx(ctx),
+		ChatID:           chatID,
+		CreatedByAgentID: &agentID,
+		Metadata:         meta,
+		Now:              now,
+	})
+	if err != nil {
+		return ErrorResult(fmt.Sprintf("failed to run template (%d task(s) created): %v", len(created), err))
+	}
+	// A manual run does not move the schedule.
+	if err := t.manager.Store().MarkTaskTemplateRun(ctx, tpl.ID, now, tpl.NextRunAt); err != nil {
+		slog.Warn("team_tasks.template_run: mark run failed", "template_id", tpl.ID, "error", err)
+	}
+
+	agentKey := t.manager.AgentKeyFromID(ctx, agentID)
+	ptd := PendingTeamDispatchFromCtx(ctx)
+	var lines []string
+	for _, task := range created {
+		t.manager.BroadcastTeamEvent(ctx, protocol.EventTeamTaskCreated, BuildTaskEventPayload(
+			team.ID.String(), task.ID.String(),
+			task.Status,
+			"agent", agentKey,
+			WithSubject(task.Subject),
+			WithContextInfo(ctx),
+			WithTimestamp(task.CreatedAt.UTC().Format("2006-01-02T15:04:05Z")),
+		))
+		lines = append(lines, fmt.Sprintf("  - #%d %s (id=%s, status=%s, assignee=%s)",
+			task.TaskNumber, task.Subject, task.ID, task.Status, t.manager.AgentKeyFromID(ctx, *task.OwnerAgentID)))
+
+		if task.Status != store.TeamTaskStatusPending {
+			continue // blocked — dispatched when its blockers complete
+		}
+		if ptd != nil {
+			ptd.Add(team.ID, task.ID)
+		} else if err := t.manager.Store().AssignTask(ctx, task.ID, *task.OwnerAgentID, team.ID); err != nil {
+			slog.Warn("team_tasks.template_run: fallback assign failed", "task_id", task.ID, "error", err)
+		} else {
+			t.manager.BroadcastTeamEvent(ctx, protocol.EventTeamTaskDispatched, BuildTaskEventPayload(
+				team.ID.String(), task.ID.String(),
+				store.TeamTaskStatusInProgress,
+				"system", "fallback_dispatch",
+				WithTaskInfo(task.TaskNumber, task.Subject),
+				WithOwnerAgentKey(t.manager.AgentKeyFromID(ctx, *task.OwnerAgentID)),
+				WithChannel(task.Channel),
+				WithChatID(task.ChatID),
+			))
+			t.manager.DispatchTaskToAgent(ctx, task, team, *task.OwnerAgentID)
+		}
+	}
+
+	return NewResult(fmt.Sprintf("Template %s instantiated — %d task(s) created:\n%s",
+		tpl.Name, len(created), strings.Join(lines, "\n")))
+}
+
+// resolveTemplate finds a template of the team by template_id (UUID or name).
+func (t *TeamTasksTool) resolveTemplate(ctx context.Context, team *store.TeamData, args map[string]any) (*store.TeamTaskTemplateData, error) {
+	ref, _ := args["template_id"].(string)
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return nil, fmt.Errorf("template_id is required (template UUID or name)")
+	}
+	if id, err := uuid.Parse(ref); err == nil {
+		tpl, err := t.manager.Store().GetTaskTemplate(ctx, id)
+		if err != nil {
+			if errors.Is(err, store.ErrTaskTemplateNotFound) {
+				return nil, fmt.Errorf("template %s not found", ref)
+			}
+			return nil, err
+		}
+		if tpl.TeamID != team.ID {
+			return nil, fmt.Errorf("template %s not found", ref)
+		}
+		return tpl, nil
+	}
+	tpls, err := t.manager.Store().ListTaskTemplates(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tpls {
+		if strings.EqualFold(tpls[i].Name, ref) {
+			return &tpls[i], nil
+		}
+	}
+	return nil, fmt.Errorf("template %q not found — use team_tasks(action=\"template_list\")", ref)
+}
+
+// applyTemplateArgs copies the template fields present in args onto tpl.
+func (t *TeamTasksTool) applyTemplateArgs(ctx context.Context, tpl *store.TeamTaskTemplateData, args map[string]any) error {
+	if v, ok := args["name"].(string); ok && v != "" {
+		tpl.Name = strings.TrimSpace(v)
+	}
+	if v, ok := args["subject"].(string); ok && v != "" {
+		tpl.Subject = v
+	}
+	if v, ok := args["description"].(string); ok {
+		tpl.Description = v
+	}
+	if p, ok := args["priority"].(float64); ok {
+		tpl.Priority = int(p)
+	}
+	if key, ok := args["assignee"].(string); ok && key != "" {
+		id, err := t.manager.ResolveAgentByKey(ctx, key)
+		if err != nil {
+			return fmt.Errorf("assignee %q not found: %v", key, err)
+		}
+		tpl.OwnerAgentID = &id
+	}
+	if v, ok := args["schedule"].(string); ok {
+		if v = strings.TrimSpace(v); v == "none" {
+			v = ""
+		}
+		tpl.Schedule = v
+	}
+	if v, ok := args["timezone"].(string); ok {
+		tpl.Timezone = strings.TrimSpace(v)
+	}
+	if v, ok := args["enabled"].(bool); ok {
+		tpl.Enabled = v
+	}
+	if raw, ok := args["subtasks"].([]any); ok {
+		subtasks, err := t.parseTemplateSubtasks(ctx, raw)
+		if err != nil {
+			return err
+		}
+		tpl.Subtasks = subtasks
+	}
+	return nil
+}
+
+func (t *TeamTasksTool) parseTemplateSubtasks(ctx context.Context, raw []any) ([]store.TeamTaskTemplateSubtask, error) {
+	subtasks := make([]store.TeamTaskTemplateSubtask, 0, len(raw))
+	for i, v := range raw {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("subtasks[%d] must be an object with key, subject, description?, assignee?, blocked_by?", i)
+		}
+		sub := store.TeamTaskTemplateSubtask{}
+		sub.Key, _ = m["key"].(string)
+		if sub.Key == "" {
+			sub.Key = strconv.Itoa(i + 1)
+		}
+		sub.Subject, _ = m["subject"].(string)
+		sub.Description, _ = m["description"].(string)
+		if p, ok := m["priority"].(float64); ok {
+			sub.Priority = int(p)
+		}
+		if key, _ := m["assignee"].(string); key != "" {
+			id, err := t.manager.ResolveAgentByKey(ctx, key)
+			if err != nil {
+				return nil, fmt.Errorf("subtask %q: assignee %q not found: %v", sub.Key, key, err)
+			}
+			sub.OwnerAgentID = &id
+		}
+		if deps, ok := m["blocked_by"].([]any); ok {
+			for _, d := range deps {
+				if s, ok := d.(string); ok && s != "" {
+					sub.BlockedBy = append(sub.BlockedBy, s)
+				}
+			}
+		}
+		subtasks = append(subtasks, sub)
+	}
+	return subtasks, nil
+}
+
+// checkTemplate validates the template, its owners and its schedule (setting NextRunAt).
+func (t *TeamTasksTool) checkTemplate(ctx context.Context, team *store.TeamData, agentID uuid.UUID, tpl *store.TeamTaskTemplateData) error {
+	if err := ValidateTaskTemplate(tpl); err != nil {
+		return err
+	}
+	members, err := t.manager.CachedListMembers(ctx, team.ID, agentID)
+	if err != nil {
+		return fmt.Errorf("failed to verify team membership: %w", err)
+	}
+	if err := ValidateTemplateOwners(tpl, team, members); err != nil {
+		return err
+	}
+	if err := ScheduleTaskTemplate(tpl, time.Now()); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	return nil
+}
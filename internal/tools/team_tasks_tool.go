@@ -58,7 +58,7 @@ func (t *TeamTasksTool) Parameters() map[string]any {
 			},
 			"status": map[string]any{
 				"type":        "string",
-				"description": "Filter for list: '' (all, default), 'active', 'completed', 'in_review'",
+				"description": "Filter for list: '' (all, default), 'active', 'completed', 'in_review', 'overdue', 'at_risk'",
 			},
 			"query": map[string]any{
 				"type":        "string",
@@ -68,6 +68,10 @@ func (t *TeamTasksTool) Parameters() map[string]any {
 				"type":        "number",
 				"description": "Priority, higher = more important (for create, default 0)",
 			},
+			"due_at": map[string]any{
+				"type":        "string",
+				"description": "Due date for create/update: RFC 3339 time, date (YYYY-MM-DD), or relative duration ('4h', '2d', '1w'). 'none' clears it on update. Defaults to the team's per-priority SLA.",
+			},
 			"blocked_by": map[string]any{
 				"type":        "array",
 				"items":       map[string]any{"type": "string"},
@@ -97,6 +101,41 @@ func (t *TeamTasksTool) Parameters() map[string]any {
 				"type":        "number",
 				"description": "Page number for list/search (default 1, 30 per page)",
 			},
+			"template_id": map[string]any{
+				"type":        "string",
+				"description": "Task template UUID or name (for template_update, template_delete, template_run)",
+			},
+			"name": map[string]any{
+				"type":        "string",
+				"description": "Template name, unique per team (for template_create/template_update)",
+			},
+			"subtasks": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"key":         map[string]any{"type": "string"},
+						"subject":     map[string]any{"type": "string"},
+						"description": map[string]any{"type": "string"},
+						"assignee":    map[string]any{"type": "string"},
+						"priority":    map[string]any{"type": "number"},
+						"blocked_by":  map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+					},
+				},
+				"description": "Template subtasks: {key, subject, description?, assignee? (defaults to the template assignee), blocked_by? (keys of sibling subtasks)}. The parent task waits for all subtasks.",
+			},
+			"schedule": map[string]any{
+				"type":        "string",
+				"description": "Cron expression for recurring templates, e.g. '0 9 * * 1' (Mondays 09:00). 'none' clears it on template_update.",
+			},
+			"timezone": map[string]any{
+				"type":        "string",
+				"description": "IANA timezone for schedule (default UTC)",
+			},
+			"enabled": map[string]any{
+				"type":        "boolean",
+				"description": "Enable/disable a template's schedule (template_create/template_update)",
+			},
 		},
 		"required": []string{"action"},
 	}
@@ -112,26 +151,34 @@ func (t *TeamTasksTool) buildActionDescription() string {
 	if t.policy.IsAllowed("retry") {
 		base += " retry: re-dispatch a stale/failed task."
 	}
+	if t.policy.IsAllowed("template_run") {
+		base += " template_*: reusable task templates (parent + subtasks) with optional cron schedule; template_run instantiates one now."
+	}
 	// Per-action param guide — only list actions allowed by policy.
 	base += "\n\nParams per action (only send listed params):\n"
 	guide := map[string]string{
-		"list":           "- list: status?, page?\n",
-		"get":            "- get: task_id\n",
-		"create":         "- create: subject, description, assignee, priority?, blocked_by?, require_approval?, task_type?\n",
-		"claim":          "- claim: task_id\n",
-		"complete":       "- complete: task_id?, result\n",
-		"cancel":         "- cancel: task_id, text\n",
-		"search":         "- search: query, page?\n",
-		"review":         "- review: task_id\n",
-		"comment":        "- comment: task_id?, text, type?\n",
-		"progress":       "- progress: task_id?, percent, text?\n",
-		"attach":         "- attach: task_id, path\n",
-		"update":         "- update: task_id, subject?, description?, priority?, blocked_by?\n",
-		"approve":        "- approve: task_id\n",
-		"reject":         "- reject: task_id, text\n",
-		"ask_user":       "- ask_user: task_id, text\n",
-		"clear_ask_user": "- clear_ask_user: task_id\n",
-		"retry":          "- retry: task_id\n",
+		"list":            "- list: status?, page?\n",
+		"get":             "- get: task_id\n",
+		"create":          "- create: subject, description, assignee, priority?, due_at?, blocked_by?, require_approval?, task_type?\n",
+		"claim":           "- claim: task_id\n",
+		"complete":        "- complete: task_id?, result\n",
+		"cancel":          "- cancel: task_id, text\n",
+		"search":          "- search: query, page?\n",
+		"review":          "- review: task_id\n",
+		"comment":         "- comment: task_id?, text, type?\n",
+		"progress":        "- progress: task_id?, percent, text?\n",
+		"attach":          "- attach: task_id, path\n",
+		"update":          "- update: task_id, subject?, description?, priority?, due_at?, blocked_by?\n",
+		"approve":         "- approve: task_id\n",
+		"reject":          "- reject: task_id, text\n",
+		"ask_user":        "- ask_user: task_id, text\n",
+		"clear_ask_user":  "- clear_ask_user: task_id\n",
+		"retry":           "- retry: task_id\n",
+		"template_list":   "- template_list: (no params)\n",
+		"template_create": "- template_create: name, subject, assignee, description?, priority?, subtasks?, schedule?, timezone?\n",
+		"template_update": "- template_update: template_id, name?, subject?, description?, assignee?, priority?, subtasks?, schedule?, timezone?, enabled?\n",
+		"template_delete": "- template_delete: template_id\n",
+		"template_run":    "- template_run: template_id\n",
 	}
 	for _, action := range t.policy.AllowedActions() {
 		if line, ok := guide[action]; ok {
@@ -152,7 +199,7 @@ func (t *TeamTasksTool) Execute(ctx context.Context, args map[string]any) *Resul
 	// Block mutations during notification runs — leader may only relay status.
 	if RunKindFromCtx(ctx) == RunKindNotification {
 		switch action {
-		case "list", "get", "search":
+		case "list", "get", "search", "template_list":
 			// Read-only actions allowed.
 		default:
 			return ErrorResult("This is a notification run. Your role is to relay task status to the user in a natural, conversational style. Do not modify tasks.")
@@ -194,7 +241,17 @@ func (t *TeamTasksTool) Execute(ctx context.Context, args map[string]any) *Resul
 		return t.executeClearAskUser(ctx, args)
 	case "retry":
 		return t.executeRetry(ctx, args)
+	case "template_list":
+		return t.executeTemplateList(ctx, args)
+	case "template_create":
+		return t.executeTemplateCreate(ctx, args)
+	case "template_update":
+		return t.executeTemplateUpdate(ctx, args)
+	case "template_delete":
+		return t.executeTemplateDelete(ctx, args)
+	case "template_run":
+		return t.executeTemplateRun(ctx, args)
 	default:
-		return ErrorResult(fmt.Sprintf("unknown action: %s (use list, get, create, claim, complete, cancel, search, review, comment, progress, attach, update, ask_user, clear_ask_user, or retry)", action))
+		return ErrorResult(fmt.Sprintf("unknown action: %s (use list, get, create, claim, complete, cancel, search, review, comment, progress, attach, update, ask_user, clear_ask_user, retry, or template_list/create/update/delete/run)", action))
 	}
 }
@@ -0,0 +1,264 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// maxTemplateSubtasks caps the number of child tasks a template may create per run.
+const maxTemplateSubtasks = 20
+
+// TemplateDatePlaceholder in a template subject/description is replaced with
+// the run date (YYYY-MM-DD, in the template's timezone) at instantiation.
+const TemplateDatePlaceholder = "{{date}}"
+
+// TemplateRunOptions carries the routing context stamped on every task
+// created from a template.
+type TemplateRunOptions struct {
+	UserID           string
+	Channel          string
+	ChatID           string
+	CreatedByAgentID *uuid.UUID
+	Metadata         map[string]any // copied into every created task's metadata
+	Now              time.Time      // zero = time.Now()
+}
+
+// ValidateTaskTemplate checks required fields and the subtask dependency graph:
+// keys must be unique, blocked_by must reference sibling keys, and there must
+// be no cycles.
+func ValidateTaskTemplate(tpl *store.TeamTaskTemplateData) error {
+	if strings.TrimSpace(tpl.Name) == "" {
+		return fmt.Errorf("template name is required")
+	}
+	if strings.TrimSpace(tpl.Subject) == "" {
+		return fmt.Errorf("template subject is required")
+	}
+	if len(tpl.Subject) > 500 {
+		return fmt.Errorf("template subject too long (max 500 chars)")
+	}
+	if len(tpl.Subtasks) > maxTemplateSubtasks {
+		return fmt.Errorf("too many subtasks: %d (max %d)", len(tpl.Subtasks), maxTemplateSubtasks)
+	}
+
+	deps := make(map[string][]string, len(tpl.Subtasks))
+	for i, sub := range tpl.Subtasks {
+		if sub.Key == "" {
+			return fmt.Errorf("subtask %d: key is required", i+1)
+		}
+		if strings.TrimSpace(sub.Subject) == "" {
+			return fmt.Errorf("subtask %q: subject is required", sub.Key)
+		}
+		if _, dup := deps[sub.Key]; dup {
+			return fmt.Errorf("duplicate subtask key %q", sub.Key)
+		}
+		deps[sub.Key] = sub.BlockedBy
+	}
+	for key, blockers := range deps {
+		for _, b := range blockers {
+			if b == key {
+				return fmt.Errorf("subtask %q cannot block itself", key)
+			}
+			if _, ok := deps[b]; !ok {
+				return fmt.Errorf("subtask %q: blocked_by references unknown key %q", key, b)
+			}
+		}
+	}
+
+	// Cycle detection (DFS, 0 = unvisited, 1 = on stack, 2 = done).
+	state := make(map[string]int, len(deps))
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch state[key] {
+		case 1:
+			return fmt.Errorf("subtask dependency cycle at %q", key)
+		case 2:
+			return nil
+		}
+		state[key] = 1
+		for _, b := range deps[key] {
+			if err := visit(b); err != nil {
+				return err
+			}
+		}
+		state[key] = 2
+		return nil
+	}
+	for _, sub := range tpl.Subtasks {
+		if err := visit(sub.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateTemplateOwners checks that every task the template creates has an
+// owner that is a team member other than the lead.
+func ValidateTemplateOwners(tpl *store.TeamTaskTemplateData, team *store.TeamData, members []store.TeamMemberData) error {
+	isMember := make(map[uuid.UUID]bool, len(members))
+	for _, m := range members {
+		isMember[m.AgentID] = true
+	}
+	check := func(label string, owner *uuid.UUID) error {
+		if owner == nil {
+			return fmt.Errorf("%s has no owner — set the template's default owner", label)
+		}
+		if *owner == team.LeadAgentID {
+			return fmt.Errorf("%s is owned by the team lead — assign a team member instead", label)
+		}
+		if !isMember[*owner] {
+			return fmt.Errorf("%s owner %s is not a member of this team", label, *owner)
+		}
+		return nil
+	}
+	if err := check("template task", tpl.OwnerAgentID); err != nil {
+		return err
+	}
+	for _, sub := range tpl.Subtasks {
+		if sub.OwnerAgentID == nil {
+			continue // inherits the template owner, checked above
+		}
+		if err := check(fmt.Sprintf("subtask %q", sub.Key), sub.OwnerAgentID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ScheduleTaskTemplate validates the template's cron schedule (parsed by gronx,
+// same as cron jobs) and sets NextRunAt to the next tick after now. Templates
+// without a schedule, or disabled ones, get a nil NextRunAt.
+func ScheduleTaskTemplate(tpl *store.TeamTaskTemplateData, now time.Time) error {
+	tpl.NextRunAt = nil
+	if tpl.Schedule == "" {
+		return nil
+	}
+	sched := store.CronSchedule{Kind: "cron", Expr: tpl.Schedule, TZ: tpl.Timezone}
+	if err := store.ValidateCronSchedule(&sched); err != nil {
+		return err
+	}
+	next, err := store.NextRunForSchedule(&sched, tpl.Enabled, now, "")
+	if err != nil {
+		return err
+	}
+	tpl.NextRunAt = next
+	return nil
+}
+
+// InstantiateTaskTemplate creates the template's tasks: one parent task plus a
+// child task (ParentID = parent) per subtask. Subtask blocked_by keys become
+// blocked_by task IDs; the parent is blocked by all subtasks so it reaches its
+// owner last, as the roll-up. Returns the created tasks, parent first.
+// Pending tasks are not dispatched — that is up to the caller.
+func InstantiateTaskTemplate(ctx context.Context, ts store.TaskStore, team *store.TeamData, tpl *store.TeamTaskTemplateData, opts TemplateRunOptions) ([]*store.TeamTaskData, error) {
+	if err := ValidateTaskTemplate(tpl); err != nil {
+		return nil, err
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	loc := time.UTC
+	if tpl.Timezone != "" {
+		if l, err := time.LoadLocation(tpl.Timezone); err == nil {
+			loc = l
+		}
+	}
+	runDate := now.In(loc).Format("2006-01-02")
+	expand := func(s string) string {
+		return strings.ReplaceAll(s, TemplateDatePlaceholder, runDate)
+	}
+	sla := ParseSLAConfig(team.Settings)
+
+	// Pre-allocate IDs so blocked_by and parent_id can be wired before insert.
+	parentID := store.GenNewID()
+	subIDs := make(map[string]uuid.UUID, len(tpl.Subtasks))
+	for _, sub := range tpl.Subtasks {
+		subIDs[sub.Key] = store.GenNewID()
+	}
+
+	build := func(id uuid.UUID, subject, description string, owner *uuid.UUID, priority int, blockedBy []uuid.UUID) (*store.TeamTaskData, error) {
+		if owner == nil {
+			return nil, fmt.Errorf("task %q has no owner", subject)
+		}
+		if *owner == team.LeadAgentID {
+			return nil, fmt.Errorf("task %q is owned by the team lead", subject)
+		}
+		meta := make(map[string]any, len(opts.Metadata)+2)
+		for k, v := range opts.Metadata {
+			meta[k] = v
+		}
+		meta[TaskMetaTemplateID] = tpl.ID.String()
+		status := store.TeamTaskStatusPending
+		if len(blockedBy) > 0 {
+			status = store.TeamTaskStatusBlocked
+			ids := make([]string, len(blockedBy))
+			for i, b := range blockedBy {
+				ids[i] = b.String()
+			}
+			meta["original_blocked_by"] = ids
+		}
+		ownerID := *owner
+		task := &store.TeamTaskData{
+			BaseModel:        store.BaseModel{ID: id},
+			TeamID:           team.ID,
+			Subject:          expand(subject),
+			Description:      expand(description),
+			Status:           status,
+			OwnerAgentID:     &ownerID,
+			BlockedBy:        blockedBy,
+			Priority:         priority,
+			UserID:           opts.UserID,
+			Channel:          opts.Channel,
+			TaskType:         "general",
+			CreatedByAgentID: opts.CreatedByAgentID,
+			ChatID:           opts.ChatID,
+			Metadata:         meta,
+		}
+		sla.ApplyDue(task, nil, now)
+		return task, nil
+	}
+
+	var parentBlockers []uuid.UUID
+	for _, sub := range tpl.Subtasks {
+		parentBlockers = append(parentBlockers, subIDs[sub.Key])
+	}
+	parent, err := build(parentID, tpl.Subject, tpl.Description, tpl.OwnerAgentID, tpl.Priority, parentBlockers)
+	if err != nil {
+		return nil, err
+	}
+	tasks := []*store.TeamTaskData{parent}
+	for _, sub := range tpl.Subtasks {
+		owner := sub.OwnerAgentID
+		if owner == nil {
+			owner = tpl.OwnerAgentID
+		}
+		priority := sub.Priority
+		if priority == 0 {
+			priority = tpl.Priority
+		}
+		var blockedBy []uuid.UUID
+		for _, b := range sub.BlockedBy {
+			blockedBy = append(blockedBy, subIDs[b])
+		}
+		child, err := build(subIDs[sub.Key], sub.Subject, sub.Description, owner, priority, blockedBy)
+		if err != nil {
+			return nil, err
+		}
+		child.ParentID = &parentID
+		tasks = append(tasks, child)
+	}
+
+	// Parent first: children reference it via parent_id.
+	for i, task := range tasks {
+		if err := ts.CreateTask(ctx, task); err != nil {
+			return tasks[:i], fmt.Errorf("create task %q: %w", task.Subject, err)
+		}
+	}
+	return tasks, nil
+}
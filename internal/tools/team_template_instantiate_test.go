@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+func TestValidateTaskTemplate(t *testing.T) {
+	base := func(subs ...store.TeamTaskTemplateSubtask) *store.TeamTaskTemplateData {
+		return &store.TeamTaskTemplateData{Name: "weekly", Subject: "Weekly report", Subtasks: subs}
+	}
+	sub := func(key string, blockedBy ...string) store.TeamTaskTemplateSubtask {
+		return store.TeamTaskTemplateSubtask{Key: key, Subject: "do " + key, BlockedBy: blockedBy}
+	}
+
+	cases := []struct {
+		name    string
+		tpl     *store.TeamTaskTemplateData
+		wantErr string
+	}{
+		{"ok", base(sub("a"), sub("b", "a")), ""},
+		{"missing name", &store.TeamTaskTemplateData{Subject: "x"}, "name is required"},
+		{"duplicate key", base(sub("a"), sub("a")), "duplicate subtask key"},
+		{"unknown key", base(sub("a", "zzz")), "unknown key"},
+		{"self block", base(sub("a", "a")), "cannot block itself"},
+		{"cycle", base(sub("a", "c"), sub("b", "a"), sub("c", "b")), "cycle"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateTaskTemplate(tc.tpl)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleTaskTemplate(t *testing.T) {
+	now := time.Date(2026, 5, 4, 10, 30, 0, 0, time.UTC) // Monday
+
+	tpl := &store.TeamTaskTemplateData{Schedule: "0 9 * * 1", Enabled: true}
+	if err := ScheduleTaskTemplate(tpl, now); err != nil {
+		t.Fatalf("schedule: %v", err)
+	}
+	want := time.Date(2026, 5, 11, 9, 0, 0, 0, time.UTC)
+	if tpl.NextRunAt == nil || !tpl.NextRunAt.Equal(want) {
+		t.Fatalf("next run = %v, want %v", tpl.NextRunAt, want)
+	}
+
+	// Timezone is honoured: 09:00 in Ho Chi Minh (UTC+7) is 02:00 UTC.
+	tpl = &store.TeamTaskTemplateData{Schedule: "0 9 * * *", Timezone: "Asia/Ho_Chi_Minh", Enabled: true}
+	if err := ScheduleTaskTemplate(tpl, now); err != nil {
+		t.Fatalf("schedule tz: %v", err)
+	}
+	want = time.Date(2026, 5, 5, 2, 0, 0, 0, time.UTC)
+	if tpl.NextRunAt == nil || !tpl.NextRunAt.Equal(want) {
+		t.Fatalf("next run (tz) = %v, want %v", tpl.NextRunAt, want)
+	}
+
+	// Disabled and unscheduled templates have no next run.
+	tpl = &store.TeamTaskTemplateData{Schedule: "0 9 * * *"}
+	if err := ScheduleTaskTemplate(tpl, now); err != nil || tpl.NextRunAt != nil {
+		t.Fatalf("disabled: next = %v, err = %v", tpl.NextRunAt, err)
+	}
+	tpl = &store.TeamTaskTemplateData{Enabled: true}
+	if err := ScheduleTaskTemplate(tpl, now); err != nil || tpl.NextRunAt != nil {
+		t.Fatalf("unscheduled: next = %v, err = %v", tpl.NextRunAt, err)
+	}
+
+	if err := ScheduleTaskTemplate(&store.TeamTaskTemplateData{Schedule: "not a cron", Enabled: true}, now); err == nil {
+		t.Fatal("expected error for invalid cron expression")
+	}
+}
+
+func TestInstantiateTaskTemplate(t *testing.T) {
+	leadID, writerID, reviewerID := uuid.New(), uuid.New(), uuid.New()
+	team := &store.TeamData{BaseModel: store.BaseModel{ID: uuid.New()}, LeadAgentID: leadID}
+	ts := newMockTaskStore(team, nil)
+
+	tpl := &store.TeamTaskTemplateData{
+		BaseModel:    store.BaseModel{ID: uuid.New()},
+		TeamID:       team.ID,
+		Name:         "daily",
+		Subject:      "Daily digest {{date}}",
+		OwnerAgentID: &reviewerID,
+		Priority:     2,
+		Subtasks: []store.TeamTaskTemplateSubtask{
+			{Key: "draft", Subject: "Draft {{date}}", OwnerAgentID: &writerID},
+			{Key: "review", Subject: "Review", BlockedBy: []string{"draft"}},
+		},
+	}
+	now := time.Date(2026, 5, 4, 23, 0, 0, 0, time.UTC)
+	created, err := InstantiateTaskTemplate(context.Background(), ts, team, tpl, TemplateRunOptions{
+		UserID: "u1", Channel: "dashboard", ChatID: team.ID.String(), Now: now,
+	})
+	if err != nil {
+		t.Fatalf("instantiate: %v", err)
+	}
+	if len(created) != 3 {
+		t.Fatalf("created %d tasks, want 3", len(created))
+	}
+	parent, draft, review := created[0], created[1], created[2]
+
+	if parent.Subject != "Daily digest 2026-05-04" || draft.Subject != "Draft 2026-05-04" {
+		t.Errorf("date not expanded: %q / %q", parent.Subject, draft.Subject)
+	}
+	if parent.Status != store.TeamTaskStatusBlocked || len(parent.BlockedBy) != 2 {
+		t.Errorf("parent should be blocked by both subtasks: status=%s blocked_by=%v", parent.Status, parent.BlockedBy)
+	}
+	if draft.ParentID == nil || *draft.ParentID != parent.ID || review.ParentID == nil || *review.ParentID != parent.ID {
+		t.Errorf("subtasks not linked to parent")
+	}
+	if draft.Status != store.TeamTaskStatusPending || *draft.OwnerAgentID != writerID {
+		t.Errorf("draft: status=%s owner=%v", draft.Status, draft.OwnerAgentID)
+	}
+	if review.Status != store.TeamTaskStatusBlocked || len(review.BlockedBy) != 1 || review.BlockedBy[0] != draft.ID {
+		t.Errorf("review should be blocked by draft: status=%s blocked_by=%v", review.Status, review.BlockedBy)
+	}
+	if *review.OwnerAgentID != reviewerID || review.Priority != 2 {
+		t.Errorf("review should inherit template owner/priority: owner=%v priority=%d", review.OwnerAgentID, review.Priority)
+	}
+	for _, task := range created {
+		if task.Metadata[TaskMetaTemplateID] != tpl.ID.String() {
+			t.Errorf("task %q missing template_id metadata", task.Subject)
+		}
+		if _, err := ts.GetTask(context.Background(), task.ID); err != nil {
+			t.Errorf("task %q not stored: %v", task.Subject, err)
+		}
+	}
+
+	// The date follows the template timezone.
+	tpl.Timezone = "Asia/Ho_Chi_Minh"
+	created, err = InstantiateTaskTemplate(context.Background(), ts, team, tpl, TemplateRunOptions{Now: now})
+	if err != nil {
+		t.Fatalf("instantiate tz: %v", err)
+	}
+	if created[0].Subject != "Daily digest 2026-05-05" {
+		t.Errorf("tz date = %q", created[0].Subject)
+	}
+
+	// A lead-owned task is rejected before anything is created.
+	tpl.OwnerAgentID = &leadID
+	before := len(ts.tasks)
+	if _, err := InstantiateTaskTemplate(context.Background(), ts, team, tpl, TemplateRunOptions{Now: now}); err == nil {
+		t.Fatal("expected error for lead-owned template")
+	}
+	if len(ts.tasks) != before {
+		t.Errorf("tasks created despite error")
+	}
+}
@@ -0,0 +1,459 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+const defaultToolApprovalTimeout = 5 * time.Minute
+
+// ToolApprovalRequest is an in-flight approval request for a single tool call.
+type ToolApprovalRequest struct {
+	ID         string         `json:"id"`
+	Tool       string         `json:"tool"`
+	Args       map[string]any `json:"args"`
+	AgentID    string         `json:"agentId,omitempty"`
+	AgentKey   string         `json:"agentKey,omitempty"`
+	UserID     string         `json:"userId,omitempty"`
+	SenderID   string         `json:"senderId,omitempty"`
+	Channel    string         `json:"channel,omitempty"`
+	ChatID     string         `json:"chatId,omitempty"`
+	SessionKey string         `json:"sessionKey,omitempty"`
+	CreatedAt  time.Time      `json:"createdAt"`
+	ExpiresAt  time.Time      `json:"expiresAt"`
+	TenantID   uuid.UUID      `json:"-"`
+
+	approvers []config.ToolApprover
+	resultCh  chan ToolApprovalResolution
+}
+
+// ToolApprovalResolution records how and by whom a request was decided.
+type ToolApprovalResolution struct {
+	Decision   ApprovalDecision `json:"decision"`
+	ResolvedBy string           `json:"resolvedBy,omitempty"` // dashboard user or platform user ID (empty on timeout)
+	Via        string           `json:"via"`                  // "rpc", channel instance name, "timeout" or "cancelled"
+}
+
+// ToolApprovalPrompter delivers an approval prompt with approve/deny buttons to a chat.
+// Implemented by channels.Manager.SendApprovalPrompt.
+type ToolApprovalPrompter func(ctx context.Context, channel, chatID, id, text string) error
+
+// ToolApprovalManager gates tool calls matching the configured approval rules.
+// Matching calls block until an approver decides via RPC or a channel button,
+// or until the timeout passes (default-deny). Every decision is audited with
+// the call's arguments.
+type ToolApprovalManager struct {
+	cfg      config.ToolApprovalCfg
+	events   bus.EventPublisher
+	prompter ToolApprovalPrompter
+
+	mu      sync.Mutex
+	pending map[string]*ToolApprovalRequest
+}
+
+// NewToolApprovalManager creates a manager for the given rules.
+// events may be nil (no WS events or audit entries).
+func NewToolApprovalManager(cfg config.ToolApprovalCfg, events bus.EventPublisher) *ToolApprovalManager {
+	return &ToolApprovalManager{
+		cfg:     cfg,
+		events:  events,
+		pending: make(map[string]*ToolApprovalRequest),
+	}
+}
+
+// SetPrompter sets how approval prompts reach chat approvers.
+// Without a prompter, requests can only be decided via RPC.
+func (m *ToolApprovalManager) SetPrompter(p ToolApprovalPrompter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prompter = p
+}
+
+// Enabled reports whether any approval rule is configured.
+func (m *ToolApprovalManager) Enabled() bool {
+	return m != nil && len(m.cfg.Rules) > 0
+}
+
+// Gate checks a tool call against the approval rules. It returns nil when the
+// call may proceed, or an error result when it was denied or timed out.
+func (m *ToolApprovalManager) Gate(ctx context.Context, tool Tool, args map[string]any) *Result {
+	rule := m.match(ctx, tool, args)
+	if rule == nil {
+		return nil
+	}
+
+	timeout := defaultToolApprovalTimeout
+	if m.cfg.TimeoutSec > 0 {
+		timeout = time.Duration(m.cfg.TimeoutSec) * time.Second
+	}
+	if rule.TimeoutSec > 0 {
+		timeout = time.Duration(rule.TimeoutSec) * time.Second
+	}
+
+	now := time.Now()
+	req := &ToolApprovalRequest{
+		ID:         uuid.NewString(),
+		Tool:       tool.Name(),
+		Args:       scrubToolArgs(args),
+		AgentKey:   ToolAgentKeyFromCtx(ctx),
+		UserID:     store.UserIDFromContext(ctx),
+		SenderID:   store.SenderIDFromContext(ctx),
+		Channel:    ToolChannelFromCtx(ctx),
+		ChatID:     ToolChatIDFromCtx(ctx),
+		SessionKey: ToolSessionKeyFromCtx(ctx),
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(timeout),
+		TenantID:   store.TenantIDFromContext(ctx),
+		resultCh:   make(chan ToolApprovalResolution, 1),
+	}
+	if id := store.AgentIDFromContext(ctx); id != uuid.Nil {
+		req.AgentID = id.String()
+	}
+	if req.AgentKey == "" {
+		req.AgentKey = store.AgentKeyFromContext(ctx)
+	}
+	req.approvers = m.approversFor(rule)
+
+	m.mu.Lock()
+	m.pending[req.ID] = req
+	prompter := m.prompter
+	m.mu.Unlock()
+
+	slog.Info("tool approval requested", "id", req.ID, "tool", req.Tool, "agent", req.AgentKey, "timeout", timeout)
+	m.broadcast(protocol.EventToolApprovalReq, req.TenantID, req)
+
+	if prompter != nil {
+		text := FormatToolApprovalPrompt(req)
+		for _, a := range req.approvers {
+			if err := prompter(ctx, a.Channel, a.ChatID, req.ID, text); err != nil {
+				slog.Warn("tool approval: prompt delivery failed", "id", req.ID, "channel", a.Channel, "error", err)
+			}
+		}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var res ToolApprovalResolution
+	select {
+	case res = <-req.resultCh:
+	case <-timer.C:
+		res = ToolApprovalResolution{Decision: ApprovalDeny, Via: "timeout"}
+	case <-ctx.Done():
+		res = ToolApprovalResolution{Decision: ApprovalDeny, Via: "cancelled"}
+	}
+
+	m.mu.Lock()
+	delete(m.pending, req.ID)
+	m.mu.Unlock()
+
+	m.finish(req, res)
+
+	switch {
+	case res.Decision != ApprovalDeny:
+		return nil
+	case res.Via == "timeout":
+		return ErrorResult(fmt.Sprintf("tool %s was not approved within %s (denied by default)", req.Tool, timeout))
+	case res.Via == "cancelled":
+		return ErrorResult(fmt.Sprintf("tool %s approval cancelled", req.Tool))
+	default:
+		return ErrorResult(fmt.Sprintf("tool %s was denied by an approver", req.Tool))
+	}
+}
+
+// Resolve decides a pending request. Used by the tool.approval.* RPCs.
+func (m *ToolApprovalManager) Resolve(id string, res ToolApprovalResolution) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	req, ok := m.pending[id]
+	if !ok {
+		return fmt.Errorf("approval %q not found or already resolved", id)
+	}
+	delete(m.pending, id)
+	req.resultCh <- res
+	return nil
+}
+
+// ResolveFromChannel decides a pending request from a chat button press.
+// Only chats the prompt was sent to — and, when configured, only listed
+// platform users in those chats — may decide.
+func (m *ToolApprovalManager) ResolveFromChannel(channel, chatID, senderID, id string, approve bool) error {
+	m.mu.Lock()
+	req, ok := m.pending[id]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("approval not found or already resolved")
+	}
+	if !req.allowsApprover(channel, chatID, senderID) {
+		return fmt.Errorf("you are not allowed to decide this approval")
+	}
+
+	decision := ApprovalDeny
+	if approve {
+		decision = ApprovalAllowOnce
+	}
+	return m.Resolve(id, ToolApprovalResolution{Decision: decision, ResolvedBy: senderID, Via: channel})
+}
+
+// Get returns a pending request by ID, or nil.
+func (m *ToolApprovalManager) Get(id string) *ToolApprovalRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.pending[id]
+}
+
+// ListPending returns pending requests, oldest first.
+func (m *ToolApprovalManager) ListPending() []*ToolApprovalRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]*ToolApprovalRequest, 0, len(m.pending))
+	for _, req := range m.pending {
+		result = append(result, req)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result
+}
+
+// FormatToolApprovalPrompt renders the human-readable text of an approval prompt.
+func FormatToolApprovalPrompt(req *ToolApprovalRequest) string {
+	var sb strings.Builder
+	sb.WriteString("Approval required: tool ")
+	sb.WriteString(req.Tool)
+	if req.AgentKey != "" {
+		sb.WriteString(" (agent ")
+		sb.WriteString(req.AgentKey)
+		sb.WriteString(")")
+	}
+	sb.WriteString("\n\n")
+	if data, err := json.MarshalIndent(req.Args, "", "  "); err == nil {
+		sb.WriteString(truncateCmd(ScrubCredentials(string(data)), 1500))
+		sb.WriteString("\n\n")
+	}
+	fmt.Fprintf(&sb, "Denied automatically at %s UTC.", req.ExpiresAt.UTC().Format("15:04:05"))
+	return sb.String()
+}
+
+// match returns the first rule that selects this call, or nil.
+func (m *ToolApprovalManager) match(ctx context.Context, tool Tool, args map[string]any) *config.ToolApprovalRule {
+	if !m.Enabled() {
+		return nil
+	}
+	name := tool.Name()
+	agentKey := ToolAgentKeyFromCtx(ctx)
+	if agentKey == "" {
+		agentKey = store.AgentKeyFromContext(ctx)
+	}
+	for i := range m.cfg.Rules {
+		rule := &m.cfg.Rules[i]
+		if !matchToolSpec(name, rule.Tools) {
+			continue
+		}
+		if len(rule.Agents) > 0 && !slices.Contains(rule.Agents, agentKey) {
+			continue
+		}
+		if rule.WriteOnly {
+			if ro, ok := tool.(ReadOnlyTool); ok && ro.ReadOnly() {
+				continue
+			}
+		}
+		if !matchArgConditions(args, rule.Args) {
+			continue
+		}
+		if len(rule.PathOutside) > 0 && !pathOutside(argString(args, "path"), ToolWorkspaceFromCtx(ctx), rule.PathOutside) {
+			continue
+		}
+		return rule
+	}
+	return nil
+}
+
+// approversFor resolves prompt destinations: rule, then config default.
+// With neither set the request can only be decided by an admin from the
+// dashboard; the originating chat is never used, since its sender is the one
+// whose call is being gated.
+func (m *ToolApprovalManager) approversFor(rule *config.ToolApprovalRule) []config.ToolApprover {
+	if len(rule.Approvers) > 0 {
+		return rule.Approvers
+	}
+	return m.cfg.Approvers
+}
+
+// allowsApprover reports whether a button press in channel/chatID by senderID may decide req.
+// Chat IDs match on the raw ID so topic/thread-scoped origins ("-100123:topic:5") accept
+// presses reported with the bare chat ID.
+func (req *ToolApprovalRequest) allowsApprover(channel, chatID, senderID string) bool {
+	for _, a := range req.approvers {
+		if a.Channel != channel {
+			continue
+		}
+		if a.ChatID != chatID && !strings.HasPrefix(a.ChatID, chatID+":") {
+			continue
+		}
+		if len(a.UserIDs) == 0 || slices.Contains(a.UserIDs, senderID) {
+			return true
+		}
+	}
+	return false
+}
+
+// finish logs, broadcasts and audits a decided request.
+func (m *ToolApprovalManager) finish(req *ToolApprovalRequest, res ToolApprovalResolution) {
+	slog.Info("tool approval resolved", "id", req.ID, "tool", req.Tool, "decision", res.Decision, "by", res.ResolvedBy, "via", res.Via)
+
+	m.broadcast(protocol.EventToolApprovalRes, req.TenantID, map[string]any{
+		"id":         req.ID,
+		"tool":       req.Tool,
+		"userId":     req.UserID,
+		"decision":   string(res.Decision),
+		"resolvedBy": res.ResolvedBy,
+		"via":        res.Via,
+	})
+
+	if m.events == nil {
+		return
+	}
+	details, _ := json.Marshal(map[string]any{
+		"tool":       req.Tool,
+		"args":       req.Args,
+		"agent_id":   req.AgentID,
+		"agent_key":  req.AgentKey,
+		"user_id":    req.UserID,
+		"channel":    req.Channel,
+		"chat_id":    req.ChatID,
+		"decision":   string(res.Decision),
+		"via":        res.Via,
+		"created_at": req.CreatedAt,
+	})
+	actorType, actorID := "user", res.ResolvedBy
+	if res.ResolvedBy == "" {
+		actorType, actorID = "system", res.Via
+	}
+	action := "tool.approved"
+	if res.Decision == ApprovalDeny {
+		action = "tool.denied"
+	}
+	m.events.Broadcast(bus.Event{
+		Name: protocol.EventAuditLog,
+		Payload: bus.AuditEventPayload{
+			ActorType:  actorType,
+			ActorID:    actorID,
+			Action:     action,
+			EntityType: "tool_approval",
+			EntityID:   req.ID,
+			Details:    details,
+			TenantID:   req.TenantID,
+		},
+	})
+}
+
+// scrubToolArgs returns a copy of args with credentials scrubbed. The copy is
+// what prompts, events, the list RPC and the audit log see; the tool itself
+// still runs with the original arguments.
+func scrubToolArgs(args map[string]any) map[string]any {
+	if len(args) == 0 {
+		return args
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return map[string]any{"_unserializable": true}
+	}
+	scrubbed := ScrubCredentials(string(data))
+	var out map[string]any
+	if err := json.Unmarshal([]byte(scrubbed), &out); err != nil {
+		// A pattern matched across JSON syntax; keep the scrubbed text as-is.
+		return map[string]any{"_scrubbed": scrubbed}
+	}
+	return out
+}
+
+func (m *ToolApprovalManager) broadcast(name string, tenantID uuid.UUID, payload any) {
+	if m.events == nil {
+		return
+	}
+	m.events.Broadcast(bus.Event{Name: name, Payload: payload, TenantID: tenantID})
+}
+
+// MatchToolSpec reports whether name matches any tool name, glob or "group:xxx" entry.
+func MatchToolSpec(name string, spec []string) bool { return matchToolSpec(name, spec) }
+
+// matchToolSpec reports whether name matches any tool name, glob or "group:xxx" entry.
+func matchToolSpec(name string, spec []string) bool {
+	toolGroupsMu.RLock()
+	defer toolGroupsMu.RUnlock()
+
+	for _, s := range spec {
+		if group, ok := strings.CutPrefix(s, "group:"); ok {
+			if slices.Contains(toolGroups[group], name) {
+				return true
+			}
+			continue
+		}
+		if matched, _ := filepath.Match(s, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchArgConditions reports whether every argument glob holds.
+// "*" matches any run of characters (including "/"); a leading "!" negates.
+func matchArgConditions(args map[string]any, conds map[string]string) bool {
+	for key, pattern := range conds {
+		negate := false
+		if p, ok := strings.CutPrefix(pattern, "!"); ok {
+			negate, pattern = true, p
+		}
+		if wildcardMatch(pattern, argString(args, key)) == negate {
+			return false
+		}
+	}
+	return true
+}
+
+func wildcardMatch(pattern, s string) bool {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, `.*`)
+	expr = strings.ReplaceAll(expr, `\?`, `.`)
+	matched, _ := regexp.MatchString("^(?s:"+expr+")$", s)
+	return matched
+}
+
+// pathOutside reports whether p resolves outside every dir. Relative paths and
+// dirs resolve against the workspace. An empty path never matches.
+func pathOutside(p, workspace string, dirs []string) bool {
+	if p == "" {
+		return false
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(workspace, p)
+	}
+	p = filepath.Clean(p)
+	for _, d := range dirs {
+		if !filepath.IsAbs(d) {
+			d = filepath.Join(workspace, d)
+		}
+		rel, err := filepath.Rel(filepath.Clean(d), p)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return false
+		}
+	}
+	return true
+}
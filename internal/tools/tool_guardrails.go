@@ -0,0 +1,450 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/internal/pii"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// Guardrail stages and actions (config.ToolGuardrailRule.Stage / Action).
+const (
+	GuardrailStageCall   = "call"
+	GuardrailStageResult = "result"
+
+	GuardrailActionDeny    = "deny"
+	GuardrailActionRewrite = "rewrite"
+	GuardrailActionRedact  = "redact"
+)
+
+const (
+	defaultGuardrailReplacement = "[REDACTED]"
+	guardrailLookupTimeout      = 2 * time.Second
+)
+
+// guardrailRegexCache caches compiled rule patterns (per-agent rules arrive on
+// every call via context, so compiling them each time would be wasteful).
+var guardrailRegexCache sync.Map // pattern → *regexp.Regexp
+
+// ToolGuardrails evaluates content-aware rules around tool execution: argument
+// rules before the call (deny/rewrite) and result rules before the output
+// reaches the LLM (redact/deny). Global rules come from config.ToolsConfig;
+// per-agent rules are read from context (WithToolGuardrails). Every trigger is
+// audited without the matched content.
+type ToolGuardrails struct {
+	rules  []config.ToolGuardrailRule
+	events bus.EventPublisher
+
+	// lookupIP resolves hostnames for CIDR rules (replaced in tests).
+	lookupIP func(ctx context.Context, host string) ([]net.IP, error)
+}
+
+// NewToolGuardrails creates an evaluator for the global rules.
+// Rules with invalid patterns, CIDRs or detectors are dropped with a warning.
+func NewToolGuardrails(rules []config.ToolGuardrailRule, events bus.EventPublisher) *ToolGuardrails {
+	valid := make([]config.ToolGuardrailRule, 0, len(rules))
+	for _, r := range rules {
+		if err := ValidateGuardrailRule(r); err != nil {
+			slog.Warn("tool guardrail rule ignored", "rule", r.Name, "error", err)
+			continue
+		}
+		valid = append(valid, r)
+	}
+	return &ToolGuardrails{
+		rules:  valid,
+		events: events,
+		lookupIP: func(ctx context.Context, host string) ([]net.IP, error) {
+			return net.DefaultResolver.LookupIP(ctx, "ip", host)
+		},
+	}
+}
+
+// ValidateGuardrailRule checks a rule's stage, action, pattern, CIDRs and detectors.
+func ValidateGuardrailRule(r config.ToolGuardrailRule) error {
+	if len(r.Tools) == 0 {
+		return fmt.Errorf("tools is required")
+	}
+	switch guardrailStage(r) {
+	case GuardrailStageCall:
+		if a := guardrailAction(r); a != GuardrailActionDeny && a != GuardrailActionRewrite {
+			return fmt.Errorf("action %q is not valid for stage call", a)
+		}
+		if guardrailAction(r) == GuardrailActionRewrite && r.Pattern == "" && len(r.Detect) == 0 {
+			return fmt.Errorf("rewrite requires pattern or detect")
+		}
+	case GuardrailStageResult:
+		if a := guardrailAction(r); a != GuardrailActionRedact && a != GuardrailActionDeny {
+			return fmt.Errorf("action %q is not valid for stage result", a)
+		}
+		if len(r.CIDRs) > 0 {
+			return fmt.Errorf("cidrs only apply to stage call")
+		}
+	default:
+		return fmt.Errorf("unknown stage %q", r.Stage)
+	}
+	if r.Pattern != "" {
+		if _, err := compileGuardrailPattern(r.Pattern); err != nil {
+			return fmt.Errorf("pattern: %w", err)
+		}
+	}
+	for _, c := range r.CIDRs {
+		if _, _, err := net.ParseCIDR(c); err != nil {
+			return fmt.Errorf("cidr %q: %w", c, err)
+		}
+	}
+	for _, d := range r.Detect {
+		if _, ok := pii.LookupDetector(d); !ok {
+			return fmt.Errorf("unknown detector %q", d)
+		}
+	}
+	return nil
+}
+
+// CheckCall evaluates call-stage rules against args. It returns the (possibly
+// rewritten) arguments, or a non-nil error result when a rule denies the call.
+// The caller's args map is never mutated.
+func (g *ToolGuardrails) CheckCall(ctx context.Context, tool Tool, args map[string]any) (map[string]any, *Result) {
+	for _, rule := range g.rulesFor(ctx, tool, GuardrailStageCall) {
+		if !matchArgConditions(args, rule.Args) {
+			continue
+		}
+		fields, hits := g.scanArgs(ctx, &rule, args)
+		if hits == 0 {
+			continue
+		}
+
+		action := guardrailAction(rule)
+		// CIDR hits cannot be rewritten into something safe — deny instead.
+		if action == GuardrailActionRewrite && !slices.Contains(fields, "") {
+			args = rewriteArgs(&rule, args, fields)
+			g.audit(ctx, &rule, tool.Name(), "rewritten", fields, hits)
+			continue
+		}
+		g.audit(ctx, &rule, tool.Name(), "denied", compactFields(fields), hits)
+		return args, ErrorResult(guardrailDenyMessage(&rule, tool.Name()))
+	}
+	return args, nil
+}
+
+// FilterResult evaluates result-stage rules against result.ForLLM in place.
+// args are the arguments the tool ran with (for args selectors).
+func (g *ToolGuardrails) FilterResult(ctx context.Context, tool Tool, args map[string]any, result *Result) {
+	if result == nil || result.ForLLM == "" {
+		return
+	}
+	for _, rule := range g.rulesFor(ctx, tool, GuardrailStageResult) {
+		if !matchArgConditions(args, rule.Args) {
+			continue
+		}
+		hits := countContentMatches(&rule, result.ForLLM)
+		if hits == 0 {
+			continue
+		}
+		if guardrailAction(rule) == GuardrailActionDeny {
+			g.audit(ctx, &rule, tool.Name(), "withheld", nil, hits)
+			msg := rule.Message
+			if msg == "" {
+				msg = fmt.Sprintf("The output of %s was withheld by guardrail %q.", tool.Name(), guardrailName(&rule))
+			}
+			*result = *ErrorResult(msg)
+			return
+		}
+		result.ForLLM = replaceContent(&rule, result.ForLLM)
+		g.audit(ctx, &rule, tool.Name(), "redacted", nil, hits)
+	}
+}
+
+// rulesFor returns global then per-agent rules of the given stage selecting this call.
+func (g *ToolGuardrails) rulesFor(ctx context.Context, tool Tool, stage string) []config.ToolGuardrailRule {
+	agentRules := ToolGuardrailsFromCtx(ctx)
+	if len(g.rules) == 0 && len(agentRules) == 0 {
+		return nil
+	}
+	name := tool.Name()
+	agentKey := guardrailAgentKey(ctx)
+	tenantID := store.TenantIDFromContext(ctx).String()
+
+	var out []config.ToolGuardrailRule
+	for i, rule := range slices.Concat(g.rules, agentRules) {
+		if guardrailStage(rule) != stage || !matchToolSpec(name, rule.Tools) {
+			continue
+		}
+		if len(rule.Agents) > 0 && !slices.Contains(rule.Agents, agentKey) {
+			continue
+		}
+		if len(rule.Tenants) > 0 && !slices.Contains(rule.Tenants, tenantID) {
+			continue
+		}
+		// Per-agent rules come straight from the agent's tools_config; skip invalid ones.
+		if i >= len(g.rules) {
+			if err := ValidateGuardrailRule(rule); err != nil {
+				slog.Debug("tool guardrail: invalid agent rule skipped", "rule", rule.Name, "agent", agentKey, "error", err)
+				continue
+			}
+		}
+		out = append(out, rule)
+	}
+	return out
+}
+
+// scanArgs counts content matches in the selected string arguments.
+// A rule without content conditions matches on its selectors alone. CIDR hits
+// are reported under the field name "" so callers can tell them apart.
+func (g *ToolGuardrails) scanArgs(ctx context.Context, rule *config.ToolGuardrailRule, args map[string]any) ([]string, int) {
+	if rule.Pattern == "" && len(rule.Detect) == 0 && len(rule.CIDRs) == 0 {
+		return nil, 1
+	}
+	var fields []string
+	hits := 0
+	for _, key := range guardrailFields(rule, args) {
+		value := argString(args, key)
+		if value == "" {
+			continue
+		}
+		if n := countContentMatches(rule, value); n > 0 {
+			fields = append(fields, key)
+			hits += n
+		}
+		if len(rule.CIDRs) > 0 && g.hostInCIDRs(ctx, value, rule.CIDRs) {
+			fields = append(fields, key, "")
+			hits++
+		}
+	}
+	return fields, hits
+}
+
+// hostInCIDRs reports whether the URL or host in value resolves into any CIDR.
+// Unresolvable hosts do not match (the tool itself will fail to reach them).
+func (g *ToolGuardrails) hostInCIDRs(ctx context.Context, value string, cidrs []string) bool {
+	host := extractGuardrailHost(value)
+	if host == "" {
+		return false
+	}
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		lctx, cancel := context.WithTimeout(ctx, guardrailLookupTimeout)
+		resolved, err := g.lookupIP(lctx, host)
+		cancel()
+		if err != nil {
+			return false
+		}
+		ips = resolved
+	}
+	for _, c := range cidrs {
+		_, network, err := net.ParseCIDR(c)
+		if err != nil {
+			continue
+		}
+		for _, ip := range ips {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// audit logs and broadcasts a guardrail trigger. Matched content is never
+// included — only the rule, tool, fields and hit count.
+func (g *ToolGuardrails) audit(ctx context.Context, rule *config.ToolGuardrailRule, tool, outcome string, fields []string, hits int) {
+	agentKey := guardrailAgentKey(ctx)
+	slog.Info("tool guardrail triggered", "rule", guardrailName(rule), "tool", tool, "outcome", outcome, "agent", agentKey, "hits", hits)
+
+	if g.events == nil {
+		return
+	}
+	details, _ := json.Marshal(map[string]any{
+		"rule":        guardrailName(rule),
+		"stage":       guardrailStage(*rule),
+		"tool":        tool,
+		"fields":      fields,
+		"hits":        hits,
+		"agent_key":   agentKey,
+		"user_id":     store.UserIDFromContext(ctx),
+		"channel":     ToolChannelFromCtx(ctx),
+		"chat_id":     ToolChatIDFromCtx(ctx),
+		"session_key": ToolSessionKeyFromCtx(ctx),
+	})
+	actorID := agentKey
+	if actorID == "" {
+		actorID = "system"
+	}
+	g.events.Broadcast(bus.Event{
+		Name: protocol.EventAuditLog,
+		Payload: bus.AuditEventPayload{
+			ActorType:  "agent",
+			ActorID:    actorID,
+			Action:     "tool.guardrail." + outcome,
+			EntityType: "tool_guardrail",
+			EntityID:   guardrailName(rule),
+			Details:    details,
+			TenantID:   store.TenantIDFromContext(ctx),
+		},
+	})
+}
+
+// guardrailFields returns the argument names a rule scans, sorted for stable audits.
+func guardrailFields(rule *config.ToolGuardrailRule, args map[string]any) []string {
+	if len(rule.Fields) > 0 {
+		return rule.Fields
+	}
+	keys := make([]string, 0, len(args))
+	for k, v := range args {
+		if _, ok := v.(string); ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// countContentMatches counts pattern and detector hits in s.
+func countContentMatches(rule *config.ToolGuardrailRule, s string) int {
+	n := 0
+	if rule.Pattern != "" {
+		if re, err := compileGuardrailPattern(rule.Pattern); err == nil {
+			n += len(re.FindAllStringIndex(s, -1))
+		}
+	}
+	n += len(pii.Scan(s, guardrailDetectorsFor(rule)))
+	return n
+}
+
+// replaceContent replaces pattern and detector hits in s.
+func replaceContent(rule *config.ToolGuardrailRule, s string) string {
+	repl := rule.Replacement
+	if repl == "" {
+		repl = defaultGuardrailReplacement
+	}
+	if rule.Pattern != "" {
+		if re, err := compileGuardrailPattern(rule.Pattern); err == nil {
+			s = re.ReplaceAllLiteralString(s, repl)
+		}
+	}
+	findings := pii.Scan(s, guardrailDetectorsFor(rule))
+	for i := len(findings) - 1; i >= 0; i-- {
+		s = s[:findings[i].Start] + repl + s[findings[i].End:]
+	}
+	return s
+}
+
+// rewriteArgs returns a copy of args with matches replaced in the given fields.
+func rewriteArgs(rule *config.ToolGuardrailRule, args map[string]any, fields []string) map[string]any {
+	out := make(map[string]any, len(args))
+	for k, v := range args {
+		out[k] = v
+	}
+	for _, key := range fields {
+		if s, ok := out[key].(string); ok {
+			out[key] = replaceContent(rule, s)
+		}
+	}
+	return out
+}
+
+func compileGuardrailPattern(pattern string) (*regexp.Regexp, error) {
+	if v, ok := guardrailRegexCache.Load(pattern); ok {
+		return v.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	guardrailRegexCache.Store(pattern, re)
+	return re, nil
+}
+
+// extractGuardrailHost returns the host of a URL, "host:port" or bare host/IP.
+func extractGuardrailHost(value string) string {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "://") {
+		u, err := url.Parse(value)
+		if err != nil {
+			return ""
+		}
+		return u.Hostname()
+	}
+	if strings.ContainsAny(value, " /\n\t") {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		return host
+	}
+	return strings.Trim(value, "[]")
+}
+
+func guardrailStage(r config.ToolGuardrailRule) string {
+	if r.Stage == "" {
+		return GuardrailStageCall
+	}
+	return r.Stage
+}
+
+func guardrailAction(r config.ToolGuardrailRule) string {
+	if r.Action != "" {
+		return r.Action
+	}
+	if guardrailStage(r) == GuardrailStageResult {
+		return GuardrailActionRedact
+	}
+	return GuardrailActionDeny
+}
+
+func guardrailName(r *config.ToolGuardrailRule) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return strings.Join(r.Tools, ",")
+}
+
+func guardrailDenyMessage(r *config.ToolGuardrailRule, tool string) string {
+	if r.Message != "" {
+		return r.Message
+	}
+	return fmt.Sprintf("The %s call was blocked by guardrail %q. Do not retry with the same arguments.", tool, guardrailName(r))
+}
+
+func guardrailAgentKey(ctx context.Context) string {
+	if key := ToolAgentKeyFromCtx(ctx); key != "" {
+		return key
+	}
+	return store.AgentKeyFromContext(ctx)
+}
+
+// compactFields drops the CIDR marker and duplicates from a field list.
+func compactFields(fields []string) []string {
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f != "" && !slices.Contains(out, f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// guardrailDetectorsFor resolves a rule's detect names to PII detectors.
+func guardrailDetectorsFor(rule *config.ToolGuardrailRule) []*pii.Detector {
+	var out []*pii.Detector
+	for _, name := range rule.Detect {
+		if d, ok := pii.LookupDetector(name); ok {
+			out = append(out, d)
+		}
+	}
+	return out
+}
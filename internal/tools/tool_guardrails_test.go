@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/config"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+type guardrailEchoTool struct {
+	name string
+	out  string
+	args map[string]any
+}
+
+func (t *guardrailEchoTool) Name() string               { return t.name }
+func (t *guardrailEchoTool) Description() string        { return "test" }
+func (t *guardrailEchoTool) Parameters() map[string]any { return map[string]any{"type": "object"} }
+func (t *guardrailEchoTool) Execute(_ context.Context, args map[string]any) *Result {
+	t.args = args
+	return NewResult(t.out)
+}
+
+func newGuardrailRegistry(rules []config.ToolGuardrailRule, pub bus.EventPublisher, tool Tool) *Registry {
+	g := NewToolGuardrails(rules, pub)
+	g.lookupIP = func(_ context.Context, host string) ([]net.IP, error) {
+		if host == "metadata.internal" {
+			return []net.IP{net.ParseIP("10.1.2.3")}, nil
+		}
+		return []net.IP{net.ParseIP("93.184.216.34")}, nil
+	}
+	reg := NewRegistry()
+	reg.SetGuardrails(g)
+	reg.Register(tool)
+	return reg
+}
+
+func TestToolGuardrails_DenyCall(t *testing.T) {
+	rules := []config.ToolGuardrailRule{
+		{Name: "no-env", Tools: []string{"write_file"}, Args: map[string]string{"path": "*.env"}},
+		{Name: "internal-net", Tools: []string{"web_fetch"}, CIDRs: []string{"10.0.0.0/8"}},
+	}
+	cases := []struct {
+		tool   string
+		args   map[string]any
+		denied bool
+	}{
+		{"write_file", map[string]any{"path": "config/.env"}, true},
+		{"write_file", map[string]any{"path": "notes.md"}, false},
+		{"web_fetch", map[string]any{"url": "http://10.0.0.5/admin"}, true},
+		{"web_fetch", map[string]any{"url": "https://metadata.internal/latest"}, true},
+		{"web_fetch", map[string]any{"url": "https://example.com/"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.tool+" "+argString(tc.args, "path")+argString(tc.args, "url"), func(t *testing.T) {
+			pub := &recordingPublisher{}
+			tool := &guardrailEchoTool{name: tc.tool, out: "ok"}
+			res := newGuardrailRegistry(rules, pub, tool).ExecuteWithContext(context.Background(), tc.tool, tc.args, "", "", "", "", nil)
+			if res.IsError != tc.denied || (tool.args == nil) != tc.denied {
+				t.Fatalf("denied = %v, want %v (%s)", res.IsError, tc.denied, res.ForLLM)
+			}
+			if audit := pub.find(protocol.EventAuditLog); (audit != nil) != tc.denied {
+				t.Fatalf("audit event present = %v, want %v", audit != nil, tc.denied)
+			} else if audit != nil && audit.Payload.(bus.AuditEventPayload).Action != "tool.guardrail.denied" {
+				t.Fatalf("unexpected audit action %q", audit.Payload.(bus.AuditEventPayload).Action)
+			}
+		})
+	}
+}
+
+func TestToolGuardrails_RewriteCall(t *testing.T) {
+	pub := &recordingPublisher{}
+	tool := &guardrailEchoTool{name: "message", out: "sent"}
+	reg := newGuardrailRegistry([]config.ToolGuardrailRule{
+		{Name: "no-phones", Tools: []string{"message"}, Action: "rewrite", Fields: []string{"message"}, Detect: []string{"phone"}, Replacement: "[phone]"},
+	}, pub, tool)
+
+	args := map[string]any{"message": "Call me at +1 (415) 555-0132 before 2026-05-04", "channel": "telegram"}
+	res := reg.ExecuteWithContext(context.Background(), "message", args, "", "", "", "", nil)
+	if res.IsError {
+		t.Fatalf("unexpected error: %s", res.ForLLM)
+	}
+	if got := tool.args["message"]; got != "Call me at [phone] before 2026-05-04" {
+		t.Fatalf("rewritten message = %q", got)
+	}
+	if args["message"] == tool.args["message"] {
+		t.Fatal("caller's args must not be mutated")
+	}
+	audit := pub.find(protocol.EventAuditLog)
+	if audit == nil {
+		t.Fatal("expected audit event")
+	}
+	payload := audit.Payload.(bus.AuditEventPayload)
+	if payload.Action != "tool.guardrail.rewritten" || strings.Contains(string(payload.Details), "555") {
+		t.Fatalf("unexpected audit payload: %+v %s", payload, payload.Details)
+	}
+}
+
+func TestToolGuardrails_RedactResult(t *testing.T) {
+	tool := &guardrailEchoTool{name: "read_file", out: "alice@example.com paid with 4111 1111 1111 1111 (order 1234567890123)"}
+	reg := newGuardrailRegistry([]config.ToolGuardrailRule{
+		{Name: "pii", Tools: []string{"group:fs", "read_file"}, Stage: "result", Detect: []string{"email", "credit_card"}},
+	}, nil, tool)
+
+	res := reg.ExecuteWithContext(context.Background(), "read_file", map[string]any{"path": "a.txt"}, "", "", "", "", nil)
+	want := "[REDACTED] paid with [REDACTED] (order 1234567890123)"
+	if res.ForLLM != want {
+		t.Fatalf("ForLLM = %q, want %q", res.ForLLM, want)
+	}
+}
+
+func TestToolGuardrails_AgentRulesFromContext(t *testing.T) {
+	tool := &guardrailEchoTool{name: "exec", out: "ok"}
+	reg := newGuardrailRegistry(nil, nil, tool)
+	ctx := WithToolGuardrails(context.Background(), []config.ToolGuardrailRule{
+		{Tools: []string{"exec"}, Pattern: `rm\s+-rf`, Message: "no"},
+		{Tools: []string{"exec"}, Pattern: `(`}, // invalid: skipped
+	})
+
+	if res := reg.ExecuteWithContext(ctx, "exec", map[string]any{"command": "rm -rf /"}, "", "", "", "", nil); !res.IsError || res.ForLLM != "no" {
+		t.Fatalf("expected agent rule to deny, got %+v", res)
+	}
+	if res := reg.ExecuteWithContext(ctx, "exec", map[string]any{"command": "ls"}, "", "", "", "", nil); res.IsError {
+		t.Fatalf("unexpected denial: %s", res.ForLLM)
+	}
+	if res := reg.ExecuteWithContext(context.Background(), "exec", map[string]any{"command": "rm -rf /"}, "", "", "", "", nil); res.IsError {
+		t.Fatal("agent rules must not leak to other contexts")
+	}
+}
+
+func TestValidateGuardrailRule(t *testing.T) {
+	bad := []config.ToolGuardrailRule{
+		{},
+		{Tools: []string{"x"}, Stage: "later"},
+		{Tools: []string{"x"}, Action: "redact"},
+		{Tools: []string{"x"}, Action: "rewrite"},
+		{Tools: []string{"x"}, Stage: "result", CIDRs: []string{"10.0.0.0/8"}},
+		{Tools: []string{"x"}, CIDRs: []string{"10.0.0.0"}},
+		{Tools: []string{"x"}, Detect: []string{"ssn"}},
+	}
+	for i, r := range bad {
+		if ValidateGuardrailRule(r) == nil {
+			t.Errorf("rule %d: expected validation error", i)
+		}
+	}
+	if err := ValidateGuardrailRule(config.ToolGuardrailRule{Tools: []string{"x"}, Stage: "result", Action: "deny", Pattern: "secret"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -117,6 +117,13 @@ type ChannelAware interface {
 	RequiredChannelTypes() []string
 }
 
+// ReadOnlyTool is optionally implemented by tools that know whether they
+// mutate external state (e.g. MCP tools declaring readOnlyHint). Approval
+// rules with write_only skip tools reporting ReadOnly() == true.
+type ReadOnlyTool interface {
+	ReadOnly() bool
+}
+
 // ToProviderDef converts a Tool to a providers.ToolDefinition for LLM APIs.
 func ToProviderDef(t Tool) providers.ToolDefinition {
 	return providers.ToolDefinition{
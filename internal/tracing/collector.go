@@ -58,6 +58,10 @@ type Collector struct {
 	verbose  bool         // when true, LLM spans include full input messages
 	exporter SpanExporter // optional external exporter (nil = disabled)
 
+	// redactPreview rewrites input/output previews before they are stored or
+	// exported (e.g. PII redaction). nil = previews are stored as-is.
+	redactPreview func(string) string
+
 	// OnFlush is called after each flush cycle with the trace IDs that had
 	// their aggregates updated. Used to broadcast realtime trace events.
 	OnFlush func(traceIDs []uuid.UUID)
@@ -97,6 +101,12 @@ func (c *Collector) SetExporter(exp SpanExporter) {
 	c.exporter = exp
 }
 
+// SetPreviewRedactor installs a function applied to trace and span
+// input/output previews before they are stored or exported.
+func (c *Collector) SetPreviewRedactor(fn func(string) string) {
+	c.redactPreview = fn
+}
+
 // Start begins the background flush loop.
 func (c *Collector) Start() {
 	c.wg.Add(1)
@@ -123,11 +133,16 @@ func (c *Collector) Stop() {
 
 // CreateTrace synchronously creates a trace record.
 func (c *Collector) CreateTrace(ctx context.Context, trace *store.TraceData) error {
+	if c.redactPreview != nil {
+		trace.InputPreview = c.redact(trace.InputPreview)
+		trace.OutputPreview = c.redact(trace.OutputPreview)
+	}
 	return c.store.CreateTrace(ctx, trace)
 }
 
 // UpdateTrace synchronously updates a trace record.
 func (c *Collector) UpdateTrace(ctx context.Context, traceID uuid.UUID, updates map[string]any) error {
+	c.redactPreviewUpdates(updates)
 	return c.store.UpdateTrace(ctx, traceID, updates)
 }
 
@@ -140,6 +155,10 @@ func (c *Collector) EmitSpan(span store.SpanData) {
 	if span.CreatedAt.IsZero() {
 		span.CreatedAt = time.Now().UTC()
 	}
+	if c.redactPreview != nil {
+		span.InputPreview = c.redact(span.InputPreview)
+		span.OutputPreview = c.redact(span.OutputPreview)
+	}
 
 	select {
 	case c.spanCh <- span:
@@ -155,6 +174,7 @@ func (c *Collector) EmitSpan(span store.SpanData) {
 // execution starts, then updated via EmitSpanUpdate when execution completes.
 // Non-blocking channel send — safe to call even after ctx cancellation.
 func (c *Collector) EmitSpanUpdate(spanID, traceID uuid.UUID, updates map[string]any) {
+	c.redactPreviewUpdates(updates)
 	select {
 	case c.spanUpdateCh <- spanUpdate{SpanID: spanID, TraceID: traceID, Updates: updates}:
 		c.markDirty(traceID)
@@ -185,7 +205,7 @@ func (c *Collector) FinishTrace(ctx context.Context, traceID uuid.UUID, status s
 		updates["error"] = errMsg
 	}
 	if outputPreview != "" {
-		updates["output_preview"] = c.truncatePreviewStr(outputPreview)
+		updates["output_preview"] = c.truncatePreviewStr(c.redact(outputPreview))
 	}
 	if err := c.store.UpdateTrace(ctx, traceID, updates); err != nil {
 		slog.Warn("tracing: failed to finish trace", "trace_id", traceID, "error", err)
@@ -323,3 +343,23 @@ doneUpdates:
 func (c *Collector) truncatePreviewStr(s string) string {
 	return TruncateMid(s, c.PreviewMaxLen())
 }
+
+// redact applies the preview redactor, if any.
+func (c *Collector) redact(s string) string {
+	if c.redactPreview == nil || s == "" {
+		return s
+	}
+	return c.redactPreview(s)
+}
+
+// redactPreviewUpdates applies the preview redactor to preview fields of an update map.
+func (c *Collector) redactPreviewUpdates(updates map[string]any) {
+	if c.redactPreview == nil {
+		return
+	}
+	for _, key := range []string{"input_preview", "output_preview"} {
+		if s, ok := updates[key].(string); ok {
+			updates[key] = c.redact(s)
+		}
+	}
+}
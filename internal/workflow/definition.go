@@ -0,0 +1,377 @@
+// Package workflow runs declarative multi-agent workflows on top of team tasks.
+//
+// A workflow is a DAG of steps. Each step becomes one team task owned by a
+// team member; dependencies become blocked_by edges, so the existing task
+// dispatcher drives execution and feeds each step the results of the steps it
+// depends on. Steps whose task text depends on another step's output, or that
+// have a condition, are created lazily once their dependencies resolve.
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/nextlevelbuilder/goclaw/internal/cron"
+)
+
+// maxSteps caps the number of steps in one workflow.
+const maxSteps = 50
+
+// Definition is the declarative form of a workflow, authored as YAML or JSON.
+//
+//	description: Weekly market digest
+//	inputs:
+//	  - name: topic
+//	    required: true
+//	steps:
+//	  - id: research
+//	    agent: researcher
+//	    task: "Research {{input.topic}}"
+//	  - id: draft
+//	    agent: writer
+//	    task: Draft the digest
+//	    depends_on: [research]
+//	  - id: translate
+//	    agent: translator
+//	    task: "Translate: {{steps.draft.output}}"
+//	    depends_on: [draft]
+//	    when: {input: language, if: "!contains:en"}
+type Definition struct {
+	Description string  `json:"description,omitempty"`
+	Inputs      []Input `json:"inputs,omitempty"`
+	Steps       []Step  `json:"steps"`
+}
+
+// Input declares a run parameter, referenced as {{input.NAME}}.
+type Input struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required,omitempty"`
+	Default  string `json:"default,omitempty"`
+}
+
+// Step is one unit of work executed by a team member as a team task.
+type Step struct {
+	ID           string     `json:"id"`
+	Agent        string     `json:"agent"`                  // member agent key
+	Task         string     `json:"task"`                   // task subject, supports placeholders
+	Instructions string     `json:"instructions,omitempty"` // task description, supports placeholders
+	DependsOn    []string   `json:"depends_on,omitempty"`
+	When         *Condition `json:"when,omitempty"`
+	Priority     int        `json:"priority,omitempty"`
+}
+
+// Condition gates a step on a dependency's output or a run input. If uses the
+// cron deliver_if syntax: "nonempty", "contains:<text>", "matches:<regex>",
+// each negatable with "!". A step whose condition is false is skipped.
+type Condition struct {
+	Step  string `json:"step,omitempty"`
+	Input string `json:"input,omitempty"`
+	If    string `json:"if"`
+}
+
+var (
+	stepIDPattern    = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+	placeholderRegex = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+)
+
+// Parse decodes a YAML or JSON workflow definition (JSON is valid YAML) and
+// validates it. Unknown fields are rejected so typos do not silently change
+// the workflow's shape.
+func Parse(data []byte) (*Definition, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("workflow definition is empty")
+	}
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid workflow definition: %w", err)
+	}
+	// Round-trip through JSON so the json tags are the single schema.
+	normalized, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workflow definition: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(normalized))
+	dec.DisallowUnknownFields()
+	var def Definition
+	if err := dec.Decode(&def); err != nil {
+		return nil, fmt.Errorf("invalid workflow definition: %w", err)
+	}
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+// JSON returns the normalized JSON form stored with the workflow.
+func (d *Definition) JSON() json.RawMessage {
+	b, _ := json.Marshal(d)
+	return b
+}
+
+// Step returns the step with the given ID, or nil.
+func (d *Definition) Step(id string) *Step {
+	for i := range d.Steps {
+		if d.Steps[i].ID == id {
+			return &d.Steps[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks step IDs, dependencies (known, acyclic), conditions and
+// placeholders. A step may only reference the output of one of its
+// (transitive) dependencies, since only those are guaranteed to have finished.
+func (d *Definition) Validate() error {
+	if len(d.Steps) == 0 {
+		return fmt.Errorf("workflow has no steps")
+	}
+	if len(d.Steps) > maxSteps {
+		return fmt.Errorf("too many steps: %d (max %d)", len(d.Steps), maxSteps)
+	}
+
+	inputs := make(map[string]bool, len(d.Inputs))
+	for i, in := range d.Inputs {
+		if !stepIDPattern.MatchString(in.Name) {
+			return fmt.Errorf("input %d: invalid name %q", i+1, in.Name)
+		}
+		if inputs[in.Name] {
+			return fmt.Errorf("duplicate input %q", in.Name)
+		}
+		inputs[in.Name] = true
+	}
+
+	steps := make(map[string]*Step, len(d.Steps))
+	for i := range d.Steps {
+		s := &d.Steps[i]
+		if !stepIDPattern.MatchString(s.ID) {
+			return fmt.Errorf("step %d: invalid id %q (letters, digits, '_' and '-')", i+1, s.ID)
+		}
+		if _, dup := steps[s.ID]; dup {
+			return fmt.Errorf("duplicate step id %q", s.ID)
+		}
+		if strings.TrimSpace(s.Agent) == "" {
+			return fmt.Errorf("step %q: agent is required", s.ID)
+		}
+		if strings.TrimSpace(s.Task) == "" {
+			return fmt.Errorf("step %q: task is required", s.ID)
+		}
+		if len(s.Task) > 500 {
+			return fmt.Errorf("step %q: task too long (max 500 chars)", s.ID)
+		}
+		steps[s.ID] = s
+	}
+	for _, s := range d.Steps {
+		for _, dep := range s.DependsOn {
+			if dep == s.ID {
+				return fmt.Errorf("step %q cannot depend on itself", s.ID)
+			}
+			if _, ok := steps[dep]; !ok {
+				return fmt.Errorf("step %q: depends_on references unknown step %q", s.ID, dep)
+			}
+		}
+	}
+	if _, err := d.order(); err != nil {
+		return err
+	}
+
+	for _, s := range d.Steps {
+		ancestors := d.ancestors(s.ID)
+		if c := s.When; c != nil {
+			switch {
+			case (c.Step == "") == (c.Input == ""):
+				return fmt.Errorf("step %q: condition needs exactly one of step or input", s.ID)
+			case c.Step != "" && !ancestors[c.Step]:
+				return fmt.Errorf("step %q: condition step %q is not one of its dependencies", s.ID, c.Step)
+			case c.Input != "" && !inputs[c.Input]:
+				return fmt.Errorf("step %q: condition input %q is not declared", s.ID, c.Input)
+			}
+			op, _, _ := strings.Cut(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(c.If), "!")), ":")
+			if op == "changed" {
+				return fmt.Errorf("step %q: condition %q is not supported in workflows", s.ID, c.If)
+			}
+			if _, err := cron.EvalCondition(c.If, "", ""); err != nil {
+				return fmt.Errorf("step %q: %w", s.ID, err)
+			}
+		}
+		for _, text := range []string{s.Task, s.Instructions} {
+			for _, ref := range placeholders(text) {
+				if err := checkPlaceholder(ref, inputs, ancestors); err != nil {
+					return fmt.Errorf("step %q: %w", s.ID, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// order returns the step IDs in topological order (dependencies first,
+// otherwise in declaration order).
+func (d *Definition) order() ([]string, error) {
+	state := make(map[string]int, len(d.Steps)) // 0 = unvisited, 1 = on stack, 2 = done
+	out := make([]string, 0, len(d.Steps))
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case 1:
+			return fmt.Errorf("step dependency cycle at %q", id)
+		case 2:
+			return nil
+		}
+		state[id] = 1
+		for _, dep := range d.Step(id).DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[id] = 2
+		out = append(out, id)
+		return nil
+	}
+	for _, s := range d.Steps {
+		if err := visit(s.ID); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// ancestors returns the transitive dependencies of a step.
+func (d *Definition) ancestors(id string) map[string]bool {
+	seen := make(map[string]bool)
+	var walk func(id string)
+	walk = func(id string) {
+		for _, dep := range d.Step(id).DependsOn {
+			if !seen[dep] {
+				seen[dep] = true
+				walk(dep)
+			}
+		}
+	}
+	walk(id)
+	return seen
+}
+
+// sinks returns the steps no other step depends on, in declaration order.
+// Their outputs form the run output.
+func (d *Definition) sinks() []string {
+	hasDependents := make(map[string]bool)
+	for _, s := range d.Steps {
+		for _, dep := range s.DependsOn {
+			hasDependents[dep] = true
+		}
+	}
+	var out []string
+	for _, s := range d.Steps {
+		if !hasDependents[s.ID] {
+			out = append(out, s.ID)
+		}
+	}
+	return out
+}
+
+// lazy reports whether a step's task can only be created once its
+// dependencies resolve: it has a condition, references another step's
+// output, or depends on a lazy step. Other steps are created up front,
+// blocked by their dependencies' tasks.
+func (d *Definition) lazy(id string) bool {
+	s := d.Step(id)
+	if s.When != nil {
+		return true
+	}
+	for _, text := range []string{s.Task, s.Instructions} {
+		for _, ref := range placeholders(text) {
+			if strings.HasPrefix(ref, "steps.") {
+				return true
+			}
+		}
+	}
+	for _, dep := range s.DependsOn {
+		if d.lazy(dep) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveInputs applies defaults to the given run inputs and checks required
+// ones. Undeclared inputs are kept so ad-hoc triggers (cron message, wake
+// payload) can pass through extra context.
+func (d *Definition) ResolveInputs(given map[string]string) (map[string]string, error) {
+	out := make(map[string]string, len(given)+len(d.Inputs))
+	for k, v := range given {
+		out[k] = v
+	}
+	for _, in := range d.Inputs {
+		if strings.TrimSpace(out[in.Name]) != "" {
+			continue
+		}
+		if in.Default != "" {
+			out[in.Name] = in.Default
+			continue
+		}
+		if in.Required {
+			return nil, fmt.Errorf("input %q is required", in.Name)
+		}
+	}
+	return out, nil
+}
+
+func placeholders(text string) []string {
+	var refs []string
+	for _, m := range placeholderRegex.FindAllStringSubmatch(text, -1) {
+		refs = append(refs, m[1])
+	}
+	return refs
+}
+
+func checkPlaceholder(ref string, inputs, ancestors map[string]bool) error {
+	parts := strings.Split(ref, ".")
+	switch {
+	case ref == "date" || ref == "run.id":
+		return nil
+	case parts[0] == "input" && len(parts) == 2:
+		if !inputs[parts[1]] {
+			return fmt.Errorf("placeholder {{%s}} references undeclared input %q", ref, parts[1])
+		}
+		return nil
+	case parts[0] == "steps" && len(parts) == 3 && parts[2] == "output":
+		if !ancestors[parts[1]] {
+			return fmt.Errorf("placeholder {{%s}} references step %q which is not one of its dependencies", ref, parts[1])
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown placeholder {{%s}}", ref)
+}
+
+// expandVars holds the values substituted into step text.
+type expandVars struct {
+	runID   string
+	date    string
+	inputs  map[string]string
+	outputs map[string]string
+}
+
+// expand replaces placeholders in text. Unknown placeholders are left as-is
+// (Validate rejects them up front).
+func expand(text string, v expandVars) string {
+	return placeholderRegex.ReplaceAllStringFunc(text, func(m string) string {
+		ref := placeholderRegex.FindStringSubmatch(m)[1]
+		parts := strings.Split(ref, ".")
+		switch {
+		case ref == "date":
+			return v.date
+		case ref == "run.id":
+			return v.runID
+		case parts[0] == "input" && len(parts) == 2:
+			return v.inputs[parts[1]]
+		case parts[0] == "steps" && len(parts) == 3:
+			return v.outputs[parts[1]]
+		}
+		return m
+	})
+}
@@ -0,0 +1,133 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse_YAMLAndJSON(t *testing.T) {
+	yamlDef := `
+description: digest
+inputs:
+  - name: topic
+    required: true
+steps:
+  - id: research
+    agent: researcher
+    task: "Research {{input.topic}}"
+  - id: draft
+    agent: writer
+    task: Draft
+    instructions: "Use: {{steps.research.output}}"
+    depends_on: [research]
+`
+	def, err := Parse([]byte(yamlDef))
+	if err != nil {
+		t.Fatalf("yaml: %v", err)
+	}
+	if len(def.Steps) != 2 || def.Steps[1].DependsOn[0] != "research" || !def.Inputs[0].Required {
+		t.Fatalf("unexpected definition: %+v", def)
+	}
+
+	// The normalized JSON form parses back to the same definition.
+	again, err := Parse(def.JSON())
+	if err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	if again.Steps[1].Instructions != def.Steps[1].Instructions {
+		t.Fatalf("round trip mismatch: %+v", again)
+	}
+
+	if _, err := Parse([]byte(`{"steps":[{"id":"a","agent":"x","task":"t","dependson":["b"]}]}`)); err == nil {
+		t.Fatal("expected unknown field to be rejected")
+	}
+}
+
+func TestDefinitionValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		def     string
+		wantErr string
+	}{
+		{"ok fan-out fan-in", `steps:
+  - {id: a, agent: x, task: t}
+  - {id: b, agent: x, task: t, depends_on: [a]}
+  - {id: c, agent: x, task: t, depends_on: [a]}
+  - {id: d, agent: x, task: "{{steps.a.output}} {{steps.c.output}}", depends_on: [b, c]}`, ""},
+		{"no steps", `steps: []`, "no steps"},
+		{"missing agent", `steps: [{id: a, task: t}]`, "agent is required"},
+		{"bad id", `steps: [{id: "a b", agent: x, task: t}]`, "invalid id"},
+		{"duplicate", `steps: [{id: a, agent: x, task: t}, {id: a, agent: x, task: t}]`, "duplicate step id"},
+		{"unknown dep", `steps: [{id: a, agent: x, task: t, depends_on: [z]}]`, "unknown step"},
+		{"self dep", `steps: [{id: a, agent: x, task: t, depends_on: [a]}]`, "itself"},
+		{"cycle", `steps:
+  - {id: a, agent: x, task: t, depends_on: [b]}
+  - {id: b, agent: x, task: t, depends_on: [a]}`, "cycle"},
+		{"output of non-ancestor", `steps:
+  - {id: a, agent: x, task: t}
+  - {id: b, agent: x, task: "{{steps.a.output}}"}`, "not one of its dependencies"},
+		{"undeclared input", `steps: [{id: a, agent: x, task: "{{input.topic}}"}]`, "undeclared input"},
+		{"unknown placeholder", `steps: [{id: a, agent: x, task: "{{now}}"}]`, "unknown placeholder"},
+		{"condition both", `inputs: [{name: lang}]
+steps: [{id: a, agent: x, task: t, when: {input: lang, step: a, if: nonempty}}]`, "exactly one"},
+		{"condition bad op", `inputs: [{name: lang}]
+steps: [{id: a, agent: x, task: t, when: {input: lang, if: "bogus"}}]`, "unknown condition"},
+		{"condition changed", `inputs: [{name: lang}]
+steps: [{id: a, agent: x, task: t, when: {input: lang, if: "!changed"}}]`, "not supported"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.def))
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefinitionLazyAndSinks(t *testing.T) {
+	def, err := Parse([]byte(`inputs: [{name: lang}]
+steps:
+  - {id: a, agent: x, task: t}
+  - {id: b, agent: x, task: t, depends_on: [a]}
+  - {id: c, agent: x, task: "{{steps.a.output}}", depends_on: [a]}
+  - {id: d, agent: x, task: t, depends_on: [c]}
+  - {id: e, agent: x, task: t, when: {input: lang, if: nonempty}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[string]bool{"a": false, "b": false, "c": true, "d": true, "e": true} {
+		if got := def.lazy(id); got != want {
+			t.Errorf("lazy(%s) = %v, want %v", id, got, want)
+		}
+	}
+	if got := strings.Join(def.sinks(), ","); got != "b,d,e" {
+		t.Errorf("sinks = %s", got)
+	}
+}
+
+func TestResolveInputsAndExpand(t *testing.T) {
+	def := &Definition{Inputs: []Input{{Name: "topic", Required: true}, {Name: "lang", Default: "en"}}}
+	if _, err := def.ResolveInputs(nil); err == nil {
+		t.Fatal("expected missing required input error")
+	}
+	in, err := def.ResolveInputs(map[string]string{"topic": "go", "extra": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in["lang"] != "en" || in["extra"] != "x" {
+		t.Fatalf("inputs = %v", in)
+	}
+	got := expand("{{input.topic}}/{{ input.lang }} {{steps.a.output}} {{date}} {{run.id}}", expandVars{
+		runID: "r1", date: "2026-05-04", inputs: in, outputs: map[string]string{"a": "A"},
+	})
+	if got != "go/en A 2026-05-04 r1" {
+		t.Fatalf("expand = %q", got)
+	}
+}
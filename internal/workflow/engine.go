@@ -0,0 +1,723 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/bus"
+	"github.com/nextlevelbuilder/goclaw/internal/cron"
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+	"github.com/nextlevelbuilder/goclaw/internal/tools"
+	"github.com/nextlevelbuilder/goclaw/internal/tracing"
+	"github.com/nextlevelbuilder/goclaw/pkg/protocol"
+)
+
+// Task metadata keys stamped on every task a workflow run creates.
+const (
+	TaskMetaRunID = "workflow_run_id"
+	TaskMetaStep  = "workflow_step"
+)
+
+// ErrDisabled is returned when starting a disabled workflow.
+var ErrDisabled = errors.New("workflow is disabled")
+
+// RunOptions describes what started a run.
+type RunOptions struct {
+	Trigger     string // store.WorkflowTrigger*
+	TriggeredBy string // user ID, cron job ID, ...
+	Input       map[string]string
+}
+
+// Engine starts workflow runs and advances them as their step tasks finish.
+// Run state lives in the WorkflowStore and every change happens under the
+// store's run lock, so any replica can pick up a run (events first, the task
+// ticker as the safety net via ReconcileActiveRuns) without two of them
+// advancing it at once.
+type Engine struct {
+	teams      store.TeamStore
+	workflows  store.WorkflowStore
+	msgBus     *bus.MessageBus
+	tracer     *tracing.Collector      // optional: one trace per run
+	dispatcher tools.PostTurnProcessor // optional: starts pending step tasks
+}
+
+// errRunSettled tells LockWorkflowRun to skip the write for a run that had
+// already finished when the lock was taken.
+var errRunSettled = errors.New("workflow run already finished")
+
+func NewEngine(teams store.TeamStore, workflows store.WorkflowStore, msgBus *bus.MessageBus) *Engine {
+	return &Engine{teams: teams, workflows: workflows, msgBus: msgBus}
+}
+
+// SetTracer enables run traces: one trace per run with a child span per step.
+func (e *Engine) SetTracer(c *tracing.Collector) { e.tracer = c }
+
+// SetDispatcher wires the dispatcher that hands pending step tasks to their
+// owners. Without it step tasks wait for the task ticker.
+func (e *Engine) SetDispatcher(d tools.PostTurnProcessor) { e.dispatcher = d }
+
+// ResolveAgents maps every step's agent key to a team member. The lead cannot
+// own steps: lead-owned tasks are never dispatched.
+func ResolveAgents(def *Definition, team *store.TeamData, members []store.TeamMemberData) (map[string]uuid.UUID, error) {
+	byKey := make(map[string]uuid.UUID, len(members))
+	for _, m := range members {
+		byKey[m.AgentKey] = m.AgentID
+	}
+	out := make(map[string]uuid.UUID, len(def.Steps))
+	for _, s := range def.Steps {
+		id, ok := byKey[s.Agent]
+		if !ok {
+			return nil, fmt.Errorf("step %q: agent %q is not a member of this team", s.ID, s.Agent)
+		}
+		if id == team.LeadAgentID {
+			return nil, fmt.Errorf("step %q: agent %q is the team lead — assign a team member instead", s.ID, s.Agent)
+		}
+		out[s.Agent] = id
+	}
+	return out, nil
+}
+
+// Start creates a run of wf and its first step tasks, then dispatches them.
+// ctx must carry the workflow's tenant.
+func (e *Engine) Start(ctx context.Context, wf *store.WorkflowData, opts RunOptions) (*store.WorkflowRunData, error) {
+	if !wf.Enabled {
+		return nil, ErrDisabled
+	}
+	def, err := Parse(wf.Definition)
+	if err != nil {
+		return nil, err
+	}
+	input, err := def.ResolveInputs(opts.Input)
+	if err != nil {
+		return nil, err
+	}
+	team, err := e.teams.GetTeam(ctx, wf.TeamID)
+	if err != nil || team == nil {
+		return nil, fmt.Errorf("team %s not found", wf.TeamID)
+	}
+	members, err := e.teams.ListMembers(ctx, team.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ResolveAgents(def, team, members); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	run := &store.WorkflowRunData{
+		BaseModel:    store.BaseModel{ID: store.GenNewID()},
+		WorkflowID:   wf.ID,
+		TeamID:       team.ID,
+		Status:       store.WorkflowRunStatusRunning,
+		Trigger:      opts.Trigger,
+		TriggeredBy:  opts.TriggeredBy,
+		Input:        input,
+		Definition:   def.JSON(),
+		Steps:        make(map[string]*store.WorkflowStepState, len(def.Steps)),
+		StartedAt:    now,
+		WorkflowName: wf.Name,
+	}
+	for _, s := range def.Steps {
+		run.Steps[s.ID] = &store.WorkflowStepState{Status: store.WorkflowStepStatusWaiting}
+	}
+	if e.tracer != nil {
+		traceID := store.GenNewID()
+		trace := &store.TraceData{
+			ID:           traceID,
+			AgentID:      &team.LeadAgentID,
+			UserID:       opts.TriggeredBy,
+			RunID:        run.ID.String(),
+			StartTime:    now,
+			Name:         "workflow:" + wf.Name,
+			Channel:      "workflow",
+			InputPreview: inputPreview(input),
+			Status:       store.TraceStatusRunning,
+			Tags:         []string{"workflow", opts.Trigger},
+			TeamID:       &team.ID,
+			CreatedAt:    now,
+		}
+		if err := e.tracer.CreateTrace(ctx, trace); err != nil {
+			slog.Warn("workflow: create trace failed", "workflow_id", wf.ID, "error", err)
+		} else {
+			run.TraceID = &traceID
+		}
+	}
+	if err := e.workflows.CreateWorkflowRun(ctx, run); err != nil {
+		if run.TraceID != nil {
+			e.tracer.FinishTrace(ctx, *run.TraceID, store.TraceStatusError, err.Error(), "")
+		}
+		return nil, err
+	}
+	slog.Info("workflow: run started", "workflow_id", wf.ID, "run_id", run.ID, "trigger", opts.Trigger, "team_id", team.ID)
+	e.broadcastRun(ctx, run)
+
+	if err := e.Reconcile(ctx, run.ID); err != nil {
+		return run, err
+	}
+	if latest, err := e.workflows.GetWorkflowRun(ctx, run.ID); err == nil {
+		run = latest
+	}
+	return run, nil
+}
+
+// Reconcile syncs a run with its step tasks: finished tasks complete their
+// steps, steps whose dependencies resolved get their tasks, and the run
+// finishes once every step is terminal. A failed or cancelled step fails the
+// run and cancels the remaining steps. Safe to call repeatedly; ctx must
+// carry the run's tenant.
+func (e *Engine) Reconcile(ctx context.Context, runID uuid.UUID) error {
+	var created []*store.TeamTaskData
+	run, err := e.workflows.LockWorkflowRun(ctx, runID, func(run *store.WorkflowRunData) error {
+		if run.Status != store.WorkflowRunStatusRunning {
+			return errRunSettled
+		}
+		created = e.reconcileLocked(ctx, run)
+		return nil
+	})
+	if len(created) > 0 {
+		e.announceCreated(ctx, run, created)
+	}
+	if errors.Is(err, errRunSettled) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("save workflow run: %w", err)
+	}
+	if run.Status != store.WorkflowRunStatusRunning {
+		e.broadcastRun(ctx, run)
+	}
+	return nil
+}
+
+// ReconcileActiveRuns reconciles every running run that has not been touched
+// for a while. It catches completions whose events were missed (restarts,
+// events without tenant). Called by the task ticker.
+func (e *Engine) ReconcileActiveRuns(ctx context.Context) {
+	runs, err := e.workflows.ListActiveWorkflowRuns(ctx)
+	if err != nil {
+		slog.Warn("workflow: list active runs", "error", err)
+		return
+	}
+	cutoff := time.Now().Add(-30 * time.Second)
+	for _, run := range runs {
+		if run.UpdatedAt.After(cutoff) {
+			continue // recently advanced by an event
+		}
+		if err := e.Reconcile(store.WithTenantID(ctx, run.TenantID), run.ID); err != nil {
+			slog.Warn("workflow: reconcile run", "run_id", run.ID, "error", err)
+		}
+	}
+}
+
+// Cancel stops a running run: active step tasks are cancelled and pending
+// steps are marked cancelled.
+func (e *Engine) Cancel(ctx context.Context, runID uuid.UUID, reason string) (*store.WorkflowRunData, error) {
+	if reason == "" {
+		reason = "workflow run cancelled"
+	}
+	run, err := e.workflows.LockWorkflowRun(ctx, runID, func(run *store.WorkflowRunData) error {
+		if run.Status != store.WorkflowRunStatusRunning {
+			return fmt.Errorf("workflow run is already %s", run.Status)
+		}
+		e.syncSteps(ctx, run)
+		e.abort(ctx, run, store.WorkflowRunStatusCancelled, reason)
+		return nil
+	})
+	if err != nil {
+		return run, err
+	}
+	e.broadcastRun(ctx, run)
+	return run, nil
+}
+
+// Subscribe listens for terminal task events and reconciles the run the task
+// belongs to. Events relayed from other cluster nodes are ignored — the node
+// that produced them reconciles.
+func (e *Engine) Subscribe() {
+	if e.msgBus == nil {
+		return
+	}
+	e.msgBus.Subscribe("workflow.engine", func(event bus.Event) {
+		switch event.Name {
+		case protocol.EventTeamTaskCompleted, protocol.EventTeamTaskFailed, protocol.EventTeamTaskCancelled:
+		default:
+			return
+		}
+		if event.Origin != "" || event.TenantID == uuid.Nil {
+			return
+		}
+		payload, ok := event.Payload.(protocol.TeamTaskEventPayload)
+		if !ok {
+			return
+		}
+		taskID, err := uuid.Parse(payload.TaskID)
+		if err != nil {
+			return
+		}
+		// Subscribers run inside Broadcast; never block the publisher.
+		go e.handleTaskEvent(store.WithTenantID(context.Background(), event.TenantID), taskID)
+	})
+}
+
+func (e *Engine) handleTaskEvent(ctx context.Context, taskID uuid.UUID) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("workflow: panic handling task event", "task_id", taskID, "panic", r)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	task, err := e.teams.GetTask(ctx, taskID)
+	if err != nil || task == nil || task.Metadata == nil {
+		return
+	}
+	runIDStr, _ := task.Metadata[TaskMetaRunID].(string)
+	runID, err := uuid.Parse(runIDStr)
+	if err != nil {
+		return
+	}
+	if err := e.Reconcile(ctx, runID); err != nil {
+		slog.Warn("workflow: reconcile after task event", "run_id", runID, "task_id", taskID, "error", err)
+	}
+}
+
+// reconcileLocked advances a running run held under the store's run lock and
+// returns the step tasks it created. Failures finish the run instead of being
+// returned; the caller writes the run back.
+func (e *Engine) reconcileLocked(ctx context.Context, run *store.WorkflowRunData) []*store.TeamTaskData {
+	def, err := Parse(run.Definition)
+	if err != nil {
+		e.abort(ctx, run, store.WorkflowRunStatusFailed, err.Error())
+		return nil
+	}
+
+	e.syncSteps(ctx, run)
+	for _, s := range def.Steps {
+		st := run.Steps[s.ID]
+		if st != nil && (st.Status == store.WorkflowStepStatusFailed || st.Status == store.WorkflowStepStatusCancelled) {
+			reason := fmt.Sprintf("step %q %s", s.ID, st.Status)
+			if st.Error != "" {
+				reason += ": " + st.Error
+			}
+			e.abort(ctx, run, store.WorkflowRunStatusFailed, reason)
+			return nil
+		}
+	}
+
+	created, err := e.advance(ctx, run, def)
+	if err != nil {
+		e.abort(ctx, run, store.WorkflowRunStatusFailed, err.Error())
+		return created
+	}
+	if allTerminal(run) {
+		e.complete(ctx, run, def)
+	}
+	return created
+}
+
+// syncSteps copies the status and result of each active step's task onto the
+// step and closes its span.
+func (e *Engine) syncSteps(ctx context.Context, run *store.WorkflowRunData) {
+	for id, st := range run.Steps {
+		if st.Status != store.WorkflowStepStatusActive || st.TaskID == nil {
+			continue
+		}
+		task, err := e.teams.GetTask(ctx, *st.TaskID)
+		if errors.Is(err, store.ErrTaskNotFound) {
+			e.finishStep(run, id, store.WorkflowStepStatusFailed, "", "step task was deleted")
+			continue
+		}
+		if err != nil || task == nil {
+			continue // transient; next reconcile retries
+		}
+		result := ""
+		if task.Result != nil {
+			result = *task.Result
+		}
+		switch task.Status {
+		case store.TeamTaskStatusCompleted:
+			e.finishStep(run, id, store.WorkflowStepStatusCompleted, result, "")
+		case store.TeamTaskStatusFailed:
+			e.finishStep(run, id, store.WorkflowStepStatusFailed, "", result)
+		case store.TeamTaskStatusCancelled:
+			e.finishStep(run, id, store.WorkflowStepStatusCancelled, "", result)
+		}
+	}
+}
+
+// advance creates the tasks of every waiting step that can start. Eager
+// steps are created as soon as their dependencies have tasks, blocked by the
+// unfinished ones; lazy steps wait until their dependencies completed so
+// conditions and output placeholders can be evaluated.
+func (e *Engine) advance(ctx context.Context, run *store.WorkflowRunData, def *Definition) ([]*store.TeamTaskData, error) {
+	order, err := def.order()
+	if err != nil {
+		return nil, err
+	}
+	var team *store.TeamData
+	var agents map[string]uuid.UUID
+	now := time.Now().UTC()
+	vars := expandVars{
+		runID:   run.ID.String(),
+		date:    run.StartedAt.UTC().Format("2006-01-02"),
+		inputs:  run.Input,
+		outputs: make(map[string]string, len(run.Steps)),
+	}
+	for id, st := range run.Steps {
+		if st.Status == store.WorkflowStepStatusCompleted {
+			vars.outputs[id] = st.Output
+		}
+	}
+
+	var created []*store.TeamTaskData
+	for _, id := range order {
+		st := run.Steps[id]
+		if st == nil {
+			st = &store.WorkflowStepState{Status: store.WorkflowStepStatusWaiting}
+			run.Steps[id] = st
+		}
+		if st.Status != store.WorkflowStepStatusWaiting {
+			continue
+		}
+		step := def.Step(id)
+		lazy := def.lazy(id)
+
+		ready, allSkipped := true, len(step.DependsOn) > 0
+		var blockers, results []uuid.UUID
+		for _, dep := range step.DependsOn {
+			ds := run.Steps[dep]
+			switch ds.Status {
+			case store.WorkflowStepStatusCompleted:
+				allSkipped = false
+				results = append(results, *ds.TaskID)
+			case store.WorkflowStepStatusSkipped:
+			case store.WorkflowStepStatusActive:
+				allSkipped = false
+				if lazy {
+					ready = false
+				} else {
+					blockers = append(blockers, *ds.TaskID)
+					results = append(results, *ds.TaskID)
+				}
+			default:
+				allSkipped = false
+				ready = false
+			}
+		}
+		if !ready {
+			continue
+		}
+		if allSkipped {
+			e.skipStep(run, id, "all dependencies were skipped")
+			continue
+		}
+		if c := step.When; c != nil {
+			subject := vars.outputs[c.Step]
+			if c.Input != "" {
+				subject = run.Input[c.Input]
+			}
+			if ok, err := cron.EvalCondition(c.If, subject, ""); err != nil || !ok {
+				e.skipStep(run, id, "condition not met")
+				continue
+			}
+		}
+
+		if team == nil {
+			if team, agents, err = e.loadTeam(ctx, run.TeamID, def); err != nil {
+				return created, err
+			}
+		}
+		task := e.buildTask(run, team, step, agents[step.Agent], blockers, results, vars, now)
+		if err := e.teams.CreateTask(ctx, task); err != nil {
+			return created, fmt.Errorf("step %q: create task: %w", id, err)
+		}
+		st.Status = store.WorkflowStepStatusActive
+		st.TaskID = &task.ID
+		st.StartedAt = &now
+		e.startSpan(run, team, id, task, now)
+		created = append(created, task)
+	}
+	return created, nil
+}
+
+func (e *Engine) loadTeam(ctx context.Context, teamID uuid.UUID, def *Definition) (*store.TeamData, map[string]uuid.UUID, error) {
+	team, err := e.teams.GetTeam(ctx, teamID)
+	if err != nil || team == nil {
+		return nil, nil, fmt.Errorf("team %s not found", teamID)
+	}
+	members, err := e.teams.ListMembers(ctx, teamID)
+	if err != nil {
+		return nil, nil, err
+	}
+	agents, err := ResolveAgents(def, team, members)
+	if err != nil {
+		return nil, nil, err
+	}
+	return team, agents, nil
+}
+
+func (e *Engine) buildTask(run *store.WorkflowRunData, team *store.TeamData, step *Step, owner uuid.UUID, blockers, results []uuid.UUID, vars expandVars, now time.Time) *store.TeamTaskData {
+	meta := map[string]any{
+		TaskMetaRunID: run.ID.String(),
+		TaskMetaStep:  step.ID,
+	}
+	if run.TraceID != nil {
+		// The member's run links to the workflow trace, under the step span.
+		meta[tools.TaskMetaOriginTrace] = run.TraceID.String()
+		meta[tools.TaskMetaOriginRootSpan] = stepSpanID(run, step.ID).String()
+	}
+	if len(results) > 0 {
+		ids := make([]string, len(results))
+		for i, r := range results {
+			ids[i] = r.String()
+		}
+		meta["original_blocked_by"] = ids
+	}
+	status := store.TeamTaskStatusPending
+	if len(blockers) > 0 {
+		status = store.TeamTaskStatusBlocked
+	}
+	userID := run.TriggeredBy
+	if run.Trigger != store.WorkflowTriggerManual || userID == "" {
+		userID = team.CreatedBy
+	}
+	task := &store.TeamTaskData{
+		BaseModel:    store.BaseModel{ID: store.GenNewID()},
+		TeamID:       team.ID,
+		Subject:      truncateRunes(expand(step.Task, vars), 500),
+		Description:  expand(step.Instructions, vars),
+		Status:       status,
+		OwnerAgentID: &owner,
+		BlockedBy:    blockers,
+		Priority:     step.Priority,
+		UserID:       userID,
+		Channel:      "dashboard",
+		TaskType:     "general",
+		ChatID:       team.ID.String(),
+		Metadata:     meta,
+	}
+	tools.ParseSLAConfig(team.Settings).ApplyDue(task, nil, now)
+	return task
+}
+
+// stepSpanID derives a stable span ID per (run, step) so the task metadata
+// can reference the span before it is emitted.
+func stepSpanID(run *store.WorkflowRunData, stepID string) uuid.UUID {
+	return uuid.NewSHA1(run.ID, []byte("step:"+stepID))
+}
+
+func (e *Engine) startSpan(run *store.WorkflowRunData, team *store.TeamData, stepID string, task *store.TeamTaskData, now time.Time) {
+	if e.tracer == nil || run.TraceID == nil {
+		return
+	}
+	spanID := stepSpanID(run, stepID)
+	run.Steps[stepID].SpanID = &spanID
+	e.tracer.EmitSpan(store.SpanData{
+		ID:           spanID,
+		TraceID:      *run.TraceID,
+		AgentID:      task.OwnerAgentID,
+		SpanType:     store.SpanTypeAgent,
+		Name:         "workflow.step:" + stepID,
+		StartTime:    now,
+		Status:       store.SpanStatusRunning,
+		Level:        store.SpanLevelDefault,
+		InputPreview: task.Subject,
+		TeamID:       &team.ID,
+		TenantID:     run.TenantID,
+	})
+}
+
+func (e *Engine) finishStep(run *store.WorkflowRunData, stepID, status, output, errMsg string) {
+	st := run.Steps[stepID]
+	now := time.Now().UTC()
+	st.Status = status
+	st.Output = output
+	st.Error = errMsg
+	st.FinishedAt = &now
+	if e.tracer == nil || run.TraceID == nil || st.SpanID == nil {
+		return
+	}
+	updates := map[string]any{
+		"end_time": now,
+		"status":   store.SpanStatusCompleted,
+	}
+	if st.StartedAt != nil {
+		updates["duration_ms"] = int(now.Sub(*st.StartedAt).Milliseconds())
+	}
+	if output != "" {
+		updates["output_preview"] = truncateRunes(output, 500)
+	}
+	if status != store.WorkflowStepStatusCompleted {
+		updates["status"] = store.SpanStatusError
+		updates["error"] = status + ": " + errMsg
+	}
+	e.tracer.EmitSpanUpdate(*st.SpanID, *run.TraceID, updates)
+}
+
+func (e *Engine) skipStep(run *store.WorkflowRunData, stepID, reason string) {
+	now := time.Now().UTC()
+	st := run.Steps[stepID]
+	st.Status = store.WorkflowStepStatusSkipped
+	st.Error = reason
+	st.FinishedAt = &now
+	if e.tracer == nil || run.TraceID == nil {
+		return
+	}
+	e.tracer.EmitSpan(store.SpanData{
+		TraceID:       *run.TraceID,
+		SpanType:      store.SpanTypeEvent,
+		Name:          "workflow.step:" + stepID + " (skipped)",
+		StartTime:     now,
+		EndTime:       &now,
+		Status:        store.SpanStatusCompleted,
+		Level:         store.SpanLevelDefault,
+		OutputPreview: reason,
+		TeamID:        &run.TeamID,
+		TenantID:      run.TenantID,
+	})
+}
+
+// abort finishes the run with status, cancelling active step tasks and
+// marking steps that never started as cancelled.
+func (e *Engine) abort(ctx context.Context, run *store.WorkflowRunData, status, reason string) {
+	for id, st := range run.Steps {
+		switch st.Status {
+		case store.WorkflowStepStatusActive:
+			if st.TaskID != nil {
+				if err := e.teams.CancelTask(ctx, *st.TaskID, run.TeamID, reason); err != nil {
+					slog.Debug("workflow: cancel step task", "run_id", run.ID, "step", id, "error", err)
+				} else if e.msgBus != nil {
+					// Stops the member agent if it is working on the task.
+					bus.BroadcastForTenant(e.msgBus, protocol.EventTeamTaskCancelled, run.TenantID, tools.BuildTaskEventPayload(
+						run.TeamID.String(), st.TaskID.String(),
+						store.TeamTaskStatusCancelled,
+						"system", "workflow",
+						tools.WithReason(reason),
+					))
+				}
+			}
+			e.finishStep(run, id, store.WorkflowStepStatusCancelled, "", reason)
+		case store.WorkflowStepStatusWaiting:
+			now := time.Now().UTC()
+			st.Status = store.WorkflowStepStatusCancelled
+			st.FinishedAt = &now
+		}
+	}
+	now := time.Now().UTC()
+	run.Status = status
+	run.Error = reason
+	run.FinishedAt = &now
+	slog.Info("workflow: run finished", "run_id", run.ID, "status", status, "reason", reason)
+	if e.tracer != nil && run.TraceID != nil {
+		traceStatus := store.TraceStatusError
+		if status == store.WorkflowRunStatusCancelled {
+			traceStatus = store.TraceStatusCancelled
+		}
+		e.tracer.FinishTrace(ctx, *run.TraceID, traceStatus, reason, "")
+	}
+}
+
+// complete finishes a run whose steps are all completed or skipped. The run
+// output is the output of its sink steps (those nothing depends on).
+func (e *Engine) complete(ctx context.Context, run *store.WorkflowRunData, def *Definition) {
+	var parts []string
+	sinks := def.sinks()
+	for _, id := range sinks {
+		st := run.Steps[id]
+		if st.Status != store.WorkflowStepStatusCompleted || st.Output == "" {
+			continue
+		}
+		if len(sinks) == 1 {
+			parts = append(parts, st.Output)
+		} else {
+			parts = append(parts, fmt.Sprintf("## %s\n%s", id, st.Output))
+		}
+	}
+	now := time.Now().UTC()
+	run.Status = store.WorkflowRunStatusCompleted
+	run.Output = strings.Join(parts, "\n\n")
+	run.FinishedAt = &now
+	slog.Info("workflow: run completed", "run_id", run.ID, "workflow_id", run.WorkflowID)
+	if e.tracer != nil && run.TraceID != nil {
+		e.tracer.FinishTrace(ctx, *run.TraceID, store.TraceStatusCompleted, "", run.Output)
+	}
+}
+
+// announceCreated broadcasts task.created for new step tasks and dispatches
+// the pending ones.
+func (e *Engine) announceCreated(ctx context.Context, run *store.WorkflowRunData, created []*store.TeamTaskData) {
+	if e.msgBus != nil {
+		for _, task := range created {
+			bus.BroadcastForTenant(e.msgBus, protocol.EventTeamTaskCreated, run.TenantID, tools.BuildTaskEventPayload(
+				run.TeamID.String(), task.ID.String(),
+				task.Status,
+				"system", "workflow",
+				tools.WithTaskInfo(task.TaskNumber, task.Subject),
+				tools.WithChannel(task.Channel),
+				tools.WithChatID(task.ChatID),
+			))
+		}
+	}
+	if e.dispatcher != nil {
+		e.dispatcher.DispatchUnblockedTasks(ctx, run.TeamID)
+	}
+}
+
+func (e *Engine) broadcastRun(ctx context.Context, run *store.WorkflowRunData) {
+	if e.msgBus == nil {
+		return
+	}
+	tenantID := run.TenantID
+	if tenantID == uuid.Nil {
+		tenantID = store.TenantIDFromContext(ctx)
+	}
+	bus.BroadcastForTenant(e.msgBus, protocol.EventTeamWorkflowRun, tenantID, protocol.TeamWorkflowRunPayload{
+		TeamID:     run.TeamID.String(),
+		WorkflowID: run.WorkflowID.String(),
+		RunID:      run.ID.String(),
+		Name:       run.WorkflowName,
+		Status:     run.Status,
+		Trigger:    run.Trigger,
+		Error:      run.Error,
+	})
+}
+
+func allTerminal(run *store.WorkflowRunData) bool {
+	for _, st := range run.Steps {
+		if st.Status != store.WorkflowStepStatusCompleted && st.Status != store.WorkflowStepStatusSkipped {
+			return false
+		}
+	}
+	return true
+}
+
+func inputPreview(input map[string]string) string {
+	if len(input) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(input))
+	for k := range input {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+input[k])
+	}
+	return truncateRunes(strings.Join(parts, "\n"), 500)
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "..."
+}
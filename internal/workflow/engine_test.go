@@ -0,0 +1,340 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/nextlevelbuilder/goclaw/internal/store"
+)
+
+// fakeTeams implements the TeamStore methods the engine uses.
+type fakeTeams struct {
+	store.TeamStore
+	mu      sync.Mutex
+	team    *store.TeamData
+	members []store.TeamMemberData
+	tasks   map[uuid.UUID]*store.TeamTaskData
+	order   []uuid.UUID
+}
+
+func (f *fakeTeams) GetTeam(_ context.Context, id uuid.UUID) (*store.TeamData, error) {
+	if id != f.team.ID {
+		return nil, nil
+	}
+	return f.team, nil
+}
+
+func (f *fakeTeams) ListMembers(context.Context, uuid.UUID) ([]store.TeamMemberData, error) {
+	return f.members, nil
+}
+
+func (f *fakeTeams) CreateTask(_ context.Context, t *store.TeamTaskData) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cp := *t
+	f.tasks[t.ID] = &cp
+	f.order = append(f.order, t.ID)
+	return nil
+}
+
+func (f *fakeTeams) GetTask(_ context.Context, id uuid.UUID) (*store.TeamTaskData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.tasks[id]
+	if !ok {
+		return nil, store.ErrTaskNotFound
+	}
+	cp := *t
+	return &cp, nil
+}
+
+func (f *fakeTeams) CancelTask(_ context.Context, id, _ uuid.UUID, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tasks[id].Status = store.TeamTaskStatusCancelled
+	f.tasks[id].Result = &reason
+	return nil
+}
+
+// finish sets the task of a step to a terminal status, like a member would.
+func (f *fakeTeams) finish(t *testing.T, run *store.WorkflowRunData, step, status, result string) {
+	t.Helper()
+	st := run.Steps[step]
+	if st == nil || st.TaskID == nil {
+		t.Fatalf("step %q has no task", step)
+	}
+	f.mu.Lock()
+	f.tasks[*st.TaskID].Status = status
+	f.tasks[*st.TaskID].Result = &result
+	// Mirror the store: completing a blocker unblocks dependents.
+	if status == store.TeamTaskStatusCompleted {
+		for _, task := range f.tasks {
+			if task.Status == store.TeamTaskStatusBlocked {
+				task.Status = store.TeamTaskStatusPending
+			}
+		}
+	}
+	f.mu.Unlock()
+}
+
+type fakeWorkflows struct {
+	store.WorkflowStore
+	mu     sync.Mutex
+	runs   map[uuid.UUID]store.WorkflowRunData
+	lockMu sync.Mutex // stands in for the row lock
+}
+
+func (f *fakeWorkflows) CreateWorkflowRun(_ context.Context, run *store.WorkflowRunData) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.runs[run.ID] = cloneRun(run)
+	return nil
+}
+
+func (f *fakeWorkflows) GetWorkflowRun(_ context.Context, id uuid.UUID) (*store.WorkflowRunData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	run, ok := f.runs[id]
+	if !ok {
+		return nil, store.ErrWorkflowRunNotFound
+	}
+	cp := cloneRun(&run)
+	return &cp, nil
+}
+
+func (f *fakeWorkflows) UpdateWorkflowRun(_ context.Context, run *store.WorkflowRunData) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.runs[run.ID] = cloneRun(run)
+	return nil
+}
+
+func (f *fakeWorkflows) LockWorkflowRun(ctx context.Context, id uuid.UUID, fn func(run *store.WorkflowRunData) error) (*store.WorkflowRunData, error) {
+	f.lockMu.Lock()
+	defer f.lockMu.Unlock()
+	run, err := f.GetWorkflowRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(run); err != nil {
+		return run, err
+	}
+	return run, f.UpdateWorkflowRun(ctx, run)
+}
+
+// cloneRun deep-copies via JSON, like a database round trip.
+func cloneRun(run *store.WorkflowRunData) store.WorkflowRunData {
+	b, _ := json.Marshal(run)
+	var out store.WorkflowRunData
+	_ = json.Unmarshal(b, &out)
+	return out
+}
+
+func newTestEngine(t *testing.T, def string) (*Engine, *fakeTeams, *store.WorkflowData) {
+	t.Helper()
+	leadID := uuid.New()
+	team := &store.TeamData{BaseModel: store.BaseModel{ID: uuid.New()}, LeadAgentID: leadID, CreatedBy: "owner"}
+	teams := &fakeTeams{
+		team: team,
+		members: []store.TeamMemberData{
+			{AgentID: leadID, AgentKey: "lead", Role: store.TeamRoleLead},
+			{AgentID: uuid.New(), AgentKey: "researcher"},
+			{AgentID: uuid.New(), AgentKey: "writer"},
+		},
+		tasks: make(map[uuid.UUID]*store.TeamTaskData),
+	}
+	parsed, err := Parse([]byte(def))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	wf := &store.WorkflowData{
+		BaseModel:  store.BaseModel{ID: uuid.New()},
+		TeamID:     team.ID,
+		Name:       "digest",
+		Definition: parsed.JSON(),
+		Enabled:    true,
+	}
+	return NewEngine(teams, &fakeWorkflows{runs: make(map[uuid.UUID]store.WorkflowRunData)}, nil), teams, wf
+}
+
+func TestEngine_FanOutFanIn(t *testing.T) {
+	eng, teams, wf := newTestEngine(t, `inputs: [{name: topic, required: true}]
+steps:
+  - {id: news, agent: researcher, task: "News on {{input.topic}}"}
+  - {id: papers, agent: writer, task: "Papers on {{input.topic}}"}
+  - {id: summary, agent: writer, task: Summarize, instructions: "{{steps.news.output}}", depends_on: [news, papers]}
+  - {id: ship, agent: researcher, task: Ship, depends_on: [papers]}`)
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+
+	if _, err := eng.Start(ctx, wf, RunOptions{Trigger: store.WorkflowTriggerManual}); err == nil {
+		t.Fatal("expected missing input error")
+	}
+	run, err := eng.Start(ctx, wf, RunOptions{Trigger: store.WorkflowTriggerManual, TriggeredBy: "u1", Input: map[string]string{"topic": "go"}})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	// Eager steps exist up front; "ship" is blocked by the "papers" task.
+	// "summary" references an output, so it waits for both dependencies.
+	if len(teams.order) != 3 {
+		t.Fatalf("created %d tasks, want 3", len(teams.order))
+	}
+	news := teams.tasks[*run.Steps["news"].TaskID]
+	ship := teams.tasks[*run.Steps["ship"].TaskID]
+	if news.Subject != "News on go" || news.Status != store.TeamTaskStatusPending || news.Metadata[TaskMetaRunID] != run.ID.String() {
+		t.Fatalf("news task: %+v", news)
+	}
+	if ship.Status != store.TeamTaskStatusBlocked || len(ship.BlockedBy) != 1 || ship.BlockedBy[0] != *run.Steps["papers"].TaskID {
+		t.Fatalf("ship should be blocked by papers: %+v", ship)
+	}
+	if run.Steps["summary"].Status != store.WorkflowStepStatusWaiting {
+		t.Fatalf("summary status = %s", run.Steps["summary"].Status)
+	}
+
+	teams.finish(t, run, "news", store.TeamTaskStatusCompleted, "N")
+	if err := eng.Reconcile(ctx, run.ID); err != nil {
+		t.Fatal(err)
+	}
+	teams.finish(t, run, "papers", store.TeamTaskStatusCompleted, "P")
+	if err := eng.Reconcile(ctx, run.ID); err != nil {
+		t.Fatal(err)
+	}
+	run, _ = eng.workflows.GetWorkflowRun(ctx, run.ID)
+	summary := teams.tasks[*run.Steps["summary"].TaskID]
+	if summary.Description != "N" || summary.Status != store.TeamTaskStatusPending {
+		t.Fatalf("summary task: %+v", summary)
+	}
+	if ids, _ := summary.Metadata["original_blocked_by"].([]string); len(ids) != 2 {
+		t.Fatalf("summary should receive both dependency results: %v", summary.Metadata["original_blocked_by"])
+	}
+
+	teams.finish(t, run, "summary", store.TeamTaskStatusCompleted, "S")
+	teams.finish(t, run, "ship", store.TeamTaskStatusCompleted, "shipped")
+	if err := eng.Reconcile(ctx, run.ID); err != nil {
+		t.Fatal(err)
+	}
+	run, _ = eng.workflows.GetWorkflowRun(ctx, run.ID)
+	if run.Status != store.WorkflowRunStatusCompleted || run.FinishedAt == nil {
+		t.Fatalf("run status = %s", run.Status)
+	}
+	if run.Output != "## summary\nS\n\n## ship\nshipped" {
+		t.Fatalf("run output = %q", run.Output)
+	}
+}
+
+func TestEngine_ConditionSkipsBranch(t *testing.T) {
+	eng, teams, wf := newTestEngine(t, `steps:
+  - {id: check, agent: researcher, task: Check}
+  - {id: alert, agent: writer, task: Alert, depends_on: [check], when: {step: check, if: "contains:urgent"}}
+  - {id: followup, agent: writer, task: Follow up, depends_on: [alert]}`)
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+	run, err := eng.Start(ctx, wf, RunOptions{Trigger: store.WorkflowTriggerCron})
+	if err != nil {
+		t.Fatal(err)
+	}
+	teams.finish(t, run, "check", store.TeamTaskStatusCompleted, "all good")
+	if err := eng.Reconcile(ctx, run.ID); err != nil {
+		t.Fatal(err)
+	}
+	run, _ = eng.workflows.GetWorkflowRun(ctx, run.ID)
+	if run.Steps["alert"].Status != store.WorkflowStepStatusSkipped || run.Steps["followup"].Status != store.WorkflowStepStatusSkipped {
+		t.Fatalf("steps = alert:%s followup:%s", run.Steps["alert"].Status, run.Steps["followup"].Status)
+	}
+	if run.Status != store.WorkflowRunStatusCompleted || len(teams.order) != 1 {
+		t.Fatalf("run = %s, tasks = %d", run.Status, len(teams.order))
+	}
+	// Cron-triggered tasks are attributed to the team creator.
+	if teams.tasks[teams.order[0]].UserID != "owner" {
+		t.Fatalf("task user = %q", teams.tasks[teams.order[0]].UserID)
+	}
+}
+
+func TestEngine_FailedStepFailsRun(t *testing.T) {
+	eng, teams, wf := newTestEngine(t, `steps:
+  - {id: a, agent: researcher, task: A}
+  - {id: b, agent: writer, task: B}
+  - {id: c, agent: writer, task: C, depends_on: [a, b]}`)
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+	run, err := eng.Start(ctx, wf, RunOptions{Trigger: store.WorkflowTriggerManual})
+	if err != nil {
+		t.Fatal(err)
+	}
+	teams.finish(t, run, "a", store.TeamTaskStatusFailed, "boom")
+	if err := eng.Reconcile(ctx, run.ID); err != nil {
+		t.Fatal(err)
+	}
+	run, _ = eng.workflows.GetWorkflowRun(ctx, run.ID)
+	if run.Status != store.WorkflowRunStatusFailed || run.Error != `step "a" failed: boom` {
+		t.Fatalf("run = %s %q", run.Status, run.Error)
+	}
+	for _, id := range []string{"b", "c"} {
+		if run.Steps[id].Status != store.WorkflowStepStatusCancelled {
+			t.Errorf("step %s = %s, want cancelled", id, run.Steps[id].Status)
+		}
+		if task := teams.tasks[*run.Steps[id].TaskID]; task.Status != store.TeamTaskStatusCancelled {
+			t.Errorf("task of %s = %s, want cancelled", id, task.Status)
+		}
+	}
+
+	// Reconciling a finished run is a no-op; cancelling it is an error.
+	if err := eng.Reconcile(ctx, run.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := eng.Cancel(ctx, run.ID, ""); err == nil {
+		t.Fatal("expected error cancelling a finished run")
+	}
+}
+
+func TestEngine_StartRejectsLeadAndNonMembers(t *testing.T) {
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+	for _, agent := range []string{"lead", "stranger"} {
+		eng, teams, wf := newTestEngine(t, `steps: [{id: a, agent: `+agent+`, task: A}]`)
+		if _, err := eng.Start(ctx, wf, RunOptions{Trigger: store.WorkflowTriggerManual}); err == nil {
+			t.Errorf("agent %s: expected error", agent)
+		}
+		if len(teams.tasks) != 0 {
+			t.Errorf("agent %s: tasks created despite error", agent)
+		}
+	}
+
+	eng, _, wf := newTestEngine(t, `steps: [{id: a, agent: writer, task: A}]`)
+	wf.Enabled = false
+	if _, err := eng.Start(ctx, wf, RunOptions{}); err != ErrDisabled {
+		t.Fatalf("disabled: err = %v", err)
+	}
+}
+
+func TestEngine_ConcurrentReconcileCreatesTasksOnce(t *testing.T) {
+	eng, teams, wf := newTestEngine(t, `steps:
+  - {id: research, agent: researcher, task: Research}
+  - {id: write, agent: writer, task: Write, instructions: "{{steps.research.output}}", depends_on: [research]}`)
+	ctx := store.WithTenantID(context.Background(), store.MasterTenantID)
+	run, err := eng.Start(ctx, wf, RunOptions{Trigger: store.WorkflowTriggerManual})
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	teams.finish(t, run, "research", store.TeamTaskStatusCompleted, "R")
+
+	// A second replica shares the stores: the node that saw the completion
+	// event and the ticker's ReconcileActiveRuns race on the same run.
+	other := NewEngine(teams, eng.workflows, nil)
+	var wg sync.WaitGroup
+	for _, e := range []*Engine{eng, other, eng, other} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := e.Reconcile(ctx, run.ID); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(teams.order) != 2 {
+		t.Fatalf("created %d tasks, want 2 (one per step)", len(teams.order))
+	}
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_bus_inbound_queue_ready;
+
+DROP TABLE IF EXISTS bus_inbound_queue;
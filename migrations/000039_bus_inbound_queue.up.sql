@@ -0,0 +1,14 @@
+-- Durable inbound message queue for GOCLAW_BUS_BACKEND=postgres.
+-- Rows are claimed with FOR UPDATE SKIP LOCKED and hidden until visible_at;
+-- unacked rows become visible again for redelivery.
+CREATE TABLE IF NOT EXISTS bus_inbound_queue (
+    id         BIGSERIAL PRIMARY KEY,
+    queue      VARCHAR(64) NOT NULL,
+    payload    JSONB NOT NULL,
+    attempts   INT NOT NULL DEFAULT 0,
+    visible_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_bus_inbound_queue_ready
+    ON bus_inbound_queue(queue, visible_at, id);
@@ -0,0 +1,7 @@
+-- Distributed lock leases for multi-replica gateways (GOCLAW_CLUSTER_BACKEND=postgres).
+-- A lock is free when its row is missing or expired; owners renew expires_at.
+CREATE TABLE IF NOT EXISTS cluster_locks (
+    lock_key   VARCHAR(512) PRIMARY KEY,
+    owner      VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
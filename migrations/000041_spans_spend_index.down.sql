@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_spans_tenant_cost;
@@ -0,0 +1,2 @@
+-- Supports budget spend queries that sum span costs per tenant over a window.
+CREATE INDEX IF NOT EXISTS idx_spans_tenant_cost ON spans(tenant_id, created_at DESC) WHERE total_cost IS NOT NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_tt_due;
+ALTER TABLE team_tasks DROP COLUMN IF EXISTS next_escalation_at;
+ALTER TABLE team_tasks DROP COLUMN IF EXISTS escalation_count;
+ALTER TABLE team_tasks DROP COLUMN IF EXISTS at_risk_at;
+ALTER TABLE team_tasks DROP COLUMN IF EXISTS sla_minutes;
+ALTER TABLE team_tasks DROP COLUMN IF EXISTS due_at;
@@ -0,0 +1,9 @@
+-- Due dates, SLA targets and overdue escalation tracking for team tasks.
+ALTER TABLE team_tasks ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ;
+ALTER TABLE team_tasks ADD COLUMN IF NOT EXISTS sla_minutes INT NOT NULL DEFAULT 0;
+ALTER TABLE team_tasks ADD COLUMN IF NOT EXISTS at_risk_at TIMESTAMPTZ;
+ALTER TABLE team_tasks ADD COLUMN IF NOT EXISTS escalation_count INT NOT NULL DEFAULT 0;
+ALTER TABLE team_tasks ADD COLUMN IF NOT EXISTS next_escalation_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_tt_due ON team_tasks(due_at)
+    WHERE due_at IS NOT NULL AND status NOT IN ('completed', 'cancelled');
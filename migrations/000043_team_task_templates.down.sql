@@ -0,0 +1 @@
+DROP TABLE IF EXISTS team_task_templates;
@@ -0,0 +1,28 @@
+-- Reusable team task templates with optional cron recurrence.
+-- subtasks holds the template's child tasks: [{key, subject, description, owner_agent_id, priority, blocked_by: [key]}].
+CREATE TABLE IF NOT EXISTS team_task_templates (
+    id             UUID PRIMARY KEY,
+    team_id        UUID NOT NULL REFERENCES agent_teams(id) ON DELETE CASCADE,
+    name           VARCHAR(255) NOT NULL,
+    subject        VARCHAR(500) NOT NULL,
+    description    TEXT NOT NULL DEFAULT '',
+    owner_agent_id UUID REFERENCES agents(id) ON DELETE SET NULL,
+    priority       INT NOT NULL DEFAULT 0,
+    subtasks       JSONB NOT NULL DEFAULT '[]',
+    schedule       VARCHAR(100) NOT NULL DEFAULT '',
+    timezone       VARCHAR(64) NOT NULL DEFAULT '',
+    enabled        BOOLEAN NOT NULL DEFAULT true,
+    next_run_at    TIMESTAMPTZ,
+    last_run_at    TIMESTAMPTZ,
+    run_count      INT NOT NULL DEFAULT 0,
+    created_by     VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_id      UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(team_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ttt_team ON team_task_templates(team_id);
+CREATE INDEX IF NOT EXISTS idx_ttt_tenant ON team_task_templates(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_ttt_next_run ON team_task_templates(next_run_at)
+    WHERE enabled AND next_run_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS team_workflow_runs;
+DROP TABLE IF EXISTS team_workflows;
@@ -0,0 +1,45 @@
+-- Declarative multi-agent workflows (DAG of steps executed as team tasks).
+-- definition holds the normalized JSON workflow: {inputs, steps: [{id, agent, task, instructions, depends_on, when}]}.
+CREATE TABLE IF NOT EXISTS team_workflows (
+    id          UUID PRIMARY KEY,
+    team_id     UUID NOT NULL REFERENCES agent_teams(id) ON DELETE CASCADE,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    definition  JSONB NOT NULL DEFAULT '{}',
+    enabled     BOOLEAN NOT NULL DEFAULT true,
+    created_by  VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_id   UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(team_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_twf_team ON team_workflows(team_id);
+CREATE INDEX IF NOT EXISTS idx_twf_tenant ON team_workflows(tenant_id);
+
+-- One row per workflow execution. steps maps step id -> {status, task_id, span_id, output, error, started_at, finished_at}.
+-- definition is a snapshot taken at start so edits do not affect in-flight runs.
+CREATE TABLE IF NOT EXISTS team_workflow_runs (
+    id           UUID PRIMARY KEY,
+    workflow_id  UUID NOT NULL REFERENCES team_workflows(id) ON DELETE CASCADE,
+    team_id      UUID NOT NULL REFERENCES agent_teams(id) ON DELETE CASCADE,
+    status       VARCHAR(20) NOT NULL DEFAULT 'running',
+    trigger_kind VARCHAR(20) NOT NULL DEFAULT 'manual',
+    triggered_by VARCHAR(255) NOT NULL DEFAULT '',
+    input        JSONB NOT NULL DEFAULT '{}',
+    definition   JSONB NOT NULL DEFAULT '{}',
+    steps        JSONB NOT NULL DEFAULT '{}',
+    output       TEXT NOT NULL DEFAULT '',
+    error        TEXT NOT NULL DEFAULT '',
+    trace_id     UUID,
+    started_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at  TIMESTAMPTZ,
+    tenant_id    UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_twfr_workflow ON team_workflow_runs(workflow_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_twfr_team ON team_workflow_runs(team_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_twfr_tenant ON team_workflow_runs(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_twfr_running ON team_workflow_runs(status) WHERE status = 'running';
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS kb_chunks;
+DROP TABLE IF EXISTS kb_documents;
+DROP TABLE IF EXISTS kb_collection_agents;
+DROP TABLE IF EXISTS kb_collections;
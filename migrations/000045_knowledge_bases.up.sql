@@ -0,0 +1,64 @@
+-- Knowledge bases: named document collections per tenant, attachable to many agents.
+CREATE TABLE IF NOT EXISTS kb_collections (
+    id          UUID PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by  VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_id   UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(tenant_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS kb_collection_agents (
+    collection_id UUID NOT NULL REFERENCES kb_collections(id) ON DELETE CASCADE,
+    agent_id      UUID NOT NULL REFERENCES agents(id) ON DELETE CASCADE,
+    tenant_id     UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (collection_id, agent_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kbca_agent ON kb_collection_agents(agent_id);
+
+-- content keeps the extracted text so documents can be re-indexed without the original file.
+CREATE TABLE IF NOT EXISTS kb_documents (
+    id            UUID PRIMARY KEY,
+    collection_id UUID NOT NULL REFERENCES kb_collections(id) ON DELETE CASCADE,
+    file_name     VARCHAR(500) NOT NULL,
+    mime_type     VARCHAR(255) NOT NULL DEFAULT '',
+    hash          VARCHAR(64) NOT NULL DEFAULT '',
+    size_bytes    BIGINT NOT NULL DEFAULT 0,
+    pages         INT NOT NULL DEFAULT 0,
+    content       TEXT NOT NULL DEFAULT '',
+    status        VARCHAR(20) NOT NULL DEFAULT 'pending',
+    error         TEXT NOT NULL DEFAULT '',
+    chunk_count   INT NOT NULL DEFAULT 0,
+    created_by    VARCHAR(255) NOT NULL DEFAULT '',
+    tenant_id     UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(collection_id, file_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kbdoc_tenant ON kb_documents(tenant_id);
+
+-- page is the 1-based PDF page or PPTX slide of the chunk (0 = not paged).
+CREATE TABLE IF NOT EXISTS kb_chunks (
+    id            UUID PRIMARY KEY DEFAULT uuid_generate_v7(),
+    collection_id UUID NOT NULL REFERENCES kb_collections(id) ON DELETE CASCADE,
+    document_id   UUID NOT NULL REFERENCES kb_documents(id) ON DELETE CASCADE,
+    page          INT NOT NULL DEFAULT 0,
+    start_line    INT NOT NULL DEFAULT 0,
+    end_line      INT NOT NULL DEFAULT 0,
+    hash          VARCHAR(64) NOT NULL,
+    text          TEXT NOT NULL,
+    embedding     vector(1536),
+    tsv           tsvector GENERATED ALWAYS AS (to_tsvector('simple', text)) STORED,
+    tenant_id     UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_kbchunk_collection ON kb_chunks(collection_id);
+CREATE INDEX IF NOT EXISTS idx_kbchunk_document ON kb_chunks(document_id);
+CREATE INDEX IF NOT EXISTS idx_kbchunk_tsv ON kb_chunks USING GIN(tsv);
+CREATE INDEX IF NOT EXISTS idx_kbchunk_vec ON kb_chunks USING hnsw(embedding vector_cosine_ops);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_bus_inbound_queue_ready;
+CREATE INDEX IF NOT EXISTS idx_bus_inbound_queue_ready
+    ON bus_inbound_queue(queue, visible_at, id);
+
+ALTER TABLE bus_inbound_queue DROP COLUMN IF EXISTS dead_at;
@@ -0,0 +1,8 @@
+-- Dead-letter inbound messages that keep failing instead of redelivering
+-- them forever. Rows with dead_at set are never claimed again and are kept
+-- for inspection.
+ALTER TABLE bus_inbound_queue ADD COLUMN IF NOT EXISTS dead_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS idx_bus_inbound_queue_ready;
+CREATE INDEX IF NOT EXISTS idx_bus_inbound_queue_ready
+    ON bus_inbound_queue(queue, visible_at, id) WHERE dead_at IS NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE cron_run_logs DROP COLUMN IF EXISTS missed;
+ALTER TABLE cron_run_logs DROP COLUMN IF EXISTS catch_up;
+
+ALTER TABLE cron_jobs DROP COLUMN IF EXISTS catch_up_pending;
+ALTER TABLE cron_jobs DROP COLUMN IF EXISTS catch_up;
@@ -0,0 +1,8 @@
+-- Per-job policy for runs missed while the gateway was down ('' = run-once),
+-- plus the number of missed runs still to replay under 'run-all'.
+ALTER TABLE cron_jobs ADD COLUMN IF NOT EXISTS catch_up VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE cron_jobs ADD COLUMN IF NOT EXISTS catch_up_pending INT NOT NULL DEFAULT 0;
+
+-- Catch-up decisions are logged as runs with status 'catchup'.
+ALTER TABLE cron_run_logs ADD COLUMN IF NOT EXISTS catch_up VARCHAR(16);
+ALTER TABLE cron_run_logs ADD COLUMN IF NOT EXISTS missed INT;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_cron_jobs_after;
+
+ALTER TABLE cron_jobs DROP COLUMN IF EXISTS last_output;
+ALTER TABLE cron_jobs DROP COLUMN IF EXISTS deliver_if;
+ALTER TABLE cron_jobs DROP COLUMN IF EXISTS chain_input;
+ALTER TABLE cron_jobs DROP COLUMN IF EXISTS after_job_id;
@@ -0,0 +1,12 @@
+-- Cron chaining: a job with schedule_kind 'after' runs when after_job_id
+-- succeeds, receiving its output in chain_input. Deleting the upstream job
+-- deletes the jobs chained after it, which could otherwise never run again.
+ALTER TABLE cron_jobs ADD COLUMN IF NOT EXISTS after_job_id UUID REFERENCES cron_jobs(id) ON DELETE CASCADE;
+ALTER TABLE cron_jobs ADD COLUMN IF NOT EXISTS chain_input TEXT;
+
+-- Delivery condition on the run output, and the previous successful output
+-- it is compared against ('changed').
+ALTER TABLE cron_jobs ADD COLUMN IF NOT EXISTS deliver_if TEXT NOT NULL DEFAULT '';
+ALTER TABLE cron_jobs ADD COLUMN IF NOT EXISTS last_output TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_cron_jobs_after ON cron_jobs(after_job_id) WHERE after_job_id IS NOT NULL;
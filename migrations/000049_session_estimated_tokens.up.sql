@@ -0,0 +1,7 @@
+-- Estimated prompt tokens of each session, computed with the model's
+-- tokenizer when the session is saved so session lists need not load
+-- messages. Existing rows get the previous bytes/4 + system prompt estimate.
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS estimated_tokens INTEGER NOT NULL DEFAULT 0;
+
+UPDATE sessions SET estimated_tokens = octet_length(messages::text) / 4 + 12000
+ WHERE jsonb_array_length(messages) > 0;
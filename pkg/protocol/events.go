@@ -9,6 +9,8 @@ const (
 	EventHeartbeat         = "heartbeat"
 	EventExecApprovalReq   = "exec.approval.requested"
 	EventExecApprovalRes   = "exec.approval.resolved"
+	EventToolApprovalReq   = "tool.approval.requested"
+	EventToolApprovalRes   = "tool.approval.resolved"
 	EventPresence          = "presence"
 	EventTick              = "tick"
 	EventShutdown          = "shutdown"
@@ -51,8 +53,12 @@ const (
 	EventTeamTaskUpdated         = "team.task.updated"
 	EventTeamTaskDeleted         = "team.task.deleted"
 	EventTeamTaskStale           = "team.task.stale"
+	EventTeamTaskOverdue         = "team.task.overdue"
 	EventTeamTaskAttachmentAdded = "team.task.attachment_added"
 
+	// Team workflow run lifecycle (started, completed, failed, cancelled).
+	EventTeamWorkflowRun = "team.workflow.run"
+
 	// Emitted when leader starts processing completed team task results (before announce run).
 	EventTeamLeaderProcessing = "team.leader.processing"
 
@@ -92,6 +98,9 @@ const (
 	// Audit log event (internal, not forwarded to WS clients).
 	EventAuditLog = "audit.log"
 
+	// Budget soft-limit warning (spend crossed soft_limit_percent of a USD cap).
+	EventBudgetWarning = "budget.warning"
+
 	// Session lifecycle events.
 	EventSessionUpdated = "session.updated"
 
@@ -16,6 +16,7 @@ const (
 	MethodChatAbort         = "chat.abort"
 	MethodChatInject        = "chat.inject"
 	MethodChatSessionStatus = "chat.session.status"
+	MethodChatTokens        = "chat.tokens"
 
 	// Agents management
 	MethodAgentsList     = "agents.list"
@@ -83,10 +84,15 @@ const (
 	MethodApprovalsApprove = "exec.approval.approve"
 	MethodApprovalsDeny    = "exec.approval.deny"
 
+	MethodToolApprovalsList    = "tool.approval.list"
+	MethodToolApprovalsApprove = "tool.approval.approve"
+	MethodToolApprovalsDeny    = "tool.approval.deny"
+
 	MethodUsageGet     = "usage.get"
 	MethodUsageSummary = "usage.summary"
 
-	MethodQuotaUsage = "quota.usage"
+	MethodQuotaUsage  = "quota.usage"
+	MethodQuotaBudget = "quota.budget"
 
 	MethodSend = "send"
 )
@@ -146,6 +152,21 @@ const (
 	MethodTeamsTaskDeleteBulk      = "teams.tasks.delete-bulk"
 	MethodTeamsTaskAssign          = "teams.tasks.assign"
 	MethodTeamsTaskActiveBySession = "teams.tasks.active-by-session"
+	MethodTeamsTaskTemplatesList   = "teams.tasks.templates.list"
+	MethodTeamsTaskTemplatesGet    = "teams.tasks.templates.get"
+	MethodTeamsTaskTemplatesCreate = "teams.tasks.templates.create"
+	MethodTeamsTaskTemplatesUpdate = "teams.tasks.templates.update"
+	MethodTeamsTaskTemplatesDelete = "teams.tasks.templates.delete"
+	MethodTeamsTaskTemplatesRun    = "teams.tasks.templates.run"
+	MethodTeamsWorkflowsList       = "teams.workflows.list"
+	MethodTeamsWorkflowsGet        = "teams.workflows.get"
+	MethodTeamsWorkflowsCreate     = "teams.workflows.create"
+	MethodTeamsWorkflowsUpdate     = "teams.workflows.update"
+	MethodTeamsWorkflowsDelete     = "teams.workflows.delete"
+	MethodTeamsWorkflowsRun        = "teams.workflows.run"
+	MethodTeamsWorkflowRunsList    = "teams.workflows.runs.list"
+	MethodTeamsWorkflowRunsGet     = "teams.workflows.runs.get"
+	MethodTeamsWorkflowRunsCancel  = "teams.workflows.runs.cancel"
 	MethodTeamsMembersAdd          = "teams.members.add"
 	MethodTeamsMembersRemove       = "teams.members.remove"
 	MethodTeamsUpdate              = "teams.update"
@@ -110,11 +110,26 @@ type TeamTaskEventPayload struct {
 	ProgressPercent int    `json:"progress_percent,omitempty"`
 	ProgressStep    string `json:"progress_step,omitempty"`
 
+	// Due date and escalation level (for team.task.overdue events).
+	DueAt           string `json:"due_at,omitempty"`
+	EscalationLevel int    `json:"escalation_level,omitempty"`
+
 	// Actor info for audit trail (recorded to team_task_events by subscriber).
 	ActorType string `json:"actor_type,omitempty"` // "agent", "human", "system"
 	ActorID   string `json:"actor_id,omitempty"`   // agent key, user ID, or system identifier
 }
 
+// TeamWorkflowRunPayload is the typed payload for team.workflow.run events.
+type TeamWorkflowRunPayload struct {
+	TeamID     string `json:"team_id"`
+	WorkflowID string `json:"workflow_id"`
+	RunID      string `json:"run_id"`
+	Name       string `json:"name,omitempty"`
+	Status     string `json:"status"` // running, completed, failed, cancelled
+	Trigger    string `json:"trigger,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
 // TeamMessageEventPayload is the typed payload for team.message.sent events.
 type TeamMessageEventPayload struct {
 	TeamID          string `json:"team_id"`
@@ -118,6 +118,7 @@ export const Methods = {
   USAGE_SUMMARY: "usage.summary",
 
   QUOTA_USAGE: "quota.usage",
+  QUOTA_BUDGET: "quota.budget",
 
   SEND: "send",
 
@@ -9,6 +9,23 @@ export interface ToolPolicyConfig {
   alsoAllow?: string[];
   byProvider?: Record<string, { profile?: string; allow?: string[]; deny?: string[]; alsoAllow?: string[] }>;
   toolCallPrefix?: string; // prefix to strip from model's tool call names
+  guardrails?: ToolGuardrailRule[]; // content-aware argument/result rules
+}
+
+export interface ToolGuardrailRule {
+  name?: string;
+  tools: string[]; // names, globs or "group:xxx"
+  agents?: string[];
+  tenants?: string[];
+  stage?: "call" | "result";
+  action?: "deny" | "rewrite" | "redact";
+  args?: Record<string, string>;
+  fields?: string[];
+  pattern?: string;
+  detect?: string[]; // "email", "phone", "credit_card"
+  cidrs?: string[];
+  replacement?: string;
+  message?: string;
 }
 
 export interface SubagentsConfig {